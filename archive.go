@@ -0,0 +1,161 @@
+package rdf2go
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Archive formats supported by ExportArchive and ImportArchive
+const (
+	ArchiveZip = "zip"
+	ArchiveTar = "tar"
+)
+
+// defaultGraphEntry is the base name of the archive entry holding the default graph
+const defaultGraphEntry = "default"
+
+// archiveEntryName returns the archive file name used for a graph
+func archiveEntryName(graph Term) string {
+	if graph == nil {
+		return defaultGraphEntry + ".ttl"
+	}
+	return url.QueryEscape(graph.RawValue()) + ".ttl"
+}
+
+// archiveEntryGraph returns the graph term encoded in an archive file name
+func archiveEntryGraph(name string) (Term, error) {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if base == defaultGraphEntry {
+		return nil, nil
+	}
+	iri, err := url.QueryUnescape(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph name in archive entry %s: %s", name, err)
+	}
+	if strings.HasPrefix(iri, "_:") {
+		return NewBlankNode(iri[2:]), nil
+	}
+	return NewResource(iri), nil
+}
+
+// ExportArchive writes each graph of the dataset as a separate Turtle file
+// inside a zip or tar archive. Files are named after the escaped graph IRI,
+// and the default graph is stored as default.ttl.
+func (d *Dataset) ExportArchive(w io.Writer, format string) error {
+	graphs := d.GetNamedGraphs()
+	if d.One(nil, nil, nil, DefaultGraph) != nil {
+		graphs = append([]Term{nil}, graphs...)
+	}
+
+	switch format {
+	case ArchiveZip:
+		zw := zip.NewWriter(w)
+		for _, name := range graphs {
+			fw, err := zw.Create(archiveEntryName(name))
+			if err != nil {
+				return err
+			}
+			if err := d.GetGraph(storedGraph(name)).Serialize(fw, "text/turtle"); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	case ArchiveTar:
+		tw := tar.NewWriter(w)
+		for _, name := range graphs {
+			buf := new(bytes.Buffer)
+			if err := d.GetGraph(storedGraph(name)).Serialize(buf, "text/turtle"); err != nil {
+				return err
+			}
+			hdr := &tar.Header{
+				Name: archiveEntryName(name),
+				Mode: 0644,
+				Size: int64(buf.Len()),
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return tw.Close()
+	}
+	return errors.New(format + " is not a supported archive format")
+}
+
+// ImportArchive reads a zip or tar archive produced by ExportArchive and adds
+// every graph it contains to the dataset. The parser used for each file is
+// chosen from its extension.
+func (d *Dataset) ImportArchive(r io.Reader, format string) error {
+	switch format {
+	case ArchiveZip:
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(r); err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = d.importArchiveEntry(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case ArchiveTar:
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := d.importArchiveEntry(hdr.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.New(format + " is not a supported archive format")
+}
+
+func (d *Dataset) importArchiveEntry(name string, r io.Reader) error {
+	mime, ok := mimeRdfExt[path.Ext(name)]
+	if !ok {
+		return fmt.Errorf("unknown RDF file extension for archive entry %s", name)
+	}
+	graph, err := archiveEntryGraph(name)
+	if err != nil {
+		return err
+	}
+	g := NewGraph(d.uri)
+	if err := g.Parse(r, mime); err != nil {
+		return fmt.Errorf("could not parse archive entry %s: %s", name, err)
+	}
+	for triple := range g.IterTriples() {
+		d.AddQuad(triple.Subject, triple.Predicate, triple.Object, graph)
+	}
+	return nil
+}
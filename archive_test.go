@@ -0,0 +1,55 @@
+package rdf2go
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func archiveTestDataset() *Dataset {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/alice"), NewResource("http://xmlns.com/foaf/0.1/name"), NewLiteral("Alice"))
+	d.AddQuad(NewResource("http://example.org/bob"), NewResource("http://xmlns.com/foaf/0.1/name"), NewLiteral("Bob"), NewResource("http://example.org/graph1"))
+	d.AddQuad(NewResource("http://example.org/bob"), NewResource("http://xmlns.com/foaf/0.1/knows"), NewResource("http://example.org/alice"), NewResource("http://example.org/graph1"))
+	d.AddQuad(NewResource("http://example.org/carol"), NewResource("http://xmlns.com/foaf/0.1/name"), NewLiteral("Carol"), NewResource("http://example.org/graph2"))
+	return d
+}
+
+func TestDatasetArchiveRoundtrip(t *testing.T) {
+	for _, format := range []string{ArchiveZip, ArchiveTar} {
+		d := archiveTestDataset()
+		var buf bytes.Buffer
+		assert.NoError(t, d.ExportArchive(&buf, format))
+
+		d2 := NewDataset(testDatasetUri)
+		assert.NoError(t, d2.ImportArchive(&buf, format))
+		assert.Equal(t, d.Len(), d2.Len(), format)
+		assert.Equal(t, 1, d2.GetDefaultGraph().Len(), format)
+		assert.Equal(t, 2, d2.GetGraph(NewResource("http://example.org/graph1")).Len(), format)
+		assert.Equal(t, 1, d2.GetGraph(NewResource("http://example.org/graph2")).Len(), format)
+	}
+}
+
+func TestDatasetExportArchiveEntries(t *testing.T) {
+	d := archiveTestDataset()
+	var buf bytes.Buffer
+	assert.NoError(t, d.ExportArchive(&buf, ArchiveZip))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		names[f.Name] = true
+	}
+	assert.True(t, names["default.ttl"])
+	assert.True(t, names["http%3A%2F%2Fexample.org%2Fgraph1.ttl"])
+	assert.True(t, names["http%3A%2F%2Fexample.org%2Fgraph2.ttl"])
+}
+
+func TestDatasetArchiveUnsupportedFormat(t *testing.T) {
+	d := archiveTestDataset()
+	assert.Error(t, d.ExportArchive(new(bytes.Buffer), "rar"))
+	assert.Error(t, d.ImportArchive(new(bytes.Buffer), "rar"))
+}
@@ -0,0 +1,116 @@
+package rdf2go
+
+// defaultArenaChunk is the number of values allocated at once by a TermArena
+const defaultArenaChunk = 4096
+
+// TermArena allocates terms, triples and quads in chunks, so that a bulk
+// parse performs a few large allocations instead of millions of small ones,
+// which reduces the work of the garbage collector in one-shot conversion
+// jobs. The values of a chunk are freed together, once none of them is
+// referenced anymore; a single live term therefore keeps its whole chunk in
+// memory, which makes arenas a poor fit for long-lived data sets from which
+// most statements are later removed.
+//
+// A nil *TermArena is valid and allocates every value individually. A
+// TermArena is not safe for concurrent use.
+type TermArena struct {
+	chunk      int
+	resources  []Resource
+	literals   []Literal
+	blankNodes []BlankNode
+	triples    []Triple
+	quads      []Quad
+}
+
+// NewTermArena creates a TermArena allocating chunk values at a time. When
+// chunk is not positive, a default size is used.
+func NewTermArena(chunk int) *TermArena {
+	if chunk <= 0 {
+		chunk = defaultArenaChunk
+	}
+	return &TermArena{chunk: chunk}
+}
+
+// Reset detaches the arena from the chunks it has handed out, so that the
+// next values are allocated in new chunks. The previous chunks are freed by
+// the garbage collector once their values are no longer referenced.
+func (a *TermArena) Reset() {
+	if a == nil {
+		return
+	}
+	a.resources, a.literals, a.blankNodes, a.triples, a.quads = nil, nil, nil, nil, nil
+}
+
+// NewResource returns a new IRI term
+func (a *TermArena) NewResource(uri string) Term {
+	if a == nil {
+		return NewResource(uri)
+	}
+	if len(a.resources) == cap(a.resources) {
+		a.resources = make([]Resource, 0, a.chunk)
+	}
+	a.resources = append(a.resources, Resource{URI: uri})
+	return &a.resources[len(a.resources)-1]
+}
+
+func (a *TermArena) newLiteral(lit Literal) Term {
+	if a == nil {
+		return &lit
+	}
+	if len(a.literals) == cap(a.literals) {
+		a.literals = make([]Literal, 0, a.chunk)
+	}
+	a.literals = append(a.literals, lit)
+	return &a.literals[len(a.literals)-1]
+}
+
+// NewLiteral returns a new plain literal
+func (a *TermArena) NewLiteral(value string) Term {
+	return a.newLiteral(Literal{Value: value})
+}
+
+// NewLiteralWithLanguage returns a new literal with a language tag
+func (a *TermArena) NewLiteralWithLanguage(value string, language string) Term {
+	return a.newLiteral(Literal{Value: value, Language: language})
+}
+
+// NewLiteralWithDatatype returns a new literal with a datatype
+func (a *TermArena) NewLiteralWithDatatype(value string, datatype Term) Term {
+	return a.newLiteral(Literal{Value: value, Datatype: datatype})
+}
+
+// NewBlankNode returns a new blank node with the given identifier
+func (a *TermArena) NewBlankNode(id string) Term {
+	if a == nil {
+		return NewBlankNode(id)
+	}
+	if len(a.blankNodes) == cap(a.blankNodes) {
+		a.blankNodes = make([]BlankNode, 0, a.chunk)
+	}
+	a.blankNodes = append(a.blankNodes, BlankNode{ID: id})
+	return &a.blankNodes[len(a.blankNodes)-1]
+}
+
+// NewTriple returns a new triple
+func (a *TermArena) NewTriple(subject Term, predicate Term, object Term) *Triple {
+	if a == nil {
+		return NewTriple(subject, predicate, object)
+	}
+	if len(a.triples) == cap(a.triples) {
+		a.triples = make([]Triple, 0, a.chunk)
+	}
+	a.triples = append(a.triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+	return &a.triples[len(a.triples)-1]
+}
+
+// NewQuad returns a new quad
+func (a *TermArena) NewQuad(subject Term, predicate Term, object Term, graph Term) *Quad {
+	if a == nil {
+		return NewQuad(subject, predicate, object, graph)
+	}
+	if len(a.quads) == cap(a.quads) {
+		a.quads = make([]Quad, 0, a.chunk)
+	}
+	a.quads = append(a.quads, Quad{Subject: subject, Predicate: predicate, Object: object, Graph: graph})
+	return &a.quads[len(a.quads)-1]
+}
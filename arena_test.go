@@ -0,0 +1,51 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermArena(t *testing.T) {
+	a := NewTermArena(2)
+	r1 := a.NewResource("http://example.org/a")
+	r2 := a.NewResource("http://example.org/b")
+	r3 := a.NewResource("http://example.org/c")
+	assert.True(t, r1.Equal(NewResource("http://example.org/a")))
+	assert.Equal(t, "<http://example.org/b>", r2.String())
+	assert.Equal(t, "<http://example.org/c>", r3.String())
+
+	lit := a.NewLiteralWithDatatype("1", a.NewResource(xsdNamespace+"integer"))
+	assert.True(t, lit.Equal(NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer"))))
+	assert.True(t, a.NewLiteralWithLanguage("x", "en").Equal(NewLiteralWithLanguage("x", "en")))
+	assert.True(t, a.NewBlankNode("b1").Equal(NewBlankNode("b1")))
+
+	q := a.NewQuad(r1, r2, r3, nil)
+	assert.True(t, q.Equal(NewQuad(r1, r2, r3, nil)))
+	assert.True(t, a.NewTriple(r1, r2, r3).Equal(NewTriple(r1, r2, r3)))
+
+	a.Reset()
+	assert.Equal(t, "http://example.org/a", r1.RawValue())
+}
+
+func TestNilTermArena(t *testing.T) {
+	var a *TermArena
+	assert.True(t, a.NewLiteral("x").Equal(NewLiteral("x")))
+	assert.NotNil(t, a.NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil))
+	a.Reset()
+}
+
+func TestDatasetParseWithArena(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.SetArena(NewTermArena(0))
+	err := d.Parse(strings.NewReader("<a> <b> \"c\"@en <g> .\n_:x <b> \"1\"^^<http://www.w3.org/2001/XMLSchema#integer> .\n"), "application/n-quads")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Len())
+	assert.NotNil(t, d.One(NewBlankNode("x"), NewResource("b"), nil, nil))
+
+	g := NewGraph(testUri)
+	g.SetArena(NewTermArena(0))
+	assert.NoError(t, g.Parse(strings.NewReader("<a> <b> <c> .\n"), "application/n-triples"))
+	assert.Equal(t, 1, g.Len())
+}
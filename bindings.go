@@ -0,0 +1,95 @@
+package rdf2go
+
+import "slices"
+
+// Var returns a variable, to be used in the patterns of Graph.Query and
+// Dataset.Query
+func Var(name string) Term {
+	return &variable{name: name}
+}
+
+// Binding maps the names of variables to the terms bound to them by a
+// solution
+type Binding map[string]Term
+
+// Bindings holds the solutions of a query, with the names of its variables
+// in the order they first appear in the patterns
+type Bindings struct {
+	Vars      []string
+	Solutions []Binding
+	// Boolean is the result of an ASK query read by ParseBindings, nil for
+	// the results of other queries
+	Boolean *bool
+}
+
+// Len returns the number of solutions
+func (b *Bindings) Len() int {
+	return len(b.Solutions)
+}
+
+// Terms returns the term bound to a variable by each solution binding it
+func (b *Bindings) Terms(name string) []Term {
+	var terms []Term
+	for _, solution := range b.Solutions {
+		if t, ok := solution[name]; ok {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// addVars records the variables among terms that are not known yet
+func (b *Bindings) addVars(terms ...Term) {
+	for _, t := range terms {
+		v, ok := t.(*variable)
+		if ok && !slices.Contains(b.Vars, v.name) {
+			b.Vars = append(b.Vars, v.name)
+		}
+	}
+}
+
+// Query joins triple patterns, whose terms may be variables created by Var,
+// and returns one binding of the variables per solution, in no particular
+// order. A variable bound by a pattern takes the same value in the others.
+func (g *Graph) Query(patterns ...*Triple) *Bindings {
+	b := &Bindings{}
+	for _, pattern := range patterns {
+		b.addVars(pattern.Subject, pattern.Predicate, pattern.Object)
+	}
+	solutions := []Binding{{}}
+	for _, pattern := range patterns {
+		var next []Binding
+		for _, solution := range solutions {
+			s := bindVariable(pattern.Subject, solution)
+			p := bindVariable(pattern.Predicate, solution)
+			o := bindVariable(pattern.Object, solution)
+			g.match(unboundAsNil(s), unboundAsNil(p), unboundAsNil(o), func(triple *Triple) bool {
+				extended, ok := extendBinding(solution, []Term{s, p, o}, []Term{triple.Subject, triple.Predicate, triple.Object})
+				if ok {
+					next = append(next, extended)
+				}
+				return true
+			})
+		}
+		solutions = next
+		if len(solutions) == 0 {
+			break
+		}
+	}
+	b.Solutions = solutions
+	return b
+}
+
+// Query joins quad patterns, see Graph.Query. A nil graph selects the
+// default graph, as in All, and a variable graph the named graphs, to which
+// it is bound.
+func (d *Dataset) Query(patterns ...*Quad) *Bindings {
+	b := &Bindings{}
+	for _, pattern := range patterns {
+		b.addVars(pattern.Subject, pattern.Predicate, pattern.Object, pattern.Graph)
+	}
+	for _, solution := range d.solve(patterns) {
+		b.Solutions = append(b.Solutions, solution)
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQuery(t *testing.T) {
+	g := NewGraph(testUri)
+	knows := NewResource("http://xmlns.com/foaf/0.1/knows")
+	name := NewResource("http://xmlns.com/foaf/0.1/name")
+	alice := NewResource("http://example.org/alice")
+	bob := NewResource("http://example.org/bob")
+	carol := NewResource("http://example.org/carol")
+	g.AddTriple(alice, knows, bob)
+	g.AddTriple(alice, knows, carol)
+	g.AddTriple(bob, name, NewLiteral("Bob"))
+	g.AddTriple(carol, name, NewLiteral("Carol"))
+	g.AddTriple(bob, knows, bob)
+
+	b := g.Query(
+		NewTriple(alice, knows, Var("friend")),
+		NewTriple(Var("friend"), name, Var("name")),
+	)
+	assert.Equal(t, []string{"friend", "name"}, b.Vars)
+	assert.Equal(t, 2, b.Len())
+	assert.ElementsMatch(t, []Term{NewLiteral("Bob"), NewLiteral("Carol")}, b.Terms("name"))
+
+	self := g.Query(NewTriple(Var("x"), knows, Var("x")))
+	assert.Equal(t, 1, self.Len())
+	assert.True(t, bob.Equal(self.Solutions[0]["x"]))
+
+	none := g.Query(NewTriple(carol, knows, Var("x")), NewTriple(Var("x"), name, Var("n")))
+	assert.Equal(t, 0, none.Len())
+	assert.Equal(t, []string{"x", "n"}, none.Vars)
+	assert.Equal(t, 1, g.Query(NewTriple(alice, knows, bob)).Len())
+}
+
+func TestDatasetQuery(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	p := NewResource("http://example.org/p")
+	a := NewResource("http://example.org/a")
+	g1 := NewResource("http://example.org/g1")
+	g2 := NewResource("http://example.org/g2")
+	d.AddQuad(a, p, NewLiteral("x"), g1)
+	d.AddQuad(a, p, NewLiteral("y"), g2)
+	d.AddQuad(a, p, NewLiteral("z"), nil)
+
+	b := d.Query(NewQuad(a, p, Var("o"), Var("g")))
+	assert.Equal(t, []string{"o", "g"}, b.Vars)
+	assert.Equal(t, 2, b.Len())
+	assert.ElementsMatch(t, []Term{g1, g2}, b.Terms("g"))
+
+	b = d.Query(NewQuad(Var("s"), p, Var("o"), nil))
+	assert.Equal(t, 1, b.Len())
+	assert.Equal(t, NewLiteral("z"), b.Solutions[0]["o"])
+}
@@ -0,0 +1,354 @@
+package rdf2go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// The buckets of a BoltStore: terms maps term IDs to their N-Triples
+// representation and ids the other way around, while each index holds the
+// keys made of the IDs of the terms of the quads in its order, the default
+// graph having ID 0
+var (
+	boltTerms   = []byte("terms")
+	boltIDs     = []byte("ids")
+	boltMeta    = []byte("meta")
+	boltCount   = []byte("count")
+	boltIndexes = []boltIndex{
+		{[]byte("spog"), [4]int{0, 1, 2, 3}},
+		{[]byte("posg"), [4]int{1, 2, 0, 3}},
+		{[]byte("ospg"), [4]int{2, 0, 1, 3}},
+		{[]byte("gspo"), [4]int{3, 0, 1, 2}},
+	}
+)
+
+// boltBatchSize is the number of quads read by a transaction of Match,
+// between which fn may modify the store
+const boltBatchSize = 1000
+
+// boltIndex is an index of a BoltStore, order giving the quad position of
+// each ID of its keys
+type boltIndex struct {
+	name  []byte
+	order [4]int
+}
+
+// key returns the key of a quad in the index
+func (idx boltIndex) key(ids [4]uint64) []byte {
+	key := make([]byte, 32)
+	for i, pos := range idx.order {
+		binary.BigEndian.PutUint64(key[i*8:], ids[pos])
+	}
+	return key
+}
+
+// ids returns the IDs of the terms of a quad from its key in the index
+func (idx boltIndex) ids(key []byte) [4]uint64 {
+	var ids [4]uint64
+	for i, pos := range idx.order {
+		ids[pos] = binary.BigEndian.Uint64(key[i*8:])
+	}
+	return ids
+}
+
+// BoltStore is a Store persisting quads to a single file with bbolt, a
+// key/value store, so that datasets larger than memory can be queried
+// without loading them first. Terms are stored once in a dictionary, and
+// quads in four indexes, SPOG, POSG, OSPG and GSPO, so that patterns are
+// answered by scanning the quads sharing their bound terms. Each change is a
+// transaction written to disk, so that loading many quads is faster with
+// AddAll. It is safe for concurrent use.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens the BoltStore of a file, creating it if needed. A
+// file can only be opened by one process at a time.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltTerms, boltIDs, boltMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		for _, idx := range boltIndexes {
+			if _, err := tx.CreateBucketIfNotExists(idx.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the file of the store
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// Add adds a quad, unless an equal quad is already present
+func (b *BoltStore) Add(q *Quad) (bool, error) {
+	n, err := b.AddAll([]*Quad{q})
+	return n > 0, err
+}
+
+// AddAll adds quads in a single transaction, skipping those already
+// present, and returns the number of quads added
+func (b *BoltStore) AddAll(quads []*Quad) (int, error) {
+	added := 0
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		added = 0
+		for _, q := range quads {
+			var ids [4]uint64
+			for i, t := range []Term{q.Subject, q.Predicate, q.Object, q.Graph} {
+				id, err := b.termID(tx, t)
+				if err != nil {
+					return err
+				}
+				ids[i] = id
+			}
+			if boltHas(tx, ids) {
+				continue
+			}
+			for _, idx := range boltIndexes {
+				if err := tx.Bucket(idx.name).Put(idx.key(ids), nil); err != nil {
+					return err
+				}
+			}
+			added++
+		}
+		return b.addCount(tx, added)
+	})
+	return added, err
+}
+
+// termID returns the ID of a term, adding it to the dictionary if needed
+func (b *BoltStore) termID(tx *bolt.Tx, t Term) (uint64, error) {
+	if t == nil || t == DefaultGraph {
+		return 0, nil
+	}
+	s, err := encodeStoredTerm(t)
+	if err != nil {
+		return 0, err
+	}
+	ids := tx.Bucket(boltIDs)
+	if v := ids.Get([]byte(s)); v != nil {
+		return binary.BigEndian.Uint64(v), nil
+	}
+	terms := tx.Bucket(boltTerms)
+	id, err := terms.NextSequence()
+	if err != nil {
+		return 0, err
+	}
+	key := binary.BigEndian.AppendUint64(nil, id)
+	if err := terms.Put(key, []byte(s)); err != nil {
+		return 0, err
+	}
+	return id, ids.Put([]byte(s), key)
+}
+
+// lookupID returns the ID of a term, telling whether the dictionary holds it
+func lookupID(tx *bolt.Tx, t Term) (uint64, bool) {
+	if t == nil || t == DefaultGraph {
+		return 0, true
+	}
+	s, err := encodeStoredTerm(t)
+	if err != nil {
+		return 0, false
+	}
+	v := tx.Bucket(boltIDs).Get([]byte(s))
+	if v == nil {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(v), true
+}
+
+// boltHas tells whether the store holds the quad of term IDs. The keys of
+// the indexes have no value, which Get would not tell from a missing key.
+func boltHas(tx *bolt.Tx, ids [4]uint64) bool {
+	key := boltIndexes[0].key(ids)
+	k, _ := tx.Bucket(boltIndexes[0].name).Cursor().Seek(key)
+	return bytes.Equal(k, key)
+}
+
+// addCount adds delta to the number of quads of the store
+func (b *BoltStore) addCount(tx *bolt.Tx, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	meta := tx.Bucket(boltMeta)
+	var n uint64
+	if v := meta.Get(boltCount); v != nil {
+		n = binary.BigEndian.Uint64(v)
+	}
+	return meta.Put(boltCount, binary.BigEndian.AppendUint64(nil, uint64(int64(n)+int64(delta))))
+}
+
+// Remove removes the quad equal to q
+func (b *BoltStore) Remove(q *Quad) (bool, error) {
+	removed := false
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var ids [4]uint64
+		for i, t := range []Term{q.Subject, q.Predicate, q.Object, q.Graph} {
+			id, ok := lookupID(tx, t)
+			if !ok {
+				return nil
+			}
+			ids[i] = id
+		}
+		if !boltHas(tx, ids) {
+			return nil
+		}
+		for _, idx := range boltIndexes {
+			if err := tx.Bucket(idx.name).Delete(idx.key(ids)); err != nil {
+				return err
+			}
+		}
+		removed = true
+		return b.addCount(tx, -1)
+	})
+	return removed, err
+}
+
+// Len returns the number of quads
+func (b *BoltStore) Len() (int, error) {
+	n := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(boltMeta).Get(boltCount); v != nil {
+			n = int(binary.BigEndian.Uint64(v))
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Iterate calls fn with every quad until it returns false
+func (b *BoltStore) Iterate(fn func(*Quad) bool) error {
+	return b.Match(nil, nil, nil, AnyGraph, fn)
+}
+
+// Match calls fn with the quads matching a pattern, see Store. The quads
+// are read in batches, between which fn is called and may modify the store.
+func (b *BoltStore) Match(s Term, p Term, o Term, g Term, fn func(*Quad) bool) error {
+	var bound [4]bool
+	var ids [4]uint64
+	found := true
+	err := b.db.View(func(tx *bolt.Tx) error {
+		for i, t := range []Term{s, p, o, g} {
+			if t == nil && i < 3 || t == AnyGraph || t == UnionGraph {
+				continue
+			}
+			bound[i] = true
+			ids[i], found = lookupID(tx, t)
+			if !found {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil || !found {
+		return err
+	}
+
+	// the index whose keys start with the most bound terms is scanned
+	idx := boltIndexes[0]
+	prefixLen := -1
+	for _, candidate := range boltIndexes {
+		n := 0
+		for n < 4 && bound[candidate.order[n]] {
+			n++
+		}
+		if n > prefixLen {
+			idx, prefixLen = candidate, n
+		}
+	}
+	prefix := idx.key(ids)[:prefixLen*8]
+
+	terms := make(map[uint64]Term)
+	var last []byte
+	for {
+		var batch []*Quad
+		err := b.db.View(func(tx *bolt.Tx) error {
+			c := tx.Bucket(idx.name).Cursor()
+			k, _ := c.Seek(prefix)
+			if last != nil {
+				// the batch resumes after the last key of the previous one
+				if k, _ = c.Seek(last); bytes.Equal(k, last) {
+					k, _ = c.Next()
+				}
+			}
+			for ; k != nil && bytes.HasPrefix(k, prefix) && len(batch) < boltBatchSize; k, _ = c.Next() {
+				last = append(last[:0], k...)
+				qids := idx.ids(k)
+				if !matchIDs(qids, ids, bound) || g == UnionGraph && qids[3] == 0 {
+					continue
+				}
+				q, err := b.quad(tx, qids, terms)
+				if err != nil {
+					return err
+				}
+				batch = append(batch, q)
+			}
+			if k == nil || !bytes.HasPrefix(k, prefix) {
+				last = nil
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, q := range batch {
+			if !fn(q) {
+				return nil
+			}
+		}
+		if last == nil {
+			return nil
+		}
+		if len(terms) > boltBatchSize*16 {
+			terms = make(map[uint64]Term)
+		}
+	}
+}
+
+// matchIDs tells whether the IDs of a quad are those of the bound terms
+func matchIDs(qids [4]uint64, ids [4]uint64, bound [4]bool) bool {
+	for i := range qids {
+		if bound[i] && qids[i] != ids[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// quad returns the quad of term IDs, decoding the terms through a cache
+func (b *BoltStore) quad(tx *bolt.Tx, ids [4]uint64, cache map[uint64]Term) (*Quad, error) {
+	var terms [4]Term
+	for i, id := range ids {
+		if id == 0 {
+			continue
+		}
+		t, ok := cache[id]
+		if !ok {
+			var err error
+			t, err = decodeTerm(string(tx.Bucket(boltTerms).Get(binary.BigEndian.AppendUint64(nil, id))))
+			if err != nil {
+				return nil, err
+			}
+			cache[id] = t
+		}
+		terms[i] = t
+	}
+	return NewQuad(terms[0], terms[1], terms[2], terms[3]), nil
+}
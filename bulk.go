@@ -0,0 +1,158 @@
+package rdf2go
+
+import (
+	"io"
+	"time"
+)
+
+// AddAll adds a batch of triples, taking the lock of a thread-safe graph
+// once and indexing the triples once all of them are stored, and returns
+// the number of triples added. Hooks are called once the batch is added.
+func (g *Graph) AddAll(triples []*Triple) int {
+	unlock := g.lock()
+	if len(g.triples) == 0 {
+		g.triples = make(map[*Triple]uint64, len(triples))
+	}
+	added := make([]*Triple, 0, len(triples))
+	for _, t := range triples {
+		if g.iriPolicy != nil {
+			// equivalent triples are looked up in the index, which must
+			// then be kept up to date
+			if g.addUnlocked(t) {
+				added = append(added, t)
+			}
+			continue
+		}
+		if g.insertUnlocked(t) {
+			added = append(added, t)
+		}
+	}
+	if g.index != nil && g.iriPolicy == nil {
+		for _, t := range added {
+			sk, pk, ok := g.iriPolicy.tripleKeys(t)
+			g.index.add(sk, pk, ok, t)
+		}
+	}
+	unlock()
+	for _, t := range added {
+		g.notify(QuadAdded, t)
+	}
+	return len(added)
+}
+
+// AddAll adds a batch of quads, taking the lock of a thread-safe dataset
+// once and indexing the quads once all of them are stored, and returns the
+// number of quads added. Subscriptions and hooks are notified once the
+// batch is added.
+func (d *Dataset) AddAll(quads []*Quad) int {
+	unlock := d.lock()
+	added := d.addAllUnlocked(quads)
+	unlock()
+	for _, q := range added {
+		d.notify(QuadAdded, q)
+	}
+	return len(added)
+}
+
+// addAllUnlocked implements AddAll, returning the quads added
+func (d *Dataset) addAllUnlocked(quads []*Quad) []*Quad {
+	if len(d.quads) == 0 {
+		d.quads = make(map[*Quad]uint64, len(quads))
+	}
+	added := make([]*Quad, 0, len(quads))
+	for _, q := range quads {
+		if d.iriPolicy != nil {
+			// equivalent quads are looked up in the index, which must then
+			// be kept up to date
+			if d.addUnlocked(q, "") {
+				added = append(added, q)
+			}
+			continue
+		}
+		if d.insertUnlocked(q) {
+			added = append(added, q)
+		}
+	}
+	if d.graphs != nil && d.iriPolicy == nil {
+		for _, q := range added {
+			d.index(q)
+		}
+	}
+	return added
+}
+
+// BulkStats reports the work of a BulkLoader
+type BulkStats struct {
+	// Added counts the quads added to the dataset
+	Added int
+	// Skipped counts the quads that were not added, being already part of
+	// the dataset or added to a graph selector such as AnyGraph
+	Skipped int
+	// Flushes counts the flushes of buffered quads
+	Flushes int
+	// Duration is the time spent flushing
+	Duration time.Duration
+}
+
+// BulkLoader loads large amounts of quads into a dataset faster than Add:
+// the quads are buffered until Flush, which adds all of them at once
+// taking the lock of the dataset once, sizing the maps of an empty dataset
+// for them and updating the indexes once all of them are stored. The
+// buffered quads are not visible in the dataset before Flush. A BulkLoader
+// is not safe for concurrent use.
+type BulkLoader struct {
+	dataset *Dataset
+	pending []*Quad
+	stats   BulkStats
+}
+
+// NewBulkLoader creates a BulkLoader for a dataset, sizeHint being the
+// expected number of quads per flush, or 0 when unknown
+func NewBulkLoader(d *Dataset, sizeHint int) *BulkLoader {
+	return &BulkLoader{dataset: d, pending: make([]*Quad, 0, max(sizeHint, 0))}
+}
+
+// Add buffers a quad
+func (b *BulkLoader) Add(q *Quad) {
+	b.pending = append(b.pending, q)
+}
+
+// AddQuad buffers a quad made of individual S, P, O, G objects
+func (b *BulkLoader) AddQuad(s Term, p Term, o Term, g Term) {
+	b.Add(NewQuad(s, p, o, g))
+}
+
+// Parse buffers the quads of a document, streamed as ParseStream does
+func (b *BulkLoader) Parse(reader io.Reader, mime string) error {
+	return ParseStream(reader, mime, func(q *Quad) error {
+		b.Add(q)
+		return nil
+	})
+}
+
+// Pending returns the number of buffered quads
+func (b *BulkLoader) Pending() int {
+	return len(b.pending)
+}
+
+// Flush adds the buffered quads to the dataset, and returns the number of
+// quads added
+func (b *BulkLoader) Flush() int {
+	if len(b.pending) == 0 {
+		return 0
+	}
+	start := time.Now()
+	added := b.dataset.AddAll(b.pending)
+	b.stats.Added += added
+	b.stats.Skipped += len(b.pending) - added
+	b.stats.Flushes++
+	b.stats.Duration += time.Since(start)
+	clear(b.pending)
+	b.pending = b.pending[:0]
+	return added
+}
+
+// Stats returns the statistics of the flushes so far
+func (b *BulkLoader) Stats() BulkStats {
+	return b.stats
+}
@@ -0,0 +1,74 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphAddAll(t *testing.T) {
+	g := NewGraph(testUri)
+	var hooked int
+	g.OnAdd(func(*Triple) { hooked++ })
+	a := NewTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("1"))
+	b := NewTriple(NewResource("http://example.org/b"), NewResource("http://example.org/p"), NewLiteral("2"))
+	assert.Equal(t, 2, g.AddAll([]*Triple{a, b, a}))
+	assert.Equal(t, 0, g.AddAll([]*Triple{b}))
+	assert.Equal(t, 2, g.Len())
+	assert.Equal(t, 2, hooked)
+	assert.Equal(t, b, g.One(nil, nil, NewLiteral("2")))
+	assert.Len(t, g.All(nil, NewResource("http://example.org/p"), nil), 2)
+
+	g = NewGraph(testUri)
+	g.SetIRIPolicy(&IRIPolicy{CaseInsensitiveHost: true})
+	assert.Equal(t, 1, g.AddAll([]*Triple{
+		NewTriple(NewResource("http://EXAMPLE.org/a"), NewResource("http://example.org/p"), NewLiteral("1")),
+		NewTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("1")),
+	}))
+}
+
+func TestDatasetAddAll(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true))
+	var changes []ChangeKind
+	sub := d.Subscribe(nil, nil, nil, AnyGraph, func(kind ChangeKind, q *Quad) {
+		changes = append(changes, kind)
+	})
+	defer sub.Cancel()
+	graph := NewResource("http://example.org/g")
+	q := NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("1"), graph)
+	n := d.AddAll([]*Quad{
+		q,
+		NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("2"), DefaultGraph),
+		NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("3"), AnyGraph),
+		q,
+	})
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []ChangeKind{QuadAdded, QuadAdded}, changes)
+	assert.Equal(t, q, d.One(nil, nil, nil, graph))
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("2"), nil))
+}
+
+func TestBulkLoader(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("0"))
+	loader := NewBulkLoader(d, 4)
+	assert.NoError(t, loader.Parse(strings.NewReader(`
+<http://example.org/a> <http://example.org/p> "1" .
+<http://example.org/a> <http://example.org/p> "2" <http://example.org/g> .
+`), "application/n-quads"))
+	loader.AddQuad(NewResource("http://example.org/b"), NewResource("http://example.org/p"), NewLiteral("3"), AnyGraph)
+	assert.Equal(t, 3, loader.Pending())
+	assert.Equal(t, 1, d.Len())
+
+	assert.Equal(t, 2, loader.Flush())
+	assert.Equal(t, 0, loader.Pending())
+	assert.Equal(t, 3, d.Len())
+	assert.Len(t, d.All(NewResource("http://example.org/a"), nil, nil, nil), 2)
+	assert.Equal(t, 0, loader.Flush())
+
+	stats := loader.Stats()
+	assert.Equal(t, 2, stats.Added)
+	assert.Equal(t, 1, stats.Skipped)
+	assert.Equal(t, 1, stats.Flushes)
+}
@@ -0,0 +1,443 @@
+package rdf2go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxCanonicalizationCalls bounds the number of times the N-degree hashing
+// step of RDFC-1.0 may run, protecting against poisoned datasets whose blank
+// node structure makes canonicalization exponentially expensive
+const maxCanonicalizationCalls = 1 << 16
+
+// ErrCanonicalizationLimit is returned when a dataset is too complex to be
+// canonicalized within the work limit
+var ErrCanonicalizationLimit = errors.New("dataset is too complex to be canonicalized")
+
+// Canonicalize returns a copy of the dataset in which blank nodes have been
+// relabeled with the canonical identifiers (c14n0, c14n1, ...) computed by
+// the RDF Dataset Canonicalization algorithm (RDFC-1.0). Two datasets are
+// isomorphic when their canonical forms hold the same quads. Quads are added
+// in canonical N-Quads order, and duplicate quads are removed.
+func (d *Dataset) Canonicalize() (*Dataset, error) {
+	labels, err := d.canonicalLabels()
+	if err != nil {
+		return nil, err
+	}
+	quads := make(map[string]*Quad)
+	var keys []string
+	for quad := range d.Quads() {
+		relabeled := NewQuad(relabel(quad.Subject, labels), quad.Predicate, relabel(quad.Object, labels), relabel(quad.Graph, labels))
+		key := canonicalNQuad(relabeled, nil)
+		if _, ok := quads[key]; !ok {
+			quads[key] = relabeled
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	c := NewDataset(d.uri)
+	for _, key := range keys {
+		c.Add(quads[key])
+	}
+	return c, nil
+}
+
+// CanonicalNQuads returns the canonical N-Quads serialization of the dataset,
+// as defined by RDFC-1.0: one line per distinct quad, using canonical blank
+// node identifiers, ordered by Unicode code point.
+func (d *Dataset) CanonicalNQuads() (string, error) {
+	labels, err := d.canonicalLabels()
+	if err != nil {
+		return "", err
+	}
+	return canonicalDocument(d, labels), nil
+}
+
+// Hash returns the SHA-256 digest, in hexadecimal, of the canonical N-Quads
+// serialization of the dataset. Isomorphic datasets have the same hash,
+// whatever the order of their quads, the labels of their blank nodes or the
+// format they were read from, which makes it suitable for cache keys,
+// deduplication and integrity checks.
+func (d *Dataset) Hash() (string, error) {
+	nquads, err := d.CanonicalNQuads()
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(nquads), nil
+}
+
+// Hash returns the digest of the canonical N-Quads serialization of the
+// graph, see Dataset.Hash. It is the hash of a dataset holding the triples
+// of the graph in its default graph.
+func (g *Graph) Hash() (string, error) {
+	return defaultGraphDataset(g).Hash()
+}
+
+// defaultGraphDataset returns a dataset holding the triples of a graph in
+// its default graph
+func defaultGraphDataset(g *Graph) *Dataset {
+	d := NewDataset(g.uri)
+	for t := range g.Triples() {
+		d.AddTriple(t.Subject, t.Predicate, t.Object)
+	}
+	return d
+}
+
+// canonicalDocument serializes the quads of a dataset with the given labels
+func canonicalDocument(d *Dataset, labels map[string]string) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for quad := range d.Quads() {
+		line := canonicalNQuad(quad, func(id string) string { return labels[id] })
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
+
+func relabel(t Term, labels map[string]string) Term {
+	switch t := t.(type) {
+	case *BlankNode:
+		return NewBlankNode(labels[t.ID])
+	case *QuotedTriple:
+		return NewQuotedTriple(relabel(t.Subject, labels), t.Predicate, relabel(t.Object, labels))
+	}
+	return t
+}
+
+// blankNodeIDs appends the identifiers of the blank nodes of a term to ids,
+// including those nested in quoted triples
+func blankNodeIDs(ids []string, t Term) []string {
+	switch t := t.(type) {
+	case *BlankNode:
+		ids = append(ids, t.ID)
+	case *QuotedTriple:
+		ids = blankNodeIDs(ids, t.Subject)
+		ids = blankNodeIDs(ids, t.Object)
+	}
+	return ids
+}
+
+// canonicalNQuad serializes a quad in canonical N-Quads form, including the
+// final line break. Blank node identifiers are mapped with label, when it is
+// not nil.
+func canonicalNQuad(q *Quad, label func(id string) string) string {
+	var b strings.Builder
+	terms := []Term{q.Subject, q.Predicate, q.Object, q.Graph}
+	for _, t := range terms {
+		if t == nil {
+			continue
+		}
+		writeCanonicalTerm(&b, t, label)
+		b.WriteByte(' ')
+	}
+	b.WriteString(".\n")
+	return b.String()
+}
+
+func writeCanonicalTerm(b *strings.Builder, t Term, label func(id string) string) {
+	switch t := t.(type) {
+	case *Resource:
+		b.WriteByte('<')
+		b.WriteString(escapeIRI(t.URI))
+		b.WriteByte('>')
+	case *BlankNode:
+		b.WriteString("_:")
+		if label != nil {
+			b.WriteString(label(t.ID))
+		} else {
+			b.WriteString(t.ID)
+		}
+	case *Literal:
+		b.WriteByte('"')
+		writeEscapedString(b, t.Value)
+		b.WriteByte('"')
+		if len(t.Language) > 0 {
+			b.WriteByte('@')
+			b.WriteString(t.Language)
+		} else if t.Datatype != nil && t.Datatype.RawValue() != xsdNamespace+"string" {
+			b.WriteString("^^<")
+			b.WriteString(t.Datatype.RawValue())
+			b.WriteByte('>')
+		}
+	case *QuotedTriple:
+		b.WriteString("<< ")
+		writeCanonicalTerm(b, t.Subject, label)
+		b.WriteByte(' ')
+		writeCanonicalTerm(b, t.Predicate, label)
+		b.WriteByte(' ')
+		writeCanonicalTerm(b, t.Object, label)
+		b.WriteString(" >>")
+	default:
+		b.WriteString(t.String())
+	}
+}
+
+// identifierIssuer issues blank node identifiers with a prefix and a counter
+type identifierIssuer struct {
+	prefix string
+	issued map[string]string
+	order  []string
+}
+
+func newIdentifierIssuer(prefix string) *identifierIssuer {
+	return &identifierIssuer{prefix: prefix, issued: make(map[string]string)}
+}
+
+func (i *identifierIssuer) issue(id string) string {
+	if issued, ok := i.issued[id]; ok {
+		return issued
+	}
+	issued := fmt.Sprintf("%s%d", i.prefix, len(i.order))
+	i.issued[id] = issued
+	i.order = append(i.order, id)
+	return issued
+}
+
+func (i *identifierIssuer) copy() *identifierIssuer {
+	c := &identifierIssuer{prefix: i.prefix, issued: make(map[string]string, len(i.issued))}
+	for k, v := range i.issued {
+		c.issued[k] = v
+	}
+	c.order = append([]string(nil), i.order...)
+	return c
+}
+
+// canonicalizer holds the state of the RDFC-1.0 algorithm
+type canonicalizer struct {
+	bnodeQuads map[string][]*Quad
+	canonical  *identifierIssuer
+	firstHash  map[string]string
+	calls      int
+}
+
+// canonicalLabels computes the canonical identifier of every blank node of
+// the dataset, without the "_:" prefix
+func (d *Dataset) canonicalLabels() (map[string]string, error) {
+	c := &canonicalizer{
+		bnodeQuads: make(map[string][]*Quad),
+		canonical:  newIdentifierIssuer("c14n"),
+		firstHash:  make(map[string]string),
+	}
+	for quad := range d.Quads() {
+		for _, t := range []Term{quad.Subject, quad.Object, quad.Graph} {
+			for _, id := range blankNodeIDs(nil, t) {
+				c.bnodeQuads[id] = append(c.bnodeQuads[id], quad)
+			}
+		}
+	}
+
+	hashToNodes := make(map[string][]string)
+	for id := range c.bnodeQuads {
+		h := c.hashFirstDegree(id)
+		hashToNodes[h] = append(hashToNodes[h], id)
+	}
+	hashes := make([]string, 0, len(hashToNodes))
+	for h := range hashToNodes {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+
+	// blank nodes with a unique first degree hash get their identifier first
+	var shared []string
+	for _, h := range hashes {
+		if len(hashToNodes[h]) == 1 {
+			c.canonical.issue(hashToNodes[h][0])
+		} else {
+			shared = append(shared, h)
+		}
+	}
+
+	for _, h := range shared {
+		type pathResult struct {
+			hash   string
+			issuer *identifierIssuer
+		}
+		var results []pathResult
+		nodes := append([]string(nil), hashToNodes[h]...)
+		sort.Strings(nodes)
+		for _, id := range nodes {
+			if _, ok := c.canonical.issued[id]; ok {
+				continue
+			}
+			issuer := newIdentifierIssuer("b")
+			issuer.issue(id)
+			hash, issuer, err := c.hashNDegree(id, issuer)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, pathResult{hash, issuer})
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].hash < results[j].hash
+		})
+		for _, result := range results {
+			for _, id := range result.issuer.order {
+				c.canonical.issue(id)
+			}
+		}
+	}
+	return c.canonical.issued, nil
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFirstDegree hashes the quads mentioning a blank node, the node itself
+// being labeled a and every other blank node z
+func (c *canonicalizer) hashFirstDegree(id string) string {
+	if h, ok := c.firstHash[id]; ok {
+		return h
+	}
+	var lines []string
+	for _, quad := range c.bnodeQuads[id] {
+		lines = append(lines, canonicalNQuad(quad, func(other string) string {
+			if other == id {
+				return "a"
+			}
+			return "z"
+		}))
+	}
+	sort.Strings(lines)
+	h := sha256Hex(strings.Join(lines, ""))
+	c.firstHash[id] = h
+	return h
+}
+
+// hashRelated hashes a blank node related to another one through a quad
+func (c *canonicalizer) hashRelated(related string, quad *Quad, issuer *identifierIssuer, position string) string {
+	var id string
+	if issued, ok := c.canonical.issued[related]; ok {
+		id = "_:" + issued
+	} else if issued, ok := issuer.issued[related]; ok {
+		id = "_:" + issued
+	} else {
+		id = c.hashFirstDegree(related)
+	}
+	input := position
+	if position != "g" {
+		input += "<" + quad.Predicate.RawValue() + ">"
+	}
+	return sha256Hex(input + id)
+}
+
+// hashNDegree computes the hash of a blank node from the paths reaching the
+// blank nodes it is related to, trying every order in which they can be
+// labeled and keeping the smallest path
+func (c *canonicalizer) hashNDegree(id string, issuer *identifierIssuer) (string, *identifierIssuer, error) {
+	c.calls++
+	if c.calls > maxCanonicalizationCalls {
+		return "", nil, ErrCanonicalizationLimit
+	}
+
+	related := make(map[string][]string)
+	for _, quad := range c.bnodeQuads[id] {
+		positions := []struct {
+			term     Term
+			position string
+		}{{quad.Subject, "s"}, {quad.Object, "o"}, {quad.Graph, "g"}}
+		for _, p := range positions {
+			for _, b := range blankNodeIDs(nil, p.term) {
+				if b == id {
+					continue
+				}
+				h := c.hashRelated(b, quad, issuer, p.position)
+				related[h] = append(related[h], b)
+			}
+		}
+	}
+	hashes := make([]string, 0, len(related))
+	for h := range related {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+
+	var data strings.Builder
+	for _, h := range hashes {
+		data.WriteString(h)
+		chosenPath := ""
+		var chosenIssuer *identifierIssuer
+		var err error
+		permute(related[h], func(perm []string) bool {
+			issuerCopy := issuer.copy()
+			path := ""
+			var recursion []string
+			worse := func() bool {
+				return len(chosenPath) > 0 && len(path) >= len(chosenPath) && path > chosenPath
+			}
+			for _, r := range perm {
+				if issued, ok := c.canonical.issued[r]; ok {
+					path += "_:" + issued
+				} else {
+					if _, ok := issuerCopy.issued[r]; !ok {
+						recursion = append(recursion, r)
+					}
+					path += "_:" + issuerCopy.issue(r)
+				}
+				if worse() {
+					return true
+				}
+			}
+			for _, r := range recursion {
+				var hash string
+				var resultIssuer *identifierIssuer
+				hash, resultIssuer, err = c.hashNDegree(r, issuerCopy)
+				if err != nil {
+					return false
+				}
+				path += "_:" + issuerCopy.issue(r) + "<" + hash + ">"
+				issuerCopy = resultIssuer
+				if worse() {
+					return true
+				}
+			}
+			if len(chosenPath) == 0 || path < chosenPath {
+				chosenPath, chosenIssuer = path, issuerCopy
+			}
+			return true
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		data.WriteString(chosenPath)
+		issuer = chosenIssuer
+	}
+	return sha256Hex(data.String()), issuer, nil
+}
+
+// permute calls fn with every permutation of items, in lexicographic order,
+// until fn returns false
+func permute(items []string, fn func([]string) bool) {
+	perm := append([]string(nil), items...)
+	sort.Strings(perm)
+	for {
+		if !fn(perm) {
+			return
+		}
+		// next lexicographic permutation
+		i := len(perm) - 2
+		for i >= 0 && perm[i] >= perm[i+1] {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		j := len(perm) - 1
+		for perm[j] <= perm[i] {
+			j--
+		}
+		perm[i], perm[j] = perm[j], perm[i]
+		for l, r := i+1, len(perm)-1; l < r; l, r = l+1, r-1 {
+			perm[l], perm[r] = perm[r], perm[l]
+		}
+	}
+}
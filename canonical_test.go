@@ -0,0 +1,200 @@
+package rdf2go
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTestNQuads(t *testing.T, src string) *Dataset {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(src), "application/n-quads"))
+	return d
+}
+
+func TestCanonicalNQuadsSingleBlankNode(t *testing.T) {
+	d := parseTestNQuads(t, "_:x <http://example.org/p> \"a\\tb\"^^<http://www.w3.org/2001/XMLSchema#string> .\n_:x <http://example.org/q> \"c\"@en _:x .\n")
+	out, err := d.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, "_:c14n0 <http://example.org/p> \"a\\tb\" .\n_:c14n0 <http://example.org/q> \"c\"@en _:c14n0 .\n", out)
+}
+
+func TestCanonicalNQuadsEscaping(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("q\"\\\n\r\x01\x7fé"))
+	out, err := d.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, "<http://example.org/s> <http://example.org/p> \"q\\\"\\\\\\n\\r\\u0001\\u007Fé\" .\n", out)
+}
+
+func TestCanonicalNQuadsSymmetric(t *testing.T) {
+	a := parseTestNQuads(t, "_:a <http://example.org/p> _:b .\n_:b <http://example.org/p> _:a .\n")
+	b := parseTestNQuads(t, "_:y <http://example.org/p> _:x .\n_:x <http://example.org/p> _:y .\n")
+	outA, err := a.CanonicalNQuads()
+	assert.NoError(t, err)
+	outB, err := b.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, outA, outB)
+	assert.Equal(t, "_:c14n0 <http://example.org/p> _:c14n1 .\n_:c14n1 <http://example.org/p> _:c14n0 .\n", outA)
+}
+
+// relabeledCopy returns the quads of d with renamed blank nodes, in random order
+func relabeledCopy(d *Dataset, rng *rand.Rand) *Dataset {
+	names := make(map[string]string)
+	rename := func(term Term) Term {
+		if b, ok := term.(*BlankNode); ok {
+			if _, ok := names[b.ID]; !ok {
+				names[b.ID] = fmt.Sprintf("r%d", rng.Intn(1000000))
+			}
+			return NewBlankNode(names[b.ID])
+		}
+		return term
+	}
+	quads := d.orderedQuads()
+	rng.Shuffle(len(quads), func(i, j int) { quads[i], quads[j] = quads[j], quads[i] })
+	c := NewDataset(d.URI())
+	for _, q := range quads {
+		c.AddQuad(rename(q.Subject), q.Predicate, rename(q.Object), rename(q.Graph))
+	}
+	return c
+}
+
+func TestCanonicalizeIsomorphic(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	d := NewDataset(testDatasetUri)
+	p := NewResource("http://example.org/p")
+	// two identical rings of blank nodes, plus a few distinguishable nodes
+	for ring := 0; ring < 2; ring++ {
+		for i := 0; i < 4; i++ {
+			d.AddTriple(NewBlankNode(fmt.Sprintf("r%d_%d", ring, i)), p, NewBlankNode(fmt.Sprintf("r%d_%d", ring, (i+1)%4)))
+		}
+	}
+	d.AddQuad(NewBlankNode("x"), p, NewLiteral("x"), NewBlankNode("g"))
+	d.AddQuad(NewBlankNode("x"), p, NewBlankNode("r0_0"), NewBlankNode("g"))
+
+	expected, err := d.CanonicalNQuads()
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		out, err := relabeledCopy(d, rng).CanonicalNQuads()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out)
+	}
+
+	c, err := d.Canonicalize()
+	assert.NoError(t, err)
+	assert.Equal(t, d.Len(), c.Len())
+	out, err := c.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, out)
+	for quad := range c.Quads() {
+		if b, ok := quad.Subject.(*BlankNode); ok {
+			assert.True(t, strings.HasPrefix(b.ID, "c14n"))
+		}
+	}
+
+	// a different structure yields a different canonical form
+	d.AddTriple(NewBlankNode("r1_0"), p, NewBlankNode("r1_2"))
+	other, err := d.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.NotEqual(t, expected, other)
+}
+
+func TestCanonicalizeRemovesDuplicates(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	c, err := d.Canonicalize()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestPermute(t *testing.T) {
+	var perms []string
+	permute([]string{"c", "a", "b"}, func(p []string) bool {
+		perms = append(perms, strings.Join(p, ""))
+		return true
+	})
+	assert.Equal(t, []string{"abc", "acb", "bac", "bca", "cab", "cba"}, perms)
+}
+
+// examples from the RDFC-1.0 specification
+func TestCanonicalNQuadsSpecExamples(t *testing.T) {
+	d := parseTestNQuads(t, `<http://example.com/#p> <http://example.com/#q> _:e0 .
+<http://example.com/#p> <http://example.com/#r> _:e1 .
+_:e0 <http://example.com/#s> <http://example.com/#u> .
+_:e1 <http://example.com/#t> <http://example.com/#u> .
+`)
+	out, err := d.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, `<http://example.com/#p> <http://example.com/#q> _:c14n0 .
+<http://example.com/#p> <http://example.com/#r> _:c14n1 .
+_:c14n0 <http://example.com/#s> <http://example.com/#u> .
+_:c14n1 <http://example.com/#t> <http://example.com/#u> .
+`, out)
+
+	d = parseTestNQuads(t, `<http://example.com/#p> <http://example.com/#q> _:e0 .
+<http://example.com/#p> <http://example.com/#q> _:e1 .
+_:e0 <http://example.com/#p> _:e2 .
+_:e1 <http://example.com/#p> _:e3 .
+_:e2 <http://example.com/#r> _:e3 .
+`)
+	out, err = d.CanonicalNQuads()
+	assert.NoError(t, err)
+	assert.Equal(t, `<http://example.com/#p> <http://example.com/#q> _:c14n2 .
+<http://example.com/#p> <http://example.com/#q> _:c14n3 .
+_:c14n0 <http://example.com/#r> _:c14n1 .
+_:c14n2 <http://example.com/#p> _:c14n1 .
+_:c14n3 <http://example.com/#p> _:c14n0 .
+`, out)
+}
+
+func TestHash(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`
+@prefix ex: <http://example.org/> .
+ex:a ex:knows [ ex:name "Bob" ] ; ex:name "Alice" .
+`), "text/turtle"))
+	hash, err := g.Hash()
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// a reordered document with other blank node labels
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(strings.NewReader(`
+_:x <http://example.org/name> "Bob" .
+<http://example.org/a> <http://example.org/name> "Alice" .
+<http://example.org/a> <http://example.org/knows> _:x .
+`), "application/n-triples"))
+	hash2, err := g2.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, hash2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.Serialize(&buf, "text/turtle"))
+	g3 := NewGraph(testUri)
+	assert.NoError(t, g3.Parse(&buf, "text/turtle"))
+	hash3, err := g3.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, hash3)
+
+	g2.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteral("Alicia"))
+	hash2, err = g2.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, hash2)
+
+	d := NewDataset(testDatasetUri)
+	for triple := range g.Triples() {
+		d.AddTriple(triple.Subject, triple.Predicate, triple.Object)
+	}
+	dhash, err := d.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, dhash)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteral("Alice"), NewResource("http://example.org/g"))
+	dhash, err = d.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, dhash)
+}
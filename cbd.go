@@ -0,0 +1,121 @@
+package rdf2go
+
+// CBD returns the concise bounded description of a resource
+// (https://www.w3.org/submission/CBD/): the statements having it as subject,
+// the descriptions of the blank nodes they have as objects, recursively, and
+// the descriptions of the reifications of these statements. When symmetric
+// is true, it returns the symmetric description, which also holds the
+// statements having the resource as object and, recursively, the
+// descriptions of their blank node subjects.
+func (g *Graph) CBD(resource Term, symmetric ...bool) *Graph {
+	out := newGraph(g.uri, g.config, g.httpClient)
+	out.index = newSPOIndex[*Triple]()
+	out.iriPolicy = g.iriPolicy
+	b := &cbdBuilder[*Triple]{
+		match: g.match,
+		terms: func(t *Triple) (Term, Term, Term) {
+			return t.Subject, t.Predicate, t.Object
+		},
+		add:       out.Add,
+		key:       g.iriPolicy.key,
+		symmetric: len(symmetric) > 0 && symmetric[0],
+	}
+	b.describe(resource)
+	return out
+}
+
+// CBD returns the concise bounded description of a resource in graph g of
+// the dataset, nil being the default graph, see Graph.CBD
+func (d *Dataset) CBD(resource Term, g Term, symmetric ...bool) *Graph {
+	out := newGraph(d.uri, d.config, d.httpClient)
+	out.index = newSPOIndex[*Triple]()
+	out.iriPolicy = d.iriPolicy
+	b := &cbdBuilder[*Quad]{
+		match: func(s Term, p Term, o Term, fn func(*Quad) bool) {
+			d.match(s, p, o, g, fn)
+		},
+		terms: func(q *Quad) (Term, Term, Term) {
+			return q.Subject, q.Predicate, q.Object
+		},
+		add: func(q *Quad) {
+			out.Add(q.ToTriple())
+		},
+		key:       d.iriPolicy.key,
+		symmetric: len(symmetric) > 0 && symmetric[0],
+	}
+	b.describe(resource)
+	return out
+}
+
+// cbdBuilder collects the statements of a concise bounded description
+type cbdBuilder[T comparable] struct {
+	match     func(s Term, p Term, o Term, fn func(T) bool)
+	terms     func(T) (Term, Term, Term)
+	add       func(T)
+	key       func(Term) string
+	symmetric bool
+
+	described map[string]bool
+	included  map[T]bool
+}
+
+// describe adds the statements about node, following its blank nodes and the
+// reifications of the statements
+func (b *cbdBuilder[T]) describe(node Term) {
+	if b.described == nil {
+		b.described = make(map[string]bool)
+		b.included = make(map[T]bool)
+	}
+	k := b.key(node)
+	if b.described[k] {
+		return
+	}
+	b.described[k] = true
+
+	var statements []T
+	collect := func(st T) bool {
+		if !b.included[st] {
+			b.included[st] = true
+			statements = append(statements, st)
+		}
+		return true
+	}
+	b.match(node, nil, nil, collect)
+	if b.symmetric {
+		b.match(nil, nil, node, collect)
+	}
+	for _, st := range statements {
+		b.add(st)
+	}
+	for _, st := range statements {
+		s, p, o := b.terms(st)
+		if _, blank := o.(*BlankNode); blank {
+			b.describe(o)
+		}
+		if _, blank := s.(*BlankNode); blank && b.symmetric {
+			b.describe(s)
+		}
+		b.match(nil, rdfSubject, s, func(r T) bool {
+			reification, _, _ := b.terms(r)
+			if b.reifies(reification, p, o) {
+				b.describe(reification)
+			}
+			return true
+		})
+	}
+}
+
+// reifies tells whether a node has the rdf:predicate and rdf:object of a
+// statement
+func (b *cbdBuilder[T]) reifies(node Term, p Term, o Term) bool {
+	found := 0
+	b.match(node, rdfPredicate, p, func(T) bool {
+		found++
+		return false
+	})
+	b.match(node, rdfObject, o, func(T) bool {
+		found++
+		return false
+	})
+	return found == 2
+}
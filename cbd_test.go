@@ -0,0 +1,48 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCBDTurtle = `
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix ex: <http://example.org/> .
+
+ex:alice ex:name "Alice" ;
+	ex:address [ ex:city "Oslo" ; ex:geo [ ex:lat "59.9" ] ] ;
+	ex:knows ex:bob .
+ex:bob ex:name "Bob" ; ex:knows ex:alice .
+[ ex:member ex:alice ; ex:label "club" ] .
+[ rdf:subject ex:alice ; rdf:predicate ex:knows ; rdf:object ex:bob ; ex:source ex:survey ] .
+`
+
+func TestCBD(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(testCBDTurtle), "text/turtle"))
+
+	cbd := g.CBD(ex.Get("alice"))
+	assert.Equal(t, 10, cbd.Len())
+	assert.NotNil(t, cbd.One(nil, ex.Get("lat"), NewLiteral("59.9")))
+	assert.NotNil(t, cbd.One(nil, ex.Get("source"), ex.Get("survey")))
+	assert.Nil(t, cbd.One(ex.Get("bob"), nil, nil))
+	assert.Nil(t, cbd.One(nil, ex.Get("member"), nil))
+
+	scbd := g.CBD(ex.Get("alice"), true)
+	assert.Equal(t, 13, scbd.Len())
+	assert.NotNil(t, scbd.One(ex.Get("bob"), ex.Get("knows"), ex.Get("alice")))
+	assert.NotNil(t, scbd.One(nil, ex.Get("label"), NewLiteral("club")))
+	assert.Nil(t, scbd.One(ex.Get("bob"), ex.Get("name"), nil))
+
+	d := NewDataset(testDatasetUri)
+	graph := NewResource("http://example.org/g")
+	for triple := range g.Triples() {
+		d.AddQuad(triple.Subject, triple.Predicate, triple.Object, graph)
+	}
+	assert.Equal(t, 10, d.CBD(ex.Get("alice"), graph).Len())
+	assert.Equal(t, 13, d.CBD(ex.Get("alice"), graph, true).Len())
+	assert.Equal(t, 0, d.CBD(ex.Get("alice"), nil).Len())
+}
@@ -0,0 +1,153 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// changeSetSampleSize is the number of quads listed by ChangeSet.String
+const changeSetSampleSize = 10
+
+// ChangeSet describes the quads added and removed by a mutating operation.
+// Operations run in dry-run mode return the change set they would have
+// applied without modifying the graph or dataset.
+type ChangeSet struct {
+	Added   []*Quad
+	Removed []*Quad
+	DryRun  bool
+}
+
+// Len returns the total number of quads added and removed
+func (c *ChangeSet) Len() int {
+	return len(c.Added) + len(c.Removed)
+}
+
+// Sample returns at most n of the added and removed quads
+func (c *ChangeSet) Sample(n int) (added []*Quad, removed []*Quad) {
+	added = c.Added
+	if len(added) > n {
+		added = added[:n]
+	}
+	removed = c.Removed
+	if len(removed) > n {
+		removed = removed[:n]
+	}
+	return added, removed
+}
+
+// String returns a human readable summary of the change set, with counts and
+// a sample of the affected quads
+func (c *ChangeSet) String() string {
+	var b strings.Builder
+	if c.DryRun {
+		b.WriteString("dry run: ")
+	}
+	fmt.Fprintf(&b, "%d added, %d removed\n", len(c.Added), len(c.Removed))
+	added, removed := c.Sample(changeSetSampleSize)
+	for _, quad := range added {
+		b.WriteString("+ " + quad.String() + "\n")
+	}
+	for _, quad := range removed {
+		b.WriteString("- " + quad.String() + "\n")
+	}
+	return b.String()
+}
+
+// SPARQLUpdate returns the SPARQL update applying the change set, a DELETE
+// DATA operation for the removed quads followed by an INSERT DATA operation
+// for the added ones. It fails when a removed quad holds a blank node, which
+// DELETE DATA does not allow.
+func (c *ChangeSet) SPARQLUpdate() (string, error) {
+	var b strings.Builder
+	for _, quad := range c.Removed {
+		for _, term := range []Term{quad.Subject, quad.Object, quad.Graph} {
+			if _, ok := term.(*BlankNode); ok {
+				return "", errors.New("cannot delete a quad holding a blank node with DELETE DATA: " + quad.String())
+			}
+		}
+	}
+	writeUpdateData(&b, "DELETE DATA", c.Removed)
+	writeUpdateData(&b, "INSERT DATA", c.Added)
+	return b.String(), nil
+}
+
+// writeUpdateData writes a SPARQL DELETE DATA or INSERT DATA operation,
+// nothing for no quads
+func writeUpdateData(b *strings.Builder, operation string, quads []*Quad) {
+	if len(quads) == 0 {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteString(";\n")
+	}
+	b.WriteString(operation + " {\n")
+	for _, quad := range quads {
+		triple := encodeTerm(quad.Subject) + " " + encodeTerm(quad.Predicate) + " " + encodeTerm(quad.Object) + " ."
+		if quad.Graph == nil {
+			b.WriteString("  " + triple + "\n")
+		} else {
+			b.WriteString("  GRAPH " + encodeTerm(quad.Graph) + " { " + triple + " }\n")
+		}
+	}
+	b.WriteString("}\n")
+}
+
+// Apply applies a change set to the dataset, removing quads first
+func (d *Dataset) Apply(c *ChangeSet) {
+	for _, quad := range c.Removed {
+		d.Remove(quad)
+	}
+	for _, quad := range c.Added {
+		d.Add(quad)
+	}
+}
+
+// RemoveMatching removes every quad matching the given pattern of S, P, O, G
+// objects and returns the removed quads. When dryRun is true, the dataset is
+// left untouched and the returned change set lists the quads that would
+// have been removed.
+func (d *Dataset) RemoveMatching(s Term, p Term, o Term, g Term, dryRun ...bool) *ChangeSet {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	c.Removed = d.All(s, p, o, g)
+	if !c.DryRun {
+		d.Apply(c)
+	}
+	return c
+}
+
+// Apply applies a change set to the graph, ignoring the graph term of quads
+func (g *Graph) Apply(c *ChangeSet) {
+	for _, quad := range c.Removed {
+		for _, triple := range g.All(quad.Subject, quad.Predicate, quad.Object) {
+			g.Remove(triple)
+		}
+	}
+	for _, quad := range c.Added {
+		g.Add(quad.ToTriple())
+	}
+}
+
+// RemoveMatching removes every triple matching the given pattern of S, P, O
+// objects. When dryRun is true, the graph is left untouched and the returned
+// change set lists the statements that would have been removed.
+func (g *Graph) RemoveMatching(s Term, p Term, o Term, dryRun ...bool) *ChangeSet {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	var triples []*Triple
+	if s == nil && p == nil && o == nil {
+		for triple := range g.Triples() {
+			triples = append(triples, triple)
+		}
+	} else {
+		triples = g.All(s, p, o)
+	}
+	for _, triple := range triples {
+		c.Removed = append(c.Removed, NewTripleQuad(triple))
+	}
+	if !c.DryRun {
+		for _, triple := range triples {
+			g.Remove(triple)
+		}
+	}
+	return c
+}
@@ -0,0 +1,93 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetRemoveMatchingDryRun(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	g1 := NewResource("http://example.org/graph1")
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("c"), g1)
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("d"), g1)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("e"))
+
+	c := d.RemoveMatching(NewResource("a"), nil, nil, g1, true)
+	assert.True(t, c.DryRun)
+	assert.Equal(t, 2, len(c.Removed))
+	assert.Equal(t, 3, d.Len())
+	assert.Contains(t, c.String(), "dry run: 0 added, 2 removed")
+
+	c = d.RemoveMatching(NewResource("a"), nil, nil, g1)
+	assert.False(t, c.DryRun)
+	assert.Equal(t, 2, len(c.Removed))
+	assert.Equal(t, 1, d.Len())
+}
+
+func TestDatasetApply(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	old := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil)
+	d.Add(old)
+
+	d.Apply(&ChangeSet{
+		Added:   []*Quad{NewQuad(NewResource("a"), NewResource("b"), NewResource("d"), nil)},
+		Removed: []*Quad{old},
+	})
+	assert.Equal(t, 1, d.Len())
+	assert.NotNil(t, d.One(nil, nil, NewResource("d"), nil))
+}
+
+func TestChangeSetSample(t *testing.T) {
+	c := &ChangeSet{}
+	for i := 0; i < 5; i++ {
+		c.Added = append(c.Added, NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil))
+	}
+	added, removed := c.Sample(3)
+	assert.Equal(t, 3, len(added))
+	assert.Equal(t, 0, len(removed))
+	assert.Equal(t, 5, c.Len())
+}
+
+func TestGraphRemoveMatchingDryRun(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.AddTriple(NewResource("x"), NewResource("b"), NewResource("c"))
+
+	c := g.RemoveMatching(nil, nil, nil, true)
+	assert.Equal(t, 2, len(c.Removed))
+	assert.Equal(t, 2, g.Len())
+
+	c = g.RemoveMatching(NewResource("a"), nil, nil)
+	assert.Equal(t, 1, len(c.Removed))
+	assert.Equal(t, 1, g.Len())
+}
+
+func TestChangeSetSPARQLUpdate(t *testing.T) {
+	g1 := NewResource("http://example.org/graph1")
+	old := NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"), nil)
+	c := &ChangeSet{
+		Removed: []*Quad{old},
+		Added: []*Quad{
+			NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteralWithLanguage("d", "en"), nil),
+			NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewBlankNode("e"), g1),
+		},
+	}
+	update, err := c.SPARQLUpdate()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE DATA {\n  <http://example.org/a> <http://example.org/b> \"c\" .\n}\n;\nINSERT DATA {\n  <http://example.org/a> <http://example.org/b> \"d\"@en .\n  GRAPH <http://example.org/graph1> { <http://example.org/a> <http://example.org/b> _:e . }\n}\n", update)
+
+	d := NewDataset(testDatasetUri)
+	d.Add(old)
+	_, err = d.Update(update)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Len())
+	assert.Nil(t, d.One(nil, nil, NewLiteral("c"), nil))
+	assert.NotNil(t, d.One(nil, nil, NewLiteralWithLanguage("d", "en"), nil))
+
+	update, err = (&ChangeSet{}).SPARQLUpdate()
+	assert.NoError(t, err)
+	assert.Empty(t, update)
+	_, err = (&ChangeSet{Removed: c.Added}).SPARQLUpdate()
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package rdf2go
+
+import (
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses exported documents, and decompresses loaded ones
+type Codec interface {
+	// Extension returns the file extension of compressed documents, such as
+	// .gz, which CodecForPath maps back to the codec
+	Extension() string
+	// NewWriter returns a writer compressing to w, which must be closed to
+	// flush the compressed stream
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader decompressing r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Built-in codecs
+var (
+	Gzip Codec = gzipCodec{}
+	Zstd Codec = zstdCodec{}
+)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = []Codec{Gzip, Zstd}
+)
+
+// RegisterCodec makes a codec known to CodecForPath, replacing the codec
+// registered for the same extension if any
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	for i, c := range codecs {
+		if c.Extension() == codec.Extension() {
+			codecs[i] = codec
+			return
+		}
+	}
+	codecs = append(codecs, codec)
+}
+
+// CodecForPath returns the codec of a file name or object key after its
+// extension, such as Gzip for dump.nq.gz, along with the name without the
+// extension of the codec, so that the format of the document can be
+// guessed from the rest. The codec is nil for names without a registered
+// compression extension.
+func CodecForPath(name string) (Codec, string) {
+	ext := path.Ext(name)
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	for _, c := range codecs {
+		if strings.EqualFold(c.Extension(), ext) {
+			return c, strings.TrimSuffix(name, ext)
+		}
+	}
+	return nil, name
+}
+
+// compressTo calls write with a writer compressing to w with codec, which
+// may be nil for no compression
+func compressTo(w io.Writer, codec Codec, write func(io.Writer) error) error {
+	if codec == nil {
+		return write(w)
+	}
+	cw, err := codec.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	if err := write(cw); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Extension() string {
+	return ".gz"
+}
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) Extension() string {
+	return ".zst"
+}
+
+func (zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
@@ -0,0 +1,62 @@
+package rdf2go
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecForPath(t *testing.T) {
+	codec, name := CodecForPath("dumps/all.nq.gz")
+	assert.Equal(t, Gzip, codec)
+	assert.Equal(t, "dumps/all.nq", name)
+	codec, name = CodecForPath("all.ttl.ZST")
+	assert.Equal(t, Zstd, codec)
+	assert.Equal(t, "all.ttl", name)
+	codec, name = CodecForPath("all.ttl")
+	assert.Nil(t, codec)
+	assert.Equal(t, "all.ttl", name)
+}
+
+func TestStreamCodec(t *testing.T) {
+	for _, codec := range []Codec{Gzip, Zstd} {
+		g := NewGraphWithOptions(testUri, WithCodec(codec))
+		g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+		buf := new(bytes.Buffer)
+		assert.NoError(t, g.Stream(buf, "application/n-triples", nil))
+		assert.NotEqual(t, g.String(), buf.String())
+		r, err := codec.NewReader(buf)
+		assert.NoError(t, err)
+		out, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		r.Close()
+		assert.Equal(t, g.String(), string(out))
+	}
+}
+
+func TestSaveCompressedObjectURI(t *testing.T) {
+	fake, server, store := newFakeS3()
+	defer server.Close()
+
+	d := NewDatasetWithOptions("", WithObjectStore("s3", store))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"), NewResource("http://example.org/g"))
+	assert.NoError(t, d.SaveURI("s3://bucket/all.trig.zst", ""))
+	assert.Equal(t, "application/trig", fake.types["/bucket/all.trig.zst"])
+	assert.Equal(t, []byte{0x28, 0xb5, 0x2f, 0xfd}, fake.objects["/bucket/all.trig.zst"][:4])
+
+	d2 := NewDatasetWithOptions("", WithObjectStore("s3", store), WithStrict(true))
+	assert.NoError(t, d2.LoadURI("s3://bucket/all.trig.zst"))
+	changes, err := Diff(d, d2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, changes.Len())
+
+	d3 := NewDatasetWithOptions("", WithObjectStore("s3", store), WithCodec(Gzip))
+	d3.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"), nil)
+	assert.NoError(t, d3.SaveURI("s3://bucket/all", "application/n-quads"))
+	r, err := Gzip.NewReader(bytes.NewReader(fake.objects["/bucket/all"]))
+	assert.NoError(t, err)
+	out, _ := io.ReadAll(r)
+	assert.Equal(t, "<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n", string(out))
+}
@@ -0,0 +1,107 @@
+package rdf2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CoercionError lists the literals whose lexical form is not valid for the
+// datatype they were to be given by CoerceDatatypes
+type CoercionError struct {
+	Invalid []*Quad
+}
+
+func (e *CoercionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d literals cannot be coerced", len(e.Invalid))
+	for i, quad := range e.Invalid {
+		if i == changeSetSampleSize {
+			b.WriteString(", ...")
+			break
+		}
+		b.WriteString("\n" + quad.String())
+	}
+	return b.String()
+}
+
+// CoerceDatatypes gives a datatype to the plain literals used as objects of
+// the given predicates, which map predicate IRIs to datatype IRIs, in every
+// graph of the dataset. Literals with a language tag or a datatype other than
+// xsd:string are left untouched. The lexical forms are validated first:
+// when some are not valid for their new datatype (numbers, booleans, dates
+// and times), a *CoercionError is returned and nothing is changed. When
+// dryRun is true, the dataset is left untouched and the returned change set
+// lists the changes that would have been made.
+func (d *Dataset) CoerceDatatypes(datatypes map[string]string, dryRun ...bool) (*ChangeSet, error) {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	var invalid []*Quad
+	for _, quad := range d.orderedQuads() {
+		lit, ok := coerceLiteral(quad, datatypes)
+		if !ok {
+			continue
+		}
+		if !wellFormedLiteral(lit) {
+			invalid = append(invalid, quad)
+			continue
+		}
+		c.Removed = append(c.Removed, quad)
+		c.Added = append(c.Added, NewQuad(quad.Subject, quad.Predicate, lit, quad.Graph))
+	}
+	if len(invalid) > 0 {
+		return nil, &CoercionError{Invalid: invalid}
+	}
+	if !c.DryRun {
+		d.Apply(c)
+	}
+	return c, nil
+}
+
+// CoerceDatatypes gives a datatype to the plain literals used as objects of
+// the given predicates, see Dataset.CoerceDatatypes
+func (g *Graph) CoerceDatatypes(datatypes map[string]string, dryRun ...bool) (*ChangeSet, error) {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	var invalid []*Quad
+	for predicate := range datatypes {
+		for _, triple := range g.All(nil, NewResource(predicate), nil) {
+			quad := NewTripleQuad(triple)
+			lit, ok := coerceLiteral(quad, datatypes)
+			if !ok {
+				continue
+			}
+			if !wellFormedLiteral(lit) {
+				invalid = append(invalid, quad)
+				continue
+			}
+			c.Removed = append(c.Removed, quad)
+			c.Added = append(c.Added, NewQuad(quad.Subject, quad.Predicate, lit, nil))
+		}
+	}
+	if len(invalid) > 0 {
+		sortQuads(invalid)
+		return nil, &CoercionError{Invalid: invalid}
+	}
+	if !c.DryRun {
+		g.Apply(c)
+	}
+	return c, nil
+}
+
+// coerceLiteral returns the object of a statement with the datatype of its
+// predicate, if it is a plain literal to coerce
+func coerceLiteral(quad *Quad, datatypes map[string]string) (*Literal, bool) {
+	datatype, ok := datatypes[quad.Predicate.RawValue()]
+	if !ok {
+		return nil, false
+	}
+	lit, ok := quad.Object.(*Literal)
+	if !ok || len(lit.Language) > 0 {
+		return nil, false
+	}
+	if lit.Datatype != nil && lit.Datatype.RawValue() != xsdNamespace+"string" {
+		return nil, false
+	}
+	if datatype == xsdNamespace+"string" && lit.Datatype != nil {
+		return nil, false
+	}
+	return NewLiteralWithDatatype(lit.Value, NewResource(datatype)).(*Literal), true
+}
@@ -0,0 +1,59 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetCoerceDatatypes(t *testing.T) {
+	age := NewResource("http://xmlns.com/foaf/0.1/age")
+	name := NewResource("http://xmlns.com/foaf/0.1/name")
+	integer := NewResource(xsdNamespace + "integer")
+	datatypes := map[string]string{age.RawValue(): integer.RawValue()}
+
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), age, NewLiteral("42"))
+	d.AddQuad(NewResource("b"), age, NewLiteral("7"), NewResource("g"))
+	d.AddQuad(NewResource("c"), age, NewLiteralWithDatatype("8", integer), NewResource("g"))
+	d.AddTriple(NewResource("a"), name, NewLiteral("12"))
+
+	c, err := d.CoerceDatatypes(datatypes, true)
+	assert.NoError(t, err)
+	assert.Len(t, c.Added, 2)
+	assert.NotNil(t, d.One(NewResource("a"), age, NewLiteral("42"), nil))
+
+	c, err = d.CoerceDatatypes(datatypes)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, c.Len())
+	assert.NotNil(t, d.One(NewResource("a"), age, NewLiteralWithDatatype("42", integer), nil))
+	assert.NotNil(t, d.One(NewResource("b"), age, NewLiteralWithDatatype("7", integer), NewResource("g")))
+	assert.NotNil(t, d.One(NewResource("a"), name, NewLiteral("12"), nil))
+	assert.Equal(t, 4, d.Len())
+
+	d.AddTriple(NewResource("d"), age, NewLiteral("old"))
+	d.AddTriple(NewResource("e"), age, NewLiteral("9"))
+	_, err = d.CoerceDatatypes(datatypes)
+	if assert.IsType(t, &CoercionError{}, err) {
+		assert.Len(t, err.(*CoercionError).Invalid, 1)
+		assert.Contains(t, err.Error(), `"old"`)
+	}
+	assert.NotNil(t, d.One(NewResource("e"), age, NewLiteral("9"), nil))
+}
+
+func TestGraphCoerceDatatypes(t *testing.T) {
+	age := NewResource("http://xmlns.com/foaf/0.1/age")
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), age, NewLiteral("42"))
+	g.AddTriple(NewResource("b"), age, NewLiteralWithLanguage("42", "en"))
+
+	c, err := g.CoerceDatatypes(map[string]string{age.RawValue(): xsdNamespace + "int"})
+	assert.NoError(t, err)
+	assert.Len(t, c.Added, 1)
+	assert.NotNil(t, g.One(NewResource("a"), age, NewLiteralWithDatatype("42", NewResource(xsdNamespace+"int"))))
+	assert.Equal(t, 2, g.Len())
+
+	g.AddTriple(NewResource("c"), age, NewLiteral("3000000000"))
+	_, err = g.CoerceDatatypes(map[string]string{age.RawValue(): xsdNamespace + "int"})
+	assert.Error(t, err)
+}
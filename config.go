@@ -0,0 +1,615 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config holds the settings of a graph or dataset. A package-level default
+// applies to every graph and dataset, and can be changed with
+// SetDefaultConfig; options passed to NewGraphWithOptions and
+// NewDatasetWithOptions override it for a single graph or dataset.
+type Config struct {
+	// Prefixes maps prefixes to namespaces, used to abbreviate IRIs in
+	// output meant for humans. It defaults to rdf, rdfs, xsd and owl. The
+	// other prefixes are bound to new graphs and datasets, see Graph.Bind.
+	Prefixes map[string]string
+	// Base is the IRI that relative IRIs of parsed documents are resolved
+	// against, defaulting to the URI of the graph or dataset
+	Base string
+	// Strict makes parsing and loading fail on input that is tolerated by
+	// default: statements of named graphs parsed into a Graph, which are
+	// otherwise dropped.
+	Strict bool
+	// Duplicates tells how Parse handles the statements repeated within a
+	// document, which are all added by default
+	Duplicates DuplicatePolicy
+	// Lenient makes Parse skip the malformed statements of N-Triples,
+	// N-Quads, Turtle and TriG documents instead of failing on the first
+	// one, and return the errors of all of them in a *ParseErrors
+	Lenient bool
+	// Limits bounds the resources used by Parse, for untrusted documents
+	Limits ParseLimits
+
+	// Unindexed makes NewGraphWithOptions and NewDatasetWithOptions create
+	// graphs and datasets without lookup indexes, see NewUnindexedGraph
+	Unindexed bool
+	// ThreadSafe guards graphs and datasets with a read-write lock, so that
+	// they can be added to, removed from, looked up and iterated by several
+	// goroutines at once. Lookups and iterations then work on a snapshot of
+	// the matching statements.
+	ThreadSafe bool
+	// InternTerms gives each graph and dataset a TermDictionary, so that
+	// their statements share equal terms, see Graph.Terms
+	InternTerms bool
+	// Provenance makes the datasets record the URI of the document each
+	// quad was loaded from by LoadURI, see Dataset.Source
+	Provenance bool
+	// UnionDefaultGraph makes the pattern matching methods of datasets, such
+	// as One, All and GetDefaultGraph, treat a nil graph as UnionGraph, the
+	// union of the named graphs, as many SPARQL stores do. The stored
+	// default graph remains reachable with DefaultGraph. Adding and
+	// removing quads is not affected.
+	UnionDefaultGraph bool
+
+	// HTTPClient is used to fetch remote documents. When nil, a client is
+	// created from SkipVerify and Timeout.
+	HTTPClient *http.Client
+	// SkipVerify disables the verification of TLS certificates
+	SkipVerify bool
+	// Certificates are the client certificates presented to the servers
+	// requesting them, and RootCAs the authorities trusted to sign the
+	// certificates of servers, the system ones being used when nil. Both
+	// are ignored when HTTPClient is set.
+	Certificates []tls.Certificate
+	RootCAs      *x509.CertPool
+	// Timeout bounds the duration of remote requests, zero meaning none
+	Timeout time.Duration
+	// UserAgent is sent with remote requests, when not empty
+	UserAgent string
+	// RequestHooks are called in turn on the requests of LoadURI and of
+	// LDPClient before they are sent, to add authentication headers or to
+	// trace and log them. A hook returning an error aborts the request.
+	RequestHooks []RequestHook
+	// MaxBytes limits the size of the documents fetched by LoadURI, zero
+	// meaning no limit
+	MaxBytes int64
+	// Retry sets how LoadURI, and LDPClient for requests without a body,
+	// retry those failing with a network error or a transient HTTP
+	// status. Requests are not retried by default.
+	Retry RetryPolicy
+	// HTTPCache stores the documents fetched over HTTP by LoadURI, which
+	// revalidates them with conditional requests. Documents are not cached
+	// when nil.
+	HTTPCache HTTPCache
+
+	// ObjectStores maps URI schemes, such as s3 or gs, to the object stores
+	// read by LoadURI and written by SaveURI for URIs of that scheme
+	ObjectStores map[string]ObjectStore
+	// Resolvers maps URI schemes, such as did or ipfs, to the resolvers
+	// dereferencing the IRIs of that scheme for LoadURI. They take
+	// precedence over object stores and HTTP.
+	Resolvers map[string]Resolver
+
+	// DocumentLoader fetches the remote contexts referenced by JSON-LD
+	// input. When nil, a package-level CachingDocumentLoader is used.
+	DocumentLoader DocumentLoader
+
+	// Codec compresses the output of Stream and SaveURI, which is not
+	// compressed when nil
+	Codec Codec
+
+	// Serialize controls the layout of Turtle and TriG output
+	Serialize SerializeOptions
+	// Order sets the order in which IterTriples, Triples, IterQuads and
+	// Quads yield statements, which is unspecified by default
+	Order IterationOrder
+
+	// Logger receives diagnostic messages, such as remote fetches and
+	// tolerated errors. Logging is disabled when nil.
+	Logger *slog.Logger
+}
+
+// IterationOrder tells in which order the statements of a graph or dataset
+// are iterated
+type IterationOrder int
+
+const (
+	// OrderUnspecified iterates in the random order of Go maps, which is the
+	// fastest
+	OrderUnspecified IterationOrder = iota
+	// OrderInsertion iterates in the order statements were added, sorting
+	// them on each iteration
+	OrderInsertion
+	// OrderSorted iterates in the lexical order of the N-Triples or N-Quads
+	// serialization of statements, which is reproducible across runs and
+	// processes but costs serializing them on each iteration
+	OrderSorted
+)
+
+// RequestHook prepares a remote request, see Config.RequestHooks
+type RequestHook func(req *http.Request) error
+
+// Option changes a setting of a Config
+type Option func(*Config)
+
+var (
+	defaultConfigMu sync.RWMutex
+	defaultConfig   = Config{Prefixes: maps.Clone(builtinPrefixes)}
+)
+
+// builtinPrefixes are the prefixes of the package-level configuration. They
+// abbreviate IRIs in HTML, but are not bound to new graphs and datasets so
+// that their Turtle and TriG output is unchanged.
+var builtinPrefixes = map[string]string{
+	"rdf":  rdfNamespace,
+	"rdfs": rdfsNamespace,
+	"xsd":  xsdNamespace,
+	"owl":  owlNamespace,
+}
+
+// boundPrefixes returns the prefixes bound to a new graph or dataset, those
+// of the configuration that are not built in
+func (c *Config) boundPrefixes() map[string]string {
+	var prefixes map[string]string
+	for prefix, ns := range c.Prefixes {
+		if builtinPrefixes[prefix] == ns {
+			continue
+		}
+		if prefixes == nil {
+			prefixes = make(map[string]string)
+		}
+		prefixes[prefix] = ns
+	}
+	return prefixes
+}
+
+// DefaultConfig returns a copy of the package-level configuration
+func DefaultConfig() Config {
+	defaultConfigMu.RLock()
+	defer defaultConfigMu.RUnlock()
+	return defaultConfig.copy()
+}
+
+// SetDefaultConfig replaces the package-level configuration. It applies to
+// the graphs and datasets created afterwards.
+func SetDefaultConfig(c Config) {
+	defaultConfigMu.Lock()
+	defer defaultConfigMu.Unlock()
+	defaultConfig = c.copy()
+}
+
+// newConfig returns the default configuration modified by options
+func newConfig(opts ...Option) *Config {
+	c := DefaultConfig()
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+func (c Config) copy() Config {
+	c.Prefixes = maps.Clone(c.Prefixes)
+	c.ObjectStores = maps.Clone(c.ObjectStores)
+	c.Resolvers = maps.Clone(c.Resolvers)
+	c.RequestHooks = slices.Clone(c.RequestHooks)
+	c.Certificates = slices.Clone(c.Certificates)
+	return c
+}
+
+// WithPrefixes adds prefix to namespace mappings
+func WithPrefixes(prefixes map[string]string) Option {
+	return func(c *Config) {
+		if c.Prefixes == nil {
+			c.Prefixes = make(map[string]string, len(prefixes))
+		}
+		maps.Copy(c.Prefixes, prefixes)
+	}
+}
+
+// WithBase sets the base IRI of parsed documents, see Config.Base
+func WithBase(base string) Option {
+	return func(c *Config) {
+		c.Base = base
+	}
+}
+
+// WithIndexing tells whether graphs and datasets maintain lookup indexes,
+// which they do by default
+func WithIndexing(indexed bool) Option {
+	return func(c *Config) {
+		c.Unindexed = !indexed
+	}
+}
+
+// WithProvenance makes datasets record the documents their quads are loaded
+// from, see Config.Provenance
+func WithProvenance(provenance bool) Option {
+	return func(c *Config) {
+		c.Provenance = provenance
+	}
+}
+
+// WithInternTerms makes graphs and datasets intern their terms, see
+// Config.InternTerms
+func WithInternTerms(intern bool) Option {
+	return func(c *Config) {
+		c.InternTerms = intern
+	}
+}
+
+// WithUnionDefaultGraph makes datasets match a nil graph against the union
+// of their named graphs, see Config.UnionDefaultGraph
+func WithUnionDefaultGraph(union bool) Option {
+	return func(c *Config) {
+		c.UnionDefaultGraph = union
+	}
+}
+
+// WithThreadSafe makes graphs and datasets safe for concurrent use, see
+// Config.ThreadSafe
+func WithThreadSafe(safe bool) Option {
+	return func(c *Config) {
+		c.ThreadSafe = safe
+	}
+}
+
+// WithStrict sets strict parsing and loading, see Config.Strict
+func WithStrict(strict bool) Option {
+	return func(c *Config) {
+		c.Strict = strict
+	}
+}
+
+// WithDuplicates sets how Parse handles duplicated statements
+func WithDuplicates(policy DuplicatePolicy) Option {
+	return func(c *Config) {
+		c.Duplicates = policy
+	}
+}
+
+// WithLenient sets whether Parse skips malformed statements
+func WithLenient(lenient bool) Option {
+	return func(c *Config) {
+		c.Lenient = lenient
+	}
+}
+
+// WithParseLimits bounds the documents parsed by graphs and datasets, see
+// Config.Limits
+func WithParseLimits(limits ParseLimits) Option {
+	return func(c *Config) {
+		c.Limits = limits
+	}
+}
+
+// WithHTTPClient sets the client used to fetch remote documents
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// WithSkipVerify disables the verification of TLS certificates
+func WithSkipVerify(skip bool) Option {
+	return func(c *Config) {
+		c.SkipVerify = skip
+	}
+}
+
+// WithTimeout bounds the duration of remote requests
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header of remote requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithRequestHook adds a hook called on remote requests before they are
+// sent, see Config.RequestHooks
+func WithRequestHook(hook RequestHook) Option {
+	return func(c *Config) {
+		c.RequestHooks = append(c.RequestHooks, hook)
+	}
+}
+
+// WithBearerToken authenticates remote requests with an OAuth 2.0 bearer
+// token
+func WithBearerToken(token string) Option {
+	return WithRequestHook(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// WithBasicAuth authenticates remote requests with a user name and password
+func WithBasicAuth(username string, password string) Option {
+	return WithRequestHook(func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// WithClientCertificate adds a client certificate presented to the servers
+// requesting one, such as one loaded with tls.LoadX509KeyPair
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(c *Config) {
+		c.Certificates = append(c.Certificates, cert)
+	}
+}
+
+// WithRootCAs sets the authorities trusted to sign the certificates of
+// servers, such as those of an enterprise network
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Config) {
+		c.RootCAs = pool
+	}
+}
+
+// WithMaxBytes limits the size of the documents fetched by LoadURI
+func WithMaxBytes(n int64) Option {
+	return func(c *Config) {
+		c.MaxBytes = n
+	}
+}
+
+// WithRetry sets the retry policy of remote requests, see Config.Retry
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Config) {
+		c.Retry = policy
+	}
+}
+
+// WithHTTPCache sets the cache of the documents fetched over HTTP, see
+// Config.HTTPCache
+func WithHTTPCache(cache HTTPCache) Option {
+	return func(c *Config) {
+		c.HTTPCache = cache
+	}
+}
+
+// WithOrder sets the iteration order of statements, see Config.Order
+func WithOrder(order IterationOrder) Option {
+	return func(c *Config) {
+		c.Order = order
+	}
+}
+
+// WithObjectStore sets the object store used for URIs of the given scheme
+func WithObjectStore(scheme string, store ObjectStore) Option {
+	return func(c *Config) {
+		if c.ObjectStores == nil {
+			c.ObjectStores = make(map[string]ObjectStore)
+		}
+		c.ObjectStores[scheme] = store
+	}
+}
+
+// WithResolver sets the resolver used for IRIs of the given scheme
+func WithResolver(scheme string, resolver Resolver) Option {
+	return func(c *Config) {
+		if c.Resolvers == nil {
+			c.Resolvers = make(map[string]Resolver)
+		}
+		c.Resolvers[strings.ToLower(scheme)] = resolver
+	}
+}
+
+// WithDocumentLoader sets the loader of remote JSON-LD contexts
+func WithDocumentLoader(loader DocumentLoader) Option {
+	return func(c *Config) {
+		c.DocumentLoader = loader
+	}
+}
+
+// WithCodec compresses the output of Stream and SaveURI with codec
+func WithCodec(codec Codec) Option {
+	return func(c *Config) {
+		c.Codec = codec
+	}
+}
+
+// WithLogger sets the logger receiving diagnostic messages
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// client returns the HTTP client described by the configuration
+func (c *Config) client() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	client := NewHttpClient(c.SkipVerify)
+	client.Timeout = c.Timeout
+	tlsConfig := client.Transport.(*http.Transport).TLSClientConfig
+	tlsConfig.Certificates = c.Certificates
+	tlsConfig.RootCAs = c.RootCAs
+	return client
+}
+
+// base returns the base IRI of the documents parsed into the graph or
+// dataset named uri
+func (c *Config) base(uri string) string {
+	if len(c.Base) > 0 {
+		return c.Base
+	}
+	return uri
+}
+
+func (c *Config) log(level slog.Level, msg string, args ...any) {
+	if c.Logger != nil {
+		c.Logger.Log(context.Background(), level, msg, args...)
+	}
+}
+
+// LoadResult describes the document read by LoadURIWithResult, for callers
+// revalidating or caching what they loaded
+type LoadResult struct {
+	// URL is the URL the document was read from, after redirects
+	URL string
+	// ContentType is the content type the document was parsed as
+	ContentType string
+	// ETag and LastModified are the validators of the HTTP response, empty
+	// and zero when the server sent none or the document was not fetched
+	// over HTTP
+	ETag         string
+	LastModified time.Time
+	// Header holds the headers of the HTTP response, nil for documents read
+	// from object stores and resolvers
+	Header http.Header
+	// Cached tells that the server reported the document unchanged, and
+	// that the copy held by the HTTP cache was parsed
+	Cached bool
+	// ParseError is the error of a document that was fetched but could not
+	// be parsed, also returned by LoadURIWithResult, the statements parsed
+	// before it being loaded
+	ParseError error
+}
+
+// fetchRDF fetches the RDF document describing a graph or dataset (named by
+// what in error messages) and passes its body and content type to parse,
+// enforcing the size limit and the strictness of the configuration
+func (c *Config) fetchRDF(ctx context.Context, client *http.Client, uri string, what string, parse func(io.Reader, string) error) (*LoadResult, error) {
+	if resolver, ok := c.resolver(uri); ok {
+		return c.fetchResolved(ctx, resolver, uri, what, parse)
+	}
+	if store, bucket, key, ok := c.objectStore(uri); ok {
+		return c.fetchObject(ctx, store, bucket, key, uri, what, parse)
+	}
+	q, err := newRDFRequest(ctx, defrag(uri))
+	if err != nil {
+		return nil, err
+	}
+	var cached *CachedDocument
+	if c.HTTPCache != nil {
+		if doc, ok := c.HTTPCache.Get(defrag(uri)); ok {
+			cached = doc
+			setConditional(q, doc)
+		}
+	}
+	if err := c.prepare(q); err != nil {
+		return nil, err
+	}
+	r, err := c.do(ctx, client, q)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	c.log(slog.LevelDebug, "fetched RDF document", "uri", uri, "status", r.StatusCode, "contentType", r.Header.Get("Content-Type"))
+	if r.StatusCode == http.StatusNotModified && cached != nil {
+		res := newLoadResult(cached.URL, cached.Header)
+		res.Cached = true
+		return res, c.parseFetched(bytes.NewReader(cached.Body), res, parse)
+	}
+	if r.StatusCode != 200 {
+		return nil, fmt.Errorf("Could not fetch %s from %s - HTTP %d", what, uri, r.StatusCode)
+	}
+
+	res := newLoadResult(r.Request.URL.String(), r.Header)
+	if c.HTTPCache == nil || !cacheable(r.Header) {
+		return res, c.parseFetched(r.Body, res, parse)
+	}
+	body := new(bytes.Buffer)
+	if err := c.parseFetched(io.TeeReader(r.Body, body), res, parse); err != nil || res.ParseError != nil {
+		return res, err
+	}
+	c.HTTPCache.Put(defrag(uri), &CachedDocument{URL: res.URL, Header: r.Header.Clone(), Body: body.Bytes()})
+	return res, nil
+}
+
+// prepare sets the user agent of a remote request and calls the request
+// hooks of the configuration on it
+func (c *Config) prepare(q *http.Request) error {
+	if len(c.UserAgent) > 0 {
+		q.Header.Set("User-Agent", c.UserAgent)
+	}
+	for _, hook := range c.RequestHooks {
+		if err := hook(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newLoadResult describes a document fetched over HTTP from the headers of
+// its response
+func newLoadResult(url string, header http.Header) *LoadResult {
+	res := &LoadResult{
+		URL:         url,
+		ContentType: header.Get("Content-Type"),
+		ETag:        header.Get("ETag"),
+		Header:      header,
+	}
+	if modified, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		res.LastModified = modified
+	}
+	return res
+}
+
+// parseFetched parses a fetched document as the content type of its result,
+// enforcing the size limit and the strictness of the configuration
+func (c *Config) parseFetched(r io.Reader, res *LoadResult, parse func(io.Reader, string) error) error {
+	body := &limitedReader{r: r, n: c.MaxBytes}
+	err := parse(body, res.ContentType)
+	if body.exceeded {
+		return fmt.Errorf("the document exceeds the limit of %d bytes", c.MaxBytes)
+	}
+	if err != nil {
+		res.ParseError = err
+		c.log(slog.LevelWarn, "could not parse fetched document", "uri", res.URL, "error", err)
+	}
+	return err
+}
+
+// limitedReader fails once more than n bytes have been read, with err if
+// set, n <= 0 meaning no limit
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	read     int64
+	exceeded bool
+	err      error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return l.r.Read(p)
+	}
+	if l.read >= l.n {
+		// check whether the document ends exactly at the limit
+		var b [1]byte
+		if n, err := l.r.Read(b[:]); n == 0 {
+			return 0, err
+		}
+		l.exceeded = true
+		if l.err != nil {
+			return 0, l.err
+		}
+		return 0, fmt.Errorf("the document exceeds the limit of %d bytes", l.n)
+	}
+	if int64(len(p)) > l.n-l.read {
+		p = p[:l.n-l.read]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,296 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	c := DefaultConfig()
+	assert.Equal(t, rdfNamespace, c.Prefixes["rdf"])
+	assert.False(t, c.Strict)
+
+	// the returned configuration is a copy
+	c.Prefixes["ex"] = "http://example.org/"
+	_, ok := DefaultConfig().Prefixes["ex"]
+	assert.False(t, ok)
+
+	saved := DefaultConfig()
+	defer SetDefaultConfig(saved)
+	c.Strict = true
+	SetDefaultConfig(c)
+	g := NewGraph(testUri)
+	assert.True(t, g.Config().Strict)
+	assert.Equal(t, "http://example.org/", g.Config().Prefixes["ex"])
+	assert.True(t, NewDataset(testDatasetUri).Config().Strict)
+}
+
+func TestConfigOptions(t *testing.T) {
+	client := &http.Client{}
+	g := NewGraphWithOptions(testUri,
+		WithPrefixes(map[string]string{"foaf": "http://xmlns.com/foaf/0.1/"}),
+		WithStrict(true),
+		WithHTTPClient(client),
+		WithTimeout(time.Second),
+		WithUserAgent("test"),
+		WithMaxBytes(10),
+	)
+	c := g.Config()
+	assert.Equal(t, "http://xmlns.com/foaf/0.1/", c.Prefixes["foaf"])
+	assert.Equal(t, rdfNamespace, c.Prefixes["rdf"])
+	assert.True(t, c.Strict)
+	assert.Equal(t, time.Second, c.Timeout)
+	assert.Equal(t, "test", c.UserAgent)
+	assert.Equal(t, int64(10), c.MaxBytes)
+	assert.Equal(t, client, g.httpClient)
+	assert.NotNil(t, g.index)
+
+	d := NewDatasetWithOptions(testDatasetUri, WithSkipVerify(true), WithTimeout(time.Second))
+	assert.True(t, d.Config().SkipVerify)
+	assert.Equal(t, time.Second, d.httpClient.Timeout)
+	assert.True(t, d.GetGraph(nil).Config().SkipVerify)
+
+	// the skipVerify argument of the older constructors is kept
+	assert.True(t, NewGraph(testUri, true).Config().SkipVerify)
+	assert.True(t, NewUnindexedDataset(testDatasetUri, true).Config().SkipVerify)
+}
+
+func TestConfigConstructorOptions(t *testing.T) {
+	assert.Nil(t, NewGraphWithOptions(testUri, WithIndexing(false)).index)
+	assert.Nil(t, NewDatasetWithOptions(testDatasetUri, WithIndexing(false)).graphs)
+	assert.Nil(t, NewUnindexedGraph(testUri).index)
+	assert.NotNil(t, NewGraph(testUri).index)
+	assert.Nil(t, NewGraph(testUri).mu)
+	assert.NotNil(t, NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true)).mu)
+
+	// relative IRIs are resolved against the base instead of the graph URI
+	g := NewGraphWithOptions("urn:graph", WithBase("http://example.org/dir/"))
+	assert.NoError(t, g.Parse(strings.NewReader("<a> <b> <../c> ."), "text/turtle"))
+	assert.NotNil(t, g.One(NewResource("http://example.org/dir/a"), NewResource("http://example.org/dir/b"), NewResource("http://example.org/c")))
+	d := NewDatasetWithOptions("urn:dataset", WithBase("http://example.org/"))
+	assert.NoError(t, d.Parse(strings.NewReader("<g> { <a> <b> <c> . }"), "application/trig"))
+	assert.NotNil(t, d.One(NewResource("http://example.org/a"), nil, nil, NewResource("http://example.org/g")))
+	g = NewGraphWithOptions("urn:graph", WithBase("http://example.org/"))
+	assert.NoError(t, g.Parse(strings.NewReader("{ <a> <b> <c> . }"), "application/trig"))
+	assert.NotNil(t, g.One(NewResource("http://example.org/a"), nil, nil))
+}
+
+func TestConfigStrictParse(t *testing.T) {
+	nquads := "<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n" +
+		"<http://example.org/a> <http://example.org/b> <http://example.org/d> <http://example.org/g> .\n"
+
+	logs := new(bytes.Buffer)
+	g := NewGraphWithOptions(testUri, WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	assert.NoError(t, g.Parse(strings.NewReader(nquads), "application/n-quads"))
+	assert.Equal(t, 1, g.Len())
+	assert.Contains(t, logs.String(), "dropped statements of named graphs")
+	assert.Contains(t, logs.String(), "count=1")
+
+	g = NewGraphWithOptions(testUri, WithStrict(true))
+	assert.Error(t, g.Parse(strings.NewReader(nquads), "application/n-quads"))
+	g = NewGraphWithOptions(testUri, WithStrict(true))
+	assert.Error(t, g.Parse(strings.NewReader(simpleTrig), "application/trig"))
+}
+
+func TestConfigLoadURI(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/n-triples")
+		if req.URL.Path == "/broken" {
+			w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n<broken\n"))
+			return
+		}
+		if req.URL.Path == "/broken.ttl" {
+			w.Header().Set("Content-Type", "text/turtle")
+			w.Write([]byte("@prefix ex: <http://example.org/> .\nex:a ex:b ex:c ;\n"))
+			return
+		}
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+	}))
+	defer server.Close()
+
+	g := NewGraphWithOptions("", WithUserAgent("rdf2go-test"))
+	assert.NoError(t, g.LoadURI(server.URL+"/doc"))
+	assert.Equal(t, 1, g.Len())
+	assert.Equal(t, "rdf2go-test", userAgent)
+	assert.Equal(t, server.URL+"/doc", g.URI())
+
+	// the document is exactly 71 bytes long
+	assert.NoError(t, NewGraphWithOptions("", WithMaxBytes(71)).LoadURI(server.URL+"/doc"))
+	err := NewDatasetWithOptions("", WithMaxBytes(70)).LoadURI(server.URL + "/doc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the limit of 70 bytes")
+
+	// parse errors are returned, the statements parsed before them being
+	// loaded
+	logs := new(bytes.Buffer)
+	g = NewGraphWithOptions("", WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	assert.Error(t, g.LoadURI(server.URL+"/broken"))
+	assert.Equal(t, 1, g.Len())
+	assert.Contains(t, logs.String(), "could not parse fetched document")
+	assert.Error(t, NewGraph("").LoadURI(server.URL+"/broken.ttl"))
+	assert.Error(t, NewDataset("").LoadURIContext(context.Background(), server.URL+"/broken.ttl"))
+	assert.Error(t, NewGraphWithOptions("", WithStrict(true)).LoadURI(server.URL+"/broken"))
+	assert.Error(t, NewDatasetWithOptions("", WithStrict(true)).LoadURI(server.URL+"/broken"))
+}
+
+func TestLoadURIWithResult(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/moved":
+			http.Redirect(w, req, "/doc", http.StatusFound)
+			return
+		case "/broken":
+			w.Header().Set("Content-Type", "application/n-quads")
+			w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n<broken\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/n-triples; charset=utf-8")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+	}))
+	defer server.Close()
+
+	g := NewGraph("")
+	res, err := g.LoadURIWithResult(context.Background(), server.URL+"/moved#me")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, g.Len())
+	assert.Equal(t, server.URL+"/doc", res.URL)
+	assert.Equal(t, `"v1"`, res.ETag)
+	assert.True(t, modified.Equal(res.LastModified))
+	assert.Equal(t, "application/n-triples; charset=utf-8", res.ContentType)
+	assert.Equal(t, `"v1"`, res.Header.Get("ETag"))
+	assert.NoError(t, res.ParseError)
+
+	// the parse errors are returned, and reported
+	d := NewDataset("")
+	res, err = d.LoadURIWithResult(context.Background(), server.URL+"/broken")
+	assert.Error(t, err)
+	assert.Equal(t, err, res.ParseError)
+	assert.Empty(t, res.ETag)
+	assert.True(t, res.LastModified.IsZero())
+	assert.Equal(t, 1, d.Len())
+	_, err = NewDatasetWithOptions("", WithStrict(true)).LoadURIWithResult(context.Background(), server.URL+"/broken")
+	assert.Error(t, err)
+}
+
+func TestConfigRequestHooks(t *testing.T) {
+	var authorization, trace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		trace = req.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/n-triples")
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+	}))
+	defer server.Close()
+
+	var logged []string
+	g := NewGraphWithOptions("",
+		WithHTTPClient(server.Client()),
+		WithRequestHook(func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer token")
+			return nil
+		}),
+		WithRequestHook(func(req *http.Request) error {
+			req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			logged = append(logged, req.Method+" "+req.URL.String())
+			return nil
+		}),
+	)
+	assert.NoError(t, g.LoadURI(server.URL+"/doc#me"))
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", trace)
+	assert.Equal(t, []string{"GET " + server.URL + "/doc"}, logged)
+	assert.Len(t, g.Config().RequestHooks, 2)
+
+	// a failing hook aborts the load before the request is sent
+	authorization = ""
+	d := NewDatasetWithOptions("", WithRequestHook(func(req *http.Request) error {
+		return fmt.Errorf("no credentials for %s", req.URL.Host)
+	}))
+	err := d.LoadURI(server.URL + "/doc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no credentials")
+	assert.Empty(t, authorization)
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestConfigAuthentication(t *testing.T) {
+	var authorization string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/n-triples")
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	assert.NoError(t, NewGraphWithOptions("", WithBearerToken("secret")).LoadURI(server.URL))
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.NoError(t, NewDatasetWithOptions("", WithBasicAuth("alice", "pw")).LoadURI(server.URL))
+	assert.Equal(t, "Basic YWxpY2U6cHc=", authorization)
+
+	// the server requires a client certificate, and its own is trusted
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsServer.Certificate())
+	cert := tlsServer.TLS.Certificates[0]
+
+	g := NewGraphWithOptions("", WithRootCAs(roots), WithClientCertificate(cert))
+	assert.NoError(t, g.LoadURI(tlsServer.URL))
+	assert.Equal(t, 1, g.Len())
+	assert.Error(t, NewGraphWithOptions("", WithRootCAs(roots)).LoadURI(tlsServer.URL))
+	assert.Error(t, NewGraphWithOptions("", WithClientCertificate(cert)).LoadURI(tlsServer.URL))
+}
+
+func TestConfigPrefixesInHTML(t *testing.T) {
+	g := NewGraphWithOptions(testUri, WithPrefixes(map[string]string{"ex": "http://example.org/"}))
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.ExportHTML(buf, nil))
+	assert.Contains(t, buf.String(), `"label":"ex:b"`)
+}
+
+func TestConfigPrefixesInTurtle(t *testing.T) {
+	opts := []Option{WithPrefixes(map[string]string{"ex": "http://example.org/", "xsd": xsdNamespace})}
+	g := NewGraphWithOptions(testUri, opts...)
+	assert.Equal(t, map[string]string{"ex": "http://example.org/"}, g.Prefixes())
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer")))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Equal(t, "@prefix ex: <http://example.org/> .\n\nex:a\n  ex:b \"1\"^^<http://www.w3.org/2001/XMLSchema#integer> .", buf.String())
+
+	// Bind overrides the configured prefixes
+	assert.NoError(t, g.Bind("ex", ""))
+	assert.NoError(t, g.Bind("e", "http://example.org/"))
+	buf.Reset()
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Contains(t, buf.String(), "e:a\n  e:b")
+	assert.NotContains(t, buf.String(), "ex:")
+	_, ok := NewGraphWithOptions(testUri, opts...).Prefixes()["e"]
+	assert.False(t, ok)
+
+	d := NewDatasetWithOptions(testUri, opts...)
+	d.Add(NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"), NewResource("http://example.org/g")))
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	assert.Contains(t, buf.String(), "@prefix ex: <http://example.org/> .")
+	assert.Contains(t, buf.String(), "ex:a")
+}
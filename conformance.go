@@ -0,0 +1,344 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The vocabularies of the W3C test manifests
+const (
+	mfNamespace   Namespace = "http://www.w3.org/2001/sw/DataAccess/tests/test-manifest#"
+	rdftNamespace Namespace = "http://www.w3.org/ns/rdftest#"
+	jldNamespace  Namespace = "https://w3c.github.io/json-ld-api/tests/vocab#"
+)
+
+// The outcomes of conformance tests, named as in EARL
+const (
+	OutcomePassed   = "passed"
+	OutcomeFailed   = "failed"
+	OutcomeUntested = "untested"
+)
+
+// ConformanceResult is the result of a test of a W3C test suite
+type ConformanceResult struct {
+	// Test is the IRI of the test, and Name its name
+	Test string
+	Name string
+	// Type is the local name of the type of the test, such as
+	// TestTurtleEval
+	Type string
+	// Outcome is OutcomePassed, OutcomeFailed or OutcomeUntested for the
+	// types of tests that are not run
+	Outcome string
+	// Err tells why the test failed
+	Err error
+	// Date is the time the test was run
+	Date time.Time
+}
+
+// conformanceTest tells how to run a type of test: the syntax of its input,
+// and whether parsing it must fail, or be compared to the result of the test
+type conformanceTest struct {
+	mime     string
+	negative bool
+	eval     bool
+}
+
+// conformanceTests are the types of tests run by RunManifest
+var conformanceTests = map[string]conformanceTest{
+	"TestTurtlePositiveSyntax":   {mime: "text/turtle"},
+	"TestTurtleNegativeSyntax":   {mime: "text/turtle", negative: true},
+	"TestTurtleEval":             {mime: "text/turtle", eval: true},
+	"TestTurtleNegativeEval":     {mime: "text/turtle", negative: true},
+	"TestTrigPositiveSyntax":     {mime: "application/trig"},
+	"TestTrigNegativeSyntax":     {mime: "application/trig", negative: true},
+	"TestTrigEval":               {mime: "application/trig", eval: true},
+	"TestTrigNegativeEval":       {mime: "application/trig", negative: true},
+	"TestNTriplesPositiveSyntax": {mime: "application/n-triples"},
+	"TestNTriplesNegativeSyntax": {mime: "application/n-triples", negative: true},
+	"TestNQuadsPositiveSyntax":   {mime: "application/n-quads"},
+	"TestNQuadsNegativeSyntax":   {mime: "application/n-quads", negative: true},
+}
+
+// RunManifest runs the tests of a W3C test manifest, such as those of the
+// Turtle, TriG, N-Triples and N-Quads suites of
+// https://w3c.github.io/rdf-tests/ or the toRdf manifest of the JSON-LD
+// suite, along with the manifests it includes. Documents are fetched as by
+// LoadURI with the options, so that a vendored copy of the suites can be
+// used by registering a Resolver for https. Evaluation tests also check
+// that the parsed dataset survives a round trip through the serializer of
+// the format. Types of tests that cannot be run are reported as untested.
+func RunManifest(ctx context.Context, manifest string, opts ...Option) ([]*ConformanceResult, error) {
+	r := &conformanceRunner{ctx: ctx, opts: opts, seen: make(map[string]bool)}
+	if err := r.manifest(manifest); err != nil {
+		return nil, err
+	}
+	return r.results, nil
+}
+
+// conformanceRunner runs the tests of manifests
+type conformanceRunner struct {
+	ctx     context.Context
+	opts    []Option
+	seen    map[string]bool
+	results []*ConformanceResult
+}
+
+// fetch returns the content of a document
+func (r *conformanceRunner) fetch(uri string) ([]byte, error) {
+	d := NewDatasetWithOptions(uri, r.opts...)
+	var body []byte
+	_, err := d.config.fetchRDF(r.ctx, d.httpClient, uri, "test document", func(reader io.Reader, _ string) error {
+		var err error
+		body, err = io.ReadAll(reader)
+		return err
+	})
+	return body, err
+}
+
+// manifest runs the tests of a manifest and of the manifests it includes
+func (r *conformanceRunner) manifest(uri string) error {
+	if r.seen[uri] {
+		return nil
+	}
+	r.seen[uri] = true
+	body, err := r.fetch(uri)
+	if err != nil {
+		return err
+	}
+	g := NewGraphWithOptions(uri, append(r.opts, WithStrict(true))...)
+	if objectMime("", uri) == "application/ld+json" {
+		err = readJSONLDManifest(g, uri, body)
+	} else {
+		err = g.Parse(bytes.NewReader(body), objectMime("", uri))
+	}
+	if err != nil {
+		return fmt.Errorf("invalid manifest %s: %s", uri, err)
+	}
+	lists := &unmarshaler{graph: g}
+	for _, include := range g.All(nil, mfNamespace.Get("include"), nil) {
+		manifests, err := lists.listElements(include.Object)
+		if err != nil {
+			return err
+		}
+		for _, m := range manifests {
+			if err := r.manifest(m.RawValue()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, entries := range g.All(nil, mfNamespace.Get("entries"), nil) {
+		tests, err := lists.listElements(entries.Object)
+		if err != nil {
+			return err
+		}
+		for _, test := range tests {
+			r.results = append(r.results, r.run(g, test))
+		}
+	}
+	return nil
+}
+
+// jsonldManifest is the layout of the manifests of the JSON-LD test suite
+type jsonldManifest struct {
+	Sequence []struct {
+		ID     string          `json:"@id"`
+		Type   json.RawMessage `json:"@type"`
+		Name   string          `json:"name"`
+		Input  string          `json:"input"`
+		Expect string          `json:"expect"`
+	} `json:"sequence"`
+}
+
+// readJSONLDManifest adds to g the statements of a manifest of the JSON-LD
+// test suite, read as JSON as the context of the suite is not needed to
+// find its tests
+func readJSONLDManifest(g *Graph, uri string, body []byte) error {
+	var manifest jsonldManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return err
+	}
+	tests := make([]Term, len(manifest.Sequence))
+	for i, entry := range manifest.Sequence {
+		test := NewResource(resolveIRI(uri, entry.ID))
+		tests[i] = test
+		var types []string
+		if err := json.Unmarshal(entry.Type, &types); err != nil {
+			types = []string{strings.Trim(string(entry.Type), `"`)}
+		}
+		for _, t := range types {
+			if local, ok := strings.CutPrefix(t, "jld:"); ok {
+				t = jldNamespace.IRI() + local
+			}
+			g.AddTriple(test, RDF.Get("type"), NewResource(t))
+		}
+		if len(entry.Name) > 0 {
+			g.AddTriple(test, mfNamespace.Get("name"), NewLiteral(entry.Name))
+		}
+		if len(entry.Input) > 0 {
+			g.AddTriple(test, mfNamespace.Get("action"), NewResource(resolveIRI(uri, entry.Input)))
+		}
+		if len(entry.Expect) > 0 {
+			g.AddTriple(test, mfNamespace.Get("result"), NewResource(resolveIRI(uri, entry.Expect)))
+		}
+	}
+	list := RDF.Get("nil")
+	for i := len(tests) - 1; i >= 0; i-- {
+		cell := NewAnonNode()
+		g.AddTriple(cell, RDF.Get("first"), tests[i])
+		g.AddTriple(cell, RDF.Get("rest"), list)
+		list = cell
+	}
+	g.AddTriple(NewResource(uri), mfNamespace.Get("entries"), list)
+	return nil
+}
+
+// run runs a test of a manifest
+func (r *conformanceRunner) run(g *Graph, test Term) *ConformanceResult {
+	result := &ConformanceResult{Test: test.RawValue(), Outcome: OutcomeUntested, Date: time.Now().UTC().Truncate(time.Second)}
+	if name := g.One(test, mfNamespace.Get("name"), nil); name != nil {
+		result.Name = name.Object.RawValue()
+	}
+	var kind conformanceTest
+	var known bool
+	for _, t := range g.All(test, RDF.Get("type"), nil) {
+		typ := t.Object.RawValue()
+		if local, ok := strings.CutPrefix(typ, rdftNamespace.IRI()); ok {
+			result.Type = local
+			kind, known = conformanceTests[local]
+		} else if local, ok := strings.CutPrefix(typ, jldNamespace.IRI()); ok {
+			if local == "ToRDFTest" {
+				result.Type = local
+				kind.mime, known = "application/ld+json", true
+			}
+			kind.negative = kind.negative || local == "NegativeEvaluationTest"
+			kind.eval = kind.eval || local == "PositiveEvaluationTest"
+		}
+	}
+	action := g.One(test, mfNamespace.Get("action"), nil)
+	if !known || action == nil {
+		return result
+	}
+	err := r.check(action.Object.RawValue(), kind, func() string {
+		if expected := g.One(test, mfNamespace.Get("result"), nil); expected != nil {
+			return expected.Object.RawValue()
+		}
+		return ""
+	}())
+	result.Outcome, result.Err = OutcomePassed, err
+	if err != nil {
+		result.Outcome = OutcomeFailed
+	}
+	return result
+}
+
+// errUnexpectedSuccess is the error of negative tests that parse
+var errUnexpectedSuccess = errors.New("the document was parsed, but should have been rejected")
+
+// check parses the input of a test, comparing it to the expected result of
+// evaluation tests
+func (r *conformanceRunner) check(action string, kind conformanceTest, expected string) error {
+	body, err := r.fetch(action)
+	if err != nil {
+		return err
+	}
+	d := r.parse(action)
+	err = d.Parse(bytes.NewReader(body), kind.mime)
+	switch {
+	case kind.negative && err == nil:
+		return errUnexpectedSuccess
+	case kind.negative:
+		return nil
+	case err != nil:
+		return err
+	case !kind.eval:
+		return nil
+	}
+
+	body, err = r.fetch(expected)
+	if err != nil {
+		return err
+	}
+	want := r.parse(expected)
+	if err := want.Parse(bytes.NewReader(body), "application/n-quads"); err != nil {
+		return fmt.Errorf("invalid result %s: %s", expected, err)
+	}
+	if err := sameDataset(d, want); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	switch kind.mime {
+	case "application/trig", "application/n-quads", "application/ld+json":
+		err = d.Serialize(&out, kind.mime)
+	default:
+		err = d.SerializeGraph(&out, nil, kind.mime)
+	}
+	if err != nil {
+		return fmt.Errorf("serializing: %s", err)
+	}
+	roundtrip := r.parse(action)
+	if err := roundtrip.Parse(&out, kind.mime); err != nil {
+		return fmt.Errorf("parsing the serialized dataset: %s", err)
+	}
+	if err := sameDataset(roundtrip, want); err != nil {
+		return fmt.Errorf("round trip: %s", err)
+	}
+	return nil
+}
+
+// parse returns an empty dataset parsing the documents of a test
+func (r *conformanceRunner) parse(uri string) *Dataset {
+	return NewDatasetWithOptions(uri, append(r.opts, WithStrict(true), WithBase(uri))...)
+}
+
+// sameDataset fails unless two datasets are isomorphic
+func sameDataset(got *Dataset, want *Dataset) error {
+	a, err := got.Hash()
+	if err != nil {
+		return err
+	}
+	b, err := want.Hash()
+	if err != nil {
+		return err
+	}
+	if a != b {
+		return fmt.Errorf("got %d statements not isomorphic to the %d expected ones", got.Len(), want.Len())
+	}
+	return nil
+}
+
+// EARLReport describes the results of conformance tests in EARL, the
+// format of the W3C implementation reports: an earl:Assertion of the
+// outcome of each test for subject, the IRI of the software tested
+func EARLReport(subject string, results []*ConformanceResult) *Graph {
+	g := NewGraph(subject)
+	software := NewResource(subject)
+	g.AddTriple(software, RDF.Get("type"), EARL.Get("TestSubject"))
+	g.AddTriple(software, RDF.Get("type"), EARL.Get("Software"))
+	for _, res := range results {
+		assertion := NewAnonNode()
+		g.AddTriple(assertion, RDF.Get("type"), EARL.Get("Assertion"))
+		g.AddTriple(assertion, EARL.Get("assertedBy"), software)
+		g.AddTriple(assertion, EARL.Get("subject"), software)
+		g.AddTriple(assertion, EARL.Get("test"), NewResource(res.Test))
+		g.AddTriple(assertion, EARL.Get("mode"), EARL.Get("automatic"))
+		result := NewAnonNode()
+		g.AddTriple(assertion, EARL.Get("result"), result)
+		g.AddTriple(result, RDF.Get("type"), EARL.Get("TestResult"))
+		g.AddTriple(result, EARL.Get("outcome"), EARL.Get(res.Outcome))
+		if !res.Date.IsZero() {
+			g.AddTriple(result, DCTerms.Get("date"), NewLiteralWithDatatype(res.Date.Format(time.RFC3339), XSD.Get("dateTime")))
+		}
+		if res.Err != nil {
+			g.AddTriple(result, DCTerms.Get("description"), NewLiteral(res.Err.Error()))
+		}
+	}
+	return g
+}
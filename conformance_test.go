@@ -0,0 +1,119 @@
+package rdf2go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSuite serves the documents of a test suite from memory
+func testSuite(docs map[string]string) Option {
+	return WithResolver("https", ResolverFunc(func(_ context.Context, iri string) (io.ReadCloser, string, error) {
+		doc, ok := docs[strings.TrimPrefix(iri, "https://example.org/tests/")]
+		if !ok {
+			return nil, "", errors.New("not found")
+		}
+		return io.NopCloser(strings.NewReader(doc)), "text/plain", nil
+	}))
+}
+
+func TestRunManifest(t *testing.T) {
+	suite := testSuite(map[string]string{
+		"manifest.ttl": `@prefix mf: <http://www.w3.org/2001/sw/DataAccess/tests/test-manifest#> .
+<> mf:include (<turtle/manifest.ttl> <turtle/manifest.ttl>) .`,
+		"turtle/manifest.ttl": `@prefix mf: <http://www.w3.org/2001/sw/DataAccess/tests/test-manifest#> .
+@prefix rdft: <http://www.w3.org/ns/rdftest#> .
+<> mf:entries (<#syntax> <#bad> <#eval> <#wrong> <#xml>) .
+<#syntax> a rdft:TestTurtlePositiveSyntax ; mf:name "syntax" ; mf:action <syntax.ttl> .
+<#bad> a rdft:TestTurtleNegativeSyntax ; mf:name "bad" ; mf:action <bad.ttl> .
+<#eval> a rdft:TestTurtleEval ; mf:name "eval" ; mf:action <eval.ttl> ; mf:result <eval.nt> .
+<#wrong> a rdft:TestTurtleEval ; mf:name "wrong" ; mf:action <eval.ttl> ; mf:result <wrong.nt> .
+<#xml> a rdft:TestXMLEval ; mf:name "xml" ; mf:action <test.rdf> .`,
+		"turtle/syntax.ttl": `<a> <b> "c" .`,
+		"turtle/bad.ttl":    `<a> <b> .`,
+		"turtle/eval.ttl":   `@prefix : <http://example.org/> . :a :b [ :c ("d" 1) ] .`,
+		"turtle/eval.nt": `<http://example.org/a> <http://example.org/b> _:x .
+_:x <http://example.org/c> _:l1 .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "d" .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:l2 .
+_:l2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "1"^^<http://www.w3.org/2001/XMLSchema#integer> .
+_:l2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .`,
+		"turtle/wrong.nt": `<http://example.org/a> <http://example.org/b> "c" .`,
+	})
+	results, err := RunManifest(context.Background(), "https://example.org/tests/manifest.ttl", suite)
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	outcomes := make(map[string]string)
+	for _, res := range results {
+		outcomes[res.Name] = res.Outcome
+	}
+	assert.Equal(t, map[string]string{"syntax": OutcomePassed, "bad": OutcomePassed, "eval": OutcomePassed, "wrong": OutcomeFailed, "xml": OutcomeUntested}, outcomes)
+	assert.Equal(t, "https://example.org/tests/turtle/manifest.ttl#eval", results[2].Test)
+	assert.Equal(t, "TestTurtleEval", results[2].Type)
+	assert.Error(t, results[3].Err)
+
+	_, err = RunManifest(context.Background(), "https://example.org/tests/missing.ttl", suite)
+	assert.Error(t, err)
+}
+
+func TestRunManifestNQuads(t *testing.T) {
+	suite := testSuite(map[string]string{
+		"manifest.ttl": `@prefix mf: <http://www.w3.org/2001/sw/DataAccess/tests/test-manifest#> .
+@prefix rdft: <http://www.w3.org/ns/rdftest#> .
+<> mf:entries (<#ok> <#bad>) .
+<#ok> a rdft:TestNQuadsPositiveSyntax ; mf:action <ok.nq> .
+<#bad> a rdft:TestNQuadsNegativeSyntax ; mf:action <ok.nq> .`,
+		"ok.nq": "<http://a.example/s> <http://a.example/p> <http://a.example/o> <http://a.example/g> .\n",
+	})
+	results, err := RunManifest(context.Background(), "https://example.org/tests/manifest.ttl", suite)
+	assert.NoError(t, err)
+	assert.Equal(t, OutcomePassed, results[0].Outcome)
+	assert.Equal(t, OutcomeFailed, results[1].Outcome)
+	assert.ErrorIs(t, results[1].Err, errUnexpectedSuccess)
+}
+
+func TestRunManifestJSONLD(t *testing.T) {
+	suite := testSuite(map[string]string{
+		"manifest.jsonld": `{
+  "@context": ["context.jsonld", {"@base": "manifest"}],
+  "@id": "",
+  "@type": "mf:Manifest",
+  "sequence": [{
+    "@id": "#t0001", "@type": ["jld:PositiveEvaluationTest", "jld:ToRDFTest"],
+    "name": "plain literal", "input": "toRdf/0001-in.jsonld", "expect": "toRdf/0001-out.nq"
+  }, {
+    "@id": "#te001", "@type": ["jld:NegativeEvaluationTest", "jld:ToRDFTest"],
+    "name": "invalid", "input": "toRdf/e001-in.jsonld"
+  }]
+}`,
+		"toRdf/0001-in.jsonld": `{"@id": "http://example.com/id1", "http://example.com/name": "a"}`,
+		"toRdf/0001-out.nq":    "<http://example.com/id1> <http://example.com/name> \"a\" .\n",
+		"toRdf/e001-in.jsonld": `{"@id": `,
+	})
+	results, err := RunManifest(context.Background(), "https://example.org/tests/manifest.jsonld", suite)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, res := range results {
+		assert.Equal(t, "ToRDFTest", res.Type)
+		assert.Equal(t, OutcomePassed, res.Outcome, res.Name)
+	}
+}
+
+func TestEARLReport(t *testing.T) {
+	results := []*ConformanceResult{
+		{Test: "https://example.org/tests/manifest.ttl#a", Outcome: OutcomePassed},
+		{Test: "https://example.org/tests/manifest.ttl#b", Outcome: OutcomeFailed, Err: errors.New("boom")},
+	}
+	g := EARLReport("https://github.com/deiu/rdf2go", results)
+	software := NewResource("https://github.com/deiu/rdf2go")
+	assert.NotNil(t, g.One(software, RDF.Get("type"), EARL.Get("TestSubject")))
+	assert.Len(t, g.All(nil, EARL.Get("subject"), software), 2)
+	assertion := g.One(nil, EARL.Get("test"), NewResource("https://example.org/tests/manifest.ttl#b")).Subject
+	result := g.One(assertion, EARL.Get("result"), nil).Object
+	assert.NotNil(t, g.One(result, EARL.Get("outcome"), EARL.Get("failed")))
+	assert.NotNil(t, g.One(result, DCTerms.Get("description"), NewLiteral("boom")))
+}
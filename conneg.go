@@ -0,0 +1,94 @@
+package rdf2go
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// datasetMimes lists the formats in which ServeDataset serves datasets, in
+// order of preference. Turtle and N-Triples are left out, as they cannot
+// hold named graphs.
+var datasetMimes = []string{
+	"application/trig",
+	"application/n-quads",
+	"application/ld+json",
+}
+
+// ServeGraph replies to a request with g, serialized in the format preferred
+// by its Accept header among Turtle, N-Triples, JSON-LD, TriG and N-Quads,
+// Turtle being served to requests without an Accept header. Requests that
+// accept none of these formats get a 406 Not Acceptable response.
+func ServeGraph(w http.ResponseWriter, req *http.Request, g *Graph) {
+	if mediaType, ok := negotiate(w, req, graphStoreMimes); ok {
+		writeRDF(w, req, mediaType, g.Serialize)
+	}
+}
+
+// ServeDataset replies to a request with d, serialized in the format
+// preferred by its Accept header among TriG, N-Quads and JSON-LD, TriG being
+// served to requests without an Accept header. Requests that accept none of
+// these formats get a 406 Not Acceptable response.
+func ServeDataset(w http.ResponseWriter, req *http.Request, d *Dataset) {
+	if mediaType, ok := negotiate(w, req, datasetMimes); ok {
+		writeRDF(w, req, mediaType, d.Serialize)
+	}
+}
+
+// NewGraphHandler returns an http.Handler serving g to GET and HEAD
+// requests, see ServeGraph
+func NewGraphHandler(g *Graph) http.Handler {
+	return readOnlyHandler(func(w http.ResponseWriter, req *http.Request) {
+		ServeGraph(w, req, g)
+	})
+}
+
+// NewDatasetHandler returns an http.Handler serving d to GET and HEAD
+// requests, see ServeDataset
+func NewDatasetHandler(d *Dataset) http.Handler {
+	return readOnlyHandler(func(w http.ResponseWriter, req *http.Request) {
+		ServeDataset(w, req, d)
+	})
+}
+
+// readOnlyHandler returns a handler passing GET and HEAD requests to serve,
+// and rejecting the others
+func readOnlyHandler(serve http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		serve(w, req)
+	})
+}
+
+// negotiate returns the offer preferred by the Accept header of a request,
+// replying with 406 Not Acceptable when none is acceptable. The response
+// varies with the Accept header in either case.
+func negotiate(w http.ResponseWriter, req *http.Request, offers []string) (string, bool) {
+	w.Header().Add("Vary", "Accept")
+	mediaType := negotiateMime(req.Header.Get("Accept"), offers)
+	if len(mediaType) == 0 {
+		http.Error(w, "none of the requested formats is supported", http.StatusNotAcceptable)
+		return "", false
+	}
+	return mediaType, true
+}
+
+// writeRDF replies to a GET or HEAD request with the document written by
+// serialize in mediaType. The document is buffered so that serialization
+// errors get a 500 response.
+func writeRDF(w http.ResponseWriter, req *http.Request, mediaType string, serialize func(io.Writer, string) error) {
+	buf := new(bytes.Buffer)
+	if err := serialize(buf, mediaType); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(http.StatusOK)
+	if req.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
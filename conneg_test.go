@@ -0,0 +1,83 @@
+package rdf2go
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func connegRequest(h http.Handler, method string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGraphHandler(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	h := NewGraphHandler(g)
+
+	rec := connegRequest(h, "GET", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/turtle", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	assert.Contains(t, rec.Body.String(), `"c"`)
+
+	rec = connegRequest(h, "GET", "text/turtle;q=0.5, application/ld+json;q=0.8, */*;q=0.1")
+	assert.Equal(t, "application/ld+json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"@value":"c"`)
+
+	rec = connegRequest(h, "GET", "application/*, text/turtle;q=0.9")
+	assert.Equal(t, "application/n-triples", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "<http://example.org/a> <http://example.org/b> \"c\" .\n", rec.Body.String())
+
+	rec = connegRequest(h, "HEAD", "application/n-quads")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/n-quads", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+
+	rec = connegRequest(h, "GET", "image/png, text/turtle;q=0")
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+
+	rec = connegRequest(h, "PUT", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestDatasetHandler(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"), NewResource("http://example.org/g"))
+	h := NewDatasetHandler(d)
+
+	rec := connegRequest(h, "GET", "")
+	assert.Equal(t, "application/trig", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<http://example.org/g>")
+
+	rec = connegRequest(h, "GET", "application/n-quads, application/trig;q=0.9")
+	assert.Equal(t, "application/n-quads", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "<http://example.org/a> <http://example.org/b> \"c\" <http://example.org/g> .\n", rec.Body.String())
+
+	// Turtle cannot hold the named graphs of a dataset
+	rec = connegRequest(h, "GET", "text/turtle")
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+
+	// ServeDataset can be called from other handlers
+	mux := http.NewServeMux()
+	mux.HandleFunc("/data", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		ServeDataset(w, req, d)
+	})
+	req := httptest.NewRequest("GET", "/data", nil)
+	req.Header.Set("Accept", "application/ld+json")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, "application/ld+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "max-age=60", rec.Header().Get("Cache-Control"))
+}
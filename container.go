@@ -0,0 +1,95 @@
+package rdf2go
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The classes of RDF containers, whose members are the objects of their
+// rdf:_1, rdf:_2... membership properties
+var (
+	RDFSeq = NewResource(rdfNamespace + "Seq")
+	RDFBag = NewResource(rdfNamespace + "Bag")
+	RDFAlt = NewResource(rdfNamespace + "Alt")
+)
+
+// MembershipProperty returns the container membership property rdf:_n
+func MembershipProperty(n int) Term {
+	return NewResource(rdfNamespace + "_" + strconv.Itoa(n))
+}
+
+// MembershipIndex returns the index n of a container membership property
+// rdf:_n, telling whether the term is one
+func MembershipIndex(p Term) (int, bool) {
+	r, ok := p.(*Resource)
+	if !ok {
+		return 0, false
+	}
+	digits, ok := strings.CutPrefix(r.URI, rdfNamespace+"_")
+	if !ok || len(digits) == 0 || digits[0] == '0' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil && n > 0
+}
+
+// NewContainer adds to the graph a container of a kind, RDFSeq, RDFBag or
+// RDFAlt, holding members, and returns its new blank node
+func (g *Graph) NewContainer(kind Term, members ...Term) Term {
+	container := NewAnonNode()
+	g.AddTriple(container, rdfType, kind)
+	g.AddToContainer(container, members...)
+	return container
+}
+
+// AddToContainer appends members to a container, numbering them after its
+// last member
+func (g *Graph) AddToContainer(container Term, members ...Term) {
+	last := 0
+	g.match(container, nil, nil, func(triple *Triple) bool {
+		if n, ok := MembershipIndex(triple.Predicate); ok && n > last {
+			last = n
+		}
+		return true
+	})
+	for i, member := range members {
+		g.AddTriple(container, MembershipProperty(last+i+1), member)
+	}
+}
+
+// ContainerMembers returns the members of a container, ordered by their
+// membership properties, which may leave gaps. For an rdf:Alt, the first
+// member is the default choice.
+func (g *Graph) ContainerMembers(container Term) []Term {
+	type member struct {
+		n    int
+		term Term
+	}
+	var members []member
+	g.match(container, nil, nil, func(triple *Triple) bool {
+		if n, ok := MembershipIndex(triple.Predicate); ok {
+			members = append(members, member{n, triple.Object})
+		}
+		return true
+	})
+	slices.SortStableFunc(members, func(a member, b member) int {
+		return a.n - b.n
+	})
+	terms := make([]Term, len(members))
+	for i, m := range members {
+		terms[i] = m.term
+	}
+	return terms
+}
+
+// ContainerKind returns the kind of a container, RDFSeq, RDFBag or RDFAlt,
+// or nil when the term is not typed as a container
+func (g *Graph) ContainerKind(container Term) Term {
+	for _, kind := range []Term{RDFSeq, RDFBag, RDFAlt} {
+		if g.One(container, rdfType, kind) != nil {
+			return kind
+		}
+	}
+	return nil
+}
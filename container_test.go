@@ -0,0 +1,38 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainers(t *testing.T) {
+	assert.Equal(t, NewResource(rdfNamespace+"_3"), MembershipProperty(3))
+	n, ok := MembershipIndex(NewResource(rdfNamespace + "_12"))
+	assert.True(t, ok)
+	assert.Equal(t, 12, n)
+	for _, p := range []Term{NewResource(rdfNamespace + "_0"), NewResource(rdfNamespace + "_01"), NewResource(rdfNamespace + "_"), rdfType, NewLiteral(rdfNamespace + "_1")} {
+		_, ok := MembershipIndex(p)
+		assert.False(t, ok, p.String())
+	}
+
+	g := NewGraph(testUri)
+	seq := g.NewContainer(RDFSeq, NewLiteral("a"), NewLiteral("b"))
+	g.AddToContainer(seq, NewLiteral("c"))
+	assert.Equal(t, []Term{NewLiteral("a"), NewLiteral("b"), NewLiteral("c")}, g.ContainerMembers(seq))
+	assert.NotNil(t, g.One(seq, MembershipProperty(3), NewLiteral("c")))
+	assert.Equal(t, RDFSeq, g.ContainerKind(seq))
+	assert.Nil(t, g.ContainerKind(NewLiteral("a")))
+
+	// members are read in order despite gaps, as written by RSS 1.0 feeds
+	assert.NoError(t, g.Parse(strings.NewReader(`
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+<http://example.org/items> a rdf:Bag ; rdf:_10 "ten" ; rdf:_2 "two" ; rdf:_5 "five" .
+`), "text/turtle"))
+	bag := NewResource("http://example.org/items")
+	assert.Equal(t, RDFBag, g.ContainerKind(bag))
+	assert.Equal(t, []Term{NewLiteral("two"), NewLiteral("five"), NewLiteral("ten")}, g.ContainerMembers(bag))
+	g.AddToContainer(bag, NewLiteral("eleven"))
+	assert.NotNil(t, g.One(bag, MembershipProperty(11), NewLiteral("eleven")))
+}
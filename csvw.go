@@ -0,0 +1,549 @@
+package rdf2go
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSVWMode is the mode of the conversion of tabular data to RDF
+type CSVWMode int
+
+const (
+	// CSVWStandard describes the table group, the table and its rows along
+	// with the statements of the cells
+	CSVWStandard CSVWMode = iota
+	// CSVWMinimal only generates the statements of the cells
+	CSVWMinimal
+)
+
+// csvwPrefixes are the prefixes of the CSVW initial context that the
+// propertyUrl and valueUrl of columns may use
+var csvwPrefixes = map[string]string{
+	"csvw":    CSVW.IRI(),
+	"dc":      DCTerms.IRI(),
+	"dcat":    DCAT.IRI(),
+	"dcterms": DCTerms.IRI(),
+	"foaf":    FOAF.IRI(),
+	"owl":     owlNamespace,
+	"rdf":     rdfNamespace,
+	"rdfs":    rdfsNamespace,
+	"schema":  "http://schema.org/",
+	"skos":    SKOS.IRI(),
+	"xsd":     xsdNamespace,
+}
+
+// csvwDatatypes maps the names of the built-in datatypes of CSVW that are
+// not XML Schema datatypes to their IRI
+var csvwDatatypes = map[string]string{
+	"number":   xsdNamespace + "double",
+	"binary":   xsdNamespace + "base64Binary",
+	"datetime": xsdNamespace + "dateTime",
+	"any":      xsdNamespace + "anyAtomicType",
+	"xml":      rdfNamespace + "XMLLiteral",
+	"html":     rdfNamespace + "HTML",
+	"json":     CSVW.IRI() + "JSON",
+}
+
+// csvwInherited holds the inherited properties of tables, schemas and
+// columns
+type csvwInherited struct {
+	AboutURL    *string         `json:"aboutUrl"`
+	PropertyURL *string         `json:"propertyUrl"`
+	ValueURL    *string         `json:"valueUrl"`
+	Datatype    json.RawMessage `json:"datatype"`
+	Default     *string         `json:"default"`
+	Lang        *string         `json:"lang"`
+	Null        json.RawMessage `json:"null"`
+	Separator   *string         `json:"separator"`
+	Ordered     *bool           `json:"ordered"`
+}
+
+// inherit returns the properties, inheriting those of parent that are not
+// set
+func (c csvwInherited) inherit(parent csvwInherited) csvwInherited {
+	if c.AboutURL == nil {
+		c.AboutURL = parent.AboutURL
+	}
+	if c.PropertyURL == nil {
+		c.PropertyURL = parent.PropertyURL
+	}
+	if c.ValueURL == nil {
+		c.ValueURL = parent.ValueURL
+	}
+	if c.Datatype == nil {
+		c.Datatype = parent.Datatype
+	}
+	if c.Default == nil {
+		c.Default = parent.Default
+	}
+	if c.Lang == nil {
+		c.Lang = parent.Lang
+	}
+	if c.Null == nil {
+		c.Null = parent.Null
+	}
+	if c.Separator == nil {
+		c.Separator = parent.Separator
+	}
+	if c.Ordered == nil {
+		c.Ordered = parent.Ordered
+	}
+	return c
+}
+
+// csvwTable is the layout of the metadata of a table
+type csvwTable struct {
+	URL     string `json:"url"`
+	Dialect struct {
+		Delimiter      string `json:"delimiter"`
+		Header         *bool  `json:"header"`
+		HeaderRowCount *int   `json:"headerRowCount"`
+		SkipRows       int    `json:"skipRows"`
+		CommentPrefix  string `json:"commentPrefix"`
+		Trim           any    `json:"trim"`
+	} `json:"dialect"`
+	TableSchema struct {
+		Columns []csvwColumn `json:"columns"`
+		csvwInherited
+	} `json:"tableSchema"`
+	SuppressOutput bool `json:"suppressOutput"`
+	csvwInherited
+}
+
+// csvwMetadata is the layout of a metadata document, describing a table or
+// a table group of tables
+type csvwMetadata struct {
+	Tables []csvwTable `json:"tables"`
+	csvwTable
+}
+
+// csvwColumn is the layout of the description of a column
+type csvwColumn struct {
+	Name           string          `json:"name"`
+	Titles         json.RawMessage `json:"titles"`
+	Virtual        bool            `json:"virtual"`
+	SuppressOutput bool            `json:"suppressOutput"`
+	csvwInherited
+}
+
+// title returns the first title of a column, "" if none
+func (c *csvwColumn) title() string {
+	var title string
+	if json.Unmarshal(c.Titles, &title) == nil {
+		return title
+	}
+	var titles []string
+	if json.Unmarshal(c.Titles, &titles) == nil && len(titles) > 0 {
+		return titles[0]
+	}
+	var natural map[string]json.RawMessage
+	if json.Unmarshal(c.Titles, &natural) == nil {
+		for _, lang := range sortedKeys(natural) {
+			if json.Unmarshal(natural[lang], &title) == nil {
+				return title
+			}
+			if json.Unmarshal(natural[lang], &titles) == nil && len(titles) > 0 {
+				return titles[0]
+			}
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// ImportCSVW converts tabular data to RDF following the csv2rdf algorithm
+// of CSV on the Web (https://www.w3.org/TR/csv2rdf/), adding to the graph
+// the statements generated from a CSV file and the JSON metadata document
+// describing it, and returns the number of statements added. The metadata
+// describes a table, or a table group whose first table is converted. The
+// URL of the table, which URI templates and the IRIs of the columns are
+// resolved against, is resolved against the base IRI of the graph.
+//
+// The cells are trimmed, split by the separator of their column and typed
+// after its datatype, a boolean format such as "Y|N" being applied, while
+// the other formats of numbers and dates are not parsed. The constraints
+// of the schema, such as required columns and primary keys, are not
+// validated.
+func (g *Graph) ImportCSVW(data io.Reader, metadata io.Reader, mode CSVWMode) (int, error) {
+	var doc csvwMetadata
+	if err := json.NewDecoder(metadata).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("invalid CSVW metadata: %s", err)
+	}
+	table := doc.csvwTable
+	if len(doc.Tables) > 0 {
+		table = doc.Tables[0]
+	}
+	c := &csvwConverter{g: g, table: &table, url: resolveIRI(g.config.base(g.uri), table.URL), mode: mode}
+	before := g.Len()
+	err := c.convert(data)
+	return g.Len() - before, err
+}
+
+// csvwConverter converts a table to RDF
+type csvwConverter struct {
+	g     *Graph
+	table *csvwTable
+	url   string
+	mode  CSVWMode
+	// columns holds the columns of the table, and props their inherited
+	// properties
+	columns []csvwColumn
+	props   []csvwInherited
+}
+
+func (c *csvwConverter) convert(data io.Reader) error {
+	dialect := c.table.Dialect
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if len(dialect.Delimiter) > 0 {
+		reader.Comma = []rune(dialect.Delimiter)[0]
+	}
+	if len(dialect.CommentPrefix) > 0 {
+		reader.Comment = []rune(dialect.CommentPrefix)[0]
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+	if dialect.SkipRows > 0 {
+		records = records[min(dialect.SkipRows, len(records)):]
+	}
+	headers := 1
+	if dialect.HeaderRowCount != nil {
+		headers = *dialect.HeaderRowCount
+	} else if dialect.Header != nil && !*dialect.Header {
+		headers = 0
+	}
+	headers = min(headers, len(records))
+	var titles []string
+	if headers > 0 {
+		titles = records[0]
+	}
+	c.columns = c.table.TableSchema.Columns
+	if len(c.columns) == 0 {
+		width := len(titles)
+		for _, record := range records {
+			width = max(width, len(record))
+		}
+		for i := range width {
+			column := csvwColumn{}
+			if i < len(titles) {
+				column.Titles, _ = json.Marshal(titles[i])
+			}
+			c.columns = append(c.columns, column)
+		}
+	}
+	schema := c.table.TableSchema.csvwInherited.inherit(c.table.csvwInherited)
+	for i := range c.columns {
+		column := &c.columns[i]
+		if len(column.Name) == 0 {
+			column.Name = column.title()
+		}
+		if len(column.Name) == 0 {
+			column.Name = "_col." + strconv.Itoa(i+1)
+		}
+		c.props = append(c.props, column.csvwInherited.inherit(schema))
+	}
+
+	var group, table Term
+	if c.mode == CSVWStandard {
+		group, table = NewAnonNode(), NewAnonNode()
+		c.g.AddTriple(group, RDF.Get("type"), CSVW.Get("TableGroup"))
+		c.g.AddTriple(group, CSVW.Get("table"), table)
+		c.g.AddTriple(table, RDF.Get("type"), CSVW.Get("Table"))
+		c.g.AddTriple(table, CSVW.Get("url"), NewResource(c.url))
+	}
+	for i, record := range records[headers:] {
+		if c.table.SuppressOutput {
+			break
+		}
+		sourceRow := dialect.SkipRows + headers + i + 1
+		if err := c.row(table, record, i+1, sourceRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// row generates the statements of a row, numbered from 1, and from 1 in the
+// file for sourceRow
+func (c *csvwConverter) row(table Term, record []string, number int, sourceRow int) error {
+	trim := c.table.Dialect.Trim
+	values := make(map[string]string, len(c.columns))
+	cells := make([][]string, len(c.columns))
+	for i, column := range c.columns {
+		var raw string
+		if i < len(record) {
+			raw = record[i]
+		}
+		switch trim {
+		case nil, true, "true":
+			raw = strings.TrimSpace(raw)
+		case "start":
+			raw = strings.TrimLeft(raw, " \t\r\n")
+		case "end":
+			raw = strings.TrimRight(raw, " \t\r\n")
+		}
+		if column.Virtual {
+			continue
+		}
+		cells[i] = c.cellValues(raw, c.props[i])
+		if len(cells[i]) > 0 {
+			values[column.Name] = strings.Join(cells[i], ",")
+		}
+	}
+
+	var rowNode Term
+	if c.mode == CSVWStandard {
+		rowNode = NewAnonNode()
+		c.g.AddTriple(table, CSVW.Get("row"), rowNode)
+		c.g.AddTriple(rowNode, RDF.Get("type"), CSVW.Get("Row"))
+		c.g.AddTriple(rowNode, CSVW.Get("rownum"), NewLiteralWithDatatype(strconv.Itoa(number), XSD.Get("integer")))
+		c.g.AddTriple(rowNode, CSVW.Get("url"), NewResource(c.url+"#row="+strconv.Itoa(sourceRow)))
+	}
+	var defaultSubject Term
+	described := make(map[string]bool)
+	for i, column := range c.columns {
+		props := c.props[i]
+		vars := map[string]string{"_row": strconv.Itoa(number), "_sourceRow": strconv.Itoa(sourceRow), "_column": strconv.Itoa(i + 1), "_sourceColumn": strconv.Itoa(i + 1), "_name": column.Name}
+		for name, value := range values {
+			vars[name] = value
+		}
+		var subject Term
+		if props.AboutURL != nil {
+			subject = NewResource(c.expand(*props.AboutURL, vars))
+		} else {
+			if defaultSubject == nil {
+				defaultSubject = NewAnonNode()
+			}
+			subject = defaultSubject
+		}
+		if rowNode != nil && !described[subject.String()] && (len(cells[i]) > 0 || column.Virtual) {
+			described[subject.String()] = true
+			c.g.AddTriple(rowNode, CSVW.Get("describes"), subject)
+		}
+		if column.SuppressOutput || len(cells[i]) == 0 && !column.Virtual {
+			continue
+		}
+		if column.Virtual && props.ValueURL == nil {
+			continue
+		}
+		predicate := NewResource(c.url + "#" + url.PathEscape(column.Name))
+		if props.PropertyURL != nil {
+			predicate = NewResource(c.expand(*props.PropertyURL, vars))
+		}
+		var objects []Term
+		if props.ValueURL != nil {
+			objects = []Term{NewResource(c.expand(*props.ValueURL, vars))}
+		} else {
+			datatype, err := c.datatype(props)
+			if err != nil {
+				return fmt.Errorf("column %s: %s", column.Name, err)
+			}
+			for _, value := range cells[i] {
+				objects = append(objects, csvwLiteral(value, datatype, props))
+			}
+		}
+		if props.Ordered != nil && *props.Ordered && props.Separator != nil {
+			c.g.AddTriple(subject, predicate, c.list(objects))
+			continue
+		}
+		for _, object := range objects {
+			c.g.AddTriple(subject, predicate, object)
+		}
+	}
+	return nil
+}
+
+// cellValues returns the values of a cell, none for null cells, split by the
+// separator of the column
+func (c *csvwConverter) cellValues(raw string, props csvwInherited) []string {
+	if len(raw) == 0 && props.Default != nil {
+		raw = *props.Default
+	}
+	nulls := []string{""}
+	if props.Null != nil {
+		var null string
+		if json.Unmarshal(props.Null, &null) == nil {
+			nulls = []string{null}
+		} else {
+			json.Unmarshal(props.Null, &nulls)
+		}
+	}
+	isNull := func(s string) bool {
+		for _, null := range nulls {
+			if s == null {
+				return true
+			}
+		}
+		return false
+	}
+	if isNull(raw) {
+		return nil
+	}
+	if props.Separator == nil {
+		return []string{raw}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	var values []string
+	for _, value := range strings.Split(raw, *props.Separator) {
+		if value = strings.TrimSpace(value); !isNull(value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// csvwDatatype is a datatype of a column, with its format
+type csvwDatatype struct {
+	IRI    string
+	Format string
+	// plain is set for strings, which have a language rather than a datatype
+	plain bool
+}
+
+// datatype returns the datatype of the cells of a column
+func (c *csvwConverter) datatype(props csvwInherited) (csvwDatatype, error) {
+	if props.Datatype == nil {
+		return csvwDatatype{IRI: xsdNamespace + "string", plain: true}, nil
+	}
+	var name string
+	var format string
+	if json.Unmarshal(props.Datatype, &name) != nil {
+		var desc struct {
+			ID     string `json:"@id"`
+			Base   string `json:"base"`
+			Format any    `json:"format"`
+		}
+		if err := json.Unmarshal(props.Datatype, &desc); err != nil {
+			return csvwDatatype{}, fmt.Errorf("invalid datatype: %s", err)
+		}
+		if len(desc.ID) > 0 {
+			return csvwDatatype{IRI: c.expandCURIE(desc.ID)}, nil
+		}
+		name = desc.Base
+		if name == "" {
+			name = "string"
+		}
+		format, _ = desc.Format.(string)
+	}
+	if isAbsoluteIRI(name) {
+		return csvwDatatype{IRI: c.expandCURIE(name)}, nil
+	}
+	if iri, ok := csvwDatatypes[name]; ok {
+		return csvwDatatype{IRI: iri, Format: format}, nil
+	}
+	return csvwDatatype{IRI: xsdNamespace + name, Format: format, plain: name == "string"}, nil
+}
+
+// csvwLiteral returns the literal of a cell value
+func csvwLiteral(value string, datatype csvwDatatype, props csvwInherited) Term {
+	if datatype.plain {
+		if props.Lang != nil && len(*props.Lang) > 0 && *props.Lang != "und" {
+			return NewLiteralWithLanguage(value, *props.Lang)
+		}
+		return NewLiteral(value)
+	}
+	if datatype.IRI == xsdNamespace+"boolean" {
+		if yes, no, ok := strings.Cut(datatype.Format, "|"); ok {
+			switch value {
+			case yes:
+				value = "true"
+			case no:
+				value = "false"
+			}
+		}
+	}
+	return NewLiteralWithDatatype(value, NewResource(datatype.IRI))
+}
+
+// list adds an rdf:List of terms, and returns its head
+func (c *csvwConverter) list(terms []Term) Term {
+	head := RDF.Get("nil")
+	for i := len(terms) - 1; i >= 0; i-- {
+		cell := NewAnonNode()
+		c.g.AddTriple(cell, RDF.Get("first"), terms[i])
+		c.g.AddTriple(cell, RDF.Get("rest"), head)
+		head = cell
+	}
+	return head
+}
+
+// expand expands a URI template with the values of a row, following the
+// simple, reserved ({+var}) and fragment ({#var}) expansions of RFC 6570,
+// and resolves it against the URL of the table. Prefixed names of the
+// initial context of CSVW are expanded too.
+func (c *csvwConverter) expand(template string, vars map[string]string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template[max(start, 0):], '}')
+		if start < 0 || end < 0 {
+			b.WriteString(template)
+			break
+		}
+		b.WriteString(template[:start])
+		expression := template[start+1 : start+end]
+		template = template[start+end+1:]
+		operator := ""
+		if len(expression) > 0 && (expression[0] == '+' || expression[0] == '#') {
+			operator, expression = expression[:1], expression[1:]
+		}
+		var values []string
+		for _, name := range strings.Split(expression, ",") {
+			if value, ok := vars[name]; ok {
+				values = append(values, uriTemplateEscape(value, operator != ""))
+			}
+		}
+		if len(values) > 0 && operator == "#" {
+			b.WriteByte('#')
+		}
+		b.WriteString(strings.Join(values, ","))
+	}
+	return resolveIRI(c.url, c.expandCURIE(b.String()))
+}
+
+// expandCURIE expands the prefixed names of the initial context of CSVW
+func (c *csvwConverter) expandCURIE(iri string) string {
+	if prefix, local, ok := strings.Cut(iri, ":"); ok && !strings.HasPrefix(local, "//") {
+		if ns, ok := csvwPrefixes[prefix]; ok {
+			return ns + local
+		}
+	}
+	return iri
+}
+
+// uriTemplateEscape percent-encodes the characters that are not unreserved,
+// or reserved as well unless reserved is set
+func uriTemplateEscape(value string, reserved bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', strings.IndexByte("-._~", c) >= 0:
+			b.WriteByte(c)
+		case reserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
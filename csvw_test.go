@@ -0,0 +1,143 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csvwTestData = `id,name,born,tags,active
+1,Alice Smith,1990-04-01,a b,Y
+2,Bob,,,N
+`
+
+const csvwTestMetadata = `{
+	"@context": "http://www.w3.org/ns/csvw",
+	"url": "people.csv",
+	"tableSchema": {
+		"aboutUrl": "people/{id}",
+		"columns": [
+			{"name": "id", "titles": "id", "suppressOutput": true},
+			{"name": "name", "titles": "name", "propertyUrl": "foaf:name", "lang": "en"},
+			{"name": "born", "titles": "born", "propertyUrl": "schema:birthDate", "datatype": "date"},
+			{"name": "tags", "titles": "tags", "separator": " ", "ordered": true},
+			{"name": "active", "titles": "active", "datatype": {"base": "boolean", "format": "Y|N"}},
+			{"virtual": true, "propertyUrl": "rdf:type", "valueUrl": "foaf:Person"},
+			{"virtual": true, "propertyUrl": "foaf:homepage", "valueUrl": "https://example.org/~{+name}"}
+		]
+	}
+}`
+
+func TestImportCSVWMinimal(t *testing.T) {
+	g := NewGraph("https://example.org/data/")
+	n, err := g.ImportCSVW(strings.NewReader(csvwTestData), strings.NewReader(csvwTestMetadata), CSVWMinimal)
+	assert.NoError(t, err)
+	assert.Equal(t, g.Len(), n)
+
+	alice := NewResource("https://example.org/data/people/1")
+	bob := NewResource("https://example.org/data/people/2")
+	assert.Equal(t, NewLiteralWithLanguage("Alice Smith", "en"), g.One(alice, FOAF.Get("name"), nil).Object)
+	assert.Equal(t, NewLiteralWithDatatype("1990-04-01", XSD.Get("date")), g.One(alice, NewResource("http://schema.org/birthDate"), nil).Object)
+	assert.Equal(t, NewLiteralWithDatatype("true", XSD.Get("boolean")), g.One(alice, NewResource("https://example.org/data/people.csv#active"), nil).Object)
+	assert.Equal(t, NewLiteralWithDatatype("false", XSD.Get("boolean")), g.One(bob, NewResource("https://example.org/data/people.csv#active"), nil).Object)
+	assert.NotNil(t, g.One(alice, RDF.Get("type"), FOAF.Get("Person")))
+	assert.NotNil(t, g.One(bob, RDF.Get("type"), FOAF.Get("Person")))
+	assert.NotNil(t, g.One(alice, FOAF.Get("homepage"), NewResource("https://example.org/~Alice%20Smith")))
+
+	// suppressed and null cells generate nothing
+	assert.Nil(t, g.One(alice, NewResource("https://example.org/data/people.csv#id"), nil))
+	assert.Nil(t, g.One(bob, NewResource("http://schema.org/birthDate"), nil))
+	assert.Nil(t, g.One(bob, NewResource("https://example.org/data/people.csv#tags"), nil))
+
+	// ordered separated cells are lists
+	head := g.One(alice, NewResource("https://example.org/data/people.csv#tags"), nil)
+	if assert.NotNil(t, head) {
+		elements, err := (&unmarshaler{graph: g}).listElements(head.Object)
+		assert.NoError(t, err)
+		assert.Equal(t, []Term{NewLiteral("a"), NewLiteral("b")}, elements)
+	}
+	assert.Nil(t, g.One(nil, CSVW.Get("row"), nil))
+}
+
+func TestImportCSVWStandard(t *testing.T) {
+	g := NewGraph("https://example.org/data/")
+	_, err := g.ImportCSVW(strings.NewReader(csvwTestData), strings.NewReader(`{"tables": [`+csvwTestMetadata+`]}`), CSVWStandard)
+	assert.NoError(t, err)
+
+	group := g.One(nil, RDF.Get("type"), CSVW.Get("TableGroup"))
+	if !assert.NotNil(t, group) {
+		return
+	}
+	table := g.One(group.Subject, CSVW.Get("table"), nil).Object
+	assert.NotNil(t, g.One(table, CSVW.Get("url"), NewResource("https://example.org/data/people.csv")))
+	rows := g.All(table, CSVW.Get("row"), nil)
+	assert.Len(t, rows, 2)
+	row := g.One(nil, CSVW.Get("rownum"), NewLiteralWithDatatype("2", XSD.Get("integer")))
+	if assert.NotNil(t, row) {
+		assert.NotNil(t, g.One(row.Subject, CSVW.Get("url"), NewResource("https://example.org/data/people.csv#row=3")))
+		assert.NotNil(t, g.One(row.Subject, CSVW.Get("describes"), NewResource("https://example.org/data/people/2")))
+	}
+}
+
+func TestImportCSVWDefaults(t *testing.T) {
+	data := "# a comment\ncountry;population;languages\nFrance;68;fr\nBelgium; 11 ;nl|fr|de\nNowhere;-;\n"
+	metadata := `{
+		"url": "https://example.org/countries.csv",
+		"dialect": {"delimiter": ";", "commentPrefix": "#"},
+		"null": "-",
+		"tableSchema": {
+			"columns": [
+				{"titles": {"en": ["country"]}},
+				{"titles": "population", "datatype": "integer"},
+				{"titles": "languages", "separator": "|"},
+				{"titles": "continent", "default": "Europe"}
+			]
+		}
+	}`
+	g := NewGraph("")
+	n, err := g.ImportCSVW(strings.NewReader(data), strings.NewReader(metadata), CSVWMinimal)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, n)
+
+	population := NewResource("https://example.org/countries.csv#population")
+	belgium := g.One(nil, NewResource("https://example.org/countries.csv#country"), NewLiteral("Belgium"))
+	if assert.NotNil(t, belgium) {
+		_, ok := belgium.Subject.(*BlankNode)
+		assert.True(t, ok)
+		assert.Equal(t, NewLiteralWithDatatype("11", XSD.Get("integer")), g.One(belgium.Subject, population, nil).Object)
+		assert.Len(t, g.All(belgium.Subject, NewResource("https://example.org/countries.csv#languages"), nil), 3)
+		assert.NotNil(t, g.One(belgium.Subject, NewResource("https://example.org/countries.csv#continent"), NewLiteral("Europe")))
+	}
+	nowhere := g.One(nil, NewResource("https://example.org/countries.csv#country"), NewLiteral("Nowhere"))
+	if assert.NotNil(t, nowhere) {
+		assert.Nil(t, g.One(nowhere.Subject, population, nil))
+	}
+}
+
+func TestImportCSVWWithoutSchema(t *testing.T) {
+	g := NewGraph("https://example.org/")
+	_, err := g.ImportCSVW(strings.NewReader("first name,age\nAlice,28\n"), strings.NewReader(`{"url": "t.csv"}`), CSVWMinimal)
+	assert.NoError(t, err)
+	assert.NotNil(t, g.One(nil, NewResource("https://example.org/t.csv#first%20name"), NewLiteral("Alice")))
+	assert.NotNil(t, g.One(nil, NewResource("https://example.org/t.csv#age"), NewLiteral("28")))
+}
+
+func TestImportCSVWErrors(t *testing.T) {
+	g := NewGraph("https://example.org/")
+	_, err := g.ImportCSVW(strings.NewReader("a\n1\n"), strings.NewReader(`{"url": `), CSVWMinimal)
+	assert.Error(t, err)
+	_, err = g.ImportCSVW(strings.NewReader("a\n1\n"), strings.NewReader(`{"url": "t.csv", "tableSchema": {"columns": [{"name": "a", "datatype": 1}]}}`), CSVWMinimal)
+	assert.Error(t, err)
+	assert.Equal(t, 0, g.Len())
+}
+
+func TestURITemplateExpansion(t *testing.T) {
+	c := &csvwConverter{url: "https://example.org/t.csv"}
+	vars := map[string]string{"id": "a b/c", "n": "1"}
+	assert.Equal(t, "https://example.org/p/a%20b%2Fc", c.expand("p/{id}", vars))
+	assert.Equal(t, "https://example.org/p/a%20b/c", c.expand("p/{+id}", vars))
+	assert.Equal(t, "https://example.org/t.csv#a%20b/c", c.expand("{#id}", vars))
+	assert.Equal(t, "https://example.org/t.csv?x=1", c.expand("?x={n}{missing}", vars))
+	assert.Equal(t, "http://www.w3.org/2004/02/skos/core#Concept", c.expand("skos:Concept", vars))
+}
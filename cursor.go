@@ -0,0 +1,79 @@
+package rdf2go
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor token cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is an opaque token marking a position in a dataset. Quads are
+// visited in insertion order, so a cursor remains valid across requests and
+// mutations: quads removed in the meantime are skipped and quads added later
+// are returned on subsequent pages. The zero value marks the beginning.
+type Cursor string
+
+const cursorPrefix = "q1:"
+
+func encodeCursor(seq uint64) Cursor {
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(seq, 10))))
+}
+
+func decodeCursor(c Cursor) (uint64, error) {
+	if len(c) == 0 {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+	seq, err := strconv.ParseUint(string(raw[len(cursorPrefix):]), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return seq, nil
+}
+
+// Page returns up to limit quads following the position marked by cursor,
+// together with the cursor of the next page. The returned cursor is empty
+// once the end of the dataset has been reached.
+func (d *Dataset) Page(cursor Cursor, limit int) ([]*Quad, Cursor, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		return nil, "", errors.New("page limit must be positive")
+	}
+
+	type entry struct {
+		quad *Quad
+		seq  uint64
+	}
+	var entries []entry
+	unlock := d.rlock()
+	for quad, seq := range d.quads {
+		if seq > after {
+			entries = append(entries, entry{quad, seq})
+		}
+	}
+	unlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+
+	var next Cursor
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next = encodeCursor(entries[limit-1].seq)
+	}
+	quads := make([]*Quad, len(entries))
+	for i, e := range entries {
+		quads[i] = e.quad
+	}
+	return quads, next, nil
+}
@@ -0,0 +1,47 @@
+package rdf2go
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetPage(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	for i := 0; i < 5; i++ {
+		d.AddTriple(NewResource(fmt.Sprintf("s%d", i)), NewResource("p"), NewLiteral("o"))
+	}
+
+	quads, next, err := d.Page("", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(quads))
+	assert.Equal(t, "s0", quads[0].Subject.RawValue())
+	assert.Equal(t, "s1", quads[1].Subject.RawValue())
+	assert.NotEmpty(t, next)
+
+	// mutations between requests do not invalidate the cursor
+	d.Remove(d.One(NewResource("s2"), nil, nil, nil))
+	d.AddTriple(NewResource("s5"), NewResource("p"), NewLiteral("o"))
+
+	quads, next, err = d.Page(next, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(quads))
+	assert.Equal(t, "s3", quads[0].Subject.RawValue())
+	assert.Equal(t, "s4", quads[1].Subject.RawValue())
+
+	quads, next, err = d.Page(next, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(quads))
+	assert.Equal(t, "s5", quads[0].Subject.RawValue())
+	assert.Empty(t, next)
+}
+
+func TestDatasetPageInvalidCursor(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	_, _, err := d.Page("not a cursor", 10)
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, _, err = d.Page("", 0)
+	assert.Error(t, err)
+}
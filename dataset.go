@@ -2,42 +2,160 @@ package rdf2go
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
 
 	rdf "github.com/deiu/gon3"
-	jsonld "github.com/linkeddata/gojsonld"
 )
 
 // Dataset structure holds multiple named graphs
 type Dataset struct {
-	quads      map[*Quad]bool
-	httpClient *http.Client
-	uri        string
-	term       Term
+	quads         map[*Quad]uint64
+	seq           uint64
+	graphs        map[string]*graphIndex
+	subscriptions []*Subscription
+	sources       map[*Quad]string
+	arena         *TermArena
+	dict          *TermDictionary
+	iriPolicy     *IRIPolicy
+	config        *Config
+	prefixes      map[string]string
+	httpClient    *http.Client
+	uri           string
+	term          Term
+	guard
 }
 
-// NewDataset creates a Dataset object
+// The graph selectors of the pattern matching methods of datasets: while nil
+// selects the default graph, or the union of the named graphs when
+// Config.UnionDefaultGraph is set, DefaultGraph always selects the stored
+// default graph, UnionGraph the union of the named graphs and AnyGraph every
+// graph, the default one included, whatever the configuration. Quads added
+// to DefaultGraph go to the default graph.
+var (
+	DefaultGraph Term = &graphSelector{"DEFAULT"}
+	UnionGraph   Term = &graphSelector{"UNION"}
+	AnyGraph     Term = &graphSelector{"ANY"}
+)
+
+// storedGraph returns the selector of a graph as stored, nil being the
+// default graph alone, for the methods reading the graphs they write to
+func storedGraph(g Term) Term {
+	if g == nil {
+		return DefaultGraph
+	}
+	return g
+}
+
+// graphSelector is a graph selector, only equal to itself
+type graphSelector struct {
+	name string
+}
+
+func (s *graphSelector) String() string {
+	return s.name
+}
+
+func (s *graphSelector) RawValue() string {
+	return s.name
+}
+
+func (s *graphSelector) Equal(other Term) bool {
+	return s == other
+}
+
+// NewDataset creates a Dataset object. It is a shorthand for
+// NewDatasetWithOptions with the WithSkipVerify option.
 func NewDataset(uri string, skipVerify ...bool) *Dataset {
-	skip := false
-	if len(skipVerify) > 0 {
-		skip = skipVerify[0]
+	return NewDatasetWithOptions(uri, skipVerifyOptions(skipVerify)...)
+}
+
+// NewUnindexedDataset creates a Dataset object that does not maintain lookup
+// indexes, trading pattern matching speed for a smaller memory footprint. It
+// is a shorthand for NewDatasetWithOptions with WithIndexing(false).
+func NewUnindexedDataset(uri string, skipVerify ...bool) *Dataset {
+	return NewDatasetWithOptions(uri, append(skipVerifyOptions(skipVerify), WithIndexing(false))...)
+}
+
+// NewDatasetWithOptions creates a Dataset object configured by the
+// package-level configuration and the given options
+func NewDatasetWithOptions(uri string, opts ...Option) *Dataset {
+	config := newConfig(opts...)
+	d := newDataset(uri, config, config.client())
+	if !config.Unindexed {
+		d.graphs = make(map[string]*graphIndex)
 	}
+	return d
+}
+
+func newDataset(uri string, config *Config, client *http.Client) *Dataset {
 	d := &Dataset{
-		quads:      make(map[*Quad]bool),
-		httpClient: NewHttpClient(skip),
+		quads:      make(map[*Quad]uint64),
+		config:     config,
+		httpClient: client,
 		uri:        uri,
 		term:       NewResource(uri),
+		prefixes:   config.boundPrefixes(),
+		guard:      newGuard(config),
+	}
+	if config.InternTerms {
+		d.dict = NewTermDictionary()
 	}
 	return d
 }
 
+// Config returns a copy of the configuration of the dataset
+func (d *Dataset) Config() Config {
+	return d.config.copy()
+}
+
+// SetArena makes the parsers of the dataset allocate terms and quads from
+// arena, which suits bulk loads whose result is discarded as a whole. Passing
+// nil restores individual allocations. Arenas are currently used by the
+// N-Quads and N-Triples parsers.
+func (d *Dataset) SetArena(arena *TermArena) {
+	d.arena = arena
+}
+
+// SetIRIPolicy sets the IRI equivalence policy used when matching and adding
+// quads, re-indexing the dataset. Passing nil restores exact comparison.
+// Quads that were added before the policy was set are kept, even when they
+// are equivalent under the new policy.
+func (d *Dataset) SetIRIPolicy(policy *IRIPolicy) {
+	defer d.lock()()
+	d.iriPolicy = policy
+	if d.graphs != nil {
+		d.graphs = make(map[string]*graphIndex)
+		for q := range d.quads {
+			d.index(q)
+		}
+	}
+}
+
+// index adds a quad to the index of its graph
+func (d *Dataset) index(q *Quad) {
+	gk := d.iriPolicy.key(q.Graph)
+	gi, found := d.graphs[gk]
+	if !found {
+		gi = &graphIndex{term: q.Graph, idx: newSPOIndex[*Quad]()}
+		d.graphs[gk] = gi
+	}
+	sk, pk, ok := d.iriPolicy.quadKeys(q)
+	gi.idx.add(sk, pk, ok, q)
+}
+
 // Len returns the length of the dataset as number of quads
 func (d *Dataset) Len() int {
+	defer d.rlock()()
 	return len(d.quads)
 }
 
@@ -53,259 +171,502 @@ func (d *Dataset) URI() string {
 
 // Add is used to add a Quad object to the dataset
 func (d *Dataset) Add(q *Quad) {
-	d.quads[q] = true
+	d.addFrom(q, "")
+}
+
+// addFrom adds a quad read from the document source, which is recorded as
+// its provenance when not empty
+func (d *Dataset) addFrom(q *Quad, source string) {
+	if d.add(q, source) {
+		d.notify(QuadAdded, q)
+	}
+}
+
+// add adds a quad, telling whether it was missing from the dataset
+func (d *Dataset) add(q *Quad, source string) bool {
+	defer d.lock()()
+	return d.addUnlocked(q, source)
+}
+
+// addUnlocked implements add
+func (d *Dataset) addUnlocked(q *Quad, source string) bool {
+	if !d.insertUnlocked(q) {
+		return false
+	}
+	if d.graphs != nil {
+		d.index(q)
+	}
+	if len(source) > 0 {
+		if d.sources == nil {
+			d.sources = make(map[*Quad]string)
+		}
+		d.sources[q] = source
+	}
+	return true
+}
+
+// insertUnlocked adds a quad without indexing it, telling whether it was
+// missing from the dataset
+func (d *Dataset) insertUnlocked(q *Quad) bool {
+	if q.Graph == DefaultGraph {
+		q.Graph = nil
+	}
+	if _, selector := q.Graph.(*graphSelector); selector {
+		return false
+	}
+	if _, exists := d.quads[q]; exists {
+		return false
+	}
+	d.dict.internQuad(q)
+	if d.iriPolicy != nil {
+		equivalent := false
+		d.matchUnlocked(q.Subject, q.Predicate, q.Object, q.Graph, func(*Quad) bool {
+			equivalent = true
+			return false
+		})
+		if equivalent {
+			return false
+		}
+	}
+	d.seq++
+	d.quads[q] = d.seq
+	return true
 }
 
 // AddQuad is used to add a quad made of individual S, P, O, G objects
 func (d *Dataset) AddQuad(s Term, p Term, o Term, g Term) {
-	d.quads[NewQuad(s, p, o, g)] = true
+	d.Add(NewQuad(s, p, o, g))
 }
 
 // AddTriple is used to add a triple to the default graph (G = nil)
 func (d *Dataset) AddTriple(s Term, p Term, o Term) {
-	d.quads[NewQuad(s, p, o, nil)] = true
+	d.Add(NewQuad(s, p, o, nil))
 }
 
 // Remove is used to remove a Quad object
 func (d *Dataset) Remove(q *Quad) {
+	if d.remove(q) {
+		d.notify(QuadRemoved, q)
+	}
+}
+
+// remove removes a quad, telling whether it was part of the dataset
+func (d *Dataset) remove(q *Quad) bool {
+	defer d.lock()()
+	return d.removeUnlocked(q)
+}
+
+// removeUnlocked implements remove
+func (d *Dataset) removeUnlocked(q *Quad) bool {
+	if _, exists := d.quads[q]; !exists {
+		return false
+	}
 	delete(d.quads, q)
+	delete(d.sources, q)
+	if d.graphs != nil {
+		gk := d.iriPolicy.key(q.Graph)
+		if gi, ok := d.graphs[gk]; ok {
+			sk, pk, ok := d.iriPolicy.quadKeys(q)
+			gi.idx.remove(sk, pk, ok, q)
+			if gi.idx.size == 0 {
+				delete(d.graphs, gk)
+			}
+		}
+	}
+	return true
 }
 
 // IterQuads provides a channel containing all the quads in the dataset.
 func (d *Dataset) IterQuads() (ch chan *Quad) {
-	ch = make(chan *Quad, len(d.quads))
-	for quad := range d.quads {
+	quads := slices.Collect(d.Quads())
+	ch = make(chan *Quad, len(quads))
+	for _, quad := range quads {
 		ch <- quad
 	}
 	close(ch)
 	return ch
 }
 
+// Quads returns an iterator over all the quads in the dataset. Unlike
+// IterQuads, quads are yielded lazily and iteration can be stopped early.
+// Both follow the iteration order of the configuration, and iterate over a
+// snapshot of thread-safe datasets.
+func (d *Dataset) Quads() iter.Seq[*Quad] {
+	return func(yield func(*Quad) bool) {
+		if d.config.Order != OrderUnspecified || d.mu != nil {
+			var quads []*Quad
+			switch d.config.Order {
+			case OrderUnspecified:
+				unlock := d.rlock()
+				quads = slices.Collect(maps.Keys(d.quads))
+				unlock()
+			case OrderSorted:
+				quads = d.orderedQuads()
+				sortQuads(quads)
+			default:
+				quads = d.orderedQuads()
+			}
+			for _, quad := range quads {
+				if !yield(quad) {
+					return
+				}
+			}
+			return
+		}
+		for quad := range d.quads {
+			if !yield(quad) {
+				return
+			}
+		}
+	}
+}
+
 // GetGraph returns a Graph containing all triples for a specific named graph
 func (d *Dataset) GetGraph(graphName Term) *Graph {
-	g := NewGraph(d.uri)
-	for quad := range d.IterQuads() {
-		// Handle default graph (nil) vs named graphs
-		if graphName == nil && quad.Graph == nil {
-			g.Add(quad.ToTriple())
-		} else if graphName != nil && quad.Graph != nil && graphName.Equal(quad.Graph) {
-			g.Add(quad.ToTriple())
+	g := newGraph(d.uri, d.config, d.httpClient)
+	g.index = newSPOIndex[*Triple]()
+	g.iriPolicy = d.iriPolicy
+	// the union of several graphs holds each statement once
+	union := graphName == UnionGraph || graphName == AnyGraph || graphName == nil && d.config.UnionDefaultGraph
+	seen := make(map[string]bool)
+	d.match(nil, nil, nil, graphName, func(quad *Quad) bool {
+		t := quad.ToTriple()
+		if union {
+			k := tripleKey(d.iriPolicy, t)
+			if seen[k] {
+				return true
+			}
+			seen[k] = true
 		}
-	}
+		g.Add(t)
+		return true
+	})
 	return g
 }
 
-// GetDefaultGraph returns a Graph containing all triples in the default graph (Graph = nil)
+// GetDefaultGraph returns a Graph containing all triples in the default graph
+// (Graph = nil), or in the named graphs when Config.UnionDefaultGraph is set
 func (d *Dataset) GetDefaultGraph() *Graph {
 	return d.GetGraph(nil)
 }
 
 // GetNamedGraphs returns a list of all named graph identifiers in the dataset
 func (d *Dataset) GetNamedGraphs() []Term {
+	var result []Term
+	if d.graphs != nil {
+		defer d.rlock()()
+		for _, gi := range d.graphs {
+			if gi.term != nil {
+				result = append(result, gi.term)
+			}
+		}
+		return result
+	}
+
 	graphNames := make(map[string]Term)
 	for quad := range d.IterQuads() {
 		if quad.Graph != nil {
 			graphNames[quad.Graph.String()] = quad.Graph
 		}
 	}
-	
-	var result []Term
 	for _, graph := range graphNames {
 		result = append(result, graph)
 	}
 	return result
 }
 
-// One returns one quad based on a quad pattern of S, P, O, G objects
-func (d *Dataset) One(s Term, p Term, o Term, g Term) *Quad {
-	for quad := range d.IterQuads() {
-		if s != nil && !quad.Subject.Equal(s) {
-			continue
-		}
-		if p != nil && !quad.Predicate.Equal(p) {
-			continue
-		}
-		if o != nil && !quad.Object.Equal(o) {
-			continue
-		}
-		if g != nil && (quad.Graph == nil || !quad.Graph.Equal(g)) {
-			continue
+// Select returns a new dataset holding the graphs whose name satisfies
+// filter, which is called once per graph, with nil for the default graph.
+// The new dataset shares the quads, their provenance, the configuration and
+// the IRI policy of d.
+func (d *Dataset) Select(filter func(graph Term) bool) *Dataset {
+	selected := newDataset(d.uri, d.config, d.httpClient)
+	if d.graphs != nil {
+		selected.graphs = make(map[string]*graphIndex)
+	}
+	selected.iriPolicy = d.iriPolicy
+	selected.prefixes = maps.Clone(d.prefixes)
+	keep := make(map[string]bool)
+	for _, quad := range d.orderedQuads() {
+		key := d.iriPolicy.key(quad.Graph)
+		ok, seen := keep[key]
+		if !seen {
+			ok = filter(quad.Graph)
+			keep[key] = ok
 		}
-		if g == nil && quad.Graph != nil {
-			continue
+		if ok {
+			selected.addFrom(quad, d.Source(quad))
 		}
-		return quad
 	}
-	return nil
+	return selected
 }
 
-// All returns all quads that match a given pattern of S, P, O, G objects
+// One returns one quad based on a quad pattern of S, P, O, G objects, a nil
+// graph being the default graph and AnyGraph matching every graph
+func (d *Dataset) One(s Term, p Term, o Term, g Term) *Quad {
+	var found *Quad
+	d.match(s, p, o, g, func(quad *Quad) bool {
+		found = quad
+		return false
+	})
+	return found
+}
+
+// All returns all quads that match a given pattern of S, P, O, G objects, see
+// One
 func (d *Dataset) All(s Term, p Term, o Term, g Term) []*Quad {
 	var quads []*Quad
-	for quad := range d.IterQuads() {
-		if s != nil && !quad.Subject.Equal(s) {
-			continue
+	d.match(s, p, o, g, func(quad *Quad) bool {
+		quads = append(quads, quad)
+		return true
+	})
+	return quads
+}
+
+// InstancesOf returns the subjects typed with a class by rdf:type statements
+// of graph g (nil being the default graph), see Graph.InstancesOf
+func (d *Dataset) InstancesOf(class Term, g Term) []Term {
+	var instances []Term
+	d.match(nil, NewResource(rdfNamespace+"type"), class, g, func(quad *Quad) bool {
+		instances = append(instances, quad.Subject)
+		return true
+	})
+	return instances
+}
+
+// match calls fn for every quad matching the pattern of S, P, O objects within
+// graph g (nil being the default graph) until fn returns false. Thread-safe
+// datasets collect the matches first, so that fn runs unlocked and may modify
+// the dataset.
+func (d *Dataset) match(s Term, p Term, o Term, g Term, fn func(*Quad) bool) {
+	if g == nil && d.config.UnionDefaultGraph {
+		g = UnionGraph
+	}
+	if d.mu == nil {
+		d.matchUnlocked(s, p, o, g, fn)
+		return
+	}
+	var matches []*Quad
+	d.mu.RLock()
+	d.matchUnlocked(s, p, o, g, func(quad *Quad) bool {
+		matches = append(matches, quad)
+		return true
+	})
+	d.mu.RUnlock()
+	for _, quad := range matches {
+		if !fn(quad) {
+			return
 		}
-		if p != nil && !quad.Predicate.Equal(p) {
-			continue
+	}
+}
+
+// matchUnlocked implements match, a nil graph being the default graph
+// alone
+func (d *Dataset) matchUnlocked(s Term, p Term, o Term, g Term, fn func(*Quad) bool) {
+	if g == DefaultGraph {
+		g = nil
+	}
+	filter := func(quad *Quad) bool {
+		if !quadMatches(d.iriPolicy, quad, s, p, o, g) {
+			return true
 		}
-		if o != nil && !quad.Object.Equal(o) {
-			continue
+		return fn(quad)
+	}
+	if d.graphs != nil {
+		sk, sok := d.iriPolicy.indexKey(s)
+		pk, pok := d.iriPolicy.indexKey(p)
+		ok, ook := d.iriPolicy.indexKey(o)
+		if gk, gok := d.iriPolicy.indexKey(g); gok && g != UnionGraph && g != AnyGraph {
+			if gi, found := d.graphs[gk]; found {
+				gi.idx.match(sk, s != nil && sok, pk, p != nil && pok, ok, o != nil && ook, filter)
+			}
+			return
 		}
-		if g != nil && (quad.Graph == nil || !quad.Graph.Equal(g)) {
-			continue
+		for _, gi := range d.graphs {
+			if g == UnionGraph && gi.term == nil {
+				continue
+			}
+			if !gi.idx.match(sk, s != nil && sok, pk, p != nil && pok, ok, o != nil && ook, filter) {
+				return
+			}
 		}
-		if g == nil && quad.Graph != nil {
-			continue
+		return
+	}
+	for quad := range d.quads {
+		if !filter(quad) {
+			return
 		}
-		quads = append(quads, quad)
 	}
-	return quads
+}
+
+// quadMatches returns whether a quad matches the pattern of S, P, O objects
+// within graph g (nil being the default graph), under an IRI policy
+func quadMatches(policy *IRIPolicy, quad *Quad, s Term, p Term, o Term, g Term) bool {
+	if !policy.match(s, quad.Subject) || !policy.match(p, quad.Predicate) || !policy.match(o, quad.Object) {
+		return false
+	}
+	switch g {
+	case AnyGraph:
+		return true
+	case UnionGraph:
+		return quad.Graph != nil
+	}
+	if g == nil || g == DefaultGraph {
+		return quad.Graph == nil
+	}
+	return quad.Graph != nil && policy.Equal(quad.Graph, g)
 }
 
 // String returns the NQuads representation of the dataset
 func (d *Dataset) String() string {
-	var toString string
-	for quad := range d.IterQuads() {
-		toString += quad.String() + "\n"
-	}
-	return toString
+	var b strings.Builder
+	d.WriteTo(&b)
+	return b.String()
 }
 
 // Parse is used to parse RDF data from a reader, using the provided mime type
 func (d *Dataset) Parse(reader io.Reader, mime string) error {
+	_, err := d.ParseWithStats(reader, mime)
+	return err
+}
+
+// ParseGraph parses RDF data from a reader like Parse, adding the statements
+// of the default graph of the document to a named graph instead, such as
+// the graph recording the provenance of the document. The statements of the
+// named graphs of the document keep their graph.
+func (d *Dataset) ParseGraph(reader io.Reader, graph Term, mime string) error {
+	t := newParseTracker(d.config, d.iriPolicy)
+	t.graph = graph
+	err := d.parse(reader, mime, t)
+	t.done(d.uri)
+	return t.result(err)
+}
+
+// ParseSource parses RDF data from a reader like Parse, recording source, the
+// URI of the document, as the provenance of the added quads, see Source
+func (d *Dataset) ParseSource(reader io.Reader, source string, mime string) error {
+	t := newParseTracker(d.config, d.iriPolicy)
+	t.source = source
+	err := d.parse(reader, mime, t)
+	t.done(d.uri)
+	return t.result(err)
+}
+
+// ParseWithStats parses RDF data from a reader like Parse, returning a
+// summary of what was read, see Graph.ParseWithStats
+func (d *Dataset) ParseWithStats(reader io.Reader, mime string) (*ParseStats, error) {
+	t := newParseTracker(d.config, d.iriPolicy)
+	err := d.parse(reader, mime, t)
+	return t.done(d.uri), t.result(err)
+}
+
+func (d *Dataset) parse(reader io.Reader, mime string, t *parseTracker) error {
+	reader, err := decodeCharset(d.config.Limits.limit(reader), mime)
+	if err != nil {
+		return err
+	}
+	mime = mediaType(mime)
+	base := d.config.base(d.uri)
 	parserName := mimeParser[mime]
 	if len(parserName) == 0 {
 		parserName = "guess"
 	}
-	
+	add := func(s Term, p Term, o Term, g Term) error {
+		if g == nil {
+			g = t.graph
+		}
+		ok, err := t.admit(s, p, o, g)
+		if ok {
+			d.addFrom(d.arena.NewQuad(s, p, o, g), t.source)
+		}
+		return err
+	}
+
 	if parserName == "trig" {
-		return d.parseTrig(reader)
+		return d.parseTrig(reader, false, t, add)
 	} else if parserName == "jsonld" {
-		buf := new(bytes.Buffer)
-		buf.ReadFrom(reader)
-		jsonData, err := jsonld.ReadJSON(buf.Bytes())
+		dataSet, err := parseJSONLD(reader, base, d.config.documentLoader())
 		if err != nil {
 			return err
 		}
-		options := &jsonld.Options{}
-		options.Base = ""
-		options.ProduceGeneralizedRdf = false
-		dataSet, err := jsonld.ToRDF(jsonData, options)
-		if err != nil {
-			return err
+		// graphs are sorted by name for the statements to be added in a
+		// stable order
+		names := make([]string, 0, len(dataSet.Graphs))
+		for name := range dataSet.Graphs {
+			names = append(names, name)
 		}
-		for t := range dataSet.IterTriples() {
-			d.AddTriple(jterm2term(t.Subject), jterm2term(t.Predicate), jterm2term(t.Object))
+		sort.Strings(names)
+		for _, name := range names {
+			// the names of blank node graphs come without their _: prefix
+			var graph Term
+			if name != "@default" && !strings.Contains(name, ":") {
+				graph = NewBlankNode(name)
+			} else if name != "@default" {
+				graph = NewResource(name)
+			}
+			for _, t := range dataSet.Graphs[name] {
+				if err := add(jterm2term(t.Subject), jterm2term(t.Predicate), jterm2term(t.Object), graph); err != nil {
+					return err
+				}
+			}
 		}
 	} else if parserName == "turtle" {
-		parser, err := rdf.NewParser(d.uri).Parse(reader)
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(reader); err != nil {
+			return err
+		}
+		// gon3 cannot skip malformed statements nor limit their nesting
+		if d.config.Lenient || d.config.Limits.MaxDepth > 0 {
+			return d.parseTrig(buf, true, t, add)
+		}
+		t.declare(declaredPrefixes(buf.String(), base))
+		parser, err := rdf.NewParser(base).Parse(buf)
 		if err != nil {
 			return err
 		}
 		for s := range parser.IterTriples() {
-			d.AddTriple(rdf2term(s.Subject), rdf2term(s.Predicate), rdf2term(s.Object))
+			if err := add(rdf2term(s.Subject), rdf2term(s.Predicate), rdf2term(s.Object), nil); err != nil {
+				return err
+			}
 		}
+	} else if parserName == "nquads" || parserName == "ntriples" {
+		return parseNQuads(reader, d.arena, t.skipper(), func(quad *Quad) error {
+			if parserName == "ntriples" && quad.Graph != nil {
+				return errors.New("N-Triples statements cannot have a graph label")
+			}
+			if quad.Graph == nil {
+				quad.Graph = t.graph
+			}
+			ok, err := t.admit(quad.Subject, quad.Predicate, quad.Object, quad.Graph)
+			if ok {
+				d.addFrom(quad, t.source)
+			}
+			return err
+		})
 	} else {
 		return errors.New(parserName + " is not supported by the parser")
 	}
 	return nil
 }
 
-// parseTrig parses TriG format - simplified implementation
-func (d *Dataset) parseTrig(reader io.Reader) error {
+// parseTrig parses TriG, or Turtle with turtle set, including the quoted
+// triples of RDF-star, passing the statements to add until it fails
+func (d *Dataset) parseTrig(reader io.Reader, turtle bool, t *parseTracker, add func(Term, Term, Term, Term) error) error {
 	buf := new(bytes.Buffer)
-	buf.ReadFrom(reader)
-	content := buf.String()
-	
-	// This is a simplified TriG parser. A full implementation would require
-	// a proper grammar parser, but this handles basic TriG syntax
-	lines := strings.Split(content, "\n")
-	var currentGraph Term = nil // Default graph
-	var currentTripleLines []string
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		
-		// Handle prefix declarations
-		if strings.HasPrefix(line, "@prefix") {
-			// TODO: Handle prefixes properly - for now skip
-			continue
-		}
-		
-		// Handle graph declarations like { or <graphname> {
-		if strings.Contains(line, "{") {
-			parts := strings.Split(line, "{")
-			if len(parts) > 1 {
-				graphPart := strings.TrimSpace(parts[0])
-				if graphPart == "" {
-					currentGraph = nil // Default graph
-				} else {
-					// Parse graph name
-					currentGraph = parseGraphName(graphPart)
-				}
-			}
-			continue
-		}
-		
-		// Handle end of graph
-		if strings.Contains(line, "}") {
-			// Process any remaining triple lines
-			if len(currentTripleLines) > 0 {
-				d.processTripleLines(currentTripleLines, currentGraph)
-				currentTripleLines = nil
-			}
-			currentGraph = nil // Reset to default graph
-			continue
-		}
-		
-		// Collect lines for turtle-style parsing within graph blocks
-		if line != "" {
-			currentTripleLines = append(currentTripleLines, line)
-			// If line ends with '.', process the collected lines
-			if strings.HasSuffix(line, ".") {
-				d.processTripleLines(currentTripleLines, currentGraph)
-				currentTripleLines = nil
-			}
-		}
-	}
-	
-	// Process any remaining lines
-	if len(currentTripleLines) > 0 {
-		d.processTripleLines(currentTripleLines, currentGraph)
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return err
 	}
-	
-	return nil
-}
-
-// processTripleLines processes a set of lines that form turtle-style statements
-func (d *Dataset) processTripleLines(lines []string, currentGraph Term) {
-	// Join all lines and parse as turtle-style content
-	content := strings.Join(lines, "\n")
-	
-	// Use the gon3 parser to parse this as turtle content
-	reader := strings.NewReader(content)
-	parser, err := rdf.NewParser(d.uri).Parse(reader)
+	var addErr error
+	prefixes, err := parseTrigPrefixes(buf.String(), d.config.base(d.uri), turtle, t.skipper(), d.config.Limits.MaxDepth, func(s Term, p Term, o Term, g Term) {
+		if addErr == nil {
+			addErr = add(s, p, o, g)
+		}
+	})
+	t.declare(prefixes)
 	if err != nil {
-		return // Skip invalid content
-	}
-	
-	for s := range parser.IterTriples() {
-		d.AddQuad(rdf2term(s.Subject), rdf2term(s.Predicate), rdf2term(s.Object), currentGraph)
-	}
-}
-
-// parseGraphName parses a graph name from TriG syntax
-func parseGraphName(graphStr string) Term {
-	graphStr = strings.TrimSpace(graphStr)
-	if strings.HasPrefix(graphStr, "<") && strings.HasSuffix(graphStr, ">") {
-		return NewResource(graphStr[1 : len(graphStr)-1])
+		return err
 	}
-	// TODO: Handle prefixed names, blank nodes, etc.
-	return NewResource(graphStr)
+	return addErr
 }
 
 // Serialize serializes the dataset to a writer in the specified format
@@ -315,145 +676,166 @@ func (d *Dataset) Serialize(w io.Writer, mime string) error {
 		return d.serializeTrig(w)
 	} else if serializerName == "jsonld" {
 		return d.serializeJSONLD(w)
+	} else if serializerName == "internal" && mime == "text/html" {
+		return d.ExportHTML(w, nil)
 	}
 	// Default to NQuads
 	return d.serializeNQuads(w)
 }
 
-// serializeTrig serializes to TriG format
+// SerializeGraph serializes a single graph of the dataset, nil being the
+// default graph, reading its statements from the dataset instead of copying
+// them into a Graph. Turtle, TriG, JSON-LD and N-Triples are supported, the
+// graph being named in TriG output, and Turtle being the default as with
+// Graph.Serialize.
+func (d *Dataset) SerializeGraph(w io.Writer, graph Term, mime string) error {
+	quads := func(yield func(*Quad) bool) {
+		d.match(nil, nil, nil, storedGraph(graph), yield)
+	}
+	triples := func(yield func(*Triple) bool) {
+		for quad := range quads {
+			if !yield(quad.ToTriple()) {
+				return
+			}
+		}
+	}
+	source := d.uri
+	if graph != nil {
+		source = graph.RawValue()
+	}
+	switch mimeSerializer[mime] {
+	case "jsonld":
+		return writeJSONLD(w, triples)
+	case "trig":
+		return d.writeTrig(w, quads, source)
+	case "ntriples", "nquads":
+		return writeNTriples(w, triples, source, d.config)
+	}
+	return writeTurtle(w, slices.Collect(triples), source, d.config, d.prefixes)
+}
+
+// serializeTrig serializes to TriG format, the default graph first and the
+// named graphs in the order of their names, laid out following the
+// serialization options of the configuration
 func (d *Dataset) serializeTrig(w io.Writer) error {
-	// Group quads by graph
-	graphQuads := make(map[string][]*Quad)
-	var defaultGraphQuads []*Quad
-	
-	for quad := range d.IterQuads() {
-		if quad.Graph == nil {
-			defaultGraphQuads = append(defaultGraphQuads, quad)
+	return d.writeTrig(w, d.Quads(), d.uri)
+}
+
+// writeTrig writes quads of the dataset as a TriG document, source being the
+// IRI of the document named by comments
+func (d *Dataset) writeTrig(w io.Writer, quads iter.Seq[*Quad], source string) error {
+	graphs := make(map[string][]*Triple)
+	names := make(map[string]Term)
+	// blank nodes shared by several graphs, or naming one, keep their label
+	// as it is scoped to the whole document
+	bnodeGraphs := make(map[string]map[string]bool)
+	labeled := make(map[string]bool)
+	for quad := range quads {
+		graphName := termKey(quad.Graph)
+		graphs[graphName] = append(graphs[graphName], quad.ToTriple())
+		names[graphName] = quad.Graph
+		for _, id := range blankNodeIDs(blankNodeIDs(nil, quad.Subject), quad.Object) {
+			if bnodeGraphs[id] == nil {
+				bnodeGraphs[id] = make(map[string]bool)
+			}
+			bnodeGraphs[id][graphName] = true
+		}
+		for _, id := range blankNodeIDs(nil, quad.Graph) {
+			labeled[id] = true
+		}
+	}
+	for id, in := range bnodeGraphs {
+		if len(in) > 1 {
+			labeled[id] = true
+		}
+	}
+	keys := slices.Sorted(maps.Keys(graphs))
+
+	var b strings.Builder
+	if d.config.Serialize.Comments {
+		b.WriteString(sourceComment(source))
+	}
+	base := d.config.serializeBase(source)
+	writeTurtlePrefixes(&b, base, d.prefixes)
+	encode := turtleEncoder(base, d.prefixes)
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if d.config.Serialize.Comments {
+			b.WriteString(graphComment(names[key]))
+		}
+		if names[key] == nil {
+			b.WriteString("{\n")
 		} else {
-			graphName := quad.Graph.String()
-			graphQuads[graphName] = append(graphQuads[graphName], quad)
-		}
-	}
-	
-	// Write default graph first
-	if len(defaultGraphQuads) > 0 {
-		fmt.Fprintln(w, "{")
-		for _, quad := range defaultGraphQuads {
-			fmt.Fprintf(w, "  %s %s %s .\n", 
-				encodeTerm(quad.Subject), 
-				encodeTerm(quad.Predicate), 
-				encodeTerm(quad.Object))
-		}
-		fmt.Fprintln(w, "}")
-	}
-	
-	// Write named graphs
-	for graphName, quads := range graphQuads {
-		fmt.Fprintf(w, "\n%s {\n", graphName)
-		for _, quad := range quads {
-			fmt.Fprintf(w, "  %s %s %s .\n", 
-				encodeTerm(quad.Subject), 
-				encodeTerm(quad.Predicate), 
-				encodeTerm(quad.Object))
-		}
-		fmt.Fprintln(w, "}")
-	}
-	
-	return nil
+			b.WriteString(encode(names[key]) + " {\n")
+		}
+		writeTurtleTriples(&b, graphs[key], d.config.Serialize.indent(), d.config.Serialize, encode, labeled)
+		b.WriteString("\n}\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
 }
 
 // serializeNQuads serializes to NQuads format (default)
 func (d *Dataset) serializeNQuads(w io.Writer) error {
+	if d.config.Serialize.Comments {
+		var b strings.Builder
+		writeAnnotatedNQuads(&b, slices.Collect(d.Quads()), d.uri, true)
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
 	for quad := range d.IterQuads() {
 		fmt.Fprintln(w, quad.String())
 	}
 	return nil
 }
 
-// serializeJSONLD serializes to JSON-LD format with named graphs
+// serializeJSONLD serializes to JSON-LD format with named graphs. Following
+// the JSON-LD 1.1 RDF serialization algorithm, the document is an object
+// whose @graph holds the node objects of the default graph, each named graph
+// being a node object with its name as @id and its statements in its own
+// @graph.
 func (d *Dataset) serializeJSONLD(w io.Writer) error {
-	// Create a JSON-LD compatible structure
-	result := make(map[string]interface{})
-	
-	// Handle default graph
-	defaultGraph := d.GetDefaultGraph()
-	if defaultGraph.Len() > 0 {
-		var defaultTriples []map[string]interface{}
-		subjectMap := make(map[string]map[string]interface{})
-		
-		for triple := range defaultGraph.IterTriples() {
-			subjectID := termToJSONLDID(triple.Subject)
-			predicateID := termToJSONLDID(triple.Predicate)
-			objectValue := termToJSONLDValue(triple.Object)
-			
-			if _, exists := subjectMap[subjectID]; !exists {
-				subjectMap[subjectID] = map[string]interface{}{
-					"@id": subjectID,
-				}
-			}
-			
-			// Handle multiple values for the same predicate
-			if existing, exists := subjectMap[subjectID][predicateID]; exists {
-				// Convert to array if not already
-				if arr, isArray := existing.([]interface{}); isArray {
-					subjectMap[subjectID][predicateID] = append(arr, objectValue)
-				} else {
-					subjectMap[subjectID][predicateID] = []interface{}{existing, objectValue}
-				}
-			} else {
-				subjectMap[subjectID][predicateID] = objectValue
-			}
+	quads := d.orderedQuads()
+	for _, quad := range quads {
+		_, s := quad.Subject.(*QuotedTriple)
+		_, o := quad.Object.(*QuotedTriple)
+		if s || o {
+			return errQuotedTripleJSONLD
 		}
-		
-		for _, subjectData := range subjectMap {
-			defaultTriples = append(defaultTriples, subjectData)
-		}
-		result["@graph"] = defaultTriples
-	}
-	
-	// Handle named graphs
-	namedGraphs := d.GetNamedGraphs()
-	for _, graphName := range namedGraphs {
-		graph := d.GetGraph(graphName)
-		if graph.Len() > 0 {
-			var graphTriples []map[string]interface{}
-			subjectMap := make(map[string]map[string]interface{})
-			
-			for triple := range graph.IterTriples() {
-				subjectID := termToJSONLDID(triple.Subject)
-				predicateID := termToJSONLDID(triple.Predicate)
-				objectValue := termToJSONLDValue(triple.Object)
-				
-				if _, exists := subjectMap[subjectID]; !exists {
-					subjectMap[subjectID] = map[string]interface{}{
-						"@id": subjectID,
-					}
-				}
-				
-				// Handle multiple values for the same predicate
-				if existing, exists := subjectMap[subjectID][predicateID]; exists {
-					// Convert to array if not already
-					if arr, isArray := existing.([]interface{}); isArray {
-						subjectMap[subjectID][predicateID] = append(arr, objectValue)
-					} else {
-						subjectMap[subjectID][predicateID] = []interface{}{existing, objectValue}
-					}
-				} else {
-					subjectMap[subjectID][predicateID] = objectValue
-				}
-			}
-			
-			for _, subjectData := range subjectMap {
-				graphTriples = append(graphTriples, subjectData)
-			}
-			
-			graphNameID := termToJSONLDID(graphName)
-			result[graphNameID] = map[string]interface{}{
-				"@graph": graphTriples,
+	}
+
+	var graphNames []string
+	graphQuads := make(map[string][]*Quad)
+	for _, quad := range quads {
+		name := ""
+		if quad.Graph != nil {
+			name = termToJSONLDID(quad.Graph)
+		}
+		if _, ok := graphQuads[name]; !ok && len(name) > 0 {
+			graphNames = append(graphNames, name)
+		}
+		graphQuads[name] = append(graphQuads[name], quad)
+	}
+
+	nodes := jsonLDNodes(graphQuads[""])
+	for _, name := range graphNames {
+		var node map[string]interface{}
+		for _, n := range nodes {
+			if n["@id"] == name {
+				node = n
+				break
 			}
 		}
+		if node == nil {
+			node = map[string]interface{}{"@id": name}
+			nodes = append(nodes, node)
+		}
+		node["@graph"] = jsonLDNodes(graphQuads[name])
 	}
-	
+	result := map[string]interface{}{"@graph": nodes}
+
 	// Use json.NewEncoder to avoid HTML escaping
 	encoder := json.NewEncoder(w)
 	encoder.SetEscapeHTML(false)
@@ -461,6 +843,38 @@ func (d *Dataset) serializeJSONLD(w io.Writer) error {
 	return encoder.Encode(result)
 }
 
+// jsonLDNodes returns the node objects describing the subjects of quads, in
+// the order they first appear
+func jsonLDNodes(quads []*Quad) []map[string]interface{} {
+	nodes := []map[string]interface{}{}
+	subjects := make(map[string]map[string]interface{})
+	for _, quad := range quads {
+		subjectID := termToJSONLDID(quad.Subject)
+		predicateID := termToJSONLDID(quad.Predicate)
+		objectValue := termToJSONLDValue(quad.Object)
+
+		node, exists := subjects[subjectID]
+		if !exists {
+			node = map[string]interface{}{"@id": subjectID}
+			subjects[subjectID] = node
+			nodes = append(nodes, node)
+		}
+
+		// Handle multiple values for the same predicate
+		if existing, exists := node[predicateID]; exists {
+			// Convert to array if not already
+			if arr, isArray := existing.([]interface{}); isArray {
+				node[predicateID] = append(arr, objectValue)
+			} else {
+				node[predicateID] = []interface{}{existing, objectValue}
+			}
+		} else {
+			node[predicateID] = objectValue
+		}
+	}
+	return nodes
+}
+
 // termToJSONLDID converts a term to a JSON-LD @id value
 func termToJSONLDID(term Term) string {
 	switch t := term.(type) {
@@ -496,33 +910,71 @@ func termToJSONLDValue(term Term) interface{} {
 
 // LoadURI loads RDF data from a specific URI into the dataset
 func (d *Dataset) LoadURI(uri string) error {
-	doc := defrag(uri)
-	q, err := http.NewRequest("GET", doc, nil)
-	if err != nil {
-		return err
-	}
+	return d.LoadURIContext(context.Background(), uri)
+}
+
+// LoadURIContext loads RDF data from a specific URI into the dataset. The
+// request is aborted when the context is cancelled or its deadline expires.
+func (d *Dataset) LoadURIContext(ctx context.Context, uri string) error {
+	_, err := d.LoadURIWithResult(ctx, uri)
+	return err
+}
+
+// LoadURIWithResult loads RDF data from a specific URI like LoadURIContext,
+// and describes the document that was read: its final URL after redirects,
+// its HTTP validators and headers, and the parse error returned when the
+// document is malformed
+func (d *Dataset) LoadURIWithResult(ctx context.Context, uri string) (*LoadResult, error) {
 	if len(d.uri) == 0 {
-		d.uri = doc
-	}
-	q.Header.Set("Accept", "application/trig;q=1,text/turtle;q=0.8,application/ld+json;q=0.5")
-	r, err := d.httpClient.Do(q)
-	if err != nil {
-		return err
+		d.uri = defrag(uri)
 	}
-	if r != nil {
-		defer r.Body.Close()
-		if r.StatusCode == 200 {
-			d.Parse(r.Body, r.Header.Get("Content-Type"))
-		} else {
-			return fmt.Errorf("Could not fetch dataset from %s - HTTP %d", uri, r.StatusCode)
+	parse := d.Parse
+	if d.config.Provenance {
+		parse = func(reader io.Reader, mime string) error {
+			return d.ParseSource(reader, defrag(uri), mime)
 		}
 	}
-	return nil
+	return d.config.fetchRDF(ctx, d.httpClient, uri, "dataset", parse)
 }
 
-// Merge merges another dataset into this one
+// Merge merges another dataset into this one, along with the provenance of
+// its quads
 func (d *Dataset) Merge(toMerge *Dataset) {
 	for quad := range toMerge.IterQuads() {
-		d.Add(quad)
+		d.addFrom(quad, toMerge.Source(quad))
+	}
+}
+
+// clone returns a copy of the dataset holding the same quads, without its
+// subscriptions
+func (d *Dataset) clone() *Dataset {
+	c := &Dataset{
+		quads:      make(map[*Quad]uint64, len(d.quads)),
+		config:     d.config,
+		httpClient: d.httpClient,
+		iriPolicy:  d.iriPolicy,
+		dict:       d.dict,
+		uri:        d.uri,
+		term:       d.term,
+	}
+	if d.graphs != nil {
+		c.graphs = make(map[string]*graphIndex)
 	}
+	for quad := range d.Quads() {
+		c.addFrom(quad, d.Source(quad))
+	}
+	return c
+}
+
+// orderedQuads returns the quads of the dataset in insertion order
+func (d *Dataset) orderedQuads() []*Quad {
+	defer d.rlock()()
+	quads := make([]*Quad, 0, len(d.quads))
+	for quad := range d.quads {
+		quads = append(quads, quad)
+	}
+	sort.Slice(quads, func(i, j int) bool {
+		return d.quads[quads[i]] < d.quads[quads[j]]
+	})
+	return quads
 }
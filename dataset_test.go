@@ -2,7 +2,11 @@ package rdf2go
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"github.com/stretchr/testify/assert"
 )
@@ -141,6 +145,19 @@ func TestDatasetAll(t *testing.T) {
 	assert.Equal(t, 1, len(quads2))
 }
 
+func TestDatasetInstancesOf(t *testing.T) {
+	rdfType := NewResource("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	person := NewResource("http://xmlns.com/foaf/0.1/Person")
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), rdfType, person)
+	d.AddQuad(NewResource("b"), rdfType, person, NewResource("g"))
+	d.AddQuad(NewResource("c"), rdfType, person, NewResource("g"))
+
+	assert.Equal(t, []Term{NewResource("a")}, d.InstancesOf(person, nil))
+	assert.ElementsMatch(t, []Term{NewResource("b"), NewResource("c")}, d.InstancesOf(person, NewResource("g")))
+	assert.Empty(t, d.InstancesOf(person, NewResource("h")))
+}
+
 func TestDatasetString(t *testing.T) {
 	d := NewDataset(testDatasetUri)
 	quad := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
@@ -196,6 +213,149 @@ func TestDatasetSerializeNQuads(t *testing.T) {
 	assert.Contains(t, output, "<a> <b> <c> <g> .")
 }
 
+func TestDatasetSerializeGraph(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	graph1 := NewResource("http://example.org/graph1")
+	assert.NoError(t, d.Bind("ex", "http://example.org/"))
+	d.AddTriple(NewResource("http://example.org/alice"), NewResource("http://example.org/knows"), NewResource("http://example.org/bob"))
+	d.AddQuad(NewResource("http://example.org/bob"), NewResource("http://example.org/name"), NewLiteral("Bob"), graph1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, d.SerializeGraph(&buf, graph1, "text/turtle"))
+	assert.Equal(t, "@prefix ex: <http://example.org/> .\n\nex:bob\n  ex:name \"Bob\" .", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, d.SerializeGraph(&buf, nil, "application/n-triples"))
+	assert.Equal(t, "<http://example.org/alice> <http://example.org/knows> <http://example.org/bob> .\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, d.SerializeGraph(&buf, graph1, "application/trig"))
+	assert.Equal(t, "@prefix ex: <http://example.org/> .\n\nex:graph1 {\n  ex:bob\n    ex:name \"Bob\" .\n}\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, d.SerializeGraph(&buf, graph1, "application/ld+json"))
+	assert.Contains(t, buf.String(), "http://example.org/bob")
+	assert.NotContains(t, buf.String(), "alice")
+
+	buf.Reset()
+	assert.NoError(t, d.SerializeGraph(&buf, NewResource("http://example.org/missing"), "application/n-triples"))
+	assert.Empty(t, buf.String())
+
+	// blank node objects and escaped IRIs are kept
+	graph2 := NewResource("http://example.org/graph2")
+	s, p := NewResource("http://example.org/s"), NewResource("http://example.org/p")
+	d.AddQuad(s, p, NewBlankNode("x"), graph2)
+	d.AddQuad(s, p, NewResource("http://example.org/a%20b"), graph2)
+	buf.Reset()
+	assert.NoError(t, d.SerializeGraph(&buf, graph2, "application/ld+json"))
+	assert.Contains(t, buf.String(), `"http://example.org/a%20b"`)
+	g := NewGraph("")
+	assert.NoError(t, g.Parse(&buf, "application/ld+json"))
+	assert.Equal(t, 2, g.Len())
+	assert.NotNil(t, g.One(s, p, NewResource("http://example.org/a%20b")))
+	blank := 0
+	for _, triple := range g.All(s, p, nil) {
+		if _, ok := triple.Object.(*BlankNode); ok {
+			blank++
+		}
+	}
+	assert.Equal(t, 1, blank)
+}
+
+func TestDatasetJSONLDNamedGraphs(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:alice ex:name "Alice" .
+ex:g1 ex:source ex:web .
+ex:g1 { ex:alice ex:knows ex:bob }
+_:g2 { ex:bob ex:knows _:x }`), "application/trig"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, d.Serialize(&buf, "application/ld+json"))
+	var doc map[string][]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc, 1)
+	nodes := doc["@graph"]
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, "http://example.org/alice", nodes[0]["@id"])
+	assert.NotContains(t, nodes[0], "@graph")
+	assert.Equal(t, "http://example.org/g1", nodes[1]["@id"])
+	assert.Contains(t, nodes[1], "http://example.org/source")
+	assert.Len(t, nodes[1]["@graph"], 1)
+	assert.Equal(t, "_:g2", nodes[2]["@id"])
+
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(buf.String()), "application/ld+json"))
+	assert.Equal(t, d.Len(), d2.Len())
+	assert.NotNil(t, d2.One(NewResource("http://example.org/alice"), NewResource("http://example.org/name"), nil, nil))
+	assert.NotNil(t, d2.One(NewResource("http://example.org/g1"), NewResource("http://example.org/source"), nil, nil))
+	assert.NotNil(t, d2.One(NewResource("http://example.org/alice"), NewResource("http://example.org/knows"), NewResource("http://example.org/bob"), NewResource("http://example.org/g1")))
+	assert.Nil(t, d2.One(NewResource("http://example.org/alice"), NewResource("http://example.org/knows"), nil, nil))
+	named := d2.GetNamedGraphs()
+	assert.Len(t, named, 2)
+	for _, g := range named {
+		if _, ok := g.(*BlankNode); ok {
+			assert.Equal(t, 1, d2.GetGraph(g).Len())
+		}
+	}
+}
+
+func TestDatasetJSONLDTypedLiterals(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	s, p := NewResource("http://example.org/s"), NewResource("http://example.org/p")
+	literals := []Term{
+		NewLiteralWithDatatype("007", XSD.Get("integer")),
+		NewLiteralWithDatatype("-12", XSD.Get("integer")),
+		NewLiteralWithDatatype("1.50", XSD.Get("decimal")),
+		NewLiteralWithDatatype("1", XSD.Get("boolean")),
+		NewLiteralWithDatatype("1.0E2", XSD.Get("double")),
+		NewLiteralWithDatatype("abc", NewResource("http://example.org/dt")),
+	}
+	for _, l := range literals {
+		d.AddQuad(s, p, l, NewResource("http://example.org/g"))
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, d.Serialize(&buf, "application/ld+json"))
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(&buf, "application/ld+json"))
+	assert.Equal(t, len(literals), d2.Len())
+	for _, l := range literals {
+		assert.NotNil(t, d2.One(s, p, l, NewResource("http://example.org/g")), l.String())
+	}
+
+	// native numbers and coerced strings are typed as well
+	g := NewGraph("")
+	assert.NoError(t, g.Parse(strings.NewReader(`{
+		"@context": {"n": {"@id": "http://example.org/n", "@type": "http://www.w3.org/2001/XMLSchema#integer"}},
+		"@id": "http://example.org/s", "n": "0042", "http://example.org/m": 7
+	}`), "application/ld+json"))
+	assert.NotNil(t, g.One(s, NewResource("http://example.org/n"), NewLiteralWithDatatype("0042", XSD.Get("integer"))))
+	assert.NotNil(t, g.One(s, NewResource("http://example.org/m"), NewLiteralWithDatatype("7", XSD.Get("integer"))))
+}
+
+func TestDatasetSelect(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	acme := NewResource("http://example.org/tenants/acme/orders")
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("d"), acme)
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("e"), NewResource("http://example.org/tenants/acme/users"))
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("f"), NewResource("http://example.org/tenants/other/orders"))
+
+	calls := 0
+	selected := d.Select(func(g Term) bool {
+		calls++
+		return g != nil && strings.HasPrefix(g.RawValue(), "http://example.org/tenants/acme/")
+	})
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, 2, selected.Len())
+	assert.NotNil(t, selected.One(nil, nil, NewResource("d"), acme))
+	assert.Nil(t, selected.One(nil, nil, nil, nil))
+	assert.Equal(t, 4, d.Len())
+
+	assert.Equal(t, 1, d.Select(func(g Term) bool { return g == nil }).Len())
+	assert.Equal(t, 0, d.Select(func(g Term) bool { return false }).Len())
+}
+
 func TestDatasetMerge(t *testing.T) {
 	d1 := NewDataset(testDatasetUri)
 	d2 := NewDataset(testDatasetUri)
@@ -206,3 +366,174 @@ func TestDatasetMerge(t *testing.T) {
 	d1.Merge(d2)
 	assert.Equal(t, 2, d1.Len())
 }
+
+func TestDatasetQuads(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	d.AddQuad(NewResource("d"), NewResource("e"), NewResource("f"), NewResource("g"))
+
+	count := 0
+	for range d.Quads() {
+		count++
+	}
+	assert.Equal(t, 2, count)
+
+	count = 0
+	for range d.Quads() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestDatasetIterationOrder(t *testing.T) {
+	graphs := []string{"g3", "g1", "g2"}
+	d := NewDatasetWithOptions(testDatasetUri, WithOrder(OrderInsertion))
+	for _, g := range graphs {
+		d.AddQuad(NewResource("s"), NewResource("p"), NewResource("o"), NewResource(g))
+	}
+	var got []string
+	for quad := range d.IterQuads() {
+		got = append(got, quad.Graph.RawValue())
+	}
+	assert.Equal(t, graphs, got)
+
+	d = NewDatasetWithOptions(testDatasetUri, WithOrder(OrderSorted))
+	for _, g := range graphs {
+		d.AddQuad(NewResource("s"), NewResource("p"), NewResource("o"), NewResource(g))
+	}
+	got = nil
+	for quad := range d.Quads() {
+		got = append(got, quad.Graph.RawValue())
+	}
+	assert.Equal(t, []string{"g1", "g2", "g3"}, got)
+}
+
+func TestDatasetThreadSafe(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			graph := NewResource(fmt.Sprintf("g%d", i))
+			for j := 0; j < 100; j++ {
+				d.AddQuad(NewResource("s"), NewResource("p"), NewLiteral(fmt.Sprint(j)), graph)
+				d.All(nil, nil, nil, graph)
+				d.GetNamedGraphs()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 800, d.Len())
+	d.RemoveMatching(nil, nil, nil, NewResource("g0"))
+	assert.Equal(t, 700, d.Len())
+	assert.NoError(t, d.Verify())
+}
+
+func TestDatasetLoadURIContext(t *testing.T) {
+	uri := testServer.URL + "/foo"
+	d := NewDataset(uri)
+	err := d.LoadURIContext(context.Background(), uri)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = NewDataset(uri).LoadURIContext(ctx, testServer.URL+"/slow")
+	assert.Error(t, err)
+}
+
+func TestDatasetParseGraph(t *testing.T) {
+	graph := NewResource("http://example.org/source")
+	d := NewDataset(testDatasetUri)
+	turtle := `@prefix ex: <http://example.org/> .
+ex:a ex:p "x" ; ex:q "y" .`
+	assert.NoError(t, d.ParseGraph(strings.NewReader(turtle), graph, "text/turtle"))
+	assert.Equal(t, 2, d.Len())
+	assert.Equal(t, 2, d.GetGraph(graph).Len())
+	assert.Nil(t, d.One(nil, nil, nil, nil))
+
+	nq := `<http://example.org/a> <http://example.org/p> "z" .
+<http://example.org/a> <http://example.org/p> "w" <http://example.org/other> .
+`
+	assert.NoError(t, d.ParseGraph(strings.NewReader(nq), graph, "application/n-quads"))
+	assert.Equal(t, 3, d.GetGraph(graph).Len())
+	assert.Equal(t, 1, d.GetGraph(NewResource("http://example.org/other")).Len())
+
+	trig := `@prefix ex: <http://example.org/> .
+ex:b ex:p "v" .
+ex:g { ex:b ex:p "u" . }`
+	assert.NoError(t, d.ParseGraph(strings.NewReader(trig), graph, "application/trig"))
+	assert.Equal(t, 4, d.GetGraph(graph).Len())
+	assert.Equal(t, 1, d.GetGraph(NewResource("http://example.org/g")).Len())
+	assert.Error(t, d.ParseGraph(strings.NewReader("not turtle"), graph, "text/turtle"))
+}
+
+func TestDatasetUnionDefaultGraph(t *testing.T) {
+	for _, indexed := range []bool{true, false} {
+		d := NewDatasetWithOptions(testDatasetUri, WithUnionDefaultGraph(true), WithIndexing(indexed))
+		p := NewResource("http://example.org/p")
+		g1 := NewResource("http://example.org/g1")
+		g2 := NewResource("http://example.org/g2")
+		d.AddQuad(NewResource("http://example.org/a"), p, NewLiteral("x"), g1)
+		d.AddQuad(NewResource("http://example.org/a"), p, NewLiteral("x"), g2)
+		d.AddQuad(NewResource("http://example.org/b"), p, NewLiteral("y"), g2)
+		d.AddQuad(NewResource("http://example.org/c"), p, NewLiteral("z"), nil)
+
+		assert.Len(t, d.All(nil, p, nil, nil), 3)
+		assert.Len(t, d.All(nil, p, nil, UnionGraph), 3)
+		assert.NotNil(t, d.One(NewResource("http://example.org/b"), nil, nil, nil))
+		assert.Nil(t, d.One(NewResource("http://example.org/c"), nil, nil, nil))
+		assert.Equal(t, 2, d.GetDefaultGraph().Len())
+
+		stored := d.All(nil, nil, nil, DefaultGraph)
+		assert.Len(t, stored, 1)
+		assert.Equal(t, "http://example.org/c", stored[0].Subject.RawValue())
+		assert.Len(t, d.All(nil, nil, nil, g2), 2)
+
+		d.AddQuad(NewResource("http://example.org/d"), p, NewLiteral("w"), DefaultGraph)
+		assert.Len(t, d.All(nil, nil, nil, DefaultGraph), 2)
+		assert.Nil(t, d.One(NewResource("http://example.org/d"), nil, nil, DefaultGraph).Graph)
+
+		c := d.ClearDefaultGraph()
+		assert.Len(t, c.Removed, 2)
+		assert.Equal(t, 3, d.Len())
+	}
+}
+
+func TestDatasetGraphSelectors(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	g := NewResource("http://example.org/g")
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), g)
+	d.AddQuad(NewResource("http://example.org/b"), NewResource("http://example.org/p"), NewLiteral("y"), nil)
+
+	assert.Len(t, d.All(nil, nil, nil, nil), 1)
+	assert.Len(t, d.All(nil, nil, nil, DefaultGraph), 1)
+	assert.Len(t, d.All(nil, nil, nil, UnionGraph), 1)
+	assert.Equal(t, "http://example.org/a", d.GetGraph(UnionGraph).One(nil, nil, nil).Subject.RawValue())
+	assert.True(t, DefaultGraph.Equal(DefaultGraph))
+	assert.False(t, DefaultGraph.Equal(UnionGraph))
+}
+
+func TestDatasetAnyGraph(t *testing.T) {
+	for _, indexed := range []bool{true, false} {
+		d := NewDatasetWithOptions(testDatasetUri, WithIndexing(indexed))
+		a := NewResource("http://example.org/a")
+		p := NewResource("http://example.org/p")
+		d.AddQuad(a, p, NewLiteral("x"), NewResource("http://example.org/g1"))
+		d.AddQuad(a, p, NewLiteral("x"), NewResource("http://example.org/g2"))
+		d.AddQuad(a, p, NewLiteral("y"), nil)
+		d.AddQuad(NewResource("http://example.org/b"), p, NewLiteral("z"), nil)
+
+		assert.Len(t, d.All(a, nil, nil, AnyGraph), 3)
+		assert.Len(t, d.All(nil, nil, NewLiteral("x"), AnyGraph), 2)
+		assert.Len(t, d.All(a, nil, nil, nil), 1)
+		assert.NotNil(t, d.One(nil, nil, NewLiteral("x"), AnyGraph))
+		assert.Nil(t, d.One(nil, nil, NewLiteral("w"), AnyGraph))
+		assert.Equal(t, 3, d.GetGraph(AnyGraph).Len())
+
+		d.AddQuad(a, p, NewLiteral("w"), AnyGraph)
+		assert.Equal(t, 4, d.Len())
+	}
+}
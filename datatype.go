@@ -0,0 +1,258 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LiteralComparator holds the equality and ordering functions used for the
+// lexical values of literals sharing a datatype.
+type LiteralComparator struct {
+	// Equal reports whether two lexical values denote the same value.
+	// When nil, the lexical values are compared as strings.
+	Equal func(a, b string) bool
+
+	// Compare orders two lexical values, returning a negative number, zero or
+	// a positive number. When nil, the lexical values are compared as strings.
+	Compare func(a, b string) (int, error)
+}
+
+var datatypeComparators = struct {
+	sync.RWMutex
+	m map[string]LiteralComparator
+}{m: make(map[string]LiteralComparator)}
+
+// RegisterDatatype registers the comparator used for literals of the given
+// datatype IRI. Pattern matching (One/All) and CompareTerms use it whenever
+// both literals share that datatype.
+func RegisterDatatype(datatype string, cmp LiteralComparator) {
+	datatypeComparators.Lock()
+	defer datatypeComparators.Unlock()
+	datatypeComparators.m[debrack(datatype)] = cmp
+}
+
+// UnregisterDatatype removes the comparator registered for the given datatype IRI.
+func UnregisterDatatype(datatype string) {
+	datatypeComparators.Lock()
+	defer datatypeComparators.Unlock()
+	delete(datatypeComparators.m, debrack(datatype))
+}
+
+// lookupComparator returns the comparator registered for a datatype term, if any
+func lookupComparator(datatype Term) (LiteralComparator, bool) {
+	if datatype == nil {
+		return LiteralComparator{}, false
+	}
+	datatypeComparators.RLock()
+	defer datatypeComparators.RUnlock()
+	if len(datatypeComparators.m) == 0 {
+		return LiteralComparator{}, false
+	}
+	cmp, ok := datatypeComparators.m[datatype.RawValue()]
+	return cmp, ok
+}
+
+// hasComparator returns whether a term is a literal whose datatype has a
+// registered comparator
+func hasComparator(t Term) bool {
+	if lit, ok := t.(*Literal); ok {
+		_, found := lookupComparator(lit.Datatype)
+		return found
+	}
+	return false
+}
+
+// CompareTerms orders two terms following the SPARQL ORDER BY conventions:
+// blank nodes sort before IRIs, which sort before literals. Literals sharing a
+// datatype with a registered comparator are ordered by its Compare function,
+// all other terms by their lexical form.
+func CompareTerms(a Term, b Term) (int, error) {
+	ra, rb := termRank(a), termRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1, nil
+		}
+		return 1, nil
+	}
+	la, aok := a.(*Literal)
+	lb, bok := b.(*Literal)
+	if aok && bok {
+		if la.Datatype != nil && lb.Datatype != nil && la.Datatype.Equal(lb.Datatype) {
+			if cmp, ok := lookupComparator(la.Datatype); ok && cmp.Compare != nil {
+				c, err := cmp.Compare(la.Value, lb.Value)
+				if err != nil {
+					return 0, fmt.Errorf("cannot compare %s and %s: %s", la, lb, err)
+				}
+				return c, nil
+			}
+		}
+		if c := strings.Compare(la.Value, lb.Value); c != 0 {
+			return c, nil
+		}
+		return strings.Compare(la.String(), lb.String()), nil
+	}
+	if a == nil || b == nil {
+		return 0, nil
+	}
+	return strings.Compare(a.RawValue(), b.RawValue()), nil
+}
+
+func termRank(t Term) int {
+	switch t.(type) {
+	case nil:
+		return 0
+	case *BlankNode:
+		return 1
+	case *Resource:
+		return 2
+	case *Literal:
+		return 3
+	case *QuotedTriple:
+		return 4
+	}
+	return 5
+}
+
+var (
+	xsdIntegerLexical = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	xsdDecimalLexical = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+	xsdDoubleLexical  = regexp.MustCompile(`^([+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?|[+-]?INF|NaN)$`)
+)
+
+// xsdIntegerTypes lists xsd:integer and the datatypes derived from it
+var xsdIntegerTypes = map[string]bool{
+	"integer": true, "nonPositiveInteger": true, "negativeInteger": true,
+	"long": true, "int": true, "short": true, "byte": true,
+	"nonNegativeInteger": true, "positiveInteger": true, "unsignedLong": true,
+	"unsignedInt": true, "unsignedShort": true, "unsignedByte": true,
+}
+
+// literalValueKey returns a key identifying the value of a literal, such
+// that "1"^^xsd:integer, "01"^^xsd:int, "1.0"^^xsd:decimal and "1E0"^^xsd:double
+// share a key, as do "x" and "x"^^xsd:string. Numbers are compared after
+// promotion to a common type, as with the = operator of SPARQL. It returns
+// false for literals of other datatypes and for invalid lexical forms.
+func literalValueKey(l *Literal) (string, bool) {
+	if l.Datatype == nil {
+		if l.Language != "" {
+			return "", false
+		}
+		return NewLiteral(l.Value).String(), true
+	}
+	switch l.Datatype.RawValue() {
+	case xsdNamespace + "string":
+		return NewLiteral(l.Value).String(), true
+	case xsdNamespace + "boolean":
+		switch strings.TrimSpace(l.Value) {
+		case "true", "1":
+			return `"true"^^boolean`, true
+		case "false", "0":
+			return `"false"^^boolean`, true
+		}
+		return "", false
+	}
+	r, inf, ok := literalNumber(l)
+	switch {
+	case !ok:
+		return "", false
+	case inf != 0:
+		return `"` + strconv.Itoa(inf) + `/0"^^number`, true
+	}
+	return `"` + r.RatString() + `"^^number`, true
+}
+
+// wellFormedLiteral returns whether the lexical form of a literal is valid
+// for its datatype, among the xsd datatypes of numbers, booleans, dates and
+// times. Literals of other datatypes are always well-formed.
+func wellFormedLiteral(lit *Literal) bool {
+	if lit.Datatype == nil {
+		return true
+	}
+	switch local := strings.TrimPrefix(lit.Datatype.RawValue(), xsdNamespace); {
+	case local == "boolean":
+		_, ok := literalValueKey(lit)
+		return ok
+	case local == "float", local == "double":
+		_, _, ok := literalNumber(lit)
+		return ok || strings.TrimSpace(lit.Value) == "NaN"
+	case local == "decimal", xsdIntegerTypes[local]:
+		_, _, ok := literalNumber(lit)
+		return ok && (local == "decimal" || integerInRange(local, lit.Value))
+	case local == "dateTime", local == "date":
+		_, ok := literalTime(lit)
+		return ok
+	}
+	return true
+}
+
+// integerInRange checks the bounds of the datatypes derived from xsd:integer
+func integerInRange(local string, value string) bool {
+	n, _ := new(big.Int).SetString(strings.TrimSpace(value), 10)
+	if n == nil {
+		return false
+	}
+	bounds := map[string][2]string{
+		"nonPositiveInteger": {"", "0"},
+		"negativeInteger":    {"", "-1"},
+		"long":               {"-9223372036854775808", "9223372036854775807"},
+		"int":                {"-2147483648", "2147483647"},
+		"short":              {"-32768", "32767"},
+		"byte":               {"-128", "127"},
+		"nonNegativeInteger": {"0", ""},
+		"positiveInteger":    {"1", ""},
+		"unsignedLong":       {"0", "18446744073709551615"},
+		"unsignedInt":        {"0", "4294967295"},
+		"unsignedShort":      {"0", "65535"},
+		"unsignedByte":       {"0", "255"},
+	}[local]
+	if min, ok := new(big.Int).SetString(bounds[0], 10); ok && n.Cmp(min) < 0 {
+		return false
+	}
+	if max, ok := new(big.Int).SetString(bounds[1], 10); ok && n.Cmp(max) > 0 {
+		return false
+	}
+	return true
+}
+
+// literalNumber returns the value of a literal of an xsd numeric datatype.
+// Infinite floating-point values have a nil value and inf set to -1 or 1.
+// It returns false for other literals, invalid lexical forms and NaN.
+func literalNumber(l *Literal) (r *big.Rat, inf int, ok bool) {
+	if l.Datatype == nil {
+		return nil, 0, false
+	}
+	local, found := strings.CutPrefix(l.Datatype.RawValue(), xsdNamespace)
+	if !found {
+		return nil, 0, false
+	}
+	value := strings.TrimSpace(l.Value)
+	switch {
+	case xsdIntegerTypes[local] && xsdIntegerLexical.MatchString(value),
+		local == "decimal" && xsdDecimalLexical.MatchString(value):
+		r, ok = new(big.Rat).SetString(value)
+		return r, 0, ok
+	case (local == "double" || local == "float") && xsdDoubleLexical.MatchString(value):
+		bits := 64
+		if local == "float" {
+			bits = 32
+		}
+		f, err := strconv.ParseFloat(value, bits)
+		if err != nil && !errors.Is(err, strconv.ErrRange) || math.IsNaN(f) {
+			return nil, 0, false
+		}
+		if math.IsInf(f, 0) {
+			if f < 0 {
+				return nil, -1, true
+			}
+			return nil, 1, true
+		}
+		return new(big.Rat).SetFloat64(f), 0, true
+	}
+	return nil, 0, false
+}
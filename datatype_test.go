@@ -0,0 +1,82 @@
+package rdf2go
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testVersionType = "http://example.org/types#version"
+
+func versionComparator() LiteralComparator {
+	return LiteralComparator{
+		Equal: func(a, b string) bool {
+			return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+		},
+		Compare: func(a, b string) (int, error) {
+			pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+			pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+			for i := 0; i < len(pa) && i < len(pb); i++ {
+				na, err := strconv.Atoi(pa[i])
+				if err != nil {
+					return 0, err
+				}
+				nb, err := strconv.Atoi(pb[i])
+				if err != nil {
+					return 0, err
+				}
+				if na != nb {
+					return na - nb, nil
+				}
+			}
+			return len(pa) - len(pb), nil
+		},
+	}
+}
+
+func TestRegisterDatatypeEqual(t *testing.T) {
+	RegisterDatatype(testVersionType, versionComparator())
+	defer UnregisterDatatype(testVersionType)
+
+	dt := NewResource(testVersionType)
+	t1 := NewLiteralWithDatatype("v1.2", dt)
+	assert.True(t, t1.Equal(NewLiteralWithDatatype("1.2", dt)))
+	assert.False(t, t1.Equal(NewLiteralWithDatatype("1.3", dt)))
+	assert.False(t, t1.Equal(NewLiteral("1.2")))
+
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("version"), NewLiteralWithDatatype("1.2", dt))
+	assert.NotNil(t, g.One(nil, NewResource("version"), t1))
+}
+
+func TestUnregisterDatatype(t *testing.T) {
+	RegisterDatatype(testVersionType, versionComparator())
+	UnregisterDatatype(testVersionType)
+
+	dt := NewResource(testVersionType)
+	assert.False(t, NewLiteralWithDatatype("v1.2", dt).Equal(NewLiteralWithDatatype("1.2", dt)))
+}
+
+func TestCompareTerms(t *testing.T) {
+	RegisterDatatype(testVersionType, versionComparator())
+	defer UnregisterDatatype(testVersionType)
+
+	dt := NewResource(testVersionType)
+	c, err := CompareTerms(NewLiteralWithDatatype("1.10", dt), NewLiteralWithDatatype("1.9", dt))
+	assert.NoError(t, err)
+	assert.True(t, c > 0)
+
+	c, err = CompareTerms(NewLiteral("1.10"), NewLiteral("1.9"))
+	assert.NoError(t, err)
+	assert.True(t, c < 0)
+
+	_, err = CompareTerms(NewLiteralWithDatatype("1.x", dt), NewLiteralWithDatatype("1.9", dt))
+	assert.Error(t, err)
+
+	c, _ = CompareTerms(NewBlankNode("z"), NewResource("a"))
+	assert.Equal(t, -1, c)
+	c, _ = CompareTerms(NewLiteral("a"), NewResource("z"))
+	assert.Equal(t, 1, c)
+}
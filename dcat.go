@@ -0,0 +1,185 @@
+package rdf2go
+
+import (
+	"io"
+	"strconv"
+	"time"
+)
+
+// Catalog describes a dcat:Catalog, a collection of datasets. Its IRI, and
+// those of the other catalog descriptions, is a new blank node when empty.
+type Catalog struct {
+	IRI         string
+	Title       string
+	Description string
+	// Publisher and License are IRIs
+	Publisher string
+	License   string
+	Datasets  []*CatalogDataset
+}
+
+// CatalogDataset describes a dcat:Dataset, published as distributions
+type CatalogDataset struct {
+	IRI         string
+	Title       string
+	Description string
+	Keywords    []string
+	// Publisher and License are IRIs
+	Publisher     string
+	License       string
+	Issued        time.Time
+	Modified      time.Time
+	Distributions []*Distribution
+}
+
+// Distribution describes a dcat:Distribution, a serialization of a dataset
+type Distribution struct {
+	IRI   string
+	Title string
+	// License, AccessURL and DownloadURL are IRIs
+	License     string
+	AccessURL   string
+	DownloadURL string
+	// MediaType is a MIME type, such as text/turtle, described by its IANA
+	// IRI
+	MediaType string
+	// ByteSize is the size of the serialization, omitted when 0
+	ByteSize int64
+	Issued   time.Time
+	Modified time.Time
+}
+
+// ianaMediaTypes is the namespace of media types
+const ianaMediaTypes = "http://www.iana.org/assignments/media-types/"
+
+// catalogNode returns the node of an IRI, a new blank node when empty
+func catalogNode(iri string) Term {
+	if iri == "" {
+		return NewAnonNode()
+	}
+	return NewResource(iri)
+}
+
+// catalogDescription adds the statements describing a node, skipping empty
+// values
+type catalogDescription struct {
+	g    *Graph
+	node Term
+}
+
+func (c catalogDescription) literal(p Term, value string) {
+	if value != "" {
+		c.g.AddTriple(c.node, p, NewLiteral(value))
+	}
+}
+
+func (c catalogDescription) resource(p Term, iri string) {
+	if iri != "" {
+		c.g.AddTriple(c.node, p, NewResource(iri))
+	}
+}
+
+func (c catalogDescription) time(p Term, t time.Time) {
+	if !t.IsZero() {
+		c.g.AddTriple(c.node, p, NewLiteralWithDatatype(t.Format(time.RFC3339), XSD.Get("dateTime")))
+	}
+}
+
+// Describe adds the description of the catalog and of its datasets to a
+// graph, and returns the node of the catalog
+func (c *Catalog) Describe(g *Graph) Term {
+	desc := catalogDescription{g, catalogNode(c.IRI)}
+	g.AddTriple(desc.node, RDF.Get("type"), DCAT.Get("Catalog"))
+	desc.literal(DCTerms.Get("title"), c.Title)
+	desc.literal(DCTerms.Get("description"), c.Description)
+	desc.resource(DCTerms.Get("publisher"), c.Publisher)
+	desc.resource(DCTerms.Get("license"), c.License)
+	for _, dataset := range c.Datasets {
+		g.AddTriple(desc.node, DCAT.Get("dataset"), dataset.Describe(g))
+	}
+	return desc.node
+}
+
+// Describe adds the description of the dataset and of its distributions to
+// a graph, and returns the node of the dataset
+func (ds *CatalogDataset) Describe(g *Graph) Term {
+	desc := catalogDescription{g, catalogNode(ds.IRI)}
+	g.AddTriple(desc.node, RDF.Get("type"), DCAT.Get("Dataset"))
+	desc.literal(DCTerms.Get("title"), ds.Title)
+	desc.literal(DCTerms.Get("description"), ds.Description)
+	for _, keyword := range ds.Keywords {
+		desc.literal(DCAT.Get("keyword"), keyword)
+	}
+	desc.resource(DCTerms.Get("publisher"), ds.Publisher)
+	desc.resource(DCTerms.Get("license"), ds.License)
+	desc.time(DCTerms.Get("issued"), ds.Issued)
+	desc.time(DCTerms.Get("modified"), ds.Modified)
+	for _, distribution := range ds.Distributions {
+		g.AddTriple(desc.node, DCAT.Get("distribution"), distribution.Describe(g))
+	}
+	return desc.node
+}
+
+// Describe adds the description of the distribution to a graph, and returns
+// its node
+func (dist *Distribution) Describe(g *Graph) Term {
+	desc := catalogDescription{g, catalogNode(dist.IRI)}
+	g.AddTriple(desc.node, RDF.Get("type"), DCAT.Get("Distribution"))
+	desc.literal(DCTerms.Get("title"), dist.Title)
+	desc.resource(DCTerms.Get("license"), dist.License)
+	desc.resource(DCAT.Get("accessURL"), dist.AccessURL)
+	desc.resource(DCAT.Get("downloadURL"), dist.DownloadURL)
+	if dist.MediaType != "" {
+		desc.resource(DCAT.Get("mediaType"), ianaMediaTypes+dist.MediaType)
+	}
+	if dist.ByteSize > 0 {
+		g.AddTriple(desc.node, DCAT.Get("byteSize"), NewLiteralWithDatatype(strconv.FormatInt(dist.ByteSize, 10), XSD.Get("nonNegativeInteger")))
+	}
+	desc.time(DCTerms.Get("issued"), dist.Issued)
+	desc.time(DCTerms.Get("modified"), dist.Modified)
+	return desc.node
+}
+
+// byteCounter counts the bytes written through it
+type byteCounter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// SerializeDistribution serializes the graph as Serialize does, and returns
+// the description of the serialization, published at downloadURL: its media
+// type, byte size and time of issue
+func (g *Graph) SerializeDistribution(w io.Writer, mime string, downloadURL string) (*Distribution, error) {
+	counter := &byteCounter{w: w}
+	if err := g.Serialize(counter, mime); err != nil {
+		return nil, err
+	}
+	return newDistribution(mime, downloadURL, counter.n), nil
+}
+
+// SerializeDistribution serializes the dataset as Serialize does, and
+// returns the description of the serialization, published at downloadURL:
+// its media type, byte size and time of issue
+func (d *Dataset) SerializeDistribution(w io.Writer, mime string, downloadURL string) (*Distribution, error) {
+	counter := &byteCounter{w: w}
+	if err := d.Serialize(counter, mime); err != nil {
+		return nil, err
+	}
+	return newDistribution(mime, downloadURL, counter.n), nil
+}
+
+func newDistribution(mime string, downloadURL string, size int64) *Distribution {
+	return &Distribution{
+		AccessURL:   downloadURL,
+		DownloadURL: downloadURL,
+		MediaType:   mime,
+		ByteSize:    size,
+		Issued:      time.Now().UTC().Truncate(time.Second),
+	}
+}
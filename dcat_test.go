@@ -0,0 +1,64 @@
+package rdf2go
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogDescribe(t *testing.T) {
+	issued := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	catalog := &Catalog{
+		IRI:   "http://example.org/catalog",
+		Title: "Catalog",
+		Datasets: []*CatalogDataset{{
+			IRI:      "http://example.org/people",
+			Title:    "People",
+			Keywords: []string{"people", "social"},
+			License:  "https://creativecommons.org/licenses/by/4.0/",
+			Issued:   issued,
+			Distributions: []*Distribution{{
+				DownloadURL: "http://example.org/people.ttl",
+				MediaType:   "text/turtle",
+				ByteSize:    1024,
+			}},
+		}},
+	}
+	g := NewGraph(testUri)
+	node := catalog.Describe(g)
+	assert.Equal(t, NewResource("http://example.org/catalog"), node)
+
+	people := NewResource("http://example.org/people")
+	assert.NotNil(t, g.One(node, RDF.Get("type"), DCAT.Get("Catalog")))
+	assert.NotNil(t, g.One(node, DCAT.Get("dataset"), people))
+	assert.Len(t, g.All(people, DCAT.Get("keyword"), nil), 2)
+	assert.Equal(t, NewLiteralWithDatatype("2024-05-01T12:00:00Z", XSD.Get("dateTime")), g.One(people, DCTerms.Get("issued"), nil).Object)
+	assert.Nil(t, g.One(people, DCTerms.Get("description"), nil))
+
+	dist := g.One(people, DCAT.Get("distribution"), nil).Object
+	assert.IsType(t, &BlankNode{}, dist)
+	assert.Equal(t, NewResource("http://www.iana.org/assignments/media-types/text/turtle"), g.One(dist, DCAT.Get("mediaType"), nil).Object)
+	assert.Equal(t, NewLiteralWithDatatype("1024", XSD.Get("nonNegativeInteger")), g.One(dist, DCAT.Get("byteSize"), nil).Object)
+	assert.Nil(t, g.One(dist, DCAT.Get("accessURL"), nil))
+}
+
+func TestSerializeDistribution(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("1"))
+	var buf bytes.Buffer
+	dist, err := g.SerializeDistribution(&buf, "text/turtle", "http://example.org/a.ttl")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), dist.ByteSize)
+	assert.Equal(t, "text/turtle", dist.MediaType)
+	assert.Equal(t, "http://example.org/a.ttl", dist.DownloadURL)
+	assert.False(t, dist.Issued.IsZero())
+
+	d := NewDataset(testUri)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("1"), NewResource("http://example.org/g"))
+	buf.Reset()
+	dist, err = d.SerializeDistribution(&buf, "application/n-quads", "http://example.org/a.nq")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), dist.ByteSize)
+}
@@ -0,0 +1,103 @@
+package rdf2go
+
+// Description gives access to the properties of a resource of a graph,
+// without handling its statements, see Graph.Describe. It reads and writes
+// through to the graph, so it always reflects its current content.
+type Description struct {
+	graph   *Graph
+	subject Term
+}
+
+// Describe returns a description of a subject of the graph
+func (g *Graph) Describe(subject Term) *Description {
+	return &Description{graph: g, subject: subject}
+}
+
+// Subject returns the described subject
+func (r *Description) Subject() Term {
+	return r.subject
+}
+
+// Graph returns the graph holding the description
+func (r *Description) Graph() *Graph {
+	return r.graph
+}
+
+// Get returns an object of a property of the resource, or nil
+func (r *Description) Get(p Term) Term {
+	if t := r.graph.One(r.subject, p, nil); t != nil {
+		return t.Object
+	}
+	return nil
+}
+
+// Value returns the raw value of an object of a property of the resource,
+// such as the lexical form of a literal, or an empty string
+func (r *Description) Value(p Term) string {
+	if o := r.Get(p); o != nil {
+		return o.RawValue()
+	}
+	return ""
+}
+
+// Has tells whether the resource has a property
+func (r *Description) Has(p Term) bool {
+	return r.graph.One(r.subject, p, nil) != nil
+}
+
+// Objects returns the objects of a property of the resource
+func (r *Description) Objects(p Term) []Term {
+	var objects []Term
+	r.graph.match(r.subject, p, nil, func(triple *Triple) bool {
+		objects = append(objects, triple.Object)
+		return true
+	})
+	return objects
+}
+
+// Subjects returns the subjects linking to the resource with a predicate,
+// those of which it is the object
+func (r *Description) Subjects(p Term) []Term {
+	var subjects []Term
+	r.graph.match(nil, p, r.subject, func(triple *Triple) bool {
+		subjects = append(subjects, triple.Subject)
+		return true
+	})
+	return subjects
+}
+
+// Types returns the classes of the resource, the objects of its rdf:type
+// property
+func (r *Description) Types() []Term {
+	return r.Objects(rdfType)
+}
+
+// Set replaces the objects of a property of the resource, removing the
+// property when no object is given
+func (r *Description) Set(p Term, objects ...Term) *Description {
+	r.graph.RemoveMatching(r.subject, p, nil)
+	return r.Add(p, objects...)
+}
+
+// Add adds objects to a property of the resource, skipping those it
+// already has
+func (r *Description) Add(p Term, objects ...Term) *Description {
+	for _, o := range objects {
+		if r.graph.One(r.subject, p, o) == nil {
+			r.graph.AddTriple(r.subject, p, o)
+		}
+	}
+	return r
+}
+
+// Remove removes objects from a property of the resource, or the whole
+// property when no object is given
+func (r *Description) Remove(p Term, objects ...Term) *Description {
+	if len(objects) == 0 {
+		r.graph.RemoveMatching(r.subject, p, nil)
+	}
+	for _, o := range objects {
+		r.graph.RemoveMatching(r.subject, p, o)
+	}
+	return r
+}
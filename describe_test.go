@@ -0,0 +1,41 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	foaf := NewNamespace(testFOAF)
+	g := NewGraph(testUri)
+	alice := NewResource("https://alice.example/#me")
+	bob := NewResource("https://bob.example/#me")
+	g.AddTriple(bob, foaf.Get("knows"), alice)
+
+	r := g.Describe(alice)
+	assert.Equal(t, alice, r.Subject())
+	assert.Same(t, g, r.Graph())
+	assert.Nil(t, r.Get(foaf.Get("name")))
+	assert.Equal(t, "", r.Value(foaf.Get("name")))
+
+	r.Set(foaf.Get("name"), NewLiteral("Alice")).
+		Add(rdfType, foaf.Get("Person"), foaf.Get("Agent"), foaf.Get("Person")).
+		Add(foaf.Get("nick"), NewLiteral("al"), NewLiteral("ali"))
+	assert.Equal(t, 6, g.Len())
+	assert.Equal(t, NewLiteral("Alice"), r.Get(foaf.Get("name")))
+	assert.Equal(t, "Alice", r.Value(foaf.Get("name")))
+	assert.True(t, r.Has(foaf.Get("nick")))
+	assert.ElementsMatch(t, []Term{foaf.Get("Person"), foaf.Get("Agent")}, r.Types())
+	assert.Equal(t, []Term{bob}, r.Subjects(foaf.Get("knows")))
+
+	// Set replaces the objects, Remove drops some or all of them
+	r.Set(foaf.Get("name"), NewLiteral("Alice A."))
+	assert.Equal(t, []Term{NewLiteral("Alice A.")}, r.Objects(foaf.Get("name")))
+	r.Remove(foaf.Get("nick"), NewLiteral("al"))
+	assert.Equal(t, []Term{NewLiteral("ali")}, r.Objects(foaf.Get("nick")))
+	r.Remove(rdfType).Set(foaf.Get("nick"))
+	assert.Empty(t, r.Types())
+	assert.False(t, r.Has(foaf.Get("nick")))
+	assert.Equal(t, 2, g.Len())
+}
@@ -0,0 +1,412 @@
+// Package did reads and writes the DID documents of decentralized
+// identifiers (https://www.w3.org/TR/did-core/) and converts them to and
+// from rdf2go graphs.
+//
+// Documents are mapped to RDF after the terms of the DID v1 context, which
+// keeps the conversion independent of the processing of remote JSON-LD
+// contexts. The types of verification methods, such as
+// Ed25519VerificationKey2020, are expanded in the security vocabulary, and
+// the types of services in the DID vocabulary, unless they are IRIs.
+package did
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/deiu/rdf2go"
+)
+
+// Vocabularies and context of DID documents
+const (
+	// ContextV1 is the JSON-LD context of DID documents
+	ContextV1 = "https://www.w3.org/ns/did/v1"
+	// Namespace is the namespace of the DID vocabulary
+	Namespace = "https://www.w3.org/ns/did#"
+	// SecurityNamespace is the namespace of the security vocabulary, which
+	// describes verification methods
+	SecurityNamespace = "https://w3id.org/security#"
+)
+
+const (
+	rdfType         = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	rdfJSON         = "http://www.w3.org/1999/02/22-rdf-syntax-ns#JSON"
+	asAlsoKnownAs   = "https://www.w3.org/ns/activitystreams#alsoKnownAs"
+	secController   = SecurityNamespace + "controller"
+	secMethod       = SecurityNamespace + "verificationMethod"
+	secMultibase    = SecurityNamespace + "multibase"
+	secKeyMultibase = SecurityNamespace + "publicKeyMultibase"
+	secKeyJwk       = SecurityNamespace + "publicKeyJwk"
+	didService      = Namespace + "service"
+	didEndpoint     = Namespace + "serviceEndpoint"
+
+	secAuthentication       = SecurityNamespace + "authenticationMethod"
+	secAssertion            = SecurityNamespace + "assertionMethod"
+	secKeyAgreement         = SecurityNamespace + "keyAgreementMethod"
+	secCapabilityInvocation = SecurityNamespace + "capabilityInvocationMethod"
+	secCapabilityDelegation = SecurityNamespace + "capabilityDelegationMethod"
+)
+
+// Document is a DID document. The entries of its verification
+// relationships, such as Authentication, are either methods embedded in the
+// relationship, or references to methods holding only their ID.
+type Document struct {
+	// Context is the JSON-LD context of the document, ContextV1 when nil
+	Context []interface{}
+	// ID is the DID the document describes
+	ID                   string
+	Controller           []string
+	AlsoKnownAs          []string
+	VerificationMethod   []VerificationMethod
+	Authentication       []VerificationMethod
+	AssertionMethod      []VerificationMethod
+	KeyAgreement         []VerificationMethod
+	CapabilityInvocation []VerificationMethod
+	CapabilityDelegation []VerificationMethod
+	Service              []Service
+}
+
+// VerificationMethod is a public key or another means of verifying proofs
+// made by the subject of a DID
+type VerificationMethod struct {
+	ID                 string
+	Type               string
+	Controller         string
+	PublicKeyMultibase string
+	PublicKeyJwk       map[string]interface{}
+}
+
+// Service is a service endpoint of the subject of a DID. The entries of
+// ServiceEndpoint are either URLs or JSON objects.
+type Service struct {
+	ID              string
+	Type            []string
+	ServiceEndpoint []interface{}
+}
+
+// Parse reads a DID document from its JSON-LD representation
+func Parse(data []byte) (*Document, error) {
+	d := &Document{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	if len(d.ID) == 0 {
+		return nil, errors.New("the DID document has no id")
+	}
+	return d, nil
+}
+
+// IsReference returns whether the method only references a method by its ID
+func (m VerificationMethod) IsReference() bool {
+	return len(m.Type) == 0 && len(m.Controller) == 0 && len(m.PublicKeyMultibase) == 0 && m.PublicKeyJwk == nil
+}
+
+// URLs returns the URLs of the endpoints of the service, including the uri
+// of the endpoints given as objects
+func (s Service) URLs() []string {
+	var urls []string
+	for _, e := range s.ServiceEndpoint {
+		switch e := e.(type) {
+		case string:
+			urls = append(urls, e)
+		case map[string]interface{}:
+			if uri, ok := e["uri"].(string); ok {
+				urls = append(urls, uri)
+			}
+		}
+	}
+	return urls
+}
+
+// resolve returns the absolute form of a DID URL relative to the DID of the
+// document, such as #key-1
+func (d *Document) resolve(ref string) string {
+	if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "?") || strings.HasPrefix(ref, "/") {
+		return d.ID + ref
+	}
+	return ref
+}
+
+// relationship is a verification relationship of a document, along with
+// its predicate
+type relationship struct {
+	predicate string
+	methods   *[]VerificationMethod
+}
+
+func (d *Document) relationships() []relationship {
+	return []relationship{
+		{secAuthentication, &d.Authentication},
+		{secAssertion, &d.AssertionMethod},
+		{secKeyAgreement, &d.KeyAgreement},
+		{secCapabilityInvocation, &d.CapabilityInvocation},
+		{secCapabilityDelegation, &d.CapabilityDelegation},
+	}
+}
+
+// Method returns the verification method with the given ID, which may be
+// relative to the DID, searching the methods embedded in verification
+// relationships as well
+func (d *Document) Method(id string) (VerificationMethod, bool) {
+	id = d.resolve(id)
+	lists := [][]VerificationMethod{d.VerificationMethod}
+	for _, r := range d.relationships() {
+		lists = append(lists, *r.methods)
+	}
+	for _, list := range lists {
+		for _, m := range list {
+			if !m.IsReference() && d.resolve(m.ID) == id {
+				return m, true
+			}
+		}
+	}
+	return VerificationMethod{}, false
+}
+
+// Methods returns the methods of a verification relationship, such as
+// d.Authentication, with their references replaced by the methods they
+// reference. Dangling references are left out.
+func (d *Document) Methods(relationship []VerificationMethod) []VerificationMethod {
+	var methods []VerificationMethod
+	for _, m := range relationship {
+		if m.IsReference() {
+			var ok bool
+			if m, ok = d.Method(m.ID); !ok {
+				continue
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// Services returns the services of the given type, or all of them for an
+// empty type
+func (d *Document) Services(typ string) []Service {
+	var services []Service
+	for _, s := range d.Service {
+		if len(typ) == 0 || slices.Contains(s.Type, typ) {
+			services = append(services, s)
+		}
+	}
+	return services
+}
+
+// Graph returns the RDF statements of the document, in a graph named by its
+// DID
+func (d *Document) Graph() *rdf2go.Graph {
+	g := rdf2go.NewGraph(d.ID)
+	w := &graphWriter{d: d, g: g}
+	subject := rdf2go.NewResource(d.ID)
+	for _, c := range d.Controller {
+		g.AddTriple(subject, rdf2go.NewResource(secController), rdf2go.NewResource(d.resolve(c)))
+	}
+	for _, aka := range d.AlsoKnownAs {
+		g.AddTriple(subject, rdf2go.NewResource(asAlsoKnownAs), rdf2go.NewResource(aka))
+	}
+	for _, m := range d.VerificationMethod {
+		g.AddTriple(subject, rdf2go.NewResource(secMethod), w.method(m))
+	}
+	for _, r := range d.relationships() {
+		for _, m := range *r.methods {
+			g.AddTriple(subject, rdf2go.NewResource(r.predicate), w.method(m))
+		}
+	}
+	for _, s := range d.Service {
+		g.AddTriple(subject, rdf2go.NewResource(didService), w.service(s))
+	}
+	return g
+}
+
+// graphWriter adds the statements of the parts of a document to a graph
+type graphWriter struct {
+	d      *Document
+	g      *rdf2go.Graph
+	blanks int
+}
+
+// node returns the term of a part of the document, a blank node when it
+// has no ID
+func (w *graphWriter) node(id string) rdf2go.Term {
+	if len(id) == 0 {
+		w.blanks++
+		return rdf2go.NewBlankNode(fmt.Sprintf("b%d", w.blanks))
+	}
+	return rdf2go.NewResource(w.d.resolve(id))
+}
+
+func (w *graphWriter) method(m VerificationMethod) rdf2go.Term {
+	node := w.node(m.ID)
+	if m.IsReference() {
+		return node
+	}
+	if len(m.Type) > 0 {
+		w.g.AddTriple(node, rdf2go.NewResource(rdfType), rdf2go.NewResource(expandType(m.Type, SecurityNamespace)))
+	}
+	if len(m.Controller) > 0 {
+		w.g.AddTriple(node, rdf2go.NewResource(secController), rdf2go.NewResource(w.d.resolve(m.Controller)))
+	}
+	if len(m.PublicKeyMultibase) > 0 {
+		w.g.AddTriple(node, rdf2go.NewResource(secKeyMultibase), rdf2go.NewLiteralWithDatatype(m.PublicKeyMultibase, rdf2go.NewResource(secMultibase)))
+	}
+	if m.PublicKeyJwk != nil {
+		w.g.AddTriple(node, rdf2go.NewResource(secKeyJwk), jsonLiteral(m.PublicKeyJwk))
+	}
+	return node
+}
+
+func (w *graphWriter) service(s Service) rdf2go.Term {
+	node := w.node(s.ID)
+	for _, t := range s.Type {
+		w.g.AddTriple(node, rdf2go.NewResource(rdfType), rdf2go.NewResource(expandType(t, Namespace)))
+	}
+	for _, e := range s.ServiceEndpoint {
+		if url, ok := e.(string); ok {
+			w.g.AddTriple(node, rdf2go.NewResource(didEndpoint), rdf2go.NewResource(url))
+		} else {
+			w.g.AddTriple(node, rdf2go.NewResource(didEndpoint), jsonLiteral(e))
+		}
+	}
+	return node
+}
+
+// jsonLiteral returns a JSON value as an rdf:JSON literal
+func jsonLiteral(value interface{}) rdf2go.Term {
+	// the keys of maps are sorted by encoding/json
+	data, _ := json.Marshal(value)
+	return rdf2go.NewLiteralWithDatatype(string(data), rdf2go.NewResource(rdfJSON))
+}
+
+// expandType returns the IRI of a type, expanding the terms of a vocabulary
+func expandType(typ string, ns string) string {
+	if strings.Contains(typ, ":") {
+		return typ
+	}
+	return ns + typ
+}
+
+// compactType returns the term of a type of a vocabulary, see expandType
+func compactType(iri string, ns string) string {
+	if term, ok := strings.CutPrefix(iri, ns); ok && !strings.ContainsAny(term, "/#:") {
+		return term
+	}
+	return iri
+}
+
+// FromGraph reads the DID document of a DID from the statements of a graph,
+// such as one returned by Document.Graph. The methods described by the graph
+// are embedded in the verification relationships, unless they are listed as
+// verification methods of the document.
+func FromGraph(g *rdf2go.Graph, id string) (*Document, error) {
+	subject := rdf2go.NewResource(id)
+	if g.One(subject, nil, nil) == nil {
+		return nil, fmt.Errorf("the graph does not describe %s", id)
+	}
+	d := &Document{Context: []interface{}{ContextV1}, ID: id}
+	r := &graphReader{d: d, g: g}
+	d.Controller = r.values(subject, secController)
+	d.AlsoKnownAs = r.values(subject, asAlsoKnownAs)
+	listed := make(map[string]bool)
+	for _, node := range r.objects(subject, secMethod) {
+		d.VerificationMethod = append(d.VerificationMethod, r.method(node))
+		listed[node.String()] = true
+	}
+	for _, rel := range d.relationships() {
+		for _, node := range r.objects(subject, rel.predicate) {
+			if listed[node.String()] {
+				*rel.methods = append(*rel.methods, VerificationMethod{ID: node.RawValue()})
+			} else {
+				*rel.methods = append(*rel.methods, r.method(node))
+			}
+		}
+	}
+	for _, node := range r.objects(subject, didService) {
+		s, err := r.service(node)
+		if err != nil {
+			return nil, err
+		}
+		d.Service = append(d.Service, s)
+	}
+	return d, nil
+}
+
+// graphReader reads the parts of a document from a graph
+type graphReader struct {
+	d *Document
+	g *rdf2go.Graph
+}
+
+// objects returns the objects of a subject and predicate, sorted for the
+// parts of the document to come in a stable order
+func (r *graphReader) objects(subject rdf2go.Term, predicate string) []rdf2go.Term {
+	var objects []rdf2go.Term
+	for _, t := range r.g.All(subject, rdf2go.NewResource(predicate), nil) {
+		objects = append(objects, t.Object)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].String() < objects[j].String()
+	})
+	return objects
+}
+
+// values returns the raw values of the objects of a subject and predicate
+func (r *graphReader) values(subject rdf2go.Term, predicate string) []string {
+	var values []string
+	for _, o := range r.objects(subject, predicate) {
+		values = append(values, o.RawValue())
+	}
+	return values
+}
+
+// value returns the raw value of an object of a subject and predicate
+func (r *graphReader) value(subject rdf2go.Term, predicate string) string {
+	if values := r.values(subject, predicate); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// id returns the ID of a part of the document, empty for blank nodes
+func (r *graphReader) id(node rdf2go.Term) string {
+	if _, ok := node.(*rdf2go.Resource); ok {
+		return node.RawValue()
+	}
+	return ""
+}
+
+func (r *graphReader) method(node rdf2go.Term) VerificationMethod {
+	m := VerificationMethod{
+		ID:                 r.id(node),
+		Controller:         r.value(node, secController),
+		PublicKeyMultibase: r.value(node, secKeyMultibase),
+	}
+	if typ := r.value(node, rdfType); len(typ) > 0 {
+		m.Type = compactType(typ, SecurityNamespace)
+	}
+	if jwk := r.value(node, secKeyJwk); len(jwk) > 0 {
+		json.Unmarshal([]byte(jwk), &m.PublicKeyJwk)
+	}
+	return m
+}
+
+func (r *graphReader) service(node rdf2go.Term) (Service, error) {
+	s := Service{ID: r.id(node)}
+	for _, typ := range r.values(node, rdfType) {
+		s.Type = append(s.Type, compactType(typ, Namespace))
+	}
+	for _, e := range r.objects(node, didEndpoint) {
+		if l, ok := e.(*rdf2go.Literal); ok {
+			var value interface{}
+			if err := json.Unmarshal([]byte(l.Value), &value); err != nil {
+				return s, fmt.Errorf("invalid endpoint of the service %s: %s", s.ID, err)
+			}
+			s.ServiceEndpoint = append(s.ServiceEndpoint, value)
+		} else {
+			s.ServiceEndpoint = append(s.ServiceEndpoint, e.RawValue())
+		}
+	}
+	return s, nil
+}
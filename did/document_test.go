@@ -0,0 +1,113 @@
+package did
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/deiu/rdf2go"
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleDocument = `{
+  "@context": ["https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/ed25519-2020/v1"],
+  "id": "did:example:123",
+  "controller": "did:example:ctrl",
+  "verificationMethod": [{
+    "id": "did:example:123#key-1",
+    "type": "Ed25519VerificationKey2020",
+    "controller": "did:example:123",
+    "publicKeyMultibase": "z6MkmM42vxfqZQsv4ehtTjFFxQ4sQKS2w6WR7emozFAn5cxu"
+  }],
+  "authentication": [
+    "#key-1",
+    {
+      "id": "did:example:123#key-2",
+      "type": "JsonWebKey2020",
+      "controller": "did:example:123",
+      "publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "VCpo2LMLhn6iWku8MKvSLg2ZAoC-nlOyPVQaO3FxVeQ"}
+    }
+  ],
+  "assertionMethod": ["did:example:123#key-1"],
+  "service": [{
+    "id": "did:example:123#linked-domain",
+    "type": "LinkedDomains",
+    "serviceEndpoint": "https://example.com"
+  }, {
+    "id": "did:example:123#didcomm",
+    "type": "DIDCommMessaging",
+    "serviceEndpoint": {"uri": "https://example.com/didcomm", "accept": ["didcomm/v2"]}
+  }]
+}`
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte(exampleDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, "did:example:123", d.ID)
+	assert.Equal(t, []string{"did:example:ctrl"}, d.Controller)
+	assert.Len(t, d.Context, 2)
+
+	assert.Len(t, d.Authentication, 2)
+	assert.True(t, d.Authentication[0].IsReference())
+	methods := d.Methods(d.Authentication)
+	if assert.Len(t, methods, 2) {
+		assert.Equal(t, "Ed25519VerificationKey2020", methods[0].Type)
+		assert.Equal(t, "OKP", methods[1].PublicKeyJwk["kty"])
+	}
+	m, ok := d.Method("#key-2")
+	assert.True(t, ok)
+	assert.Equal(t, "JsonWebKey2020", m.Type)
+	_, ok = d.Method("#key-3")
+	assert.False(t, ok)
+
+	services := d.Services("DIDCommMessaging")
+	if assert.Len(t, services, 1) {
+		assert.Equal(t, []string{"https://example.com/didcomm"}, services[0].URLs())
+	}
+	assert.Len(t, d.Services(""), 2)
+
+	_, err = Parse([]byte(`{"controller": "did:example:ctrl"}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"id": "did:example:123", "controller": 1}`))
+	assert.Error(t, err)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	d, err := Parse([]byte(exampleDocument))
+	assert.NoError(t, err)
+	data, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.JSONEq(t, exampleDocument, string(data))
+
+	// documents get the DID context by default
+	data, err = json.Marshal(Document{ID: "did:example:456"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"@context": "https://www.w3.org/ns/did/v1", "id": "did:example:456"}`, string(data))
+}
+
+func TestGraph(t *testing.T) {
+	d, err := Parse([]byte(exampleDocument))
+	assert.NoError(t, err)
+	g := d.Graph()
+	assert.Equal(t, "did:example:123", g.URI())
+	subject := rdf2go.NewResource("did:example:123")
+	key1 := rdf2go.NewResource("did:example:123#key-1")
+	assert.NotNil(t, g.One(subject, rdf2go.NewResource(secAuthentication), key1))
+	assert.NotNil(t, g.One(key1, rdf2go.NewResource(rdfType), rdf2go.NewResource(SecurityNamespace+"Ed25519VerificationKey2020")))
+	assert.NotNil(t, g.One(rdf2go.NewResource("did:example:123#linked-domain"), rdf2go.NewResource(didEndpoint), rdf2go.NewResource("https://example.com")))
+	jwk := g.One(rdf2go.NewResource("did:example:123#key-2"), rdf2go.NewResource(secKeyJwk), nil)
+	if assert.NotNil(t, jwk) {
+		assert.Equal(t, rdf2go.NewResource(rdfJSON), jwk.Object.(*rdf2go.Literal).Datatype)
+	}
+
+	back, err := FromGraph(g, "did:example:123")
+	assert.NoError(t, err)
+	assert.Equal(t, d.Controller, back.Controller)
+	assert.Equal(t, d.VerificationMethod, back.VerificationMethod)
+	// the references are absolute, and the embedded method stays embedded
+	assert.Equal(t, []VerificationMethod{{ID: "did:example:123#key-1"}, d.Authentication[1]}, back.Authentication)
+	assert.Equal(t, d.AssertionMethod, back.AssertionMethod)
+	assert.ElementsMatch(t, d.Service, back.Service)
+
+	_, err = FromGraph(g, "did:example:456")
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// document is the JSON representation of a Document
+type document struct {
+	Context              json.RawMessage      `json:"@context,omitempty"`
+	ID                   string               `json:"id"`
+	Controller           json.RawMessage      `json:"controller,omitempty"`
+	AlsoKnownAs          []string             `json:"alsoKnownAs,omitempty"`
+	VerificationMethod   []VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication       []VerificationMethod `json:"authentication,omitempty"`
+	AssertionMethod      []VerificationMethod `json:"assertionMethod,omitempty"`
+	KeyAgreement         []VerificationMethod `json:"keyAgreement,omitempty"`
+	CapabilityInvocation []VerificationMethod `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []VerificationMethod `json:"capabilityDelegation,omitempty"`
+	Service              []Service            `json:"service,omitempty"`
+}
+
+// MarshalJSON writes the JSON-LD representation of the document
+func (d Document) MarshalJSON() ([]byte, error) {
+	context := d.Context
+	if context == nil {
+		context = []interface{}{ContextV1}
+	}
+	doc := document{
+		ID:                   d.ID,
+		AlsoKnownAs:          d.AlsoKnownAs,
+		VerificationMethod:   d.VerificationMethod,
+		Authentication:       d.Authentication,
+		AssertionMethod:      d.AssertionMethod,
+		KeyAgreement:         d.KeyAgreement,
+		CapabilityInvocation: d.CapabilityInvocation,
+		CapabilityDelegation: d.CapabilityDelegation,
+		Service:              d.Service,
+	}
+	var err error
+	if doc.Context, err = marshalSet(context); err != nil {
+		return nil, err
+	}
+	if doc.Controller, err = marshalSet(d.Controller); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON reads the JSON-LD representation of a document
+func (d *Document) UnmarshalJSON(data []byte) error {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*d = Document{
+		ID:                   doc.ID,
+		AlsoKnownAs:          doc.AlsoKnownAs,
+		VerificationMethod:   doc.VerificationMethod,
+		Authentication:       doc.Authentication,
+		AssertionMethod:      doc.AssertionMethod,
+		KeyAgreement:         doc.KeyAgreement,
+		CapabilityInvocation: doc.CapabilityInvocation,
+		CapabilityDelegation: doc.CapabilityDelegation,
+		Service:              doc.Service,
+	}
+	if err := unmarshalSet(doc.Context, &d.Context); err != nil {
+		return fmt.Errorf("invalid @context: %s", err)
+	}
+	if err := unmarshalSet(doc.Controller, &d.Controller); err != nil {
+		return fmt.Errorf("invalid controller: %s", err)
+	}
+	return nil
+}
+
+// verificationMethod is the JSON representation of an embedded
+// VerificationMethod
+type verificationMethod struct {
+	ID                 string                 `json:"id"`
+	Type               string                 `json:"type,omitempty"`
+	Controller         string                 `json:"controller,omitempty"`
+	PublicKeyMultibase string                 `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk       map[string]interface{} `json:"publicKeyJwk,omitempty"`
+}
+
+// MarshalJSON writes references to methods as strings, and embedded
+// methods as objects
+func (m VerificationMethod) MarshalJSON() ([]byte, error) {
+	if m.IsReference() {
+		return json.Marshal(m.ID)
+	}
+	return json.Marshal(verificationMethod(m))
+}
+
+// UnmarshalJSON reads a reference to a method or an embedded method
+func (m *VerificationMethod) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*m = VerificationMethod{ID: id}
+		return nil
+	}
+	var method verificationMethod
+	if err := json.Unmarshal(data, &method); err != nil {
+		return err
+	}
+	*m = VerificationMethod(method)
+	return nil
+}
+
+// service is the JSON representation of a Service
+type service struct {
+	ID              string          `json:"id,omitempty"`
+	Type            json.RawMessage `json:"type,omitempty"`
+	ServiceEndpoint json.RawMessage `json:"serviceEndpoint,omitempty"`
+}
+
+// MarshalJSON writes the service, with its type and endpoint as single
+// values when there is one of them
+func (s Service) MarshalJSON() ([]byte, error) {
+	out := service{ID: s.ID}
+	var err error
+	if out.Type, err = marshalSet(s.Type); err != nil {
+		return nil, err
+	}
+	if out.ServiceEndpoint, err = marshalSet(s.ServiceEndpoint); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads a service
+func (s *Service) UnmarshalJSON(data []byte) error {
+	var in service
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = Service{ID: in.ID}
+	if err := unmarshalSet(in.Type, &s.Type); err != nil {
+		return fmt.Errorf("invalid type of the service %s: %s", in.ID, err)
+	}
+	if err := unmarshalSet(in.ServiceEndpoint, &s.ServiceEndpoint); err != nil {
+		return fmt.Errorf("invalid endpoint of the service %s: %s", in.ID, err)
+	}
+	return nil
+}
+
+// marshalSet writes a list as a single value when it has a single element,
+// as DID documents do for the properties holding one or more values
+func marshalSet[T any](list []T) (json.RawMessage, error) {
+	switch len(list) {
+	case 0:
+		return nil, nil
+	case 1:
+		return json.Marshal(list[0])
+	}
+	return json.Marshal(list)
+}
+
+// unmarshalSet reads a single value or a list of values into a list
+func unmarshalSet[T any](data json.RawMessage, list *[]T) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] == '[' {
+		return json.Unmarshal(data, list)
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*list = []T{value}
+	return nil
+}
@@ -0,0 +1,104 @@
+package did
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxDocumentSize bounds the size of the DID documents fetched by WebResolver
+const maxDocumentSize = 1 << 20
+
+// WebResolver resolves the DIDs of the did:web method, fetching their
+// documents over HTTPS. It implements rdf2go.Resolver, so that registering
+// it for the did scheme with rdf2go.WithResolver makes LoadURI read DID
+// documents into graphs.
+type WebResolver struct {
+	// HTTPClient fetches documents, http.DefaultClient being used when nil
+	HTTPClient *http.Client
+}
+
+// WebURL returns the URL of the DID document of a did:web DID, such as
+// https://example.com/user/alice/did.json for did:web:example.com:user:alice
+func WebURL(did string) (string, error) {
+	id, ok := strings.CutPrefix(did, "did:web:")
+	if !ok || len(id) == 0 {
+		return "", fmt.Errorf("%s is not a did:web DID", did)
+	}
+	parts := strings.Split(id, ":")
+	// the port of the host is percent-encoded
+	host, err := url.PathUnescape(parts[0])
+	if err != nil || strings.ContainsAny(host, "/?#") {
+		return "", fmt.Errorf("invalid host in %s", did)
+	}
+	path := "/.well-known"
+	if len(parts) > 1 {
+		path = ""
+		for _, part := range parts[1:] {
+			segment, err := url.PathUnescape(part)
+			if err != nil || len(segment) == 0 {
+				return "", fmt.Errorf("invalid path in %s", did)
+			}
+			path += "/" + url.PathEscape(segment)
+		}
+	}
+	return "https://" + host + path + "/did.json", nil
+}
+
+// Document fetches the DID document of a did:web DID
+func (r *WebResolver) Document(ctx context.Context, did string) (*Document, error) {
+	target, err := WebURL(did)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+ld+json,application/did+json;q=0.9,application/json;q=0.8")
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Could not fetch the DID document of %s from %s - HTTP %d", did, target, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDocumentSize {
+		return nil, fmt.Errorf("the DID document of %s exceeds %d bytes", did, maxDocumentSize)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DID document %s: %s", target, err)
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("the DID document fetched from %s describes %s instead of %s", target, doc.ID, did)
+	}
+	return doc, nil
+}
+
+// Resolve implements rdf2go.Resolver, returning the statements of the DID
+// document as N-Triples
+func (r *WebResolver) Resolve(ctx context.Context, iri string) (io.ReadCloser, string, error) {
+	doc, err := r.Document(ctx, iri)
+	if err != nil {
+		return nil, "", err
+	}
+	buf := new(bytes.Buffer)
+	if err := doc.Graph().Serialize(buf, "application/n-triples"); err != nil {
+		return nil, "", err
+	}
+	return io.NopCloser(buf), "application/n-triples", nil
+}
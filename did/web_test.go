@@ -0,0 +1,59 @@
+package did
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/deiu/rdf2go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebURL(t *testing.T) {
+	u, err := WebURL("did:web:w3c-ccg.github.io")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://w3c-ccg.github.io/.well-known/did.json", u)
+	u, err = WebURL("did:web:w3c-ccg.github.io:user:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://w3c-ccg.github.io/user/alice/did.json", u)
+	u, err = WebURL("did:web:example.com%3A3000")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com:3000/.well-known/did.json", u)
+
+	for _, did := range []string{"did:key:z6Mk", "did:web:", "did:web:example.com::a", "did:web:example.com%2Fa"} {
+		_, err := WebURL(did)
+		assert.Error(t, err, did)
+	}
+}
+
+func TestWebResolver(t *testing.T) {
+	var did string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/alice/did.json" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		w.Write([]byte(strings.ReplaceAll(exampleDocument, "did:example:123", did)))
+	}))
+	defer server.Close()
+	// the port of the host is percent-encoded
+	host := strings.ReplaceAll(strings.TrimPrefix(server.URL, "https://"), ":", "%3A")
+	did = "did:web:" + host + ":user:alice"
+	resolver := &WebResolver{HTTPClient: server.Client()}
+
+	doc, err := resolver.Document(context.Background(), did)
+	assert.NoError(t, err)
+	assert.Equal(t, did, doc.ID)
+	_, err = resolver.Document(context.Background(), "did:web:"+host)
+	assert.Error(t, err)
+
+	g := rdf2go.NewGraphWithOptions("", rdf2go.WithResolver("did", resolver))
+	assert.NoError(t, g.LoadURI(did+"#key-1"))
+	assert.NotNil(t, g.One(rdf2go.NewResource(did), rdf2go.NewResource(secMethod), rdf2go.NewResource(did+"#key-1")))
+	back, err := FromGraph(g, did)
+	assert.NoError(t, err)
+	assert.Len(t, back.Service, 2)
+}
@@ -0,0 +1,150 @@
+package rdf2go
+
+import (
+	"sort"
+)
+
+// Diff returns the changes turning a into b, which may be graphs or
+// datasets. Statements without blank nodes are compared directly. Statements
+// with blank nodes are grouped into components of blank nodes connected to
+// each other, and a component only counts as changed when no isomorphic
+// component exists on the other side, so that relabeled blank nodes are not
+// reported. Added statements use the blank node labels of b. Changes are
+// sorted in N-Quads order.
+func Diff(a Model, b Model) (*ChangeSet, error) {
+	added, removed, err := diffQuads(modelQuads(a), modelQuads(b))
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeSet{Added: added, Removed: removed}, nil
+}
+
+// DiffGraphs returns the triples added and removed between graphs a and b,
+// comparing blank nodes as Diff does
+func DiffGraphs(a *Graph, b *Graph) (added []*Triple, removed []*Triple, err error) {
+	c, err := Diff(a, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, q := range c.Added {
+		added = append(added, q.ToTriple())
+	}
+	for _, q := range c.Removed {
+		removed = append(removed, q.ToTriple())
+	}
+	return added, removed, nil
+}
+
+// diffSide holds the statements of one side of a diff
+type diffSide struct {
+	// ground statements, by N-Quads line
+	ground map[string]*Quad
+	// blank node components, by canonical form
+	components map[string][][]*Quad
+}
+
+func newDiffSide(quads []*Quad) (*diffSide, error) {
+	side := &diffSide{ground: make(map[string]*Quad), components: make(map[string][][]*Quad)}
+
+	// union-find over blank node labels
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if p, ok := parent[id]; ok && p != id {
+			root := find(p)
+			parent[id] = root
+			return root
+		}
+		parent[id] = id
+		return id
+	}
+	var withBlanks []*Quad
+	for _, q := range quads {
+		var ids []string
+		for _, t := range []Term{q.Subject, q.Object, q.Graph} {
+			ids = blankNodeIDs(ids, t)
+		}
+		if len(ids) == 0 {
+			side.ground[canonicalNQuad(q, nil)] = q
+			continue
+		}
+		withBlanks = append(withBlanks, q)
+		for _, id := range ids[1:] {
+			parent[find(id)] = find(ids[0])
+		}
+	}
+
+	groups := make(map[string][]*Quad)
+	var roots []string
+	for _, q := range withBlanks {
+		var ids []string
+		for _, t := range []Term{q.Subject, q.Object, q.Graph} {
+			ids = blankNodeIDs(ids, t)
+		}
+		root := find(ids[0])
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], q)
+	}
+	for _, root := range roots {
+		component := NewDataset("")
+		for _, q := range groups[root] {
+			component.Add(q)
+		}
+		labels, err := component.canonicalLabels()
+		if err != nil {
+			return nil, err
+		}
+		key := canonicalDocument(component, labels)
+		side.components[key] = append(side.components[key], groups[root])
+	}
+	return side, nil
+}
+
+func diffQuads(a []*Quad, b []*Quad) (added []*Quad, removed []*Quad, err error) {
+	before, err := newDiffSide(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := newDiffSide(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, q := range after.ground {
+		if _, ok := before.ground[key]; !ok {
+			added = append(added, q)
+		}
+	}
+	for key, q := range before.ground {
+		if _, ok := after.ground[key]; !ok {
+			removed = append(removed, q)
+		}
+	}
+	// components present more often on one side than on the other
+	for key, components := range after.components {
+		for _, c := range components[min(len(components), len(before.components[key])):] {
+			added = append(added, c...)
+		}
+	}
+	for key, components := range before.components {
+		for _, c := range components[min(len(components), len(after.components[key])):] {
+			removed = append(removed, c...)
+		}
+	}
+	sortQuads(added)
+	sortQuads(removed)
+	return added, removed, nil
+}
+
+// sortQuads sorts quads by their N-Quads serialization
+func sortQuads(quads []*Quad) {
+	keys := make(map[*Quad]string, len(quads))
+	for _, q := range quads {
+		keys[q] = canonicalNQuad(q, nil)
+	}
+	sort.SliceStable(quads, func(i, j int) bool {
+		return keys[quads[i]] < keys[quads[j]]
+	})
+}
@@ -0,0 +1,64 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := parseTestNQuads(t, `<http://example.org/a> <http://example.org/p> "1" .
+<http://example.org/a> <http://example.org/p> "2" <http://example.org/g> .
+<http://example.org/a> <http://example.org/addr> _:x .
+_:x <http://example.org/city> "Oslo" .
+<http://example.org/a> <http://example.org/addr> _:y .
+_:y <http://example.org/city> "Bergen" .
+`)
+	// same data, relabeled blank nodes, one literal and one address changed
+	b := parseTestNQuads(t, `<http://example.org/a> <http://example.org/p> "1" .
+<http://example.org/a> <http://example.org/p> "3" <http://example.org/g> .
+<http://example.org/a> <http://example.org/addr> _:b1 .
+_:b1 <http://example.org/city> "Oslo" .
+<http://example.org/a> <http://example.org/addr> _:b2 .
+_:b2 <http://example.org/city> "Trondheim" .
+`)
+	c, err := Diff(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(c.Added))
+	assert.Equal(t, 3, len(c.Removed))
+	assert.Equal(t, `<http://example.org/a> <http://example.org/addr> _:b2 .
+`, canonicalNQuad(c.Added[0], nil))
+	assert.Equal(t, `"3"`, c.Added[1].Object.String())
+	assert.Equal(t, "b2", c.Added[2].Subject.RawValue())
+	assert.Equal(t, `"2"`, c.Removed[1].Object.String())
+
+	c, err = Diff(a, a.clone())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestDiffRepeatedComponents(t *testing.T) {
+	a := parseTestNQuads(t, "<http://example.org/a> <http://example.org/p> _:x .\n<http://example.org/a> <http://example.org/p> _:y .\n")
+	b := parseTestNQuads(t, "<http://example.org/a> <http://example.org/p> _:z .\n")
+	c, err := Diff(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(c.Added))
+	assert.Equal(t, 1, len(c.Removed))
+}
+
+func TestDiffGraphs(t *testing.T) {
+	a := NewGraph(testUri)
+	a.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewBlankNode("x"))
+	a.AddTriple(NewBlankNode("x"), NewResource("http://example.org/q"), NewLiteral("v"))
+	b := NewGraph(testUri)
+	b.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewBlankNode("n"))
+	b.AddTriple(NewBlankNode("n"), NewResource("http://example.org/q"), NewLiteral("v"))
+	b.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/r"), NewLiteral("new"))
+
+	added, removed, err := DiffGraphs(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(removed))
+	if assert.Equal(t, 1, len(added)) {
+		assert.Equal(t, "new", added[0].Object.RawValue())
+	}
+}
@@ -0,0 +1,278 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	jsonld "github.com/linkeddata/gojsonld"
+)
+
+// DocumentLoader fetches the remote documents referenced by JSON-LD input,
+// such as the contexts named by @context
+type DocumentLoader interface {
+	// LoadDocument returns the parsed JSON document found at uri
+	LoadDocument(ctx context.Context, uri string) (interface{}, error)
+}
+
+// CachingDocumentLoader is a DocumentLoader fetching documents over HTTP and
+// caching them, so that the contexts shared by many documents are fetched
+// once. Documents can be added to the cache beforehand, and fetches
+// disabled with Offline, which suits environments without network access.
+type CachingDocumentLoader struct {
+	// HTTPClient fetches documents, http.DefaultClient being used when nil
+	HTTPClient *http.Client
+	// Offline makes the loader fail on documents missing from its cache
+	// instead of fetching them
+	Offline bool
+
+	mu    sync.Mutex
+	cache map[string]interface{}
+}
+
+// defaultDocumentLoader is used by the graphs and datasets whose
+// configuration has no document loader
+var defaultDocumentLoader = NewCachingDocumentLoader(nil)
+
+// NewCachingDocumentLoader returns a CachingDocumentLoader fetching
+// documents with client, which may be nil
+func NewCachingDocumentLoader(client *http.Client) *CachingDocumentLoader {
+	return &CachingDocumentLoader{
+		HTTPClient: client,
+		cache:      make(map[string]interface{}),
+	}
+}
+
+// Add caches a JSON document as the one found at uri
+func (l *CachingDocumentLoader) Add(uri string, document []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache[uri] = doc
+	return nil
+}
+
+// LoadDocument implements DocumentLoader
+func (l *CachingDocumentLoader) LoadDocument(ctx context.Context, uri string) (interface{}, error) {
+	l.mu.Lock()
+	doc, ok := l.cache[uri]
+	l.mu.Unlock()
+	if ok {
+		return doc, nil
+	}
+	if l.Offline {
+		return nil, fmt.Errorf("%s is not cached and the document loader is offline", uri)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/ld+json,application/json;q=0.9")
+	client := l.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != 200 {
+		return nil, fmt.Errorf("Could not fetch %s - HTTP %d", uri, r.StatusCode)
+	}
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON document %s: %s", uri, err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache[uri] = doc
+	return doc, nil
+}
+
+// documentLoader returns the document loader of the configuration
+func (c *Config) documentLoader() DocumentLoader {
+	if c.DocumentLoader != nil {
+		return c.DocumentLoader
+	}
+	return defaultDocumentLoader
+}
+
+// parseJSONLD converts a JSON-LD document to RDF, after replacing the
+// remote contexts it references with their content
+func parseJSONLD(reader io.Reader, base string, loader DocumentLoader) (*jsonld.Dataset, error) {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return nil, err
+	}
+	jsonData, err := jsonld.ReadJSON(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	r := &contextResolver{ctx: context.Background(), loader: loader}
+	if err := r.resolve(jsonData, base); err != nil {
+		return nil, err
+	}
+	options := &jsonld.Options{}
+	options.Base = ""
+	options.ProduceGeneralizedRdf = false
+	// gojsonld turns the string values typed xsd:integer into "0", so that
+	// they are given another datatype until the conversion is done
+	expanded, err := jsonld.Expand(jsonData, options)
+	if err != nil {
+		return nil, err
+	}
+	retypeJSONLDValues(expanded, xsdNamespace+"integer", jsonldStringInteger)
+	dataset, err := jsonld.ToRDF(expanded, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, triples := range dataset.Graphs {
+		for _, triple := range triples {
+			if l, ok := triple.Object.(*jsonld.Literal); ok && l.Datatype != nil && l.Datatype.RawValue() == jsonldStringInteger {
+				l.Datatype = jsonld.NewResource(xsdNamespace + "integer")
+			}
+		}
+	}
+	return dataset, nil
+}
+
+// jsonldStringInteger is the datatype given to xsd:integer string values
+// while gojsonld converts a document to RDF
+const jsonldStringInteger = "urn:rdf2go:jsonld:integer"
+
+// retypeJSONLDValues replaces the datatype of the value objects of an
+// expanded JSON-LD document that have a string value
+func retypeJSONLDValues(value interface{}, from string, to string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["@value"].(string); ok && v["@type"] == from {
+			v["@type"] = to
+			return
+		}
+		for _, elt := range v {
+			retypeJSONLDValues(elt, from, to)
+		}
+	case []interface{}:
+		for _, elt := range v {
+			retypeJSONLDValues(elt, from, to)
+		}
+	}
+}
+
+// contextResolver replaces the remote contexts referenced by a JSON-LD
+// document with the contexts they hold
+type contextResolver struct {
+	ctx    context.Context
+	loader DocumentLoader
+	// loading lists the contexts being resolved, to detect cycles
+	loading []string
+}
+
+// resolve replaces the remote contexts found in a JSON value, relative
+// context IRIs being resolved against base
+func (r *contextResolver) resolve(value interface{}, base string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elt := range v {
+			if key == "@context" {
+				resolved, err := r.context(elt, base)
+				if err != nil {
+					return err
+				}
+				v[key] = resolved
+			} else if err := r.resolve(elt, base); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, elt := range v {
+			if err := r.resolve(elt, base); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// context returns a local context with its remote contexts replaced
+func (r *contextResolver) context(local interface{}, base string) (interface{}, error) {
+	switch v := local.(type) {
+	case string:
+		return r.remote(v, base)
+	case []interface{}:
+		var contexts []interface{}
+		for _, elt := range v {
+			resolved, err := r.context(elt, base)
+			if err != nil {
+				return nil, err
+			}
+			// the contexts of a remote context array are processed in order
+			if list, ok := resolved.([]interface{}); ok {
+				contexts = append(contexts, list...)
+			} else {
+				contexts = append(contexts, resolved)
+			}
+		}
+		return contexts, nil
+	}
+	return local, nil
+}
+
+// remote loads a remote context, returning the value of its @context
+func (r *contextResolver) remote(iri string, base string) (interface{}, error) {
+	if u, err := url.Parse(iri); err == nil && !u.IsAbs() && len(base) > 0 {
+		if b, err := url.Parse(base); err == nil {
+			iri = b.ResolveReference(u).String()
+		}
+	}
+	if slices.Contains(r.loading, iri) {
+		return nil, errors.New("recursive inclusion of the remote context " + iri)
+	}
+	doc, err := r.loader.LoadDocument(r.ctx, iri)
+	if err != nil {
+		return nil, fmt.Errorf("loading the remote context %s failed: %s", iri, err)
+	}
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the remote context %s is not a JSON object", iri)
+	}
+	local, ok := m["@context"]
+	if !ok {
+		return nil, fmt.Errorf("the remote context %s has no @context", iri)
+	}
+	r.loading = append(r.loading, iri)
+	defer func() { r.loading = r.loading[:len(r.loading)-1] }()
+	// the context is copied for the cached document not to be changed by
+	// the processing of the document
+	return r.context(copyJSON(local), iri)
+}
+
+// copyJSON returns a deep copy of a JSON value
+func copyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elt := range v {
+			m[key] = copyJSON(elt)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elt := range v {
+			list[i] = copyJSON(elt)
+		}
+		return list
+	}
+	return value
+}
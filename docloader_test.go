@@ -0,0 +1,63 @@
+package rdf2go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONLDRemoteContext(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		switch req.URL.Path {
+		case "/person.jsonld":
+			w.Write([]byte(`{"@context": ["/base.jsonld", {"name": "http://xmlns.com/foaf/0.1/name"}]}`))
+		case "/base.jsonld":
+			w.Write([]byte(`{"@context": {"knows": {"@id": "http://xmlns.com/foaf/0.1/knows", "@type": "@id"}}}`))
+		case "/loop.jsonld":
+			w.Write([]byte(`{"@context": "/loop.jsonld"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := NewCachingDocumentLoader(nil)
+	doc := `{"@context": "` + server.URL + `/person.jsonld", "@id": "http://example.org/alice", "name": "Alice", "knows": "http://example.org/bob"}`
+	for i := 0; i < 2; i++ {
+		g := NewGraphWithOptions(testUri, WithDocumentLoader(loader))
+		assert.NoError(t, g.Parse(strings.NewReader(doc), "application/ld+json"))
+		assert.Equal(t, 2, g.Len())
+		assert.NotNil(t, g.One(NewResource("http://example.org/alice"), NewResource("http://xmlns.com/foaf/0.1/knows"), NewResource("http://example.org/bob")))
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+
+	d := NewDatasetWithOptions(server.URL+"/data", WithDocumentLoader(loader))
+	assert.NoError(t, d.Parse(strings.NewReader(`{"@context": "person.jsonld", "@id": "http://example.org/bob", "name": "Bob"}`), "application/ld+json"))
+	assert.Equal(t, 1, d.Len())
+	assert.Equal(t, int32(2), fetches.Load())
+
+	g := NewGraphWithOptions(testUri, WithDocumentLoader(loader))
+	assert.ErrorContains(t, g.Parse(strings.NewReader(`{"@context": "`+server.URL+`/loop.jsonld"}`), "application/ld+json"), "recursive")
+	assert.Error(t, g.Parse(strings.NewReader(`{"@context": "`+server.URL+`/missing.jsonld"}`), "application/ld+json"))
+}
+
+func TestCachingDocumentLoaderOffline(t *testing.T) {
+	loader := NewCachingDocumentLoader(nil)
+	loader.Offline = true
+	assert.NoError(t, loader.Add("https://schema.org/", []byte(`{"@context": {"@vocab": "https://schema.org/"}}`)))
+	assert.Error(t, loader.Add("https://example.org/", []byte(`{`)))
+
+	g := NewGraphWithOptions(testUri, WithDocumentLoader(loader))
+	assert.NoError(t, g.Parse(strings.NewReader(`{"@context": "https://schema.org/", "@id": "http://example.org/alice", "name": "Alice"}`), "application/ld+json"))
+	assert.NotNil(t, g.One(nil, Schema.Get("name"), nil))
+
+	_, err := loader.LoadDocument(context.Background(), "https://example.org/context.jsonld")
+	assert.ErrorContains(t, err, "offline")
+}
@@ -72,7 +72,7 @@ func main() {
 	fmt.Println("✓ Resource URIs are maintained correctly")
 	fmt.Println("✓ String literals are preserved")
 	fmt.Println("⚠ Literal datatypes may get normalized (e.g., plain string → xsd:string)")
-	fmt.Println("⚠ Named graph structure is flattened due to JSON-LD library behavior")
+	fmt.Println("✓ Named graphs are preserved as @graph node objects")
 	fmt.Println("⚠ Blank nodes may be introduced during JSON-LD parsing")
 	fmt.Println()
 	fmt.Println("💡 RECOMMENDATIONS:")
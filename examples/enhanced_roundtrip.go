@@ -146,7 +146,7 @@ func main() {
 	fmt.Println("✅ TriG parsing: EXCELLENT")
 	fmt.Println("✅ TriG → JSON-LD: EXCELLENT (clean output, no escaping)")
 	fmt.Println("✅ JSON-LD → TriG: GOOD (content preserved)")
-	fmt.Println("⚠️  Named graph preservation: LIMITED (JSON-LD library limitation)")
+	fmt.Println("✅ Named graph preservation: EXCELLENT (@graph per JSON-LD 1.1)")
 	fmt.Println("✅ Single graph round-trip: PERFECT")
 	
 	fmt.Println("\n🎉 KEY ACHIEVEMENTS:")
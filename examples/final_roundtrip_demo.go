@@ -217,7 +217,7 @@ func testNamedGraphs() {
 	
 	// Check if semantic content is preserved
 	if checkContentPreservation(original, converted) {
-		fmt.Println("✅ Semantic content preserved despite graph structure changes")
+		fmt.Println("✅ Semantic content and named graphs preserved")
 	} else {
 		fmt.Println("⚠️  Some semantic content was lost")
 	}
@@ -275,10 +275,10 @@ func printSummary() {
 	fmt.Println("• Resource URI preservation")
 	fmt.Println("• Basic literal value preservation")
 	fmt.Println("• Multiple format support (TriG, Turtle, JSON-LD)")
+	fmt.Println("• Named graph preservation in JSON-LD round-trips")
 	fmt.Println()
 	
 	fmt.Println("⚠️  CURRENT LIMITATIONS:")
-	fmt.Println("• Named graph structure is simplified in JSON-LD round-trips")
 	fmt.Println("• Literal datatypes may be normalized (string literals get xsd:string)")
 	fmt.Println("• JSON-LD library introduces metadata triples in some cases")
 	fmt.Println("• Complex blank node structures may change")
@@ -284,9 +284,7 @@ func main() {
 	fmt.Println("• ✓ Programmatic dataset construction")
 	fmt.Println("• ✓ Full round-trip TriG → JSON-LD → TriG conversion")
 	fmt.Println("• ✓ Triple content preservation during round-trip")
-	fmt.Println("• ⚠ JSON-LD named graph handling is simplified (basic implementation)")
+	fmt.Println("• ✓ Named graph preservation in JSON-LD format")
 	fmt.Println("\nThe library successfully enables working with TriG datasets and converting")
 	fmt.Println("between different RDF serialization formats while preserving data integrity!")
-	fmt.Println("\nNote: Named graph structure may be simplified when round-tripping through")
-	fmt.Println("JSON-LD due to the underlying JSON-LD library, but all triple content is preserved.")
 }
@@ -0,0 +1,82 @@
+package rdf2go
+
+import (
+	"strings"
+)
+
+// Explanation traces why a statement was derived by a reasoner, or why a
+// constraint rejected a focus node. It names the rule or constraint that
+// fired, the statements it matched, and the explanations of those supporting
+// statements that were themselves derived, forming a derivation tree.
+//
+// Rule-based components, such as reasoners and shape validators, report
+// explanations so that unexpected results can be debugged.
+type Explanation struct {
+	// Statement is the derived statement, or the offending statement of a
+	// violation when there is one
+	Statement *Quad
+	// Rule identifies the rule or constraint, usually by IRI
+	Rule string
+	// Message is a human readable description of the step
+	Message string
+	// Supports are the statements matched by the rule or constraint
+	Supports []*Quad
+	// Premises explain the supporting statements that were derived rather
+	// than asserted
+	Premises []*Explanation
+}
+
+// Asserted returns the supporting statements that were asserted rather than
+// derived, for the whole derivation tree, without duplicates
+func (e *Explanation) Asserted() []*Quad {
+	seen := make(map[string]bool)
+	var quads []*Quad
+	var visit func(e *Explanation)
+	visit = func(e *Explanation) {
+		derived := make(map[string]bool)
+		for _, p := range e.Premises {
+			if p.Statement != nil {
+				derived[p.Statement.String()] = true
+			}
+		}
+		for _, q := range e.Supports {
+			key := q.String()
+			if !derived[key] && !seen[key] {
+				seen[key] = true
+				quads = append(quads, q)
+			}
+		}
+		for _, p := range e.Premises {
+			visit(p)
+		}
+	}
+	visit(e)
+	return quads
+}
+
+// String renders the derivation tree, one step per line, indented by depth
+func (e *Explanation) String() string {
+	var b strings.Builder
+	e.write(&b, 0)
+	return b.String()
+}
+
+func (e *Explanation) write(b *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent)
+	if e.Statement != nil {
+		b.WriteString(e.Statement.String())
+		b.WriteString(" ")
+	}
+	b.WriteString("[" + e.Rule + "]")
+	if len(e.Message) > 0 {
+		b.WriteString(" " + e.Message)
+	}
+	b.WriteByte('\n')
+	for _, q := range e.Supports {
+		b.WriteString(indent + "  <- " + q.String() + "\n")
+	}
+	for _, p := range e.Premises {
+		p.write(b, depth+1)
+	}
+}
@@ -0,0 +1,39 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplanation(t *testing.T) {
+	subClass := NewResource("http://www.w3.org/2000/01/rdf-schema#subClassOf")
+	typ := NewResource(rdfNamespace + "type")
+	alice := NewResource("http://example.org/alice")
+	student := NewResource("http://example.org/Student")
+	person := NewResource("http://example.org/Person")
+	agent := NewResource("http://example.org/Agent")
+
+	aliceStudent := NewQuad(alice, typ, student, nil)
+	studentPerson := NewQuad(student, subClass, person, nil)
+	personAgent := NewQuad(person, subClass, agent, nil)
+	alicePerson := NewQuad(alice, typ, person, nil)
+
+	e := &Explanation{
+		Statement: NewQuad(alice, typ, agent, nil),
+		Rule:      "rdfs9",
+		Supports:  []*Quad{alicePerson, personAgent},
+		Premises: []*Explanation{{
+			Statement: alicePerson,
+			Rule:      "rdfs9",
+			Supports:  []*Quad{aliceStudent, studentPerson},
+		}},
+	}
+	assert.Equal(t, []*Quad{personAgent, aliceStudent, studentPerson}, e.Asserted())
+
+	out := e.String()
+	assert.Contains(t, out, "<http://example.org/Agent> . [rdfs9]\n")
+	assert.Contains(t, out, "  <- "+personAgent.String()+"\n")
+	assert.Contains(t, out, "\n  "+alicePerson.String()+" [rdfs9]\n")
+	assert.Contains(t, out, "    <- "+aliceStudent.String()+"\n")
+}
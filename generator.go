@@ -0,0 +1,126 @@
+package rdf2go
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+const defaultGeneratorNamespace = "http://example.org/synthetic/"
+
+// GeneratorConfig describes a synthetic dataset, in the spirit of the LUBM
+// benchmark: a population of typed entities linked to each other and
+// described by literal properties, spread over named graphs.
+type GeneratorConfig struct {
+	// Namespace of the generated IRIs
+	Namespace string
+	// Subjects is the number of entities
+	Subjects int
+	// FanOut is the number of statements per entity, besides its type
+	FanOut int
+	// Predicates is the number of distinct predicates
+	Predicates int
+	// Classes is the number of distinct classes entities are typed with; no
+	// rdf:type statements are generated when it is zero
+	Classes int
+	// LiteralRatio is the fraction of objects that are literals rather than
+	// links to other entities, between 0 and 1
+	LiteralRatio float64
+	// Graphs is the number of named graphs; all statements go to the default
+	// graph when it is zero
+	Graphs int
+	// GraphSkew controls how entities are distributed over named graphs:
+	// uniformly when it is at most 1, and following a Zipf distribution with
+	// that exponent otherwise, so that a few graphs hold most statements
+	GraphSkew float64
+	// Seed makes the output reproducible
+	Seed int64
+}
+
+// DefaultGeneratorConfig returns a configuration generating about a hundred
+// thousand quads
+func DefaultGeneratorConfig() GeneratorConfig {
+	return GeneratorConfig{
+		Namespace:    defaultGeneratorNamespace,
+		Subjects:     10000,
+		FanOut:       10,
+		Predicates:   20,
+		Classes:      5,
+		LiteralRatio: 0.5,
+		Graphs:       4,
+		Seed:         1,
+	}
+}
+
+// GenerateQuads generates the quads described by cfg, calling fn for each of
+// them until it returns false. The same configuration always produces the
+// same quads in the same order.
+func GenerateQuads(cfg GeneratorConfig, fn func(*Quad) bool) {
+	if len(cfg.Namespace) == 0 {
+		cfg.Namespace = defaultGeneratorNamespace
+	}
+	if cfg.Predicates <= 0 {
+		cfg.Predicates = 1
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	var zipf *rand.Zipf
+	if cfg.Graphs > 1 && cfg.GraphSkew > 1 {
+		zipf = rand.NewZipf(rng, cfg.GraphSkew, 1, uint64(cfg.Graphs-1))
+	}
+
+	entity := func(i int) Term {
+		return NewResource(cfg.Namespace + "entity/" + strconv.Itoa(i))
+	}
+	rdfType := NewResource(rdfNamespace + "type")
+	xsdInteger := NewResource(xsdNamespace + "integer")
+
+	for i := 0; i < cfg.Subjects; i++ {
+		subject := entity(i)
+		var graph Term
+		if cfg.Graphs > 0 {
+			n := rng.Intn(cfg.Graphs)
+			if zipf != nil {
+				n = int(zipf.Uint64())
+			}
+			graph = NewResource(cfg.Namespace + "graph/" + strconv.Itoa(n))
+		}
+		if cfg.Classes > 0 {
+			class := NewResource(cfg.Namespace + "Class" + strconv.Itoa(rng.Intn(cfg.Classes)))
+			if !fn(NewQuad(subject, rdfType, class, graph)) {
+				return
+			}
+		}
+		for j := 0; j < cfg.FanOut; j++ {
+			n := rng.Intn(cfg.Predicates)
+			predicate := NewResource(cfg.Namespace + "p" + strconv.Itoa(n))
+			var object Term
+			if rng.Float64() < cfg.LiteralRatio {
+				// even predicates carry numbers, odd ones carry strings
+				if n%2 == 0 {
+					object = NewLiteralWithDatatype(strconv.Itoa(rng.Intn(1000000)), xsdInteger)
+				} else {
+					object = NewLiteral(fmt.Sprintf("value %d of entity %d", j, i))
+				}
+			} else {
+				object = entity(rng.Intn(cfg.Subjects))
+			}
+			if !fn(NewQuad(subject, predicate, object, graph)) {
+				return
+			}
+		}
+	}
+}
+
+// GenerateDataset returns a new dataset holding the quads described by cfg
+func GenerateDataset(cfg GeneratorConfig) *Dataset {
+	uri := cfg.Namespace
+	if len(uri) == 0 {
+		uri = defaultGeneratorNamespace
+	}
+	d := NewDataset(uri)
+	GenerateQuads(cfg, func(quad *Quad) bool {
+		d.Add(quad)
+		return true
+	})
+	return d
+}
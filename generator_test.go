@@ -0,0 +1,48 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDataset(t *testing.T) {
+	cfg := GeneratorConfig{Subjects: 100, FanOut: 5, Predicates: 4, Classes: 3, LiteralRatio: 0.5, Graphs: 3, Seed: 42}
+	d := GenerateDataset(cfg)
+	assert.Equal(t, 600, d.Len())
+	assert.Equal(t, 100, len(d.All(nil, NewResource(rdfNamespace+"type"), nil, NewResource("http://example.org/synthetic/graph/0")))+
+		len(d.All(nil, NewResource(rdfNamespace+"type"), nil, NewResource("http://example.org/synthetic/graph/1")))+
+		len(d.All(nil, NewResource(rdfNamespace+"type"), nil, NewResource("http://example.org/synthetic/graph/2"))))
+	assert.True(t, len(d.GetNamedGraphs()) <= 3)
+
+	literals := 0
+	for quad := range d.Quads() {
+		if _, ok := quad.Object.(*Literal); ok {
+			literals++
+		}
+	}
+	assert.InDelta(t, 250, literals, 50)
+}
+
+func TestGenerateQuadsReproducible(t *testing.T) {
+	cfg := DefaultGeneratorConfig()
+	cfg.Subjects = 50
+	cfg.GraphSkew = 2
+	var first, second []string
+	GenerateQuads(cfg, func(q *Quad) bool {
+		first = append(first, q.String())
+		return true
+	})
+	GenerateQuads(cfg, func(q *Quad) bool {
+		second = append(second, q.String())
+		return len(second) < 10
+	})
+	assert.Equal(t, 50*11, len(first))
+	assert.Equal(t, first[:10], second)
+}
+
+func TestGenerateDatasetDefaultGraph(t *testing.T) {
+	d := GenerateDataset(GeneratorConfig{Subjects: 10, FanOut: 2})
+	assert.Equal(t, 20, len(d.All(nil, nil, nil, nil)))
+	assert.Equal(t, 0, len(d.GetNamedGraphs()))
+}
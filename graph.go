@@ -2,23 +2,38 @@ package rdf2go
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 
 	rdf "github.com/deiu/gon3"
-	jsonld "github.com/linkeddata/gojsonld"
 )
 
 // Graph structure
 type Graph struct {
-	triples    map[*Triple]bool
+	triples    map[*Triple]uint64
+	seq        uint64
+	index      *spoIndex[*Triple]
+	arena      *TermArena
+	dict       *TermDictionary
+	iriPolicy  *IRIPolicy
+	config     *Config
+	prefixes   map[string]string
 	httpClient *http.Client
 	uri        string
 	term       Term
+	hooks      []*graphHook
+	guard
 }
 
 // NewHttpClient creates an http.Client to be used for parsing resources
@@ -33,23 +48,130 @@ func NewHttpClient(skip bool) *http.Client {
 	}
 }
 
-// NewGraph creates a Graph object
+// newRDFRequest creates a GET request for an RDF document, negotiating one of
+// the formats supported by the parser
+func newRDFRequest(ctx context.Context, uri string) (*http.Request, error) {
+	q, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	q.Header.Set("Accept", "application/trig;q=1,text/turtle;q=0.8,application/ld+json;q=0.5")
+	return q, nil
+}
+
+// NewGraph creates a Graph object. It is a shorthand for NewGraphWithOptions
+// with the WithSkipVerify option.
 func NewGraph(uri string, skipVerify ...bool) *Graph {
-	skip := false
+	return NewGraphWithOptions(uri, skipVerifyOptions(skipVerify)...)
+}
+
+// NewUnindexedGraph creates a Graph object that does not maintain lookup
+// indexes. Pattern matching scans every triple, but the graph uses less
+// memory, which suits large graphs that are only iterated or serialized. It
+// is a shorthand for NewGraphWithOptions with WithIndexing(false).
+func NewUnindexedGraph(uri string, skipVerify ...bool) *Graph {
+	return NewGraphWithOptions(uri, append(skipVerifyOptions(skipVerify), WithIndexing(false))...)
+}
+
+// NewGraphWithOptions creates a Graph object configured by the package-level
+// configuration and the given options
+func NewGraphWithOptions(uri string, opts ...Option) *Graph {
+	config := newConfig(opts...)
+	g := newGraph(uri, config, config.client())
+	if !config.Unindexed {
+		g.index = newSPOIndex[*Triple]()
+	}
+	return g
+}
+
+// skipVerifyOptions turns the skipVerify argument of the older constructors
+// into options
+func skipVerifyOptions(skipVerify []bool) []Option {
 	if len(skipVerify) > 0 {
-		skip = skipVerify[0]
+		return []Option{WithSkipVerify(skipVerify[0])}
 	}
+	return nil
+}
+
+func newGraph(uri string, config *Config, client *http.Client) *Graph {
 	g := &Graph{
-		triples:    make(map[*Triple]bool),
-		httpClient: NewHttpClient(skip),
+		triples:    make(map[*Triple]uint64),
+		config:     config,
+		httpClient: client,
 		uri:        uri,
 		term:       NewResource(uri),
+		prefixes:   config.boundPrefixes(),
+	}
+	g.guard = newGuard(config)
+	if config.InternTerms {
+		g.dict = NewTermDictionary()
 	}
 	return g
 }
 
+// guard holds the lock of thread-safe graphs and datasets, mu being nil for
+// the others
+type guard struct {
+	mu *sync.RWMutex
+}
+
+func newGuard(config *Config) guard {
+	if config.ThreadSafe {
+		return guard{mu: new(sync.RWMutex)}
+	}
+	return guard{}
+}
+
+// lock locks for writing, returning the function unlocking
+func (g guard) lock() func() {
+	if g.mu == nil {
+		return func() {}
+	}
+	g.mu.Lock()
+	return g.mu.Unlock
+}
+
+// rlock locks for reading, returning the function unlocking
+func (g guard) rlock() func() {
+	if g.mu == nil {
+		return func() {}
+	}
+	g.mu.RLock()
+	return g.mu.RUnlock
+}
+
+// Config returns a copy of the configuration of the graph
+func (g *Graph) Config() Config {
+	return g.config.copy()
+}
+
+// SetArena makes the parsers of the graph allocate terms and triples from
+// arena, which suits bulk loads whose result is discarded as a whole. Passing
+// nil restores individual allocations. Arenas are currently used by the
+// N-Quads and N-Triples parsers.
+func (g *Graph) SetArena(arena *TermArena) {
+	g.arena = arena
+}
+
+// SetIRIPolicy sets the IRI equivalence policy used when matching and adding
+// triples, re-indexing the graph. Passing nil restores exact comparison.
+// Triples that were added before the policy was set are kept, even when they
+// are equivalent under the new policy.
+func (g *Graph) SetIRIPolicy(policy *IRIPolicy) {
+	defer g.lock()()
+	g.iriPolicy = policy
+	if g.index != nil {
+		g.index = newSPOIndex[*Triple]()
+		for t := range g.triples {
+			sk, pk, ok := policy.tripleKeys(t)
+			g.index.add(sk, pk, ok, t)
+		}
+	}
+}
+
 // Len returns the length of the graph as number of triples in the graph
 func (g *Graph) Len() int {
+	defer g.rlock()()
 	return len(g.triples)
 }
 
@@ -65,42 +187,56 @@ func (g *Graph) URI() string {
 
 // One returns one triple based on a triple pattern of S, P, O objects
 func (g *Graph) One(s Term, p Term, o Term) *Triple {
-	for triple := range g.IterTriples() {
-		if s != nil {
-			if p != nil {
-				if o != nil {
-					if triple.Subject.Equal(s) && triple.Predicate.Equal(p) && triple.Object.Equal(o) {
-						return triple
-					}
-				} else {
-					if triple.Subject.Equal(s) && triple.Predicate.Equal(p) {
-						return triple
-					}
-				}
-			} else {
-				if triple.Subject.Equal(s) {
-					return triple
-				}
-			}
-		} else if p != nil {
-			if o != nil {
-				if triple.Predicate.Equal(p) && triple.Object.Equal(o) {
-					return triple
-				}
-			} else {
-				if triple.Predicate.Equal(p) {
-					return triple
-				}
-			}
-		} else if o != nil {
-			if triple.Object.Equal(o) {
-				return triple
-			}
-		} else {
-			return triple
+	var found *Triple
+	g.match(s, p, o, func(triple *Triple) bool {
+		found = triple
+		return false
+	})
+	return found
+}
+
+// match calls fn for every triple matching the pattern of S, P, O objects
+// (nil matching anything) until fn returns false. Thread-safe graphs collect
+// the matches first, so that fn runs unlocked and may modify the graph.
+func (g *Graph) match(s Term, p Term, o Term, fn func(*Triple) bool) {
+	if g.mu == nil {
+		g.matchUnlocked(s, p, o, fn)
+		return
+	}
+	var matches []*Triple
+	g.mu.RLock()
+	g.matchUnlocked(s, p, o, func(triple *Triple) bool {
+		matches = append(matches, triple)
+		return true
+	})
+	g.mu.RUnlock()
+	for _, triple := range matches {
+		if !fn(triple) {
+			return
+		}
+	}
+}
+
+// matchUnlocked implements match
+func (g *Graph) matchUnlocked(s Term, p Term, o Term, fn func(*Triple) bool) {
+	filter := func(triple *Triple) bool {
+		if !g.iriPolicy.match(s, triple.Subject) || !g.iriPolicy.match(p, triple.Predicate) || !g.iriPolicy.match(o, triple.Object) {
+			return true
+		}
+		return fn(triple)
+	}
+	if g.index != nil {
+		sk, sok := g.iriPolicy.indexKey(s)
+		pk, pok := g.iriPolicy.indexKey(p)
+		ok, ook := g.iriPolicy.indexKey(o)
+		g.index.match(sk, s != nil && sok, pk, p != nil && pok, ok, o != nil && ook, filter)
+		return
+	}
+	for triple := range g.triples {
+		if !filter(triple) {
+			return
 		}
 	}
-	return nil
 }
 
 // IterTriples provides a channel containing all the triples in the graph.
@@ -109,64 +245,178 @@ func (g *Graph) IterTriples() (ch chan *Triple) {
 	// This function returns a channel rather than a slice for backwards compatibility.
 	// It does not use a goroutine to populate the channel because that can trigger Go's 'concurrent map misuse'
 	// detector, and would have little performance benefit.
-	ch = make(chan *Triple, len(g.triples))
-	for triple := range g.triples {
+	triples := slices.Collect(g.Triples())
+	ch = make(chan *Triple, len(triples))
+	for _, triple := range triples {
 		ch <- triple
 	}
 	close(ch)
 	return ch
 }
 
+// Triples returns an iterator over all the triples in the graph. Unlike
+// IterTriples, triples are yielded lazily and iteration can be stopped early.
+// Both follow the iteration order of the configuration, and iterate over a
+// snapshot of thread-safe graphs.
+func (g *Graph) Triples() iter.Seq[*Triple] {
+	return func(yield func(*Triple) bool) {
+		if g.config.Order != OrderUnspecified || g.mu != nil {
+			unlock := g.rlock()
+			triples := g.orderedTriples(g.config.Order)
+			unlock()
+			for _, triple := range triples {
+				if !yield(triple) {
+					return
+				}
+			}
+			return
+		}
+		for triple := range g.triples {
+			if !yield(triple) {
+				return
+			}
+		}
+	}
+}
+
 // Add is used to add a Triple object to the graph
 func (g *Graph) Add(t *Triple) {
-	g.triples[t] = true
+	if g.add(t) {
+		g.notify(QuadAdded, t)
+	}
+}
+
+// add adds a triple, telling whether it was missing from the graph
+func (g *Graph) add(t *Triple) bool {
+	defer g.lock()()
+	return g.addUnlocked(t)
+}
+
+// addUnlocked implements add
+func (g *Graph) addUnlocked(t *Triple) bool {
+	if !g.insertUnlocked(t) {
+		return false
+	}
+	if g.index != nil {
+		sk, pk, ok := g.iriPolicy.tripleKeys(t)
+		g.index.add(sk, pk, ok, t)
+	}
+	return true
+}
+
+// insertUnlocked adds a triple without indexing it, telling whether it was
+// missing from the graph
+func (g *Graph) insertUnlocked(t *Triple) bool {
+	if _, exists := g.triples[t]; exists {
+		return false
+	}
+	g.dict.internTriple(t)
+	if g.iriPolicy != nil {
+		equivalent := false
+		g.matchUnlocked(t.Subject, t.Predicate, t.Object, func(*Triple) bool {
+			equivalent = true
+			return false
+		})
+		if equivalent {
+			return false
+		}
+	}
+	g.seq++
+	g.triples[t] = g.seq
+	return true
 }
 
 // AddTriple is used to add a triple made of individual S, P, O objects
 func (g *Graph) AddTriple(s Term, p Term, o Term) {
-	g.triples[NewTriple(s, p, o)] = true
+	g.Add(NewTriple(s, p, o))
 }
 
 // Remove is used to remove a Triple object
 func (g *Graph) Remove(t *Triple) {
+	if g.remove(t) {
+		g.notify(QuadRemoved, t)
+	}
+}
+
+// remove removes a triple, telling whether it was part of the graph
+func (g *Graph) remove(t *Triple) bool {
+	defer g.lock()()
+	if _, exists := g.triples[t]; !exists {
+		return false
+	}
 	delete(g.triples, t)
+	if g.index != nil {
+		sk, pk, ok := g.iriPolicy.tripleKeys(t)
+		g.index.remove(sk, pk, ok, t)
+	}
+	return true
 }
 
 // All is used to return all triples that match a given pattern of S, P, O objects
 func (g *Graph) All(s Term, p Term, o Term) []*Triple {
 	var triples []*Triple
-	for triple := range g.IterTriples() {
-		if s != nil {
-			if p != nil {
-				if o != nil {
-					if triple.Subject.Equal(s) && triple.Predicate.Equal(p) && triple.Object.Equal(o) {
-						triples = append(triples, triple)
-					}
-				} else {
-					if triple.Subject.Equal(s) && triple.Predicate.Equal(p) {
-						triples = append(triples, triple)
-					}
-				}
-			} else {
-				if triple.Subject.Equal(s) {
-					triples = append(triples, triple)
-				}
-			}
-		} else if p != nil {
-			if o != nil {
-				if triple.Predicate.Equal(p) && triple.Object.Equal(o) {
-					triples = append(triples, triple)
-				}
-			} else {
-				if triple.Predicate.Equal(p) {
-					triples = append(triples, triple)
-				}
-			}
-		} else if o != nil {
-			if triple.Object.Equal(o) {
-				triples = append(triples, triple)
-			}
+	if s == nil && p == nil && o == nil {
+		return triples
+	}
+	g.match(s, p, o, func(triple *Triple) bool {
+		triples = append(triples, triple)
+		return true
+	})
+	return triples
+}
+
+// InstancesOf returns the subjects typed with a class by rdf:type statements.
+// The index keeps the statements of each predicate and object together, so
+// indexed graphs answer it without looking at other statements.
+func (g *Graph) InstancesOf(class Term) []Term {
+	var instances []Term
+	g.match(nil, NewResource(rdfNamespace+"type"), class, func(triple *Triple) bool {
+		instances = append(instances, triple.Subject)
+		return true
+	})
+	return instances
+}
+
+// Properties returns the objects of the statements about a subject, grouped
+// by predicate, in a single lookup. Each distinct predicate is the key of a
+// single entry, which holds one of the predicate terms of the graph: being
+// pointers, keys compare by identity, so the map is meant to be ranged over.
+func (g *Graph) Properties(subject Term) map[Term][]Term {
+	properties := make(map[Term][]Term)
+	if subject == nil {
+		return properties
+	}
+	predicates := make(map[string]Term)
+	g.match(subject, nil, nil, func(triple *Triple) bool {
+		k := g.iriPolicy.key(triple.Predicate)
+		predicate, ok := predicates[k]
+		if !ok {
+			predicate = triple.Predicate
+			predicates[k] = predicate
 		}
+		properties[predicate] = append(properties[predicate], triple.Object)
+		return true
+	})
+	return properties
+}
+
+// Referrers returns the statements linking to an object, restricted to the
+// given predicates if any. Indexed graphs answer it from the object
+// permutation of the index, without looking at other statements.
+func (g *Graph) Referrers(object Term, predicates ...Term) []*Triple {
+	var triples []*Triple
+	if object == nil {
+		return triples
+	}
+	collect := func(triple *Triple) bool {
+		triples = append(triples, triple)
+		return true
+	}
+	if len(predicates) == 0 {
+		g.match(nil, nil, object, collect)
+	}
+	for _, p := range predicates {
+		g.match(nil, p, object, collect)
 	}
 	return triples
 }
@@ -180,88 +430,172 @@ func (g *Graph) Merge(toMerge *Graph) {
 
 // Parse is used to parse RDF data from a reader, using the provided mime type
 func (g *Graph) Parse(reader io.Reader, mime string) error {
+	_, err := g.ParseWithStats(reader, mime)
+	return err
+}
+
+// ParseWithStats parses RDF data from a reader like Parse, returning a
+// summary of what was read. The statements repeated within the document are
+// handled after the duplicate policy of the configuration.
+func (g *Graph) ParseWithStats(reader io.Reader, mime string) (*ParseStats, error) {
+	t := newParseTracker(g.config, g.iriPolicy)
+	err := g.parse(reader, mime, t)
+	return t.done(g.uri), t.result(err)
+}
+
+func (g *Graph) parse(reader io.Reader, mime string, t *parseTracker) error {
+	reader, err := decodeCharset(g.config.Limits.limit(reader), mime)
+	if err != nil {
+		return err
+	}
+	mime = mediaType(mime)
+	base := g.config.base(g.uri)
 	parserName := mimeParser[mime]
 	if len(parserName) == 0 {
 		parserName = "guess"
 	}
-	if parserName == "jsonld" {
-		buf := new(bytes.Buffer)
-		buf.ReadFrom(reader)
-		jsonData, err := jsonld.ReadJSON(buf.Bytes())
-		if err != nil {
-			return err
+	add := func(s Term, p Term, o Term) error {
+		ok, err := t.admit(s, p, o, nil)
+		if ok {
+			g.Add(g.arena.NewTriple(s, p, o))
 		}
-		options := &jsonld.Options{}
-		options.Base = ""
-		options.ProduceGeneralizedRdf = false
-		dataSet, err := jsonld.ToRDF(jsonData, options)
+		return err
+	}
+	if parserName == "jsonld" {
+		dataSet, err := parseJSONLD(reader, base, g.config.documentLoader())
 		if err != nil {
 			return err
 		}
 		for t := range dataSet.IterTriples() {
-			g.AddTriple(jterm2term(t.Subject), jterm2term(t.Predicate), jterm2term(t.Object))
+			if err := add(jterm2term(t.Subject), jterm2term(t.Predicate), jterm2term(t.Object)); err != nil {
+				return err
+			}
 		}
 
 	} else if parserName == "turtle" {
-		parser, err := rdf.NewParser(g.uri).Parse(reader)
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(reader); err != nil {
+			return err
+		}
+		// gon3 does not know the quoted triples and annotations of
+		// Turtle-star, and cannot skip malformed statements nor limit
+		// their nesting
+		if g.config.Lenient || g.config.Limits.MaxDepth > 0 || bytes.Contains(buf.Bytes(), []byte("<<")) || bytes.Contains(buf.Bytes(), []byte("{|")) {
+			var addErr error
+			prefixes, err := parseTrigPrefixes(buf.String(), base, true, t.skipper(), g.config.Limits.MaxDepth, func(s Term, p Term, o Term, _ Term) {
+				if addErr == nil {
+					addErr = add(s, p, o)
+				}
+			})
+			t.declare(prefixes)
+			if err != nil {
+				return err
+			}
+			return addErr
+		}
+		t.declare(declaredPrefixes(buf.String(), base))
+		parser, err := rdf.NewParser(base).Parse(buf)
 		if err != nil {
 			return err
 		}
 		for s := range parser.IterTriples() {
-			g.AddTriple(rdf2term(s.Subject), rdf2term(s.Predicate), rdf2term(s.Object))
+			if err := add(rdf2term(s.Subject), rdf2term(s.Predicate), rdf2term(s.Object)); err != nil {
+				return err
+			}
 		}
 	} else if parserName == "trig" {
-		// Parse TriG by creating a dataset and extracting the default graph
-		dataset := NewDataset(g.uri)
-		err := dataset.Parse(reader, mime)
+		// Parse TriG by creating a dataset and extracting the default graph,
+		// whose duplicates are looked for while adding its statements
+		dataset := NewDatasetWithOptions(g.uri, WithBase(base))
+		inner := newParseTracker(&Config{Lenient: g.config.Lenient, Limits: g.config.Limits}, nil)
+		err := dataset.parse(reader, mime, inner)
+		t.declare(inner.stats.Prefixes)
+		t.errs = inner.errs
+		t.stats.Skipped = inner.stats.Skipped
 		if err != nil {
 			return err
 		}
 		// Add all quads from default graph to this graph
-		for quad := range dataset.IterQuads() {
+		dropped := 0
+		for _, quad := range dataset.orderedQuads() {
 			if quad.Graph == nil {
-				g.AddTriple(quad.Subject, quad.Predicate, quad.Object)
+				if err := add(quad.Subject, quad.Predicate, quad.Object); err != nil {
+					return err
+				}
+			} else {
+				dropped++
 			}
 		}
+		return g.droppedNamedGraphs(dropped, t)
+	} else if parserName == "ntriples" || parserName == "nquads" {
+		// Only statements of the default graph are added to the graph
+		dropped := 0
+		err := parseNQuads(reader, g.arena, t.skipper(), func(quad *Quad) error {
+			if quad.Graph == nil {
+				return add(quad.Subject, quad.Predicate, quad.Object)
+			} else if parserName == "ntriples" {
+				return errors.New("N-Triples statements cannot have a graph label")
+			} else if g.config.Strict {
+				return errNamedGraphInGraph
+			} else {
+				dropped++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return g.droppedNamedGraphs(dropped, t)
 	} else {
 		return errors.New(parserName + " is not supported by the parser")
 	}
 	return nil
 }
 
+var errNamedGraphInGraph = errors.New("a graph cannot hold statements of named graphs")
+
+// droppedNamedGraphs reports the statements of named graphs left out by Parse,
+// which is an error in strict mode
+func (g *Graph) droppedNamedGraphs(n int, t *parseTracker) error {
+	if n == 0 {
+		return nil
+	}
+	if g.config.Strict {
+		return errNamedGraphInGraph
+	}
+	t.warn("dropped %d statements of named graphs", n)
+	g.config.log(slog.LevelWarn, "dropped statements of named graphs", "graph", g.uri, "count", n)
+	return nil
+}
+
 // LoadURI is used to load RDF data from a specific URI
 func (g *Graph) LoadURI(uri string) error {
-	doc := defrag(uri)
-	q, err := http.NewRequest("GET", doc, nil)
-	if err != nil {
-		return err
-	}
+	return g.LoadURIContext(context.Background(), uri)
+}
+
+// LoadURIContext is used to load RDF data from a specific URI. The request is
+// aborted when the context is cancelled or its deadline expires.
+func (g *Graph) LoadURIContext(ctx context.Context, uri string) error {
+	_, err := g.LoadURIWithResult(ctx, uri)
+	return err
+}
+
+// LoadURIWithResult loads RDF data from a specific URI like LoadURIContext,
+// and describes the document that was read: its final URL after redirects,
+// its HTTP validators and headers, and the parse error returned when the
+// document is malformed
+func (g *Graph) LoadURIWithResult(ctx context.Context, uri string) (*LoadResult, error) {
 	if len(g.uri) == 0 {
-		g.uri = doc
-	}
-	q.Header.Set("Accept", "application/trig;q=1,text/turtle;q=0.8,application/ld+json;q=0.5")
-	r, err := g.httpClient.Do(q)
-	if err != nil {
-		return err
+		g.uri = defrag(uri)
 	}
-	if r != nil {
-		defer r.Body.Close()
-		if r.StatusCode == 200 {
-			g.Parse(r.Body, r.Header.Get("Content-Type"))
-		} else {
-			return fmt.Errorf("Could not fetch graph from %s - HTTP %d", uri, r.StatusCode)
-		}
-	}
-	return nil
+	return g.config.fetchRDF(ctx, g.httpClient, uri, "graph", g.Parse)
 }
 
 // String is used to serialize the graph object using NTriples
 func (g *Graph) String() string {
-	var toString string
-	for triple := range g.IterTriples() {
-		toString += triple.String() + "\n"
-	}
-	return toString
+	var b strings.Builder
+	g.WriteTo(&b)
+	return b.String()
 }
 
 // Serialize is used to serialize a graph based on a given mime type
@@ -271,48 +605,33 @@ func (g *Graph) Serialize(w io.Writer, mime string) error {
 		return g.serializeJSONLD(w)
 	} else if serializerName == "trig" {
 		return g.serializeTrig(w)
+	} else if serializerName == "ntriples" || serializerName == "nquads" {
+		return g.serializeNTriples(w)
+	} else if serializerName == "internal" && mime == "text/html" {
+		return g.ExportHTML(w, nil)
 	}
 	// just return Turtle by default
 	return g.serializeTurtle(w)
 }
 
-// @TODO improve streaming
+// serializeTurtle serializes the graph to Turtle, laid out following the
+// serialization options of the configuration
 func (g *Graph) serializeTurtle(w io.Writer) error {
-	var err error
-
-	triplesBySubject := make(map[string][]*Triple)
-
-	for triple := range g.IterTriples() {
-		s := encodeTerm(triple.Subject)
-		triplesBySubject[s] = append(triplesBySubject[s], triple)
-	}
-
-	for subject, triples := range triplesBySubject {
-		_, err = fmt.Fprintf(w, "%s\n", subject)
-		if err != nil {
-			return err
-		}
-
-		for key, triple := range triples {
-			p := encodeTerm(triple.Predicate)
-			o := encodeTerm(triple.Object)
-
-			if key == len(triples)-1 {
-				_, err = fmt.Fprintf(w, "  %s %s .", p, o)
-				if err != nil {
-					return err
-				}
-				break
-			}
-			_, err = fmt.Fprintf(w, "  %s %s ;\n", p, o)
-			if err != nil {
-				return err
-			}
-		}
+	return writeTurtle(w, slices.Collect(g.Triples()), g.uri, g.config, g.prefixes)
+}
 
+// writeTurtle writes triples as a Turtle document, source being the IRI of
+// the document named by comments
+func writeTurtle(w io.Writer, triples []*Triple, source string, config *Config, prefixes map[string]string) error {
+	var b strings.Builder
+	if config.Serialize.Comments {
+		b.WriteString(sourceComment(source))
 	}
-
-	return nil
+	base := config.serializeBase(source)
+	writeTurtlePrefixes(&b, base, prefixes)
+	writeTurtleTriples(&b, triples, "", config.Serialize, turtleEncoder(base, prefixes), nil)
+	_, err := io.WriteString(w, b.String())
+	return err
 }
 
 // func (g *Graph) serializeJSONLD(w io.Writer) error {
@@ -338,15 +657,27 @@ func (g *Graph) serializeTurtle(w io.Writer) error {
 // 	return err
 // }
 
+// errQuotedTripleJSONLD is returned when serializing quoted triples to
+// JSON-LD, which cannot represent them
+var errQuotedTripleJSONLD = errors.New("quoted triples cannot be serialized to JSON-LD")
+
 func (g *Graph) serializeJSONLD(w io.Writer) error {
+	return writeJSONLD(w, g.Triples())
+}
+
+// writeJSONLD writes triples as a flat JSON-LD document, one node object per
+// statement
+func writeJSONLD(w io.Writer, triples iter.Seq[*Triple]) error {
 	r := []map[string]interface{}{}
-	for elt := range g.IterTriples() {
+	for elt := range triples {
 		var one map[string]interface{}
 		switch elt.Subject.(type) {
 		case *BlankNode:
 			one = map[string]interface{}{
 				"@id": elt.Subject.(*BlankNode).String(),
 			}
+		case *QuotedTriple:
+			return errQuotedTripleJSONLD
 		default:
 			one = map[string]interface{}{
 				"@id": elt.Subject.(*Resource).URI,
@@ -359,7 +690,12 @@ func (g *Graph) serializeJSONLD(w io.Writer) error {
 					"@id": t.URI,
 				},
 			}
-			break
+		case *BlankNode:
+			one[elt.Predicate.(*Resource).URI] = []map[string]string{
+				{
+					"@id": t.String(),
+				},
+			}
 		case *Literal:
 			v := map[string]string{
 				"@value": t.Value,
@@ -371,6 +707,8 @@ func (g *Graph) serializeJSONLD(w io.Writer) error {
 				v["@language"] = t.Language
 			}
 			one[elt.Predicate.(*Resource).URI] = []map[string]string{v}
+		case *QuotedTriple:
+			return errQuotedTripleJSONLD
 		}
 		r = append(r, one)
 	}
@@ -378,35 +716,73 @@ func (g *Graph) serializeJSONLD(w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(w, string(bytes))
+	_, err = w.Write(bytes)
+	return err
+}
+
+// serializeNTriples serializes the graph to N-Triples, one statement per line
+func (g *Graph) serializeNTriples(w io.Writer) error {
+	return writeNTriples(w, g.Triples(), g.uri, g.config)
+}
+
+// writeNTriples writes triples as N-Triples, source being the IRI of the
+// document named by comments
+func writeNTriples(w io.Writer, triples iter.Seq[*Triple], source string, config *Config) error {
+	if config.Serialize.Comments {
+		var b strings.Builder
+		var quads []*Quad
+		for triple := range triples {
+			quads = append(quads, NewTripleQuad(triple))
+		}
+		writeAnnotatedNQuads(&b, quads, source, false)
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	for triple := range triples {
+		if _, err := fmt.Fprintln(w, triple.String()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // serializeTrig serializes the graph to TriG format (as default graph)
 func (g *Graph) serializeTrig(w io.Writer) error {
-	// Serialize as TriG with all triples in the default graph
-	fmt.Fprintln(w, "{")
-
-	triplesBySubject := make(map[string][]*Triple)
-	for triple := range g.IterTriples() {
-		s := encodeTerm(triple.Subject)
-		triplesBySubject[s] = append(triplesBySubject[s], triple)
+	var b strings.Builder
+	if g.config.Serialize.Comments {
+		b.WriteString(sourceComment(g.uri))
 	}
+	base := g.config.serializeBase(g.uri)
+	writeTurtlePrefixes(&b, base, g.prefixes)
+	b.WriteString("{\n")
+	if g.Len() > 0 {
+		writeTurtleTriples(&b, slices.Collect(g.Triples()), g.config.Serialize.indent(), g.config.Serialize, turtleEncoder(base, g.prefixes), nil)
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
 
-	for subject, triples := range triplesBySubject {
-		fmt.Fprintf(w, "  %s\n", subject)
-		for key, triple := range triples {
-			p := encodeTerm(triple.Predicate)
-			o := encodeTerm(triple.Object)
-
-			if key == len(triples)-1 {
-				fmt.Fprintf(w, "    %s %s .\n", p, o)
-				break
-			}
-			fmt.Fprintf(w, "    %s %s ;\n", p, o)
+// orderedTriples returns the triples of the graph in insertion order, or
+// sorted by their N-Triples serialization, or in map order when order is
+// OrderUnspecified
+func (g *Graph) orderedTriples(order IterationOrder) []*Triple {
+	triples := make([]*Triple, 0, len(g.triples))
+	for triple := range g.triples {
+		triples = append(triples, triple)
+	}
+	if order == OrderUnspecified {
+		return triples
+	}
+	if order == OrderSorted {
+		keys := make(map[*Triple]string, len(triples))
+		for _, t := range triples {
+			keys[t] = t.String()
 		}
+		slices.SortFunc(triples, func(a, b *Triple) int { return strings.Compare(keys[a], keys[b]) })
+		return triples
 	}
-
-	fmt.Fprintln(w, "}")
-	return nil
+	slices.SortFunc(triples, func(a, b *Triple) int { return cmp.Compare(g.triples[a], g.triples[b]) })
+	return triples
 }
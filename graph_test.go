@@ -2,10 +2,14 @@ package rdf2go
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +35,14 @@ func MockServer() http.Handler {
 		w.Write([]byte(simpleTurtle))
 		return
 	}))
+	handler.Handle("/slow", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.Header().Add("Content-Type", "text/turtle")
+		w.Write([]byte(simpleTurtle))
+	}))
 	return handler
 }
 
@@ -123,6 +135,58 @@ func TestGraphAll(t *testing.T) {
 	assert.Equal(t, 1, len(g.All(nil, NewResource("f"), NewLiteral("h"))))
 }
 
+func TestGraphInstancesOf(t *testing.T) {
+	rdfType := NewResource("http://www.w3.org/1999/02/22-rdf-syntax-ns#type")
+	person := NewResource("http://xmlns.com/foaf/0.1/Person")
+	for _, g := range []*Graph{NewGraph(testUri), NewUnindexedGraph(testUri)} {
+		g.AddTriple(NewResource("a"), rdfType, person)
+		g.AddTriple(NewResource("b"), rdfType, person)
+		g.AddTriple(NewResource("b"), rdfType, NewResource("http://xmlns.com/foaf/0.1/Agent"))
+		g.AddTriple(NewResource("c"), NewResource("http://xmlns.com/foaf/0.1/knows"), person)
+
+		assert.ElementsMatch(t, []Term{NewResource("a"), NewResource("b")}, g.InstancesOf(person))
+		assert.Empty(t, g.InstancesOf(NewResource("http://xmlns.com/foaf/0.1/Group")))
+	}
+}
+
+func TestGraphProperties(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	g.AddTriple(NewResource("a"), NewResource("f"), NewLiteral("h"))
+	g.AddTriple(NewResource("g"), NewResource("b"), NewResource("e"))
+
+	properties := g.Properties(NewResource("a"))
+	assert.Len(t, properties, 2)
+	for predicate, objects := range properties {
+		switch predicate.RawValue() {
+		case "b":
+			assert.ElementsMatch(t, []Term{NewResource("c"), NewResource("d")}, objects)
+		case "f":
+			assert.Equal(t, []Term{NewLiteral("h")}, objects)
+		default:
+			t.Errorf("unexpected predicate %s", predicate)
+		}
+	}
+	assert.Empty(t, g.Properties(NewResource("c")))
+	assert.Empty(t, g.Properties(nil))
+}
+
+func TestGraphReferrers(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("knows"), NewResource("c"))
+	g.AddTriple(NewResource("b"), NewResource("knows"), NewResource("c"))
+	g.AddTriple(NewResource("b"), NewResource("likes"), NewResource("c"))
+	g.AddTriple(NewResource("c"), NewResource("knows"), NewResource("a"))
+
+	assert.Len(t, g.Referrers(NewResource("c")), 3)
+	assert.Len(t, g.Referrers(NewResource("c"), NewResource("knows")), 2)
+	assert.Len(t, g.Referrers(NewResource("c"), NewResource("knows"), NewResource("likes")), 3)
+	assert.Equal(t, []*Triple{NewTriple(NewResource("c"), NewResource("knows"), NewResource("a"))}, g.Referrers(NewResource("a")))
+	assert.Empty(t, g.Referrers(NewResource("b")))
+	assert.Empty(t, g.Referrers(nil))
+}
+
 func TestGraphLoadURI(t *testing.T) {
 	uri := testServer.URL + "/foo#me"
 	g := NewGraph(uri)
@@ -147,6 +211,26 @@ func TestGraphLoadURINoSkip(t *testing.T) {
 	assert.Equal(t, 2, g.Len())
 }
 
+func TestGraphLoadURIContext(t *testing.T) {
+	uri := testServer.URL + "/foo#me"
+	g := NewGraph(uri)
+	err := g.LoadURIContext(context.Background(), uri)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, g.Len())
+}
+
+func TestGraphLoadURIContextTimeout(t *testing.T) {
+	uri := testServer.URL + "/slow"
+	g := NewGraph(uri)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := g.LoadURIContext(ctx, uri)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, 0, g.Len())
+}
+
 func TestParseFail(t *testing.T) {
 	g := NewGraph(testUri)
 	g.Parse(strings.NewReader(simpleTurtle), "text/plain")
@@ -263,3 +347,75 @@ func TestGraphSerializeTrig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, g.Len(), g2.Len())
 }
+
+func TestGraphTriples(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("e"))
+
+	count := 0
+	for triple := range g.Triples() {
+		assert.True(t, triple.Subject.Equal(NewResource("a")))
+		count++
+	}
+	assert.Equal(t, 3, count)
+
+	count = 0
+	for range g.Triples() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestGraphIterationOrder(t *testing.T) {
+	objects := []string{"d", "b", "e", "a", "c"}
+	g := NewGraphWithOptions(testUri, WithOrder(OrderInsertion))
+	for _, o := range objects {
+		g.AddTriple(NewResource("s"), NewResource("p"), NewResource(o))
+	}
+	g.Remove(g.One(nil, nil, NewResource("e")))
+	var got []string
+	for triple := range g.IterTriples() {
+		got = append(got, triple.Object.RawValue())
+	}
+	assert.Equal(t, []string{"d", "b", "a", "c"}, got)
+
+	g = NewGraphWithOptions(testUri, WithOrder(OrderSorted))
+	for _, o := range objects {
+		g.AddTriple(NewResource("s"), NewResource("p"), NewResource(o))
+	}
+	got = nil
+	for triple := range g.Triples() {
+		got = append(got, triple.Object.RawValue())
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, got)
+}
+
+func TestGraphThreadSafe(t *testing.T) {
+	g := NewGraphWithOptions(testUri, WithThreadSafe(true))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				g.AddTriple(NewResource(fmt.Sprintf("s%d", i)), NewResource("p"), NewLiteral(fmt.Sprint(j)))
+				g.One(NewResource(fmt.Sprintf("s%d", i)), nil, nil)
+				for range g.Triples() {
+					break
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 800, g.Len())
+
+	// lookups and iterations may modify the graph
+	for triple := range g.Triples() {
+		g.Remove(triple)
+	}
+	assert.Equal(t, 0, g.Len())
+	assert.NoError(t, g.Verify())
+}
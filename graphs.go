@@ -0,0 +1,88 @@
+package rdf2go
+
+// RemoveGraph removes the quads of a graph, nil being the default graph, as
+// the SPARQL DROP GRAPH operation does. When dryRun is true, the dataset is
+// left untouched and the returned change set lists the quads that would
+// have been removed.
+func (d *Dataset) RemoveGraph(name Term, dryRun ...bool) *ChangeSet {
+	return d.manageGraphs(dryRun, func(target *Dataset, tracker *changeTracker) {
+		target.clearGraph(name, tracker)
+	})
+}
+
+// ClearDefaultGraph removes the quads of the default graph, as the SPARQL
+// CLEAR DEFAULT operation does, see RemoveGraph
+func (d *Dataset) ClearDefaultGraph(dryRun ...bool) *ChangeSet {
+	return d.RemoveGraph(nil, dryRun...)
+}
+
+// RenameGraph moves the quads of graph from to graph to, replacing its
+// content, as the SPARQL MOVE operation does. nil stands for the default
+// graph. When dryRun is true, the dataset is left untouched and the
+// returned change set lists the quads that would have been added and
+// removed.
+func (d *Dataset) RenameGraph(from Term, to Term, dryRun ...bool) *ChangeSet {
+	return d.manageGraphs(dryRun, func(target *Dataset, tracker *changeTracker) {
+		target.transferGraph("MOVE", from, to, tracker)
+	})
+}
+
+// CopyGraph copies the quads of graph from to graph to, replacing its
+// content, as the SPARQL COPY operation does, see RenameGraph
+func (d *Dataset) CopyGraph(from Term, to Term, dryRun ...bool) *ChangeSet {
+	return d.manageGraphs(dryRun, func(target *Dataset, tracker *changeTracker) {
+		target.transferGraph("COPY", from, to, tracker)
+	})
+}
+
+// AddGraph adds the triples of g to a graph of the dataset, nil being the
+// default graph, skipping those it already holds, as the SPARQL ADD
+// operation does between graphs of a dataset, see RenameGraph
+func (d *Dataset) AddGraph(name Term, g *Graph, dryRun ...bool) *ChangeSet {
+	return d.manageGraphs(dryRun, func(target *Dataset, tracker *changeTracker) {
+		for triple := range g.Triples() {
+			target.insertValue(NewQuad(triple.Subject, triple.Predicate, triple.Object, name), tracker)
+		}
+	})
+}
+
+// manageGraphs applies a graph management operation to the dataset, or to
+// a copy of it for a dry run, and returns its changes
+func (d *Dataset) manageGraphs(dryRun []bool, op func(target *Dataset, tracker *changeTracker)) *ChangeSet {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	target := d
+	if c.DryRun {
+		target = d.clone()
+	}
+	tracker := newChangeTracker()
+	op(target, tracker)
+	tracker.fill(c)
+	return c
+}
+
+// clearGraph removes the quads of a graph
+func (d *Dataset) clearGraph(name Term, tracker *changeTracker) {
+	for _, quad := range d.All(nil, nil, nil, storedGraph(name)) {
+		d.Remove(quad)
+		tracker.remove(quad)
+	}
+}
+
+// transferGraph adds the quads of graph from to graph to for the ADD, COPY
+// and MOVE operations, clearing graph to first for COPY and MOVE, and graph
+// from afterwards for MOVE. Nothing happens when both graphs are the same.
+func (d *Dataset) transferGraph(kind string, from Term, to Term, tracker *changeTracker) {
+	if from == nil && to == nil || from != nil && to != nil && d.iriPolicy.Equal(from, to) {
+		return
+	}
+	quads := d.All(nil, nil, nil, storedGraph(from))
+	if kind != "ADD" {
+		d.clearGraph(to, tracker)
+	}
+	for _, quad := range quads {
+		d.insertValue(NewQuad(quad.Subject, quad.Predicate, quad.Object, to), tracker)
+	}
+	if kind == "MOVE" {
+		d.clearGraph(from, tracker)
+	}
+}
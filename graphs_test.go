@@ -0,0 +1,46 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetGraphManagement(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(ex.Get("a"), ex.Get("p"), NewLiteral("default"), nil)
+	d.AddQuad(ex.Get("a"), ex.Get("p"), NewLiteral("one"), ex.Get("g1"))
+	d.AddQuad(ex.Get("a"), ex.Get("p"), NewLiteral("two"), ex.Get("g2"))
+
+	// copying replaces the destination
+	c := d.CopyGraph(ex.Get("g1"), ex.Get("g2"))
+	assert.Len(t, c.Added, 1)
+	assert.Len(t, c.Removed, 1)
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("one"), ex.Get("g2")))
+	assert.Nil(t, d.One(nil, nil, NewLiteral("two"), nil))
+
+	// renaming moves the quads, and dry runs leave the dataset untouched
+	c = d.RenameGraph(ex.Get("g1"), ex.Get("g3"), true)
+	assert.True(t, c.DryRun)
+	assert.Len(t, d.All(nil, nil, nil, ex.Get("g1")), 1)
+	d.RenameGraph(ex.Get("g1"), ex.Get("g3"))
+	assert.Empty(t, d.All(nil, nil, nil, ex.Get("g1")))
+	assert.Len(t, d.All(nil, nil, nil, ex.Get("g3")), 1)
+	assert.Equal(t, 0, d.RenameGraph(ex.Get("g3"), ex.Get("g3")).Len())
+
+	// adding a graph skips the statements already there
+	g := NewGraph(testUri)
+	g.AddTriple(ex.Get("a"), ex.Get("p"), NewLiteral("one"))
+	g.AddTriple(ex.Get("a"), ex.Get("p"), NewLiteral("more"))
+	c = d.AddGraph(ex.Get("g3"), g)
+	assert.Len(t, c.Added, 1)
+	assert.Len(t, d.All(nil, nil, nil, ex.Get("g3")), 2)
+
+	c = d.RemoveGraph(ex.Get("g3"))
+	assert.Len(t, c.Removed, 2)
+	c = d.ClearDefaultGraph()
+	assert.Len(t, c.Removed, 1)
+	assert.Equal(t, 1, d.Len())
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("one"), ex.Get("g2")))
+}
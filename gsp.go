@@ -0,0 +1,217 @@
+package rdf2go
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+	"sync"
+)
+
+// graphStoreMimes lists the formats served by a GraphStoreHandler, in order
+// of preference
+var graphStoreMimes = []string{
+	"text/turtle",
+	"application/n-triples",
+	"application/ld+json",
+	"application/trig",
+	"application/n-quads",
+}
+
+// GraphStoreHandler is an http.Handler implementing the SPARQL 1.1 Graph
+// Store HTTP Protocol over a Dataset. Graphs are identified either
+// indirectly, with the ?default or ?graph=<IRI> query parameters, or directly
+// by the request path, resolved against the URI of the dataset.
+//
+// GET and HEAD return the graph in the format negotiated from the Accept
+// header, PUT replaces its content, POST merges the request body into it,
+// PATCH applies an LD Patch document (text/ldpatch) to it and DELETE removes
+// it.
+type GraphStoreHandler struct {
+	// Dataset holding the graphs of the store
+	Dataset *Dataset
+	// MaxBytes limits the size of the request body
+	MaxBytes int64
+
+	mu sync.RWMutex
+}
+
+// NewGraphStoreHandler returns a GraphStoreHandler serving the graphs of the
+// given dataset
+func NewGraphStoreHandler(d *Dataset) *GraphStoreHandler {
+	return &GraphStoreHandler{
+		Dataset:  d,
+		MaxBytes: defaultIngestMaxBytes,
+	}
+}
+
+// graphName returns the graph targeted by a request, nil being the default graph
+func (h *GraphStoreHandler) graphName(req *http.Request) (Term, error) {
+	query := req.URL.Query()
+	_, isDefault := query["default"]
+	graph := query.Get("graph")
+	if isDefault && len(graph) > 0 {
+		return nil, errors.New("the default and graph parameters are mutually exclusive")
+	}
+	if isDefault {
+		return nil, nil
+	}
+	if len(graph) > 0 {
+		if !isAbsoluteIRI(graph) {
+			return nil, errors.New("the graph parameter must be an absolute IRI")
+		}
+		return NewResource(graph), nil
+	}
+	return NewResource(resolveIRI(h.Dataset.URI(), req.URL.Path)), nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *GraphStoreHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	graph, err := h.graphName(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		h.serveGraph(w, req, graph)
+	case http.MethodPut, http.MethodPost:
+		h.storeGraph(w, req, graph)
+	case http.MethodPatch:
+		h.patchGraph(w, req, graph)
+	case http.MethodDelete:
+		h.mu.Lock()
+		quads := h.Dataset.All(nil, nil, nil, storedGraph(graph))
+		for _, quad := range quads {
+			h.Dataset.Remove(quad)
+		}
+		h.mu.Unlock()
+		if graph != nil && len(quads) == 0 {
+			http.Error(w, "graph not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST, PATCH, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *GraphStoreHandler) serveGraph(w http.ResponseWriter, req *http.Request, graph Term) {
+	mediaType, ok := negotiate(w, req, graphStoreMimes)
+	if !ok {
+		return
+	}
+
+	h.mu.RLock()
+	exists := graph == nil || h.Dataset.One(nil, nil, nil, storedGraph(graph)) != nil
+	g := h.Dataset.GetGraph(storedGraph(graph))
+	h.mu.RUnlock()
+	if !exists {
+		http.Error(w, "graph not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Accept-Patch", "text/ldpatch")
+	writeRDF(w, req, mediaType, g.Serialize)
+}
+
+func (h *GraphStoreHandler) storeGraph(w http.ResponseWriter, req *http.Request, graph Term) {
+	if h.MaxBytes > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, h.MaxBytes)
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, "invalid Content-Type: "+err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if parser, ok := mimeParser[mediaType]; !ok || parser == "internal" {
+		http.Error(w, "unsupported content type "+mediaType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	base := h.Dataset.URI()
+	if graph != nil {
+		base = graph.RawValue()
+	}
+	staged := NewGraph(base)
+	// the charset of the body is given by the parameters of its content type
+	if err := staged.Parse(req.Body, req.Header.Get("Content-Type")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	existed := graph == nil || h.Dataset.One(nil, nil, nil, storedGraph(graph)) != nil
+	if req.Method == http.MethodPut {
+		for _, quad := range h.Dataset.All(nil, nil, nil, storedGraph(graph)) {
+			h.Dataset.Remove(quad)
+		}
+	}
+	for triple := range staged.Triples() {
+		if h.Dataset.One(triple.Subject, triple.Predicate, triple.Object, storedGraph(graph)) == nil {
+			h.Dataset.AddQuad(triple.Subject, triple.Predicate, triple.Object, graph)
+		}
+	}
+	h.mu.Unlock()
+
+	if !existed {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// patchGraph applies an LD Patch document to a graph. Malformed documents are
+// rejected with 400 Bad Request, and documents that cannot be applied, such
+// as a Bind matching no node, with 422 Unprocessable Entity.
+func (h *GraphStoreHandler) patchGraph(w http.ResponseWriter, req *http.Request, graph Term) {
+	if h.MaxBytes > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, h.MaxBytes)
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/ldpatch" {
+		w.Header().Set("Accept-Patch", "text/ldpatch")
+		http.Error(w, "unsupported patch format", http.StatusUnsupportedMediaType)
+		return
+	}
+	body := new(bytes.Buffer)
+	if _, err := body.ReadFrom(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	existed := graph == nil || h.Dataset.One(nil, nil, nil, storedGraph(graph)) != nil
+	g := h.Dataset.GetGraph(storedGraph(graph))
+	if graph != nil {
+		// relative IRIs of the patch are resolved against the graph
+		g.uri = graph.RawValue()
+	}
+	c, err := g.Patch(body.String())
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		var syntaxErr *syntaxError
+		if errors.As(err, &syntaxErr) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	for _, quad := range c.Removed {
+		for _, match := range h.Dataset.All(quad.Subject, quad.Predicate, quad.Object, storedGraph(graph)) {
+			h.Dataset.Remove(match)
+		}
+	}
+	for _, quad := range c.Added {
+		h.Dataset.AddQuad(quad.Subject, quad.Predicate, quad.Object, graph)
+	}
+
+	if !existed && len(c.Added) > 0 {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
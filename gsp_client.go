@@ -0,0 +1,152 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// GraphStoreClient reads and writes the graphs of a remote SPARQL 1.1 Graph
+// Store Protocol endpoint, such as those of Fuseki or Oxigraph, using
+// indirect graph identification (?default and ?graph=<IRI>).
+type GraphStoreClient struct {
+	// Endpoint is the URL of the graph store service
+	Endpoint string
+	// Format is the media type used to send graphs, Turtle by default
+	Format string
+
+	httpClient *http.Client
+}
+
+// NewGraphStoreClient creates a GraphStoreClient for the given endpoint
+func NewGraphStoreClient(endpoint string, skipVerify ...bool) *GraphStoreClient {
+	skip := false
+	if len(skipVerify) > 0 {
+		skip = skipVerify[0]
+	}
+	return &GraphStoreClient{
+		Endpoint:   endpoint,
+		Format:     "text/turtle",
+		httpClient: NewHttpClient(skip),
+	}
+}
+
+// graphURL returns the URL addressing a graph, nil being the default graph
+func (c *GraphStoreClient) graphURL(graph Term) (string, error) {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	if graph == nil {
+		u.RawQuery = query.Encode()
+		if len(u.RawQuery) > 0 {
+			u.RawQuery += "&default"
+		} else {
+			u.RawQuery = "default"
+		}
+		return u.String(), nil
+	}
+	query.Set("graph", graph.RawValue())
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (c *GraphStoreClient) do(ctx context.Context, method string, graph Term, body io.Reader, contentType string) (*http.Response, error) {
+	target, err := c.graphURL(graph)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Accept", "text/turtle;q=1,application/n-triples;q=0.9,application/ld+json;q=0.5")
+	}
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		r.Body.Close()
+		return nil, fmt.Errorf("%s %s failed - HTTP %d", method, target, r.StatusCode)
+	}
+	return r, nil
+}
+
+// GetGraph fetches a graph from the store, nil being the default graph
+func (c *GraphStoreClient) GetGraph(ctx context.Context, graph Term) (*Graph, error) {
+	r, err := c.do(ctx, http.MethodGet, graph, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	base := c.Endpoint
+	if graph != nil {
+		base = graph.RawValue()
+	}
+	g := NewGraph(base)
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Parse(r.Body, mediaType); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// send writes a graph with the given method
+func (c *GraphStoreClient) send(ctx context.Context, method string, graph Term, g *Graph) error {
+	buf := new(bytes.Buffer)
+	if err := g.Serialize(buf, c.Format); err != nil {
+		return err
+	}
+	r, err := c.do(ctx, method, graph, buf, c.Format)
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// PutGraph replaces the content of a graph of the store with g
+func (c *GraphStoreClient) PutGraph(ctx context.Context, graph Term, g *Graph) error {
+	return c.send(ctx, http.MethodPut, graph, g)
+}
+
+// PostGraph merges g into a graph of the store
+func (c *GraphStoreClient) PostGraph(ctx context.Context, graph Term, g *Graph) error {
+	return c.send(ctx, http.MethodPost, graph, g)
+}
+
+// DeleteGraph removes a graph from the store
+func (c *GraphStoreClient) DeleteGraph(ctx context.Context, graph Term) error {
+	r, err := c.do(ctx, http.MethodDelete, graph, nil, "")
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// PutDataset replaces every graph of the store that is present in the
+// dataset, including the default graph, with its content in the dataset.
+// Other graphs of the store are left untouched.
+func (c *GraphStoreClient) PutDataset(ctx context.Context, d *Dataset) error {
+	if err := c.PutGraph(ctx, nil, d.GetGraph(DefaultGraph)); err != nil {
+		return err
+	}
+	for _, name := range d.GetNamedGraphs() {
+		if err := c.PutGraph(ctx, name, d.GetGraph(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
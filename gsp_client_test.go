@@ -0,0 +1,70 @@
+package rdf2go
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphStoreClient(t *testing.T) {
+	remote := NewDataset(testDatasetUri)
+	server := httptest.NewServer(NewGraphStoreHandler(remote))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewGraphStoreClient(server.URL + "/store")
+	g1 := NewResource("http://example.org/g1")
+
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteralWithLanguage("c", "en"))
+	assert.NoError(t, c.PutGraph(ctx, g1, g))
+	assert.Equal(t, 1, len(remote.All(nil, nil, nil, g1)))
+
+	g = NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/d"))
+	assert.NoError(t, c.PostGraph(ctx, g1, g))
+	assert.Equal(t, 2, len(remote.All(nil, nil, nil, g1)))
+
+	fetched, err := c.GetGraph(ctx, g1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetched.Len())
+	assert.NotNil(t, fetched.One(nil, nil, NewLiteralWithLanguage("c", "en")))
+
+	c.Format = "application/n-triples"
+	assert.NoError(t, c.PutGraph(ctx, nil, g))
+	assert.Equal(t, 1, len(remote.All(nil, nil, nil, nil)))
+
+	assert.NoError(t, c.DeleteGraph(ctx, g1))
+	assert.Equal(t, 1, remote.Len())
+	assert.Error(t, c.DeleteGraph(ctx, g1))
+	_, err = c.GetGraph(ctx, g1)
+	assert.Error(t, err)
+}
+
+func TestGraphStoreClientPutDataset(t *testing.T) {
+	remote := NewDataset(testDatasetUri)
+	server := httptest.NewServer(NewGraphStoreHandler(remote))
+	defer server.Close()
+
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("x"), NewResource("http://example.org/g1"))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("y"), NewResource("http://example.org/g2"))
+
+	c := NewGraphStoreClient(server.URL)
+	assert.NoError(t, c.PutDataset(context.Background(), d))
+	assert.Equal(t, 3, remote.Len())
+	assert.Equal(t, 2, len(remote.GetNamedGraphs()))
+}
+
+func TestGraphStoreClientGraphURL(t *testing.T) {
+	c := NewGraphStoreClient("http://example.org/ds/data?x=1")
+	u, err := c.graphURL(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://example.org/ds/data?x=1&default", u)
+	u, err = c.graphURL(NewResource("http://example.org/g#1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://example.org/ds/data?graph=http%3A%2F%2Fexample.org%2Fg%231&x=1", u)
+}
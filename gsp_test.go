@@ -0,0 +1,106 @@
+package rdf2go
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gspRequest(h http.Handler, method string, target string, contentType string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGraphStoreHandlerIndirect(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewGraphStoreHandler(d)
+	target := "/store?graph=http%3A%2F%2Fexample.org%2Fg1"
+
+	rec := gspRequest(h, "GET", target, "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = gspRequest(h, "PUT", target, "text/turtle", simpleTurtle)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	g1 := NewResource("http://example.org/g1")
+	assert.Equal(t, 2, len(d.All(nil, nil, nil, g1)))
+	// relative IRIs are resolved against the graph IRI
+	assert.NotNil(t, d.One(NewResource("http://example.org/g1#me"), nil, nil, g1))
+
+	rec = gspRequest(h, "POST", target, "application/n-triples", "<http://example.org/a> <http://example.org/b> \"c\" .\n")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 3, len(d.All(nil, nil, nil, g1)))
+
+	rec = gspRequest(h, "PUT", target, "application/n-triples", "<http://example.org/a> <http://example.org/b> \"d\" .\n")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 1, len(d.All(nil, nil, nil, g1)))
+
+	req := httptest.NewRequest("GET", target, nil)
+	req.Header.Set("Accept", "application/n-triples, text/turtle;q=0.5")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/n-triples", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"d"`)
+
+	rec = gspRequest(h, "DELETE", target, "", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 0, d.Len())
+	rec = gspRequest(h, "DELETE", target, "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGraphStoreHandlerDirectAndDefault(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewGraphStoreHandler(d)
+
+	rec := gspRequest(h, "POST", "/graphs/people", "text/turtle", simpleTurtle)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, len(d.All(nil, nil, nil, NewResource("https://example.org/graphs/people"))))
+
+	rec = gspRequest(h, "GET", "/store?default", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/turtle", rec.Header().Get("Content-Type"))
+
+	rec = gspRequest(h, "PUT", "/store?default", "application/n-triples", "<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 1, len(d.All(nil, nil, nil, nil)))
+}
+
+func TestGraphStoreHandlerErrors(t *testing.T) {
+	h := NewGraphStoreHandler(NewDataset(testDatasetUri))
+
+	rec := gspRequest(h, "GET", "/store?default&graph=http%3A%2F%2Fexample.org%2Fg", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = gspRequest(h, "GET", "/store?graph=relative", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = gspRequest(h, "PUT", "/store?default", "text/plain", "x")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	rec = gspRequest(h, "PUT", "/store?default", "application/n-triples", "<a> <b> .\n")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = gspRequest(h, "OPTIONS", "/store?default", "", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	req := httptest.NewRequest("GET", "/store?default", nil)
+	req.Header.Set("Accept", "image/png")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func TestNegotiateMime(t *testing.T) {
+	offers := []string{"text/turtle", "application/ld+json"}
+	assert.Equal(t, "text/turtle", negotiateMime("", offers))
+	assert.Equal(t, "application/ld+json", negotiateMime("application/ld+json", offers))
+	assert.Equal(t, "application/ld+json", negotiateMime("text/*;q=0.2, application/ld+json;q=0.9", offers))
+	assert.Equal(t, "text/turtle", negotiateMime("*/*", offers))
+	assert.Equal(t, "application/ld+json", negotiateMime("*/*;q=0.5, text/turtle;q=0", offers))
+	assert.Equal(t, "", negotiateMime("image/png", offers))
+}
@@ -0,0 +1,86 @@
+package rdf2go
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// Hook is a callback registered by OnAdd or OnRemove, invoked synchronously
+// right after each change of the graph or dataset, which it may modify.
+// Like subscriptions, hooks must be canceled when they are no longer needed.
+type Hook struct {
+	cancel func()
+}
+
+// Cancel unregisters the hook. It is safe to call from within the callback.
+func (h *Hook) Cancel() {
+	h.cancel()
+}
+
+// graphHook is a hook registered on a graph
+type graphHook struct {
+	kind     ChangeKind
+	fn       func(*Triple)
+	canceled atomic.Bool
+}
+
+// OnAdd registers a function called with each triple added to the graph
+func (g *Graph) OnAdd(fn func(*Triple)) *Hook {
+	return g.hook(QuadAdded, fn)
+}
+
+// OnRemove registers a function called with each triple removed from the
+// graph
+func (g *Graph) OnRemove(fn func(*Triple)) *Hook {
+	return g.hook(QuadRemoved, fn)
+}
+
+func (g *Graph) hook(kind ChangeKind, fn func(*Triple)) *Hook {
+	h := &graphHook{kind: kind, fn: fn}
+	unlock := g.lock()
+	g.hooks = append(g.hooks, h)
+	unlock()
+	return &Hook{cancel: func() {
+		if h.canceled.Swap(true) {
+			return
+		}
+		defer g.lock()()
+		// the hooks being notified are left untouched
+		g.hooks = slices.DeleteFunc(slices.Clone(g.hooks), func(other *graphHook) bool {
+			return other == h
+		})
+	}}
+}
+
+// notify invokes the hooks of a change of the graph
+func (g *Graph) notify(kind ChangeKind, t *Triple) {
+	unlock := g.rlock()
+	hooks := g.hooks
+	unlock()
+	for _, h := range hooks {
+		if h.kind == kind && !h.canceled.Load() {
+			h.fn(t)
+		}
+	}
+}
+
+// OnAdd registers a function called with each quad added to the dataset, in
+// any graph
+func (d *Dataset) OnAdd(fn func(*Quad)) *Hook {
+	return d.hook(QuadAdded, fn)
+}
+
+// OnRemove registers a function called with each quad removed from the
+// dataset, in any graph
+func (d *Dataset) OnRemove(fn func(*Quad)) *Hook {
+	return d.hook(QuadRemoved, fn)
+}
+
+func (d *Dataset) hook(kind ChangeKind, fn func(*Quad)) *Hook {
+	sub := d.Subscribe(nil, nil, nil, AnyGraph, func(k ChangeKind, q *Quad) {
+		if k == kind {
+			fn(q)
+		}
+	})
+	return &Hook{cancel: sub.Cancel}
+}
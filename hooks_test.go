@@ -0,0 +1,69 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphHooks(t *testing.T) {
+	g := NewGraph(testUri)
+	var added, removed []*Triple
+	onAdd := g.OnAdd(func(triple *Triple) {
+		added = append(added, triple)
+	})
+	g.OnRemove(func(triple *Triple) {
+		removed = append(removed, triple)
+	})
+
+	triple := NewTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.Add(triple)
+	g.Add(triple)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	assert.Len(t, added, 2)
+	g.Remove(triple)
+	g.Remove(triple)
+	assert.Equal(t, []*Triple{triple}, removed)
+
+	onAdd.Cancel()
+	onAdd.Cancel()
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("e"))
+	assert.Len(t, added, 2)
+}
+
+func TestGraphHookModifiesGraph(t *testing.T) {
+	g := NewGraphWithOptions(testUri, WithThreadSafe(true))
+	label := NewResource("http://www.w3.org/2000/01/rdf-schema#label")
+	var hook *Hook
+	hook = g.OnAdd(func(triple *Triple) {
+		if !triple.Predicate.Equal(label) {
+			g.AddTriple(triple.Subject, label, NewLiteral(triple.Subject.RawValue()))
+		}
+		hook.Cancel()
+	})
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.AddTriple(NewResource("d"), NewResource("b"), NewResource("c"))
+	assert.Equal(t, 3, g.Len())
+}
+
+func TestDatasetHooks(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	var added, removed []*Quad
+	d.OnAdd(func(quad *Quad) {
+		added = append(added, quad)
+	})
+	onRemove := d.OnRemove(func(quad *Quad) {
+		removed = append(removed, quad)
+	})
+
+	quad := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
+	d.Add(quad)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	assert.Len(t, added, 2)
+	d.Remove(quad)
+	assert.Equal(t, []*Quad{quad}, removed)
+
+	onRemove.Cancel()
+	d.RemoveMatching(nil, nil, nil, nil)
+	assert.Len(t, removed, 1)
+}
@@ -0,0 +1,315 @@
+package rdf2go
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// defaultHTMLMaxNodes is the number of nodes drawn by ExportHTML when no cap
+// is configured; force layouts become unreadable well before a few hundred
+const defaultHTMLMaxNodes = 500
+
+// HTMLOptions configures the HTML visualization produced by ExportHTML
+type HTMLOptions struct {
+	// Title is the title of the page
+	Title string
+	// Prefixes maps prefixes to namespaces, used to shorten IRIs in labels
+	// in addition to the prefixes of the configuration
+	Prefixes map[string]string
+	// MaxNodes caps the number of nodes drawn; statements introducing new
+	// nodes past the cap are left out. Zero means 500, a negative value
+	// disables the cap.
+	MaxNodes int
+}
+
+// htmlNode and htmlEdge follow the node and edge format of vis-network, so
+// that the embedded data can also be fed to it directly
+type htmlNode struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+	Title string `json:"title"`
+	Group string `json:"group"`
+}
+
+type htmlEdge struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Label string `json:"label"`
+	Title string `json:"title"`
+	Graph string `json:"graph,omitempty"`
+}
+
+type htmlData struct {
+	Nodes     []htmlNode `json:"nodes"`
+	Edges     []htmlEdge `json:"edges"`
+	Truncated bool       `json:"truncated"`
+}
+
+// ExportHTML writes the graph to w as a standalone HTML page drawing its
+// nodes and edges with an interactive force layout (drag to move nodes,
+// scroll to zoom). It needs no external scripts, so that small graphs can be
+// inspected in any browser. IRIs are shortened with prefixes and resources
+// are labeled with their rdfs:label when they have one. opts may be nil.
+func (g *Graph) ExportHTML(w io.Writer, opts *HTMLOptions) error {
+	var quads []*Quad
+	for t := range g.Triples() {
+		quads = append(quads, NewTripleQuad(t))
+	}
+	sortQuads(quads)
+	if opts == nil || len(opts.Title) == 0 {
+		opts = withHTMLTitle(opts, g.uri)
+	}
+	return exportHTML(w, quads, g.config.Prefixes, opts)
+}
+
+// ExportHTML writes the dataset to w as a standalone HTML page, see
+// Graph.ExportHTML. Edges of named graphs are tagged with the graph name.
+func (d *Dataset) ExportHTML(w io.Writer, opts *HTMLOptions) error {
+	if opts == nil || len(opts.Title) == 0 {
+		opts = withHTMLTitle(opts, d.uri)
+	}
+	return exportHTML(w, d.orderedQuads(), d.config.Prefixes, opts)
+}
+
+func withHTMLTitle(opts *HTMLOptions, title string) *HTMLOptions {
+	c := HTMLOptions{}
+	if opts != nil {
+		c = *opts
+	}
+	c.Title = title
+	return &c
+}
+
+func exportHTML(w io.Writer, quads []*Quad, prefixes map[string]string, opts *HTMLOptions) error {
+	return htmlTemplate.Execute(w, struct {
+		Title string
+		Data  *htmlData
+	}{opts.Title, buildHTMLData(quads, prefixes, opts)})
+}
+
+// buildHTMLData turns statements into nodes and edges. Every literal gets a
+// node of its own, so that shared values do not pull unrelated resources
+// together.
+func buildHTMLData(quads []*Quad, prefixes map[string]string, opts *HTMLOptions) *htmlData {
+	maxNodes := opts.MaxNodes
+	if maxNodes == 0 {
+		maxNodes = defaultHTMLMaxNodes
+	}
+	namespaces := make(map[string]string, len(prefixes)+len(opts.Prefixes))
+	for prefix, ns := range prefixes {
+		namespaces[ns] = prefix
+	}
+	for prefix, ns := range opts.Prefixes {
+		namespaces[ns] = prefix
+	}
+
+	labels := make(map[string]string)
+	rdfsLabel := NewResource(rdfsNamespace + "label")
+	for _, q := range quads {
+		if lit, ok := q.Object.(*Literal); ok && q.Predicate.Equal(rdfsLabel) {
+			if _, ok := labels[termKey(q.Subject)]; !ok {
+				labels[termKey(q.Subject)] = lit.Value
+			}
+		}
+	}
+
+	data := &htmlData{Nodes: []htmlNode{}, Edges: []htmlEdge{}}
+	ids := make(map[string]int)
+	addNode := func(t Term) int {
+		if _, ok := t.(*Literal); !ok {
+			if id, ok := ids[termKey(t)]; ok {
+				return id
+			}
+		}
+		node := htmlNode{ID: len(data.Nodes), Title: t.String(), Group: termKind(t)}
+		switch t := t.(type) {
+		case *Literal:
+			node.Label = t.Value
+		default:
+			if label, ok := labels[termKey(t)]; ok {
+				node.Label = label
+			} else {
+				node.Label = compactIRI(namespaces, t)
+			}
+			ids[termKey(t)] = node.ID
+		}
+		data.Nodes = append(data.Nodes, node)
+		return node.ID
+	}
+	// newNodes counts the nodes a statement would add to the drawing
+	newNodes := func(q *Quad) int {
+		n := 0
+		if _, ok := ids[termKey(q.Subject)]; !ok {
+			n++
+		}
+		if _, ok := q.Object.(*Literal); ok {
+			n++
+		} else if _, ok := ids[termKey(q.Object)]; !ok && !q.Object.Equal(q.Subject) {
+			n++
+		}
+		return n
+	}
+
+	for _, q := range quads {
+		if maxNodes > 0 && len(data.Nodes)+newNodes(q) > maxNodes {
+			data.Truncated = true
+			continue
+		}
+		edge := htmlEdge{
+			From:  addNode(q.Subject),
+			To:    addNode(q.Object),
+			Label: compactIRI(namespaces, q.Predicate),
+			Title: q.Predicate.String(),
+		}
+		if q.Graph != nil {
+			edge.Graph = compactIRI(namespaces, q.Graph)
+		}
+		data.Edges = append(data.Edges, edge)
+	}
+	return data
+}
+
+// compactIRI shortens an IRI with the longest matching namespace. Other
+// terms are returned in their N-Triples form.
+func compactIRI(namespaces map[string]string, t Term) string {
+	r, ok := t.(*Resource)
+	if !ok {
+		return t.String()
+	}
+	best := ""
+	for ns := range namespaces {
+		if len(ns) > len(best) && strings.HasPrefix(r.URI, ns) {
+			best = ns
+		}
+	}
+	if len(best) == 0 {
+		return r.String()
+	}
+	return namespaces[best] + ":" + r.URI[len(best):]
+}
+
+var htmlTemplate = template.Must(template.New("graph").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  html, body { margin: 0; height: 100%; font: 12px sans-serif; }
+  svg { width: 100%; height: 100%; display: block; cursor: grab; }
+  #info { position: absolute; top: 8px; left: 8px; color: #555; }
+  .edge line { stroke: #999; }
+  .edge text { fill: #777; }
+  .node circle { stroke: #fff; stroke-width: 1.5px; }
+  .node text { fill: #222; pointer-events: none; }
+  .iri circle { fill: #4e79a7; }
+  .bnode circle { fill: #bab0ac; }
+  .literal circle { fill: #f28e2b; }
+</style>
+</head>
+<body>
+<div id="info"></div>
+<svg id="graph"><defs><marker id="arrow" viewBox="0 0 10 10" refX="18" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0L10,5L0,10z" fill="#999"/></marker></defs><g id="view"></g></svg>
+<script>
+const data = {{.Data}};
+(function () {
+  const svgNS = "http://www.w3.org/2000/svg";
+  const svg = document.getElementById("graph");
+  const view = document.getElementById("view");
+  const info = document.getElementById("info");
+  info.textContent = data.nodes.length + " nodes, " + data.edges.length + " edges" +
+    (data.truncated ? " (truncated)" : "");
+
+  const el = (name, attrs, parent) => {
+    const e = document.createElementNS(svgNS, name);
+    for (const k in attrs) e.setAttribute(k, attrs[k]);
+    parent.appendChild(e);
+    return e;
+  };
+  const tooltip = (e, text) => { el("title", {}, e).textContent = text; };
+
+  const nodes = data.nodes.map((n, i) => Object.assign({}, n, {
+    x: 300 * Math.cos(i), y: 300 * Math.sin(i), vx: 0, vy: 0
+  }));
+  const edges = data.edges.map(e => {
+    const g = el("g", {class: "edge"}, view);
+    const line = el("line", {"marker-end": "url(#arrow)"}, g);
+    const text = el("text", {"text-anchor": "middle"}, g);
+    text.textContent = e.label;
+    tooltip(g, e.title + (e.graph ? " in " + e.graph : ""));
+    return {source: nodes[e.from], target: nodes[e.to], line: line, text: text};
+  });
+  nodes.forEach(n => {
+    n.g = el("g", {class: "node " + n.group}, view);
+    el("circle", {r: n.group === "literal" ? 5 : 8}, n.g);
+    const text = el("text", {x: 10, y: 4}, n.g);
+    text.textContent = n.label;
+    tooltip(n.g, n.title);
+    n.g.addEventListener("pointerdown", ev => { ev.stopPropagation(); dragged = n; alpha = 1; });
+  });
+
+  // force layout: repulsion between nodes, springs along edges, gravity
+  let alpha = 1, dragged = null;
+  function tick() {
+    for (let i = 0; i < nodes.length; i++) {
+      for (let j = i + 1; j < nodes.length; j++) {
+        const a = nodes[i], b = nodes[j];
+        let dx = b.x - a.x, dy = b.y - a.y, d2 = dx * dx + dy * dy || 1;
+        const f = 800 / d2;
+        a.vx -= dx * f; a.vy -= dy * f; b.vx += dx * f; b.vy += dy * f;
+      }
+    }
+    edges.forEach(e => {
+      const dx = e.target.x - e.source.x, dy = e.target.y - e.source.y;
+      const d = Math.sqrt(dx * dx + dy * dy) || 1, f = (d - 80) / d * 0.05;
+      e.source.vx += dx * f; e.source.vy += dy * f;
+      e.target.vx -= dx * f; e.target.vy -= dy * f;
+    });
+    nodes.forEach(n => {
+      n.vx -= n.x * 0.002; n.vy -= n.y * 0.002;
+      if (n !== dragged) { n.x += n.vx * alpha; n.y += n.vy * alpha; }
+      n.vx *= 0.6; n.vy *= 0.6;
+    });
+  }
+  function draw() {
+    nodes.forEach(n => n.g.setAttribute("transform", "translate(" + n.x + "," + n.y + ")"));
+    edges.forEach(e => {
+      e.line.setAttribute("x1", e.source.x); e.line.setAttribute("y1", e.source.y);
+      e.line.setAttribute("x2", e.target.x); e.line.setAttribute("y2", e.target.y);
+      e.text.setAttribute("x", (e.source.x + e.target.x) / 2);
+      e.text.setAttribute("y", (e.source.y + e.target.y) / 2);
+    });
+  }
+  function frame() {
+    if (alpha > 0.01) { tick(); draw(); alpha *= 0.99; }
+    requestAnimationFrame(frame);
+  }
+
+  // panning, zooming and dragging
+  let scale = 1, tx = 0, ty = 0, panning = null;
+  const transform = () => view.setAttribute("transform", "translate(" + tx + "," + ty + ") scale(" + scale + ")");
+  const resize = () => { tx = svg.clientWidth / 2; ty = svg.clientHeight / 2; transform(); };
+  svg.addEventListener("pointerdown", ev => { panning = {x: ev.clientX - tx, y: ev.clientY - ty}; });
+  svg.addEventListener("pointermove", ev => {
+    if (dragged) {
+      dragged.x = (ev.clientX - tx) / scale; dragged.y = (ev.clientY - ty) / scale;
+      alpha = Math.max(alpha, 0.3); draw();
+    } else if (panning) {
+      tx = ev.clientX - panning.x; ty = ev.clientY - panning.y; transform();
+    }
+  });
+  window.addEventListener("pointerup", () => { dragged = null; panning = null; });
+  svg.addEventListener("wheel", ev => {
+    ev.preventDefault();
+    const k = Math.exp(-ev.deltaY * 0.001);
+    tx = ev.clientX - (ev.clientX - tx) * k; ty = ev.clientY - (ev.clientY - ty) * k;
+    scale *= k; transform();
+  }, {passive: false});
+  window.addEventListener("resize", resize);
+  resize();
+  frame();
+})();
+</script>
+</body>
+</html>
+`))
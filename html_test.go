@@ -0,0 +1,99 @@
+package rdf2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// embeddedHTMLData extracts the graph data embedded in an exported page
+func embeddedHTMLData(t *testing.T, page string) *htmlData {
+	start := strings.Index(page, "const data = ")
+	assert.True(t, start >= 0)
+	start += len("const data = ")
+	end := strings.Index(page[start:], ";\n")
+	data := &htmlData{}
+	assert.NoError(t, json.Unmarshal([]byte(page[start:start+end]), data))
+	return data
+}
+
+func TestGraphExportHTML(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(simpleTurtle), "text/turtle"))
+	g.AddTriple(g.One(nil, nil, NewLiteral("Test")).Subject, NewResource("http://www.w3.org/2000/01/rdf-schema#label"), NewLiteral("Me"))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.ExportHTML(buf, &HTMLOptions{Prefixes: map[string]string{"foaf": "http://xmlns.com/foaf/0.1/"}}))
+	page := buf.String()
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	assert.Contains(t, page, "<title>"+testUri+"</title>")
+
+	data := embeddedHTMLData(t, page)
+	assert.False(t, data.Truncated)
+	// the subject, the class and two literals
+	assert.Equal(t, 4, len(data.Nodes))
+	assert.Equal(t, 3, len(data.Edges))
+	var nodes []string
+	for _, n := range data.Nodes {
+		nodes = append(nodes, n.Group+" "+n.Label)
+	}
+	// the subject is labeled with its rdfs:label
+	assert.ElementsMatch(t, []string{"iri Me", "iri foaf:Person", "literal Test", "literal Me"}, nodes)
+	var predicates []string
+	for _, e := range data.Edges {
+		predicates = append(predicates, e.Label)
+	}
+	assert.ElementsMatch(t, []string{"rdf:type", "foaf:name", "rdfs:label"}, predicates)
+
+	// Serialize produces the same page
+	buf.Reset()
+	assert.NoError(t, g.Serialize(buf, "text/html"))
+	assert.Contains(t, buf.String(), `"label":"rdf:type"`)
+}
+
+func TestExportHTMLMaxNodes(t *testing.T) {
+	g := NewGraph("")
+	hub := NewResource("http://example.org/hub")
+	p := NewResource("http://example.org/p")
+	for i := 0; i < 10; i++ {
+		g.AddTriple(hub, p, NewResource("http://example.org/n"+string(rune('0'+i))))
+	}
+	g.AddTriple(hub, p, hub)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.ExportHTML(buf, &HTMLOptions{Title: "hub", MaxNodes: 4}))
+	data := embeddedHTMLData(t, buf.String())
+	assert.True(t, data.Truncated)
+	assert.Equal(t, 4, len(data.Nodes))
+	// the self loop does not add a node
+	assert.Equal(t, 4, len(data.Edges))
+	assert.Contains(t, buf.String(), "<title>hub</title>")
+
+	buf.Reset()
+	assert.NoError(t, g.ExportHTML(buf, &HTMLOptions{MaxNodes: -1}))
+	data = embeddedHTMLData(t, buf.String())
+	assert.False(t, data.Truncated)
+	assert.Equal(t, 11, len(data.Nodes))
+}
+
+func TestDatasetExportHTML(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewBlankNode("b"), NewResource("http://example.org/g"))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/q"), NewLiteral("</script><script>alert(1)</script>"), nil)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, d.Serialize(buf, "text/html"))
+	page := buf.String()
+	// literals cannot break out of the script element
+	assert.NotContains(t, page, "<script>alert(1)")
+
+	data := embeddedHTMLData(t, page)
+	assert.Equal(t, 3, len(data.Nodes))
+	assert.Equal(t, "bnode", data.Nodes[1].Group)
+	assert.Equal(t, "<http://example.org/g>", data.Edges[0].Graph)
+	assert.Equal(t, "", data.Edges[1].Graph)
+	assert.Equal(t, "</script><script>alert(1)</script>", data.Nodes[2].Label)
+}
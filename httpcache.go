@@ -0,0 +1,119 @@
+package rdf2go
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// HTTPCache stores the documents fetched over HTTP by LoadURI along with
+// their validators, so that loading them again issues a conditional request
+// (If-None-Match or If-Modified-Since) and parses the cached copy when the
+// server reports it unchanged. Only responses carrying an ETag or a
+// Last-Modified header, and parsed without error, are cached.
+type HTTPCache interface {
+	// Get returns the document cached for uri
+	Get(uri string) (*CachedDocument, bool)
+	// Put caches the document fetched from uri
+	Put(uri string, doc *CachedDocument)
+}
+
+// CachedDocument is a document fetched over HTTP
+type CachedDocument struct {
+	// URL is the URL the document was read from, after redirects
+	URL string
+	// Header holds the headers of the response, among which its validators
+	Header http.Header
+	// Body is the content of the document
+	Body []byte
+}
+
+// MemoryCache is an HTTPCache holding documents in memory, evicting the
+// least recently used ones beyond its size limit. It is safe for
+// concurrent use.
+type MemoryCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// memoryEntry is an element of the eviction order of a MemoryCache
+type memoryEntry struct {
+	uri string
+	doc *CachedDocument
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxBytes of
+// document bodies, zero meaning no limit
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements HTTPCache
+func (c *MemoryCache) Get(uri string) (*CachedDocument, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[uri]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*memoryEntry).doc, true
+}
+
+// Put implements HTTPCache. Documents larger than the size limit are not
+// cached.
+func (c *MemoryCache) Put(uri string, doc *CachedDocument) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[uri]; ok {
+		c.remove(e)
+	}
+	if c.maxBytes > 0 && int64(len(doc.Body)) > c.maxBytes {
+		return
+	}
+	c.entries[uri] = c.order.PushFront(&memoryEntry{uri: uri, doc: doc})
+	c.size += int64(len(doc.Body))
+	for c.maxBytes > 0 && c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of cached documents
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *MemoryCache) remove(e *list.Element) {
+	entry := c.order.Remove(e).(*memoryEntry)
+	delete(c.entries, entry.uri)
+	c.size -= int64(len(entry.doc.Body))
+}
+
+// setConditional adds the validators of a cached document to a request
+func setConditional(q *http.Request, doc *CachedDocument) {
+	if etag := doc.Header.Get("ETag"); len(etag) > 0 {
+		q.Header.Set("If-None-Match", etag)
+	}
+	if modified := doc.Header.Get("Last-Modified"); len(modified) > 0 {
+		q.Header.Set("If-Modified-Since", modified)
+	}
+}
+
+// cacheable tells whether a response can be revalidated and may be stored
+func cacheable(header http.Header) bool {
+	if strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	return len(header.Get("ETag")) > 0 || len(header.Get("Last-Modified")) > 0
+}
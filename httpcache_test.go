@@ -0,0 +1,75 @@
+package rdf2go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCache(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/plain" {
+			w.Header().Set("Content-Type", "text/turtle")
+			w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/turtle")
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c>, <http://example.org/d> .\n"))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(0)
+	g := NewGraphWithOptions("", WithHTTPCache(cache))
+	res, err := g.LoadURIWithResult(context.Background(), server.URL+"/vocab")
+	assert.NoError(t, err)
+	assert.False(t, res.Cached)
+	assert.Equal(t, 1, cache.Len())
+
+	// the second load is revalidated, and parses the cached copy
+	g = NewGraphWithOptions("", WithHTTPCache(cache))
+	res, err = g.LoadURIWithResult(context.Background(), server.URL+"/vocab#term")
+	assert.NoError(t, err)
+	assert.True(t, res.Cached)
+	assert.Equal(t, `"v1"`, res.ETag)
+	assert.Equal(t, 2, g.Len())
+	assert.Equal(t, 1, notModified)
+
+	// responses without validators are not cached
+	assert.NoError(t, g.LoadURI(server.URL+"/plain"))
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, 3, requests)
+
+	// documents are not cached without a cache
+	assert.NoError(t, NewGraph("").LoadURI(server.URL+"/vocab"))
+	assert.Equal(t, 1, notModified)
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCache(10)
+	cache.Put("a", &CachedDocument{Body: []byte("12345")})
+	cache.Put("b", &CachedDocument{Body: []byte("12345")})
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	// b is the least recently used document
+	cache.Put("c", &CachedDocument{Body: []byte("123")})
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	// documents larger than the limit are not cached
+	cache.Put("a", &CachedDocument{Body: []byte("12345678901")})
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,171 @@
+package rdf2go
+
+// termIndexKey returns the key under which a term is stored in the indexes.
+// The second return value is false when the term cannot be looked up by key,
+// e.g. literals whose datatype has a registered comparator; such terms are
+// matched by scanning with Equal instead.
+func termIndexKey(t Term) (string, bool) {
+	if t == nil {
+		return "", true
+	}
+	if hasComparator(t) {
+		return "", false
+	}
+	return t.String(), true
+}
+
+// termKey returns the key of a stored term, regardless of whether it can be
+// used for lookups.
+func termKey(t Term) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+type keySet[T comparable] map[T]struct{}
+
+// spoIndex stores statements under their subject, predicate and object keys
+// in three permutations, so that any pattern with at least one bound term can
+// be answered without scanning unrelated statements.
+type spoIndex[T comparable] struct {
+	spo  map[string]map[string]map[string]keySet[T]
+	pos  map[string]map[string]map[string]keySet[T]
+	osp  map[string]map[string]map[string]keySet[T]
+	size int
+}
+
+func newSPOIndex[T comparable]() *spoIndex[T] {
+	return &spoIndex[T]{
+		spo: make(map[string]map[string]map[string]keySet[T]),
+		pos: make(map[string]map[string]map[string]keySet[T]),
+		osp: make(map[string]map[string]map[string]keySet[T]),
+	}
+}
+
+func indexInsert[T comparable](m map[string]map[string]map[string]keySet[T], a, b, c string, v T) bool {
+	l1, ok := m[a]
+	if !ok {
+		l1 = make(map[string]map[string]keySet[T])
+		m[a] = l1
+	}
+	l2, ok := l1[b]
+	if !ok {
+		l2 = make(map[string]keySet[T])
+		l1[b] = l2
+	}
+	set, ok := l2[c]
+	if !ok {
+		set = make(keySet[T])
+		l2[c] = set
+	}
+	if _, exists := set[v]; exists {
+		return false
+	}
+	set[v] = struct{}{}
+	return true
+}
+
+func indexDelete[T comparable](m map[string]map[string]map[string]keySet[T], a, b, c string, v T) bool {
+	set, ok := m[a][b][c]
+	if !ok {
+		return false
+	}
+	if _, exists := set[v]; !exists {
+		return false
+	}
+	delete(set, v)
+	if len(set) == 0 {
+		delete(m[a][b], c)
+		if len(m[a][b]) == 0 {
+			delete(m[a], b)
+			if len(m[a]) == 0 {
+				delete(m, a)
+			}
+		}
+	}
+	return true
+}
+
+func (x *spoIndex[T]) add(s, p, o string, v T) {
+	if indexInsert(x.spo, s, p, o, v) {
+		indexInsert(x.pos, p, o, s, v)
+		indexInsert(x.osp, o, s, p, v)
+		x.size++
+	}
+}
+
+func (x *spoIndex[T]) remove(s, p, o string, v T) {
+	if indexDelete(x.spo, s, p, o, v) {
+		indexDelete(x.pos, p, o, s, v)
+		indexDelete(x.osp, o, s, p, v)
+		x.size--
+	}
+}
+
+// match calls fn for every statement stored under the given keys, where an
+// unbound key is passed as bound=false. It stops as soon as fn returns false
+// and reports whether the iteration ran to completion.
+func (x *spoIndex[T]) match(s string, sb bool, p string, pb bool, o string, ob bool, fn func(T) bool) bool {
+	switch {
+	case sb && pb && ob:
+		return visitSet(x.spo[s][p][o], fn)
+	case sb && pb:
+		return visitLevel(x.spo[s][p], fn)
+	case sb && ob:
+		return visitLevel(x.osp[o][s], fn)
+	case sb:
+		return visitTree(x.spo[s], fn)
+	case pb && ob:
+		return visitLevel(x.pos[p][o], fn)
+	case pb:
+		return visitTree(x.pos[p], fn)
+	case ob:
+		return visitTree(x.osp[o], fn)
+	}
+	for _, l1 := range x.spo {
+		if !visitTree(l1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func visitSet[T comparable](set keySet[T], fn func(T) bool) bool {
+	for v := range set {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func visitLevel[T comparable](level map[string]keySet[T], fn func(T) bool) bool {
+	for _, set := range level {
+		if !visitSet(set, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func visitTree[T comparable](tree map[string]map[string]keySet[T], fn func(T) bool) bool {
+	for _, level := range tree {
+		if !visitLevel(level, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// graphIndex is the index of a single graph within a dataset
+type graphIndex struct {
+	term Term
+	idx  *spoIndex[*Quad]
+}
+
+// matchTerm returns whether a statement term satisfies a pattern term, where
+// a nil pattern term matches anything
+func matchTerm(pattern Term, t Term) bool {
+	return pattern == nil || (t != nil && t.Equal(pattern))
+}
@@ -0,0 +1,86 @@
+package rdf2go
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphIndexedMatchesUnindexed(t *testing.T) {
+	indexed := NewGraph(testUri)
+	plain := NewUnindexedGraph(testUri)
+	for i := 0; i < 20; i++ {
+		s := NewResource(fmt.Sprintf("s%d", i%4))
+		p := NewResource(fmt.Sprintf("p%d", i%3))
+		o := NewLiteral(fmt.Sprintf("o%d", i%5))
+		indexed.AddTriple(s, p, o)
+		plain.AddTriple(s, p, o)
+	}
+
+	patterns := [][3]Term{
+		{NewResource("s1"), nil, nil},
+		{nil, NewResource("p2"), nil},
+		{nil, nil, NewLiteral("o3")},
+		{NewResource("s1"), NewResource("p1"), nil},
+		{NewResource("s2"), nil, NewLiteral("o2")},
+		{nil, NewResource("p0"), NewLiteral("o0")},
+		{NewResource("s3"), NewResource("p0"), NewLiteral("o3")},
+		{NewResource("missing"), nil, nil},
+	}
+	for _, pat := range patterns {
+		assert.Equal(t, len(plain.All(pat[0], pat[1], pat[2])), len(indexed.All(pat[0], pat[1], pat[2])), "pattern %v", pat)
+	}
+}
+
+func TestGraphIndexRemove(t *testing.T) {
+	g := NewGraph(testUri)
+	triple := NewTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	g.Add(triple)
+	g.Add(triple)
+	assert.Equal(t, 1, g.Len())
+
+	g.Remove(triple)
+	assert.Nil(t, g.One(NewResource("a"), nil, nil))
+	assert.Nil(t, g.One(nil, nil, NewResource("c")))
+	assert.Empty(t, g.index.spo)
+	assert.Empty(t, g.index.pos)
+	assert.Empty(t, g.index.osp)
+}
+
+func TestGraphOneSubjectObject(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	assert.Nil(t, g.One(NewResource("a"), nil, NewResource("d")))
+	assert.NotNil(t, g.One(NewResource("a"), nil, NewResource("c")))
+}
+
+func TestDatasetIndexedMatchesUnindexed(t *testing.T) {
+	indexed := NewDataset(testDatasetUri)
+	plain := NewUnindexedDataset(testDatasetUri)
+	graphs := []Term{nil, NewResource("g1"), NewResource("g2")}
+	for i := 0; i < 30; i++ {
+		s := NewResource(fmt.Sprintf("s%d", i%4))
+		p := NewResource(fmt.Sprintf("p%d", i%3))
+		o := NewLiteral(fmt.Sprintf("o%d", i%5))
+		indexed.AddQuad(s, p, o, graphs[i%3])
+		plain.AddQuad(s, p, o, graphs[i%3])
+	}
+
+	for _, g := range graphs {
+		assert.Equal(t, len(plain.All(nil, nil, nil, g)), len(indexed.All(nil, nil, nil, g)))
+		assert.Equal(t, len(plain.All(NewResource("s1"), nil, nil, g)), len(indexed.All(NewResource("s1"), nil, nil, g)))
+		assert.Equal(t, len(plain.All(nil, NewResource("p1"), NewLiteral("o1"), g)), len(indexed.All(nil, NewResource("p1"), NewLiteral("o1"), g)))
+	}
+	assert.Equal(t, len(plain.GetNamedGraphs()), len(indexed.GetNamedGraphs()))
+}
+
+func TestDatasetIndexRemoveGraph(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	quad := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
+	d.Add(quad)
+	assert.Equal(t, 1, len(d.GetNamedGraphs()))
+	d.Remove(quad)
+	assert.Equal(t, 0, len(d.GetNamedGraphs()))
+	assert.Nil(t, d.One(NewResource("a"), nil, nil, NewResource("g")))
+}
@@ -0,0 +1,176 @@
+package rdf2go
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"sync"
+)
+
+// defaultIngestMaxBytes is the default request body limit of an IngestHandler
+const defaultIngestMaxBytes = 32 << 20
+
+// IngestHandler is an http.Handler that ingests RDF documents into a Dataset.
+// It accepts either a multipart/form-data request carrying several RDF files,
+// or a single RDF document in the request body (e.g. a batch of N-Quads).
+//
+// Ingestion is all-or-nothing: every part is parsed first, under the
+// configuration of the dataset, and quads are only added to the dataset, in
+// a single transaction, when all parts parsed successfully. The quads the
+// dataset already holds are not added again. The response is
+// a JSON IngestReport describing the outcome of each part.
+type IngestHandler struct {
+	// Dataset receiving the ingested quads
+	Dataset *Dataset
+	// MaxBytes limits the size of the request body
+	MaxBytes int64
+
+	mu sync.Mutex
+}
+
+// IngestReport describes the outcome of an ingestion request
+type IngestReport struct {
+	Committed bool `json:"committed"`
+	// Added counts the quads added to the dataset, leaving out those it
+	// already held
+	Added int                `json:"added"`
+	Parts []IngestPartReport `json:"parts"`
+}
+
+// IngestPartReport describes the outcome of ingesting a single part
+type IngestPartReport struct {
+	Name        string `json:"name,omitempty"`
+	ContentType string `json:"contentType"`
+	Quads       int    `json:"quads"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NewIngestHandler returns an IngestHandler adding quads to the given dataset
+func NewIngestHandler(d *Dataset) *IngestHandler {
+	return &IngestHandler{
+		Dataset:  d,
+		MaxBytes: defaultIngestMaxBytes,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		w.Header().Set("Allow", "POST, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.MaxBytes > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, h.MaxBytes)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, "invalid Content-Type: "+err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report := &IngestReport{}
+	var staged []*Dataset
+	if mediaType == "multipart/form-data" || mediaType == "multipart/mixed" {
+		staged, err = h.stageMultipart(req, params["boundary"], report)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		part := h.stage(req.Body, "", mediaType, report)
+		staged = append(staged, part)
+	}
+
+	status := http.StatusOK
+	for _, part := range report.Parts {
+		if len(part.Error) > 0 {
+			status = http.StatusBadRequest
+		}
+	}
+	if len(report.Parts) == 0 {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusOK {
+		// the parts are added in one transaction, so that readers of the
+		// dataset see all of them or none, leaving out the quads the dataset
+		// already holds and those repeated across parts
+		h.mu.Lock()
+		tx := h.Dataset.Begin()
+		seen := make(map[string]bool)
+		for _, d := range staged {
+			for _, quad := range d.orderedQuads() {
+				key := quad.String()
+				if seen[key] || h.Dataset.One(quad.Subject, quad.Predicate, quad.Object, storedGraph(quad.Graph)) != nil {
+					continue
+				}
+				seen[key] = true
+				tx.Add(quad)
+			}
+		}
+		changes, err := tx.Commit()
+		h.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		report.Added = len(changes.Added)
+		report.Committed = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *IngestHandler) stageMultipart(req *http.Request, boundary string, report *IngestReport) ([]*Dataset, error) {
+	if len(boundary) == 0 {
+		return nil, errors.New("missing multipart boundary")
+	}
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	var staged []*Dataset
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return staged, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := part.FileName()
+		if len(name) == 0 {
+			name = part.FormName()
+		}
+		contentType := part.Header.Get("Content-Type")
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+			contentType = mediaType
+		}
+		if len(contentType) == 0 || contentType == "application/octet-stream" {
+			contentType = mimeRdfExt[path.Ext(name)]
+		}
+		staged = append(staged, h.stage(part, name, contentType, report))
+		part.Close()
+	}
+}
+
+// stage parses a single document into a new dataset configured like the
+// target one, recording the outcome in report
+func (h *IngestHandler) stage(r io.Reader, name string, contentType string, report *IngestReport) *Dataset {
+	d := h.Dataset.empty()
+	result := IngestPartReport{Name: name, ContentType: contentType}
+	if _, ok := mimeParser[contentType]; !ok {
+		result.Error = "unsupported content type " + contentType
+	} else if err := d.Parse(r, contentType); err != nil {
+		result.Error = err.Error()
+	}
+	result.Quads = d.Len()
+	report.Parts = append(report.Parts, result)
+	return d
+}
@@ -0,0 +1,141 @@
+package rdf2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func multipartBody(t *testing.T, files map[string]string) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for name, content := range files {
+		hdr := textproto.MIMEHeader{}
+		hdr.Set("Content-Disposition", `form-data; name="file"; filename="`+name+`"`)
+		part, err := mw.CreatePart(hdr)
+		assert.NoError(t, err)
+		part.Write([]byte(content))
+	}
+	assert.NoError(t, mw.Close())
+	return body, mw.FormDataContentType()
+}
+
+func TestIngestHandlerMultipart(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewIngestHandler(d)
+
+	body, contentType := multipartBody(t, map[string]string{
+		"data.nq":  simpleNQuads,
+		"data.ttl": simpleTurtle,
+	})
+	req := httptest.NewRequest("POST", "/ingest", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var report IngestReport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.True(t, report.Committed)
+	assert.Equal(t, 2, len(report.Parts))
+	assert.Equal(t, 6, report.Added)
+	assert.Equal(t, 6, d.Len())
+}
+
+func TestIngestHandlerMultipartFailure(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewIngestHandler(d)
+
+	body, contentType := multipartBody(t, map[string]string{
+		"good.nq": simpleNQuads,
+		"bad.nq":  "<a> <b> .\n",
+	})
+	req := httptest.NewRequest("POST", "/ingest", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var report IngestReport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.False(t, report.Committed)
+	for _, part := range report.Parts {
+		if part.Name == "bad.nq" {
+			assert.Contains(t, part.Error, "line 1")
+		} else {
+			assert.Empty(t, part.Error)
+		}
+	}
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestIngestHandlerBatch(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewIngestHandler(d)
+
+	req := httptest.NewRequest("POST", "/ingest", strings.NewReader(simpleNQuads))
+	req.Header.Set("Content-Type", "application/n-quads")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 4, d.Len())
+
+	req = httptest.NewRequest("POST", "/ingest", strings.NewReader(simpleNQuads))
+	req.Header.Set("Content-Type", "text/csv")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest("GET", "/ingest", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestIngestHandlerAdded(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	h := NewIngestHandler(d)
+	nquads := "<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n<http://example.org/a> <http://example.org/b> \"d\" <http://example.org/g> .\n"
+
+	// quads already in the dataset, or repeated across parts, are not counted
+	body, contentType := multipartBody(t, map[string]string{
+		"a.nq": nquads,
+		"b.nq": nquads,
+	})
+	req := httptest.NewRequest("POST", "/ingest", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var report IngestReport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Added)
+
+	req = httptest.NewRequest("POST", "/ingest", strings.NewReader(nquads))
+	req.Header.Set("Content-Type", "application/n-quads")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.True(t, report.Committed)
+	assert.Equal(t, 0, report.Added)
+	assert.Equal(t, 2, d.Len())
+}
+
+func TestIngestHandlerConfig(t *testing.T) {
+	// the parts are parsed under the limits of the dataset
+	d := NewDatasetWithOptions(testDatasetUri, WithParseLimits(ParseLimits{MaxStatements: 2}))
+	h := NewIngestHandler(d)
+
+	req := httptest.NewRequest("POST", "/ingest", strings.NewReader(simpleNQuads))
+	req.Header.Set("Content-Type", "application/n-quads")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 0, d.Len())
+}
@@ -0,0 +1,114 @@
+package rdf2go
+
+import "sync"
+
+// TermDictionary interns terms: it holds one instance of each distinct term,
+// with an ID, so that the statements of a graph or dataset created with
+// WithInternTerms share their equal terms instead of repeating the same IRIs
+// and literals, which saves memory and garbage collection work on large
+// graphs. Interned terms are equal if and only if they are the same pointer,
+// or have the same ID. Terms stay in the dictionary when the statements
+// using them are removed. A TermDictionary is safe for concurrent use.
+type TermDictionary struct {
+	mu    sync.RWMutex
+	ids   map[string]uint64
+	terms []Term
+}
+
+// NewTermDictionary creates an empty TermDictionary
+func NewTermDictionary() *TermDictionary {
+	return &TermDictionary{ids: make(map[string]uint64)}
+}
+
+// Intern returns the instance of the dictionary equal to a term, adding the
+// term if needed. The datatypes of literals and the terms of quoted triples
+// are interned as well. Nil and terms other than IRIs, literals, blank nodes
+// and quoted triples are returned as is.
+func (dict *TermDictionary) Intern(t Term) Term {
+	switch t.(type) {
+	case *Resource, *Literal, *BlankNode, *QuotedTriple:
+	default:
+		return t
+	}
+	key := t.String()
+	dict.mu.RLock()
+	id, ok := dict.ids[key]
+	dict.mu.RUnlock()
+	if ok {
+		return dict.Term(id)
+	}
+
+	switch term := t.(type) {
+	case *Literal:
+		if term.Datatype != nil {
+			if datatype := dict.Intern(term.Datatype); datatype != term.Datatype {
+				t = &Literal{Value: term.Value, Language: term.Language, Datatype: datatype}
+			}
+		}
+	case *QuotedTriple:
+		t = &QuotedTriple{Subject: dict.Intern(term.Subject), Predicate: dict.Intern(term.Predicate), Object: dict.Intern(term.Object)}
+	}
+	dict.mu.Lock()
+	defer dict.mu.Unlock()
+	if id, ok := dict.ids[key]; ok {
+		return dict.terms[id-1]
+	}
+	dict.terms = append(dict.terms, t)
+	dict.ids[key] = uint64(len(dict.terms))
+	return t
+}
+
+// ID returns the ID of a term, from 1, telling whether the dictionary holds
+// it
+func (dict *TermDictionary) ID(t Term) (uint64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+	id, ok := dict.ids[t.String()]
+	return id, ok
+}
+
+// Term returns the term of an ID, nil when unknown
+func (dict *TermDictionary) Term(id uint64) Term {
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+	if id == 0 || id > uint64(len(dict.terms)) {
+		return nil
+	}
+	return dict.terms[id-1]
+}
+
+// Len returns the number of terms of the dictionary
+func (dict *TermDictionary) Len() int {
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+	return len(dict.terms)
+}
+
+// internTriple replaces the terms of a triple by their interned instances
+func (dict *TermDictionary) internTriple(t *Triple) {
+	if dict != nil {
+		t.Subject, t.Predicate, t.Object = dict.Intern(t.Subject), dict.Intern(t.Predicate), dict.Intern(t.Object)
+	}
+}
+
+// internQuad replaces the terms of a quad by their interned instances
+func (dict *TermDictionary) internQuad(q *Quad) {
+	if dict != nil {
+		q.Subject, q.Predicate, q.Object, q.Graph = dict.Intern(q.Subject), dict.Intern(q.Predicate), dict.Intern(q.Object), dict.Intern(q.Graph)
+	}
+}
+
+// Terms returns the dictionary interning the terms of the graph, nil unless
+// the graph was created with WithInternTerms
+func (g *Graph) Terms() *TermDictionary {
+	return g.dict
+}
+
+// Terms returns the dictionary interning the terms of the dataset, nil
+// unless the dataset was created with WithInternTerms
+func (d *Dataset) Terms() *TermDictionary {
+	return d.dict
+}
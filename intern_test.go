@@ -0,0 +1,60 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermDictionary(t *testing.T) {
+	dict := NewTermDictionary()
+	xsdInt := NewResource("http://www.w3.org/2001/XMLSchema#integer")
+	a := dict.Intern(NewResource("http://example.org/a"))
+	assert.True(t, a == dict.Intern(NewResource("http://example.org/a")))
+	assert.False(t, a == dict.Intern(NewBlankNode("http://example.org/a")))
+
+	lit := dict.Intern(NewLiteralWithDatatype("1", NewResource("http://www.w3.org/2001/XMLSchema#integer")))
+	assert.True(t, lit.(*Literal).Datatype == dict.Intern(xsdInt))
+	assert.True(t, lit == dict.Intern(NewLiteralWithDatatype("1", xsdInt)))
+	quoted := dict.Intern(NewQuotedTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x")))
+	assert.True(t, quoted.(*QuotedTriple).Subject == a)
+
+	id, ok := dict.ID(NewResource("http://example.org/a"))
+	assert.True(t, ok)
+	assert.True(t, dict.Term(id) == a)
+	_, ok = dict.ID(NewResource("http://example.org/missing"))
+	assert.False(t, ok)
+	assert.Nil(t, dict.Term(0))
+	assert.Nil(t, dict.Term(100))
+	assert.Nil(t, dict.Intern(nil))
+	assert.True(t, dict.Intern(DefaultGraph) == DefaultGraph)
+	assert.Equal(t, 7, dict.Len())
+}
+
+func TestGraphInternTerms(t *testing.T) {
+	g := NewGraphWithOptions(testUri, WithInternTerms(true))
+	assert.NoError(t, g.Parse(strings.NewReader(`
+<http://example.org/a> <http://example.org/p> <http://example.org/b> .
+<http://example.org/b> <http://example.org/p> <http://example.org/a> .
+`), "text/turtle"))
+	a := g.One(NewResource("http://example.org/a"), nil, nil)
+	b := g.One(NewResource("http://example.org/b"), nil, nil)
+	assert.True(t, a.Subject == b.Object)
+	assert.True(t, a.Predicate == b.Predicate)
+	assert.Equal(t, 3, g.Terms().Len())
+	assert.Nil(t, NewGraph(testUri).Terms())
+}
+
+func TestDatasetInternTerms(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithInternTerms(true))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), NewResource("http://example.org/g"))
+	d.AddQuad(NewResource("http://example.org/g"), NewResource("http://example.org/p"), NewLiteral("x"), nil)
+	quads := d.All(nil, nil, nil, AnyGraph)
+	assert.Len(t, quads, 2)
+	assert.True(t, quads[0].Object == quads[1].Object)
+	g := d.One(nil, nil, nil, NewResource("http://example.org/g")).Graph
+	assert.True(t, g == d.One(nil, nil, nil, nil).Subject)
+	assert.Equal(t, 4, d.Terms().Len())
+	assert.Nil(t, NewDataset(testDatasetUri).Terms())
+}
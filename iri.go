@@ -0,0 +1,74 @@
+package rdf2go
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var iriScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.\-]*:`)
+
+// isAbsoluteIRI returns whether an IRI starts with a scheme
+func isAbsoluteIRI(iri string) bool {
+	return iriScheme.MatchString(iri)
+}
+
+// resolveIRI resolves a relative IRI reference against a base IRI. Absolute
+// IRIs are returned unchanged.
+func resolveIRI(base string, iri string) string {
+	if len(base) == 0 || isAbsoluteIRI(iri) {
+		return iri
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return iri
+	}
+	r, err := url.Parse(iri)
+	if err != nil {
+		return iri
+	}
+	resolved := b.ResolveReference(r).String()
+	// net/url drops empty fragments, as in <#>
+	if strings.HasSuffix(iri, "#") && len(r.Fragment) == 0 {
+		resolved += "#"
+	}
+	return resolved
+}
+
+// relativeIRI returns an IRI reference relative to a base IRI that resolves
+// to iri: a fragment, a path relative to the directory of the base, or an
+// absolute path for IRIs of the same authority. Other IRIs are returned
+// unchanged.
+func relativeIRI(base string, iri string) string {
+	if len(base) == 0 || !isAbsoluteIRI(iri) {
+		return iri
+	}
+	base, _, _ = strings.Cut(base, "#")
+	b, err := url.Parse(base)
+	if err != nil || len(b.Host) == 0 || len(b.RawQuery) > 0 {
+		return iri
+	}
+	origin := b.Scheme + "://" + b.Host
+	var candidates []string
+	if rest, ok := strings.CutPrefix(iri, base); ok && (len(rest) == 0 || rest[0] == '#') {
+		candidates = append(candidates, rest)
+	}
+	if dir := base[:strings.LastIndex(base, "/")+1]; len(dir) > len(origin) {
+		if rest, ok := strings.CutPrefix(iri, dir); ok {
+			segment, _, _ := strings.Cut(rest, "/")
+			if len(rest) == 0 || strings.Contains(segment, ":") || rest[0] == '?' || rest[0] == '#' {
+				rest = "./" + rest
+			}
+			candidates = append(candidates, rest)
+		}
+	}
+	if rest, ok := strings.CutPrefix(iri, origin); ok && strings.HasPrefix(rest, "/") && !strings.HasPrefix(rest, "//") {
+		candidates = append(candidates, rest)
+	}
+	for _, rel := range candidates {
+		if resolveIRI(base, rel) == iri {
+			return rel
+		}
+	}
+	return iri
+}
@@ -0,0 +1,198 @@
+package rdf2go
+
+import (
+	"strings"
+)
+
+// IRIPolicy relaxes the equality of IRIs, which RDF otherwise compares
+// character by character, and optionally of literals. It is opt-in, per graph
+// or dataset (see Graph.SetIRIPolicy and Dataset.SetIRIPolicy), and helps when
+// merging real-world data that spells the same IRI or value in slightly
+// different ways.
+//
+// Under a policy, pattern matching treats equivalent IRIs as equal, and
+// adding a statement equivalent to one already present has no effect. The
+// stored terms are not rewritten.
+type IRIPolicy struct {
+	// CaseInsensitiveHost compares the scheme and host case insensitively
+	CaseInsensitiveHost bool
+	// IgnoreTrailingSlash treats http://example.org/a/ as http://example.org/a
+	IgnoreTrailingSlash bool
+	// NormalizePercentEncoding decodes %-encoded unreserved characters and
+	// compares the hexadecimal digits of other escapes case insensitively
+	NormalizePercentEncoding bool
+
+	// LiteralValues compares literals by value rather than by lexical form:
+	// numbers of the xsd numeric datatypes are equal when they denote the
+	// same number ("1"^^xsd:integer, "01"^^xsd:integer and "1.0"^^xsd:decimal),
+	// booleans when they denote the same truth value, and simple literals
+	// equal xsd:string literals. Other literals are compared as usual.
+	LiteralValues bool
+}
+
+// LenientIRIPolicy returns a policy enabling every IRI normalization
+func LenientIRIPolicy() *IRIPolicy {
+	return &IRIPolicy{
+		CaseInsensitiveHost:      true,
+		IgnoreTrailingSlash:      true,
+		NormalizePercentEncoding: true,
+	}
+}
+
+// Normalize returns the form of an IRI that is compared under the policy
+func (p *IRIPolicy) Normalize(iri string) string {
+	if p == nil {
+		return iri
+	}
+	rest, fragment, hasFragment := strings.Cut(iri, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	var scheme, authority, path string
+	if i := strings.IndexByte(rest, ':'); i > 0 && isAbsoluteIRI(rest) {
+		scheme, rest = rest[:i+1], rest[i+1:]
+	}
+	if strings.HasPrefix(rest, "//") {
+		end := strings.IndexByte(rest[2:], '/')
+		if end < 0 {
+			end = len(rest) - 2
+		}
+		authority, path = rest[:end+2], rest[end+2:]
+	} else {
+		path = rest
+	}
+
+	if p.CaseInsensitiveHost {
+		scheme = strings.ToLower(scheme)
+		if at := strings.LastIndexByte(authority, '@'); at >= 0 {
+			authority = authority[:at+1] + strings.ToLower(authority[at+1:])
+		} else {
+			authority = strings.ToLower(authority)
+		}
+	}
+	if p.IgnoreTrailingSlash {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	var b strings.Builder
+	b.WriteString(scheme)
+	b.WriteString(authority)
+	b.WriteString(path)
+	if hasQuery {
+		b.WriteByte('?')
+		b.WriteString(query)
+	}
+	if hasFragment {
+		b.WriteByte('#')
+		b.WriteString(fragment)
+	}
+	if p.NormalizePercentEncoding {
+		return normalizePercentEncoding(b.String())
+	}
+	return b.String()
+}
+
+// normalizePercentEncoding decodes escaped unreserved characters and turns
+// the hexadecimal digits of the other escapes to upper case
+func normalizePercentEncoding(s string) string {
+	if strings.IndexByte(s, '%') < 0 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isAlphaNum(c) || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+	return c - '0'
+}
+
+// Equal returns whether two terms are equal under the policy
+func (p *IRIPolicy) Equal(a Term, b Term) bool {
+	if p != nil {
+		if ra, ok := a.(*Resource); ok {
+			rb, ok := b.(*Resource)
+			return ok && p.Normalize(ra.URI) == p.Normalize(rb.URI)
+		}
+		if qa, ok := a.(*QuotedTriple); ok {
+			qb, ok := b.(*QuotedTriple)
+			return ok && p.Equal(qa.Subject, qb.Subject) && p.Equal(qa.Predicate, qb.Predicate) && p.Equal(qa.Object, qb.Object)
+		}
+		if la, ok := a.(*Literal); ok && p.LiteralValues {
+			if ka, ok := literalValueKey(la); ok {
+				lb, ok := b.(*Literal)
+				if !ok {
+					return false
+				}
+				if kb, ok := literalValueKey(lb); ok {
+					return ka == kb
+				}
+			}
+		}
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
+
+// key returns the index key of a stored term
+func (p *IRIPolicy) key(t Term) string {
+	if k, ok := p.valueKey(t); ok {
+		return k
+	}
+	return termKey(t)
+}
+
+// indexKey returns the index key of a pattern term, see termIndexKey
+func (p *IRIPolicy) indexKey(t Term) (string, bool) {
+	if k, ok := p.valueKey(t); ok {
+		return k, true
+	}
+	return termIndexKey(t)
+}
+
+// valueKey returns the key shared by the terms equivalent to t under the
+// policy, if the policy relaxes the equality of t
+func (p *IRIPolicy) valueKey(t Term) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	switch t := t.(type) {
+	case *Resource:
+		return "<" + p.Normalize(t.URI) + ">", true
+	case *Literal:
+		if p.LiteralValues {
+			return literalValueKey(t)
+		}
+	case *QuotedTriple:
+		return "<< " + p.key(t.Subject) + " " + p.key(t.Predicate) + " " + p.key(t.Object) + " >>", true
+	}
+	return "", false
+}
+
+// match returns whether a statement term satisfies a pattern term, see matchTerm
+func (p *IRIPolicy) match(pattern Term, t Term) bool {
+	if p == nil {
+		return matchTerm(pattern, t)
+	}
+	return pattern == nil || (t != nil && p.Equal(t, pattern))
+}
@@ -0,0 +1,118 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIRIPolicyNormalize(t *testing.T) {
+	p := LenientIRIPolicy()
+	assert.Equal(t, "http://example.org/a", p.Normalize("HTTP://Example.ORG/a/"))
+	assert.Equal(t, "http://user@example.org:8080/A?q=1#F", p.Normalize("http://user@EXAMPLE.org:8080/A?q=1#F"))
+	assert.Equal(t, "http://example.org/~a%2Fb", p.Normalize("http://example.org/%7ea%2fb"))
+	assert.Equal(t, "http://example.org", p.Normalize("http://example.org/"))
+	assert.Equal(t, "urn:isbn:123", p.Normalize("URN:isbn:123"))
+
+	strictCase := &IRIPolicy{IgnoreTrailingSlash: true}
+	assert.Equal(t, "HTTP://Example.org/a", strictCase.Normalize("HTTP://Example.org/a/"))
+
+	var none *IRIPolicy
+	assert.Equal(t, "HTTP://Example.org/a/", none.Normalize("HTTP://Example.org/a/"))
+	assert.False(t, none.Equal(NewResource("http://a/"), NewResource("http://a")))
+	assert.True(t, p.Equal(NewResource("http://a/"), NewResource("http://a")))
+	assert.False(t, p.Equal(NewResource("http://a"), NewLiteral("http://a")))
+}
+
+func TestGraphIRIPolicy(t *testing.T) {
+	for _, g := range []*Graph{NewGraph(testUri), NewUnindexedGraph(testUri)} {
+		g.AddTriple(NewResource("http://example.org/a/"), NewResource("http://example.org/p"), NewLiteral("x"))
+		assert.Nil(t, g.One(NewResource("HTTP://EXAMPLE.org/a"), nil, nil))
+
+		g.SetIRIPolicy(LenientIRIPolicy())
+		assert.NotNil(t, g.One(NewResource("HTTP://EXAMPLE.org/a"), nil, nil))
+		assert.Equal(t, 1, len(g.All(nil, NewResource("http://example.org/p/"), nil)))
+
+		// equivalent triples are not added twice
+		g.AddTriple(NewResource("http://example.org/%61"), NewResource("http://example.org/p"), NewLiteral("x"))
+		assert.Equal(t, 1, g.Len())
+		assert.NoError(t, g.Verify())
+
+		g.SetIRIPolicy(nil)
+		assert.Nil(t, g.One(NewResource("HTTP://EXAMPLE.org/a"), nil, nil))
+		assert.NoError(t, g.Verify())
+	}
+}
+
+func TestDatasetIRIPolicy(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.SetIRIPolicy(&IRIPolicy{CaseInsensitiveHost: true})
+	d.AddQuad(NewResource("http://Example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), NewResource("http://EXAMPLE.org/g"))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), NewResource("http://example.org/g"))
+	assert.Equal(t, 1, d.Len())
+	assert.Equal(t, 1, len(d.All(nil, nil, nil, NewResource("http://example.ORG/g"))))
+	assert.Equal(t, 1, len(d.GetGraph(NewResource("http://example.org/g")).All(NewResource("http://example.org/a"), nil, nil)))
+	assert.NoError(t, d.Verify())
+
+	var seen int
+	d.Subscribe(NewResource("http://EXAMPLE.org/b"), nil, nil, nil, func(ChangeKind, *Quad) { seen++ })
+	d.AddTriple(NewResource("http://example.org/b"), NewResource("http://example.org/p"), NewLiteral("y"))
+	assert.Equal(t, 1, seen)
+}
+
+func TestIRIPolicyLiteralValues(t *testing.T) {
+	xsd := func(value string, datatype string) Term {
+		return NewLiteralWithDatatype(value, NewResource(xsdNamespace+datatype))
+	}
+	p := &IRIPolicy{LiteralValues: true}
+	for _, pair := range [][2]Term{
+		{xsd("1", "integer"), xsd("01", "integer")},
+		{xsd("1", "integer"), xsd("1.0", "decimal")},
+		{xsd("1", "int"), xsd("+1", "long")},
+		{xsd("1", "integer"), xsd("1E0", "double")},
+		{xsd("0.5", "decimal"), xsd("5e-1", "float")},
+		{xsd("INF", "double"), xsd("INF", "float")},
+		{xsd("1", "boolean"), xsd("true", "boolean")},
+		{NewLiteral("x"), xsd("x", "string")},
+	} {
+		assert.True(t, p.Equal(pair[0], pair[1]), "%s %s", pair[0], pair[1])
+		assert.False(t, (*IRIPolicy)(nil).Equal(pair[0], pair[1]), "%s %s", pair[0], pair[1])
+	}
+	for _, pair := range [][2]Term{
+		{xsd("1", "integer"), xsd("2", "integer")},
+		{xsd("0.1", "decimal"), xsd("0.1", "double")},
+		{xsd("1", "integer"), NewLiteral("1")},
+		{xsd("1", "integer"), xsd("1", "string")},
+		{xsd("1", "integer"), xsd("one", "integer")},
+		{xsd("NaN", "double"), xsd("NaN", "float")},
+		{NewLiteral("x"), NewLiteralWithLanguage("x", "en")},
+	} {
+		assert.False(t, p.Equal(pair[0], pair[1]), "%s %s", pair[0], pair[1])
+	}
+	// invalid lexical forms are still equal to themselves
+	assert.True(t, p.Equal(xsd("one", "integer"), xsd("one", "integer")))
+}
+
+func TestGraphIRIPolicyLiteralValues(t *testing.T) {
+	age := NewResource("http://example.org/age")
+	for _, g := range []*Graph{NewGraph(testUri), NewUnindexedGraph(testUri)} {
+		g.AddTriple(NewResource("http://example.org/a"), age, NewLiteralWithDatatype("01", NewResource(xsdNamespace+"integer")))
+		g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteral("A"))
+		assert.Nil(t, g.One(nil, age, NewLiteralWithDatatype("1.0", NewResource(xsdNamespace+"decimal"))))
+
+		g.SetIRIPolicy(&IRIPolicy{LiteralValues: true})
+		assert.NotNil(t, g.One(nil, age, NewLiteralWithDatatype("1.0", NewResource(xsdNamespace+"decimal"))))
+		assert.NotNil(t, g.One(nil, nil, NewLiteralWithDatatype("A", NewResource(xsdNamespace+"string"))))
+
+		// equal values are not added twice
+		g.AddTriple(NewResource("http://example.org/a"), age, NewLiteralWithDatatype("1", NewResource(xsdNamespace+"int")))
+		assert.Equal(t, 2, g.Len())
+		assert.NoError(t, g.Verify())
+	}
+
+	d := NewDataset(testDatasetUri)
+	d.SetIRIPolicy(&IRIPolicy{LiteralValues: true})
+	d.AddQuad(NewResource("http://example.org/a"), age, NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer")), NewResource("http://example.org/g"))
+	assert.Equal(t, 1, len(d.All(nil, nil, NewLiteralWithDatatype("1.00", NewResource(xsdNamespace+"decimal")), NewResource("http://example.org/g"))))
+	assert.NoError(t, d.Verify())
+}
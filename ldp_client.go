@@ -0,0 +1,276 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// LDPClient reads and writes the resources of a Linked Data Platform server
+// (https://www.w3.org/TR/ldp/), such as a Solid pod. Its requests go through
+// the HTTP settings of its configuration, from the client and timeout to the
+// retry policy and the request hooks, which can authenticate them.
+type LDPClient struct {
+	// Format is the media type used to send graphs, Turtle by default
+	Format string
+
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewLDPClient creates an LDPClient configured by the package-level
+// configuration and the given options
+func NewLDPClient(opts ...Option) *LDPClient {
+	config := newConfig(opts...)
+	return &LDPClient{
+		Format:     "text/turtle",
+		config:     config,
+		httpClient: config.client(),
+	}
+}
+
+// LDPResource describes a resource of an LDP server
+type LDPResource struct {
+	// URL is the URL of the resource, after redirects
+	URL string
+	// ETag is the entity tag of the resource, to be passed to Put to
+	// detect concurrent changes
+	ETag string
+	// Links holds the links of the Link headers of the response
+	Links []Link
+	// Graph holds the RDF content of the resource, nil when only its
+	// headers were requested
+	Graph *Graph
+}
+
+// Link returns the URI of the first link of the resource with the relation
+// type rel, or an empty string
+func (r *LDPResource) Link(rel string) string {
+	for _, link := range r.Links {
+		if link.Rel == rel {
+			return link.URI
+		}
+	}
+	return ""
+}
+
+// Types returns the types of the resource advertised by its type links,
+// such as ldp:BasicContainer
+func (r *LDPResource) Types() []string {
+	var types []string
+	for _, link := range r.Links {
+		if link.Rel == "type" {
+			types = append(types, link.URI)
+		}
+	}
+	return types
+}
+
+// IsContainer tells whether the resource is advertised as an LDP container
+func (r *LDPResource) IsContainer() bool {
+	for _, t := range r.Types() {
+		switch t {
+		case LDP.IRI() + "Container", LDP.IRI() + "BasicContainer", LDP.IRI() + "DirectContainer", LDP.IRI() + "IndirectContainer":
+			return true
+		}
+	}
+	return false
+}
+
+// ACL returns the URI of the access control list of the resource, as
+// advertised by Solid servers, or an empty string
+func (r *LDPResource) ACL() string {
+	return r.Link("acl")
+}
+
+// Contains returns the sorted URIs of the members of a container, the
+// objects of its ldp:contains statements
+func (r *LDPResource) Contains() []string {
+	if r.Graph == nil {
+		return nil
+	}
+	var members []string
+	for _, triple := range r.Graph.All(r.Graph.Term(), LDP.Get("contains"), nil) {
+		members = append(members, triple.Object.RawValue())
+	}
+	slices.Sort(members)
+	return members
+}
+
+// Get fetches a resource and its RDF content
+func (c *LDPClient) Get(ctx context.Context, uri string) (*LDPResource, error) {
+	g := newGraph(defrag(uri), c.config, c.httpClient)
+	if !c.config.Unindexed {
+		g.index = newSPOIndex[*Triple]()
+	}
+	res, err := g.LoadURIWithResult(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return &LDPResource{URL: res.URL, ETag: res.ETag, Links: ParseLinks(res.Header, res.URL), Graph: g}, nil
+}
+
+// Head fetches the headers of a resource, such as a non-RDF one, to
+// discover its type and access control list
+func (c *LDPClient) Head(ctx context.Context, uri string) (*LDPResource, error) {
+	r, err := c.do(ctx, http.MethodHead, uri, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	url := r.Request.URL.String()
+	return &LDPResource{URL: url, ETag: r.Header.Get("ETag"), Links: ParseLinks(r.Header, url)}, nil
+}
+
+// Members returns the sorted URIs of the members of a container
+func (c *LDPClient) Members(ctx context.Context, container string) ([]string, error) {
+	res, err := c.Get(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+	return res.Contains(), nil
+}
+
+// Create creates an RDF resource in a container, holding g, and returns its
+// URI. The server names the resource after slug when it is not empty.
+func (c *LDPClient) Create(ctx context.Context, container string, slug string, g *Graph) (string, error) {
+	return c.create(ctx, container, slug, g, LDP.IRI()+"Resource")
+}
+
+// CreateContainer creates a basic container in a container, described by
+// g, which may be nil, and returns its URI. The server names the container
+// after slug when it is not empty.
+func (c *LDPClient) CreateContainer(ctx context.Context, container string, slug string, g *Graph) (string, error) {
+	return c.create(ctx, container, slug, g, LDP.IRI()+"BasicContainer")
+}
+
+func (c *LDPClient) create(ctx context.Context, container string, slug string, g *Graph, kind string) (string, error) {
+	body, err := c.serialize(g)
+	if err != nil {
+		return "", err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", c.Format)
+	header.Set("Link", "<"+kind+`>; rel="type"`)
+	if len(slug) > 0 {
+		header.Set("Slug", slug)
+	}
+	r, err := c.do(ctx, http.MethodPost, container, body, header)
+	if err != nil {
+		return "", err
+	}
+	r.Body.Close()
+	location := r.Header.Get("Location")
+	if len(location) == 0 {
+		return "", fmt.Errorf("POST %s returned no Location", container)
+	}
+	u, err := r.Request.URL.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// Put replaces the content of a resource with g, creating the resource if
+// needed. When etag is not empty, the resource is only replaced if it is
+// still at that version, see LDPResource.ETag.
+func (c *LDPClient) Put(ctx context.Context, uri string, g *Graph, etag string) error {
+	body, err := c.serialize(g)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", c.Format)
+	if len(etag) > 0 {
+		header.Set("If-Match", etag)
+	}
+	r, err := c.do(ctx, http.MethodPut, uri, body, header)
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// Patch sends a patch of the given media type, such as a SPARQL update or an
+// LD Patch, to a resource
+func (c *LDPClient) Patch(ctx context.Context, uri string, patch string, mime string) error {
+	header := http.Header{}
+	header.Set("Content-Type", mime)
+	r, err := c.do(ctx, http.MethodPatch, uri, []byte(patch), header)
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// PatchChanges applies a change set to a resource with a SPARQL update, see
+// ChangeSet.SPARQLUpdate. Nothing is sent for an empty change set.
+func (c *LDPClient) PatchChanges(ctx context.Context, uri string, changes *ChangeSet) error {
+	if changes.Len() == 0 {
+		return nil
+	}
+	update, err := changes.SPARQLUpdate()
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, uri, update, "application/sparql-update")
+}
+
+// Delete deletes a resource
+func (c *LDPClient) Delete(ctx context.Context, uri string) error {
+	r, err := c.do(ctx, http.MethodDelete, uri, nil, nil)
+	if err != nil {
+		return err
+	}
+	return r.Body.Close()
+}
+
+// serialize returns the serialization of g in the format of the client,
+// nothing for a nil graph
+func (c *LDPClient) serialize(g *Graph) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if g != nil {
+		if err := g.Serialize(buf, c.Format); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// do sends a request to uri, failing unless the response has a 2xx status.
+// The GET, HEAD and DELETE requests, which are sent without a body, are
+// retried as allowed by the configuration.
+func (c *LDPClient) do(ctx context.Context, method string, uri string, body []byte, header http.Header) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	target := defrag(uri)
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if err := c.config.prepare(req); err != nil {
+		return nil, err
+	}
+	var r *http.Response
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete {
+		r, err = c.config.do(ctx, c.httpClient, req)
+	} else {
+		r, err = c.httpClient.Do(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		r.Body.Close()
+		return nil, fmt.Errorf("%s %s failed - HTTP %d", method, target, r.StatusCode)
+	}
+	return r, nil
+}
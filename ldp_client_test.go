@@ -0,0 +1,103 @@
+package rdf2go
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLDPClient(t *testing.T) {
+	var patch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		switch req.Method + " " + req.URL.Path {
+		case "GET /notes/":
+			w.Header().Set("Content-Type", "text/turtle")
+			w.Header().Set("ETag", `"c1"`)
+			w.Header().Add("Link", `<http://www.w3.org/ns/ldp#BasicContainer>; rel="type", <http://www.w3.org/ns/ldp#Resource>; rel="type"`)
+			w.Header().Add("Link", `<.acl>; rel="acl"`)
+			w.Write([]byte("@prefix ldp: <http://www.w3.org/ns/ldp#> .\n<> a ldp:BasicContainer ; ldp:contains <b/>, <a> ."))
+		case "HEAD /notes/photo.png":
+			w.Header().Add("Link", `<photo.png.acl>; rel="acl", <photo.png.meta>; rel="describedby"`)
+		case "POST /notes/":
+			assert.Equal(t, "text/turtle", req.Header.Get("Content-Type"))
+			if strings.Contains(req.Header.Get("Link"), "BasicContainer") {
+				assert.Empty(t, body)
+				w.Header().Set("Location", "/notes/"+req.Header.Get("Slug")+"/")
+			} else {
+				assert.Contains(t, req.Header.Get("Link"), "<http://www.w3.org/ns/ldp#Resource>")
+				assert.Contains(t, string(body), "\"first\"")
+				w.Header().Set("Location", req.Header.Get("Slug"))
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "PUT /notes/a":
+			if match := req.Header.Get("If-Match"); len(match) > 0 && match != `"a1"` {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "PATCH /notes/a":
+			assert.Equal(t, "application/sparql-update", req.Header.Get("Content-Type"))
+			patch = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE /notes/a":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewLDPClient(WithBearerToken("t0k3n"))
+	res, err := c.Get(ctx, server.URL+"/notes/")
+	assert.NoError(t, err)
+	assert.True(t, res.IsContainer())
+	assert.Equal(t, `"c1"`, res.ETag)
+	assert.Equal(t, server.URL+"/notes/.acl", res.ACL())
+	assert.Equal(t, []string{LDP.IRI() + "BasicContainer", LDP.IRI() + "Resource"}, res.Types())
+	assert.Equal(t, []string{server.URL + "/notes/a", server.URL + "/notes/b/"}, res.Contains())
+	members, err := c.Members(ctx, server.URL+"/notes/")
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	res, err = c.Head(ctx, server.URL+"/notes/photo.png")
+	assert.NoError(t, err)
+	assert.False(t, res.IsContainer())
+	assert.Nil(t, res.Graph)
+	assert.Equal(t, server.URL+"/notes/photo.png.acl", res.ACL())
+	assert.Equal(t, server.URL+"/notes/photo.png.meta", res.Link("describedby"))
+
+	g := NewGraph("")
+	g.AddTriple(NewResource("#it"), NewResource("http://example.org/title"), NewLiteral("first"))
+	uri, err := c.Create(ctx, server.URL+"/notes/", "a", g)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/notes/a", uri)
+	uri, err = c.CreateContainer(ctx, server.URL+"/notes/", "c", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/notes/c/", uri)
+
+	assert.NoError(t, c.Put(ctx, uri[:len(uri)-2]+"a", g, `"a1"`))
+	err = c.Put(ctx, server.URL+"/notes/a", g, `"a0"`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 412")
+
+	changes := &ChangeSet{Added: []*Quad{NewQuad(NewResource(server.URL+"/notes/a#it"), NewResource("http://example.org/title"), NewLiteral("second"), nil)}}
+	assert.NoError(t, c.PatchChanges(ctx, server.URL+"/notes/a", changes))
+	assert.Equal(t, "INSERT DATA {\n  <"+server.URL+"/notes/a#it> <http://example.org/title> \"second\" .\n}\n", patch)
+	assert.NoError(t, c.PatchChanges(ctx, server.URL+"/notes/missing", &ChangeSet{}))
+
+	assert.NoError(t, c.Delete(ctx, server.URL+"/notes/a"))
+	assert.Error(t, c.Delete(ctx, server.URL+"/notes/missing"))
+	_, err = NewLDPClient().Get(ctx, server.URL+"/notes/")
+	assert.Error(t, err)
+}
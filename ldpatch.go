@@ -0,0 +1,629 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ldpatchOperation is a single statement of an LD Patch document
+type ldpatchOperation struct {
+	kind string // Bind, Add, AddNew, Delete, DeleteExisting, Cut or UpdateList
+	line int
+
+	// variable is the variable bound by Bind or cut by Cut
+	variable string
+	// value and path are the starting node and the path of Bind
+	value Term
+	path  []*ldpatchStep
+	// triples are the templates of Add, AddNew, Delete and DeleteExisting,
+	// and the statements describing the new elements of UpdateList
+	triples []*Triple
+
+	// subject, predicate, slice and elements are the arguments of UpdateList
+	subject    Term
+	predicate  Term
+	start, end *int
+	elements   []Term
+}
+
+// ldpatchStep is a step of an LD Patch path
+type ldpatchStep struct {
+	kind      byte // '/' forward, '^' backward, '#' list index, '[' constraint or '!' unicity
+	predicate Term
+	index     int
+	// path and value are the arguments of a constraint, value being nil
+	// when the constraint only tests for the existence of the path
+	path  []*ldpatchStep
+	value Term
+}
+
+// Patch applies a Linked Data Patch document (text/ldpatch) to the graph, as
+// used by Solid servers to implement the PATCH method. Every operation of the
+// LD Patch format is supported: Bind (with paths, constraints and list
+// indexes), Add, AddNew, Delete, DeleteExisting, Cut and UpdateList.
+//
+// The patch is atomic: when an operation fails, for instance because a Bind
+// path does not match exactly one node or AddNew finds a triple that already
+// exists, the changes made by the previous operations are rolled back. When
+// dryRun is true, the graph is left untouched and the returned change set
+// lists the triples that would have been added and removed.
+func (g *Graph) Patch(patch string, dryRun ...bool) (*ChangeSet, error) {
+	ops, err := parseLDPatch(patch, g.uri)
+	if err != nil {
+		return nil, err
+	}
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	tracker := newChangeTracker()
+	bindings := make(map[string]Term)
+	for _, op := range ops {
+		if err = g.applyPatch(op, bindings, tracker); err != nil {
+			err = fmt.Errorf("line %d: %s: %s", op.line, op.kind, err)
+			break
+		}
+	}
+	tracker.fill(c)
+	if err != nil || c.DryRun {
+		g.Apply(&ChangeSet{Added: c.Removed, Removed: c.Added})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (g *Graph) applyPatch(op *ldpatchOperation, bindings map[string]Term, tracker *changeTracker) error {
+	switch op.kind {
+	case "Bind":
+		start, err := bindPatchTerm(op.value, bindings)
+		if err != nil {
+			return err
+		}
+		nodes, err := g.evalPatchPath([]Term{start}, op.path, bindings)
+		if err != nil {
+			return err
+		}
+		if len(nodes) != 1 {
+			return fmt.Errorf("the path matches %d nodes instead of exactly one", len(nodes))
+		}
+		bindings[op.variable] = nodes[0]
+	case "Add", "AddNew":
+		triples, err := instantiatePatchTriples(op.triples, bindings)
+		if err != nil {
+			return err
+		}
+		for _, triple := range triples {
+			if g.One(triple.Subject, triple.Predicate, triple.Object) != nil {
+				if op.kind == "AddNew" {
+					return fmt.Errorf("the triple %s already exists", triple)
+				}
+				continue
+			}
+			g.insertPatchTriple(triple, tracker)
+		}
+	case "Delete", "DeleteExisting":
+		triples, err := instantiatePatchTriples(op.triples, bindings)
+		if err != nil {
+			return err
+		}
+		for _, triple := range triples {
+			matches := g.All(triple.Subject, triple.Predicate, triple.Object)
+			if len(matches) == 0 && op.kind == "DeleteExisting" {
+				return fmt.Errorf("the triple %s does not exist", triple)
+			}
+			for _, match := range matches {
+				g.deletePatchTriple(match, tracker)
+			}
+		}
+	case "Cut":
+		node, ok := bindings[op.variable]
+		if !ok {
+			return fmt.Errorf("unbound variable ?%s", op.variable)
+		}
+		if _, ok := node.(*BlankNode); !ok {
+			return fmt.Errorf("?%s is bound to %s, which is not a blank node", op.variable, node)
+		}
+		if g.One(node, nil, nil) == nil && g.One(nil, nil, node) == nil {
+			return fmt.Errorf("the blank node %s does not appear in the graph", node)
+		}
+		for _, triple := range g.All(nil, nil, node) {
+			g.deletePatchTriple(triple, tracker)
+		}
+		g.cutPatchNode(node, tracker)
+	case "UpdateList":
+		return g.updatePatchList(op, bindings, tracker)
+	}
+	return nil
+}
+
+func (g *Graph) insertPatchTriple(triple *Triple, tracker *changeTracker) {
+	g.Add(triple)
+	tracker.add(NewTripleQuad(triple))
+}
+
+func (g *Graph) deletePatchTriple(triple *Triple, tracker *changeTracker) {
+	g.Remove(triple)
+	tracker.remove(NewTripleQuad(triple))
+}
+
+// cutPatchNode removes the triples describing a blank node, and recursively
+// the blank nodes it describes
+func (g *Graph) cutPatchNode(node Term, tracker *changeTracker) {
+	for _, triple := range g.All(node, nil, nil) {
+		g.deletePatchTriple(triple, tracker)
+		if _, ok := triple.Object.(*BlankNode); ok && g.One(nil, nil, triple.Object) == nil {
+			g.cutPatchNode(triple.Object, tracker)
+		}
+	}
+}
+
+// bindPatchTerm replaces a variable by its value
+func bindPatchTerm(t Term, bindings map[string]Term) (Term, error) {
+	v, ok := t.(*variable)
+	if !ok {
+		return t, nil
+	}
+	value, ok := bindings[v.name]
+	if !ok {
+		return nil, fmt.Errorf("unbound variable %s", v)
+	}
+	return value, nil
+}
+
+func instantiatePatchTriples(templates []*Triple, bindings map[string]Term) ([]*Triple, error) {
+	triples := make([]*Triple, 0, len(templates))
+	for _, template := range templates {
+		var terms [3]Term
+		for i, t := range []Term{template.Subject, template.Predicate, template.Object} {
+			var err error
+			if terms[i], err = bindPatchTerm(t, bindings); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := terms[0].(*Literal); ok {
+			return nil, fmt.Errorf("a literal cannot be used as subject")
+		}
+		if _, ok := terms[1].(*Resource); !ok {
+			return nil, fmt.Errorf("the predicate %s is not an IRI", terms[1])
+		}
+		triples = append(triples, NewTriple(terms[0], terms[1], terms[2]))
+	}
+	return triples, nil
+}
+
+// evalPatchPath returns the nodes reached by following a path from the given
+// nodes, without duplicates
+func (g *Graph) evalPatchPath(nodes []Term, path []*ldpatchStep, bindings map[string]Term) ([]Term, error) {
+	for _, step := range path {
+		var next []Term
+		seen := make(map[string]bool)
+		reach := func(t Term) {
+			if key := termKey(t); !seen[key] {
+				seen[key] = true
+				next = append(next, t)
+			}
+		}
+		switch step.kind {
+		case '/':
+			for _, node := range nodes {
+				for _, triple := range g.All(node, step.predicate, nil) {
+					reach(triple.Object)
+				}
+			}
+		case '^':
+			for _, node := range nodes {
+				for _, triple := range g.All(nil, step.predicate, node) {
+					reach(triple.Subject)
+				}
+			}
+		case '#':
+			for _, node := range nodes {
+				_, elements, err := g.patchList(node)
+				if err != nil {
+					return nil, err
+				}
+				i := step.index
+				if i < 0 {
+					i += len(elements)
+				}
+				if i >= 0 && i < len(elements) {
+					reach(elements[i])
+				}
+			}
+		case '[':
+			var value Term
+			if step.value != nil {
+				var err error
+				if value, err = bindPatchTerm(step.value, bindings); err != nil {
+					return nil, err
+				}
+			}
+			for _, node := range nodes {
+				matches, err := g.evalPatchPath([]Term{node}, step.path, bindings)
+				if err != nil {
+					return nil, err
+				}
+				for _, match := range matches {
+					if value == nil || match.Equal(value) {
+						reach(node)
+						break
+					}
+				}
+			}
+		case '!':
+			if len(nodes) != 1 {
+				return nil, fmt.Errorf("the unicity constraint matches %d nodes", len(nodes))
+			}
+			next = nodes
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// patchList returns the cells and the elements of the RDF list starting at head
+func (g *Graph) patchList(head Term) (cells []Term, elements []Term, err error) {
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	nilList := NewResource(rdfNamespace + "nil")
+	seen := make(map[string]bool)
+	for node := head; !node.Equal(nilList); {
+		if seen[termKey(node)] {
+			return nil, nil, fmt.Errorf("the list %s is cyclic", head)
+		}
+		seen[termKey(node)] = true
+		firsts := g.All(node, first, nil)
+		rests := g.All(node, rest, nil)
+		if len(firsts) != 1 || len(rests) != 1 {
+			return nil, nil, fmt.Errorf("%s is not a well-formed list", head)
+		}
+		cells = append(cells, node)
+		elements = append(elements, firsts[0].Object)
+		node = rests[0].Object
+	}
+	return cells, elements, nil
+}
+
+// updatePatchList replaces a slice of a list with new elements, keeping the
+// cells outside of the slice
+func (g *Graph) updatePatchList(op *ldpatchOperation, bindings map[string]Term, tracker *changeTracker) error {
+	subject, err := bindPatchTerm(op.subject, bindings)
+	if err != nil {
+		return err
+	}
+	links := g.All(subject, op.predicate, nil)
+	if len(links) != 1 {
+		return fmt.Errorf("%s %s has %d values instead of exactly one list", subject, op.predicate, len(links))
+	}
+	cells, _, err := g.patchList(links[0].Object)
+	if err != nil {
+		return err
+	}
+	start, end := len(cells), len(cells)
+	if op.start != nil {
+		start = *op.start
+		if start < 0 {
+			start += len(cells)
+		}
+	} else if op.end != nil {
+		start = 0
+	}
+	if op.end != nil {
+		end = *op.end
+		if end < 0 {
+			end += len(cells)
+		}
+	}
+	if start < 0 || start > len(cells) || end < start || end > len(cells) {
+		return errors.New("the slice is out of the bounds of the list")
+	}
+
+	elements := make([]Term, len(op.elements))
+	for i, element := range op.elements {
+		if elements[i], err = bindPatchTerm(element, bindings); err != nil {
+			return err
+		}
+	}
+	described, err := instantiatePatchTriples(op.triples, bindings)
+	if err != nil {
+		return err
+	}
+
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	var tail Term = NewResource(rdfNamespace + "nil")
+	if end < len(cells) {
+		tail = cells[end]
+	}
+	// unlink the replaced cells
+	var link *Triple
+	if start == 0 {
+		link = links[0]
+	} else {
+		link = g.One(cells[start-1], rest, nil)
+	}
+	g.deletePatchTriple(link, tracker)
+	for _, cell := range cells[start:end] {
+		for _, triple := range g.All(cell, first, nil) {
+			g.deletePatchTriple(triple, tracker)
+		}
+		for _, triple := range g.All(cell, rest, nil) {
+			g.deletePatchTriple(triple, tracker)
+		}
+	}
+	// link the new cells, from the last one
+	for i := len(elements) - 1; i >= 0; i-- {
+		cell := NewAnonNode()
+		g.insertPatchTriple(NewTriple(cell, first, elements[i]), tracker)
+		g.insertPatchTriple(NewTriple(cell, rest, tail), tracker)
+		tail = cell
+	}
+	g.insertPatchTriple(NewTriple(link.Subject, link.Predicate, tail), tracker)
+	for _, triple := range described {
+		if g.One(triple.Subject, triple.Predicate, triple.Object) == nil {
+			g.insertPatchTriple(triple, tracker)
+		}
+	}
+	return nil
+}
+
+// parseLDPatch parses a Linked Data Patch document
+func parseLDPatch(src string, base string) ([]*ldpatchOperation, error) {
+	p, err := newSyntaxParser(src, base)
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.is(tokDirective, "prefix") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.prefixDecl(); err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct("."); err != nil {
+			return nil, err
+		}
+	}
+	var ops []*ldpatchOperation
+	for p.tok.kind != tokEOF {
+		op, err := p.ldpatchOperation()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct("."); err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// ldpatchKeywords maps the keywords of LD Patch, and their abbreviations, to
+// operation kinds. Unlike SPARQL, they are case sensitive.
+var ldpatchKeywords = map[string]string{
+	"Bind": "Bind", "B": "Bind",
+	"Add": "Add", "A": "Add",
+	"AddNew": "AddNew", "AN": "AddNew",
+	"Delete": "Delete", "D": "Delete",
+	"DeleteExisting": "DeleteExisting", "DE": "DeleteExisting",
+	"Cut": "Cut", "C": "Cut",
+	"UpdateList": "UpdateList", "UL": "UpdateList",
+}
+
+func (p *syntaxParser) ldpatchOperation() (*ldpatchOperation, error) {
+	kind, ok := ldpatchKeywords[p.tok.value]
+	if p.tok.kind != tokKeyword || !ok {
+		return nil, p.errorf("expected an LD Patch operation but found %s", p.tok)
+	}
+	op := &ldpatchOperation{kind: kind, line: p.tok.line}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch kind {
+	case "Bind":
+		if op.variable, err = p.ldpatchVariable(); err != nil {
+			return nil, err
+		}
+		if op.value, err = p.ldpatchValue(); err != nil {
+			return nil, err
+		}
+		op.path, err = p.ldpatchPath()
+	case "Add", "AddNew":
+		op.triples, err = p.ldpatchGraph(true)
+	case "Delete", "DeleteExisting":
+		if op.triples, err = p.ldpatchGraph(false); err != nil {
+			return nil, err
+		}
+		for _, triple := range op.triples {
+			for _, t := range []Term{triple.Subject, triple.Object} {
+				if _, ok := t.(*BlankNode); ok {
+					return nil, p.errorf("blank nodes are not allowed in %s", kind)
+				}
+			}
+		}
+	case "Cut":
+		op.variable, err = p.ldpatchVariable()
+	case "UpdateList":
+		err = p.ldpatchUpdateList(op)
+	}
+	return op, err
+}
+
+func (p *syntaxParser) ldpatchVariable() (string, error) {
+	if p.tok.kind != tokVariable {
+		return "", p.errorf("expected a variable but found %s", p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// ldpatchValue parses an IRI, a literal or a variable
+func (p *syntaxParser) ldpatchValue() (Term, error) {
+	switch p.tok.kind {
+	case tokVariable:
+		v := &variable{name: p.tok.value}
+		return v, p.advance()
+	case tokIRI, tokPName:
+		return p.iriTerm()
+	case tokString, tokInteger, tokDecimal, tokDouble:
+		return p.literal()
+	case tokKeyword:
+		if p.tok.value == "true" || p.tok.value == "false" {
+			return p.literal()
+		}
+	}
+	return nil, p.errorf("expected an IRI, a literal or a variable but found %s", p.tok)
+}
+
+// ldpatchPath parses a path: steps starting with '/', constraints between
+// brackets and unicity constraints
+func (p *syntaxParser) ldpatchPath() ([]*ldpatchStep, error) {
+	var path []*ldpatchStep
+	for {
+		switch {
+		case p.isPunct("/"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			step := &ldpatchStep{kind: '/'}
+			if p.isPunct("^") {
+				step.kind = '^'
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			if step.kind == '/' && p.tok.kind == tokInteger {
+				step.kind = '#'
+				var err error
+				if step.index, err = p.ldpatchIndex(); err != nil {
+					return nil, err
+				}
+			} else {
+				var err error
+				if step.predicate, err = p.iriTerm(); err != nil {
+					return nil, err
+				}
+			}
+			path = append(path, step)
+		case p.isPunct("["):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			step := &ldpatchStep{kind: '['}
+			var err error
+			if step.path, err = p.ldpatchPath(); err != nil {
+				return nil, err
+			}
+			if p.isPunct("=") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if step.value, err = p.ldpatchValue(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+			path = append(path, step)
+		case p.isPunct("!"):
+			path = append(path, &ldpatchStep{kind: '!'})
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+func (p *syntaxParser) ldpatchIndex() (int, error) {
+	var index int
+	if _, err := fmt.Sscan(p.tok.value, &index); err != nil {
+		return 0, p.errorf("invalid index %s", p.tok)
+	}
+	return index, p.advance()
+}
+
+// ldpatchGraph parses { triples }, in which variables may be used. When
+// fresh is true, blank nodes denote new blank nodes.
+func (p *syntaxParser) ldpatchGraph(fresh bool) ([]*Triple, error) {
+	p.variables = true
+	p.freshBlankNodes = fresh
+	p.bnodes = make(map[string]Term)
+	defer func() {
+		p.variables, p.freshBlankNodes = false, false
+	}()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var triples []*Triple
+	err := p.triplesBlock(func(s Term, pr Term, o Term) {
+		triples = append(triples, NewTriple(s, pr, o))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return triples, p.expectPunct("}")
+}
+
+// ldpatchUpdateList parses the arguments of UpdateList: a subject, a
+// predicate, a slice and a collection
+func (p *syntaxParser) ldpatchUpdateList(op *ldpatchOperation) error {
+	var err error
+	if p.tok.kind == tokVariable {
+		op.subject = &variable{name: p.tok.value}
+		err = p.advance()
+	} else {
+		op.subject, err = p.iriTerm()
+	}
+	if err != nil {
+		return err
+	}
+	if op.predicate, err = p.iriTerm(); err != nil {
+		return err
+	}
+
+	if p.tok.kind == tokInteger {
+		index, err := p.ldpatchIndex()
+		if err != nil {
+			return err
+		}
+		op.start = &index
+	}
+	if err := p.expectPunct(".."); err != nil {
+		return err
+	}
+	if p.tok.kind == tokInteger {
+		index, err := p.ldpatchIndex()
+		if err != nil {
+			return err
+		}
+		op.end = &index
+	}
+
+	p.variables, p.freshBlankNodes = true, true
+	p.bnodes = make(map[string]Term)
+	defer func() {
+		p.variables, p.freshBlankNodes = false, false
+	}()
+	if err := p.expectPunct("("); err != nil {
+		return err
+	}
+	emit := func(s Term, pr Term, o Term) {
+		op.triples = append(op.triples, NewTriple(s, pr, o))
+	}
+	for !p.isPunct(")") {
+		if p.tok.kind == tokEOF {
+			return p.errorf("unterminated collection")
+		}
+		element, err := p.object(emit)
+		if err != nil {
+			return err
+		}
+		op.elements = append(op.elements, element)
+	}
+	return p.advance()
+}
@@ -0,0 +1,260 @@
+package rdf2go
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ldpatchTurtle is the initial graph of the example of the LD Patch
+// specification
+const ldpatchTurtle = `@prefix schema: <http://schema.org/> .
+@prefix profile: <http://ogp.me/ns/profile#> .
+@prefix ex: <http://example.org/vocab#> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+<http://example.com/timbl#i> a schema:Person ;
+  schema:alternateName "TimBL" ;
+  profile:first_name "Tim" ;
+  profile:last_name "Berners-Lee" ;
+  schema:workLocation [ schema:name "W3C/MIT" ] ;
+  schema:performerIn _:b1, _:b2 ;
+  ex:preferredLanguages ( "en" "fr" ).
+_:b1 schema:name "F2F5 - Linked Data Platform" ;
+  schema:url <https://www.w3.org/2012/ldp/wiki/F2F5> .
+_:b2 a schema:Event ;
+  schema:name "TED 2009" ;
+  schema:startDate "2009-02-04" ;
+  schema:url <http://conferences.ted.com/TED2009/> .
+`
+
+const ldpatchExample = `@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix schema: <http://schema.org/> .
+@prefix profile: <http://ogp.me/ns/profile#> .
+@prefix ex: <http://example.org/vocab#> .
+
+Delete { <#i> profile:first_name "Tim" } .
+Add    { <#i> profile:first_name "Timothy" } .
+
+UpdateList <#i> ex:preferredLanguages 1..2 ( "fr-CH" ) .
+
+Bind ?event <#i> /schema:performerIn [ /schema:url = <https://www.w3.org/2012/ldp/wiki/F2F5> ]  .
+Add { ?event rdf:type schema:Event } .
+
+Bind ?ted <http://conferences.ted.com/TED2009/> /^schema:url ! .
+Delete { ?ted schema:startDate "2009-02-04" } .
+Add {
+  ?ted schema:location _:loc .
+  _:loc schema:name "Long Beach, California" .
+  _:loc schema:geo _:geo .
+  _:geo schema:latitude "33.7817" .
+  _:geo schema:longitude "-118.2054" .
+} .
+`
+
+func newLDPatchGraph(t *testing.T) *Graph {
+	g := NewGraph("http://example.com/timbl")
+	assert.NoError(t, g.Parse(strings.NewReader(ldpatchTurtle), "text/turtle"))
+	return g
+}
+
+func TestGraphPatch(t *testing.T) {
+	g := newLDPatchGraph(t)
+	before := g.Len()
+	me := NewResource("http://example.com/timbl#i")
+	schema := "http://schema.org/"
+
+	c, err := g.Patch(ldpatchExample)
+	assert.NoError(t, err)
+	assert.False(t, c.DryRun)
+
+	assert.Nil(t, g.One(me, NewResource("http://ogp.me/ns/profile#first_name"), NewLiteral("Tim")))
+	assert.NotNil(t, g.One(me, NewResource("http://ogp.me/ns/profile#first_name"), NewLiteral("Timothy")))
+
+	head := g.One(me, NewResource("http://example.org/vocab#preferredLanguages"), nil).Object
+	_, elements, err := g.patchList(head)
+	assert.NoError(t, err)
+	assert.Equal(t, []Term{NewLiteral("en"), NewLiteral("fr-CH")}, elements)
+
+	f2f5 := g.One(nil, NewResource(schema+"url"), NewResource("https://www.w3.org/2012/ldp/wiki/F2F5")).Subject
+	assert.NotNil(t, g.One(f2f5, NewResource(rdfNamespace+"type"), NewResource(schema+"Event")))
+
+	ted := g.One(nil, NewResource(schema+"url"), NewResource("http://conferences.ted.com/TED2009/")).Subject
+	assert.Nil(t, g.One(ted, NewResource(schema+"startDate"), nil))
+	loc := g.One(ted, NewResource(schema+"location"), nil).Object
+	assert.NotNil(t, g.One(loc, NewResource(schema+"name"), NewLiteral("Long Beach, California")))
+	geo := g.One(loc, NewResource(schema+"geo"), nil).Object
+	assert.NotNil(t, g.One(geo, NewResource(schema+"latitude"), NewLiteral("33.7817")))
+
+	// 1 + 3 + 1 + 5 triples added, 1 + 3 + 1 removed
+	assert.Equal(t, 10, len(c.Added))
+	assert.Equal(t, 5, len(c.Removed))
+	assert.Equal(t, before+5, g.Len())
+}
+
+func TestGraphPatchDryRun(t *testing.T) {
+	g := newLDPatchGraph(t)
+	before := g.Len()
+	c, err := g.Patch(ldpatchExample, true)
+	assert.NoError(t, err)
+	assert.True(t, c.DryRun)
+	assert.Equal(t, 10, len(c.Added))
+	assert.Equal(t, before, g.Len())
+	assert.NotNil(t, g.One(nil, NewResource("http://ogp.me/ns/profile#first_name"), NewLiteral("Tim")))
+}
+
+func TestGraphPatchAtomic(t *testing.T) {
+	patches := []string{
+		// the path matches two nodes
+		`Add { <#i> <http://example.org/p> "x" } .
+Bind ?event <#i> /<http://schema.org/performerIn> .`,
+		`Add { <#i> <http://example.org/p> "x" } .
+AddNew { <#i> <http://ogp.me/ns/profile#first_name> "Tim" } .`,
+		`Add { <#i> <http://example.org/p> "x" } .
+DeleteExisting { <#i> <http://ogp.me/ns/profile#first_name> "Timothy" } .`,
+		`Add { <#i> <http://example.org/p> "x" } .
+Bind ?x <http://conferences.ted.com/TED2009/> /^<http://schema.org/url>/<http://schema.org/name>[/<http://example.org/nope>] .`,
+		`Add { ?unbound <http://example.org/p> "x" } .`,
+		`Bind ?me <#i> .
+Cut ?me .`,
+		`UpdateList <#i> <http://ogp.me/ns/profile#first_name> .. ( "x" ) .`,
+	}
+	for _, patch := range patches {
+		g := newLDPatchGraph(t)
+		before := g.Len()
+		_, err := g.Patch(patch)
+		assert.Error(t, err, patch)
+		assert.Equal(t, before, g.Len(), patch)
+		assert.Nil(t, g.One(nil, NewResource("http://example.org/p"), nil), patch)
+	}
+}
+
+func TestGraphPatchSyntaxErrors(t *testing.T) {
+	patches := []string{
+		`Insert { <#i> <http://example.org/p> "x" } .`,
+		`add { <#i> <http://example.org/p> "x" } .`,
+		`Add { <#i> <http://example.org/p> "x" }`,
+		`Delete { _:b <http://example.org/p> "x" } .`,
+		`Bind <#i> <#i> .`,
+		`UpdateList <#i> <http://example.org/p> 1 ( ) .`,
+		`Add { undefined:a <http://example.org/p> "x" } .`,
+	}
+	g := newLDPatchGraph(t)
+	for _, patch := range patches {
+		_, err := g.Patch(patch)
+		assert.Error(t, err, patch)
+		_, ok := err.(*syntaxError)
+		assert.True(t, ok, patch)
+	}
+}
+
+func TestGraphPatchCut(t *testing.T) {
+	g := newLDPatchGraph(t)
+	before := g.Len()
+	c, err := g.Patch(`Bind ?loc <#i> /<http://schema.org/workLocation> .
+Cut ?loc .`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(c.Removed))
+	assert.Equal(t, before-2, g.Len())
+	assert.Nil(t, g.One(nil, NewResource("http://schema.org/workLocation"), nil))
+}
+
+func TestGraphPatchUpdateList(t *testing.T) {
+	langs := NewResource("http://example.org/vocab#preferredLanguages")
+	me := NewResource("http://example.com/timbl#i")
+	for slice, expected := range map[string][]string{
+		"..":     {"en", "fr", "x", "y"},
+		"0..0":   {"x", "y", "en", "fr"},
+		"0..":    {"x", "y"},
+		"1..":    {"en", "x", "y"},
+		"..1":    {"x", "y", "fr"},
+		"-1..":   {"en", "x", "y"},
+		"0..-1":  {"x", "y", "fr"},
+		"2..2":   {"en", "fr", "x", "y"},
+		"1..2  ": {"en", "x", "y"},
+	} {
+		g := newLDPatchGraph(t)
+		_, err := g.Patch(`UL <#i> <http://example.org/vocab#preferredLanguages> ` + slice + ` ( "x" "y" ) .`)
+		assert.NoError(t, err, slice)
+		_, elements, err := g.patchList(g.One(me, langs, nil).Object)
+		assert.NoError(t, err)
+		var values []string
+		for _, e := range elements {
+			values = append(values, e.RawValue())
+		}
+		assert.Equal(t, expected, values, slice)
+	}
+
+	// removing every element leaves rdf:nil
+	g := newLDPatchGraph(t)
+	before := g.Len()
+	_, err := g.Patch(`UL <#i> <http://example.org/vocab#preferredLanguages> 0.. ( ) .`)
+	assert.NoError(t, err)
+	assert.Equal(t, NewResource(rdfNamespace+"nil"), g.One(me, langs, nil).Object)
+	assert.Equal(t, before-4, g.Len())
+}
+
+func TestGraphPatchIndexPath(t *testing.T) {
+	g := newLDPatchGraph(t)
+	_, err := g.Patch(`Bind ?lang <#i> /<http://example.org/vocab#preferredLanguages>/-1 .
+Add { <#i> <http://example.org/p> ?lang } .`)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLiteral("fr"), g.One(nil, NewResource("http://example.org/p"), nil).Object)
+}
+
+func TestGraphParseLDPatch(t *testing.T) {
+	g := NewGraph("")
+	g.AddTriple(NewResource("http://a"), NewResource("http://b"), NewResource("http://c"))
+	// patches are only applied by Patch, never by Parse
+	patch := `Delete { <http://a> <http://b> <http://c> } .`
+	assert.Error(t, g.Parse(strings.NewReader(patch), "text/ldpatch"))
+	assert.Equal(t, 1, g.Len())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/ldpatch")
+		w.Write([]byte(patch))
+	}))
+	defer server.Close()
+	assert.Error(t, g.LoadURI(server.URL))
+	assert.Equal(t, 1, g.Len())
+}
+
+func TestGraphStoreHandlerPatch(t *testing.T) {
+	d := NewDataset("http://example.com/")
+	assert.NoError(t, d.Parse(strings.NewReader(`<http://example.com/timbl#> <http://xmlns.com/foaf/0.1/name> "Tim" <http://example.com/timbl> .
+`), "application/n-quads"))
+	h := NewGraphStoreHandler(d)
+
+	patch := func(body string, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/timbl", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := patch(`Delete { <#> <http://xmlns.com/foaf/0.1/name> "Tim" } .
+Add { <#> <http://xmlns.com/foaf/0.1/name> "Timothy" } .`, "text/ldpatch")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	g := NewResource("http://example.com/timbl")
+	assert.Equal(t, 1, len(d.All(nil, nil, nil, g)))
+	assert.NotNil(t, d.One(NewResource("http://example.com/timbl#"), nil, NewLiteral("Timothy"), g))
+
+	rec = patch(`Add { <#> `, "text/ldpatch")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = patch(`DeleteExisting { <#> <http://xmlns.com/foaf/0.1/name> "Tim" } .`, "text/ldpatch")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	rec = patch(`INSERT DATA { <a> <b> <c> }`, "application/sparql-update")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "text/ldpatch", rec.Header().Get("Accept-Patch"))
+
+	req := httptest.NewRequest(http.MethodPatch, "/new", bytes.NewBufferString(`Add { <#a> <#b> <#c> } .`))
+	req.Header.Set("Content-Type", "text/ldpatch")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotNil(t, d.One(NewResource("http://example.com/new#a"), nil, nil, NewResource("http://example.com/new")))
+}
@@ -0,0 +1,437 @@
+package rdf2go
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind identifies the kind of a token of the Turtle family of syntaxes
+// (N-Triples, N-Quads, Turtle, TriG, the SPARQL triple patterns and LD Patch)
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIRI
+	tokPName
+	tokBlankNode
+	tokVariable
+	tokString
+	tokLangTag
+	tokDatatype
+	tokInteger
+	tokDecimal
+	tokDouble
+	tokKeyword
+	tokDirective
+	tokPunct
+)
+
+var tokenKindNames = [...]string{
+	tokEOF:       "EOF",
+	tokIRI:       "IRI",
+	tokPName:     "PrefixedName",
+	tokBlankNode: "BlankNode",
+	tokVariable:  "Variable",
+	tokString:    "String",
+	tokLangTag:   "LangTag",
+	tokDatatype:  "DatatypeMarker",
+	tokInteger:   "Integer",
+	tokDecimal:   "Decimal",
+	tokDouble:    "Double",
+	tokKeyword:   "Keyword",
+	tokDirective: "Directive",
+	tokPunct:     "Punctuation",
+}
+
+func (k tokenKind) String() string {
+	if int(k) < len(tokenKindNames) {
+		return tokenKindNames[k]
+	}
+	return fmt.Sprintf("tokenKind(%d)", int(k))
+}
+
+// token is a single lexical unit. Value holds the unescaped content: the IRI
+// without brackets, the string without quotes, the blank node label without
+// "_:", the variable name without "?", and so on.
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+	offset int
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+// isKeyword compares keywords case insensitively, as SPARQL does
+func (t token) isKeyword(value string) bool {
+	return t.kind == tokKeyword && strings.EqualFold(t.value, value)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokIRI:
+		return "<" + t.value + ">"
+	case tokString:
+		return fmt.Sprintf("%q", t.value)
+	}
+	return t.value
+}
+
+// lexer splits a document into tokens
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, column: 1}
+}
+
+// syntaxError is an error located at a position of the document
+type syntaxError struct {
+	line   int
+	column int
+	msg    string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.line, e.column, e.msg)
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return &syntaxError{line: l.line, column: l.column, msg: fmt.Sprintf(format, args...)}
+}
+
+func (l *lexer) peekByte(ahead int) byte {
+	if l.pos+ahead < len(l.src) {
+		return l.src[l.pos+ahead]
+	}
+	return 0
+}
+
+func (l *lexer) peekRune() (rune, int) {
+	if l.pos >= len(l.src) {
+		return 0, 0
+	}
+	return utf8.DecodeRuneInString(l.src[l.pos:])
+}
+
+// advance moves forward by n bytes, keeping track of lines and columns
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.column = 1
+		} else if l.src[l.pos]&0xC0 != 0x80 {
+			l.column++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipSpaceAndComments() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			l.advance(1)
+		} else if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// next returns the next token of the document
+func (l *lexer) next() (token, error) {
+	l.skipSpaceAndComments()
+	tok := token{line: l.line, column: l.column, offset: l.pos}
+	if l.pos >= len(l.src) {
+		tok.kind = tokEOF
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case c == '<' && l.peekByte(1) == '<':
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, "<<"
+		return tok, nil
+	case c == '>' && l.peekByte(1) == '>':
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, ">>"
+		return tok, nil
+	case c == '{' && l.peekByte(1) == '|':
+		// annotations of Turtle-star
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, "{|"
+		return tok, nil
+	case c == '|' && l.peekByte(1) == '}':
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, "|}"
+		return tok, nil
+	case c == '<':
+		return l.iri(tok)
+	case c == '"' || c == '\'':
+		return l.str(tok)
+	case c == '_' && l.peekByte(1) == ':':
+		l.advance(2)
+		label := l.name(true)
+		if len(label) == 0 {
+			return tok, l.errorf("empty blank node label")
+		}
+		tok.kind, tok.value = tokBlankNode, label
+		return tok, nil
+	case c == '?' || c == '$':
+		l.advance(1)
+		name := l.name(false)
+		if len(name) == 0 {
+			return tok, l.errorf("empty variable name")
+		}
+		tok.kind, tok.value = tokVariable, name
+		return tok, nil
+	case c == '@':
+		l.advance(1)
+		start := l.pos
+		for l.pos < len(l.src) && (isAlphaNum(l.src[l.pos]) || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		word := l.src[start:l.pos]
+		if len(word) == 0 {
+			return tok, l.errorf("empty language tag")
+		}
+		if word == "prefix" || word == "base" {
+			tok.kind, tok.value = tokDirective, word
+		} else {
+			tok.kind, tok.value = tokLangTag, word
+		}
+		return tok, nil
+	case c == '^' && l.peekByte(1) == '^':
+		l.advance(2)
+		tok.kind, tok.value = tokDatatype, "^^"
+		return tok, nil
+	case c == '=' && l.peekByte(1) == '>':
+		// implications of N3 rules
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, "=>"
+		return tok, nil
+	case c == '.' && l.peekByte(1) == '.':
+		// slices of LD Patch
+		l.advance(2)
+		tok.kind, tok.value = tokPunct, ".."
+		return tok, nil
+	case (c >= '0' && c <= '9') || ((c == '+' || c == '-') && (isDigit(l.peekByte(1)) || l.peekByte(1) == '.')) || (c == '.' && isDigit(l.peekByte(1))):
+		return l.number(tok)
+	case strings.IndexByte("{}()[].;,=|/^!", c) >= 0:
+		l.advance(1)
+		tok.kind, tok.value = tokPunct, string(c)
+		return tok, nil
+	case c == ':':
+		return l.pname(tok, "")
+	}
+
+	r, _ := l.peekRune()
+	if isNameStartRune(r) {
+		start := l.pos
+		word := l.name(true)
+		if l.peekByte(0) == ':' {
+			return l.pname(tok, word)
+		}
+		if len(word) == 0 {
+			l.pos = start
+		} else {
+			tok.kind, tok.value = tokKeyword, word
+			return tok, nil
+		}
+	}
+	return tok, l.errorf("unexpected character %q", r)
+}
+
+func (l *lexer) iri(tok token) (token, error) {
+	l.advance(1)
+	start := l.pos
+	escaped := false
+	for l.pos < len(l.src) && l.src[l.pos] != '>' {
+		c := l.src[l.pos]
+		if c == '\\' {
+			escaped = true
+		}
+		if c == ' ' || c == '\n' || c == '<' || c == '"' || c == '{' || c == '}' || c == '|' || c == '^' || c == '`' {
+			return tok, l.errorf("invalid character %q in IRI", c)
+		}
+		l.advance(1)
+	}
+	if l.pos >= len(l.src) {
+		return tok, l.errorf("unterminated IRI")
+	}
+	value := l.src[start:l.pos]
+	l.advance(1)
+	if escaped {
+		var err error
+		if value, err = unescapeString(value); err != nil {
+			return tok, l.errorf("%s", err)
+		}
+	}
+	tok.kind, tok.value = tokIRI, value
+	return tok, nil
+}
+
+func (l *lexer) str(tok token) (token, error) {
+	quote := l.src[l.pos]
+	long := l.peekByte(1) == quote && l.peekByte(2) == quote
+	delim := string([]byte{quote})
+	if long {
+		delim = strings.Repeat(delim, 3)
+	}
+	l.advance(len(delim))
+	start := l.pos
+	escaped := false
+	for {
+		if l.pos >= len(l.src) {
+			return tok, l.errorf("unterminated string")
+		}
+		c := l.src[l.pos]
+		if c == '\\' {
+			escaped = true
+			l.advance(2)
+			continue
+		}
+		if !long && (c == '\n' || c == '\r') {
+			return tok, l.errorf("line break in short string")
+		}
+		if strings.HasPrefix(l.src[l.pos:], delim) {
+			// a long string may end with up to two extra quotes
+			if long && l.peekByte(3) == quote {
+				l.advance(1)
+				continue
+			}
+			break
+		}
+		l.advance(1)
+	}
+	value := l.src[start:l.pos]
+	l.advance(len(delim))
+	if escaped {
+		var err error
+		if value, err = unescapeString(value); err != nil {
+			return tok, l.errorf("%s", err)
+		}
+	}
+	tok.kind, tok.value = tokString, value
+	return tok, nil
+}
+
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	if c := l.src[l.pos]; c == '+' || c == '-' {
+		l.advance(1)
+	}
+	kind := tokInteger
+	for isDigit(l.peekByte(0)) {
+		l.advance(1)
+	}
+	if l.peekByte(0) == '.' && isDigit(l.peekByte(1)) {
+		kind = tokDecimal
+		l.advance(1)
+		for isDigit(l.peekByte(0)) {
+			l.advance(1)
+		}
+	}
+	if c := l.peekByte(0); c == 'e' || c == 'E' {
+		next := l.peekByte(1)
+		if isDigit(next) || ((next == '+' || next == '-') && isDigit(l.peekByte(2))) {
+			kind = tokDouble
+			l.advance(2)
+			for isDigit(l.peekByte(0)) {
+				l.advance(1)
+			}
+		}
+	}
+	tok.kind, tok.value = kind, l.src[start:l.pos]
+	return tok, nil
+}
+
+// pname reads the local part of a prefixed name whose prefix has been read
+func (l *lexer) pname(tok token, prefix string) (token, error) {
+	l.advance(1) // ':'
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\\' && l.pos+1 < len(l.src) && strings.IndexByte("_~.-!$&'()*+,;=/?#@%", l.src[l.pos+1]) >= 0 {
+			b.WriteByte(l.src[l.pos+1])
+			l.advance(2)
+			continue
+		}
+		if c == '%' && l.pos+2 < len(l.src) && isHex(l.src[l.pos+1]) && isHex(l.src[l.pos+2]) {
+			b.WriteString(l.src[l.pos : l.pos+3])
+			l.advance(3)
+			continue
+		}
+		if c == ':' || c == '.' {
+			// a local name cannot end with a dot
+			if c == '.' {
+				r, _ := utf8.DecodeRuneInString(l.src[l.pos+1:])
+				if !isNameRune(r) && r != ':' && r != '%' && r != '\\' {
+					break
+				}
+			}
+			b.WriteByte(c)
+			l.advance(1)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !isNameRune(r) {
+			break
+		}
+		b.WriteString(l.src[l.pos : l.pos+size])
+		l.advance(size)
+	}
+	tok.kind, tok.value = tokPName, prefix+":"+b.String()
+	return tok, nil
+}
+
+// name reads a run of name characters. When dots is true, the name may contain
+// dots, but never ends with one.
+func (l *lexer) name(dots bool) string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if r == '.' && dots {
+			next, _ := utf8.DecodeRuneInString(l.src[l.pos+1:])
+			if !isNameRune(next) {
+				break
+			}
+		} else if !isNameRune(r) {
+			break
+		}
+		l.advance(size)
+	}
+	return l.src[start:l.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNameStartRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || r == '-' || r == 0xB7 || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
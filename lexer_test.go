@@ -0,0 +1,55 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lexAll(t *testing.T, src string) []token {
+	l := newLexer(src)
+	var toks []token
+	for {
+		tok, err := l.next()
+		assert.NoError(t, err)
+		if err != nil || tok.kind == tokEOF {
+			return toks
+		}
+		toks = append(toks, tok)
+	}
+}
+
+func TestLexerTokens(t *testing.T) {
+	toks := lexAll(t, `@prefix ex: <http://example.org/> . # comment
+ex:a a ex:b ; ex:c "x\ty"@en-GB , 'z'^^ex:t , -1.5e3 , 42 , .5 , _:b1 , ?v , [ ] .`)
+	kinds := []tokenKind{
+		tokDirective, tokPName, tokIRI, tokPunct,
+		tokPName, tokKeyword, tokPName, tokPunct, tokPName, tokString, tokLangTag, tokPunct,
+		tokString, tokDatatype, tokPName, tokPunct, tokDouble, tokPunct, tokInteger, tokPunct,
+		tokDecimal, tokPunct, tokBlankNode, tokPunct, tokVariable, tokPunct, tokPunct, tokPunct, tokPunct,
+	}
+	if assert.Equal(t, len(kinds), len(toks)) {
+		for i, kind := range kinds {
+			assert.Equal(t, kind, toks[i].kind, "token %d: %s", i, toks[i])
+		}
+	}
+	assert.Equal(t, "x\ty", toks[9].value)
+	assert.Equal(t, "en-GB", toks[10].value)
+	assert.Equal(t, 2, toks[4].line)
+}
+
+func TestLexerLongString(t *testing.T) {
+	toks := lexAll(t, `"""a "quoted"
+line"""`)
+	assert.Equal(t, 1, len(toks))
+	assert.Equal(t, "a \"quoted\"\nline", toks[0].value)
+}
+
+func TestLexerErrors(t *testing.T) {
+	for _, src := range []string{`<http://example.org/a`, `"unterminated`, `"line
+break"`, `_: x`, `~`} {
+		l := newLexer(src)
+		_, err := l.next()
+		assert.Error(t, err, src)
+	}
+}
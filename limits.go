@@ -0,0 +1,107 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ParseLimits bounds the resources used to parse a document, so that
+// services parsing untrusted input cannot be exhausted by it. Zero values
+// mean no limit.
+type ParseLimits struct {
+	// MaxStatements limits the number of statements of a document
+	MaxStatements int
+	// MaxLiteralLength limits the length in bytes of the values of literals,
+	// including those of quoted triples
+	MaxLiteralLength int
+	// MaxDepth limits the nesting of blank node property lists, collections
+	// and quoted triples. Turtle documents are then read with the parser of
+	// TriG, as gon3 cannot bound the nesting.
+	MaxDepth int
+	// MaxBytes limits the size of a document, read before any other format
+	// conversion
+	MaxBytes int64
+}
+
+// ErrLimitExceeded is matched by the errors returned by Parse for documents
+// exceeding a limit of Config.Limits
+var ErrLimitExceeded = errors.New("parse limit exceeded")
+
+// LimitError is returned by Parse for a document exceeding a limit of
+// Config.Limits, even when parsing is lenient
+type LimitError struct {
+	// Limit is the name of the exceeded limit, such as MaxStatements
+	Limit string
+	// Max is the value of the limit
+	Max int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s is %d", ErrLimitExceeded, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded, for errors.Is
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// limit bounds the size of a parsed document
+func (limits ParseLimits) limit(reader io.Reader) io.Reader {
+	if limits.MaxBytes <= 0 {
+		return reader
+	}
+	return &limitedReader{r: reader, n: limits.MaxBytes, err: &LimitError{Limit: "MaxBytes", Max: limits.MaxBytes}}
+}
+
+// check fails for the parsed statements exceeding the limits, n being the
+// number of statements of the document so far, this one included
+func (limits ParseLimits) check(n int, terms ...Term) error {
+	if limits.MaxStatements > 0 && n > limits.MaxStatements {
+		return &LimitError{Limit: "MaxStatements", Max: int64(limits.MaxStatements)}
+	}
+	if limits.MaxLiteralLength <= 0 && limits.MaxDepth <= 0 {
+		return nil
+	}
+	for _, t := range terms {
+		if err := limits.checkTerm(t, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTerm fails for the terms exceeding the limits, depth being the
+// number of quoted triples t is nested in
+func (limits ParseLimits) checkTerm(t Term, depth int) error {
+	switch term := t.(type) {
+	case *Literal:
+		if limits.MaxLiteralLength > 0 && len(term.Value) > limits.MaxLiteralLength {
+			return &LimitError{Limit: "MaxLiteralLength", Max: int64(limits.MaxLiteralLength)}
+		}
+	case *QuotedTriple:
+		if limits.MaxDepth > 0 && depth >= limits.MaxDepth {
+			return &LimitError{Limit: "MaxDepth", Max: int64(limits.MaxDepth)}
+		}
+		for _, part := range []Term{term.Subject, term.Predicate, term.Object} {
+			if err := limits.checkTerm(part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nest enters a blank node property list, a collection or a quoted triple,
+// failing beyond the depth limit of the parser. unnest leaves it.
+func (p *syntaxParser) nest() error {
+	p.depth++
+	if p.maxDepth > 0 && p.depth > p.maxDepth {
+		return &LimitError{Limit: "MaxDepth", Max: int64(p.maxDepth)}
+	}
+	return nil
+}
+
+func (p *syntaxParser) unnest() {
+	p.depth--
+}
@@ -0,0 +1,70 @@
+package rdf2go
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertLimitExceeded(t *testing.T, err error, limit string) {
+	t.Helper()
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	var limitErr *LimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, limit, limitErr.Limit)
+	}
+}
+
+func TestParseLimitsStatements(t *testing.T) {
+	doc := "<http://example.org/a> <http://example.org/p> \"1\" .\n<http://example.org/a> <http://example.org/p> \"2\" .\n"
+	for _, mime := range []string{"text/turtle", "application/n-triples"} {
+		g := NewGraphWithOptions(testUri, WithParseLimits(ParseLimits{MaxStatements: 1}))
+		assertLimitExceeded(t, g.Parse(strings.NewReader(doc), mime), "MaxStatements")
+
+		g = NewGraphWithOptions(testUri, WithParseLimits(ParseLimits{MaxStatements: 2}))
+		assert.NoError(t, g.Parse(strings.NewReader(doc), mime), mime)
+	}
+	d := NewDatasetWithOptions(testDatasetUri, WithParseLimits(ParseLimits{MaxStatements: 1}), WithLenient(true))
+	assertLimitExceeded(t, d.Parse(strings.NewReader(doc), "application/n-quads"), "MaxStatements")
+}
+
+func TestParseLimitsLiteralLength(t *testing.T) {
+	limits := WithParseLimits(ParseLimits{MaxLiteralLength: 3})
+	g := NewGraphWithOptions(testUri, limits)
+	assert.NoError(t, g.Parse(strings.NewReader(`<http://example.org/a> <http://example.org/p> "abc" .`), "text/turtle"))
+	assertLimitExceeded(t, g.Parse(strings.NewReader(`<http://example.org/a> <http://example.org/p> "abcd" .`), "text/turtle"), "MaxLiteralLength")
+	assertLimitExceeded(t, g.Parse(strings.NewReader(`<< <http://example.org/a> <http://example.org/p> "abcd" >> <http://example.org/p> <http://example.org/b> .`), "text/turtle"), "MaxLiteralLength")
+}
+
+func TestParseLimitsDepth(t *testing.T) {
+	limits := WithParseLimits(ParseLimits{MaxDepth: 2})
+	nested := func(n int) string {
+		return "<http://example.org/a> <http://example.org/p> " + strings.Repeat("[ <http://example.org/p> ", n) + "<http://example.org/b>" + strings.Repeat(" ]", n) + " ."
+	}
+	for _, mime := range []string{"text/turtle", "application/trig"} {
+		d := NewDatasetWithOptions(testDatasetUri, limits)
+		assert.NoError(t, d.Parse(strings.NewReader(nested(2)), mime), mime)
+		assertLimitExceeded(t, d.Parse(strings.NewReader(nested(3)), mime), "MaxDepth")
+	}
+	g := NewGraphWithOptions(testUri, limits, WithLenient(true))
+	assertLimitExceeded(t, g.Parse(strings.NewReader("("+strings.Repeat(" (", 3)+strings.Repeat(" )", 4)+" <http://example.org/p> <http://example.org/b> ."), "text/turtle"), "MaxDepth")
+
+	quoted := "<< << <http://example.org/a> <http://example.org/p> <http://example.org/b> >> <http://example.org/p> <http://example.org/c> >> <http://example.org/p> <http://example.org/d> .\n"
+	d := NewDatasetWithOptions(testDatasetUri, WithParseLimits(ParseLimits{MaxDepth: 1}))
+	assertLimitExceeded(t, d.Parse(strings.NewReader(quoted), "application/n-quads"), "MaxDepth")
+	d = NewDatasetWithOptions(testDatasetUri, limits)
+	assert.NoError(t, d.Parse(strings.NewReader(quoted), "application/n-quads"))
+}
+
+func TestParseLimitsBytes(t *testing.T) {
+	doc := `<http://example.org/a> <http://example.org/p> "1" .`
+	for _, mime := range []string{"text/turtle", "application/n-triples", "application/ld+json"} {
+		g := NewGraphWithOptions(testUri, WithParseLimits(ParseLimits{MaxBytes: 10}))
+		assertLimitExceeded(t, g.Parse(strings.NewReader(doc), mime), "MaxBytes")
+	}
+	g := NewGraphWithOptions(testUri, WithParseLimits(ParseLimits{MaxBytes: int64(len(doc))}))
+	assert.NoError(t, g.Parse(strings.NewReader(doc), "text/turtle"))
+	assert.Equal(t, 1, g.Len())
+}
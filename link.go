@@ -0,0 +1,128 @@
+package rdf2go
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Link is a link of an HTTP Link header (RFC 8288), through which servers
+// advertise the type of a resource, its access control list, or the
+// description of a non-RDF resource
+type Link struct {
+	// URI is the target of the link, resolved against the URL of the
+	// response
+	URI string
+	// Rel is the relation type of the link, such as "type" or "acl"
+	Rel string
+	// Params holds the other parameters of the link, with lower case names
+	Params map[string]string
+}
+
+// ParseLinks returns the links of the Link headers of a response, resolving
+// their targets against base unless it is empty. A link with several
+// relation types is returned once per type. Malformed links are skipped.
+func ParseLinks(header http.Header, base string) []Link {
+	var baseURL *url.URL
+	if len(base) > 0 {
+		baseURL, _ = url.Parse(base)
+	}
+	var links []Link
+	for _, value := range header.Values("Link") {
+		for len(value) > 0 {
+			var target string
+			var params map[string]string
+			target, params, value = parseLink(value)
+			if len(target) == 0 {
+				continue
+			}
+			if baseURL != nil {
+				if u, err := baseURL.Parse(target); err == nil {
+					target = u.String()
+				}
+			}
+			rels := strings.Fields(params["rel"])
+			delete(params, "rel")
+			for _, rel := range rels {
+				links = append(links, Link{URI: target, Rel: rel, Params: params})
+			}
+		}
+	}
+	return links
+}
+
+// parseLink parses the first link of a Link header value, returning its
+// target, empty when it is malformed, its parameters and the rest of the
+// value
+func parseLink(value string) (string, map[string]string, string) {
+	value = strings.TrimLeft(value, " \t,")
+	if !strings.HasPrefix(value, "<") {
+		// skip to the next link
+		if i := strings.IndexByte(value, ','); i >= 0 {
+			return "", nil, value[i+1:]
+		}
+		return "", nil, ""
+	}
+	end := strings.IndexByte(value, '>')
+	if end < 0 {
+		return "", nil, ""
+	}
+	target := value[1:end]
+	value = value[end+1:]
+	params := make(map[string]string)
+	for {
+		value = strings.TrimLeft(value, " \t")
+		if len(value) == 0 || value[0] == ',' {
+			return target, params, value
+		}
+		if value[0] != ';' {
+			// junk after the link
+			if i := strings.IndexByte(value, ','); i >= 0 {
+				return "", nil, value[i+1:]
+			}
+			return "", nil, ""
+		}
+		value = strings.TrimLeft(value[1:], " \t")
+		i := strings.IndexAny(value, "=;,")
+		if i < 0 {
+			i = len(value)
+		}
+		name := strings.ToLower(strings.TrimSpace(value[:i]))
+		value = value[i:]
+		param := ""
+		if strings.HasPrefix(value, "=") {
+			param, value = parseLinkParam(strings.TrimLeft(value[1:], " \t"))
+		}
+		// the first occurrence of a parameter wins
+		if _, seen := params[name]; !seen && len(name) > 0 {
+			params[name] = param
+		}
+	}
+}
+
+// parseLinkParam parses the value of a link parameter, a token or a quoted
+// string, returning it and the rest of the header value
+func parseLinkParam(value string) (string, string) {
+	if !strings.HasPrefix(value, "\"") {
+		i := strings.IndexAny(value, ";,")
+		if i < 0 {
+			i = len(value)
+		}
+		return strings.TrimSpace(value[:i]), value[i:]
+	}
+	var b strings.Builder
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 < len(value) {
+				i++
+				b.WriteByte(value[i])
+			}
+		case '"':
+			return b.String(), value[i+1:]
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String(), ""
+}
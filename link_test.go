@@ -0,0 +1,28 @@
+package rdf2go
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<http://www.w3.org/ns/ldp#BasicContainer>; rel="type", <http://www.w3.org/ns/ldp#Resource>; rel=type`)
+	header.Add("Link", `<.acl>; rel="acl", <meta>; rel="describedby alternate"; type="text/turtle"; title="a \"b\", c"`)
+	header.Add("Link", `junk, <next>; rel=next; rel=ignored, <broken`)
+
+	links := ParseLinks(header, "https://pod.example/notes/")
+	assert.Equal(t, []Link{
+		{URI: "http://www.w3.org/ns/ldp#BasicContainer", Rel: "type", Params: map[string]string{}},
+		{URI: "http://www.w3.org/ns/ldp#Resource", Rel: "type", Params: map[string]string{}},
+		{URI: "https://pod.example/notes/.acl", Rel: "acl", Params: map[string]string{}},
+		{URI: "https://pod.example/notes/meta", Rel: "describedby", Params: map[string]string{"type": "text/turtle", "title": `a "b", c`}},
+		{URI: "https://pod.example/notes/meta", Rel: "alternate", Params: map[string]string{"type": "text/turtle", "title": `a "b", c`}},
+		{URI: "https://pod.example/notes/next", Rel: "next", Params: map[string]string{}},
+	}, links)
+
+	assert.Empty(t, ParseLinks(http.Header{}, ""))
+	assert.Equal(t, "next", ParseLinks(header, "")[5].URI)
+}
@@ -0,0 +1,384 @@
+package rdf2go
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	termType          = reflect.TypeOf((*Term)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	urlType           = reflect.TypeOf(url.URL{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Marshal returns a graph describing a struct, or a pointer to one. The
+// fields tagged with a predicate IRI, such as
+//
+//	Name string `rdf:"http://xmlns.com/foaf/0.1/name"`
+//
+// give the statements about the struct, whose subject is the IRI held by the
+// field tagged `rdf:"@id"`, or a blank node without one. The field tagged
+// `rdf:"@type"` holds the IRIs of its classes. The fields of exported embedded
+// structs describe the same subject, and the other fields are ignored.
+//
+// Field values become the objects of the statements:
+//
+//   - strings are plain literals, booleans, integers and floats literals
+//     typed as xsd:boolean, xsd:integer and xsd:double, time.Time values
+//     xsd:dateTime literals, byte slices xsd:base64Binary literals and the
+//     values implementing encoding.TextMarshaler plain literals
+//   - Term values are used as they are, and url.URL values are IRIs
+//   - structs are nodes described by their own fields, a struct pointed to
+//     several times being described once
+//   - slices and arrays give a statement per element
+//   - pointers and interfaces give the statements of their value, none when
+//     they are nil
+//
+// Options follow the IRI in the tag, separated by commas:
+//
+//   - iri: strings are IRIs, empty ones giving no statement
+//   - lang=tag: strings are literals with the language tag
+//   - type=IRI: values are literals with the datatype
+//   - list: slices and arrays are a single rdf:List
+//   - omitempty: zero values give no statement
+func Marshal(v interface{}) (*Graph, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %T, which is not a struct", v)
+	}
+	m := &marshaler{graph: NewGraph(""), nodes: make(map[marshaledNode]Term)}
+	if _, err := m.node(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return m.graph, nil
+}
+
+// rdfField is a struct field tagged with a predicate, see Marshal
+type rdfField struct {
+	name      string
+	index     []int
+	predicate Term
+	id        bool
+	iri       bool
+	list      bool
+	omitempty bool
+	language  string
+	datatype  Term
+}
+
+// rdfFields returns the tagged fields of a struct type, including those of
+// its embedded structs
+func rdfFields(t reflect.Type) ([]*rdfField, error) {
+	var fields []*rdfField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("rdf")
+		if !tagged {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if sf.Anonymous && sf.IsExported() && embedded.Kind() == reflect.Struct && embedded != timeType && embedded != urlType {
+				inner, err := rdfFields(embedded)
+				if err != nil {
+					return nil, err
+				}
+				for _, f := range inner {
+					f.index = append([]int{i}, f.index...)
+				}
+				fields = append(fields, inner...)
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		f, err := parseRDFTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", sf.Name, t, err)
+		}
+		f.name = sf.Name
+		f.index = []int{i}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// parseRDFTag parses the predicate and the options of an rdf struct tag
+func parseRDFTag(tag string) (*rdfField, error) {
+	parts := strings.Split(tag, ",")
+	f := &rdfField{}
+	switch parts[0] {
+	case "":
+		return nil, fmt.Errorf("missing predicate")
+	case "@id":
+		f.id = true
+	case "@type":
+		f.predicate, f.iri = rdfType, true
+	default:
+		f.predicate = NewResource(parts[0])
+	}
+	for _, option := range parts[1:] {
+		name, value, _ := strings.Cut(option, "=")
+		switch name {
+		case "iri":
+			f.iri = true
+		case "list":
+			f.list = true
+		case "omitempty":
+			f.omitempty = true
+		case "lang":
+			f.language = value
+		case "type":
+			f.datatype = NewResource(value)
+		default:
+			return nil, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return f, nil
+}
+
+// marshaledNode identifies a struct pointed to while marshaling
+type marshaledNode struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type marshaler struct {
+	graph *Graph
+	nodes map[marshaledNode]Term
+}
+
+// node returns the subject describing a struct, or a pointer to one, adding
+// its description to the graph
+func (m *marshaler) node(v reflect.Value) (Term, error) {
+	var key marshaledNode
+	if v.Kind() == reflect.Pointer {
+		key = marshaledNode{v.Pointer(), v.Type()}
+		if node, seen := m.nodes[key]; seen {
+			return node, nil
+		}
+		v = v.Elem()
+	}
+	fields, err := rdfFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	var subject Term
+	for _, f := range fields {
+		if !f.id {
+			continue
+		}
+		value, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+		if subject, err = marshalID(value); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	if subject == nil {
+		subject = NewAnonNode()
+	}
+	if key.typ != nil {
+		m.nodes[key] = subject
+	}
+	for _, f := range fields {
+		if f.id {
+			continue
+		}
+		value, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// through a nil embedded pointer
+			continue
+		}
+		if f.omitempty && value.IsZero() {
+			continue
+		}
+		objects, err := m.objects(value, f)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+		if f.list && isList(value) {
+			objects = []Term{m.list(objects)}
+		}
+		for _, o := range objects {
+			m.graph.AddTriple(subject, f.predicate, o)
+		}
+	}
+	return subject, nil
+}
+
+// marshalID returns the subject held by an @id field, nil when it is empty
+func marshalID(v reflect.Value) (Term, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Implements(termType) {
+			return v.Interface().(Term), nil
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == urlType:
+		u := v.Interface().(url.URL)
+		return NewResource(u.String()), nil
+	case v.Type().Implements(termType):
+		return v.Interface().(Term), nil
+	case v.Kind() == reflect.String:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		return NewResource(v.String()), nil
+	}
+	return nil, fmt.Errorf("cannot use %s as @id", v.Type())
+}
+
+// objects returns the objects of the statements given by a field value
+func (m *marshaler) objects(v reflect.Value, f *rdfField) ([]Term, error) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	if v.Type().Implements(termType) {
+		return []Term{v.Interface().(Term)}, nil
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		return m.objects(v.Elem(), f)
+	case reflect.Pointer:
+		if v.Elem().Kind() == reflect.Struct && v.Type().Elem() != timeType && v.Type().Elem() != urlType {
+			node, err := m.node(v)
+			return []Term{node}, err
+		}
+		return m.objects(v.Elem(), f)
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType, urlType:
+		default:
+			node, err := m.node(v)
+			return []Term{node}, err
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			var objects []Term
+			for i := 0; i < v.Len(); i++ {
+				elements, err := m.objects(v.Index(i), f)
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, elements...)
+			}
+			return objects, nil
+		}
+	}
+	if f.iri && v.Kind() == reflect.String && v.Len() == 0 {
+		return nil, nil
+	}
+	literal, err := marshalLiteral(v, f)
+	if err != nil {
+		return nil, err
+	}
+	return []Term{literal}, nil
+}
+
+// list adds an rdf:List of terms to the graph, returning its head
+func (m *marshaler) list(elements []Term) Term {
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	var head Term = NewResource(rdfNamespace + "nil")
+	for i := len(elements) - 1; i >= 0; i-- {
+		node := NewAnonNode()
+		m.graph.AddTriple(node, first, elements[i])
+		m.graph.AddTriple(node, rest, head)
+		head = node
+	}
+	return head
+}
+
+// marshalLiteral converts a scalar value to a literal, or to an IRI for the
+// iri option
+func marshalLiteral(v reflect.Value, f *rdfField) (Term, error) {
+	var lexical string
+	var datatype string
+	switch {
+	case v.Type() == timeType:
+		lexical, datatype = v.Interface().(time.Time).Format(time.RFC3339Nano), "dateTime"
+	case v.Type() == urlType:
+		u := v.Interface().(url.URL)
+		return NewResource(u.String()), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		lexical, datatype = base64.StdEncoding.EncodeToString(v.Bytes()), "base64Binary"
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		lexical = string(text)
+	default:
+		switch v.Kind() {
+		case reflect.String:
+			lexical = v.String()
+			if f.iri {
+				return NewResource(lexical), nil
+			}
+		case reflect.Bool:
+			lexical, datatype = strconv.FormatBool(v.Bool()), "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			lexical, datatype = strconv.FormatInt(v.Int(), 10), "integer"
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			lexical, datatype = strconv.FormatUint(v.Uint(), 10), "integer"
+		case reflect.Float32, reflect.Float64:
+			lexical, datatype = formatDouble(v.Float(), v.Type().Bits()), "double"
+		default:
+			return nil, fmt.Errorf("cannot marshal %s", v.Type())
+		}
+	}
+	switch {
+	case f.datatype != nil:
+		return NewLiteralWithDatatype(lexical, f.datatype), nil
+	case len(datatype) > 0:
+		return NewLiteralWithDatatype(lexical, NewResource(xsdNamespace+datatype)), nil
+	case len(f.language) > 0:
+		return NewLiteralWithLanguage(lexical, f.language), nil
+	}
+	return NewLiteral(lexical), nil
+}
+
+// formatDouble returns the xsd:double lexical form of a float
+func formatDouble(f float64, bits int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+// isList tells whether a value is marshaled as an rdf:List with the list
+// option, a non nil slice or an array of other elements than bytes
+func isList(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		return !v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package rdf2go
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFOAF = "http://xmlns.com/foaf/0.1/"
+
+type testAddress struct {
+	City string `rdf:"http://example.org/city"`
+}
+
+// MarshaledAgent is exported, as the fields of unexported embedded structs are
+// ignored
+type MarshaledAgent struct {
+	ID    string   `rdf:"@id"`
+	Types []string `rdf:"@type"`
+}
+
+type testPerson struct {
+	MarshaledAgent
+	Name     string            `rdf:"http://xmlns.com/foaf/0.1/name,lang=en"`
+	Nick     string            `rdf:"http://xmlns.com/foaf/0.1/nick,omitempty"`
+	Age      int               `rdf:"http://xmlns.com/foaf/0.1/age"`
+	Height   float64           `rdf:"http://example.org/height"`
+	Active   bool              `rdf:"http://example.org/active"`
+	Birthday time.Time         `rdf:"http://example.org/birthday"`
+	Homepage *url.URL          `rdf:"http://xmlns.com/foaf/0.1/homepage"`
+	Mbox     string            `rdf:"http://xmlns.com/foaf/0.1/mbox,iri"`
+	Code     string            `rdf:"http://example.org/code,type=http://example.org/Code"`
+	Knows    []*testPerson     `rdf:"http://xmlns.com/foaf/0.1/knows"`
+	Address  testAddress       `rdf:"http://example.org/address"`
+	Scores   []int             `rdf:"http://example.org/scores,list"`
+	Tags     []string          `rdf:"http://example.org/tag"`
+	Status   Term              `rdf:"http://example.org/status"`
+	Spouse   *testPerson       `rdf:"http://example.org/spouse"`
+	Notes    map[string]string `rdf:"-"`
+	internal string
+}
+
+func TestMarshal(t *testing.T) {
+	homepage, _ := url.Parse("https://alice.example/")
+	alice := &testPerson{
+		MarshaledAgent: MarshaledAgent{ID: "https://alice.example/#me", Types: []string{testFOAF + "Person"}},
+		Name:           "Alice",
+		Age:            42,
+		Height:         1.5,
+		Active:         true,
+		Birthday:       time.Date(1980, 5, 17, 10, 0, 0, 0, time.UTC),
+		Homepage:       homepage,
+		Mbox:           "mailto:alice@example.org",
+		Code:           "A1",
+		Address:        testAddress{City: "Oslo"},
+		Scores:         []int{1, 2},
+		Tags:           []string{"a", "b"},
+		Status:         NewResource("http://example.org/Active"),
+	}
+	bob := &testPerson{Name: "Bob", Knows: []*testPerson{alice}}
+	alice.Knows = []*testPerson{bob}
+
+	g, err := Marshal(alice)
+	assert.NoError(t, err)
+	me := NewResource("https://alice.example/#me")
+	xsd := NewNamespace(xsdNamespace)
+	foaf := NewNamespace(testFOAF)
+	ex := NewNamespace("http://example.org/")
+	assert.NotNil(t, g.One(me, rdfType, foaf.Get("Person")))
+	assert.NotNil(t, g.One(me, foaf.Get("name"), NewLiteralWithLanguage("Alice", "en")))
+	assert.Nil(t, g.One(me, foaf.Get("nick"), nil))
+	assert.NotNil(t, g.One(me, foaf.Get("age"), NewLiteralWithDatatype("42", xsd.Get("integer"))))
+	assert.NotNil(t, g.One(me, ex.Get("height"), NewLiteralWithDatatype("1.5", xsd.Get("double"))))
+	assert.NotNil(t, g.One(me, ex.Get("active"), NewLiteralWithDatatype("true", xsd.Get("boolean"))))
+	assert.NotNil(t, g.One(me, ex.Get("birthday"), NewLiteralWithDatatype("1980-05-17T10:00:00Z", xsd.Get("dateTime"))))
+	assert.NotNil(t, g.One(me, foaf.Get("homepage"), NewResource("https://alice.example/")))
+	assert.NotNil(t, g.One(me, foaf.Get("mbox"), NewResource("mailto:alice@example.org")))
+	assert.NotNil(t, g.One(me, ex.Get("code"), NewLiteralWithDatatype("A1", ex.Get("Code"))))
+	assert.NotNil(t, g.One(me, ex.Get("status"), ex.Get("Active")))
+	assert.Len(t, g.All(me, ex.Get("tag"), nil), 2)
+	assert.Nil(t, g.One(me, ex.Get("spouse"), nil))
+
+	address := g.One(me, ex.Get("address"), nil)
+	if assert.NotNil(t, address) {
+		assert.NotNil(t, g.One(address.Object, ex.Get("city"), NewLiteral("Oslo")))
+	}
+
+	// cycles are described once
+	knows := g.One(me, foaf.Get("knows"), nil)
+	if assert.NotNil(t, knows) {
+		assert.NotNil(t, g.One(knows.Object, foaf.Get("name"), NewLiteralWithLanguage("Bob", "en")))
+		assert.NotNil(t, g.One(knows.Object, foaf.Get("knows"), me))
+	}
+
+	scores := g.One(me, ex.Get("scores"), nil)
+	if assert.NotNil(t, scores) {
+		first := g.One(scores.Object, NewResource(rdfNamespace+"first"), nil)
+		assert.Equal(t, NewLiteralWithDatatype("1", xsd.Get("integer")), first.Object)
+		rest := g.One(scores.Object, NewResource(rdfNamespace+"rest"), nil)
+		assert.Len(t, g.All(rest.Object, nil, nil), 2)
+	}
+
+	_, err = Marshal("alice")
+	assert.EqualError(t, err, "cannot marshal string, which is not a struct")
+	_, err = Marshal(struct {
+		Notes map[string]string `rdf:"http://example.org/notes"`
+	}{Notes: map[string]string{}})
+	assert.EqualError(t, err, "field Notes: cannot marshal map[string]string")
+	_, err = Marshal(struct {
+		Name string `rdf:"http://example.org/name,bogus"`
+	}{})
+	assert.Error(t, err)
+}
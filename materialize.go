@@ -0,0 +1,207 @@
+package rdf2go
+
+// materializer keeps the statements derived by a set of rules in a graph of
+// a dataset, nil being the default graph, up to date as the graph changes.
+// It is shared by Reasoner and LiveRules.
+type materializer struct {
+	dataset *Dataset
+	graph   Term
+	rules   deriver
+	sub     *Subscription
+
+	// derived records how each derived statement was first derived
+	derived map[*Quad]*derivation
+	// busy is set while the materializer changes the dataset, whose changes
+	// it does not react to
+	busy bool
+}
+
+// deriver applies rules in single steps
+type deriver interface {
+	// consequences passes to emit the statements derived in a single step
+	// with f as one of the premises, the others being looked up with all.
+	// The statement f needs not be part of the graph anymore.
+	consequences(f *Quad, all matcher, emit emitFunc)
+	// derivation finds a derivation of a statement from the statements
+	// looked up with all in a single step
+	derivation(m *Quad, all matcher) (rule string, supports []*Quad, ok bool)
+}
+
+// matcher returns the statements of a graph matching a pattern of S, P, O
+// objects
+type matcher func(s Term, p Term, o Term) []*Quad
+
+// emitFunc receives a statement derived by a rule from supports
+type emitFunc func(rule string, s Term, p Term, o Term, supports ...*Quad)
+
+type derivation struct {
+	rule     string
+	supports []*Quad
+}
+
+func newMaterializer(d *Dataset, graph Term, rules deriver) materializer {
+	return materializer{
+		dataset: d,
+		graph:   graph,
+		rules:   rules,
+		derived: make(map[*Quad]*derivation),
+	}
+}
+
+// start derives the statements entailed by the graph, and subscribes to its
+// changes
+func (r *materializer) start() {
+	r.infer(r.all(nil, nil, nil))
+	r.sub = r.dataset.Subscribe(nil, nil, nil, storedGraph(r.graph), r.changed)
+}
+
+// all returns the statements of the graph matching a pattern
+func (r *materializer) all(s Term, p Term, o Term) []*Quad {
+	return r.dataset.All(s, p, o, storedGraph(r.graph))
+}
+
+// Cancel stops tracking the changes of the dataset. The derived statements
+// are left in the graph.
+func (r *materializer) Cancel() {
+	r.sub.Cancel()
+}
+
+// IsDerived tells whether a statement of the graph was derived rather than
+// asserted
+func (r *materializer) IsDerived(q *Quad) bool {
+	_, ok := r.derived[q]
+	return ok
+}
+
+// Derived returns the derived statements, sorted
+func (r *materializer) Derived() []*Quad {
+	quads := make([]*Quad, 0, len(r.derived))
+	for quad := range r.derived {
+		quads = append(quads, quad)
+	}
+	sortQuads(quads)
+	return quads
+}
+
+// Explain returns the derivation tree of a derived statement, or nil for the
+// statements that were not derived
+func (r *materializer) Explain(q *Quad) *Explanation {
+	return r.explain(q, make(map[*Quad]bool))
+}
+
+func (r *materializer) explain(q *Quad, visiting map[*Quad]bool) *Explanation {
+	d, ok := r.derived[q]
+	if !ok || visiting[q] {
+		return nil
+	}
+	visiting[q] = true
+	defer delete(visiting, q)
+	e := &Explanation{Statement: q, Rule: d.rule, Supports: d.supports}
+	for _, support := range d.supports {
+		if premise := r.explain(support, visiting); premise != nil {
+			e.Premises = append(e.Premises, premise)
+		}
+	}
+	return e
+}
+
+// changed derives the consequences of an added statement and retracts
+// those of a removed one
+func (r *materializer) changed(kind ChangeKind, q *Quad) {
+	if r.busy {
+		return
+	}
+	if kind == QuadAdded {
+		r.infer([]*Quad{q})
+		return
+	}
+	delete(r.derived, q)
+	r.retract(q)
+}
+
+// infer adds the statements derived from the agenda, and from the derived
+// statements in turn
+func (r *materializer) infer(agenda []*Quad) {
+	for len(agenda) > 0 {
+		f := agenda[len(agenda)-1]
+		agenda = agenda[:len(agenda)-1]
+		r.rules.consequences(f, r.all, func(rule string, s Term, p Term, o Term, supports ...*Quad) {
+			if q := r.add(rule, s, p, o, supports); q != nil {
+				agenda = append(agenda, q)
+			}
+		})
+	}
+}
+
+// add adds a derived statement missing from the graph, returning it
+func (r *materializer) add(rule string, s Term, p Term, o Term, supports []*Quad) *Quad {
+	if r.dataset.One(s, p, o, storedGraph(r.graph)) != nil {
+		return nil
+	}
+	q := NewQuad(s, p, o, r.graph)
+	r.derived[q] = &derivation{rule: rule, supports: supports}
+	r.busy = true
+	r.dataset.Add(q)
+	r.busy = false
+	return q
+}
+
+// retract removes the derived statements that depended on a removed one,
+// after the delete and rederive algorithm: every statement derived from it
+// is removed, directly or not, and the removed statements that can still be
+// derived from the others are derived again
+func (r *materializer) retract(removed *Quad) {
+	var marked []*Quad
+	seen := make(map[*Quad]bool)
+	agenda := []*Quad{removed}
+	for len(agenda) > 0 {
+		f := agenda[len(agenda)-1]
+		agenda = agenda[:len(agenda)-1]
+		r.rules.consequences(f, r.all, func(_ string, s Term, p Term, o Term, _ ...*Quad) {
+			for _, q := range r.all(s, p, o) {
+				if _, ok := r.derived[q]; ok && !seen[q] {
+					seen[q] = true
+					marked = append(marked, q)
+					agenda = append(agenda, q)
+				}
+			}
+		})
+	}
+
+	r.busy = true
+	for _, q := range marked {
+		delete(r.derived, q)
+		r.dataset.Remove(q)
+	}
+	r.busy = false
+
+	// the removed statement itself is derived again when it is entailed by
+	// the others
+	for _, m := range append([]*Quad{removed}, marked...) {
+		if r.dataset.One(m.Subject, m.Predicate, m.Object, storedGraph(r.graph)) != nil {
+			continue
+		}
+		if rule, supports, ok := r.rules.derivation(m, r.all); ok {
+			if q := r.add(rule, m.Subject, m.Predicate, m.Object, supports); q != nil {
+				r.infer([]*Quad{q})
+			}
+		}
+	}
+}
+
+// validStatement tells whether derived terms make a statement, with an IRI
+// or a blank node as subject and an IRI as predicate
+func validStatement(s Term, p Term, o Term) bool {
+	_, iri := p.(*Resource)
+	return resource(s) && iri && o != nil
+}
+
+// resource tells whether a term is an IRI or a blank node, which can be the
+// subject of a statement
+func resource(t Term) bool {
+	switch t.(type) {
+	case *Resource, *BlankNode:
+		return true
+	}
+	return false
+}
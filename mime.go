@@ -1,20 +1,34 @@
 package rdf2go
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
 	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 var mimeParser = map[string]string{
 	"text/turtle":               "turtle",
 	"application/trig":          "trig",
 	"application/ld+json":       "jsonld",
+	"application/n-quads":       "nquads",
+	"application/n-triples":     "ntriples",
 	"application/sparql-update": "internal",
+	"text/ldpatch":              "internal",
 }
 
 var mimeSerializer = map[string]string{
-	"application/ld+json": "jsonld",
-	"application/trig":    "trig",
-	"text/html":           "internal",
+	"application/ld+json":   "jsonld",
+	"application/trig":      "trig",
+	"application/n-quads":   "nquads",
+	"application/n-triples": "ntriples",
+	"text/html":             "internal",
 }
 
 var mimeRdfExt = map[string]string{
@@ -23,6 +37,8 @@ var mimeRdfExt = map[string]string{
 	".n3":     "text/n3",
 	".rdf":    "application/rdf+xml",
 	".jsonld": "application/ld+json",
+	".nq":     "application/n-quads",
+	".nt":     "application/n-triples",
 }
 
 var rdfExtensions = []string{
@@ -31,9 +47,142 @@ var rdfExtensions = []string{
 	".n3",
 	".rdf",
 	".jsonld",
+	".nq",
+	".nt",
+}
+
+// mediaType returns the media type of a content type, such as text/turtle
+// for "text/turtle; charset=utf-8", lowercased and without its parameters
+func mediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// decodeCharset returns a reader converting a document from the charset
+// given by the parameters of its content type to UTF-8. UTF-8, US-ASCII,
+// ISO-8859-1 and UTF-16 are supported.
+func decodeCharset(r io.Reader, contentType string) (io.Reader, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return r, nil
+	}
+	switch charset := strings.ToLower(params["charset"]); charset {
+	case "", "utf-8", "utf8", "us-ascii":
+		return r, nil
+	case "iso-8859-1", "latin1", "l1":
+		return &latin1Reader{r: bufio.NewReader(r)}, nil
+	case "utf-16", "utf-16be", "utf-16le":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(decodeUTF16(data, charset)), nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %q", params["charset"])
+	}
+}
+
+// latin1Reader converts ISO-8859-1 text to UTF-8, every byte being the code
+// point of a character
+type latin1Reader struct {
+	r       *bufio.Reader
+	pending []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(l.pending) > 0 {
+			c := copy(p[n:], l.pending)
+			l.pending = l.pending[c:]
+			n += c
+			continue
+		}
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+		} else {
+			l.pending = utf8.AppendRune(nil, rune(b))
+		}
+	}
+	return n, nil
+}
+
+// decodeUTF16 converts UTF-16 text to UTF-8, after its byte order mark if
+// any, or else the byte order of the charset, big-endian for utf-16
+func decodeUTF16(data []byte, charset string) string {
+	bigEndian := charset != "utf-16le"
+	if bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+		bigEndian, data = true, data[2:]
+	} else if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		bigEndian, data = false, data[2:]
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
 }
 
 var (
 	serializerMimes = []string{}
 	validMimeType   = regexp.MustCompile(`^\w+/\w+$`)
 )
+
+// negotiateMime returns the offer preferred by an Accept header, or an empty
+// string when none of the offers is acceptable. A missing header accepts the
+// first offer. Offers with equal quality are chosen in the given order.
+func negotiateMime(accept string, offers []string) string {
+	if len(strings.TrimSpace(accept)) == 0 {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, r := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+			if err != nil {
+				continue
+			}
+			s := -1
+			switch {
+			case mediaType == offer:
+				s = 2
+			case strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaType, "*")):
+				s = 1
+			case mediaType == "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+			specificity, q = s, 1
+			if v, ok := params["q"]; ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,114 @@
+package rdf2go
+
+import (
+	"io"
+	"iter"
+)
+
+// Model is the interface shared by Graph and Dataset, so that utilities such
+// as diffing, validation or serialization can be written once for both.
+// Statements are exchanged as quads, a nil graph term denoting the default
+// graph.
+//
+// A Graph behaves as a dataset made of a default graph only: its triples are
+// yielded as quads of the default graph, and the graph terms of patterns and
+// of inserted or deleted quads are ignored, as with Graph.Apply.
+type Model interface {
+	// URI returns the URI of the graph or dataset
+	URI() string
+	// Len returns the number of statements
+	Len() int
+	// Quads iterates over every statement
+	Quads() iter.Seq[*Quad]
+	// Match iterates over the statements matching a pattern of S, P, O
+	// objects within graph g, nil being the default graph
+	Match(s Term, p Term, o Term, g Term) iter.Seq[*Quad]
+	// Insert adds a statement, unless an equal one is already present
+	Insert(q *Quad)
+	// Delete removes every statement equal to q
+	Delete(q *Quad)
+	// Apply applies a change set, removing statements first
+	Apply(c *ChangeSet)
+	// Parse adds the statements read from reader in the given format
+	Parse(reader io.Reader, mime string) error
+	// Serialize writes the statements to w in the given format
+	Serialize(w io.Writer, mime string) error
+}
+
+var (
+	_ Model = (*Graph)(nil)
+	_ Model = (*Dataset)(nil)
+)
+
+// Quads returns an iterator over the triples of the graph, as quads of the
+// default graph
+func (g *Graph) Quads() iter.Seq[*Quad] {
+	return func(yield func(*Quad) bool) {
+		for triple := range g.Triples() {
+			if !yield(NewTripleQuad(triple)) {
+				return
+			}
+		}
+	}
+}
+
+// Match returns an iterator over the triples matching a pattern of S, P, O
+// objects, as quads of the default graph. Unlike All, an empty pattern
+// matches every triple. The graph term is ignored.
+func (g *Graph) Match(s Term, p Term, o Term, _ Term) iter.Seq[*Quad] {
+	return func(yield func(*Quad) bool) {
+		g.match(s, p, o, func(triple *Triple) bool {
+			return yield(NewTripleQuad(triple))
+		})
+	}
+}
+
+// Insert adds the triple of a quad, unless an equal triple is already present
+func (g *Graph) Insert(q *Quad) {
+	if g.One(q.Subject, q.Predicate, q.Object) == nil {
+		g.Add(q.ToTriple())
+	}
+}
+
+// Delete removes every triple equal to the triple of a quad
+func (g *Graph) Delete(q *Quad) {
+	for _, triple := range g.All(q.Subject, q.Predicate, q.Object) {
+		g.Remove(triple)
+	}
+}
+
+// Match returns an iterator over the quads matching a pattern of S, P, O
+// objects within graph g, nil being the default graph
+func (d *Dataset) Match(s Term, p Term, o Term, g Term) iter.Seq[*Quad] {
+	return func(yield func(*Quad) bool) {
+		d.match(s, p, o, g, yield)
+	}
+}
+
+// Insert adds a quad, unless an equal quad is already present
+func (d *Dataset) Insert(q *Quad) {
+	if d.One(q.Subject, q.Predicate, q.Object, storedGraph(q.Graph)) == nil {
+		d.Add(q)
+	}
+}
+
+// Delete removes every quad equal to q
+func (d *Dataset) Delete(q *Quad) {
+	for _, quad := range d.All(q.Subject, q.Predicate, q.Object, storedGraph(q.Graph)) {
+		d.Remove(quad)
+	}
+}
+
+// modelQuads returns the statements of a model in a deterministic order:
+// insertion order for datasets, N-Quads order otherwise
+func modelQuads(m Model) []*Quad {
+	if d, ok := m.(*Dataset); ok {
+		return d.orderedQuads()
+	}
+	var quads []*Quad
+	for q := range m.Quads() {
+		quads = append(quads, q)
+	}
+	sortQuads(quads)
+	return quads
+}
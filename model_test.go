@@ -0,0 +1,67 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel(t *testing.T) {
+	for _, m := range []Model{NewGraph(testUri), NewDataset(testDatasetUri), NewUnindexedGraph(testUri), NewUnindexedDataset(testDatasetUri)} {
+		a := NewResource("http://example.org/a")
+		p := NewResource("http://example.org/p")
+		m.Insert(NewQuad(a, p, NewLiteral("1"), nil))
+		m.Insert(NewQuad(a, p, NewLiteral("1"), nil))
+		m.Insert(NewQuad(a, p, NewLiteral("2"), nil))
+		assert.Equal(t, 2, m.Len())
+
+		n := 0
+		for q := range m.Match(nil, nil, nil, nil) {
+			assert.Nil(t, q.Graph)
+			n++
+		}
+		assert.Equal(t, 2, n)
+		n = 0
+		for range m.Match(nil, nil, NewLiteral("2"), nil) {
+			n++
+		}
+		assert.Equal(t, 1, n)
+
+		m.Delete(NewQuad(a, p, NewLiteral("1"), nil))
+		assert.Equal(t, 1, m.Len())
+
+		buf := new(bytes.Buffer)
+		assert.NoError(t, m.Serialize(buf, "application/n-quads"))
+		assert.Equal(t, "<http://example.org/a> <http://example.org/p> \"2\" .\n", buf.String())
+		assert.NoError(t, m.Parse(strings.NewReader(buf.String()+"<http://example.org/b> <http://example.org/p> \"3\" .\n"), "application/n-quads"))
+		assert.Equal(t, 3, m.Len())
+	}
+}
+
+func TestGraphModelIgnoresGraphTerm(t *testing.T) {
+	g := NewGraph(testUri)
+	g.Insert(NewQuad(NewResource("a"), NewResource("p"), NewResource("c"), NewResource("g")))
+	assert.Equal(t, 1, g.Len())
+	n := 0
+	for range g.Match(nil, nil, nil, NewResource("other")) {
+		n++
+	}
+	assert.Equal(t, 1, n)
+}
+
+func TestDiffModels(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewBlankNode("x"))
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewBlankNode("y"), nil)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("z"), NewResource("http://example.org/g"))
+
+	c, err := Diff(g, d)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(c.Removed))
+	if assert.Equal(t, 1, len(c.Added)) {
+		assert.Equal(t, NewResource("http://example.org/g"), c.Added[0].Graph)
+	}
+}
@@ -0,0 +1,36 @@
+package rdf2go
+
+// Namespace is the IRI shared by a vocabulary, from which its terms are built
+type Namespace string
+
+// Well-known namespaces
+const (
+	RDF      Namespace = rdfNamespace
+	RDFS     Namespace = rdfsNamespace
+	XSD      Namespace = xsdNamespace
+	OWL      Namespace = owlNamespace
+	FOAF     Namespace = "http://xmlns.com/foaf/0.1/"
+	DCTerms  Namespace = "http://purl.org/dc/terms/"
+	Schema   Namespace = "https://schema.org/"
+	LDP      Namespace = "http://www.w3.org/ns/ldp#"
+	Security Namespace = "https://w3id.org/security#"
+	DCAT     Namespace = "http://www.w3.org/ns/dcat#"
+	SKOS     Namespace = "http://www.w3.org/2004/02/skos/core#"
+	EARL     Namespace = "http://www.w3.org/ns/earl#"
+	CSVW     Namespace = "http://www.w3.org/ns/csvw#"
+)
+
+// NewNamespace returns the namespace with the given IRI
+func NewNamespace(iri string) Namespace {
+	return Namespace(iri)
+}
+
+// Get returns the resource of the namespace with the given local name
+func (ns Namespace) Get(name string) Term {
+	return NewResource(string(ns) + name)
+}
+
+// IRI returns the IRI of the namespace
+func (ns Namespace) IRI() string {
+	return string(ns)
+}
@@ -0,0 +1,22 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespace(t *testing.T) {
+	ns := NewNamespace("http://example.org/vocab#")
+	assert.Equal(t, NewResource("http://example.org/vocab#name"), ns.Get("name"))
+	assert.Equal(t, "http://example.org/vocab#", ns.IRI())
+
+	assert.Equal(t, NewResource("http://www.w3.org/1999/02/22-rdf-syntax-ns#type"), RDF.Get("type"))
+	assert.Equal(t, NewResource("http://www.w3.org/2001/XMLSchema#integer"), XSD.Get("integer"))
+	assert.Equal(t, NewResource("http://xmlns.com/foaf/0.1/name"), FOAF.Get("name"))
+
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Bind("foaf", FOAF.IRI()))
+	g.AddTriple(NewResource(testUri), FOAF.Get("name"), NewLiteral("Test"))
+	assert.Len(t, g.All(nil, FOAF.Get("name"), nil), 1)
+}
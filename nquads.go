@@ -0,0 +1,358 @@
+package rdf2go
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxNQuadsLine is the longest statement accepted by the N-Quads parser
+const maxNQuadsLine = 64 * 1024 * 1024
+
+// parseNQuads reads N-Quads (or N-Triples) statements from reader and calls
+// fn for each of them. Terms and quads are allocated from arena, which may be
+// nil. Unless malformed is nil, malformed statements are passed to it and
+// skipped.
+func parseNQuads(reader io.Reader, arena *TermArena, malformed func(error) error, fn func(*Quad) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNQuadsLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		quad, err := parseNQuadsLine(scanner.Text(), arena)
+		if err != nil && scanner.Err() != nil {
+			// the last line is truncated by the error of the reader
+			return scanner.Err()
+		}
+		if err != nil {
+			err = fmt.Errorf("line %d: %s", line, err)
+			if malformed == nil {
+				return err
+			}
+			if err := malformed(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if quad == nil {
+			continue
+		}
+		if err := fn(quad); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseNQuadsLine parses a single N-Quads statement. It returns a nil quad for
+// empty lines and comments.
+func parseNQuadsLine(line string, arena *TermArena) (*Quad, error) {
+	l := &lineLexer{s: line, arena: arena}
+	l.skipSpace()
+	if l.done() || l.peek() == '#' {
+		return nil, nil
+	}
+
+	s, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.(*Literal); ok {
+		return nil, errors.New("a literal cannot be used as subject")
+	}
+	p, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.(*Resource); !ok {
+		return nil, errors.New("predicate must be an IRI")
+	}
+	o, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+
+	var g Term
+	l.skipSpace()
+	if !l.done() && l.peek() != '.' {
+		g, err = l.term()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := g.(*Literal); ok {
+			return nil, errors.New("a literal cannot be used as graph label")
+		}
+	}
+
+	l.skipSpace()
+	if l.done() || l.peek() != '.' {
+		return nil, errors.New("expected '.' at the end of the statement")
+	}
+	l.pos++
+	l.skipSpace()
+	if !l.done() && l.peek() != '#' {
+		return nil, fmt.Errorf("unexpected content after '.': %q", l.s[l.pos:])
+	}
+	return l.arena.NewQuad(s, p, o, g), nil
+}
+
+// lineLexer reads N-Triples terms from a single line
+type lineLexer struct {
+	s     string
+	pos   int
+	arena *TermArena
+}
+
+func (l *lineLexer) done() bool {
+	return l.pos >= len(l.s)
+}
+
+func (l *lineLexer) peek() byte {
+	return l.s[l.pos]
+}
+
+func (l *lineLexer) skipSpace() {
+	for !l.done() && (l.s[l.pos] == ' ' || l.s[l.pos] == '\t' || l.s[l.pos] == '\r') {
+		l.pos++
+	}
+}
+
+func (l *lineLexer) term() (Term, error) {
+	l.skipSpace()
+	if l.done() {
+		return nil, errors.New("unexpected end of statement")
+	}
+	switch l.peek() {
+	case '<':
+		if strings.HasPrefix(l.s[l.pos:], "<<") {
+			return l.quotedTriple()
+		}
+		iri, err := l.iri()
+		if err != nil {
+			return nil, err
+		}
+		return l.arena.NewResource(iri), nil
+	case '_':
+		return l.blankNode()
+	case '"':
+		return l.literal()
+	}
+	return nil, fmt.Errorf("unexpected character %q", l.peek())
+}
+
+// quotedTriple reads an N-Triples-star quoted triple, << s p o >>
+func (l *lineLexer) quotedTriple() (Term, error) {
+	l.pos += 2
+	s, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.(*Literal); ok {
+		return nil, errors.New("a literal cannot be used as subject")
+	}
+	p, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.(*Resource); !ok {
+		return nil, errors.New("predicate must be an IRI")
+	}
+	o, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	l.skipSpace()
+	if !strings.HasPrefix(l.s[l.pos:], ">>") {
+		return nil, errors.New("expected '>>' at the end of the quoted triple")
+	}
+	l.pos += 2
+	return NewQuotedTriple(s, p, o), nil
+}
+
+func (l *lineLexer) iri() (string, error) {
+	start := l.pos + 1
+	end := strings.IndexByte(l.s[start:], '>')
+	if end < 0 {
+		return "", errors.New("unterminated IRI")
+	}
+	raw := l.s[start : start+end]
+	l.pos = start + end + 1
+	if strings.ContainsAny(raw, " <\"{}|^`") {
+		return "", fmt.Errorf("invalid character in IRI <%s>", raw)
+	}
+	if strings.IndexByte(raw, '\\') < 0 {
+		return raw, nil
+	}
+	return unescapeString(raw)
+}
+
+func (l *lineLexer) blankNode() (Term, error) {
+	if !strings.HasPrefix(l.s[l.pos:], "_:") {
+		return nil, errors.New("invalid blank node")
+	}
+	start := l.pos + 2
+	end := start
+	for end < len(l.s) && !isTermDelimiter(l.s[end]) {
+		end++
+	}
+	// a label may contain dots, but never ends with one
+	for end > start && l.s[end-1] == '.' {
+		end--
+	}
+	if end == start {
+		return nil, errors.New("empty blank node label")
+	}
+	l.pos = end
+	return l.arena.NewBlankNode(l.s[start:end]), nil
+}
+
+func (l *lineLexer) literal() (Term, error) {
+	start := l.pos + 1
+	end := start
+	escaped := false
+	for ; end < len(l.s); end++ {
+		c := l.s[end]
+		if c == '\\' {
+			escaped = true
+			end++
+			continue
+		}
+		if c == '"' {
+			break
+		}
+	}
+	if end >= len(l.s) {
+		return nil, errors.New("unterminated literal")
+	}
+	value := l.s[start:end]
+	if escaped {
+		var err error
+		if value, err = unescapeString(value); err != nil {
+			return nil, err
+		}
+	}
+	l.pos = end + 1
+
+	if !l.done() && l.peek() == '@' {
+		langStart := l.pos + 1
+		langEnd := langStart
+		for langEnd < len(l.s) && (isAlphaNum(l.s[langEnd]) || l.s[langEnd] == '-') {
+			langEnd++
+		}
+		if langEnd == langStart {
+			return nil, errors.New("empty language tag")
+		}
+		l.pos = langEnd
+		return l.arena.NewLiteralWithLanguage(value, l.s[langStart:langEnd]), nil
+	}
+	if strings.HasPrefix(l.s[l.pos:], "^^") {
+		l.pos += 2
+		if l.done() || l.peek() != '<' {
+			return nil, errors.New("datatype must be an IRI")
+		}
+		dt, err := l.iri()
+		if err != nil {
+			return nil, err
+		}
+		return l.arena.NewLiteralWithDatatype(value, l.arena.NewResource(dt)), nil
+	}
+	return l.arena.NewLiteral(value), nil
+}
+
+func isTermDelimiter(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '<' || c == '>' || c == '"'
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// unescapeString resolves the ECHAR and UCHAR escape sequences of N-Triples
+// strings and IRIs
+func unescapeString(s string) (string, error) {
+	b, err := appendUnescaped(make([]byte, 0, len(s)), s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// appendUnescaped appends s to dst, resolving escape sequences. The result is
+// never longer than s.
+func appendUnescaped[S string | []byte](dst []byte, s S) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			dst = append(dst, c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return nil, errors.New("incomplete escape sequence")
+		}
+		switch s[i] {
+		case 't':
+			dst = append(dst, '\t')
+		case 'b':
+			dst = append(dst, '\b')
+		case 'n':
+			dst = append(dst, '\n')
+		case 'r':
+			dst = append(dst, '\r')
+		case 'f':
+			dst = append(dst, '\f')
+		case '"':
+			dst = append(dst, '"')
+		case '\'':
+			dst = append(dst, '\'')
+		case '\\':
+			dst = append(dst, '\\')
+		case 'u', 'U':
+			n := 4
+			if s[i] == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return nil, errors.New("incomplete unicode escape sequence")
+			}
+			code, err := strconv.ParseUint(string(s[i+1:i+1+n]), 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return nil, fmt.Errorf("invalid unicode escape sequence \\%s", s[i:i+1+n])
+			}
+			dst = utf8.AppendRune(dst, rune(code))
+			i += n
+		default:
+			return nil, fmt.Errorf("invalid escape sequence \\%c", s[i])
+		}
+	}
+	return dst, nil
+}
+
+// writeAnnotatedNQuads writes statements as N-Quads sorted by graph and
+// subject, introducing each subject, and with graphs set each graph, with a
+// comment
+func writeAnnotatedNQuads(b *strings.Builder, quads []*Quad, source string, graphs bool) {
+	sortQuads(quads)
+	sort.SliceStable(quads, func(i, j int) bool {
+		return termKey(quads[i].Graph) < termKey(quads[j].Graph)
+	})
+	b.WriteString(sourceComment(source))
+	for i, quad := range quads {
+		newGraph := i == 0 || termKey(quad.Graph) != termKey(quads[i-1].Graph)
+		if newGraph && graphs {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(graphComment(quad.Graph))
+		}
+		if newGraph || termKey(quad.Subject) != termKey(quads[i-1].Subject) {
+			b.WriteString("# subject: " + encodeTerm(quad.Subject) + "\n")
+		}
+		b.WriteString(quad.String() + "\n")
+	}
+}
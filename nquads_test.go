@@ -0,0 +1,89 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var simpleNQuads = `# a comment
+<http://example.org/alice> <http://xmlns.com/foaf/0.1/name> "Alice" .
+<http://example.org/alice> <http://xmlns.com/foaf/0.1/knows> _:b1 <http://example.org/graph1> .
+_:b1 <http://xmlns.com/foaf/0.1/name> "Bob\t\"B\"é"@en <http://example.org/graph1> .
+
+<http://example.org/alice> <http://xmlns.com/foaf/0.1/age> "28"^^<http://www.w3.org/2001/XMLSchema#integer> .
+`
+
+func TestParseNQuadsLine(t *testing.T) {
+	quad, err := parseNQuadsLine(`<a> <b> "c\nd"@en-GB <g> . # trailing`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "<a>", quad.Subject.String())
+	assert.Equal(t, NewLiteralWithLanguage("c\nd", "en-GB"), quad.Object)
+	assert.Equal(t, NewResource("g"), quad.Graph)
+
+	quad, err = parseNQuadsLine(`_:x.y <b> _:z.`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, NewBlankNode("x.y"), quad.Subject)
+	assert.Equal(t, NewBlankNode("z"), quad.Object)
+	assert.Nil(t, quad.Graph)
+
+	quad, err = parseNQuadsLine("   ", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, quad)
+
+	for _, bad := range []string{
+		`<a> <b> <c>`,
+		`"a" <b> <c> .`,
+		`<a> _:b <c> .`,
+		`<a> <b> "c .`,
+		`<a> <b> <c> "g" .`,
+		`<a> <b> <c> . <d>`,
+		`<a b> <b> <c> .`,
+		`<a> <b> "\q" .`,
+	} {
+		_, err := parseNQuadsLine(bad, nil)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestDatasetParseNQuads(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	err := d.Parse(strings.NewReader(simpleNQuads), "application/n-quads")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, d.Len())
+	assert.Equal(t, 2, d.GetGraph(NewResource("http://example.org/graph1")).Len())
+	assert.NotNil(t, d.One(NewBlankNode("b1"), nil, NewLiteralWithLanguage("Bob\t\"B\"é", "en"), NewResource("http://example.org/graph1")))
+
+	err = NewDataset(testDatasetUri).Parse(strings.NewReader("<a> <b> <c> .\n<a> <b>"), "application/n-quads")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestDatasetNQuadsRoundtrip(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(simpleNQuads), "application/n-quads"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, d.Serialize(&buf, "application/n-quads"))
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(&buf, "application/n-quads"))
+	assert.Equal(t, d.Len(), d2.Len())
+}
+
+func TestGraphParseNTriples(t *testing.T) {
+	g := NewGraph(testUri)
+	err := g.Parse(strings.NewReader("<a> <b> <c> .\n<a> <b> \"d\" .\n"), "application/n-triples")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, g.Len())
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.Serialize(&buf, "application/n-triples"))
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(&buf, "application/n-triples"))
+	assert.Equal(t, 2, g2.Len())
+
+	err = NewGraph(testUri).Parse(strings.NewReader("<a> <b> <c> <g> .\n"), "application/n-triples")
+	assert.Error(t, err)
+}
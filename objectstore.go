@@ -0,0 +1,141 @@
+package rdf2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ObjectStore reads and writes the objects of a cloud storage service, such
+// as Amazon S3 or Google Cloud Storage. Object stores are registered per URI
+// scheme with WithObjectStore, after which LoadURI reads and SaveURI writes
+// URIs of the form scheme://bucket/key.
+type ObjectStore interface {
+	// GetObject opens an object, returning its content and content type
+	GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, string, error)
+	// PutObject writes an object, reading its content from body until EOF
+	PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error
+}
+
+// objectStore returns the object store registered for the scheme of uri,
+// along with the bucket and key it names
+func (c *Config) objectStore(uri string) (ObjectStore, string, string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || len(u.Host) == 0 {
+		return nil, "", "", false
+	}
+	store, ok := c.ObjectStores[u.Scheme]
+	if !ok {
+		return nil, "", "", false
+	}
+	return store, u.Host, strings.TrimPrefix(u.Path, "/"), true
+}
+
+// fetchObject reads the RDF document stored in an object, see fetchRDF
+func (c *Config) fetchObject(ctx context.Context, store ObjectStore, bucket string, key string, uri string, what string, parse func(io.Reader, string) error) (*LoadResult, error) {
+	r, contentType, err := store.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("Could not fetch %s from %s - %s", what, uri, err)
+	}
+	defer r.Close()
+	c.log(slog.LevelDebug, "fetched RDF object", "uri", uri, "contentType", contentType)
+	codec, name := CodecForPath(key)
+	res := &LoadResult{URL: defrag(uri)}
+	if codec == nil {
+		res.ContentType = objectMime(contentType, key)
+		return res, c.parseFetched(r, res, parse)
+	}
+	// the content type of compressed objects may describe either the
+	// compressed or the decompressed document
+	dr, err := codec.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+	res.ContentType = objectMime(contentType, name)
+	return res, c.parseFetched(dr, res, parse)
+}
+
+// objectMime returns the mime type of an object, guessed from the extension
+// of its key when the store does not record a known RDF type
+func objectMime(contentType string, key string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && len(mimeParser[mediaType]) > 0 {
+		return mediaType
+	}
+	if mimeType, ok := mimeRdfExt[path.Ext(key)]; ok {
+		return mimeType
+	}
+	return contentType
+}
+
+// SaveURI serializes the graph to an object store URI, such as
+// s3://bucket/key, in the format of the given mime type. An empty mime type
+// is guessed from the extension of the key. The object is compressed when
+// the key ends with the extension of a codec, such as dump.nt.gz, or else
+// with the codec of the configuration. LoadURI decompresses objects after
+// the extension of their key.
+func (g *Graph) SaveURI(uri string, mime string) error {
+	return g.SaveURIContext(context.Background(), uri, mime)
+}
+
+// SaveURIContext serializes the graph to an object store URI, see SaveURI.
+// The upload is aborted when the context is cancelled or its deadline
+// expires.
+func (g *Graph) SaveURIContext(ctx context.Context, uri string, mime string) error {
+	return g.config.putObject(ctx, uri, mime, g.Serialize)
+}
+
+// SaveURI serializes the dataset to an object store URI, see Graph.SaveURI
+func (d *Dataset) SaveURI(uri string, mime string) error {
+	return d.SaveURIContext(context.Background(), uri, mime)
+}
+
+// SaveURIContext serializes the dataset to an object store URI, see
+// Graph.SaveURIContext
+func (d *Dataset) SaveURIContext(ctx context.Context, uri string, mime string) error {
+	return d.config.putObject(ctx, uri, mime, d.Serialize)
+}
+
+// putObject streams the output of serialize to the object named by uri
+func (c *Config) putObject(ctx context.Context, uri string, mime string, serialize func(io.Writer, string) error) error {
+	store, bucket, key, ok := c.objectStore(uri)
+	if !ok {
+		return errors.New("no object store is registered for " + uri)
+	}
+	codec, name := CodecForPath(key)
+	if codec == nil {
+		codec = c.Codec
+	}
+	if len(mime) == 0 {
+		mime = mimeRdfExt[path.Ext(name)]
+	}
+	if len(mimeSerializer[mime]) == 0 && mime != "text/turtle" {
+		return fmt.Errorf("%q is not supported by the serializer", mime)
+	}
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := compressTo(w, codec, func(w io.Writer) error { return serialize(w, mime) })
+		w.CloseWithError(err)
+		done <- err
+	}()
+	err := store.PutObject(ctx, bucket, key, r, mime)
+	// unblock the serializer when the store stopped reading early, and wait
+	// for it so that it no longer reads the graph once putObject returns
+	r.CloseWithError(errors.New("the object store stopped reading the upload"))
+	serr := <-done
+	if err != nil {
+		return err
+	}
+	if serr != nil {
+		return serr
+	}
+	c.log(slog.LevelDebug, "stored RDF object", "uri", uri, "contentType", mime)
+	return nil
+}
@@ -0,0 +1,127 @@
+package rdf2go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoadObjectURI(t *testing.T) {
+	fake, server, store := newFakeS3()
+	defer server.Close()
+
+	g := NewGraphWithOptions("", WithObjectStore("s3", store))
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	assert.NoError(t, g.SaveURI("s3://bucket/dumps/a.nt", ""))
+	assert.Equal(t, "application/n-triples", fake.types["/bucket/dumps/a.nt"])
+	assert.Error(t, g.SaveURI("s3://bucket/dumps/a.xyz", ""))
+	assert.Error(t, g.SaveURI("gs://bucket/dumps/a.nt", ""))
+
+	g2 := NewGraphWithOptions("", WithObjectStore("s3", store))
+	assert.NoError(t, g2.LoadURI("s3://bucket/dumps/a.nt"))
+	assert.Equal(t, "s3://bucket/dumps/a.nt", g2.URI())
+	assert.Equal(t, 1, g2.Len())
+	assert.Error(t, g2.LoadURI("s3://bucket/missing.nt"))
+
+	d := NewDatasetWithOptions("", WithObjectStore("s3", store))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://example.org/c"), NewResource("http://example.org/g"))
+	assert.NoError(t, d.SaveURIContext(context.Background(), "s3://bucket/all", "application/trig"))
+	// the extension of the key is used when the stored type is not RDF
+	fake.types["/bucket/all"] = "binary/octet-stream"
+	fake.objects["/bucket/all.trig"], fake.types["/bucket/all.trig"] = fake.objects["/bucket/all"], "binary/octet-stream"
+	d2 := NewDatasetWithOptions("", WithObjectStore("s3", store))
+	assert.NoError(t, d2.LoadURI("s3://bucket/all.trig"))
+	assert.NotNil(t, d2.One(nil, nil, nil, NewResource("http://example.org/g")))
+}
+
+type failingStore struct{}
+
+func (failingStore) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, string, error) {
+	return nil, "", errors.New("unavailable")
+}
+
+func (failingStore) PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error {
+	return errors.New("unavailable")
+}
+
+func TestSaveObjectURIError(t *testing.T) {
+	g := NewGraphWithOptions("", WithObjectStore("mem", failingStore{}))
+	for i := 0; i < 1000; i++ {
+		g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral(strconv.Itoa(i)))
+	}
+	assert.EqualError(t, g.SaveURI("mem://bucket/a.nt", ""), "unavailable")
+	assert.Error(t, g.LoadURI("mem://bucket/a.nt"))
+}
+
+// shortStore reads the first bytes of an upload and reports success
+type shortStore struct{}
+
+func (shortStore) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, string, error) {
+	return nil, "", errors.New("unavailable")
+}
+
+func (shortStore) PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error {
+	_, err := body.Read(make([]byte, 16))
+	return err
+}
+
+func TestSaveObjectURIShortRead(t *testing.T) {
+	g := NewGraphWithOptions("", WithObjectStore("mem", shortStore{}))
+	for i := 0; i < 1000; i++ {
+		g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral(strconv.Itoa(i)))
+	}
+	// the truncated upload is reported, and the serializer is done once
+	// SaveURI returns
+	assert.Error(t, g.SaveURI("mem://bucket/a.nt", ""))
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("last"))
+	assert.Error(t, g.LoadURI("mem://bucket/a.nt"))
+	assert.Equal(t, 1001, g.Len())
+}
+
+func TestGCSStore(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/upload/storage/v1/b/bucket/o":
+			assert.Equal(t, "media", req.URL.Query().Get("uploadType"))
+			body, _ := io.ReadAll(req.Body)
+			objects[req.URL.Query().Get("name")] = string(body)
+			w.Write([]byte("{}"))
+		case req.Method == http.MethodGet && req.URL.Query().Get("alt") == "media":
+			object, ok := objects[req.URL.Path[len("/storage/v1/b/bucket/o/"):]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "text/turtle; charset=utf-8")
+			w.Write([]byte(object))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	store := NewGCSStore(func(ctx context.Context) (string, error) { return "token", nil })
+	store.Endpoint = server.URL
+
+	g := NewGraphWithOptions("", WithObjectStore("gs", store))
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	assert.NoError(t, g.SaveURI("gs://bucket/a/b.ttl", ""))
+	assert.Contains(t, objects["a/b.ttl"], `"c"`)
+
+	g2 := NewGraphWithOptions("", WithObjectStore("gs", store))
+	assert.NoError(t, g2.LoadURI("gs://bucket/a/b.ttl"))
+	assert.Equal(t, 1, g2.Len())
+
+	store.Token = func(ctx context.Context) (string, error) { return "", errors.New("expired") }
+	assert.Error(t, g.SaveURI("gs://bucket/a/b.ttl", ""))
+}
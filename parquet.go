@@ -0,0 +1,327 @@
+package rdf2go
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+)
+
+// parquetRowGroupSize is the number of quads written per Parquet row group
+const parquetRowGroupSize = 65536
+
+// parquetColumns lists the columns of the Parquet export. Every column is an
+// optional UTF-8 string; graph and graph_kind are null for the default graph.
+var parquetColumns = []string{
+	"subject", "subject_kind",
+	"predicate",
+	"object", "object_kind", "object_datatype", "object_lang",
+	"graph", "graph_kind",
+}
+
+// termKind returns the kind of a term as used in tabular exports
+func termKind(t Term) string {
+	switch t.(type) {
+	case *Resource:
+		return "iri"
+	case *BlankNode:
+		return "bnode"
+	case *Literal:
+		return "literal"
+	case *QuotedTriple:
+		return "triple"
+	}
+	return ""
+}
+
+// parquetRow returns the column values of a quad, nil standing for null
+func parquetRow(q *Quad) []*string {
+	str := func(s string) *string { return &s }
+	row := make([]*string, len(parquetColumns))
+	row[0], row[1] = str(q.Subject.RawValue()), str(termKind(q.Subject))
+	row[2] = str(q.Predicate.RawValue())
+	row[3], row[4] = str(q.Object.RawValue()), str(termKind(q.Object))
+	if lit, ok := q.Object.(*Literal); ok {
+		if len(lit.Language) > 0 {
+			row[6] = str(lit.Language)
+		} else if lit.Datatype != nil {
+			row[5] = str(lit.Datatype.RawValue())
+		}
+	}
+	if q.Graph != nil {
+		row[7], row[8] = str(q.Graph.RawValue()), str(termKind(q.Graph))
+	}
+	return row
+}
+
+// ExportParquet writes the quads of the dataset to w as an Apache Parquet
+// file, in insertion order. Each row holds the lexical value of every term
+// together with its kind (iri, bnode or literal), and the datatype or
+// language of literal objects, so that the file can be queried directly with
+// tools such as DuckDB or Spark. Pages are written uncompressed with the
+// PLAIN encoding.
+func (d *Dataset) ExportParquet(w io.Writer) error {
+	pw := &parquetWriter{w: bufio.NewWriter(w)}
+	pw.write([]byte("PAR1"))
+	quads := d.orderedQuads()
+	var groups []parquetRowGroup
+	for start := 0; start < len(quads); start += parquetRowGroupSize {
+		end := start + parquetRowGroupSize
+		if end > len(quads) {
+			end = len(quads)
+		}
+		groups = append(groups, pw.rowGroup(quads[start:end]))
+	}
+	footer := parquetFileMetaData(len(quads), groups)
+	pw.write(footer)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	pw.write(size[:])
+	pw.write([]byte("PAR1"))
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
+
+type parquetWriter struct {
+	w      *bufio.Writer
+	offset int64
+	err    error
+}
+
+func (pw *parquetWriter) write(b []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int
+}
+
+type parquetRowGroup struct {
+	columns []parquetColumnChunk
+	rows    int
+	size    int64
+}
+
+// rowGroup writes one data page per column for the given quads
+func (pw *parquetWriter) rowGroup(quads []*Quad) parquetRowGroup {
+	rows := make([][]*string, len(quads))
+	for i, q := range quads {
+		rows[i] = parquetRow(q)
+	}
+	group := parquetRowGroup{rows: len(quads)}
+	for col := range parquetColumns {
+		levels := make([]bool, len(rows))
+		var values []byte
+		for i, row := range rows {
+			if v := row[col]; v != nil {
+				levels[i] = true
+				values = binary.LittleEndian.AppendUint32(values, uint32(len(*v)))
+				values = append(values, *v...)
+			}
+		}
+		encoded := encodeDefinitionLevels(levels)
+		page := binary.LittleEndian.AppendUint32(nil, uint32(len(encoded)))
+		page = append(page, encoded...)
+		page = append(page, values...)
+
+		header := parquetPageHeader(len(rows), len(page))
+		chunk := parquetColumnChunk{offset: pw.offset, numValues: len(rows)}
+		pw.write(header)
+		pw.write(page)
+		chunk.size = pw.offset - chunk.offset
+		group.size += chunk.size
+		group.columns = append(group.columns, chunk)
+	}
+	return group
+}
+
+// encodeDefinitionLevels encodes 1-bit definition levels with the RLE
+// variant of the RLE/bit-packing hybrid encoding
+func encodeDefinitionLevels(levels []bool) []byte {
+	var b []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		b = binary.AppendUvarint(b, uint64(j-i)<<1)
+		if levels[i] {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+		i = j
+	}
+	return b
+}
+
+// Parquet enumerations used by the writer
+const (
+	parquetTypeByteArray    = 6
+	parquetRepetitionOption = 1
+	parquetConvertedUTF8    = 0
+	parquetEncodingPlain    = 0
+	parquetEncodingRLE      = 3
+	parquetCodecNone        = 0
+	parquetPageData         = 0
+)
+
+func parquetPageHeader(numValues int, size int) []byte {
+	var t thriftCompactWriter
+	t.i32(1, parquetPageData)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.beginStruct(5)
+	t.i32(1, int32(numValues))
+	t.i32(2, parquetEncodingPlain)
+	t.i32(3, parquetEncodingRLE)
+	t.i32(4, parquetEncodingRLE)
+	t.endStruct()
+	t.stop()
+	return t.buf
+}
+
+func parquetFileMetaData(numRows int, groups []parquetRowGroup) []byte {
+	var t thriftCompactWriter
+	t.i32(1, 1)
+	t.beginList(2, thriftStruct, len(parquetColumns)+1)
+	t.binary(4, "schema")
+	t.i32(5, int32(len(parquetColumns)))
+	t.stop()
+	for _, name := range parquetColumns {
+		t.i32(1, parquetTypeByteArray)
+		t.i32(3, parquetRepetitionOption)
+		t.binary(4, name)
+		t.i32(6, parquetConvertedUTF8)
+		t.stop()
+	}
+	t.endList()
+	t.i64(3, int64(numRows))
+	t.beginList(4, thriftStruct, len(groups))
+	for _, g := range groups {
+		t.beginList(1, thriftStruct, len(g.columns))
+		for i, c := range g.columns {
+			t.i64(2, c.offset)
+			t.beginStruct(3)
+			t.i32(1, parquetTypeByteArray)
+			t.beginList(2, thriftI32, 2)
+			t.listI32(parquetEncodingPlain)
+			t.listI32(parquetEncodingRLE)
+			t.endList()
+			t.beginList(3, thriftBinary, 1)
+			t.listBinary(parquetColumns[i])
+			t.endList()
+			t.i32(4, parquetCodecNone)
+			t.i64(5, int64(c.numValues))
+			t.i64(6, c.size)
+			t.i64(7, c.size)
+			t.i64(9, c.offset)
+			t.endStruct()
+			t.stop()
+		}
+		t.endList()
+		t.i64(2, g.size)
+		t.i64(3, int64(g.rows))
+		t.stop()
+	}
+	t.endList()
+	t.binary(6, "rdf2go")
+	t.stop()
+	return t.buf
+}
+
+// Thrift compact protocol type identifiers
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftCompactWriter encodes the subset of the Thrift compact protocol
+// needed for Parquet metadata
+type thriftCompactWriter struct {
+	buf  []byte
+	last []int16
+	id   int16
+}
+
+func (t *thriftCompactWriter) field(id int16, typ byte) {
+	if delta := id - t.id; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.buf = binary.AppendVarint(t.buf, int64(id))
+	}
+	t.id = id
+}
+
+func (t *thriftCompactWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftCompactWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thriftCompactWriter) binary(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.listBinary(v)
+}
+
+func (t *thriftCompactWriter) listI32(v int32) {
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftCompactWriter) listBinary(v string) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(v)))
+	t.buf = append(t.buf, v...)
+}
+
+func (t *thriftCompactWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.last = append(t.last, t.id)
+	t.id = 0
+}
+
+func (t *thriftCompactWriter) endStruct() {
+	t.buf = append(t.buf, 0)
+	t.id = t.last[len(t.last)-1]
+	t.last = t.last[:len(t.last)-1]
+}
+
+// beginList starts a list field. Struct elements are written with field ids
+// relative to zero and terminated with stop.
+func (t *thriftCompactWriter) beginList(id int16, elem byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|elem)
+	} else {
+		t.buf = append(t.buf, 0xF0|elem)
+		t.buf = binary.AppendUvarint(t.buf, uint64(size))
+	}
+	t.last = append(t.last, t.id)
+	t.id = 0
+}
+
+func (t *thriftCompactWriter) endList() {
+	t.id = t.last[len(t.last)-1]
+	t.last = t.last[:len(t.last)-1]
+}
+
+// stop terminates a struct written as a list element or the outermost struct
+func (t *thriftCompactWriter) stop() {
+	t.buf = append(t.buf, 0)
+	t.id = 0
+}
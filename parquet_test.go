@@ -0,0 +1,163 @@
+package rdf2go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// thriftReader decodes Thrift compact structs into maps keyed by field id
+type thriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v, n := binary.Varint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.b[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		h := r.b[r.pos]
+		r.pos++
+		size, elem := int(h>>4), h&0x0F
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.structure()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) structure() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var id int16
+	for {
+		h := r.b[r.pos]
+		r.pos++
+		if h == 0 {
+			return fields
+		}
+		if delta := int16(h >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(h & 0x0F)
+	}
+}
+
+// readParquetStrings decodes the optional string columns of a file written
+// by ExportParquet
+func readParquetStrings(t *testing.T, file []byte) (map[string][]*string, map[int16]interface{}) {
+	assert.Equal(t, "PAR1", string(file[:4]))
+	assert.Equal(t, "PAR1", string(file[len(file)-4:]))
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	meta := (&thriftReader{b: file[len(file)-8-size : len(file)-8]}).structure()
+
+	columns := make(map[string][]*string)
+	for _, g := range meta[4].([]interface{}) {
+		for _, c := range g.(map[int16]interface{})[1].([]interface{}) {
+			md := c.(map[int16]interface{})[3].(map[int16]interface{})
+			name := md[3].([]interface{})[0].(string)
+			r := &thriftReader{b: file, pos: int(md[9].(int64))}
+			header := r.structure()
+			numValues := int(header[5].(map[int16]interface{})[1].(int64))
+
+			levelsEnd := r.pos + 4 + int(binary.LittleEndian.Uint32(file[r.pos:]))
+			r.pos += 4
+			var levels []bool
+			for r.pos < levelsEnd {
+				run := int(r.uvarint() >> 1)
+				set := file[r.pos] == 1
+				r.pos++
+				for i := 0; i < run; i++ {
+					levels = append(levels, set)
+				}
+			}
+			assert.Equal(t, numValues, len(levels))
+			for _, set := range levels {
+				if !set {
+					columns[name] = append(columns[name], nil)
+					continue
+				}
+				n := int(binary.LittleEndian.Uint32(file[r.pos:]))
+				s := string(file[r.pos+4 : r.pos+4+n])
+				r.pos += 4 + n
+				columns[name] = append(columns[name], &s)
+			}
+		}
+	}
+	return columns, meta
+}
+
+func TestDatasetExportParquet(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	g1 := NewResource("http://example.org/g1")
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteralWithLanguage("Alice", "en"))
+	d.AddQuad(NewBlankNode("n1"), NewResource("http://example.org/age"), NewLiteralWithDatatype("28", NewResource(xsdNamespace+"integer")), g1)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/knows"), NewBlankNode("n1"), g1)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, d.ExportParquet(buf))
+	columns, meta := readParquetStrings(t, buf.Bytes())
+
+	assert.Equal(t, int64(3), meta[3])
+	assert.Equal(t, len(parquetColumns)+1, len(meta[2].([]interface{})))
+
+	value := func(col string, row int) interface{} {
+		if v := columns[col][row]; v != nil {
+			return *v
+		}
+		return nil
+	}
+	assert.Equal(t, "http://example.org/a", value("subject", 0))
+	assert.Equal(t, "literal", value("object_kind", 0))
+	assert.Equal(t, "en", value("object_lang", 0))
+	assert.Nil(t, value("object_datatype", 0))
+	assert.Nil(t, value("graph", 0))
+
+	assert.Equal(t, "n1", value("subject", 1))
+	assert.Equal(t, "bnode", value("subject_kind", 1))
+	assert.Equal(t, xsdNamespace+"integer", value("object_datatype", 1))
+	assert.Equal(t, "http://example.org/g1", value("graph", 1))
+	assert.Equal(t, "iri", value("graph_kind", 1))
+
+	assert.Equal(t, "bnode", value("object_kind", 2))
+	assert.Nil(t, value("object_lang", 2))
+}
+
+func TestDatasetExportParquetEmpty(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, NewDataset(testDatasetUri).ExportParquet(buf))
+	_, meta := readParquetStrings(t, buf.Bytes())
+	assert.Equal(t, int64(0), meta[3])
+}
+
+func TestEncodeDefinitionLevels(t *testing.T) {
+	assert.Equal(t, []byte{6, 1, 2, 0}, encodeDefinitionLevels([]bool{true, true, true, false}))
+}
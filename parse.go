@@ -0,0 +1,216 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DuplicatePolicy tells how parsing handles the statements repeated within
+// a single input document, which often reveal a bug of the generator of the
+// document
+type DuplicatePolicy int
+
+const (
+	// DuplicatesKeep adds every parsed statement, leaving duplicates to the
+	// graph or dataset. This is the default.
+	DuplicatesKeep DuplicatePolicy = iota
+	// DuplicatesSkip silently adds the duplicated statements once
+	DuplicatesSkip
+	// DuplicatesCount adds the duplicated statements once, and logs a
+	// warning with the number of duplicates found
+	DuplicatesCount
+	// DuplicatesError makes parsing fail on the first duplicate, with a
+	// *DuplicateError
+	DuplicatesError
+)
+
+// DuplicateError is returned by Parse for a duplicated statement when the
+// duplicate policy is DuplicatesError
+type DuplicateError struct {
+	// Statement is the duplicated statement, with a nil graph for the
+	// default graph
+	Statement *Quad
+}
+
+func (e *DuplicateError) Error() string {
+	return "duplicate statement " + e.Statement.String()
+}
+
+// ParseErrors is returned by Parse in lenient mode, for documents with
+// malformed statements, which were skipped
+type ParseErrors struct {
+	// Errors lists the errors of the malformed statements, followed by the
+	// error that stopped the parse if any
+	Errors []error
+}
+
+func (e *ParseErrors) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0], len(e.Errors)-1)
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As
+func (e *ParseErrors) Unwrap() []error {
+	return e.Errors
+}
+
+// ParseStats summarizes what was read by ParseWithStats, for ingestion
+// pipelines to log and alert on anomalies
+type ParseStats struct {
+	// Added counts the statements added to the graph or dataset
+	Added int
+	// Duplicates counts the repeated statements of the document, which are
+	// only detected when the duplicate policy is not DuplicatesKeep
+	Duplicates int
+	// Prefixes maps the prefixes declared by Turtle and TriG documents to
+	// their namespaces
+	Prefixes map[string]string
+	// BlankNodes counts the distinct blank nodes of the added statements
+	BlankNodes int
+	// Skipped counts the malformed statements skipped in lenient mode
+	Skipped int
+	// Warnings describes the tolerated problems of the document, such as
+	// the statements of named graphs dropped by a Graph
+	Warnings []string
+	// Elapsed is the duration of the parse
+	Elapsed time.Duration
+}
+
+// parseTracker applies the duplicate policy of a configuration to the
+// statements of a document, and gathers its ParseStats
+type parseTracker struct {
+	config *Config
+	key    func(Term) string
+	// parsed counts the statements of the document, for its limits
+	parsed int
+	seen   map[string]bool
+	blanks map[string]bool
+	errs   []error
+	// graph receives the statements of the default graph of the document
+	graph Term
+	// source is the URI of the document recorded as the provenance of the
+	// statements, if any
+	source string
+	start  time.Time
+	stats  ParseStats
+}
+
+func newParseTracker(config *Config, policy *IRIPolicy) *parseTracker {
+	t := &parseTracker{
+		config: config,
+		key:    policy.key,
+		blanks: make(map[string]bool),
+		start:  time.Now(),
+		stats:  ParseStats{Prefixes: make(map[string]string)},
+	}
+	if config.Duplicates != DuplicatesKeep {
+		t.seen = make(map[string]bool)
+	}
+	return t
+}
+
+// admit returns whether a parsed statement is to be added, counting it
+// when it is
+func (t *parseTracker) admit(s Term, p Term, o Term, g Term) (bool, error) {
+	t.parsed++
+	if err := t.config.Limits.check(t.parsed, s, o); err != nil {
+		return false, err
+	}
+	if t.seen != nil {
+		key := fmt.Sprintf("%s %s %s %s", t.key(s), t.key(p), t.key(o), t.key(g))
+		if t.seen[key] {
+			t.stats.Duplicates++
+			if t.config.Duplicates == DuplicatesError {
+				return false, &DuplicateError{Statement: NewQuad(s, p, o, g)}
+			}
+			return false, nil
+		}
+		t.seen[key] = true
+	}
+	t.stats.Added++
+	for _, term := range []Term{s, o, g} {
+		if b, ok := term.(*BlankNode); ok && !t.blanks[b.String()] {
+			t.blanks[b.String()] = true
+			t.stats.BlankNodes++
+		}
+	}
+	return true, nil
+}
+
+// skipper returns the function receiving the errors of the malformed
+// statements to skip, nil unless parsing is lenient
+func (t *parseTracker) skipper() func(error) error {
+	if !t.config.Lenient {
+		return nil
+	}
+	return func(err error) error {
+		if errors.Is(err, ErrLimitExceeded) {
+			return err
+		}
+		t.errs = append(t.errs, err)
+		t.stats.Skipped++
+		return nil
+	}
+}
+
+// result returns the error of a parse, listing the errors of the skipped
+// statements in lenient mode
+func (t *parseTracker) result(err error) error {
+	if len(t.errs) == 0 {
+		return err
+	}
+	errs := t.errs
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return &ParseErrors{Errors: errs}
+}
+
+// declare records the prefixes declared by the document
+func (t *parseTracker) declare(prefixes map[string]string) {
+	for prefix, ns := range prefixes {
+		t.stats.Prefixes[prefix] = ns
+	}
+}
+
+// warn records a warning about the document
+func (t *parseTracker) warn(format string, args ...any) {
+	t.stats.Warnings = append(t.stats.Warnings, fmt.Sprintf(format, args...))
+}
+
+// done completes the stats of the document named uri, logging the
+// duplicates found
+func (t *parseTracker) done(uri string) *ParseStats {
+	if t.config.Duplicates == DuplicatesCount && t.stats.Duplicates > 0 {
+		t.warn("skipped %d duplicate statements", t.stats.Duplicates)
+		t.config.log(slog.LevelWarn, "skipped duplicate statements", "uri", uri, "count", t.stats.Duplicates)
+	}
+	t.stats.Elapsed = time.Since(t.start)
+	return &t.stats
+}
+
+// declaredPrefixes returns the prefixes declared by a Turtle or TriG
+// document parsed by another parser, skipping the rest of the document
+func declaredPrefixes(src string, base string) map[string]string {
+	p, err := newSyntaxParser(src, base)
+	for err == nil && p.tok.kind != tokEOF {
+		directive := p.tok.kind == tokDirective
+		switch {
+		case directive && p.tok.value == "prefix", p.tok.isKeyword("PREFIX"):
+			if err = p.advance(); err == nil {
+				err = p.prefixDecl()
+			}
+		case directive && p.tok.value == "base", p.tok.isKeyword("BASE"):
+			if err = p.advance(); err == nil {
+				err = p.baseDecl()
+			}
+		default:
+			err = p.advance()
+		}
+	}
+	return p.prefixes
+}
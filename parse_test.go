@@ -0,0 +1,220 @@
+package rdf2go
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const duplicatedNTriples = `<http://example.org/a> <http://example.org/p> "x" .
+<http://example.org/a> <http://example.org/p> "y" .
+<http://example.org/a> <http://example.org/p> "x" .
+<http://example.org/a> <http://example.org/p> "x" .
+`
+
+func TestParseDuplicates(t *testing.T) {
+	g := NewGraph(testUri)
+	stats, err := g.ParseWithStats(strings.NewReader(duplicatedNTriples), "application/n-triples")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Duplicates)
+	assert.Equal(t, 4, g.Len())
+
+	g = NewGraphWithOptions(testUri, WithDuplicates(DuplicatesSkip))
+	stats, err = g.ParseWithStats(strings.NewReader(duplicatedNTriples), "application/n-triples")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Duplicates)
+	assert.Equal(t, 2, g.Len())
+
+	// duplicates are looked for within a single document
+	stats, err = g.ParseWithStats(strings.NewReader(duplicatedNTriples), "application/n-triples")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Duplicates)
+
+	logs := new(bytes.Buffer)
+	g = NewGraphWithOptions(testUri, WithDuplicates(DuplicatesCount), WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	turtle := `@prefix ex: <http://example.org/> .
+ex:a ex:p "x" ; ex:p "x" .`
+	stats, err = g.ParseWithStats(strings.NewReader(turtle), "text/turtle")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Duplicates)
+	assert.Equal(t, 1, g.Len())
+	assert.Contains(t, logs.String(), "count=1")
+
+	g = NewGraphWithOptions(testUri, WithDuplicates(DuplicatesError))
+	err = g.Parse(strings.NewReader(duplicatedNTriples), "application/n-triples")
+	var dup *DuplicateError
+	if assert.True(t, errors.As(err, &dup)) {
+		assert.Equal(t, `<http://example.org/a> <http://example.org/p> "x" .`, dup.Statement.String())
+	}
+}
+
+func TestDatasetParseDuplicates(t *testing.T) {
+	trig := `@prefix ex: <http://example.org/> .
+ex:a ex:p "x" .
+ex:g { ex:a ex:p "x" . ex:a ex:p "x" . }
+ex:g { ex:a ex:p "x" . }`
+	d := NewDatasetWithOptions(testDatasetUri, WithDuplicates(DuplicatesSkip))
+	stats, err := d.ParseWithStats(strings.NewReader(trig), "application/trig")
+	assert.NoError(t, err)
+	// the statement of the default graph is not a duplicate of those of ex:g
+	assert.Equal(t, 2, stats.Duplicates)
+	assert.Equal(t, 2, d.Len())
+
+	d = NewDatasetWithOptions(testDatasetUri, WithDuplicates(DuplicatesError))
+	err = d.Parse(strings.NewReader(trig), "application/trig")
+	var dup *DuplicateError
+	if assert.True(t, errors.As(err, &dup)) {
+		assert.Equal(t, NewResource("http://example.org/g"), dup.Statement.Graph)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	trig := `@prefix ex: <http://example.org/> .
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+ex:a foaf:knows _:b1, _:b2 .
+_:b1 foaf:knows _:b2 .
+ex:g { ex:a ex:p "x" . }`
+	g := NewGraph(testUri)
+	stats, err := g.ParseWithStats(strings.NewReader(trig), "application/trig")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Added)
+	assert.Equal(t, 2, stats.BlankNodes)
+	assert.Equal(t, map[string]string{"ex": "http://example.org/", "foaf": "http://xmlns.com/foaf/0.1/"}, stats.Prefixes)
+	assert.Equal(t, []string{"dropped 1 statements of named graphs"}, stats.Warnings)
+	assert.True(t, stats.Elapsed > 0)
+
+	// the prefixes of Turtle parsed by gon3 are found as well
+	g = NewGraph(testUri)
+	stats, err = g.ParseWithStats(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:p "x" .`), "text/turtle")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Added)
+	assert.Equal(t, map[string]string{"ex": "http://example.org/"}, stats.Prefixes)
+
+	d := NewDataset(testDatasetUri)
+	stats, err = d.ParseWithStats(strings.NewReader(trig), "application/trig")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stats.Added)
+	assert.Empty(t, stats.Warnings)
+
+	d = NewDatasetWithOptions(testDatasetUri, WithDuplicates(DuplicatesCount))
+	stats, err = d.ParseWithStats(strings.NewReader(duplicatedNTriples), "application/n-quads")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Added)
+	assert.Equal(t, []string{"skipped 2 duplicate statements"}, stats.Warnings)
+}
+
+func TestParseLenient(t *testing.T) {
+	nt := `<http://example.org/a> <http://example.org/p> "x" .
+<http://example.org/a> "p" "y" .
+<http://example.org/a> <http://example.org/p> "z" .
+<http://example.org/a> <http://example.org/p> .
+`
+	g := NewGraph(testUri)
+	err := g.Parse(strings.NewReader(nt), "application/n-triples")
+	assert.Error(t, err)
+	assert.Equal(t, 1, g.Len())
+
+	g = NewGraphWithOptions(testUri, WithLenient(true))
+	stats, err := g.ParseWithStats(strings.NewReader(nt), "application/n-triples")
+	var errs *ParseErrors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs.Errors, 2)
+		assert.Contains(t, errs.Errors[0].Error(), "line 2")
+		assert.Contains(t, errs.Errors[1].Error(), "line 4")
+		assert.Contains(t, err.Error(), "and 1 more errors")
+	}
+	assert.Equal(t, 2, stats.Added)
+	assert.Equal(t, 2, stats.Skipped)
+	assert.Equal(t, 2, g.Len())
+
+	// no triple of a malformed Turtle statement is added
+	turtle := `@prefix ex: <http://example.org/> .
+ex:a ex:p "x" .
+ex:b ex:p "y" ; ex:q [ ex:r undefined:name ] .
+ex:c ex:p <not an IRI> .
+ex:d ex:p "z" .`
+	g = NewGraphWithOptions(testUri, WithLenient(true))
+	err = g.Parse(strings.NewReader(turtle), "text/turtle")
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs.Errors, 2)
+	}
+	assert.Equal(t, 2, g.Len())
+	assert.NotNil(t, g.One(NewResource("http://example.org/a"), nil, nil))
+	assert.Nil(t, g.One(NewResource("http://example.org/b"), nil, nil))
+	assert.NotNil(t, g.One(NewResource("http://example.org/d"), nil, nil))
+
+	// well-formed documents parse without errors
+	g = NewGraphWithOptions(testUri, WithLenient(true))
+	assert.NoError(t, g.Parse(strings.NewReader(`<http://example.org/a> <http://example.org/p> "x" .`), "text/turtle"))
+	assert.Equal(t, 1, g.Len())
+}
+
+func TestDatasetParseLenient(t *testing.T) {
+	trig := `@prefix ex: <http://example.org/> .
+ex:g {
+	ex:a ex:p "x" .
+	ex:b ex:p ex:p ex:p .
+	ex:c ex:p "y"
+}
+ex:h { ex:d ex:p ( "z" }
+ex:e ex:p "w" .`
+	d := NewDataset(testDatasetUri)
+	assert.Error(t, d.Parse(strings.NewReader(trig), "application/trig"))
+
+	d = NewDatasetWithOptions(testDatasetUri, WithLenient(true))
+	stats, err := d.ParseWithStats(strings.NewReader(trig), "application/trig")
+	var errs *ParseErrors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs.Errors, 2)
+	}
+	assert.Equal(t, 2, stats.Skipped)
+	assert.Equal(t, 3, d.Len())
+	assert.Equal(t, 2, d.GetGraph(NewResource("http://example.org/g")).Len())
+
+	// the duplicates reported in lenient mode are found among the errors
+	d = NewDatasetWithOptions(testDatasetUri, WithLenient(true), WithDuplicates(DuplicatesError))
+	err = d.Parse(strings.NewReader(`<http://example.org/a> <http://example.org/p> "x" .
+bad .
+<http://example.org/a> <http://example.org/p> "x" .
+`), "application/n-quads")
+	var dup *DuplicateError
+	assert.True(t, errors.As(err, &dup))
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs.Errors, 2)
+	}
+}
+
+func TestParseContentTypeParameters(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`<http://example.org/a> <http://example.org/p> "é" .`), "Text/Turtle; charset=UTF-8"))
+	assert.Equal(t, 1, g.Len())
+
+	// ISO-8859-1 and UTF-16 are converted to UTF-8
+	g = NewGraph(testUri)
+	latin1 := "<http://example.org/a> <http://example.org/p> \"caf\xe9\" ."
+	assert.NoError(t, g.Parse(strings.NewReader(latin1), "application/n-triples; charset=ISO-8859-1"))
+	assert.Equal(t, NewLiteral("café"), g.One(nil, nil, nil).Object)
+
+	utf16be := []byte{0xFE, 0xFF}
+	for _, r := range `<http://example.org/a> <http://example.org/p> "ü" .` {
+		utf16be = append(utf16be, byte(r>>8), byte(r))
+	}
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(bytes.NewReader(utf16be), "application/n-quads;charset=utf-16"))
+	assert.Equal(t, NewLiteral("ü"), d.One(nil, nil, nil, nil).Object)
+
+	err := g.Parse(strings.NewReader(latin1), "application/n-triples; charset=koi8-r")
+	assert.ErrorContains(t, err, "unsupported charset")
+
+	var quads []*Quad
+	assert.NoError(t, ParseStream(strings.NewReader(latin1), "application/n-triples; charset=latin1", func(q *Quad) error {
+		quads = append(quads, q)
+		return nil
+	}))
+	assert.Len(t, quads, 1)
+}
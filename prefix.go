@@ -0,0 +1,130 @@
+package rdf2go
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	turtlePrefixName = regexp.MustCompile(`^([A-Za-z]([A-Za-z0-9_.-]*[A-Za-z0-9_-])?)?$`)
+	turtleLocalName  = regexp.MustCompile(`^([A-Za-z0-9_]([A-Za-z0-9_.-]*[A-Za-z0-9_-])?)?$`)
+)
+
+// Bind associates a prefix with a namespace IRI. Turtle and TriG output
+// declares the bound prefixes, and writes the IRIs of their namespaces as
+// prefixed names. Binding an empty namespace removes the prefix.
+func (g *Graph) Bind(prefix string, iri string) error {
+	prefixes, err := bindPrefix(g.prefixes, prefix, iri)
+	if err != nil {
+		return err
+	}
+	g.prefixes = prefixes
+	return nil
+}
+
+// Prefixes returns a copy of the prefixes bound with Bind
+func (g *Graph) Prefixes() map[string]string {
+	return maps.Clone(g.prefixes)
+}
+
+// Bind associates a prefix with a namespace IRI, see Graph.Bind
+func (d *Dataset) Bind(prefix string, iri string) error {
+	prefixes, err := bindPrefix(d.prefixes, prefix, iri)
+	if err != nil {
+		return err
+	}
+	d.prefixes = prefixes
+	return nil
+}
+
+// Prefixes returns a copy of the prefixes bound with Bind
+func (d *Dataset) Prefixes() map[string]string {
+	return maps.Clone(d.prefixes)
+}
+
+func bindPrefix(prefixes map[string]string, prefix string, iri string) (map[string]string, error) {
+	if !turtlePrefixName.MatchString(prefix) {
+		return nil, fmt.Errorf("invalid prefix %q", prefix)
+	}
+	if len(iri) == 0 {
+		delete(prefixes, prefix)
+		return prefixes, nil
+	}
+	if prefixes == nil {
+		prefixes = make(map[string]string)
+	}
+	prefixes[prefix] = iri
+	return prefixes, nil
+}
+
+// writeTurtlePrefixes writes the @base declaration of a base IRI, unless
+// empty, and the @prefix declarations of the prefixes, sorted, followed by
+// an empty line
+func writeTurtlePrefixes(b *strings.Builder, base string, prefixes map[string]string) {
+	if len(base) > 0 {
+		b.WriteString("@base <" + base + "> .\n")
+	}
+	if len(prefixes) == 0 {
+		if len(base) > 0 {
+			b.WriteByte('\n')
+		}
+		return
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(prefixes)) {
+		b.WriteString("@prefix " + prefix + ": <" + prefixes[prefix] + "> .\n")
+	}
+	b.WriteByte('\n')
+}
+
+// prefixedName returns the prefixed name of an IRI, using the longest
+// namespace it belongs to
+func prefixedName(prefixes map[string]string, iri string) (string, bool) {
+	var best, namespace string
+	for prefix, ns := range prefixes {
+		if !strings.HasPrefix(iri, ns) || len(ns) < len(namespace) || !turtleLocalName.MatchString(iri[len(ns):]) {
+			continue
+		}
+		// the smallest prefix wins among those of a namespace
+		if len(ns) > len(namespace) || prefix < best {
+			best, namespace = prefix, ns
+		}
+	}
+	if len(namespace) == 0 {
+		return "", false
+	}
+	return best + ":" + iri[len(namespace):], true
+}
+
+// turtleEncoder returns the function writing terms in Turtle syntax, the IRIs
+// being abbreviated with the prefixes, or else written relative to a base
+// IRI unless empty
+func turtleEncoder(base string, prefixes map[string]string) func(Term) string {
+	if len(prefixes) == 0 && len(base) == 0 {
+		return encodeTerm
+	}
+	var encode func(Term) string
+	encode = func(t Term) string {
+		switch t := t.(type) {
+		case *Resource:
+			if name, ok := prefixedName(prefixes, t.URI); ok {
+				return name
+			}
+			if len(base) > 0 {
+				return encodeTerm(NewResource(relativeIRI(base, t.URI)))
+			}
+		case *Literal:
+			if t.Datatype != nil {
+				plain := *t
+				plain.Datatype = nil
+				return plain.String() + "^^" + encode(t.Datatype)
+			}
+		case *QuotedTriple:
+			return "<< " + encode(t.Subject) + " " + encode(t.Predicate) + " " + encode(t.Object) + " >>"
+		}
+		return encodeTerm(t)
+	}
+	return encode
+}
@@ -0,0 +1,55 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphBind(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Bind("ex", "http://example.org/"))
+	assert.NoError(t, g.Bind("people", "http://example.org/people/"))
+	assert.NoError(t, g.Bind("xsd", xsdNamespace))
+	assert.Error(t, g.Bind("1x", "http://example.org/x/"))
+	assert.Equal(t, map[string]string{"ex": "http://example.org/", "people": "http://example.org/people/", "xsd": xsdNamespace}, g.Prefixes())
+
+	alice := NewResource("http://example.org/people/alice")
+	g.AddTriple(alice, NewResource("http://example.org/age"), NewLiteralWithDatatype("42", NewResource(xsdNamespace+"integer")))
+	g.AddTriple(alice, NewResource("http://example.org/page"), NewResource("http://example.org/a/b"))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Equal(t, `@prefix ex: <http://example.org/> .
+@prefix people: <http://example.org/people/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+people:alice
+  ex:age "42"^^xsd:integer ;
+  ex:page <http://example.org/a/b> .`, buf.String())
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+	assert.Equal(t, 2, g2.Len())
+	assert.NotNil(t, g2.One(alice, NewResource("http://example.org/age"), NewLiteralWithDatatype("42", NewResource(xsdNamespace+"integer"))))
+
+	assert.NoError(t, g.Bind("people", ""))
+	buf.Reset()
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.NotContains(t, buf.String(), "people:")
+	assert.Contains(t, buf.String(), "<http://example.org/people/alice>\n")
+}
+
+func TestDatasetBind(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Bind("", "http://example.org/"))
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewResource("http://other.org/c"), NewResource("http://example.org/g"))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	assert.Equal(t, "@prefix : <http://example.org/> .\n\n:g {\n  :a\n    :b <http://other.org/c> .\n}\n", buf.String())
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(buf.String()), "application/trig"))
+	assert.NotNil(t, d2.One(NewResource("http://example.org/a"), nil, nil, NewResource("http://example.org/g")))
+}
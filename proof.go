@@ -0,0 +1,264 @@
+package rdf2go
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Data Integrity proofs (https://www.w3.org/TR/vc-data-integrity/)
+const (
+	// DataIntegrityProof is the type of the proofs made by Sign
+	DataIntegrityProof = "DataIntegrityProof"
+	// CryptosuiteEdDSARDFC2022 is the cryptosuite of the proofs made by
+	// Sign, Ed25519 signatures of the RDFC-1.0 canonical form of datasets
+	CryptosuiteEdDSARDFC2022 = "eddsa-rdfc-2022"
+)
+
+// ErrInvalidProof is returned by VerifyProof for proofs that do not match
+// the dataset or the key
+var ErrInvalidProof = errors.New("invalid proof")
+
+// Proof is a Data Integrity proof of a dataset. Its verification method and
+// proof purpose are IRIs, or terms of the security vocabulary, such as
+// assertionMethod, and its proof value is a multibase-encoded signature.
+type Proof struct {
+	Type               string
+	Cryptosuite        string
+	Created            time.Time
+	VerificationMethod string
+	ProofPurpose       string
+	ProofValue         string
+}
+
+// securityIRI returns the IRI of a term of the security vocabulary, unless
+// it already is an IRI
+func securityIRI(value string) string {
+	if strings.Contains(value, ":") {
+		return value
+	}
+	return Security.IRI() + value
+}
+
+// triples returns the statements describing the proof as node, without its
+// proof value unless full is set
+func (p *Proof) triples(node Term, full bool) []*Triple {
+	triples := []*Triple{
+		NewTriple(node, RDF.Get("type"), NewResource(securityIRI(p.Type))),
+		NewTriple(node, Security.Get("cryptosuite"), NewLiteralWithDatatype(p.Cryptosuite, Security.Get("cryptosuiteString"))),
+		NewTriple(node, DCTerms.Get("created"), NewLiteralWithDatatype(p.Created.Format(time.RFC3339Nano), XSD.Get("dateTime"))),
+		NewTriple(node, Security.Get("verificationMethod"), NewResource(p.VerificationMethod)),
+		NewTriple(node, Security.Get("proofPurpose"), NewResource(securityIRI(p.ProofPurpose))),
+	}
+	if full {
+		triples = append(triples, NewTriple(node, Security.Get("proofValue"), NewLiteralWithDatatype(p.ProofValue, Security.Get("multibase"))))
+	}
+	return triples
+}
+
+// hashData returns the data signed by the proof of a dataset: the hashes of
+// the canonical form of the proof options, and of the dataset
+func (p *Proof) hashData(d *Dataset) ([]byte, error) {
+	if p.Type != DataIntegrityProof || p.Cryptosuite != CryptosuiteEdDSARDFC2022 {
+		return nil, fmt.Errorf("unsupported proof %s with cryptosuite %s", p.Type, p.Cryptosuite)
+	}
+	if p.VerificationMethod == "" {
+		return nil, errors.New("the proof has no verification method")
+	}
+	config := NewDataset(d.uri)
+	for _, t := range p.triples(NewBlankNode("proof"), false) {
+		config.AddTriple(t.Subject, t.Predicate, t.Object)
+	}
+	canonicalConfig, err := config.CanonicalNQuads()
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := d.CanonicalNQuads()
+	if err != nil {
+		return nil, err
+	}
+	configHash := sha256.Sum256([]byte(canonicalConfig))
+	hash := sha256.Sum256([]byte(canonical))
+	return append(configHash[:], hash[:]...), nil
+}
+
+// Sign returns a Data Integrity proof of the dataset following the
+// eddsa-rdfc-2022 cryptosuite: the RDFC-1.0 canonical forms of the dataset
+// and of the proof options are hashed and signed with key. The options
+// must give the verification method of the key; the type, cryptosuite,
+// creation time and proof purpose default to DataIntegrityProof,
+// eddsa-rdfc-2022, the current time and assertionMethod.
+func (d *Dataset) Sign(key ed25519.PrivateKey, options Proof) (*Proof, error) {
+	proof := options
+	if proof.Type == "" {
+		proof.Type = DataIntegrityProof
+	}
+	if proof.Cryptosuite == "" {
+		proof.Cryptosuite = CryptosuiteEdDSARDFC2022
+	}
+	if proof.Created.IsZero() {
+		proof.Created = time.Now().UTC().Truncate(time.Second)
+	}
+	if proof.ProofPurpose == "" {
+		proof.ProofPurpose = "assertionMethod"
+	}
+	data, err := proof.hashData(d)
+	if err != nil {
+		return nil, err
+	}
+	proof.ProofValue = "z" + encodeBase58(ed25519.Sign(key, data))
+	return &proof, nil
+}
+
+// VerifyProof checks a proof of the dataset made by Sign, or by another
+// implementation of the eddsa-rdfc-2022 cryptosuite, with the public key of
+// its verification method. It returns ErrInvalidProof for proofs that do
+// not match.
+func (d *Dataset) VerifyProof(proof *Proof, key ed25519.PublicKey) error {
+	data, err := proof.hashData(d)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(proof.ProofValue, "z") {
+		return fmt.Errorf("%w: the proof value is not base58-btc multibase", ErrInvalidProof)
+	}
+	signature, err := decodeBase58(proof.ProofValue[1:])
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, data, signature) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// AttachProof adds a proof to the dataset, as the proofs of verifiable
+// credentials are: subject is linked by sec:proof to a new named graph, a
+// blank node, holding the description of the proof. It returns the name of
+// the graph.
+func (d *Dataset) AttachProof(subject Term, proof *Proof) Term {
+	graph := NewAnonNode()
+	d.AddQuad(subject, Security.Get("proof"), graph, nil)
+	for _, t := range proof.triples(NewAnonNode(), true) {
+		d.AddQuad(t.Subject, t.Predicate, t.Object, graph)
+	}
+	return graph
+}
+
+// ExtractProofs returns the proofs attached to the dataset, as by
+// AttachProof, along with a copy of the dataset without them, which the
+// proofs are verified against
+func (d *Dataset) ExtractProofs() (*Dataset, []*Proof, error) {
+	graphs := make(map[string]bool)
+	var proofs []*Proof
+	for _, link := range d.All(nil, Security.Get("proof"), nil, AnyGraph) {
+		graph, ok := link.Object.(*BlankNode)
+		if !ok || graphs[graph.String()] {
+			continue
+		}
+		graphs[graph.String()] = true
+		for _, node := range d.All(nil, RDF.Get("type"), nil, graph) {
+			proof, err := readProof(d, node.Subject, graph)
+			if err != nil {
+				return nil, nil, err
+			}
+			proofs = append(proofs, proof)
+		}
+	}
+	unsecured := d.empty()
+	for quad := range d.Quads() {
+		if quad.Predicate.Equal(Security.Get("proof")) && graphs[quad.Object.String()] {
+			continue
+		}
+		if quad.Graph != nil && graphs[quad.Graph.String()] {
+			continue
+		}
+		unsecured.Add(quad)
+	}
+	return unsecured, proofs, nil
+}
+
+// readProof reads the description of a proof in a graph
+func readProof(d *Dataset, node Term, graph Term) (*Proof, error) {
+	value := func(property string) string {
+		if q := d.One(node, Security.Get(property), nil, graph); q != nil {
+			return strings.TrimPrefix(q.Object.RawValue(), Security.IRI())
+		}
+		return ""
+	}
+	proof := &Proof{
+		Cryptosuite:        value("cryptosuite"),
+		VerificationMethod: value("verificationMethod"),
+		ProofPurpose:       value("proofPurpose"),
+		ProofValue:         value("proofValue"),
+	}
+	if q := d.One(node, RDF.Get("type"), nil, graph); q != nil {
+		proof.Type = strings.TrimPrefix(q.Object.RawValue(), Security.IRI())
+	}
+	if q := d.One(node, DCTerms.Get("created"), nil, graph); q != nil {
+		created, err := time.Parse(time.RFC3339Nano, q.Object.RawValue())
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation time of proof: %s", err)
+		}
+		proof.Created = created
+	}
+	return proof, nil
+}
+
+// Sign returns a Data Integrity proof of the graph, see Dataset.Sign
+func (g *Graph) Sign(key ed25519.PrivateKey, options Proof) (*Proof, error) {
+	return defaultGraphDataset(g).Sign(key, options)
+}
+
+// VerifyProof checks a proof of the graph, see Dataset.VerifyProof
+func (g *Graph) VerifyProof(proof *Proof, key ed25519.PublicKey) error {
+	return defaultGraphDataset(g).VerifyProof(proof, key)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// encodeBase58 encodes bytes with the Bitcoin base58 alphabet, used by the
+// base58-btc multibase encoding
+func encodeBase58(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// decodeBase58 decodes a string encoded by encodeBase58
+func decodeBase58(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
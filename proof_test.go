@@ -0,0 +1,117 @@
+package rdf2go
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the key pair of the test vectors of the EdDSA cryptosuites specification
+const (
+	testPublicKeyMultibase = "z6MkrJVnaZkeFzdQyMZu1cgjg7k1pZZ6pvBQ7XJPt4swbTQ2"
+	testSecretKeyMultibase = "z3u2en7t5LR2WtQH5PfFqMqwVHBeXouLzo6haApm8XHqvjxq"
+)
+
+func testProofKey(t *testing.T) ed25519.PrivateKey {
+	secret, err := decodeBase58(testSecretKeyMultibase[1:])
+	assert.NoError(t, err)
+	public, err := decodeBase58(testPublicKeyMultibase[1:])
+	assert.NoError(t, err)
+	// the keys are prefixed by the multicodecs of Ed25519 keys
+	assert.Equal(t, []byte{0x80, 0x26}, secret[:2])
+	assert.Equal(t, []byte{0xed, 0x01}, public[:2])
+	key := ed25519.NewKeyFromSeed(secret[2:])
+	assert.Equal(t, ed25519.PublicKey(public[2:]), key.Public())
+	return key
+}
+
+func TestBase58(t *testing.T) {
+	for _, b := range [][]byte{{}, {0}, {0, 0, 1}, []byte("hello world"), {0xff, 0xfe}} {
+		s := encodeBase58(b)
+		decoded, err := decodeBase58(s)
+		assert.NoError(t, err)
+		assert.Equal(t, b, decoded, s)
+	}
+	assert.Equal(t, "StV1DL6CwTryKyV", encodeBase58([]byte("hello world")))
+	_, err := decodeBase58("0OIl")
+	assert.Error(t, err)
+}
+
+func TestDatasetSignProof(t *testing.T) {
+	key := testProofKey(t)
+	method := "did:key:" + testPublicKeyMultibase + "#" + testPublicKeyMultibase
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(`
+<urn:uuid:58172aac> <http://schema.org/name> "Alice" .
+<urn:uuid:58172aac> <http://schema.org/knows> _:b0 .
+_:b0 <http://schema.org/name> "Bob" .
+`), "application/n-quads"))
+
+	created := time.Date(2023, 2, 24, 23, 36, 38, 0, time.UTC)
+	proof, err := d.Sign(key, Proof{VerificationMethod: method, Created: created})
+	assert.NoError(t, err)
+	assert.Equal(t, DataIntegrityProof, proof.Type)
+	assert.Equal(t, CryptosuiteEdDSARDFC2022, proof.Cryptosuite)
+	assert.Equal(t, "assertionMethod", proof.ProofPurpose)
+	assert.True(t, strings.HasPrefix(proof.ProofValue, "z"))
+	public := key.Public().(ed25519.PublicKey)
+	assert.NoError(t, d.VerifyProof(proof, public))
+
+	// the proof does not depend on blank node labels and statement order
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(`
+_:x <http://schema.org/name> "Bob" .
+<urn:uuid:58172aac> <http://schema.org/knows> _:x .
+<urn:uuid:58172aac> <http://schema.org/name> "Alice" .
+`), "application/n-quads"))
+	assert.NoError(t, d2.VerifyProof(proof, public))
+
+	d2.AddTriple(NewResource("urn:uuid:58172aac"), NewResource("http://schema.org/age"), NewLiteral("42"))
+	assert.ErrorIs(t, d2.VerifyProof(proof, public), ErrInvalidProof)
+	tampered := *proof
+	tampered.Created = created.Add(time.Second)
+	assert.ErrorIs(t, d.VerifyProof(&tampered, public), ErrInvalidProof)
+	other, _, _ := ed25519.GenerateKey(nil)
+	assert.ErrorIs(t, d.VerifyProof(proof, other), ErrInvalidProof)
+	tampered = *proof
+	tampered.Cryptosuite = "ecdsa-rdfc-2019"
+	assert.Error(t, d.VerifyProof(&tampered, public))
+	_, err = d.Sign(key, Proof{})
+	assert.Error(t, err)
+}
+
+func TestDatasetAttachProof(t *testing.T) {
+	key := testProofKey(t)
+	subject := NewResource("urn:uuid:58172aac")
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(subject, NewResource("http://schema.org/name"), NewLiteral("Alice"))
+	proof, err := d.Sign(key, Proof{VerificationMethod: "did:example:alice#key-1", Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))})
+	assert.NoError(t, err)
+	graph := d.AttachProof(subject, proof)
+	assert.Equal(t, 8, d.Len())
+	assert.NotNil(t, d.One(subject, Security.Get("proof"), graph, nil))
+
+	unsecured, proofs, err := d.ExtractProofs()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, unsecured.Len())
+	if assert.Len(t, proofs, 1) {
+		assert.Equal(t, proof.ProofValue, proofs[0].ProofValue)
+		assert.Equal(t, "did:example:alice#key-1", proofs[0].VerificationMethod)
+		assert.Equal(t, "assertionMethod", proofs[0].ProofPurpose)
+		assert.NoError(t, unsecured.VerifyProof(proofs[0], key.Public().(ed25519.PublicKey)))
+	}
+}
+
+func TestGraphSignProof(t *testing.T) {
+	key := testProofKey(t)
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"))
+	proof, err := g.Sign(key, Proof{VerificationMethod: "did:example:alice#key-1"})
+	assert.NoError(t, err)
+	assert.NoError(t, g.VerifyProof(proof, key.Public().(ed25519.PublicKey)))
+	g.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("y"))
+	assert.ErrorIs(t, g.VerifyProof(proof, key.Public().(ed25519.PublicKey)), ErrInvalidProof)
+}
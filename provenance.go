@@ -0,0 +1,56 @@
+package rdf2go
+
+import (
+	"slices"
+)
+
+// Source returns the URI of the document a quad of the dataset was read
+// from, as recorded by ParseSource and by LoadURI with the Provenance
+// option, or an empty string for the quads added otherwise
+func (d *Dataset) Source(q *Quad) string {
+	defer d.rlock()()
+	return d.sources[q]
+}
+
+// Sources returns the sorted URIs of the documents the quads of the dataset
+// were read from
+func (d *Dataset) Sources() []string {
+	defer d.rlock()()
+	seen := make(map[string]bool)
+	sources := []string{}
+	for _, source := range d.sources {
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+// QuadsFrom returns the quads of the dataset read from the document source,
+// in the iteration order of the dataset
+func (d *Dataset) QuadsFrom(source string) []*Quad {
+	var quads []*Quad
+	for quad := range d.Quads() {
+		if d.Source(quad) == source {
+			quads = append(quads, quad)
+		}
+	}
+	return quads
+}
+
+// RemoveSource removes the quads read from the document source, such as a
+// document to reload or an origin that is no longer trusted, and returns
+// them. When dryRun is true, the dataset is left untouched and the returned
+// change set lists the quads that would have been removed. Each document adds
+// quads of its own, so the statements also read from other documents are
+// kept, unless an IRI policy made the dataset skip their copies.
+func (d *Dataset) RemoveSource(source string, dryRun ...bool) *ChangeSet {
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	c.Removed = d.QuadsFrom(source)
+	if !c.DryRun {
+		d.Apply(c)
+	}
+	return c
+}
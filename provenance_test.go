@@ -0,0 +1,60 @@
+package rdf2go
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetProvenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/n-quads")
+		switch req.URL.Path {
+		case "/a":
+			w.Write([]byte("<http://example.org/s> <http://example.org/p> \"a\" .\n<http://example.org/s> <http://example.org/q> \"shared\" <http://example.org/g> .\n"))
+		case "/b":
+			w.Write([]byte("<http://example.org/s> <http://example.org/q> \"shared\" <http://example.org/g> .\n"))
+		}
+	}))
+	defer server.Close()
+
+	d := NewDatasetWithOptions("", WithProvenance(true))
+	assert.NoError(t, d.LoadURI(server.URL+"/a#it"))
+	assert.NoError(t, d.LoadURI(server.URL+"/b"))
+	d.AddTriple(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("local"))
+	assert.Equal(t, 4, d.Len())
+	assert.Equal(t, []string{server.URL + "/a", server.URL + "/b"}, d.Sources())
+	assert.Len(t, d.QuadsFrom(server.URL+"/a"), 2)
+	local := d.One(nil, nil, NewLiteral("local"), nil)
+	assert.Equal(t, "", d.Source(local))
+
+	// the quads of a selection keep their provenance
+	selected := d.Select(func(g Term) bool { return g != nil })
+	assert.Equal(t, []string{server.URL + "/a", server.URL + "/b"}, selected.Sources())
+
+	c := d.RemoveSource(server.URL+"/a", true)
+	assert.True(t, c.DryRun)
+	assert.Len(t, c.Removed, 2)
+	assert.Equal(t, 4, d.Len())
+
+	c = d.RemoveSource(server.URL + "/a")
+	assert.Len(t, c.Removed, 2)
+	assert.Equal(t, 2, d.Len())
+	assert.Equal(t, []string{server.URL + "/b"}, d.Sources())
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("shared"), NewResource("http://example.org/g")))
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("local"), nil))
+
+	// provenance is not recorded by default
+	d = NewDataset("")
+	assert.NoError(t, d.LoadURI(server.URL+"/a"))
+	assert.Empty(t, d.Sources())
+
+	// unless the document is parsed with its source
+	assert.NoError(t, d.ParseSource(strings.NewReader("<http://example.org/s> <http://example.org/p> \"c\" .\n"), "http://example.org/c", "text/turtle"))
+	assert.Equal(t, []string{"http://example.org/c"}, d.Sources())
+	assert.Len(t, d.RemoveSource("http://example.org/c").Removed, 1)
+	assert.Equal(t, 2, d.Len())
+}
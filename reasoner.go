@@ -0,0 +1,175 @@
+package rdf2go
+
+var (
+	rdfType              = NewResource(rdfNamespace + "type")
+	owlInverseOf         = NewResource(owlNamespace + "inverseOf")
+	owlSameAs            = NewResource(owlNamespace + "sameAs")
+	owlEquivalentClass   = NewResource(owlNamespace + "equivalentClass")
+	owlSymmetricProperty = NewResource(owlNamespace + "SymmetricProperty")
+	owlTransitive        = NewResource(owlNamespace + "TransitiveProperty")
+)
+
+// Reasoner materializes in a graph of a dataset the statements entailed by a
+// subset of the OWL 2 RL rules (https://www.w3.org/TR/owl2-profiles/#OWL_2_RL):
+//
+//   - prp-inv1 and prp-inv2, for owl:inverseOf
+//   - prp-symp, for owl:SymmetricProperty
+//   - prp-trp, for owl:TransitiveProperty
+//   - eq-sym, eq-trans, eq-rep-s, eq-rep-p and eq-rep-o, for owl:sameAs
+//   - cax-eqc1 and cax-eqc2, for owl:equivalentClass
+//
+// The derived statements are added to the graph, and kept up to date as the
+// graph changes: an added statement derives its consequences, and a removed
+// one retracts the statements that can no longer be derived without it.
+// Removing a statement that is still entailed by the others, asserted or
+// derived, derives it again. The statement that a resource is the same as
+// itself is not derived.
+//
+// Like subscriptions, a reasoner must be canceled when it is no longer
+// needed.
+type Reasoner struct {
+	materializer
+}
+
+// NewReasoner derives the statements entailed by a graph of a dataset, nil
+// being the default graph, and keeps deriving them as the graph changes
+func NewReasoner(d *Dataset, graph Term) *Reasoner {
+	r := &Reasoner{newMaterializer(d, graph, owlRules{d.iriPolicy})}
+	r.start()
+	return r
+}
+
+// owlRules implements the rules of Reasoner, comparing IRIs with policy
+type owlRules struct {
+	policy *IRIPolicy
+}
+
+// derivation finds a derivation of a statement from the statements of the
+// graph in a single step. Each rule has a premise sharing its subject with
+// the subject or object of the conclusion, or, for eq-rep-s, the premise
+// owl:sameAs statement having its subject as object.
+func (r owlRules) derivation(m *Quad, all matcher) (string, []*Quad, bool) {
+	candidates := all(m.Subject, nil, nil)
+	if resource(m.Object) {
+		candidates = append(candidates, all(m.Object, nil, nil)...)
+	}
+	candidates = append(candidates, all(nil, owlSameAs, m.Subject)...)
+	key := r.policy.key
+	target := key(m.Subject) + " " + key(m.Predicate) + " " + key(m.Object)
+	found := false
+	var rule string
+	var supports []*Quad
+	for _, c := range candidates {
+		r.consequences(c, all, func(name string, s Term, p Term, o Term, premises ...*Quad) {
+			if !found && key(s)+" "+key(p)+" "+key(o) == target {
+				found, rule, supports = true, name, premises
+			}
+		})
+		if found {
+			break
+		}
+	}
+	return rule, supports, found
+}
+
+func (r owlRules) consequences(f *Quad, all matcher, emit emitFunc) {
+	// emitChecked skips the conclusions that are not valid statements, such
+	// as those with a literal subject, and reflexive owl:sameAs statements
+	emitChecked := func(rule string, s Term, p Term, o Term, supports ...*Quad) {
+		if !validStatement(s, p, o) {
+			return
+		}
+		if p.Equal(owlSameAs) && r.policy.Equal(s, o) {
+			return
+		}
+		emit(rule, s, p, o, supports...)
+	}
+	s, p, o := f.Subject, f.Predicate, f.Object
+
+	// f as a data statement
+	for _, t := range all(p, rdfType, owlSymmetricProperty) {
+		emitChecked("prp-symp", o, p, s, t, f)
+	}
+	for _, t := range all(p, rdfType, owlTransitive) {
+		if resource(o) {
+			for _, next := range all(o, p, nil) {
+				emitChecked("prp-trp", s, p, next.Object, t, f, next)
+			}
+		}
+		for _, prev := range all(nil, p, s) {
+			emitChecked("prp-trp", prev.Subject, p, o, t, prev, f)
+		}
+	}
+	for _, inv := range all(p, owlInverseOf, nil) {
+		emitChecked("prp-inv1", o, inv.Object, s, inv, f)
+	}
+	for _, inv := range all(nil, owlInverseOf, p) {
+		emitChecked("prp-inv2", o, inv.Subject, s, inv, f)
+	}
+	if p.Equal(rdfType) && resource(o) {
+		for _, eq := range all(o, owlEquivalentClass, nil) {
+			emitChecked("cax-eqc1", s, rdfType, eq.Object, eq, f)
+		}
+		for _, eq := range all(nil, owlEquivalentClass, o) {
+			emitChecked("cax-eqc2", s, rdfType, eq.Subject, eq, f)
+		}
+	}
+	for _, same := range all(s, owlSameAs, nil) {
+		emitChecked("eq-rep-s", same.Object, p, o, same, f)
+	}
+	for _, same := range all(p, owlSameAs, nil) {
+		emitChecked("eq-rep-p", s, same.Object, o, same, f)
+	}
+	if resource(o) {
+		for _, same := range all(o, owlSameAs, nil) {
+			emitChecked("eq-rep-o", s, p, same.Object, same, f)
+		}
+	}
+
+	// f as a schema statement
+	switch {
+	case p.Equal(rdfType) && o.Equal(owlSymmetricProperty):
+		for _, q := range all(nil, s, nil) {
+			emitChecked("prp-symp", q.Object, s, q.Subject, f, q)
+		}
+	case p.Equal(rdfType) && o.Equal(owlTransitive):
+		for _, q := range all(nil, s, nil) {
+			if resource(q.Object) {
+				for _, next := range all(q.Object, s, nil) {
+					emitChecked("prp-trp", q.Subject, s, next.Object, f, q, next)
+				}
+			}
+		}
+	case p.Equal(owlInverseOf) && resource(o):
+		for _, q := range all(nil, s, nil) {
+			emitChecked("prp-inv1", q.Object, o, q.Subject, f, q)
+		}
+		for _, q := range all(nil, o, nil) {
+			emitChecked("prp-inv2", q.Object, s, q.Subject, f, q)
+		}
+	case p.Equal(owlEquivalentClass) && resource(o):
+		for _, q := range all(nil, rdfType, s) {
+			emitChecked("cax-eqc1", q.Subject, rdfType, o, f, q)
+		}
+		for _, q := range all(nil, rdfType, o) {
+			emitChecked("cax-eqc2", q.Subject, rdfType, s, f, q)
+		}
+	case p.Equal(owlSameAs) && resource(o):
+		emitChecked("eq-sym", o, owlSameAs, s, f)
+		for _, next := range all(o, owlSameAs, nil) {
+			emitChecked("eq-trans", s, owlSameAs, next.Object, f, next)
+		}
+		for _, prev := range all(nil, owlSameAs, s) {
+			emitChecked("eq-trans", prev.Subject, owlSameAs, o, prev, f)
+		}
+		for _, q := range all(s, nil, nil) {
+			emitChecked("eq-rep-s", o, q.Predicate, q.Object, f, q)
+		}
+		for _, q := range all(nil, s, nil) {
+			emitChecked("eq-rep-p", q.Subject, o, q.Object, f, q)
+		}
+		for _, q := range all(nil, nil, s) {
+			emitChecked("eq-rep-o", q.Subject, q.Predicate, o, f, q)
+		}
+	}
+}
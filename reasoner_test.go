@@ -0,0 +1,106 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReasoner(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(ex.Get("knows"), rdfType, owlSymmetricProperty)
+	d.AddTriple(ex.Get("ancestor"), rdfType, owlTransitive)
+	d.AddTriple(ex.Get("parent"), owlInverseOf, ex.Get("child"))
+	d.AddTriple(ex.Get("Person"), owlEquivalentClass, ex.Get("Human"))
+	d.AddTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))
+	d.AddTriple(ex.Get("alice"), ex.Get("ancestor"), ex.Get("bob"))
+	d.AddTriple(ex.Get("bob"), ex.Get("ancestor"), ex.Get("carol"))
+
+	r := NewReasoner(d, nil)
+	defer r.Cancel()
+	assert.NotNil(t, d.One(ex.Get("bob"), ex.Get("knows"), ex.Get("alice"), nil))
+	derived := d.One(ex.Get("alice"), ex.Get("ancestor"), ex.Get("carol"), nil)
+	assert.True(t, r.IsDerived(derived))
+	assert.Equal(t, "prp-trp", r.Explain(derived).Rule)
+	assert.Len(t, r.Derived(), 2)
+
+	// statements added later derive their consequences, also in chains
+	d.AddTriple(ex.Get("carol"), ex.Get("ancestor"), ex.Get("dave"))
+	assert.NotNil(t, d.One(ex.Get("alice"), ex.Get("ancestor"), ex.Get("dave"), nil))
+	d.AddTriple(ex.Get("dave"), ex.Get("parent"), ex.Get("erin"))
+	assert.NotNil(t, d.One(ex.Get("erin"), ex.Get("child"), ex.Get("dave"), nil))
+	d.AddTriple(ex.Get("erin"), rdfType, ex.Get("Human"))
+	assert.NotNil(t, d.One(ex.Get("erin"), rdfType, ex.Get("Person"), nil))
+
+	// removing a statement retracts what can no longer be derived
+	bobCarol := d.One(ex.Get("bob"), ex.Get("ancestor"), ex.Get("carol"), nil)
+	d.Remove(bobCarol)
+	assert.Nil(t, d.One(ex.Get("alice"), ex.Get("ancestor"), ex.Get("carol"), nil))
+	assert.Nil(t, d.One(ex.Get("alice"), ex.Get("ancestor"), ex.Get("dave"), nil))
+	assert.Nil(t, d.One(ex.Get("bob"), ex.Get("ancestor"), ex.Get("dave"), nil))
+	assert.NotNil(t, d.One(ex.Get("carol"), ex.Get("ancestor"), ex.Get("dave"), nil))
+
+	// and keeps what is still derived otherwise
+	d.AddTriple(ex.Get("bob"), ex.Get("ancestor"), ex.Get("dave"))
+	asserted := NewQuad(ex.Get("alice"), ex.Get("ancestor"), ex.Get("dave"), nil)
+	d.Add(asserted)
+	d.Remove(asserted)
+	aliceDave := d.One(ex.Get("alice"), ex.Get("ancestor"), ex.Get("dave"), nil)
+	if assert.NotNil(t, aliceDave) {
+		assert.True(t, r.IsDerived(aliceDave))
+	}
+
+	// schema statements apply to the existing data
+	d.Remove(d.One(ex.Get("knows"), rdfType, owlSymmetricProperty, nil))
+	assert.Nil(t, d.One(ex.Get("bob"), ex.Get("knows"), ex.Get("alice"), nil))
+	d.AddTriple(ex.Get("knows"), rdfType, owlSymmetricProperty)
+	assert.NotNil(t, d.One(ex.Get("bob"), ex.Get("knows"), ex.Get("alice"), nil))
+
+	// asserted statements are never removed by the reasoner
+	d.AddTriple(ex.Get("erin"), ex.Get("child"), ex.Get("dave"))
+	d.Remove(d.One(ex.Get("dave"), ex.Get("parent"), ex.Get("erin"), nil))
+	assert.NotNil(t, d.One(ex.Get("erin"), ex.Get("child"), ex.Get("dave"), nil))
+	assert.NotNil(t, d.One(ex.Get("dave"), ex.Get("parent"), ex.Get("erin"), nil))
+
+	// statements of other graphs are left alone
+	g := NewResource("http://example.org/g")
+	d.AddQuad(ex.Get("frank"), ex.Get("knows"), ex.Get("gina"), g)
+	assert.Nil(t, d.One(ex.Get("gina"), ex.Get("knows"), ex.Get("frank"), g))
+
+	r.Cancel()
+	d.AddTriple(ex.Get("frank"), ex.Get("knows"), ex.Get("gina"))
+	assert.Nil(t, d.One(ex.Get("gina"), ex.Get("knows"), ex.Get("frank"), nil))
+}
+
+func TestReasonerSameAs(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(ex.Get("alice"), ex.Get("name"), NewLiteral("Alice"))
+	d.AddTriple(ex.Get("bob"), ex.Get("likes"), ex.Get("alice"))
+	r := NewReasoner(d, nil)
+	defer r.Cancel()
+
+	d.AddTriple(ex.Get("alice"), owlSameAs, ex.Get("alice2"))
+	d.AddTriple(ex.Get("alice2"), owlSameAs, ex.Get("alice3"))
+	assert.NotNil(t, d.One(ex.Get("alice3"), owlSameAs, ex.Get("alice"), nil))
+	assert.NotNil(t, d.One(ex.Get("alice3"), ex.Get("name"), NewLiteral("Alice"), nil))
+	assert.NotNil(t, d.One(ex.Get("bob"), ex.Get("likes"), ex.Get("alice3"), nil))
+	// no resource is said to be the same as itself
+	assert.Nil(t, d.One(ex.Get("alice"), owlSameAs, ex.Get("alice"), nil))
+
+	e := r.Explain(d.One(ex.Get("alice3"), ex.Get("name"), NewLiteral("Alice"), nil))
+	if assert.NotNil(t, e) {
+		asserted := e.Asserted()
+		assert.Contains(t, asserted, d.One(ex.Get("alice"), ex.Get("name"), NewLiteral("Alice"), nil))
+		for _, q := range asserted {
+			assert.False(t, r.IsDerived(q))
+		}
+	}
+	assert.Nil(t, r.Explain(d.One(ex.Get("alice"), ex.Get("name"), nil, nil)))
+
+	d.Remove(d.One(ex.Get("alice2"), owlSameAs, ex.Get("alice3"), nil))
+	assert.Nil(t, d.One(ex.Get("alice3"), ex.Get("name"), nil, nil))
+	assert.NotNil(t, d.One(ex.Get("alice2"), ex.Get("name"), NewLiteral("Alice"), nil))
+	assert.Nil(t, d.One(ex.Get("bob"), ex.Get("likes"), ex.Get("alice3"), nil))
+}
@@ -0,0 +1,221 @@
+package rdf2go
+
+var (
+	rdfStatement = NewResource(rdfNamespace + "Statement")
+	rdfSubject   = NewResource(rdfNamespace + "subject")
+	rdfPredicate = NewResource(rdfNamespace + "predicate")
+	rdfObject    = NewResource(rdfNamespace + "object")
+)
+
+// Reify adds to the graph the reification of a triple, an rdf:Statement
+// with its rdf:subject, rdf:predicate and rdf:object, and returns its new
+// blank node. The triple itself is not asserted.
+func (g *Graph) Reify(t *Triple) Term {
+	node := NewAnonNode()
+	g.AddTriple(node, rdfType, rdfStatement)
+	g.AddTriple(node, rdfSubject, t.Subject)
+	g.AddTriple(node, rdfPredicate, t.Predicate)
+	g.AddTriple(node, rdfObject, t.Object)
+	return node
+}
+
+// Reified returns the triple described by a reification, or nil when the
+// node does not have exactly one rdf:subject, rdf:predicate and rdf:object
+func (g *Graph) Reified(node Term) *Triple {
+	var terms [3]Term
+	for i, p := range []Term{rdfSubject, rdfPredicate, rdfObject} {
+		objects := g.All(node, p, nil)
+		if len(objects) != 1 {
+			return nil
+		}
+		terms[i] = objects[0].Object
+	}
+	return NewTriple(terms[0], terms[1], terms[2])
+}
+
+// QuoteReifications converts the reifications of the graph into RDF-star
+// quoted triples: the statements about a reification become statements
+// about the quoted triple, and the reification statements are removed.
+// Only the reifications identified by blank nodes are converted, as the
+// IRIs of the others would be lost. When dryRun is true, the graph is left
+// untouched and the returned change set lists the changes that would have
+// been made.
+func (g *Graph) QuoteReifications(dryRun ...bool) *ChangeSet {
+	c := quoteReifications(graphQuads(g))
+	c.DryRun = len(dryRun) > 0 && dryRun[0]
+	if !c.DryRun {
+		g.Apply(c)
+	}
+	return c
+}
+
+// ReifyQuotedTriples converts the RDF-star quoted triples of the graph into
+// reifications, the reverse of QuoteReifications: each quoted triple is
+// replaced by a blank node, described as an rdf:Statement. When dryRun is
+// true, the graph is left untouched and the returned change set lists the
+// changes that would have been made.
+func (g *Graph) ReifyQuotedTriples(dryRun ...bool) *ChangeSet {
+	c := reifyQuotedTriples(graphQuads(g))
+	c.DryRun = len(dryRun) > 0 && dryRun[0]
+	if !c.DryRun {
+		g.Apply(c)
+	}
+	return c
+}
+
+// QuoteReifications converts the reifications of the dataset into RDF-star
+// quoted triples, in each graph, see Graph.QuoteReifications
+func (d *Dataset) QuoteReifications(dryRun ...bool) *ChangeSet {
+	c := quoteReifications(d.orderedQuads())
+	c.DryRun = len(dryRun) > 0 && dryRun[0]
+	if !c.DryRun {
+		d.Apply(c)
+	}
+	return c
+}
+
+// ReifyQuotedTriples converts the RDF-star quoted triples of the dataset
+// into reifications, in each graph, see Graph.ReifyQuotedTriples
+func (d *Dataset) ReifyQuotedTriples(dryRun ...bool) *ChangeSet {
+	c := reifyQuotedTriples(d.orderedQuads())
+	c.DryRun = len(dryRun) > 0 && dryRun[0]
+	if !c.DryRun {
+		d.Apply(c)
+	}
+	return c
+}
+
+// graphQuads returns the triples of a graph as quads of the default graph
+func graphQuads(g *Graph) []*Quad {
+	var quads []*Quad
+	for triple := range g.Triples() {
+		quads = append(quads, NewTripleQuad(triple))
+	}
+	sortQuads(quads)
+	return quads
+}
+
+// graphKey returns the key grouping the quads of a graph
+func graphKey(graph Term) string {
+	if graph == nil {
+		return ""
+	}
+	return graph.String()
+}
+
+// reification is a blank node described as a statement by a graph
+type reification struct {
+	terms  [3]Term
+	quads  []*Quad
+	valid  bool
+	quoted Term
+	busy   bool
+}
+
+func quoteReifications(quads []*Quad) *ChangeSet {
+	reifications := make(map[string]*reification)
+	key := func(graph Term, node Term) string {
+		return graphKey(graph) + " " + node.String()
+	}
+	positions := map[string]int{rdfSubject.RawValue(): 0, rdfPredicate.RawValue(): 1, rdfObject.RawValue(): 2}
+	for _, q := range quads {
+		if _, blank := q.Subject.(*BlankNode); !blank {
+			continue
+		}
+		pos, ok := positions[q.Predicate.RawValue()]
+		statement := q.Predicate.Equal(rdfType) && q.Object.Equal(rdfStatement)
+		if _, iri := q.Predicate.(*Resource); !iri || !ok && !statement {
+			continue
+		}
+		k := key(q.Graph, q.Subject)
+		r := reifications[k]
+		if r == nil {
+			r = &reification{valid: true}
+			reifications[k] = r
+		}
+		r.quads = append(r.quads, q)
+		if statement {
+			continue
+		}
+		if r.terms[pos] != nil {
+			r.valid = false
+		}
+		r.terms[pos] = q.Object
+	}
+
+	// quote returns the quoted triple replacing a node, possibly nesting
+	// those of other reifications, or the node itself
+	var quote func(graph Term, node Term) Term
+	quote = func(graph Term, node Term) Term {
+		r := reifications[key(graph, node)]
+		if r == nil || !r.valid || r.terms[0] == nil || r.terms[1] == nil || r.terms[2] == nil || r.busy {
+			return node
+		}
+		if r.quoted == nil {
+			r.busy = true
+			r.quoted = NewQuotedTriple(quote(graph, r.terms[0]), r.terms[1], quote(graph, r.terms[2]))
+			r.busy = false
+		}
+		return r.quoted
+	}
+
+	c := &ChangeSet{}
+	converted := make(map[*Quad]bool)
+	for _, q := range quads {
+		if _, blank := q.Subject.(*BlankNode); !blank {
+			continue
+		}
+		if quote(q.Graph, q.Subject) == q.Subject {
+			continue
+		}
+		for _, part := range reifications[key(q.Graph, q.Subject)].quads {
+			converted[part] = true
+		}
+	}
+	for _, q := range quads {
+		if converted[q] {
+			c.Removed = append(c.Removed, q)
+			continue
+		}
+		s, o := quote(q.Graph, q.Subject), quote(q.Graph, q.Object)
+		if s != q.Subject || o != q.Object {
+			c.Removed = append(c.Removed, q)
+			c.Added = append(c.Added, NewQuad(s, q.Predicate, o, q.Graph))
+		}
+	}
+	return c
+}
+
+func reifyQuotedTriples(quads []*Quad) *ChangeSet {
+	c := &ChangeSet{}
+	nodes := make(map[string]Term)
+	// node returns the blank node replacing a quoted triple of a graph,
+	// adding its reification the first time
+	var node func(graph Term, t Term) Term
+	node = func(graph Term, t Term) Term {
+		quoted, ok := t.(*QuotedTriple)
+		if !ok {
+			return t
+		}
+		k := graphKey(graph) + " " + quoted.String()
+		if n, seen := nodes[k]; seen {
+			return n
+		}
+		n := NewAnonNode()
+		nodes[k] = n
+		c.Added = append(c.Added,
+			NewQuad(n, rdfType, rdfStatement, graph),
+			NewQuad(n, rdfSubject, node(graph, quoted.Subject), graph),
+			NewQuad(n, rdfPredicate, quoted.Predicate, graph),
+			NewQuad(n, rdfObject, node(graph, quoted.Object), graph))
+		return n
+	}
+	for _, q := range quads {
+		s, o := node(q.Graph, q.Subject), node(q.Graph, q.Object)
+		if s != q.Subject || o != q.Object {
+			c.Removed = append(c.Removed, q)
+			c.Added = append(c.Added, NewQuad(s, q.Predicate, o, q.Graph))
+		}
+	}
+	return c
+}
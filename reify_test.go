@@ -0,0 +1,87 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReify(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	g := NewGraph(testUri)
+	triple := NewTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))
+	node := g.Reify(triple)
+	assert.Equal(t, 4, g.Len())
+	assert.NotNil(t, g.One(node, rdfType, rdfStatement))
+	assert.Nil(t, g.One(ex.Get("alice"), ex.Get("knows"), ex.Get("bob")))
+	assert.True(t, triple.Equal(g.Reified(node)))
+
+	assert.Nil(t, g.Reified(ex.Get("alice")))
+	g.AddTriple(node, rdfObject, ex.Get("carol"))
+	assert.Nil(t, g.Reified(node))
+}
+
+func TestQuoteReifications(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix ex: <http://example.org/> .
+
+_:r a rdf:Statement ; rdf:subject ex:alice ; rdf:predicate ex:knows ; rdf:object ex:bob ;
+	ex:source ex:survey .
+_:n rdf:subject _:r ; rdf:predicate ex:claimedBy ; rdf:object ex:carol .
+ex:dave ex:doubts _:n .
+ex:named rdf:subject ex:a ; rdf:predicate ex:b ; rdf:object ex:c .
+`), "text/turtle"))
+
+	changes := g.QuoteReifications(true)
+	assert.True(t, changes.DryRun)
+	assert.Equal(t, 12, g.Len())
+	g.QuoteReifications()
+	ex := NewNamespace("http://example.org/")
+	quoted := NewQuotedTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))
+	assert.NotNil(t, g.One(quoted, ex.Get("source"), ex.Get("survey")))
+	nested := NewQuotedTriple(quoted, ex.Get("claimedBy"), ex.Get("carol"))
+	assert.NotNil(t, g.One(ex.Get("dave"), ex.Get("doubts"), nested))
+	assert.Nil(t, g.One(nil, rdfSubject, ex.Get("alice")))
+	// reifications identified by IRIs are kept
+	assert.NotNil(t, g.One(ex.Get("named"), rdfSubject, ex.Get("a")))
+	assert.Equal(t, 5, g.Len())
+
+	// and back, each reification typed as an rdf:Statement
+	g.ReifyQuotedTriples()
+	assert.Equal(t, 13, g.Len())
+	doubt := g.One(ex.Get("dave"), ex.Get("doubts"), nil)
+	if assert.NotNil(t, doubt) {
+		claim := g.Reified(doubt.Object)
+		if assert.NotNil(t, claim) {
+			assert.Equal(t, ex.Get("claimedBy"), claim.Predicate)
+			assert.True(t, g.Reified(claim.Subject).Equal(NewTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))))
+			assert.NotNil(t, g.One(claim.Subject, ex.Get("source"), ex.Get("survey")))
+		}
+	}
+}
+
+func TestDatasetReifyQuotedTriples(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	d := NewDataset(testDatasetUri)
+	quoted := NewQuotedTriple(ex.Get("alice"), ex.Get("age"), NewLiteral("42"))
+	d.Add(NewQuad(quoted, ex.Get("source"), ex.Get("census"), ex.Get("g1")))
+	d.Add(NewQuad(ex.Get("bob"), ex.Get("doubts"), quoted, ex.Get("g1")))
+	d.Add(NewQuad(quoted, ex.Get("source"), ex.Get("survey"), ex.Get("g2")))
+
+	d.ReifyQuotedTriples()
+	assert.Equal(t, 11, d.Len())
+	for _, graph := range []Term{ex.Get("g1"), ex.Get("g2")} {
+		nodes := d.All(nil, rdfType, rdfStatement, graph)
+		if assert.Len(t, nodes, 1) {
+			assert.NotNil(t, d.One(nodes[0].Subject, rdfObject, NewLiteral("42"), graph))
+		}
+	}
+
+	d.QuoteReifications()
+	assert.Equal(t, 3, d.Len())
+	assert.NotNil(t, d.One(ex.Get("bob"), ex.Get("doubts"), quoted, ex.Get("g1")))
+	assert.NotNil(t, d.One(quoted, ex.Get("source"), ex.Get("survey"), ex.Get("g2")))
+}
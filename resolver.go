@@ -0,0 +1,49 @@
+package rdf2go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Resolver dereferences the IRIs of a URI scheme that is not fetched over
+// HTTP, such as did:, ipfs: or urn:, or of an intranet scheme. Resolvers are
+// registered per scheme with WithResolver, after which LoadURI reads the
+// documents they return for IRIs of that scheme.
+type Resolver interface {
+	// Resolve returns the RDF document describing iri, along with its
+	// content type
+	Resolve(ctx context.Context, iri string) (io.ReadCloser, string, error)
+}
+
+// ResolverFunc is a function used as a Resolver
+type ResolverFunc func(ctx context.Context, iri string) (io.ReadCloser, string, error)
+
+// Resolve implements Resolver
+func (f ResolverFunc) Resolve(ctx context.Context, iri string) (io.ReadCloser, string, error) {
+	return f(ctx, iri)
+}
+
+// resolver returns the resolver registered for the scheme of uri
+func (c *Config) resolver(uri string) (Resolver, bool) {
+	scheme, _, ok := strings.Cut(uri, ":")
+	if !ok || len(c.Resolvers) == 0 {
+		return nil, false
+	}
+	resolver, ok := c.Resolvers[strings.ToLower(scheme)]
+	return resolver, ok
+}
+
+// fetchResolved reads the RDF document returned by a resolver, see fetchRDF
+func (c *Config) fetchResolved(ctx context.Context, resolver Resolver, uri string, what string, parse func(io.Reader, string) error) (*LoadResult, error) {
+	r, contentType, err := resolver.Resolve(ctx, defrag(uri))
+	if err != nil {
+		return nil, fmt.Errorf("Could not resolve %s from %s - %s", what, uri, err)
+	}
+	defer r.Close()
+	c.log(slog.LevelDebug, "resolved RDF document", "uri", uri, "contentType", contentType)
+	res := &LoadResult{URL: defrag(uri), ContentType: objectMime(contentType, uri)}
+	return res, c.parseFetched(r, res, parse)
+}
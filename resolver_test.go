@@ -0,0 +1,42 @@
+package rdf2go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver(t *testing.T) {
+	var resolved []string
+	resolver := ResolverFunc(func(ctx context.Context, iri string) (io.ReadCloser, string, error) {
+		resolved = append(resolved, iri)
+		if iri != "urn:example:people" {
+			return nil, "", errors.New("not found")
+		}
+		doc := `<urn:example:alice> <http://xmlns.com/foaf/0.1/name> "Alice" .`
+		return io.NopCloser(strings.NewReader(doc)), "application/n-triples", nil
+	})
+
+	g := NewGraphWithOptions("", WithResolver("URN", resolver))
+	assert.NoError(t, g.LoadURI("urn:example:people#alice"))
+	assert.Equal(t, []string{"urn:example:people"}, resolved)
+	assert.Equal(t, 1, g.Len())
+	assert.NotNil(t, g.One(NewResource("urn:example:alice"), nil, nil))
+
+	err := g.LoadURI("urn:example:missing")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not found")
+	}
+
+	d := NewDatasetWithOptions("", WithResolver("urn", resolver))
+	assert.NoError(t, d.LoadURI("urn:example:people"))
+	assert.Equal(t, 1, d.Len())
+
+	// other schemes are not resolved
+	assert.Error(t, NewGraphWithOptions("", WithResolver("urn", resolver)).LoadURI("ipfs:QmExample"))
+	assert.Len(t, resolved, 3)
+}
@@ -0,0 +1,111 @@
+package rdf2go
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy makes LoadURI retry the HTTP requests that fail with a
+// network error, a 429 Too Many Requests or a 5xx status, waiting between
+// attempts for an exponentially growing, randomized delay. The Retry-After
+// header of the responses is honored. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, the first request included.
+	// Requests are not retried when it is below 2.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled after each
+	// attempt. It defaults to 500ms.
+	Backoff time.Duration
+	// MaxBackoff bounds the delay between attempts, and defaults to 30s. No
+	// further attempt is made when a server asks to retry later than that.
+	MaxBackoff time.Duration
+}
+
+// delay returns the delay before the given retry, the first one being 1,
+// randomized between half the exponential backoff and the full value
+func (p RetryPolicy) delay(retry int) time.Duration {
+	backoff, max := p.Backoff, p.MaxBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	for i := 1; i < retry && backoff < max; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, max)
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// limit returns the longest delay between attempts
+func (p RetryPolicy) limit() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return 30 * time.Second
+	}
+	return p.MaxBackoff
+}
+
+// retryableStatus tells whether a request failing with status may succeed
+// later
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if len(header) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// do sends a request, retrying it as allowed by the retry policy of the
+// configuration. The request has no body, and is sent again as is.
+func (c *Config) do(ctx context.Context, client *http.Client, q *http.Request) (*http.Response, error) {
+	policy := c.Retry
+	for attempt := 1; ; attempt++ {
+		r, err := client.Do(q)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return r, err
+		}
+		if err == nil && !retryableStatus(r.StatusCode) {
+			return r, nil
+		}
+		wait := policy.delay(attempt)
+		if err == nil {
+			if after, ok := retryAfter(r.Header.Get("Retry-After"), time.Now()); ok {
+				if after > policy.limit() {
+					return r, nil
+				}
+				wait = max(wait, after)
+			}
+			r.Body.Close()
+			c.log(slog.LevelDebug, "retrying request", "uri", q.URL.String(), "status", r.StatusCode, "attempt", attempt, "delay", wait)
+		} else {
+			c.log(slog.LevelDebug, "retrying request", "uri", q.URL.String(), "error", err, "attempt", attempt, "delay", wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package rdf2go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadURIRetry(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		switch {
+		case req.URL.Path == "/later":
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case req.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case requests == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case requests == 2:
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/n-triples")
+		w.Write([]byte("<http://example.org/a> <http://example.org/b> <http://example.org/c> .\n"))
+	}))
+	defer server.Close()
+	retry := WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+	g := NewGraphWithOptions("", retry)
+	assert.NoError(t, g.LoadURI(server.URL+"/doc"))
+	assert.Equal(t, 1, g.Len())
+	assert.Equal(t, 3, requests)
+
+	// client errors are not retried
+	requests = 0
+	assert.Error(t, NewGraphWithOptions("", retry).LoadURI(server.URL+"/missing"))
+	assert.Equal(t, 1, requests)
+
+	// nor are responses asking to retry later than the longest backoff
+	requests = 0
+	err := NewDatasetWithOptions("", retry).LoadURI(server.URL + "/later")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 503")
+	assert.Equal(t, 1, requests)
+
+	// requests are not retried by default
+	requests = 0
+	assert.Error(t, NewGraph("").LoadURI(server.URL+"/doc"))
+	assert.Equal(t, 1, requests)
+
+	// waiting for a retry stops with the context
+	requests = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	g = NewGraphWithOptions("", WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}))
+	assert.ErrorIs(t, g.LoadURIContext(ctx, server.URL+"/doc"), context.DeadlineExceeded)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for i := 0; i < 20; i++ {
+		d := p.delay(1)
+		assert.True(t, d >= 50*time.Millisecond && d <= 100*time.Millisecond, d)
+		d = p.delay(3)
+		assert.True(t, d >= 200*time.Millisecond && d <= 400*time.Millisecond, d)
+		d = p.delay(10)
+		assert.True(t, d >= 500*time.Millisecond && d <= time.Second, d)
+	}
+
+	after, ok := retryAfter("120", time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, after)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	after, ok = retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, after)
+	_, ok = retryAfter("soon", now)
+	assert.False(t, ok)
+}
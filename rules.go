@@ -0,0 +1,279 @@
+package rdf2go
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Rule derives the statements of its head from each solution of its body,
+// like the rules of N3: { body } => { head }. Body and head are triple
+// patterns, whose variables are those of the rules read by ParseRules.
+type Rule struct {
+	// Name identifies the rule in explanations
+	Name string
+	Body []*Triple
+	Head []*Triple
+}
+
+// RuleSet is a set of forward chaining rules, applied on demand to a graph
+// with Apply, or as a graph of a dataset changes with Watch
+type RuleSet struct {
+	Rules []*Rule
+}
+
+// NewRuleSet creates a rule set, checking that the rules have a body that
+// binds the variables of their head
+func NewRuleSet(rules ...*Rule) (*RuleSet, error) {
+	for _, rule := range rules {
+		if len(rule.Body) == 0 {
+			return nil, fmt.Errorf("%s has an empty body", rule.Name)
+		}
+		bound := make(map[string]bool)
+		for _, pattern := range rule.Body {
+			for _, t := range []Term{pattern.Subject, pattern.Predicate, pattern.Object} {
+				if v, ok := t.(*variable); ok {
+					bound[v.name] = true
+				}
+			}
+		}
+		for _, pattern := range rule.Head {
+			for _, t := range []Term{pattern.Subject, pattern.Predicate, pattern.Object} {
+				if v, ok := t.(*variable); ok && !bound[v.name] {
+					return nil, fmt.Errorf("%s: %s is not bound by the body", rule.Name, v)
+				}
+			}
+		}
+	}
+	return &RuleSet{Rules: rules}, nil
+}
+
+// ParseRules parses rules written in a subset of N3: prefix and base
+// declarations, in the Turtle or SPARQL style, followed by rules of the form
+//
+//	{ ?x ex:parent ?y . ?y ex:parent ?z } => { ?x ex:grandparent ?z } .
+//
+// The blank nodes of bodies are variables, and heads cannot hold blank nodes.
+// Rules are named after their position, "rule 1" being the first.
+func ParseRules(src string, base string) (*RuleSet, error) {
+	p, err := newSyntaxParser(src, base)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*Rule
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is(tokDirective, "prefix"), p.tok.is(tokDirective, "base"):
+			directive := p.tok.value
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if directive == "prefix" {
+				err = p.prefixDecl()
+			} else {
+				err = p.baseDecl()
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("."); err != nil {
+				return nil, err
+			}
+		case p.tok.isKeyword("PREFIX"), p.tok.isKeyword("BASE"):
+			if err := p.prologue(); err != nil {
+				return nil, err
+			}
+		default:
+			rule := &Rule{Name: fmt.Sprintf("rule %d", len(rules)+1)}
+			if rule.Body, err = p.ruleGraph(true); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("=>"); err != nil {
+				return nil, err
+			}
+			if rule.Head, err = p.ruleGraph(false); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("."); err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return NewRuleSet(rules...)
+}
+
+// ruleGraph parses the { triples } of the body or the head of a rule, in
+// which variables may be used
+func (p *syntaxParser) ruleGraph(body bool) ([]*Triple, error) {
+	p.variables = true
+	p.blankNodesAsVariables = body
+	p.bnodes = make(map[string]Term)
+	defer func() {
+		p.variables, p.blankNodesAsVariables = false, false
+	}()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var triples []*Triple
+	err := p.triplesBlock(func(s Term, pr Term, o Term) {
+		triples = append(triples, NewTriple(s, pr, o))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !body {
+		for _, triple := range triples {
+			for _, t := range []Term{triple.Subject, triple.Object} {
+				if _, ok := t.(*BlankNode); ok {
+					return nil, p.errorf("blank nodes are not allowed in rule heads")
+				}
+			}
+		}
+	}
+	return triples, p.expectPunct("}")
+}
+
+// Apply adds to g the statements derived by the rules, until no rule derives
+// new ones, and returns them
+func (rs *RuleSet) Apply(g *Graph) *ChangeSet {
+	all := func(s Term, p Term, o Term) []*Quad {
+		var quads []*Quad
+		g.match(s, p, o, func(triple *Triple) bool {
+			quads = append(quads, NewTripleQuad(triple))
+			return true
+		})
+		return quads
+	}
+	c := &ChangeSet{}
+	agenda := all(nil, nil, nil)
+	for len(agenda) > 0 {
+		f := agenda[len(agenda)-1]
+		agenda = agenda[:len(agenda)-1]
+		rs.consequences(f, all, func(_ string, s Term, p Term, o Term, _ ...*Quad) {
+			if g.One(s, p, o) != nil {
+				return
+			}
+			triple := NewTriple(s, p, o)
+			g.Add(triple)
+			q := NewTripleQuad(triple)
+			c.Added = append(c.Added, q)
+			agenda = append(agenda, q)
+		})
+	}
+	return c
+}
+
+// LiveRules keeps the statements derived by a rule set in a graph of a
+// dataset up to date, see RuleSet.Watch
+type LiveRules struct {
+	materializer
+}
+
+// Watch adds the statements derived by the rules to a graph of a dataset,
+// nil being the default graph, and keeps them up to date as the graph
+// changes: an added statement derives its consequences, and a removed one
+// retracts the statements that can no longer be derived without it.
+//
+// Like subscriptions, live rules must be canceled when they are no longer
+// needed.
+func (rs *RuleSet) Watch(d *Dataset, graph Term) *LiveRules {
+	l := &LiveRules{newMaterializer(d, graph, rs)}
+	l.start()
+	return l
+}
+
+func (rs *RuleSet) consequences(f *Quad, all matcher, emit emitFunc) {
+	for _, rule := range rs.Rules {
+		supports := make([]*Quad, len(rule.Body))
+		for i, pattern := range rule.Body {
+			binding, ok := bindPattern(map[string]Term{}, pattern, f)
+			if !ok {
+				continue
+			}
+			supports[i] = f
+			rule.solve(0, i, binding, supports, all, func(solution map[string]Term, supports []*Quad) bool {
+				rule.instantiate(solution, supports, emit)
+				return true
+			})
+		}
+	}
+}
+
+func (rs *RuleSet) derivation(m *Quad, all matcher) (string, []*Quad, bool) {
+	for _, rule := range rs.Rules {
+		for _, pattern := range rule.Head {
+			binding, ok := bindPattern(map[string]Term{}, pattern, m)
+			if !ok {
+				continue
+			}
+			var found []*Quad
+			rule.solve(0, -1, binding, make([]*Quad, len(rule.Body)), all, func(_ map[string]Term, supports []*Quad) bool {
+				found = supports
+				return false
+			})
+			if found != nil {
+				return rule.Name, found, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// solve extends a binding with the statements matching the body patterns
+// from i on, but the one at skip, which is already matched, and calls fn
+// with each solution and the statements matched by the patterns until it
+// returns false. It returns false when stopped.
+func (r *Rule) solve(i int, skip int, binding map[string]Term, supports []*Quad, all matcher, fn func(map[string]Term, []*Quad) bool) bool {
+	if i == skip {
+		i++
+	}
+	if i >= len(r.Body) {
+		return fn(binding, slices.Clone(supports))
+	}
+	pattern := r.Body[i]
+	s := unboundAsNil(bindVariable(pattern.Subject, binding))
+	p := unboundAsNil(bindVariable(pattern.Predicate, binding))
+	o := unboundAsNil(bindVariable(pattern.Object, binding))
+	for _, q := range all(s, p, o) {
+		extended, ok := bindPattern(binding, pattern, q)
+		if !ok {
+			continue
+		}
+		supports[i] = q
+		if !r.solve(i+1, skip, extended, supports, all, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// instantiate emits the statements of the head of the rule for a solution
+func (r *Rule) instantiate(solution map[string]Term, supports []*Quad, emit emitFunc) {
+	for _, pattern := range r.Head {
+		s := bindVariable(pattern.Subject, solution)
+		p := bindVariable(pattern.Predicate, solution)
+		o := bindVariable(pattern.Object, solution)
+		if validStatement(s, p, o) && !isVariable(o) {
+			emit(r.Name, s, p, o, supports...)
+		}
+	}
+}
+
+// bindPattern extends a binding with the terms of a statement matching a
+// triple pattern, failing when the statement does not match it
+func bindPattern(binding map[string]Term, pattern *Triple, q *Quad) (map[string]Term, bool) {
+	terms := []Term{pattern.Subject, pattern.Predicate, pattern.Object}
+	values := []Term{q.Subject, q.Predicate, q.Object}
+	for i, t := range terms {
+		if !isVariable(t) && !t.Equal(values[i]) {
+			return nil, false
+		}
+	}
+	return extendBinding(binding, terms, values)
+}
+
+func isVariable(t Term) bool {
+	_, ok := t.(*variable)
+	return ok
+}
@@ -0,0 +1,88 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRules = `@prefix ex: <http://example.org/> .
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+
+{ ?x ex:parent ?y . ?y ex:parent ?z } => { ?x ex:grandparent ?z } .
+{ ?x ex:grandparent _:g } => { ?x a ex:Grandchild ; foaf:knows ?x } .
+`
+
+func TestParseRules(t *testing.T) {
+	rs, err := ParseRules(testRules, "")
+	assert.NoError(t, err)
+	if assert.Len(t, rs.Rules, 2) {
+		assert.Equal(t, "rule 1", rs.Rules[0].Name)
+		assert.Len(t, rs.Rules[0].Body, 2)
+		assert.Len(t, rs.Rules[1].Head, 2)
+	}
+
+	_, err = ParseRules(`{ ?x <p> ?y } => { ?x <q> ?z } .`, "http://example.org/")
+	assert.EqualError(t, err, "rule 1: ?z is not bound by the body")
+	_, err = ParseRules(`{ ?x <p> ?y } => { ?x <q> _:b } .`, "http://example.org/")
+	assert.Error(t, err)
+	_, err = ParseRules(`{ ?x <p> ?y } { ?x <q> ?y } .`, "http://example.org/")
+	assert.Error(t, err)
+	_, err = NewRuleSet(&Rule{Name: "empty"})
+	assert.EqualError(t, err, "empty has an empty body")
+}
+
+func TestRuleSetApply(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	rs, err := ParseRules(testRules, "")
+	assert.NoError(t, err)
+
+	g := NewGraph(testUri)
+	g.AddTriple(ex.Get("alice"), ex.Get("parent"), ex.Get("bob"))
+	g.AddTriple(ex.Get("bob"), ex.Get("parent"), ex.Get("carol"))
+	g.AddTriple(ex.Get("bob"), ex.Get("parent"), NewLiteral("unknown"))
+
+	changes := rs.Apply(g)
+	assert.Len(t, changes.Added, 4)
+	assert.NotNil(t, g.One(ex.Get("alice"), ex.Get("grandparent"), ex.Get("carol")))
+	assert.NotNil(t, g.One(ex.Get("alice"), ex.Get("grandparent"), NewLiteral("unknown")))
+	assert.NotNil(t, g.One(ex.Get("alice"), rdfType, ex.Get("Grandchild")))
+	assert.NotNil(t, g.One(ex.Get("alice"), NewResource("http://xmlns.com/foaf/0.1/knows"), ex.Get("alice")))
+
+	// applying the rules again derives nothing new
+	assert.Equal(t, 0, rs.Apply(g).Len())
+}
+
+func TestRuleSetWatch(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	rs, err := ParseRules(testRules, "")
+	assert.NoError(t, err)
+
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(ex.Get("alice"), ex.Get("parent"), ex.Get("bob"))
+	live := rs.Watch(d, nil)
+	defer live.Cancel()
+	assert.Empty(t, live.Derived())
+
+	// an added statement derives its consequences, also in chains
+	d.AddTriple(ex.Get("bob"), ex.Get("parent"), ex.Get("carol"))
+	derived := d.One(ex.Get("alice"), ex.Get("grandparent"), ex.Get("carol"), nil)
+	if assert.NotNil(t, derived) {
+		explanation := live.Explain(derived)
+		assert.Equal(t, "rule 1", explanation.Rule)
+		assert.Len(t, explanation.Supports, 2)
+	}
+	assert.NotNil(t, d.One(ex.Get("alice"), rdfType, ex.Get("Grandchild"), nil))
+	assert.Len(t, live.Derived(), 3)
+
+	// a removed statement retracts what can no longer be derived
+	d.Remove(d.One(ex.Get("alice"), ex.Get("parent"), ex.Get("bob"), nil))
+	assert.Nil(t, d.One(ex.Get("alice"), ex.Get("grandparent"), ex.Get("carol"), nil))
+	assert.Nil(t, d.One(ex.Get("alice"), rdfType, ex.Get("Grandchild"), nil))
+	assert.Empty(t, live.Derived())
+
+	// after canceling, nothing is derived anymore
+	live.Cancel()
+	d.AddTriple(ex.Get("alice"), ex.Get("parent"), ex.Get("bob"))
+	assert.Nil(t, d.One(ex.Get("alice"), ex.Get("grandparent"), ex.Get("carol"), nil))
+}
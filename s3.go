@@ -0,0 +1,290 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3PartSize is the size of the parts of multipart uploads, objects smaller
+// than that being uploaded with a single request
+const s3PartSize = 8 << 20
+
+// S3Store is an ObjectStore for Amazon S3 and S3-compatible services, such
+// as MinIO. Requests are signed with AWS Signature Version 4, and objects
+// are uploaded in parts of 8 MiB, so that large exports are streamed rather
+// than held in memory.
+type S3Store struct {
+	// Region is the region of the buckets, such as us-east-1
+	Region string
+	// Endpoint is the URL of the service, defaulting to the AWS endpoint of
+	// the region. Buckets are addressed in the path of the endpoint.
+	Endpoint string
+	// AccessKeyID and SecretAccessKey are the credentials signing requests
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is sent along with temporary credentials
+	SessionToken string
+	// HTTPClient sends the requests, http.DefaultClient being used when nil
+	HTTPClient *http.Client
+
+	// now returns the time at which requests are signed
+	now func() time.Time
+}
+
+// NewS3Store returns an S3Store for the given region and credentials
+func NewS3Store(region string, accessKeyID string, secretAccessKey string) *S3Store {
+	return &S3Store{
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	}
+}
+
+// GetObject implements ObjectStore
+func (s *S3Store) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, string, error) {
+	r, err := s.do(ctx, http.MethodGet, bucket, key, nil, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return r.Body, r.Header.Get("Content-Type"), nil
+}
+
+// PutObject implements ObjectStore, using a multipart upload when the
+// content exceeds a single part
+func (s *S3Store) PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error {
+	part := make([]byte, s3PartSize)
+	n, err := io.ReadFull(body, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r, err := s.do(ctx, http.MethodPut, bucket, key, nil, part[:n], contentType)
+		if err != nil {
+			return err
+		}
+		return r.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	r, err := s.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil, contentType)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(r.Body).Decode(&initiated)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	if err := s.uploadParts(ctx, bucket, key, initiated.UploadID, body, part, n); err != nil {
+		if r, abortErr := s.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {initiated.UploadID}}, nil, ""); abortErr == nil {
+			r.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+// uploadParts uploads the parts of a multipart upload, the first one being
+// already read into part, and completes it
+func (s *S3Store) uploadParts(ctx context.Context, bucket string, key string, uploadID string, body io.Reader, part []byte, n int) error {
+	type completedPart struct {
+		PartNumber int
+		ETag       string
+	}
+	var completed struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}
+	for number := 1; n > 0; number++ {
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		r, err := s.do(ctx, http.MethodPut, bucket, key, query, part[:n], "")
+		if err != nil {
+			return err
+		}
+		r.Body.Close()
+		completed.Parts = append(completed.Parts, completedPart{PartNumber: number, ETag: r.Header.Get("ETag")})
+
+		n, err = io.ReadFull(body, part)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+	}
+	payload, err := xml.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	r, err := s.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, payload, "application/xml")
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	// errors of the completion may come with a 200 status
+	var result struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&result); err == nil && result.XMLName.Local == "Error" {
+		return fmt.Errorf("completing the upload of %s/%s failed - %s: %s", bucket, key, result.Code, result.Message)
+	}
+	return nil
+}
+
+// do sends a signed request about an object, failing on unsuccessful
+// responses
+func (s *S3Store) do(ctx context.Context, method string, bucket string, key string, query url.Values, payload []byte, contentType string) (*http.Response, error) {
+	endpoint := s.Endpoint
+	if len(endpoint) == 0 {
+		endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(u.EscapedPath(), "/")
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + key
+	u.RawPath = base + "/" + s3Escape(bucket, false) + "/" + s3Escape(key, false)
+	u.RawQuery = s3Query(query)
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(s.SessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	hash := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	signV4(req, payloadHash, s.AccessKeyID, s.SecretAccessKey, s.Region, "s3", now())
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		r.Body.Close()
+		return nil, fmt.Errorf("%s %s/%s failed - HTTP %d", method, bucket, key, r.StatusCode)
+	}
+	return r, nil
+}
+
+// signV4 signs a request with AWS Signature Version 4, setting its
+// X-Amz-Date and Authorization headers. The signed headers are the host
+// and the X-Amz-* headers, along with Content-Type when present.
+func signV4(req *http.Request, payloadHash string, accessKeyID string, secretAccessKey string, region string, service string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		s3Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	for _, s := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return h.Sum(nil)
+}
+
+// s3Query returns the canonical form of a query string: sorted by key then
+// value, with keys and values escaped as by s3Escape
+func s3Query(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes every byte but the unreserved characters, and the
+// slashes unless escapeSlash is true
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAlphaNum(c) || c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !escapeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package rdf2go
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla of the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	emptyHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	signV4(req, emptyHash, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+// fakeS3 is an in-memory S3 service supporting multipart uploads
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	parts   map[string][][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	query := req.URL.Query()
+	key := req.URL.Path
+	switch {
+	case req.Method == http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", s.types[key])
+		w.Write(object)
+	case req.Method == http.MethodPost && query.Has("uploads"):
+		s.types[key] = req.Header.Get("Content-Type")
+		s.parts[key] = nil
+		w.Write([]byte("<InitiateMultipartUploadResult><UploadId>u1</UploadId></InitiateMultipartUploadResult>"))
+	case req.Method == http.MethodPut && query.Has("partNumber"):
+		s.parts[key] = append(s.parts[key], body)
+		w.Header().Set("ETag", `"`+query.Get("partNumber")+`"`)
+	case req.Method == http.MethodPost && query.Get("uploadId") == "u1":
+		var completed struct {
+			Parts []struct{ PartNumber int } `xml:"Part"`
+		}
+		xml.Unmarshal(body, &completed)
+		if len(completed.Parts) != len(s.parts[key]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = bytes.Join(s.parts[key], nil)
+		w.Write([]byte("<CompleteMultipartUploadResult/>"))
+	case req.Method == http.MethodPut:
+		s.objects[key] = body
+		s.types[key] = req.Header.Get("Content-Type")
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newFakeS3() (*fakeS3, *httptest.Server, *S3Store) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}, parts: map[string][][]byte{}}
+	server := httptest.NewServer(fake)
+	store := NewS3Store("us-east-1", "AKID", "secret")
+	store.Endpoint = server.URL
+	return fake, server, store
+}
+
+func TestS3Store(t *testing.T) {
+	fake, server, store := newFakeS3()
+	defer server.Close()
+	ctx := context.Background()
+
+	assert.NoError(t, store.PutObject(ctx, "bucket", "a b/c.nt", strings.NewReader("<a> <b> <c> ."), "application/n-triples"))
+	assert.Equal(t, []byte("<a> <b> <c> ."), fake.objects["/bucket/a b/c.nt"])
+	r, contentType, err := store.GetObject(ctx, "bucket", "a b/c.nt")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "<a> <b> <c> .", string(body))
+	assert.Equal(t, "application/n-triples", contentType)
+	_, _, err = store.GetObject(ctx, "bucket", "missing")
+	assert.Error(t, err)
+
+	large := bytes.Repeat([]byte("x"), s3PartSize+10)
+	assert.NoError(t, store.PutObject(ctx, "bucket", "large", bytes.NewReader(large), "text/plain"))
+	assert.Len(t, fake.parts["/bucket/large"], 2)
+	assert.Equal(t, large, fake.objects["/bucket/large"])
+
+	store.SecretAccessKey, store.AccessKeyID = "other", "OTHER"
+	assert.Error(t, store.PutObject(ctx, "bucket", "c", strings.NewReader(""), ""))
+}
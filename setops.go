@@ -0,0 +1,126 @@
+package rdf2go
+
+// Union returns a new dataset, configured like d, holding the quads of d and
+// of other, each distinct statement once. Statements are compared by value,
+// with the IRI policy of d, blank nodes being compared by label.
+func (d *Dataset) Union(other *Dataset) *Dataset {
+	out := d.empty()
+	seen := make(map[string]bool)
+	for _, source := range []*Dataset{d, other} {
+		for quad := range source.Quads() {
+			k := quadKey(d.iriPolicy, quad)
+			if !seen[k] {
+				seen[k] = true
+				out.addFrom(quad, source.Source(quad))
+			}
+		}
+	}
+	return out
+}
+
+// Intersect returns a new dataset, configured like d, holding the quads of d
+// that other holds as well, compared as Union does
+func (d *Dataset) Intersect(other *Dataset) *Dataset {
+	return d.filter(other, true)
+}
+
+// Difference returns a new dataset, configured like d, holding the quads of
+// d that other does not hold, compared as Union does
+func (d *Dataset) Difference(other *Dataset) *Dataset {
+	return d.filter(other, false)
+}
+
+// filter returns the quads of d that other holds, or does not hold
+func (d *Dataset) filter(other *Dataset, held bool) *Dataset {
+	keys := make(map[string]bool)
+	for quad := range other.Quads() {
+		keys[quadKey(d.iriPolicy, quad)] = true
+	}
+	out := d.empty()
+	seen := make(map[string]bool)
+	for quad := range d.Quads() {
+		k := quadKey(d.iriPolicy, quad)
+		if keys[k] == held && !seen[k] {
+			seen[k] = true
+			out.addFrom(quad, d.Source(quad))
+		}
+	}
+	return out
+}
+
+// empty returns an empty dataset configured like d
+func (d *Dataset) empty() *Dataset {
+	out := newDataset(d.uri, d.config, d.httpClient)
+	out.iriPolicy = d.iriPolicy
+	if d.graphs != nil {
+		out.graphs = make(map[string]*graphIndex)
+	}
+	return out
+}
+
+// Union returns a new graph, configured like g, holding the triples of g
+// and of other, compared as Dataset.Union does
+func (g *Graph) Union(other *Graph) *Graph {
+	out := g.empty()
+	seen := make(map[string]bool)
+	for _, source := range []*Graph{g, other} {
+		for triple := range source.Triples() {
+			k := tripleKey(g.iriPolicy, triple)
+			if !seen[k] {
+				seen[k] = true
+				out.Add(triple)
+			}
+		}
+	}
+	return out
+}
+
+// Intersect returns a new graph, configured like g, holding the triples of
+// g that other holds as well, compared as Dataset.Union does
+func (g *Graph) Intersect(other *Graph) *Graph {
+	return g.filter(other, true)
+}
+
+// Difference returns a new graph, configured like g, holding the triples of
+// g that other does not hold, compared as Dataset.Union does
+func (g *Graph) Difference(other *Graph) *Graph {
+	return g.filter(other, false)
+}
+
+// filter returns the triples of g that other holds, or does not hold
+func (g *Graph) filter(other *Graph, held bool) *Graph {
+	keys := make(map[string]bool)
+	for triple := range other.Triples() {
+		keys[tripleKey(g.iriPolicy, triple)] = true
+	}
+	out := g.empty()
+	seen := make(map[string]bool)
+	for triple := range g.Triples() {
+		k := tripleKey(g.iriPolicy, triple)
+		if keys[k] == held && !seen[k] {
+			seen[k] = true
+			out.Add(triple)
+		}
+	}
+	return out
+}
+
+// empty returns an empty graph configured like g
+func (g *Graph) empty() *Graph {
+	out := newGraph(g.uri, g.config, g.httpClient)
+	out.iriPolicy = g.iriPolicy
+	if g.index != nil {
+		out.index = newSPOIndex[*Triple]()
+	}
+	return out
+}
+
+// tripleKey returns the key comparing triples by value
+func tripleKey(policy *IRIPolicy, t *Triple) string {
+	return policy.key(t.Subject) + " " + policy.key(t.Predicate) + " " + policy.key(t.Object)
+}
+
+// quadKey returns the key comparing quads by value
+func quadKey(policy *IRIPolicy, q *Quad) string {
+	return policy.key(q.Subject) + " " + policy.key(q.Predicate) + " " + policy.key(q.Object) + " " + policy.key(q.Graph)
+}
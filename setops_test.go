@@ -0,0 +1,50 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetSetAlgebra(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	a := NewDataset(testDatasetUri)
+	a.AddQuad(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"), nil)
+	a.AddQuad(ex.Get("alice"), ex.Get("name"), NewLiteral("Alice"), ex.Get("g"))
+	a.AddQuad(ex.Get("alice"), ex.Get("name"), NewLiteral("Alice"), ex.Get("g"))
+	b := NewDataset(testDatasetUri)
+	b.AddQuad(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"), nil)
+	b.AddQuad(ex.Get("alice"), ex.Get("name"), NewLiteral("Alice"), nil)
+
+	union := a.Union(b)
+	assert.Equal(t, 3, union.Len())
+	intersection := a.Intersect(b)
+	assert.Equal(t, 1, intersection.Len())
+	assert.NotNil(t, intersection.One(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"), nil))
+	difference := a.Difference(b)
+	assert.Equal(t, 1, difference.Len())
+	assert.NotNil(t, difference.One(ex.Get("alice"), ex.Get("name"), nil, ex.Get("g")))
+	assert.Equal(t, 3, a.Len())
+
+	// the results are new datasets
+	union.AddQuad(ex.Get("carol"), ex.Get("knows"), ex.Get("bob"), nil)
+	assert.Equal(t, 3, a.Len())
+	assert.Equal(t, 2, b.Len())
+}
+
+func TestGraphSetAlgebra(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	a := NewGraph(testUri)
+	a.AddTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))
+	a.AddTriple(NewBlankNode("b1"), ex.Get("name"), NewLiteral("Alice"))
+	b := NewGraph(testUri)
+	b.AddTriple(ex.Get("alice"), ex.Get("knows"), ex.Get("bob"))
+	b.AddTriple(NewBlankNode("b2"), ex.Get("name"), NewLiteral("Alice"))
+
+	assert.Equal(t, 3, a.Union(b).Len())
+	assert.Equal(t, 1, a.Intersect(b).Len())
+	difference := a.Difference(b)
+	assert.Equal(t, 1, difference.Len())
+	assert.NotNil(t, difference.One(NewBlankNode("b1"), nil, nil))
+	assert.Equal(t, 0, a.Difference(a).Len())
+}
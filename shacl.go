@@ -0,0 +1,935 @@
+package rdf2go
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const shNamespace = "http://www.w3.org/ns/shacl#"
+
+// Shapes is a set of SHACL shapes, loaded from a shapes graph with NewShapes.
+// It validates data graphs against the constraint components of SHACL Core,
+// either once with Validate, or continuously with Watch, which re-evaluates
+// only the shapes and focus nodes affected by each change of a dataset.
+//
+// SPARQL-based constraints and sh:qualifiedValueShapesDisjoint are not
+// supported and are ignored.
+type Shapes struct {
+	// targeted lists the shapes declaring targets, in the order of their IRIs
+	targeted []*shape
+	// all holds every shape by the key of its node
+	all map[string]*shape
+}
+
+// ValidationReport is the outcome of validating a data graph
+type ValidationReport struct {
+	// Conforms tells whether the data graph conforms to the shapes, that is
+	// whether validation produced no result at all
+	Conforms bool
+	// Results lists the validation results, sorted by focus node
+	Results []ValidationResult
+}
+
+// ValidationResult reports a focus node that does not satisfy a constraint
+type ValidationResult struct {
+	// Focus is the focus node that was validated
+	Focus Term
+	// Path is the path of the property shape, or the offending property of
+	// a closed shape
+	Path Term
+	// Value is the offending value node, when there is one
+	Value Term
+	// Shape is the shape declaring the constraint
+	Shape Term
+	// Component is the IRI of the constraint component, e.g.
+	// sh:MinCountConstraintComponent
+	Component Term
+	// Severity is the severity of the shape, sh:Violation by default
+	Severity Term
+	// Message is the sh:message of the shape, or a default description
+	Message string
+}
+
+// String returns a one line description of the result
+func (r ValidationResult) String() string {
+	s := r.Focus.String()
+	if r.Path != nil {
+		s += " " + r.Path.String()
+	}
+	if r.Value != nil {
+		s += " " + r.Value.String()
+	}
+	return s + ": " + r.Message + " [" + r.Component.RawValue() + "]"
+}
+
+// Explain returns the explanation of the result, naming the constraint
+// component as rule and the offending statement, when the path is a
+// predicate and the result has a value
+func (r ValidationResult) Explain() *Explanation {
+	e := &Explanation{Rule: r.Component.RawValue(), Message: r.Message}
+	if p, ok := r.Path.(*Resource); ok && r.Value != nil {
+		e.Statement = NewQuad(r.Focus, p, r.Value, nil)
+		e.Supports = []*Quad{e.Statement}
+	}
+	return e
+}
+
+// shape is a node or property shape
+type shape struct {
+	id          Term
+	path        *shapePath
+	pathTerm    Term
+	deactivated bool
+	severity    Term
+	message     string
+
+	targetNodes      []Term
+	targetClasses    []Term
+	targetSubjectsOf []Term
+	targetObjectsOf  []Term
+
+	constraints []shapeConstraint
+}
+
+func (sh *shape) targeted() bool {
+	return len(sh.targetNodes)+len(sh.targetClasses)+len(sh.targetSubjectsOf)+len(sh.targetObjectsOf) > 0
+}
+
+// shapeConstraint is a constraint component instance of a shape, named by the
+// local name of the component IRI without the ConstraintComponent suffix
+type shapeConstraint struct {
+	component string
+	value     Term
+	terms     []Term
+	shapes    []*shape
+	count     int
+	pattern   *regexp.Regexp
+}
+
+// shapePath is a SHACL property path
+type shapePath struct {
+	// kind is 'p' for a predicate, '^' inverse, '/' sequence, '|' alternative,
+	// and '*', '+' or '?' for repetitions
+	kind      byte
+	predicate Term
+	paths     []*shapePath
+}
+
+// NewShapes loads the shapes of a shapes graph: the instances of sh:NodeShape
+// and sh:PropertyShape, the nodes declaring targets, and the shapes they
+// reference. It fails on malformed shapes, such as a non-integer sh:minCount
+// or an invalid sh:pattern.
+func NewShapes(g *Graph) (*Shapes, error) {
+	l := &shapesLoader{g: g, shapes: make(map[string]*shape)}
+	rdfType := NewResource(rdfNamespace + "type")
+	roots := make(map[string]Term)
+	for triple := range g.Triples() {
+		switch {
+		case triple.Predicate.Equal(rdfType) && (triple.Object.Equal(NewResource(shNamespace+"NodeShape")) || triple.Object.Equal(NewResource(shNamespace+"PropertyShape"))),
+			strings.HasPrefix(triple.Predicate.RawValue(), shNamespace+"target"):
+			roots[termKey(triple.Subject)] = triple.Subject
+		}
+	}
+	keys := make([]string, 0, len(roots))
+	for k := range roots {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	shapes := &Shapes{all: l.shapes}
+	for _, k := range keys {
+		sh, err := l.shape(roots[k])
+		if err != nil {
+			return nil, err
+		}
+		if sh.targeted() {
+			shapes.targeted = append(shapes.targeted, sh)
+		}
+	}
+	return shapes, nil
+}
+
+type shapesLoader struct {
+	g      *Graph
+	shapes map[string]*shape
+}
+
+func (l *shapesLoader) objects(s Term, local string) []Term {
+	var objects []Term
+	for _, triple := range l.g.All(s, NewResource(shNamespace+local), nil) {
+		objects = append(objects, triple.Object)
+	}
+	slices.SortFunc(objects, func(a, b Term) int {
+		return strings.Compare(termKey(a), termKey(b))
+	})
+	return objects
+}
+
+func (l *shapesLoader) list(head Term) ([]Term, error) {
+	_, elements, err := l.g.patchList(head)
+	return elements, err
+}
+
+// shape returns the shape described by node, loading it the first time
+func (l *shapesLoader) shape(node Term) (*shape, error) {
+	if sh, ok := l.shapes[termKey(node)]; ok {
+		return sh, nil
+	}
+	sh := &shape{id: node, severity: NewResource(shNamespace + "Violation")}
+	// registered before its constraints are loaded, for recursive shapes
+	l.shapes[termKey(node)] = sh
+
+	if paths := l.objects(node, "path"); len(paths) > 0 {
+		path, err := l.path(paths[0], 0)
+		if err != nil {
+			return nil, fmt.Errorf("shape %s: %s", node, err)
+		}
+		sh.path, sh.pathTerm = path, paths[0]
+	}
+	for _, v := range l.objects(node, "deactivated") {
+		sh.deactivated = v.RawValue() == "true"
+	}
+	for _, v := range l.objects(node, "severity") {
+		sh.severity = v
+	}
+	for _, v := range l.objects(node, "message") {
+		// prefer messages without language, or in English
+		if lit, ok := v.(*Literal); len(sh.message) == 0 || ok && (lit.Language == "" || strings.HasPrefix(lit.Language, "en")) {
+			sh.message = v.RawValue()
+		}
+	}
+	sh.targetNodes = l.objects(node, "targetNode")
+	sh.targetClasses = l.objects(node, "targetClass")
+	sh.targetSubjectsOf = l.objects(node, "targetSubjectsOf")
+	sh.targetObjectsOf = l.objects(node, "targetObjectsOf")
+	if l.g.One(node, NewResource(rdfNamespace+"type"), NewResource(rdfsNamespace+"Class")) != nil {
+		// implicit class target
+		sh.targetClasses = append(sh.targetClasses, node)
+	}
+
+	if err := l.constraints(sh); err != nil {
+		return nil, fmt.Errorf("shape %s: %s", node, err)
+	}
+	return sh, nil
+}
+
+// shapeParameters lists the parameters of the supported constraint
+// components, in evaluation order
+var shapeParameters = []string{
+	"class", "datatype", "nodeKind", "minCount", "maxCount",
+	"minExclusive", "minInclusive", "maxExclusive", "maxInclusive",
+	"minLength", "maxLength", "pattern", "languageIn", "uniqueLang",
+	"equals", "disjoint", "lessThan", "lessThanOrEquals",
+	"not", "and", "or", "xone", "node", "property", "qualifiedValueShape",
+	"closed", "hasValue", "in",
+}
+
+func (l *shapesLoader) constraints(sh *shape) error {
+	for _, param := range shapeParameters {
+		for _, v := range l.objects(sh.id, param) {
+			c := shapeConstraint{component: strings.ToUpper(param[:1]) + param[1:], value: v}
+			var err error
+			switch param {
+			case "minCount", "maxCount", "minLength", "maxLength":
+				c.count, err = shapeInteger(param, v)
+			case "pattern":
+				c.pattern, err = l.pattern(sh.id, v)
+			case "languageIn", "in":
+				c.terms, err = l.list(v)
+			case "uniqueLang", "closed":
+				if v.RawValue() != "true" {
+					continue
+				}
+				if param == "closed" {
+					c.terms, err = l.closedProperties(sh.id)
+				}
+			case "not", "node", "property":
+				var s *shape
+				s, err = l.shape(v)
+				c.shapes = []*shape{s}
+			case "and", "or", "xone":
+				var members []Term
+				if members, err = l.list(v); err == nil {
+					for _, m := range members {
+						var s *shape
+						if s, err = l.shape(m); err != nil {
+							break
+						}
+						c.shapes = append(c.shapes, s)
+					}
+				}
+			case "qualifiedValueShape":
+				var s *shape
+				if s, err = l.shape(v); err != nil {
+					break
+				}
+				c.shapes = []*shape{s}
+				for _, bound := range []string{"qualifiedMinCount", "qualifiedMaxCount"} {
+					for _, n := range l.objects(sh.id, bound) {
+						q := shapeConstraint{component: strings.ToUpper(bound[:1]) + bound[1:], value: n, shapes: c.shapes}
+						if q.count, err = shapeInteger(bound, n); err != nil {
+							return err
+						}
+						sh.constraints = append(sh.constraints, q)
+					}
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			sh.constraints = append(sh.constraints, c)
+		}
+	}
+	return nil
+}
+
+func shapeInteger(param string, v Term) (int, error) {
+	n, err := strconv.Atoi(v.RawValue())
+	if lit, ok := v.(*Literal); err != nil || !ok || n < 0 {
+		return 0, fmt.Errorf("sh:%s must be a non-negative integer, got %s", param, v)
+	} else if lit.Datatype != nil && !lit.Datatype.Equal(NewResource(xsdNamespace+"integer")) {
+		return 0, fmt.Errorf("sh:%s must be an xsd:integer, got %s", param, v)
+	}
+	return n, nil
+}
+
+// pattern compiles sh:pattern with the flags of the shape. Go regular
+// expressions do not support the x flag.
+func (l *shapesLoader) pattern(node Term, v Term) (*regexp.Regexp, error) {
+	expr := v.RawValue()
+	for _, flags := range l.objects(node, "flags") {
+		var prefix string
+		for _, f := range flags.RawValue() {
+			switch f {
+			case 'i', 'm', 's':
+				prefix += string(f)
+			case 'q':
+				expr = regexp.QuoteMeta(v.RawValue())
+			default:
+				return nil, fmt.Errorf("unsupported sh:flags %q", flags.RawValue())
+			}
+		}
+		if len(prefix) > 0 {
+			expr = "(?" + prefix + ")" + expr
+		}
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sh:pattern %q: %s", v.RawValue(), err)
+	}
+	return re, nil
+}
+
+// closedProperties returns the properties allowed by a closed shape: the
+// predicate paths of its property shapes and sh:ignoredProperties
+func (l *shapesLoader) closedProperties(node Term) ([]Term, error) {
+	var allowed []Term
+	for _, p := range l.objects(node, "property") {
+		for _, path := range l.objects(p, "path") {
+			if _, ok := path.(*Resource); ok {
+				allowed = append(allowed, path)
+			}
+		}
+	}
+	for _, ignored := range l.objects(node, "ignoredProperties") {
+		terms, err := l.list(ignored)
+		if err != nil {
+			return nil, err
+		}
+		allowed = append(allowed, terms...)
+	}
+	return allowed, nil
+}
+
+// path parses a property path, failing on paths nested too deeply, which
+// are most likely cyclic
+func (l *shapesLoader) path(node Term, depth int) (*shapePath, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("the path %s is nested too deeply", node)
+	}
+	if _, ok := node.(*Resource); ok && !node.Equal(NewResource(rdfNamespace+"nil")) {
+		return &shapePath{kind: 'p', predicate: node}, nil
+	}
+	if l.g.One(node, NewResource(rdfNamespace+"first"), nil) != nil {
+		members, err := l.list(node)
+		if err != nil {
+			return nil, err
+		}
+		return l.paths('/', members, depth)
+	}
+	for i, local := range []string{"inversePath", "zeroOrMorePath", "oneOrMorePath", "zeroOrOnePath"} {
+		if sub := l.objects(node, local); len(sub) == 1 {
+			return l.paths("^*+?"[i], sub, depth)
+		}
+	}
+	if alt := l.objects(node, "alternativePath"); len(alt) == 1 {
+		members, err := l.list(alt[0])
+		if err != nil {
+			return nil, err
+		}
+		return l.paths('|', members, depth)
+	}
+	return nil, fmt.Errorf("%s is not a valid path", node)
+}
+
+func (l *shapesLoader) paths(kind byte, nodes []Term, depth int) (*shapePath, error) {
+	path := &shapePath{kind: kind}
+	for _, n := range nodes {
+		sub, err := l.path(n, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		path.paths = append(path.paths, sub)
+	}
+	return path, nil
+}
+
+// Validate validates a graph against the shapes
+func (s *Shapes) Validate(g *Graph) *ValidationReport {
+	c := &shaclContext{
+		match: func(subject, predicate, object Term, fn func(Term, Term, Term) bool) {
+			g.match(subject, predicate, object, func(t *Triple) bool {
+				return fn(t.Subject, t.Predicate, t.Object)
+			})
+		},
+		key: g.iriPolicy.key,
+	}
+	var results []ValidationResult
+	for _, sh := range s.targeted {
+		for _, focus := range c.focusNodes(sh) {
+			results = append(results, c.validate(focus, sh)...)
+		}
+	}
+	return newValidationReport(results)
+}
+
+func newValidationReport(results []ValidationResult) *ValidationReport {
+	keys := make(map[*ValidationResult]string, len(results))
+	for i := range results {
+		r := &results[i]
+		keys[r] = strings.Join([]string{termKey(r.Focus), termKey(r.Shape), termKey(r.Path), r.Component.RawValue(), termKey(r.Value), r.Message}, " ")
+	}
+	sorted := make([]*ValidationResult, len(results))
+	for i := range results {
+		sorted[i] = &results[i]
+	}
+	slices.SortStableFunc(sorted, func(a, b *ValidationResult) int {
+		return strings.Compare(keys[a], keys[b])
+	})
+	report := &ValidationReport{Conforms: len(results) == 0}
+	for _, r := range sorted {
+		report.Results = append(report.Results, *r)
+	}
+	return report
+}
+
+// shaclContext evaluates shapes over a data graph. When reads is not nil,
+// it records the parts of the graph that were read: "o <s> <p>" for the
+// objects of s and p, "o <s> *" for all statements about s, and "i <p> <o>"
+// for the subjects of p and o.
+type shaclContext struct {
+	match  func(s, p, o Term, fn func(Term, Term, Term) bool)
+	key    func(Term) string
+	reads  map[string]bool
+	active map[string]bool
+}
+
+func (c *shaclContext) read(key string) {
+	if c.reads != nil {
+		c.reads[key] = true
+	}
+}
+
+func (c *shaclContext) objects(s Term, p Term) []Term {
+	c.read("o " + c.key(s) + " " + c.key(p))
+	var objects []Term
+	c.match(s, p, nil, func(_, _, o Term) bool {
+		objects = append(objects, o)
+		return true
+	})
+	return objects
+}
+
+func (c *shaclContext) subjects(p Term, o Term) []Term {
+	c.read("i " + c.key(p) + " " + c.key(o))
+	var subjects []Term
+	c.match(nil, p, o, func(s, _, _ Term) bool {
+		subjects = append(subjects, s)
+		return true
+	})
+	return subjects
+}
+
+func (c *shaclContext) equal(a Term, b Term) bool {
+	return c.key(a) == c.key(b)
+}
+
+func (c *shaclContext) contains(terms []Term, t Term) bool {
+	return slices.ContainsFunc(terms, func(u Term) bool { return c.equal(t, u) })
+}
+
+// dedupe removes duplicates from terms, keeping the first occurrences
+func (c *shaclContext) dedupe(terms []Term) []Term {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0:0]
+	for _, t := range terms {
+		if k := c.key(t); !seen[k] {
+			seen[k] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// instanceOf returns whether node is a SHACL instance of class, following
+// rdfs:subClassOf
+func (c *shaclContext) instanceOf(node Term, class Term) bool {
+	subClassOf := NewResource(rdfsNamespace + "subClassOf")
+	seen := make(map[string]bool)
+	types := c.objects(node, NewResource(rdfNamespace+"type"))
+	for len(types) > 0 {
+		t := types[0]
+		types = types[1:]
+		if seen[c.key(t)] {
+			continue
+		}
+		if c.equal(t, class) {
+			return true
+		}
+		seen[c.key(t)] = true
+		types = append(types, c.objects(t, subClassOf)...)
+	}
+	return false
+}
+
+// focusNodes returns the targets of a shape
+func (c *shaclContext) focusNodes(sh *shape) []Term {
+	nodes := slices.Clone(sh.targetNodes)
+	rdfType := NewResource(rdfNamespace + "type")
+	for _, class := range sh.targetClasses {
+		seen := make(map[string]bool)
+		for classes := []Term{class}; len(classes) > 0; classes = classes[1:] {
+			if seen[c.key(classes[0])] {
+				continue
+			}
+			seen[c.key(classes[0])] = true
+			nodes = append(nodes, c.subjects(rdfType, classes[0])...)
+			classes = append(classes, c.subjects(NewResource(rdfsNamespace+"subClassOf"), classes[0])...)
+		}
+	}
+	for _, p := range sh.targetSubjectsOf {
+		c.match(nil, p, nil, func(s, _, _ Term) bool {
+			nodes = append(nodes, s)
+			return true
+		})
+	}
+	for _, p := range sh.targetObjectsOf {
+		c.match(nil, p, nil, func(_, _, o Term) bool {
+			nodes = append(nodes, o)
+			return true
+		})
+	}
+	return c.dedupe(nodes)
+}
+
+// isFocus returns whether node is a target of a shape
+func (c *shaclContext) isFocus(node Term, sh *shape) bool {
+	if c.contains(sh.targetNodes, node) {
+		return true
+	}
+	for _, class := range sh.targetClasses {
+		if c.instanceOf(node, class) {
+			return true
+		}
+	}
+	found := false
+	for _, p := range sh.targetSubjectsOf {
+		c.match(node, p, nil, func(_, _, _ Term) bool {
+			found = true
+			return false
+		})
+	}
+	for _, p := range sh.targetObjectsOf {
+		c.match(nil, p, node, func(_, _, _ Term) bool {
+			found = true
+			return false
+		})
+	}
+	return found
+}
+
+// evalPath returns the nodes reached from nodes through a path, or through
+// its inverse
+func (c *shaclContext) evalPath(nodes []Term, path *shapePath, inverse bool) []Term {
+	var reached []Term
+	switch path.kind {
+	case 'p':
+		for _, n := range nodes {
+			if inverse {
+				reached = append(reached, c.subjects(path.predicate, n)...)
+			} else {
+				reached = append(reached, c.objects(n, path.predicate)...)
+			}
+		}
+	case '^':
+		reached = c.evalPath(nodes, path.paths[0], !inverse)
+	case '/':
+		reached = nodes
+		for i := range path.paths {
+			sub := path.paths[i]
+			if inverse {
+				sub = path.paths[len(path.paths)-1-i]
+			}
+			reached = c.dedupe(c.evalPath(reached, sub, inverse))
+		}
+	case '|':
+		for _, sub := range path.paths {
+			reached = append(reached, c.evalPath(nodes, sub, inverse)...)
+		}
+	case '?':
+		reached = append(slices.Clone(nodes), c.evalPath(nodes, path.paths[0], inverse)...)
+	case '*', '+':
+		seen := make(map[string]bool)
+		if path.kind == '*' {
+			reached = slices.Clone(nodes)
+			for _, n := range nodes {
+				seen[c.key(n)] = true
+			}
+		}
+		for frontier := nodes; len(frontier) > 0; {
+			var next []Term
+			for _, n := range c.evalPath(frontier, path.paths[0], inverse) {
+				if !seen[c.key(n)] {
+					seen[c.key(n)] = true
+					reached = append(reached, n)
+					next = append(next, n)
+				}
+			}
+			frontier = next
+		}
+	}
+	return reached
+}
+
+// conforms returns whether node conforms to a shape. Shapes that are already
+// being evaluated for node are assumed to conform, so that recursive shapes
+// terminate.
+func (c *shaclContext) conforms(node Term, sh *shape) bool {
+	return len(c.nested(node, sh)) == 0
+}
+
+// nested validates node against a shape referenced by another one, unless
+// that shape is already being evaluated for node
+func (c *shaclContext) nested(node Term, sh *shape) []ValidationResult {
+	key := fmt.Sprintf("%p %s", sh, c.key(node))
+	if c.active[key] {
+		return nil
+	}
+	if c.active == nil {
+		c.active = make(map[string]bool)
+	}
+	c.active[key] = true
+	defer delete(c.active, key)
+	return c.validate(node, sh)
+}
+
+// validate returns the results of validating a focus node against a shape
+func (c *shaclContext) validate(focus Term, sh *shape) []ValidationResult {
+	if sh.deactivated {
+		return nil
+	}
+	values := []Term{focus}
+	if sh.path != nil {
+		values = c.dedupe(c.evalPath(values, sh.path, false))
+	}
+	var results []ValidationResult
+	for i := range sh.constraints {
+		results = c.check(results, focus, values, sh, &sh.constraints[i])
+	}
+	return results
+}
+
+// check appends the results of a constraint to results
+func (c *shaclContext) check(results []ValidationResult, focus Term, values []Term, sh *shape, con *shapeConstraint) []ValidationResult {
+	report := func(value Term, message string, args ...any) {
+		r := ValidationResult{
+			Focus:     focus,
+			Path:      sh.pathTerm,
+			Value:     value,
+			Shape:     sh.id,
+			Component: NewResource(shNamespace + con.component + "ConstraintComponent"),
+			Severity:  sh.severity,
+			Message:   sh.message,
+		}
+		if len(r.Message) == 0 {
+			r.Message = fmt.Sprintf(message, args...)
+		}
+		results = append(results, r)
+	}
+	each := func(fails func(v Term) bool, message string, args ...any) {
+		for _, v := range values {
+			if fails(v) {
+				report(v, message, args...)
+			}
+		}
+	}
+
+	switch con.component {
+	case "Class":
+		each(func(v Term) bool { return !c.instanceOf(v, con.value) }, "value is not an instance of %s", con.value)
+	case "Datatype":
+		each(func(v Term) bool { return !hasDatatype(v, con.value) }, "value is not a valid literal of datatype %s", con.value)
+	case "NodeKind":
+		each(func(v Term) bool { return !hasNodeKind(v, con.value) }, "value does not have node kind %s", con.value)
+	case "MinCount":
+		if len(values) < con.count {
+			report(nil, "less than %d values", con.count)
+		}
+	case "MaxCount":
+		if len(values) > con.count {
+			report(nil, "more than %d values", con.count)
+		}
+	case "MinExclusive", "MinInclusive", "MaxExclusive", "MaxInclusive":
+		each(func(v Term) bool {
+			cmp, ok := compareLiterals(v, con.value)
+			switch con.component {
+			case "MinExclusive":
+				return !ok || cmp <= 0
+			case "MinInclusive":
+				return !ok || cmp < 0
+			case "MaxExclusive":
+				return !ok || cmp >= 0
+			}
+			return !ok || cmp > 0
+		}, "value is out of the range set by sh:%s %s", strings.ToLower(con.component[:1])+con.component[1:], con.value)
+	case "MinLength", "MaxLength":
+		each(func(v Term) bool {
+			if _, ok := v.(*BlankNode); ok {
+				return true
+			}
+			n := utf8.RuneCountInString(v.RawValue())
+			return con.component == "MinLength" && n < con.count || con.component == "MaxLength" && n > con.count
+		}, "value does not satisfy sh:%s %d", strings.ToLower(con.component[:1])+con.component[1:], con.count)
+	case "Pattern":
+		each(func(v Term) bool {
+			_, blank := v.(*BlankNode)
+			return blank || !con.pattern.MatchString(v.RawValue())
+		}, "value does not match the pattern %q", con.value.RawValue())
+	case "LanguageIn":
+		each(func(v Term) bool {
+			lit, ok := v.(*Literal)
+			return !ok || !slices.ContainsFunc(con.terms, func(r Term) bool { return languageMatches(lit.Language, r.RawValue()) })
+		}, "value does not have one of the languages %v", con.terms)
+	case "UniqueLang":
+		counts := make(map[string]int)
+		var languages []string
+		for _, v := range values {
+			if lit, ok := v.(*Literal); ok && len(lit.Language) > 0 {
+				lang := strings.ToLower(lit.Language)
+				if counts[lang]++; counts[lang] == 2 {
+					languages = append(languages, lang)
+				}
+			}
+		}
+		for _, lang := range languages {
+			report(nil, "more than one value has the language %q", lang)
+		}
+	case "Equals":
+		others := c.objects(focus, con.value)
+		each(func(v Term) bool { return !c.contains(others, v) }, "value is not a value of %s", con.value)
+		for _, o := range c.dedupe(others) {
+			if !c.contains(values, o) {
+				report(o, "value of %s is missing", con.value)
+			}
+		}
+	case "Disjoint":
+		others := c.objects(focus, con.value)
+		each(func(v Term) bool { return c.contains(others, v) }, "value is also a value of %s", con.value)
+	case "LessThan", "LessThanOrEquals":
+		others := c.objects(focus, con.value)
+		each(func(v Term) bool {
+			for _, o := range others {
+				cmp, ok := compareLiterals(v, o)
+				if !ok || cmp > 0 || cmp == 0 && con.component == "LessThan" {
+					return true
+				}
+			}
+			return false
+		}, "value is not less than the values of %s", con.value)
+	case "Not":
+		each(func(v Term) bool { return c.conforms(v, con.shapes[0]) }, "value conforms to the shape %s", con.value)
+	case "And":
+		each(func(v Term) bool {
+			return slices.ContainsFunc(con.shapes, func(s *shape) bool { return !c.conforms(v, s) })
+		}, "value does not conform to all the shapes of %s", con.value)
+	case "Or":
+		each(func(v Term) bool {
+			return !slices.ContainsFunc(con.shapes, func(s *shape) bool { return c.conforms(v, s) })
+		}, "value does not conform to any shape of %s", con.value)
+	case "Xone":
+		each(func(v Term) bool {
+			n := 0
+			for _, s := range con.shapes {
+				if c.conforms(v, s) {
+					n++
+				}
+			}
+			return n != 1
+		}, "value does not conform to exactly one shape of %s", con.value)
+	case "Node":
+		each(func(v Term) bool { return !c.conforms(v, con.shapes[0]) }, "value does not conform to the shape %s", con.value)
+	case "Property":
+		for _, v := range values {
+			results = append(results, c.nested(v, con.shapes[0])...)
+		}
+	case "QualifiedMinCount", "QualifiedMaxCount":
+		n := 0
+		for _, v := range values {
+			if c.conforms(v, con.shapes[0]) {
+				n++
+			}
+		}
+		if con.component == "QualifiedMinCount" && n < con.count {
+			report(nil, "less than %d values conform to the qualified value shape", con.count)
+		} else if con.component == "QualifiedMaxCount" && n > con.count {
+			report(nil, "more than %d values conform to the qualified value shape", con.count)
+		}
+	case "Closed":
+		for _, v := range values {
+			c.read("o " + c.key(v) + " *")
+			var extra []*Triple
+			c.match(v, nil, nil, func(s, p, o Term) bool {
+				if !c.contains(con.terms, p) {
+					extra = append(extra, NewTriple(s, p, o))
+				}
+				return true
+			})
+			slices.SortFunc(extra, func(a, b *Triple) int { return strings.Compare(a.String(), b.String()) })
+			for _, t := range extra {
+				report(t.Object, "property %s is not allowed", t.Predicate)
+				results[len(results)-1].Path = t.Predicate
+			}
+		}
+	case "HasValue":
+		if !c.contains(values, con.value) {
+			report(nil, "%s is missing", con.value)
+		}
+	case "In":
+		each(func(v Term) bool { return !c.contains(con.terms, v) }, "value is not one of %v", con.terms)
+	}
+	return results
+}
+
+// hasNodeKind returns whether a term has one of the kinds of sh:nodeKind
+func hasNodeKind(t Term, kind Term) bool {
+	var name string
+	switch t.(type) {
+	case *BlankNode:
+		name = "BlankNode"
+	case *Resource:
+		name = "IRI"
+	case *Literal:
+		name = "Literal"
+	}
+	local := strings.TrimPrefix(kind.RawValue(), shNamespace)
+	return len(name) > 0 && (local == name || strings.HasPrefix(local, name+"Or") || strings.HasSuffix(local, "Or"+name))
+}
+
+// hasDatatype returns whether a term is a literal of the datatype, with a
+// valid lexical form for the xsd datatypes whose values are checked
+func hasDatatype(t Term, datatype Term) bool {
+	lit, ok := t.(*Literal)
+	if !ok {
+		return false
+	}
+	var actual Term = NewResource(xsdNamespace + "string")
+	if lit.Datatype != nil {
+		actual = lit.Datatype
+	} else if len(lit.Language) > 0 {
+		actual = NewResource(rdfNamespace + "langString")
+	}
+	return actual.Equal(datatype) && wellFormedLiteral(lit)
+}
+
+// literalTime parses the value of xsd:dateTime and xsd:date literals
+func literalTime(lit *Literal) (time.Time, bool) {
+	if lit.Datatype == nil {
+		return time.Time{}, false
+	}
+	var layouts []string
+	switch lit.Datatype.RawValue() {
+	case xsdNamespace + "dateTime":
+		layouts = []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05.999999999"}
+	case xsdNamespace + "date":
+		layouts = []string{"2006-01-02Z07:00", "2006-01-02"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(lit.Value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compareLiterals orders two literals by value: numbers, dates and times,
+// strings, and literals sharing a datatype with a registered comparator. It
+// returns false for incomparable terms.
+func compareLiterals(a Term, b Term) (int, bool) {
+	la, ok := a.(*Literal)
+	if !ok {
+		return 0, false
+	}
+	lb, ok := b.(*Literal)
+	if !ok {
+		return 0, false
+	}
+	if ra, ia, ok := literalNumber(la); ok {
+		rb, ib, ok := literalNumber(lb)
+		if !ok {
+			return 0, false
+		}
+		if ia != 0 || ib != 0 {
+			// infinite values, finite ones counting as zero
+			return ia - ib, true
+		}
+		return ra.Cmp(rb), true
+	}
+	if ta, ok := literalTime(la); ok {
+		tb, ok := literalTime(lb)
+		if !ok || !la.Datatype.Equal(lb.Datatype) {
+			return 0, false
+		}
+		return ta.Compare(tb), true
+	}
+	isString := func(l *Literal) bool {
+		return l.Datatype == nil || l.Datatype.Equal(NewResource(xsdNamespace+"string"))
+	}
+	if isString(la) && isString(lb) && la.Language == lb.Language {
+		return strings.Compare(la.Value, lb.Value), true
+	}
+	if la.Datatype != nil && lb.Datatype != nil && la.Datatype.Equal(lb.Datatype) {
+		if cmp, ok := lookupComparator(la.Datatype); ok && cmp.Compare != nil {
+			c, err := cmp.Compare(la.Value, lb.Value)
+			return c, err == nil
+		}
+	}
+	return 0, false
+}
+
+// languageMatches implements the basic filtering of RFC 4647
+func languageMatches(tag string, languageRange string) bool {
+	if len(tag) == 0 {
+		return false
+	}
+	if languageRange == "*" {
+		return true
+	}
+	tag, languageRange = strings.ToLower(tag), strings.ToLower(languageRange)
+	return tag == languageRange || strings.HasPrefix(tag, languageRange+"-")
+}
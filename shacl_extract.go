@@ -0,0 +1,129 @@
+package rdf2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Extract returns the statements of g describing focus according to a
+// shape of the shapes graph: the statements traversed by the paths of its
+// property shapes, followed recursively into the shapes that sh:node,
+// sh:property, sh:and and sh:qualifiedValueShape apply to the values. For
+// sh:or and sh:xone, only the member shapes the values conform to are
+// followed, and sh:not is ignored. This gives a payload holding what the
+// shape describes, rather than everything known about the node.
+func (s *Shapes) Extract(g *Graph, focus Term, shapeID Term) (*Graph, error) {
+	sh, ok := s.all[termKey(shapeID)]
+	if !ok {
+		return nil, fmt.Errorf("unknown shape %s", shapeID)
+	}
+	e := newExtractor(g)
+	e.shape(focus, sh)
+	return e.out, nil
+}
+
+// PropertyTree describes the statements to extract about a node, mapping
+// the IRIs of the predicates to follow to the trees describing their
+// objects, nil for none. IRIs starting with ^ are followed backwards, from
+// the objects to the subjects of the predicate.
+type PropertyTree map[string]PropertyTree
+
+// Extract returns the statements of g describing focus according to a
+// property tree, see Shapes.Extract
+func (g *Graph) Extract(focus Term, tree PropertyTree) *Graph {
+	e := newExtractor(g)
+	e.tree([]Term{focus}, tree, make(map[string]bool))
+	return e.out
+}
+
+// extractor copies the statements read by a shaclContext into out while
+// recording is true
+type extractor struct {
+	c         *shaclContext
+	out       *Graph
+	recording bool
+	active    map[string]bool
+}
+
+func newExtractor(g *Graph) *extractor {
+	e := &extractor{
+		out:       newGraph(g.uri, g.config, g.httpClient),
+		recording: true,
+		active:    make(map[string]bool),
+	}
+	e.out.index = newSPOIndex[*Triple]()
+	e.out.iriPolicy = g.iriPolicy
+	e.c = &shaclContext{
+		match: func(subject, predicate, object Term, fn func(Term, Term, Term) bool) {
+			g.match(subject, predicate, object, func(t *Triple) bool {
+				if e.recording {
+					e.out.Add(t)
+				}
+				return fn(t.Subject, t.Predicate, t.Object)
+			})
+		},
+		key: g.iriPolicy.key,
+	}
+	return e
+}
+
+// shape extracts the statements describing node according to a shape
+func (e *extractor) shape(node Term, sh *shape) {
+	key := fmt.Sprintf("%p %s", sh, e.c.key(node))
+	if sh.deactivated || e.active[key] {
+		return
+	}
+	e.active[key] = true
+
+	values := []Term{node}
+	if sh.path != nil {
+		values = e.c.dedupe(e.c.evalPath(values, sh.path, false))
+	}
+	for _, con := range sh.constraints {
+		switch con.component {
+		case "Node", "Property", "And", "QualifiedValueShape":
+			for _, v := range values {
+				for _, nested := range con.shapes {
+					e.shape(v, nested)
+				}
+			}
+		case "Or", "Xone":
+			for _, v := range values {
+				for _, nested := range con.shapes {
+					if e.conforms(v, nested) {
+						e.shape(v, nested)
+					}
+				}
+			}
+		}
+	}
+}
+
+// conforms returns whether node conforms to a shape, without recording the
+// statements read by the validation
+func (e *extractor) conforms(node Term, sh *shape) bool {
+	recording := e.recording
+	e.recording = false
+	defer func() { e.recording = recording }()
+	return e.c.conforms(node, sh)
+}
+
+// tree extracts the statements describing nodes according to a property
+// tree, seen holding the nodes already described by each subtree
+func (e *extractor) tree(nodes []Term, tree PropertyTree, seen map[string]bool) {
+	for iri, subtree := range tree {
+		path := &shapePath{kind: 'p', predicate: NewResource(strings.TrimPrefix(iri, "^"))}
+		values := e.c.dedupe(e.c.evalPath(nodes, path, strings.HasPrefix(iri, "^")))
+		var next []Term
+		for _, v := range values {
+			key := fmt.Sprintf("%p %s", subtree, e.c.key(v))
+			if len(subtree) > 0 && !seen[key] {
+				seen[key] = true
+				next = append(next, v)
+			}
+		}
+		if len(next) > 0 {
+			e.tree(next, subtree, seen)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testShapes = `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix ex: <http://example.org/> .
+
+ex:PersonShape a sh:NodeShape ;
+  sh:targetClass ex:Person ;
+  sh:property [
+    sh:path ex:name ;
+    sh:minCount 1 ;
+    sh:maxCount 1 ;
+    sh:datatype xsd:string ;
+    sh:minLength 2
+  ] ;
+  sh:property [
+    sh:path ex:age ;
+    sh:datatype xsd:integer ;
+    sh:minInclusive 0 ;
+    sh:maxExclusive 150
+  ] ;
+  sh:property [
+    sh:path ex:knows ;
+    sh:class ex:Person ;
+    sh:nodeKind sh:IRI
+  ] ;
+  sh:property [
+    sh:path ex:email ;
+    sh:pattern "^[^@]+@example\\.org$" ;
+    sh:flags "i"
+  ] ;
+  sh:property [
+    sh:path ex:status ;
+    sh:in ( "active" "retired" )
+  ] .
+
+ex:LabelShape a sh:NodeShape ;
+  sh:targetSubjectsOf ex:label ;
+  sh:property [
+    sh:path ex:label ;
+    sh:uniqueLang true ;
+    sh:languageIn ( "en" "fr" )
+  ] .
+`
+
+const testShapesData = `@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix ex: <http://example.org/> .
+
+ex:alice a ex:Person ;
+  ex:name "Alice" ;
+  ex:age 30 ;
+  ex:knows ex:bob ;
+  ex:email "ALICE@EXAMPLE.ORG" ;
+  ex:status "active" .
+
+ex:bob a ex:Employee ;
+  ex:name "B" , "Bobby" ;
+  ex:age -1 ;
+  ex:knows ex:carol ;
+  ex:email "bob@example.com" ;
+  ex:status "unknown" .
+
+ex:carol ex:label "Carol"@en , "Caroline"@en , "Carola"@de .
+`
+
+func newTestShapes(t *testing.T, turtle string) *Shapes {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(turtle), "text/turtle"))
+	shapes, err := NewShapes(g)
+	assert.NoError(t, err)
+	return shapes
+}
+
+// resultSummaries returns the focus nodes, paths and components of results
+func resultSummaries(results []ValidationResult) []string {
+	var summaries []string
+	for _, r := range results {
+		s := strings.TrimPrefix(r.Focus.RawValue(), "http://example.org/")
+		if r.Path != nil {
+			s += " " + strings.TrimPrefix(r.Path.RawValue(), "http://example.org/")
+		}
+		summaries = append(summaries, s+" "+strings.TrimSuffix(strings.TrimPrefix(r.Component.RawValue(), shNamespace), "ConstraintComponent"))
+	}
+	return summaries
+}
+
+func TestShapesValidate(t *testing.T) {
+	shapes := newTestShapes(t, testShapes)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(testShapesData+`
+ex:Employee <http://www.w3.org/2000/01/rdf-schema#subClassOf> ex:Person .
+`), "text/turtle"))
+
+	report := shapes.Validate(g)
+	assert.False(t, report.Conforms)
+	assert.ElementsMatch(t, []string{
+		"bob name MaxCount",
+		"bob name MinLength",
+		"bob age MinInclusive",
+		"bob knows Class",
+		"bob email Pattern",
+		"bob status In",
+		"carol label UniqueLang",
+		"carol label LanguageIn",
+	}, resultSummaries(report.Results))
+
+	for _, r := range report.Results {
+		assert.Equal(t, NewResource(shNamespace+"Violation"), r.Severity)
+		assert.NotEmpty(t, r.Message)
+		if r.Component.Equal(NewResource(shNamespace + "InConstraintComponent")) {
+			assert.Equal(t, NewLiteral("unknown"), r.Value)
+			e := r.Explain()
+			assert.Equal(t, shNamespace+"InConstraintComponent", e.Rule)
+			assert.Equal(t, NewResource("http://example.org/status"), e.Statement.Predicate)
+		}
+	}
+
+	// without the subclass, bob is not a person
+	g.Remove(g.One(nil, NewResource(rdfsNamespace+"subClassOf"), nil))
+	report = shapes.Validate(g)
+	assert.ElementsMatch(t, []string{"alice knows Class", "carol label UniqueLang", "carol label LanguageIn"}, resultSummaries(report.Results))
+}
+
+func TestShapesLogicalConstraints(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <http://example.org/> .
+
+ex:S sh:targetNode ex:a , ex:b , ex:c ;
+  sh:closed true ;
+  sh:ignoredProperties ( ex:q ) ;
+  sh:property [ sh:path ex:p ; sh:minCount 1 ] ;
+  sh:or ( ex:HasP ex:HasQ ) ;
+  sh:not [ sh:path ex:q ; sh:hasValue "forbidden" ] ;
+  sh:xone ( ex:HasP ex:HasQ ) .
+
+ex:HasP sh:path ex:p ; sh:minCount 1 .
+ex:HasQ sh:path ex:q ; sh:minCount 1 .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:p 1 .
+ex:b ex:p 1 ; ex:q "forbidden" ; ex:r 2 .
+`), "text/turtle"))
+
+	report := shapes.Validate(g)
+	assert.ElementsMatch(t, []string{
+		"b r Closed",
+		"b Not",
+		"b Xone",
+		"c p MinCount",
+		"c Or",
+		"c Xone",
+	}, resultSummaries(report.Results))
+}
+
+func TestShapesPaths(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <http://example.org/> .
+
+ex:S sh:targetNode ex:a ;
+  sh:property [ sh:path ( ex:p ex:q ) ; sh:minCount 2 ; sh:maxCount 2 ] ;
+  sh:property [ sh:path [ sh:inversePath ex:p ] ; sh:hasValue ex:z ] ;
+  sh:property [ sh:path [ sh:oneOrMorePath ex:next ] ; sh:minCount 3 ; sh:maxCount 3 ] ;
+  sh:property [ sh:path [ sh:zeroOrMorePath ex:next ] ; sh:hasValue ex:a ] ;
+  sh:property [ sh:path [ sh:alternativePath ( ex:p ex:next ) ] ; sh:minCount 3 ; sh:maxCount 3 ] .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:p ex:b , ex:c ; ex:next ex:n1 .
+ex:b ex:q ex:x .
+ex:c ex:q ex:y .
+ex:z ex:p ex:a .
+ex:n1 ex:next ex:n2 .
+ex:n2 ex:next ex:a .
+`), "text/turtle"))
+	report := shapes.Validate(g)
+	assert.True(t, report.Conforms, "%v", report.Results)
+}
+
+func TestShapesValueComparisons(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix ex: <http://example.org/> .
+
+ex:S sh:targetSubjectsOf ex:start ;
+  sh:property [ sh:path ex:start ; sh:lessThan ex:end ; sh:datatype xsd:date ] ;
+  sh:property [ sh:path ex:count ; sh:datatype xsd:byte ; sh:maxInclusive 10.5 ] ;
+  sh:property [ sh:path ex:alias ; sh:disjoint ex:name ] ;
+  sh:property [ sh:path ex:name ; sh:equals ex:label ] .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix ex: <http://example.org/> .
+ex:ok ex:start "2024-01-01"^^xsd:date ; ex:end "2024-02-01"^^xsd:date ; ex:count "10"^^xsd:byte .
+ex:ko ex:start "2024-03-01"^^xsd:date ; ex:end "2024-02-01"^^xsd:date ; ex:count "300"^^xsd:byte ;
+  ex:name "x" ; ex:alias "x" ; ex:label "y" .
+`), "text/turtle"))
+	report := shapes.Validate(g)
+	assert.ElementsMatch(t, []string{
+		"ko start LessThan",
+		"ko count Datatype",
+		"ko count MaxInclusive",
+		"ko alias Disjoint",
+		"ko name Equals",
+		"ko name Equals",
+	}, resultSummaries(report.Results))
+}
+
+func TestNewShapesErrors(t *testing.T) {
+	for _, shapes := range []string{
+		`<http://example.org/S> <http://www.w3.org/ns/shacl#targetNode> <http://example.org/a> ; <http://www.w3.org/ns/shacl#minCount> "one" .`,
+		`<http://example.org/S> <http://www.w3.org/ns/shacl#targetNode> <http://example.org/a> ; <http://www.w3.org/ns/shacl#pattern> "(" .`,
+		`<http://example.org/S> <http://www.w3.org/ns/shacl#targetNode> <http://example.org/a> ; <http://www.w3.org/ns/shacl#path> "p" .`,
+		`<http://example.org/S> <http://www.w3.org/ns/shacl#targetNode> <http://example.org/a> ; <http://www.w3.org/ns/shacl#in> <http://example.org/notalist> .`,
+	} {
+		g := NewGraph(testUri)
+		assert.NoError(t, g.Parse(strings.NewReader(shapes), "text/turtle"))
+		_, err := NewShapes(g)
+		assert.Error(t, err, shapes)
+	}
+}
+
+func TestShapesRecursive(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <http://example.org/> .
+ex:S sh:targetNode ex:a ;
+  sh:property [ sh:path ex:next ; sh:node ex:S ] .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:next ex:b . ex:b ex:next ex:a .
+`), "text/turtle"))
+	assert.True(t, shapes.Validate(g).Conforms)
+}
+
+func TestShapesWatch(t *testing.T) {
+	shapes := newTestShapes(t, testShapes)
+	d := NewDataset(testDatasetUri)
+	graph := NewResource("http://example.org/data")
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(testShapesData), "text/turtle"))
+	for triple := range g.Triples() {
+		d.AddQuad(triple.Subject, triple.Predicate, triple.Object, graph)
+	}
+
+	v := shapes.Watch(d, graph)
+	defer v.Cancel()
+	report := v.Report()
+	assert.ElementsMatch(t, []string{"alice knows Class", "carol label UniqueLang", "carol label LanguageIn"}, resultSummaries(report.Results))
+	// alice as a person, carol for her labels
+	assert.Equal(t, 2, v.evaluations)
+
+	ex := func(local string) Term { return NewResource("http://example.org/" + local) }
+	rdfType := NewResource(rdfNamespace + "type")
+
+	// bob becomes a person: bob is evaluated, and alice, who knows him
+	d.AddQuad(ex("bob"), rdfType, ex("Person"), graph)
+	report = v.Report()
+	assert.Equal(t, 4, v.evaluations)
+	assert.NotContains(t, resultSummaries(report.Results), "alice knows Class")
+	assert.Contains(t, resultSummaries(report.Results), "bob status In")
+	assert.Contains(t, resultSummaries(report.Results), "bob knows Class")
+
+	// carol becomes a person: carol and bob, who knows her, are re-evaluated
+	d.AddQuad(ex("carol"), rdfType, ex("Person"), graph)
+	report = v.Report()
+	assert.Equal(t, 6, v.evaluations)
+	assert.NotContains(t, resultSummaries(report.Results), "bob knows Class")
+	assert.Contains(t, resultSummaries(report.Results), "carol name MinCount")
+
+	// changes to statements nobody read do not re-evaluate anything
+	d.AddQuad(ex("dave"), ex("unrelated"), NewLiteral("x"), graph)
+	d.AddQuad(ex("alice"), rdfType, ex("Person"), nil)
+	v.Report()
+	assert.Equal(t, 6, v.evaluations)
+
+	// removing the labels removes carol from the targets of LabelShape
+	for _, q := range d.All(ex("carol"), ex("label"), nil, graph) {
+		d.Remove(q)
+	}
+	report = v.Report()
+	assert.NotContains(t, resultSummaries(report.Results), "carol label UniqueLang")
+
+	// fixing bob, within one report
+	for _, q := range d.All(ex("bob"), nil, nil, graph) {
+		if !q.Predicate.Equal(rdfType) && !q.Predicate.Equal(ex("knows")) {
+			d.Remove(q)
+		}
+	}
+	d.AddQuad(ex("bob"), ex("name"), NewLiteral("Bob"), graph)
+	d.AddQuad(ex("carol"), ex("name"), NewLiteral("Carol"), graph)
+	report = v.Report()
+	assert.True(t, report.Conforms, "%v", report.Results)
+
+	// the live report matches a full validation
+	d.AddQuad(ex("bob"), ex("age"), NewLiteralWithDatatype("200", NewResource(xsdNamespace+"integer")), graph)
+	full := NewGraph(testUri)
+	for _, q := range d.All(nil, nil, nil, graph) {
+		full.Add(q.ToTriple())
+	}
+	assert.Equal(t, shapes.Validate(full).Results, v.Report().Results)
+
+	// subclass changes re-evaluate everything
+	before := v.evaluations
+	d.AddQuad(ex("Robot"), NewResource(rdfsNamespace+"subClassOf"), ex("Person"), graph)
+	v.Report()
+	assert.Equal(t, before+3, v.evaluations)
+}
+
+func TestShapesExtract(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <http://example.org/> .
+ex:OrderShape a sh:NodeShape ;
+  sh:property [ sh:path ex:date ] ;
+  sh:property [ sh:path ex:customer ; sh:node ex:CustomerShape ] ;
+  sh:property [ sh:path ( ex:line ex:product ) ] .
+ex:CustomerShape a sh:NodeShape ;
+  sh:property [ sh:path ex:name ] ;
+  sh:property [ sh:path ex:referrer ; sh:node ex:CustomerShape ] .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:o1 ex:date "2024-01-01" ; ex:customer ex:c1 ; ex:line ex:l1 ; ex:note "internal" .
+ex:l1 ex:product ex:p1 ; ex:quantity 2 .
+ex:c1 ex:name "Alice" ; ex:email "alice@example.org" ; ex:referrer ex:c2 .
+ex:c2 ex:name "Bob" ; ex:referrer ex:c1 .
+`), "text/turtle"))
+
+	payload, err := shapes.Extract(g, NewResource("http://example.org/o1"), NewResource("http://example.org/OrderShape"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, payload.Len())
+	assert.NotNil(t, payload.One(NewResource("http://example.org/l1"), NewResource("http://example.org/product"), nil))
+	assert.NotNil(t, payload.One(NewResource("http://example.org/c2"), NewResource("http://example.org/name"), nil))
+	assert.Nil(t, payload.One(nil, NewResource("http://example.org/note"), nil))
+	assert.Nil(t, payload.One(nil, NewResource("http://example.org/email"), nil))
+	assert.Nil(t, payload.One(nil, NewResource("http://example.org/quantity"), nil))
+
+	_, err = shapes.Extract(g, NewResource("http://example.org/o1"), NewResource("http://example.org/Missing"))
+	assert.Error(t, err)
+}
+
+func TestShapesExtractOr(t *testing.T) {
+	shapes := newTestShapes(t, `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix ex: <http://example.org/> .
+ex:S a sh:NodeShape ;
+  sh:or ( [ sh:property [ sh:path ex:name ; sh:minCount 1 ] ] [ sh:property [ sh:path ex:label ; sh:minCount 1 ] ] ) .
+`)
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:label "A" ; ex:other "x" .
+`), "text/turtle"))
+	payload, err := shapes.Extract(g, NewResource("http://example.org/a"), NewResource("http://example.org/S"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, payload.Len())
+	assert.NotNil(t, payload.One(nil, NewResource("http://example.org/label"), nil))
+}
+
+func TestGraphExtract(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`@prefix ex: <http://example.org/> .
+ex:a ex:name "A" ; ex:knows ex:b ; ex:age 3 .
+ex:b ex:name "B" ; ex:knows ex:a ; ex:age 4 .
+ex:c ex:likes ex:a .
+`), "text/turtle"))
+	payload := g.Extract(NewResource("http://example.org/a"), PropertyTree{
+		"http://example.org/name":   nil,
+		"^http://example.org/likes": nil,
+		"http://example.org/knows": {
+			"http://example.org/name":  nil,
+			"http://example.org/knows": {"http://example.org/name": nil},
+		},
+	})
+	assert.Equal(t, 5, payload.Len())
+	assert.NotNil(t, payload.One(NewResource("http://example.org/c"), NewResource("http://example.org/likes"), nil))
+	assert.NotNil(t, payload.One(NewResource("http://example.org/b"), NewResource("http://example.org/knows"), nil))
+	assert.Nil(t, payload.One(nil, NewResource("http://example.org/age"), nil))
+}
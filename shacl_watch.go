@@ -0,0 +1,179 @@
+package rdf2go
+
+// LiveValidation keeps the validation report of a graph of a dataset up to
+// date, see Shapes.Watch
+type LiveValidation struct {
+	shapes  *Shapes
+	dataset *Dataset
+	graph   Term
+	sub     *Subscription
+
+	// pairs holds the results of the focus nodes of each shape, and deps the
+	// pairs that read each part of the graph, as recorded by shaclContext
+	pairs map[shapeFocus]*livePair
+	deps  map[string]map[shapeFocus]bool
+
+	// dirty lists the pairs to re-evaluate, candidates the nodes that may
+	// have become or ceased to be focus nodes, and rebuild whether
+	// everything must be re-evaluated
+	dirty      map[shapeFocus]bool
+	candidates map[string]Term
+	rebuild    bool
+
+	// evaluations counts the evaluations of pairs, for tests
+	evaluations int
+}
+
+type shapeFocus struct {
+	shape *shape
+	focus string
+}
+
+type livePair struct {
+	focus   Term
+	results []ValidationResult
+	reads   []string
+}
+
+// Watch validates a graph of a dataset (nil being the default graph) against
+// the shapes, and keeps the results up to date as the dataset changes. Each
+// change only invalidates the focus nodes whose evaluation read the changed
+// statement, and the nodes it may add to or remove from the targets; Report
+// re-evaluates them. Changing rdfs:subClassOf statements re-evaluates every
+// shape.
+//
+// Like subscriptions, a live validation must be canceled when it is no
+// longer needed.
+func (s *Shapes) Watch(d *Dataset, graph Term) *LiveValidation {
+	v := &LiveValidation{
+		shapes:     s,
+		dataset:    d,
+		graph:      graph,
+		candidates: make(map[string]Term),
+		rebuild:    true,
+	}
+	v.sub = d.Subscribe(nil, nil, nil, storedGraph(graph), v.changed)
+	return v
+}
+
+// Cancel stops tracking the changes of the dataset
+func (v *LiveValidation) Cancel() {
+	v.sub.Cancel()
+}
+
+// Report returns the current validation report, re-evaluating the focus
+// nodes affected by the changes made since the previous report
+func (v *LiveValidation) Report() *ValidationReport {
+	v.update()
+	var results []ValidationResult
+	for _, p := range v.pairs {
+		results = append(results, p.results...)
+	}
+	return newValidationReport(results)
+}
+
+func (v *LiveValidation) context(reads map[string]bool) *shaclContext {
+	return &shaclContext{
+		match: func(s, p, o Term, fn func(Term, Term, Term) bool) {
+			v.dataset.match(s, p, o, storedGraph(v.graph), func(q *Quad) bool {
+				return fn(q.Subject, q.Predicate, q.Object)
+			})
+		},
+		key:   v.dataset.iriPolicy.key,
+		reads: reads,
+	}
+}
+
+// changed records the pairs and candidate nodes affected by a change
+func (v *LiveValidation) changed(_ ChangeKind, q *Quad) {
+	if v.rebuild {
+		return
+	}
+	if q.Predicate.Equal(NewResource(rdfsNamespace + "subClassOf")) {
+		v.rebuild = true
+		return
+	}
+	key := v.dataset.iriPolicy.key
+	s, p, o := key(q.Subject), key(q.Predicate), key(q.Object)
+	for _, read := range []string{"o " + s + " " + p, "o " + s + " *", "i " + p + " " + o} {
+		for pair := range v.deps[read] {
+			v.dirty[pair] = true
+		}
+	}
+	v.candidates[s] = q.Subject
+	v.candidates[o] = q.Object
+}
+
+// update re-evaluates the pairs invalidated since the previous update
+func (v *LiveValidation) update() {
+	if v.rebuild {
+		v.pairs = make(map[shapeFocus]*livePair)
+		v.deps = make(map[string]map[shapeFocus]bool)
+		v.dirty = make(map[shapeFocus]bool)
+		clear(v.candidates)
+		c := v.context(nil)
+		for _, sh := range v.shapes.targeted {
+			for _, focus := range c.focusNodes(sh) {
+				pair := shapeFocus{sh, c.key(focus)}
+				v.pairs[pair] = &livePair{focus: focus}
+				v.dirty[pair] = true
+			}
+		}
+		v.rebuild = false
+	}
+
+	if len(v.candidates) > 0 {
+		c := v.context(nil)
+		for k, node := range v.candidates {
+			for _, sh := range v.shapes.targeted {
+				pair := shapeFocus{sh, k}
+				_, tracked := v.pairs[pair]
+				focus := c.isFocus(node, sh)
+				if focus && !tracked {
+					v.pairs[pair] = &livePair{focus: node}
+					v.dirty[pair] = true
+				} else if !focus && tracked {
+					v.forget(pair)
+					delete(v.pairs, pair)
+					delete(v.dirty, pair)
+				}
+			}
+		}
+		clear(v.candidates)
+	}
+
+	for pair := range v.dirty {
+		v.evaluate(pair, v.pairs[pair])
+	}
+	clear(v.dirty)
+}
+
+// evaluate validates a focus node against a shape, recording what it reads
+func (v *LiveValidation) evaluate(pair shapeFocus, p *livePair) {
+	v.forget(pair)
+	reads := make(map[string]bool)
+	p.results = v.context(reads).validate(p.focus, pair.shape)
+	for read := range reads {
+		p.reads = append(p.reads, read)
+		if v.deps[read] == nil {
+			v.deps[read] = make(map[shapeFocus]bool)
+		}
+		v.deps[read][pair] = true
+	}
+	v.evaluations++
+}
+
+// forget removes the recorded reads of a pair
+func (v *LiveValidation) forget(pair shapeFocus) {
+	p := v.pairs[pair]
+	if p == nil {
+		return
+	}
+	for _, read := range p.reads {
+		delete(v.deps[read], pair)
+		if len(v.deps[read]) == 0 {
+			delete(v.deps, read)
+		}
+	}
+	p.reads = nil
+}
@@ -0,0 +1,150 @@
+package rdf2go
+
+import (
+	"hash/fnv"
+	"runtime"
+	"sync"
+)
+
+// ShardedStore is an in-memory quad store that is safe for concurrent use.
+// Quads are distributed over a fixed number of shards by the hash of their
+// subject, and every shard has its own lock, so that writers working on
+// different subjects rarely wait for each other.
+//
+// Patterns with a bound subject are answered by a single shard, while other
+// patterns visit every shard in turn.
+type ShardedStore struct {
+	shards []*storeShard
+	uri    string
+}
+
+type storeShard struct {
+	sync.RWMutex
+	dataset *Dataset
+}
+
+// NewShardedStore creates a ShardedStore with the given number of shards. When
+// shards is not positive, one shard per available CPU is used.
+func NewShardedStore(uri string, shards int) *ShardedStore {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	s := &ShardedStore{
+		shards: make([]*storeShard, shards),
+		uri:    uri,
+	}
+	for i := range s.shards {
+		s.shards[i] = &storeShard{dataset: NewDataset(uri)}
+	}
+	return s
+}
+
+// URI returns the URI of the store
+func (s *ShardedStore) URI() string {
+	return s.uri
+}
+
+// Shards returns the number of shards of the store
+func (s *ShardedStore) Shards() int {
+	return len(s.shards)
+}
+
+// shard returns the shard holding the quads of a subject
+func (s *ShardedStore) shard(subject Term) *storeShard {
+	h := fnv.New32a()
+	h.Write([]byte(termKey(subject)))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Len returns the number of quads in the store
+func (s *ShardedStore) Len() int {
+	n := 0
+	for _, shard := range s.shards {
+		shard.RLock()
+		n += shard.dataset.Len()
+		shard.RUnlock()
+	}
+	return n
+}
+
+// Add adds a quad to the store
+func (s *ShardedStore) Add(q *Quad) {
+	shard := s.shard(q.Subject)
+	shard.Lock()
+	shard.dataset.Add(q)
+	shard.Unlock()
+}
+
+// AddQuad adds a quad made of individual S, P, O, G objects
+func (s *ShardedStore) AddQuad(subj Term, p Term, o Term, g Term) {
+	s.Add(NewQuad(subj, p, o, g))
+}
+
+// AddAll adds a batch of quads, taking the lock of each shard only once
+func (s *ShardedStore) AddAll(quads []*Quad) {
+	batches := make(map[*storeShard][]*Quad)
+	for _, q := range quads {
+		shard := s.shard(q.Subject)
+		batches[shard] = append(batches[shard], q)
+	}
+	for shard, batch := range batches {
+		shard.Lock()
+		for _, q := range batch {
+			shard.dataset.Add(q)
+		}
+		shard.Unlock()
+	}
+}
+
+// Remove removes a quad from the store
+func (s *ShardedStore) Remove(q *Quad) {
+	shard := s.shard(q.Subject)
+	shard.Lock()
+	shard.dataset.Remove(q)
+	shard.Unlock()
+}
+
+// One returns one quad based on a quad pattern of S, P, O, G objects
+func (s *ShardedStore) One(subj Term, p Term, o Term, g Term) *Quad {
+	for _, shard := range s.shardsFor(subj) {
+		shard.RLock()
+		q := shard.dataset.One(subj, p, o, g)
+		shard.RUnlock()
+		if q != nil {
+			return q
+		}
+	}
+	return nil
+}
+
+// All returns all quads that match a given pattern of S, P, O, G objects
+func (s *ShardedStore) All(subj Term, p Term, o Term, g Term) []*Quad {
+	var quads []*Quad
+	for _, shard := range s.shardsFor(subj) {
+		shard.RLock()
+		quads = append(quads, shard.dataset.All(subj, p, o, g)...)
+		shard.RUnlock()
+	}
+	return quads
+}
+
+// shardsFor returns the shards that may hold quads with the given subject
+func (s *ShardedStore) shardsFor(subject Term) []*storeShard {
+	if _, ok := termIndexKey(subject); ok && subject != nil {
+		return []*storeShard{s.shard(subject)}
+	}
+	return s.shards
+}
+
+// Dataset returns a Dataset holding a snapshot of the quads of the store
+func (s *ShardedStore) Dataset() *Dataset {
+	d := NewDataset(s.uri)
+	for _, shard := range s.shards {
+		shard.RLock()
+		for _, q := range shard.dataset.orderedQuads() {
+			d.Add(q)
+		}
+		shard.RUnlock()
+	}
+	return d
+}
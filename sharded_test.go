@@ -0,0 +1,52 @@
+package rdf2go
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedStoreConcurrentAdd(t *testing.T) {
+	s := NewShardedStore(testDatasetUri, 4)
+	assert.Equal(t, 4, s.Shards())
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.AddQuad(NewResource(fmt.Sprintf("http://example.org/s%d", i)), NewResource("http://example.org/p"), NewLiteral(fmt.Sprint(w)), nil)
+			}
+		}(w)
+	}
+	wg.Wait()
+	assert.Equal(t, 800, s.Len())
+
+	subj := NewResource("http://example.org/s7")
+	assert.Equal(t, 8, len(s.All(subj, nil, nil, nil)))
+	assert.Equal(t, 100, len(s.All(nil, nil, NewLiteral("3"), nil)))
+	assert.NotNil(t, s.One(nil, nil, NewLiteral("5"), nil))
+	assert.Nil(t, s.One(subj, nil, NewLiteral("9"), nil))
+
+	for _, q := range s.All(subj, nil, nil, nil) {
+		s.Remove(q)
+	}
+	assert.Equal(t, 792, s.Len())
+	assert.Equal(t, 792, s.Dataset().Len())
+}
+
+func TestShardedStoreAddAll(t *testing.T) {
+	s := NewShardedStore(testDatasetUri, 0)
+	assert.True(t, s.Shards() > 0)
+
+	g := NewResource("http://example.org/g")
+	s.AddAll([]*Quad{
+		NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), g),
+		NewQuad(NewResource("d"), NewResource("b"), NewResource("c"), g),
+	})
+	assert.Equal(t, 2, len(s.All(nil, NewResource("b"), nil, g)))
+	assert.Equal(t, 0, len(s.All(nil, nil, nil, nil)))
+}
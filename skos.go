@@ -0,0 +1,95 @@
+package rdf2go
+
+import (
+	"slices"
+	"strings"
+)
+
+// conceptLinks returns the terms linked to a node by p, or by its inverse, in
+// N-Triples order and without duplicates
+func (g *Graph) conceptLinks(node Term, p Term, inverse Term) []Term {
+	var terms []Term
+	for _, t := range g.All(node, p, nil) {
+		terms = append(terms, t.Object)
+	}
+	if inverse != nil {
+		for _, t := range g.All(nil, inverse, node) {
+			terms = append(terms, t.Subject)
+		}
+	}
+	slices.SortFunc(terms, func(a Term, b Term) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return slices.CompactFunc(terms, func(a Term, b Term) bool {
+		return a.Equal(b)
+	})
+}
+
+// conceptClosure returns the terms reachable from a node through p or its
+// inverse, nearest first, following cycles once
+func (g *Graph) conceptClosure(node Term, p Term, inverse Term) []Term {
+	seen := map[string]bool{node.String(): true}
+	var terms []Term
+	for next := []Term{node}; len(next) > 0; {
+		var level []Term
+		for _, n := range next {
+			for _, t := range g.conceptLinks(n, p, inverse) {
+				if !seen[t.String()] {
+					seen[t.String()] = true
+					level = append(level, t)
+				}
+			}
+		}
+		terms = append(terms, level...)
+		next = level
+	}
+	return terms
+}
+
+// BroaderConcepts returns the concepts broader than a SKOS concept, given by
+// skos:broader, or by skos:narrower the other way round
+func (g *Graph) BroaderConcepts(concept Term) []Term {
+	return g.conceptLinks(concept, SKOS.Get("broader"), SKOS.Get("narrower"))
+}
+
+// NarrowerConcepts returns the concepts narrower than a SKOS concept, given
+// by skos:narrower, or by skos:broader the other way round
+func (g *Graph) NarrowerConcepts(concept Term) []Term {
+	return g.conceptLinks(concept, SKOS.Get("narrower"), SKOS.Get("broader"))
+}
+
+// RelatedConcepts returns the concepts related to a SKOS concept,
+// skos:related being symmetric
+func (g *Graph) RelatedConcepts(concept Term) []Term {
+	return g.conceptLinks(concept, SKOS.Get("related"), SKOS.Get("related"))
+}
+
+// ConceptAncestors returns the concepts transitively broader than a SKOS
+// concept, the nearest first. Cycles of the hierarchy are followed once.
+func (g *Graph) ConceptAncestors(concept Term) []Term {
+	return g.conceptClosure(concept, SKOS.Get("broader"), SKOS.Get("narrower"))
+}
+
+// ConceptDescendants returns the concepts transitively narrower than a SKOS
+// concept, the nearest first. Cycles of the hierarchy are followed once.
+func (g *Graph) ConceptDescendants(concept Term) []Term {
+	return g.conceptClosure(concept, SKOS.Get("narrower"), SKOS.Get("broader"))
+}
+
+// TopConcepts returns the top concepts of a SKOS concept scheme, given by
+// skos:hasTopConcept, or by skos:topConceptOf the other way round. A nil
+// scheme matches every scheme.
+func (g *Graph) TopConcepts(scheme Term) []Term {
+	return g.conceptLinks(scheme, SKOS.Get("hasTopConcept"), SKOS.Get("topConceptOf"))
+}
+
+// PrefLabel returns the skos:prefLabel of a concept in a language, the label
+// without a language or in another one when missing, as Unmarshal picks
+// literals, and "" when the concept has no label
+func (g *Graph) PrefLabel(concept Term, language string) string {
+	labels := g.conceptLinks(concept, SKOS.Get("prefLabel"), nil)
+	if len(labels) == 0 {
+		return ""
+	}
+	return preferredObject(labels, language).RawValue()
+}
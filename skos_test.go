@@ -0,0 +1,47 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSKOS(t *testing.T) {
+	ex := NewNamespace("http://example.org/")
+	g := NewGraph(testUri)
+	g.AddTriple(ex.Get("scheme"), SKOS.Get("hasTopConcept"), ex.Get("animals"))
+	g.AddTriple(ex.Get("plants"), SKOS.Get("topConceptOf"), ex.Get("scheme"))
+	g.AddTriple(ex.Get("mammals"), SKOS.Get("broader"), ex.Get("animals"))
+	g.AddTriple(ex.Get("animals"), SKOS.Get("narrower"), ex.Get("birds"))
+	g.AddTriple(ex.Get("cats"), SKOS.Get("broader"), ex.Get("mammals"))
+	g.AddTriple(ex.Get("dogs"), SKOS.Get("broader"), ex.Get("mammals"))
+	g.AddTriple(ex.Get("mammals"), SKOS.Get("narrower"), ex.Get("cats"))
+	g.AddTriple(ex.Get("cats"), SKOS.Get("related"), ex.Get("mice"))
+
+	assert.Equal(t, []Term{ex.Get("animals"), ex.Get("plants")}, g.TopConcepts(ex.Get("scheme")))
+	assert.Equal(t, []Term{ex.Get("animals"), ex.Get("plants")}, g.TopConcepts(nil))
+	assert.Equal(t, []Term{ex.Get("mammals")}, g.BroaderConcepts(ex.Get("cats")))
+	assert.Equal(t, []Term{ex.Get("birds"), ex.Get("mammals")}, g.NarrowerConcepts(ex.Get("animals")))
+	assert.Equal(t, []Term{ex.Get("cats")}, g.RelatedConcepts(ex.Get("mice")))
+	assert.Equal(t, []Term{ex.Get("mammals"), ex.Get("animals")}, g.ConceptAncestors(ex.Get("cats")))
+	assert.Equal(t, []Term{ex.Get("birds"), ex.Get("mammals"), ex.Get("cats"), ex.Get("dogs")}, g.ConceptDescendants(ex.Get("animals")))
+	assert.Empty(t, g.ConceptAncestors(ex.Get("animals")))
+
+	g.AddTriple(ex.Get("animals"), SKOS.Get("broader"), ex.Get("cats"))
+	assert.Equal(t, []Term{ex.Get("mammals"), ex.Get("animals")}, g.ConceptAncestors(ex.Get("cats")))
+}
+
+func TestPrefLabel(t *testing.T) {
+	g := NewGraph(testUri)
+	concept := NewResource("http://example.org/cats")
+	assert.Equal(t, "", g.PrefLabel(concept, "en"))
+	g.AddTriple(concept, SKOS.Get("prefLabel"), NewLiteralWithLanguage("chats", "fr"))
+	assert.Equal(t, "chats", g.PrefLabel(concept, "en"))
+	g.AddTriple(concept, SKOS.Get("prefLabel"), NewLiteral("cats"))
+	assert.Equal(t, "cats", g.PrefLabel(concept, "en"))
+	g.AddTriple(concept, SKOS.Get("prefLabel"), NewLiteralWithLanguage("cats", "en-GB"))
+	g.AddTriple(concept, SKOS.Get("prefLabel"), NewLiteralWithLanguage("Katzen", "de"))
+	assert.Equal(t, "Katzen", g.PrefLabel(concept, "de"))
+	assert.Equal(t, "cats", g.PrefLabel(concept, "en"))
+	assert.Equal(t, "chats", g.PrefLabel(concept, "FR"))
+}
@@ -0,0 +1,276 @@
+package rdf2go
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// binaryMagic starts the binary snapshots of datasets, followed by the
+// version of the encoding
+const (
+	binaryMagic   = "RDF2GO"
+	binaryVersion = 1
+)
+
+// The kinds of the terms of the dictionary of a binary snapshot
+const (
+	binaryResource byte = iota + 1
+	binaryBlankNode
+	binaryLiteral
+	binaryQuotedTriple
+)
+
+// binaryWriter writes the varints and strings of a binary snapshot,
+// recording the first error
+type binaryWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (bw *binaryWriter) uvarint(v uint64) {
+	if bw.err == nil {
+		_, bw.err = bw.w.Write(bw.buf[:binary.PutUvarint(bw.buf[:], v)])
+	}
+}
+
+func (bw *binaryWriter) string(s string) {
+	bw.uvarint(uint64(len(s)))
+	if bw.err == nil {
+		_, bw.err = bw.w.WriteString(s)
+	}
+}
+
+func (bw *binaryWriter) byte(b byte) {
+	if bw.err == nil {
+		bw.err = bw.w.WriteByte(b)
+	}
+}
+
+// binaryDictionary assigns IDs to the terms of a snapshot, from 1, ID 0
+// being the default graph. The terms a term is made of, such as the
+// datatype of a literal, get smaller IDs than the term itself.
+type binaryDictionary struct {
+	ids   map[string]uint64
+	terms []Term
+}
+
+// id returns the ID of a term, adding it to the dictionary if needed
+func (dict *binaryDictionary) id(t Term) (uint64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	key, err := encodeStoredTerm(t)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := dict.ids[key]; ok {
+		return id, nil
+	}
+	switch term := t.(type) {
+	case *Literal:
+		if _, err := dict.id(term.Datatype); err != nil {
+			return 0, err
+		}
+	case *QuotedTriple:
+		for _, part := range []Term{term.Subject, term.Predicate, term.Object} {
+			if _, err := dict.id(part); err != nil {
+				return 0, err
+			}
+		}
+	}
+	dict.terms = append(dict.terms, t)
+	dict.ids[key] = uint64(len(dict.terms))
+	return uint64(len(dict.terms)), nil
+}
+
+// SaveBinary writes the quads of the dataset to w in a compact binary
+// encoding read back by LoadBinary: a dictionary holding each term once,
+// followed by the quads as the varint-encoded IDs of their terms. Reloading
+// a snapshot is much faster than parsing a document, as no syntax has to be
+// read and each term is decoded once. The provenance of the quads is not
+// saved.
+func (d *Dataset) SaveBinary(w io.Writer) error {
+	quads := d.orderedQuads()
+	dict := &binaryDictionary{ids: make(map[string]uint64)}
+	ids := make([]uint64, 0, 4*len(quads))
+	for _, q := range quads {
+		for _, t := range []Term{q.Subject, q.Predicate, q.Object, q.Graph} {
+			id, err := dict.id(t)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	bw := &binaryWriter{w: bufio.NewWriter(w)}
+	if _, err := bw.w.WriteString(binaryMagic); err != nil {
+		return err
+	}
+	bw.byte(binaryVersion)
+	bw.uvarint(uint64(len(dict.terms)))
+	for _, t := range dict.terms {
+		switch term := t.(type) {
+		case *Resource:
+			bw.byte(binaryResource)
+			bw.string(term.URI)
+		case *BlankNode:
+			bw.byte(binaryBlankNode)
+			bw.string(term.ID)
+		case *Literal:
+			bw.byte(binaryLiteral)
+			bw.string(term.Value)
+			bw.string(term.Language)
+			id, _ := dict.id(term.Datatype)
+			bw.uvarint(id)
+		case *QuotedTriple:
+			bw.byte(binaryQuotedTriple)
+			for _, part := range []Term{term.Subject, term.Predicate, term.Object} {
+				id, _ := dict.id(part)
+				bw.uvarint(id)
+			}
+		}
+	}
+	bw.uvarint(uint64(len(quads)))
+	for _, id := range ids {
+		bw.uvarint(id)
+	}
+	if bw.err != nil {
+		return bw.err
+	}
+	return bw.w.Flush()
+}
+
+// binaryReader reads the varints and strings of a binary snapshot
+type binaryReader struct {
+	r     *bufio.Reader
+	terms []Term
+}
+
+func (br *binaryReader) uvarint() (uint64, error) {
+	v, err := binary.ReadUvarint(br.r)
+	if errors.Is(err, io.EOF) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return v, err
+}
+
+func (br *binaryReader) string() (string, error) {
+	n, err := br.uvarint()
+	if err != nil {
+		return "", err
+	}
+	// the string is read progressively, so that a corrupted length does
+	// not allocate more than the snapshot holds
+	b, err := io.ReadAll(io.LimitReader(br.r, int64(n)))
+	if err != nil {
+		return "", err
+	}
+	if uint64(len(b)) < n {
+		return "", io.ErrUnexpectedEOF
+	}
+	return string(b), nil
+}
+
+// term reads the ID of a term of the dictionary, nil for ID 0
+func (br *binaryReader) term() (Term, error) {
+	id, err := br.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if id > uint64(len(br.terms)) {
+		return nil, fmt.Errorf("invalid binary snapshot: unknown term %d", id)
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	return br.terms[id-1], nil
+}
+
+// readTerm reads a term of the dictionary
+func (br *binaryReader) readTerm() (Term, error) {
+	kind, err := br.r.ReadByte()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case binaryResource:
+		uri, err := br.string()
+		return NewResource(uri), err
+	case binaryBlankNode:
+		id, err := br.string()
+		return NewBlankNode(id), err
+	case binaryLiteral:
+		value, err := br.string()
+		if err != nil {
+			return nil, err
+		}
+		language, err := br.string()
+		if err != nil {
+			return nil, err
+		}
+		datatype, err := br.term()
+		return &Literal{Value: value, Language: language, Datatype: datatype}, err
+	case binaryQuotedTriple:
+		var parts [3]Term
+		for i := range parts {
+			if parts[i], err = br.term(); err != nil {
+				return nil, err
+			}
+			if parts[i] == nil {
+				return nil, errors.New("invalid binary snapshot: quoted triple without a term")
+			}
+		}
+		return NewQuotedTriple(parts[0], parts[1], parts[2]), nil
+	}
+	return nil, fmt.Errorf("invalid binary snapshot: unknown term kind %d", kind)
+}
+
+// LoadBinary adds the quads of a snapshot written by SaveBinary to the
+// dataset. The quads share the terms of the dictionary of the snapshot, so
+// that equal terms are held in memory once.
+func (d *Dataset) LoadBinary(r io.Reader) error {
+	br := &binaryReader{r: bufio.NewReader(r)}
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(br.r, header); err != nil || string(header[:len(binaryMagic)]) != binaryMagic {
+		return errors.New("invalid binary snapshot: missing header")
+	}
+	if header[len(binaryMagic)] != binaryVersion {
+		return fmt.Errorf("unsupported binary snapshot version %d", header[len(binaryMagic)])
+	}
+
+	n, err := br.uvarint()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < n; i++ {
+		t, err := br.readTerm()
+		if err != nil {
+			return err
+		}
+		br.terms = append(br.terms, t)
+	}
+
+	if n, err = br.uvarint(); err != nil {
+		return err
+	}
+	for i := uint64(0); i < n; i++ {
+		var terms [4]Term
+		for j := range terms {
+			if terms[j], err = br.term(); err != nil {
+				return err
+			}
+		}
+		if terms[0] == nil || terms[1] == nil || terms[2] == nil {
+			return errors.New("invalid binary snapshot: quad without a term")
+		}
+		d.Add(NewQuad(terms[0], terms[1], terms[2], terms[3]))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package rdf2go
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetBinaryRoundtrip(t *testing.T) {
+	alice := NewResource("http://example.org/alice")
+	name := NewResource("http://xmlns.com/foaf/0.1/name")
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(alice, name, NewLiteralWithLanguage("Alice", "en"))
+	d.AddTriple(alice, NewResource("http://xmlns.com/foaf/0.1/age"), NewLiteralWithDatatype("42", NewResource("http://www.w3.org/2001/XMLSchema#integer")))
+	d.AddQuad(NewBlankNode("b1"), name, NewLiteral("line\nbreak"), NewResource("http://example.org/graph"))
+	d.AddQuad(NewQuotedTriple(alice, name, NewLiteral("Alice")), NewResource("http://example.org/source"), NewBlankNode("b1"), NewBlankNode("g"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, d.SaveBinary(&buf))
+	loaded := NewDataset(testDatasetUri)
+	assert.NoError(t, loaded.LoadBinary(&buf))
+	c, err := Diff(d, loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 4, loaded.Len())
+	assert.Equal(t, 1, loaded.GetGraph(NewResource("http://example.org/graph")).Len())
+
+	// equal terms are decoded once
+	quads := loaded.All(alice, nil, nil, nil)
+	assert.Len(t, quads, 2)
+	assert.Same(t, quads[0].Subject, quads[1].Subject)
+}
+
+func TestDatasetLoadBinaryInvalid(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("o"))
+	var buf bytes.Buffer
+	assert.NoError(t, d.SaveBinary(&buf))
+	data := buf.Bytes()
+
+	assert.Error(t, NewDataset("").LoadBinary(bytes.NewReader([]byte("<a> <b> <c> ."))))
+	assert.Error(t, NewDataset("").LoadBinary(bytes.NewReader(append([]byte(binaryMagic), 9))))
+	for i := len(binaryMagic) + 1; i < len(data); i++ {
+		assert.Error(t, NewDataset("").LoadBinary(bytes.NewReader(data[:i])), i)
+	}
+	corrupted := bytes.Clone(data)
+	corrupted[len(corrupted)-1] = 9
+	assert.Error(t, NewDataset("").LoadBinary(bytes.NewReader(corrupted)))
+}
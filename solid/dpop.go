@@ -0,0 +1,106 @@
+package solid
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// jwk is the JSON Web Key of the public key of the proofs
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// proofHeader is the JOSE header of a DPoP proof
+type proofHeader struct {
+	Typ string `json:"typ"`
+	Alg string `json:"alg"`
+	JWK jwk    `json:"jwk"`
+}
+
+// proofClaims are the claims of a DPoP proof (RFC 9449, section 4.2)
+type proofClaims struct {
+	JTI   string `json:"jti"`
+	HTM   string `json:"htm"`
+	HTU   string `json:"htu"`
+	IAT   int64  `json:"iat"`
+	ATH   string `json:"ath,omitempty"`
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// publicJWK returns the JSON Web Key of the public part of a P-256 key
+func publicJWK(key *ecdsa.PrivateKey) (jwk, error) {
+	pub, err := key.PublicKey.ECDH()
+	if err != nil {
+		return jwk{}, err
+	}
+	// the uncompressed point is 0x04 followed by X and Y
+	point := pub.Bytes()[1:]
+	size := len(point) / 2
+	return jwk{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(point[:size]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[size:]),
+	}, nil
+}
+
+// proofTarget returns the htu claim of a request URI, which leaves out its
+// query and fragment
+func proofTarget(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery, u.Fragment, u.RawFragment = "", "", ""
+	return u.String(), nil
+}
+
+// signProof returns a DPoP proof for a request, bound to an access token
+// and a server nonce when they are not empty
+func signProof(key *ecdsa.PrivateKey, method string, uri string, token string, nonce string, now time.Time) (string, error) {
+	pub, err := publicJWK(key)
+	if err != nil {
+		return "", err
+	}
+	htu, err := proofTarget(uri)
+	if err != nil {
+		return "", err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	claims := proofClaims{JTI: hex.EncodeToString(id), HTM: method, HTU: htu, IAT: now.Unix(), Nonce: nonce}
+	if len(token) > 0 {
+		hash := sha256.Sum256([]byte(token))
+		claims.ATH = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+	header, err := json.Marshal(proofHeader{Typ: "dpop+jwt", Alg: "ES256", JWK: pub})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// ES256 signatures are the concatenation of R and S, 32 bytes each
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,225 @@
+// Package solid authenticates the requests of rdf2go to the resources of
+// Solid pods, after Solid-OIDC (https://solidproject.org/TR/oidc).
+//
+// A Session holds an access token bound to a key with DPoP (RFC 9449): each
+// request carries the token along with a proof, signed by the key, of the
+// method and URL it was issued for. Sessions obtain their tokens with the
+// client credentials grant of the identity provider, which suits the
+// statically registered clients of servers and scripts, or use a token
+// obtained elsewhere, such as by an interactive login, with SetToken.
+//
+// Session.Authorize is a rdf2go.RequestHook:
+//
+//	session, err := solid.NewSession(issuer, clientID, clientSecret)
+//	g := rdf2go.NewGraphWithOptions("", rdf2go.WithRequestHook(session.Authorize))
+//	err = g.LoadURI("https://pod.example/private/profile")
+package solid
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryMargin is the time before their expiry at which tokens are renewed
+const expiryMargin = 30 * time.Second
+
+// Session authenticates requests with a DPoP-bound access token. It is safe
+// for concurrent use.
+type Session struct {
+	// Issuer is the URL of the OpenID provider issuing the tokens
+	Issuer string
+	// ClientID and ClientSecret are the credentials of the client, sent
+	// with HTTP basic authentication to the token endpoint
+	ClientID     string
+	ClientSecret string
+	// HTTPClient sends the requests to the provider, http.DefaultClient
+	// being used when nil
+	HTTPClient *http.Client
+
+	key *ecdsa.PrivateKey
+
+	mu            sync.Mutex
+	tokenEndpoint string
+	nonce         string
+	token         string
+	expiry        time.Time
+}
+
+// NewSession creates a session for a client of an OpenID provider, with a
+// new P-256 key signing its DPoP proofs
+func NewSession(issuer string, clientID string, clientSecret string) (*Session, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Issuer: issuer, ClientID: clientID, ClientSecret: clientSecret, key: key}, nil
+}
+
+// NewSessionWithKey creates a session signing its DPoP proofs with key, a
+// P-256 key to which tokens set with SetToken are bound
+func NewSessionWithKey(key *ecdsa.PrivateKey) *Session {
+	return &Session{key: key}
+}
+
+// SetToken makes the session use an access token obtained elsewhere and
+// bound to its key, until expiry, the zero time meaning that the token does
+// not expire
+func (s *Session) SetToken(token string, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token, s.expiry = token, expiry
+}
+
+// Token returns the access token of the session, requesting a new one from
+// the provider when it is missing or about to expire
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.token) > 0 && (s.expiry.IsZero() || time.Now().Add(expiryMargin).Before(s.expiry)) {
+		return s.token, nil
+	}
+	if len(s.Issuer) == 0 {
+		return "", errors.New("the session has no valid token and no issuer to request one from")
+	}
+	if err := s.requestToken(ctx); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Authorize adds the access token of the session and a DPoP proof to a
+// request. It is a rdf2go.RequestHook.
+func (s *Session) Authorize(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	proof, err := s.Proof(req.Method, req.URL.String(), token)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "DPoP "+token)
+	req.Header.Set("DPoP", proof)
+	return nil
+}
+
+// Proof returns a DPoP proof for a request to uri with method, bound to an
+// access token when it is not empty
+func (s *Session) Proof(method string, uri string, token string) (string, error) {
+	return signProof(s.key, method, uri, token, "", time.Now())
+}
+
+func (s *Session) client() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// discover reads the token endpoint from the configuration of the provider
+func (s *Session) discover(ctx context.Context) (string, error) {
+	if len(s.tokenEndpoint) > 0 {
+		return s.tokenEndpoint, nil
+	}
+	target := strings.TrimSuffix(s.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Could not fetch the configuration of %s - HTTP %d", s.Issuer, resp.StatusCode)
+	}
+	var config struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&config); err != nil {
+		return "", fmt.Errorf("invalid configuration of %s: %s", s.Issuer, err)
+	}
+	if len(config.TokenEndpoint) == 0 {
+		return "", fmt.Errorf("the configuration of %s has no token endpoint", s.Issuer)
+	}
+	s.tokenEndpoint = config.TokenEndpoint
+	return s.tokenEndpoint, nil
+}
+
+// tokenResponse is the response of the token endpoint, successful or not
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Error       string `json:"error"`
+}
+
+// requestToken requests an access token with the client credentials grant,
+// sending the proof again with the nonce the provider asks for, if any
+func (s *Session) requestToken(ctx context.Context) error {
+	endpoint, err := s.discover(ctx)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := s.postToken(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		if nonce := resp.Header.Get("DPoP-Nonce"); len(nonce) > 0 {
+			s.nonce = nonce
+		}
+		var body tokenResponse
+		err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusBadRequest && body.Error == "use_dpop_nonce" && attempt == 0 {
+			continue
+		}
+		if resp.StatusCode != 200 {
+			if len(body.Error) > 0 {
+				return fmt.Errorf("Could not get a token from %s - %s", s.Issuer, body.Error)
+			}
+			return fmt.Errorf("Could not get a token from %s - HTTP %d", s.Issuer, resp.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid token response from %s: %s", s.Issuer, err)
+		}
+		if !strings.EqualFold(body.TokenType, "DPoP") {
+			return fmt.Errorf("%s issued a %s token instead of a DPoP-bound one", s.Issuer, body.TokenType)
+		}
+		s.token = body.AccessToken
+		s.expiry = time.Time{}
+		if body.ExpiresIn > 0 {
+			s.expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+		}
+		return nil
+	}
+}
+
+func (s *Session) postToken(ctx context.Context, endpoint string) (*http.Response, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	proof, err := signProof(s.key, http.MethodPost, endpoint, "", s.nonce, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("DPoP", proof)
+	req.SetBasicAuth(url.QueryEscape(s.ClientID), url.QueryEscape(s.ClientSecret))
+	return s.client().Do(req)
+}
@@ -0,0 +1,130 @@
+package solid
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deiu/rdf2go"
+	"github.com/stretchr/testify/assert"
+)
+
+// verifyProof checks the signature of a DPoP proof and returns its claims
+func verifyProof(t *testing.T, proof string) (proofClaims, string) {
+	parts := strings.Split(proof, ".")
+	if !assert.Len(t, parts, 3) {
+		return proofClaims{}, ""
+	}
+	var header proofHeader
+	var claims proofClaims
+	data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	assert.NoError(t, json.Unmarshal(data, &header))
+	data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, json.Unmarshal(data, &claims))
+	assert.Equal(t, "dpop+jwt", header.Typ)
+	assert.Equal(t, "ES256", header.Alg)
+
+	x, _ := base64.RawURLEncoding.DecodeString(header.JWK.X)
+	y, _ := base64.RawURLEncoding.DecodeString(header.JWK.Y)
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if assert.Len(t, sig, 64) {
+		assert.True(t, ecdsa.Verify(pub, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])), "invalid signature")
+	}
+	return claims, header.JWK.X
+}
+
+func TestSession(t *testing.T) {
+	var issuer *httptest.Server
+	tokens := 0
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write([]byte(`{"issuer": "` + issuer.URL + `", "token_endpoint": "` + issuer.URL + `/token"}`))
+		case "/token":
+			claims, _ := verifyProof(t, r.Header.Get("DPoP"))
+			assert.Equal(t, "POST", claims.HTM)
+			assert.Equal(t, issuer.URL+"/token", claims.HTU)
+			// the provider asks for a nonce first
+			if claims.Nonce != "n1" {
+				w.Header().Set("DPoP-Nonce", "n1")
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "use_dpop_nonce"}`))
+				return
+			}
+			id, secret, _ := r.BasicAuth()
+			assert.Equal(t, "app", id)
+			assert.Equal(t, "s3cr3t", secret)
+			assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+			tokens++
+			w.Write([]byte(`{"access_token": "token1", "token_type": "DPoP", "expires_in": 300}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer issuer.Close()
+
+	var pod *httptest.Server
+	pod = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "DPoP token1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims, _ := verifyProof(t, r.Header.Get("DPoP"))
+		assert.Equal(t, "GET", claims.HTM)
+		assert.Equal(t, pod.URL+"/private/profile", claims.HTU)
+		hash := sha256.Sum256([]byte("token1"))
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(hash[:]), claims.ATH)
+		w.Header().Set("Content-Type", "text/turtle")
+		w.Write([]byte("<#me> <http://xmlns.com/foaf/0.1/name> \"Alice\" ."))
+	}))
+	defer pod.Close()
+
+	session, err := NewSession(issuer.URL, "app", "s3cr3t")
+	assert.NoError(t, err)
+	g := rdf2go.NewGraphWithOptions("", rdf2go.WithRequestHook(session.Authorize))
+	assert.NoError(t, g.LoadURI(pod.URL+"/private/profile?version=2#me"))
+	assert.Equal(t, 1, g.Len())
+	// the token is reused until it expires
+	assert.NoError(t, rdf2go.NewGraphWithOptions("", rdf2go.WithRequestHook(session.Authorize)).LoadURI(pod.URL+"/private/profile"))
+	assert.Equal(t, 1, tokens)
+
+	session.SetToken("token1", time.Now().Add(time.Second))
+	_, err = session.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tokens)
+
+	// loads fail when the provider cannot be discovered
+	other, err := NewSession(issuer.URL+"/missing", "app", "s3cr3t")
+	assert.NoError(t, err)
+	assert.Error(t, rdf2go.NewGraphWithOptions("", rdf2go.WithRequestHook(other.Authorize)).LoadURI(pod.URL+"/private/profile"))
+}
+
+func TestSessionWithKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	session := NewSessionWithKey(key)
+	_, err = session.Token(context.Background())
+	assert.Error(t, err)
+
+	session.SetToken("external", time.Time{})
+	req := httptest.NewRequest(http.MethodPut, "https://pod.example/notes/a.ttl", nil)
+	assert.NoError(t, session.Authorize(req))
+	assert.Equal(t, "DPoP external", req.Header.Get("Authorization"))
+	claims, x := verifyProof(t, req.Header.Get("DPoP"))
+	assert.Equal(t, "PUT", claims.HTM)
+	assert.Equal(t, "https://pod.example/notes/a.ttl", claims.HTU)
+	assert.NotEmpty(t, claims.JTI)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))), x)
+}
@@ -0,0 +1,530 @@
+package rdf2go
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The formats of SPARQL query results read by ParseBindings and written by
+// Bindings.Serialize
+const (
+	SPARQLResultsJSON = "application/sparql-results+json"
+	SPARQLResultsXML  = "application/sparql-results+xml"
+	SPARQLResultsCSV  = "text/csv"
+	SPARQLResultsTSV  = "text/tab-separated-values"
+)
+
+// ParseBindings reads SPARQL query results, such as those returned by a
+// remote endpoint, in the JSON, XML, CSV or TSV format given by mime. The
+// results of ASK queries set Boolean. As CSV does not tell IRIs from
+// literals, CSV values that are absolute IRIs are read as IRIs, those
+// starting with _: as blank nodes, and the others as plain literals.
+func ParseBindings(reader io.Reader, mime string) (*Bindings, error) {
+	switch mime {
+	case SPARQLResultsJSON, "application/json":
+		return parseSPARQLJSON(reader)
+	case SPARQLResultsXML, "application/xml":
+		return parseSPARQLXML(reader)
+	case SPARQLResultsCSV:
+		return parseSPARQLCSV(reader)
+	case SPARQLResultsTSV:
+		return parseSPARQLTSV(reader)
+	}
+	return nil, fmt.Errorf("unsupported SPARQL results format %s", mime)
+}
+
+// Serialize writes the solutions in the SPARQL query results format given
+// by mime, JSON, XML, CSV or TSV, or the boolean of ASK results, which CSV
+// and TSV cannot hold. CSV keeps the values of the terms only.
+func (b *Bindings) Serialize(w io.Writer, mime string) error {
+	switch mime {
+	case SPARQLResultsJSON, "application/json":
+		return b.serializeJSON(w)
+	case SPARQLResultsXML, "application/xml":
+		return b.serializeXML(w)
+	case SPARQLResultsCSV, SPARQLResultsTSV:
+		if b.Boolean != nil {
+			return fmt.Errorf("the boolean of ASK results cannot be serialized to %s", mime)
+		}
+		if mime == SPARQLResultsCSV {
+			return b.serializeCSV(w)
+		}
+		return b.serializeTSV(w)
+	}
+	return fmt.Errorf("unsupported SPARQL results format %s", mime)
+}
+
+// sparqlJSON is the layout of SPARQL 1.1 Query Results JSON documents
+type sparqlJSON struct {
+	Head struct {
+		Vars []string `json:"vars"`
+	} `json:"head"`
+	Boolean *bool `json:"boolean,omitempty"`
+	Results *struct {
+		Bindings []map[string]*sparqlJSONTerm `json:"bindings"`
+	} `json:"results,omitempty"`
+}
+
+// sparqlJSONTerm is a term of SPARQL JSON results, the value of triples
+// being an object with their subject, predicate and object
+type sparqlJSONTerm struct {
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Lang     string          `json:"xml:lang,omitempty"`
+	Datatype string          `json:"datatype,omitempty"`
+}
+
+type sparqlJSONTriple struct {
+	Subject   *sparqlJSONTerm `json:"subject"`
+	Predicate *sparqlJSONTerm `json:"predicate"`
+	Object    *sparqlJSONTerm `json:"object"`
+}
+
+func parseSPARQLJSON(reader io.Reader) (*Bindings, error) {
+	var doc sparqlJSON
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, err
+	}
+	b := &Bindings{Vars: doc.Head.Vars, Boolean: doc.Boolean}
+	if doc.Results == nil {
+		return b, nil
+	}
+	for _, result := range doc.Results.Bindings {
+		solution := make(Binding, len(result))
+		for name, value := range result {
+			t, err := value.term()
+			if err != nil {
+				return nil, fmt.Errorf("binding of %s: %s", name, err)
+			}
+			solution[name] = t
+		}
+		b.Solutions = append(b.Solutions, solution)
+	}
+	return b, nil
+}
+
+func (j *sparqlJSONTerm) term() (Term, error) {
+	if j == nil {
+		return nil, errors.New("missing term")
+	}
+	if j.Type == "triple" {
+		var triple sparqlJSONTriple
+		if err := json.Unmarshal(j.Value, &triple); err != nil {
+			return nil, err
+		}
+		var parts [3]Term
+		for i, part := range []*sparqlJSONTerm{triple.Subject, triple.Predicate, triple.Object} {
+			t, err := part.term()
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = t
+		}
+		return NewQuotedTriple(parts[0], parts[1], parts[2]), nil
+	}
+	var value string
+	if err := json.Unmarshal(j.Value, &value); err != nil {
+		return nil, err
+	}
+	switch j.Type {
+	case "uri":
+		return NewResource(value), nil
+	case "bnode":
+		return NewBlankNode(value), nil
+	case "literal", "typed-literal":
+		return newResultLiteral(value, j.Lang, j.Datatype), nil
+	}
+	return nil, fmt.Errorf("unknown term type %q", j.Type)
+}
+
+// newResultLiteral returns a literal of query results
+func newResultLiteral(value string, lang string, datatype string) Term {
+	switch {
+	case len(lang) > 0:
+		return NewLiteralWithLanguage(value, lang)
+	case len(datatype) > 0:
+		return NewLiteralWithDatatype(value, NewResource(datatype))
+	}
+	return NewLiteral(value)
+}
+
+func newSPARQLJSONTerm(t Term) (*sparqlJSONTerm, error) {
+	j := &sparqlJSONTerm{}
+	var value any
+	switch t := t.(type) {
+	case *Resource:
+		j.Type, value = "uri", t.URI
+	case *BlankNode:
+		j.Type, value = "bnode", t.ID
+	case *Literal:
+		j.Type, value, j.Lang = "literal", t.Value, t.Language
+		if t.Datatype != nil {
+			j.Datatype = t.Datatype.RawValue()
+		}
+	case *QuotedTriple:
+		var parts [3]*sparqlJSONTerm
+		for i, part := range []Term{t.Subject, t.Predicate, t.Object} {
+			p, err := newSPARQLJSONTerm(part)
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = p
+		}
+		j.Type, value = "triple", sparqlJSONTriple{parts[0], parts[1], parts[2]}
+	default:
+		return nil, fmt.Errorf("cannot serialize term %v of type %T", t, t)
+	}
+	raw, err := json.Marshal(value)
+	j.Value = raw
+	return j, err
+}
+
+func (b *Bindings) serializeJSON(w io.Writer) error {
+	doc := sparqlJSON{Boolean: b.Boolean}
+	doc.Head.Vars = b.Vars
+	if doc.Head.Vars == nil {
+		doc.Head.Vars = []string{}
+	}
+	if b.Boolean == nil {
+		doc.Results = &struct {
+			Bindings []map[string]*sparqlJSONTerm `json:"bindings"`
+		}{Bindings: make([]map[string]*sparqlJSONTerm, 0, len(b.Solutions))}
+		for _, solution := range b.Solutions {
+			result := make(map[string]*sparqlJSONTerm, len(solution))
+			for name, t := range solution {
+				j, err := newSPARQLJSONTerm(t)
+				if err != nil {
+					return err
+				}
+				result[name] = j
+			}
+			doc.Results.Bindings = append(doc.Results.Bindings, result)
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// sparqlXML is the layout of SPARQL Query Results XML documents
+type sparqlXML struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/sparql-results# sparql"`
+	Head    struct {
+		Variables []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"variable"`
+	} `xml:"head"`
+	Boolean *bool `xml:"boolean,omitempty"`
+	Results *struct {
+		Results []struct {
+			Bindings []sparqlXMLBinding `xml:"binding"`
+		} `xml:"result"`
+	} `xml:"results,omitempty"`
+}
+
+type sparqlXMLBinding struct {
+	Name string `xml:"name,attr"`
+	sparqlXMLTerm
+}
+
+// sparqlXMLTerm is a term of SPARQL XML results, one of its fields being set
+type sparqlXMLTerm struct {
+	URI     *string           `xml:"uri,omitempty"`
+	BNode   *string           `xml:"bnode,omitempty"`
+	Literal *sparqlXMLLiteral `xml:"literal,omitempty"`
+	Triple  *sparqlXMLTriple  `xml:"triple,omitempty"`
+}
+
+type sparqlXMLLiteral struct {
+	Lang     string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Datatype string `xml:"datatype,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type sparqlXMLTriple struct {
+	Subject   sparqlXMLTerm `xml:"subject"`
+	Predicate sparqlXMLTerm `xml:"predicate"`
+	Object    sparqlXMLTerm `xml:"object"`
+}
+
+func parseSPARQLXML(reader io.Reader) (*Bindings, error) {
+	var doc sparqlXML
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, err
+	}
+	b := &Bindings{Boolean: doc.Boolean}
+	for _, v := range doc.Head.Variables {
+		b.Vars = append(b.Vars, v.Name)
+	}
+	if doc.Results == nil {
+		return b, nil
+	}
+	for _, result := range doc.Results.Results {
+		solution := make(Binding, len(result.Bindings))
+		for _, binding := range result.Bindings {
+			t, err := binding.term()
+			if err != nil {
+				return nil, fmt.Errorf("binding of %s: %s", binding.Name, err)
+			}
+			solution[binding.Name] = t
+		}
+		b.Solutions = append(b.Solutions, solution)
+	}
+	return b, nil
+}
+
+func (x *sparqlXMLTerm) term() (Term, error) {
+	switch {
+	case x.URI != nil:
+		return NewResource(*x.URI), nil
+	case x.BNode != nil:
+		return NewBlankNode(*x.BNode), nil
+	case x.Literal != nil:
+		return newResultLiteral(x.Literal.Value, x.Literal.Lang, x.Literal.Datatype), nil
+	case x.Triple != nil:
+		var parts [3]Term
+		for i, part := range []*sparqlXMLTerm{&x.Triple.Subject, &x.Triple.Predicate, &x.Triple.Object} {
+			t, err := part.term()
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = t
+		}
+		return NewQuotedTriple(parts[0], parts[1], parts[2]), nil
+	}
+	return nil, errors.New("missing term")
+}
+
+func newSPARQLXMLTerm(t Term) (sparqlXMLTerm, error) {
+	var x sparqlXMLTerm
+	switch t := t.(type) {
+	case *Resource:
+		x.URI = &t.URI
+	case *BlankNode:
+		x.BNode = &t.ID
+	case *Literal:
+		x.Literal = &sparqlXMLLiteral{Lang: t.Language, Value: t.Value}
+		if t.Datatype != nil {
+			x.Literal.Datatype = t.Datatype.RawValue()
+		}
+	case *QuotedTriple:
+		var parts [3]sparqlXMLTerm
+		for i, part := range []Term{t.Subject, t.Predicate, t.Object} {
+			p, err := newSPARQLXMLTerm(part)
+			if err != nil {
+				return x, err
+			}
+			parts[i] = p
+		}
+		x.Triple = &sparqlXMLTriple{parts[0], parts[1], parts[2]}
+	default:
+		return x, fmt.Errorf("cannot serialize term %v of type %T", t, t)
+	}
+	return x, nil
+}
+
+func (b *Bindings) serializeXML(w io.Writer) error {
+	doc := sparqlXML{Boolean: b.Boolean}
+	for _, name := range b.Vars {
+		doc.Head.Variables = append(doc.Head.Variables, struct {
+			Name string `xml:"name,attr"`
+		}{name})
+	}
+	if b.Boolean == nil {
+		doc.Results = &struct {
+			Results []struct {
+				Bindings []sparqlXMLBinding `xml:"binding"`
+			} `xml:"result"`
+		}{}
+		for _, solution := range b.Solutions {
+			var result struct {
+				Bindings []sparqlXMLBinding `xml:"binding"`
+			}
+			for _, name := range b.solutionVars(solution) {
+				x, err := newSPARQLXMLTerm(solution[name])
+				if err != nil {
+					return err
+				}
+				result.Bindings = append(result.Bindings, sparqlXMLBinding{name, x})
+			}
+			doc.Results.Results = append(doc.Results.Results, result)
+		}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// solutionVars returns the variables bound by a solution, those of Vars
+// first and in their order
+func (b *Bindings) solutionVars(solution Binding) []string {
+	var names, others []string
+	for _, name := range b.Vars {
+		if _, ok := solution[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(solution)) {
+		if !slices.Contains(b.Vars, name) {
+			others = append(others, name)
+		}
+	}
+	return append(names, others...)
+}
+
+func parseSPARQLCSV(reader io.Reader) (*Bindings, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header of CSV results")
+	}
+	b := &Bindings{Vars: records[0]}
+	for _, record := range records[1:] {
+		solution := make(Binding, len(record))
+		for i, value := range record {
+			if i >= len(b.Vars) || len(value) == 0 {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(value, "_:"):
+				solution[b.Vars[i]] = NewBlankNode(value[2:])
+			case isAbsoluteIRI(value) && !strings.ContainsAny(value, " <>\"{}|^`\\"):
+				solution[b.Vars[i]] = NewResource(value)
+			default:
+				solution[b.Vars[i]] = NewLiteral(value)
+			}
+		}
+		b.Solutions = append(b.Solutions, solution)
+	}
+	return b, nil
+}
+
+func (b *Bindings) serializeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	if err := writer.Write(b.Vars); err != nil {
+		return err
+	}
+	for _, solution := range b.Solutions {
+		record := make([]string, len(b.Vars))
+		for i, name := range b.Vars {
+			switch t := solution[name].(type) {
+			case nil:
+			case *BlankNode, *QuotedTriple:
+				record[i] = t.String()
+			default:
+				record[i] = t.RawValue()
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// tsvNumber matches the numbers and booleans that TSV results may write as
+// in Turtle, with the datatype they have
+var tsvNumber = []struct {
+	pattern  *regexp.Regexp
+	datatype string
+}{
+	{regexp.MustCompile(`^[+-]?[0-9]+$`), "integer"},
+	{regexp.MustCompile(`^[+-]?[0-9]*\.[0-9]+$`), "decimal"},
+	{regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)[eE][+-]?[0-9]+$`), "double"},
+	{regexp.MustCompile(`^(true|false)$`), "boolean"},
+}
+
+func parseSPARQLTSV(reader io.Reader) (*Bindings, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	b := &Bindings{}
+	header := true
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		fields := strings.Split(line, "\t")
+		if header {
+			for _, field := range fields {
+				b.Vars = append(b.Vars, strings.TrimLeft(field, "?$"))
+			}
+			header = false
+			continue
+		}
+		solution := make(Binding, len(fields))
+		for i, field := range fields {
+			if i >= len(b.Vars) || len(field) == 0 {
+				continue
+			}
+			t, err := decodeTSVTerm(field)
+			if err != nil {
+				return nil, fmt.Errorf("binding of %s: %s", b.Vars[i], err)
+			}
+			solution[b.Vars[i]] = t
+		}
+		b.Solutions = append(b.Solutions, solution)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if header {
+		return nil, errors.New("missing header of TSV results")
+	}
+	return b, nil
+}
+
+// decodeTSVTerm reads a term of TSV results, in N-Triples syntax or a
+// number or boolean written as in Turtle
+func decodeTSVTerm(s string) (Term, error) {
+	for _, number := range tsvNumber {
+		if number.pattern.MatchString(s) {
+			return NewLiteralWithDatatype(s, XSD.Get(number.datatype)), nil
+		}
+	}
+	return decodeTerm(s)
+}
+
+func (b *Bindings) serializeTSV(w io.Writer) error {
+	var out strings.Builder
+	for i, name := range b.Vars {
+		if i > 0 {
+			out.WriteByte('\t')
+		}
+		out.WriteString("?" + name)
+	}
+	out.WriteByte('\n')
+	for _, solution := range b.Solutions {
+		for i, name := range b.Vars {
+			if i > 0 {
+				out.WriteByte('\t')
+			}
+			if t, ok := solution[name]; ok {
+				out.WriteString(encodeTerm(t))
+			}
+		}
+		out.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
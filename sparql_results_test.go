@@ -0,0 +1,127 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBindings() *Bindings {
+	return &Bindings{
+		Vars: []string{"s", "name", "age"},
+		Solutions: []Binding{
+			{"s": NewResource("http://example.org/alice"), "name": NewLiteralWithLanguage("Alice", "en"), "age": NewLiteralWithDatatype("42", XSD.Get("integer"))},
+			{"s": NewBlankNode("b0"), "name": NewLiteral("tab\tand, \"quotes\"")},
+			{"s": NewQuotedTriple(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("o"))},
+		},
+	}
+}
+
+func TestBindingsRoundtrip(t *testing.T) {
+	for _, mime := range []string{SPARQLResultsJSON, SPARQLResultsXML, SPARQLResultsTSV} {
+		b := newTestBindings()
+		buf := new(bytes.Buffer)
+		assert.NoError(t, b.Serialize(buf, mime), mime)
+		parsed, err := ParseBindings(buf, mime)
+		assert.NoError(t, err, mime)
+		assert.Equal(t, b.Vars, parsed.Vars, mime)
+		assert.Equal(t, b.Solutions, parsed.Solutions, mime)
+		assert.Nil(t, parsed.Boolean, mime)
+	}
+}
+
+func TestBindingsCSV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, newTestBindings().Serialize(buf, SPARQLResultsCSV))
+	assert.Equal(t, "s,name,age\r\nhttp://example.org/alice,Alice,42\r\n_:b0,\"tab\tand, \"\"quotes\"\"\",\r\n"+
+		"\"<< <http://example.org/a> <http://example.org/p> \"\"o\"\" >>\",,\r\n", buf.String())
+	b, err := ParseBindings(strings.NewReader("s,name\r\nhttp://example.org/alice,Alice\r\n_:b0,\r\n"), SPARQLResultsCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, []Binding{
+		{"s": NewResource("http://example.org/alice"), "name": NewLiteral("Alice")},
+		{"s": NewBlankNode("b0")},
+	}, b.Solutions)
+}
+
+func TestParseBindingsJSON(t *testing.T) {
+	b, err := ParseBindings(strings.NewReader(`{
+  "head": {"vars": ["x", "y"], "link": ["http://example.org/about"]},
+  "results": {"bindings": [
+    {"x": {"type": "uri", "value": "http://example.org/a"}, "y": {"type": "typed-literal", "value": "1", "datatype": "http://www.w3.org/2001/XMLSchema#integer"}},
+    {"x": {"type": "literal", "value": "chat", "xml:lang": "fr"}}
+  ]}
+}`), SPARQLResultsJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, b.Vars)
+	assert.Equal(t, 2, b.Len())
+	assert.Equal(t, []Term{NewResource("http://example.org/a"), NewLiteralWithLanguage("chat", "fr")}, b.Terms("x"))
+	assert.Equal(t, []Term{NewLiteralWithDatatype("1", XSD.Get("integer"))}, b.Terms("y"))
+
+	_, err = ParseBindings(strings.NewReader(`{"head": {"vars": ["x"]}, "results": {"bindings": [{"x": {"type": "iri", "value": "a"}}]}}`), SPARQLResultsJSON)
+	assert.Error(t, err)
+	_, err = ParseBindings(strings.NewReader(`{}`), "text/plain")
+	assert.Error(t, err)
+}
+
+func TestParseBindingsXML(t *testing.T) {
+	b, err := ParseBindings(strings.NewReader(`<?xml version="1.0"?>
+<sparql xmlns="http://www.w3.org/2005/sparql-results#">
+  <head><variable name="x"/><variable name="y"/></head>
+  <results>
+    <result>
+      <binding name="x"><uri>http://example.org/a</uri></binding>
+      <binding name="y"><literal xml:lang="en">cat</literal></binding>
+    </result>
+    <result><binding name="y"><bnode>r1</bnode></binding></result>
+  </results>
+</sparql>`), SPARQLResultsXML)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, b.Vars)
+	assert.Equal(t, []Binding{
+		{"x": NewResource("http://example.org/a"), "y": NewLiteralWithLanguage("cat", "en")},
+		{"y": NewBlankNode("r1")},
+	}, b.Solutions)
+}
+
+func TestParseBindingsTSV(t *testing.T) {
+	b, err := ParseBindings(strings.NewReader("?x\t?n\n<http://example.org/a>\t1\n_:b\t-2.5\n\"a\\tb\"@en\ttrue\n\t1e3\n"), SPARQLResultsTSV)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "n"}, b.Vars)
+	assert.Equal(t, []Binding{
+		{"x": NewResource("http://example.org/a"), "n": NewLiteralWithDatatype("1", XSD.Get("integer"))},
+		{"x": NewBlankNode("b"), "n": NewLiteralWithDatatype("-2.5", XSD.Get("decimal"))},
+		{"x": NewLiteralWithLanguage("a\tb", "en"), "n": NewLiteralWithDatatype("true", XSD.Get("boolean"))},
+		{"n": NewLiteralWithDatatype("1e3", XSD.Get("double"))},
+	}, b.Solutions)
+	_, err = ParseBindings(strings.NewReader("?x\nnot a term\n"), SPARQLResultsTSV)
+	assert.Error(t, err)
+}
+
+func TestBindingsBoolean(t *testing.T) {
+	yes := true
+	for _, mime := range []string{SPARQLResultsJSON, SPARQLResultsXML} {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, (&Bindings{Boolean: &yes}).Serialize(buf, mime))
+		b, err := ParseBindings(buf, mime)
+		assert.NoError(t, err, mime)
+		if assert.NotNil(t, b.Boolean, mime) {
+			assert.True(t, *b.Boolean, mime)
+		}
+		assert.Empty(t, b.Solutions)
+	}
+	assert.Error(t, (&Bindings{Boolean: &yes}).Serialize(new(bytes.Buffer), SPARQLResultsCSV))
+}
+
+func TestQueryResults(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("http://example.org/alice"), FOAF.Get("name"), NewLiteral("Alice"))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Query(NewTriple(Var("s"), FOAF.Get("name"), Var("name"))).Serialize(buf, SPARQLResultsJSON))
+	assert.Contains(t, buf.String(), `"vars": [
+      "s",
+      "name"
+    ]`)
+	assert.Contains(t, buf.String(), `"value": "http://example.org/alice"`)
+}
@@ -0,0 +1,618 @@
+package rdf2go
+
+import (
+	"errors"
+)
+
+// updateOperation is a single operation of a SPARQL 1.1 Update request
+type updateOperation struct {
+	kind   string // INSERT DATA, DELETE DATA, MODIFY, CLEAR, DROP, CREATE, ADD, MOVE or COPY
+	insert []*Quad
+	delete []*Quad
+	where  []*Quad
+	target string // GRAPH, DEFAULT, NAMED or ALL for CLEAR and DROP
+	graph  Term
+	to     Term // destination graph of ADD, MOVE and COPY
+	silent bool
+}
+
+// Update applies a SPARQL 1.1 Update request to the dataset. The supported
+// operations are INSERT DATA, DELETE DATA, DELETE/INSERT ... WHERE (with an
+// optional WITH clause), DELETE WHERE, CLEAR, DROP, CREATE, ADD, MOVE and
+// COPY. WHERE clauses may contain basic graph patterns and GRAPH blocks.
+//
+// The request is parsed entirely before any operation is applied, so a
+// syntax error leaves the dataset untouched. When dryRun is true, the
+// operations are evaluated on a copy of the dataset and the returned change
+// set lists the quads that would have been added and removed.
+func (d *Dataset) Update(update string, dryRun ...bool) (*ChangeSet, error) {
+	ops, err := parseUpdate(update, d.uri)
+	if err != nil {
+		return nil, err
+	}
+	c := &ChangeSet{DryRun: len(dryRun) > 0 && dryRun[0]}
+	target := d
+	if c.DryRun {
+		target = d.clone()
+	}
+	tracker := newChangeTracker()
+	for _, op := range ops {
+		target.applyUpdate(op, tracker)
+	}
+	tracker.fill(c)
+	return c, nil
+}
+
+func (d *Dataset) applyUpdate(op *updateOperation, tracker *changeTracker) {
+	switch op.kind {
+	case "INSERT DATA":
+		for _, quad := range op.insert {
+			d.insertValue(quad, tracker)
+		}
+	case "DELETE DATA":
+		for _, quad := range op.delete {
+			d.deleteValue(quad, tracker)
+		}
+	case "MODIFY":
+		solutions := d.solve(op.where)
+		var deletes, inserts []*Quad
+		for _, solution := range solutions {
+			for _, template := range op.delete {
+				if quad := instantiate(template, solution, nil); quad != nil {
+					deletes = append(deletes, quad)
+				}
+			}
+			bnodes := make(map[string]Term)
+			for _, template := range op.insert {
+				if quad := instantiate(template, solution, bnodes); quad != nil {
+					inserts = append(inserts, quad)
+				}
+			}
+		}
+		for _, quad := range deletes {
+			d.deleteValue(quad, tracker)
+		}
+		for _, quad := range inserts {
+			d.insertValue(quad, tracker)
+		}
+	case "CLEAR", "DROP":
+		var quads []*Quad
+		switch op.target {
+		case "GRAPH":
+			quads = d.All(nil, nil, nil, op.graph)
+		case "DEFAULT":
+			quads = d.All(nil, nil, nil, DefaultGraph)
+		case "NAMED", "ALL":
+			for quad := range d.Quads() {
+				if op.target == "ALL" || quad.Graph != nil {
+					quads = append(quads, quad)
+				}
+			}
+		}
+		for _, quad := range quads {
+			d.Remove(quad)
+			tracker.remove(quad)
+		}
+	case "ADD", "MOVE", "COPY":
+		d.transferGraph(op.kind, op.graph, op.to, tracker)
+	}
+}
+
+// insertValue adds a quad unless an equal quad is already present
+func (d *Dataset) insertValue(quad *Quad, tracker *changeTracker) {
+	if d.One(quad.Subject, quad.Predicate, quad.Object, storedGraph(quad.Graph)) != nil {
+		return
+	}
+	d.Add(quad)
+	tracker.add(quad)
+}
+
+// deleteValue removes all quads equal to the given one
+func (d *Dataset) deleteValue(quad *Quad, tracker *changeTracker) {
+	for _, match := range d.All(quad.Subject, quad.Predicate, quad.Object, storedGraph(quad.Graph)) {
+		d.Remove(match)
+		tracker.remove(match)
+	}
+}
+
+// solve evaluates a basic graph pattern, returning one binding of variable
+// names to terms per solution
+func (d *Dataset) solve(patterns []*Quad) []map[string]Term {
+	solutions := []map[string]Term{{}}
+	for _, pattern := range patterns {
+		var next []map[string]Term
+		for _, solution := range solutions {
+			s := bindVariable(pattern.Subject, solution)
+			p := bindVariable(pattern.Predicate, solution)
+			o := bindVariable(pattern.Object, solution)
+			g := bindVariable(pattern.Graph, solution)
+			graphs := []Term{g}
+			if _, ok := g.(*variable); ok {
+				graphs = d.GetNamedGraphs()
+			}
+			for _, graph := range graphs {
+				for _, quad := range d.All(unboundAsNil(s), unboundAsNil(p), unboundAsNil(o), graph) {
+					extended, ok := extendBinding(solution, []Term{s, p, o, g}, []Term{quad.Subject, quad.Predicate, quad.Object, graph})
+					if ok {
+						next = append(next, extended)
+					}
+				}
+			}
+		}
+		solutions = next
+		if len(solutions) == 0 {
+			break
+		}
+	}
+	return solutions
+}
+
+// bindVariable replaces a bound variable by its value
+func bindVariable(t Term, solution map[string]Term) Term {
+	if v, ok := t.(*variable); ok {
+		if value, bound := solution[v.name]; bound {
+			return value
+		}
+	}
+	return t
+}
+
+func unboundAsNil(t Term) Term {
+	if _, ok := t.(*variable); ok {
+		return nil
+	}
+	return t
+}
+
+// extendBinding binds the variables of a pattern to the terms of a matching
+// statement, failing when a variable would be bound to two different terms
+func extendBinding(solution map[string]Term, pattern []Term, values []Term) (map[string]Term, bool) {
+	extended := make(map[string]Term, len(solution)+len(pattern))
+	for k, v := range solution {
+		extended[k] = v
+	}
+	for i, t := range pattern {
+		v, ok := t.(*variable)
+		if !ok {
+			continue
+		}
+		if bound, exists := extended[v.name]; exists {
+			if !bound.Equal(values[i]) {
+				return nil, false
+			}
+			continue
+		}
+		extended[v.name] = values[i]
+	}
+	return extended, true
+}
+
+// instantiate builds a quad from a template. Blank nodes are replaced by new
+// ones (consistently within bnodes) when bnodes is not nil. It returns nil
+// when the template refers to an unbound variable.
+func instantiate(template *Quad, solution map[string]Term, bnodes map[string]Term) *Quad {
+	terms := []Term{template.Subject, template.Predicate, template.Object, template.Graph}
+	for i, t := range terms {
+		switch t := t.(type) {
+		case *variable:
+			value, ok := solution[t.name]
+			if !ok {
+				return nil
+			}
+			terms[i] = value
+		case *BlankNode:
+			if bnodes != nil {
+				node, ok := bnodes[t.ID]
+				if !ok {
+					node = NewAnonNode()
+					bnodes[t.ID] = node
+				}
+				terms[i] = node
+			}
+		}
+	}
+	if _, ok := terms[0].(*Literal); ok {
+		return nil
+	}
+	if _, ok := terms[1].(*Resource); !ok {
+		return nil
+	}
+	return NewQuad(terms[0], terms[1], terms[2], terms[3])
+}
+
+// changeTracker records the net effect of a sequence of mutations
+type changeTracker struct {
+	added   map[string]*Quad
+	removed map[string]*Quad
+	order   []string
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{
+		added:   make(map[string]*Quad),
+		removed: make(map[string]*Quad),
+	}
+}
+
+func (t *changeTracker) add(quad *Quad) {
+	key := quad.String()
+	if _, ok := t.removed[key]; ok {
+		delete(t.removed, key)
+		return
+	}
+	t.added[key] = quad
+	t.order = append(t.order, key)
+}
+
+func (t *changeTracker) remove(quad *Quad) {
+	key := quad.String()
+	if _, ok := t.added[key]; ok {
+		delete(t.added, key)
+		return
+	}
+	t.removed[key] = quad
+	t.order = append(t.order, key)
+}
+
+// fill copies the recorded changes to a change set, in order of occurrence
+func (t *changeTracker) fill(c *ChangeSet) {
+	seen := make(map[string]bool)
+	for _, key := range t.order {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if quad, ok := t.added[key]; ok {
+			c.Added = append(c.Added, quad)
+		} else if quad, ok := t.removed[key]; ok {
+			c.Removed = append(c.Removed, quad)
+		}
+	}
+}
+
+// parseUpdate parses a SPARQL 1.1 Update request
+func parseUpdate(src string, base string) ([]*updateOperation, error) {
+	p, err := newSyntaxParser(src, base)
+	if err != nil {
+		return nil, err
+	}
+	var ops []*updateOperation
+	for {
+		if err := p.prologue(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokEOF {
+			return ops, nil
+		}
+		op, err := p.updateOperation()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+		if p.isPunct(";") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if p.tok.kind != tokEOF {
+			return nil, p.errorf("expected ';' but found %s", p.tok)
+		}
+		return ops, nil
+	}
+}
+
+// prologue parses the PREFIX and BASE declarations of SPARQL
+func (p *syntaxParser) prologue() error {
+	for {
+		if p.tok.isKeyword("PREFIX") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if err := p.prefixDecl(); err != nil {
+				return err
+			}
+		} else if p.tok.isKeyword("BASE") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if err := p.baseDecl(); err != nil {
+				return err
+			}
+		} else {
+			return nil
+		}
+	}
+}
+
+func (p *syntaxParser) updateOperation() (*updateOperation, error) {
+	keyword := p.tok
+	if keyword.kind != tokKeyword {
+		return nil, p.errorf("expected an update operation but found %s", p.tok)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case keyword.isKeyword("INSERT") && p.tok.isKeyword("DATA"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		quads, err := p.quadBlock(false, true, false)
+		if err != nil {
+			return nil, err
+		}
+		return &updateOperation{kind: "INSERT DATA", insert: quads}, nil
+	case keyword.isKeyword("DELETE") && p.tok.isKeyword("DATA"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		quads, err := p.quadBlock(false, false, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := noBlankNodes(quads); err != nil {
+			return nil, err
+		}
+		return &updateOperation{kind: "DELETE DATA", delete: quads}, nil
+	case keyword.isKeyword("DELETE") && p.tok.isKeyword("WHERE"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		quads, err := p.quadBlock(true, false, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := noBlankNodes(quads); err != nil {
+			return nil, err
+		}
+		return &updateOperation{kind: "MODIFY", delete: quads, where: quads}, nil
+	case keyword.isKeyword("WITH"):
+		graph, err := p.iriTerm()
+		if err != nil {
+			return nil, err
+		}
+		keyword = p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.modify(keyword, graph)
+	case keyword.isKeyword("DELETE"), keyword.isKeyword("INSERT"):
+		return p.modify(keyword, nil)
+	case keyword.isKeyword("CLEAR"), keyword.isKeyword("DROP"):
+		op := &updateOperation{kind: "CLEAR"}
+		if keyword.isKeyword("DROP") {
+			op.kind = "DROP"
+		}
+		if p.tok.isKeyword("SILENT") {
+			op.silent = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case p.tok.isKeyword("GRAPH"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			graph, err := p.iriTerm()
+			if err != nil {
+				return nil, err
+			}
+			op.target, op.graph = "GRAPH", graph
+			return op, nil
+		case p.tok.isKeyword("DEFAULT"), p.tok.isKeyword("NAMED"), p.tok.isKeyword("ALL"):
+			op.target = upperASCII(p.tok.value)
+			return op, p.advance()
+		}
+		return nil, p.errorf("expected GRAPH, DEFAULT, NAMED or ALL but found %s", p.tok)
+	case keyword.isKeyword("ADD"), keyword.isKeyword("MOVE"), keyword.isKeyword("COPY"):
+		op := &updateOperation{kind: upperASCII(keyword.value)}
+		if p.tok.isKeyword("SILENT") {
+			op.silent = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		from, err := p.graphOrDefault()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("TO"); err != nil {
+			return nil, err
+		}
+		to, err := p.graphOrDefault()
+		if err != nil {
+			return nil, err
+		}
+		op.graph, op.to = from, to
+		return op, nil
+	case keyword.isKeyword("CREATE"):
+		op := &updateOperation{kind: "CREATE"}
+		if p.tok.isKeyword("SILENT") {
+			op.silent = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expectKeyword("GRAPH"); err != nil {
+			return nil, err
+		}
+		graph, err := p.iriTerm()
+		if err != nil {
+			return nil, err
+		}
+		op.graph = graph
+		return op, nil
+	}
+	return nil, &syntaxError{line: keyword.line, column: keyword.column, msg: "unsupported update operation " + keyword.value}
+}
+
+// graphOrDefault parses the DEFAULT keyword, returning nil, or the IRI of a
+// graph, optionally preceded by the GRAPH keyword
+func (p *syntaxParser) graphOrDefault() (Term, error) {
+	if p.tok.isKeyword("DEFAULT") {
+		return nil, p.advance()
+	}
+	if p.tok.isKeyword("GRAPH") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return p.iriTerm()
+}
+
+// modify parses DELETE { } INSERT { } WHERE { }, starting after the first
+// DELETE or INSERT keyword. Quads without a graph are put in graph with.
+func (p *syntaxParser) modify(keyword token, with Term) (*updateOperation, error) {
+	op := &updateOperation{kind: "MODIFY"}
+	var err error
+	if keyword.isKeyword("DELETE") {
+		if op.delete, err = p.quadBlock(true, false, false); err != nil {
+			return nil, err
+		}
+		if err := noBlankNodes(op.delete); err != nil {
+			return nil, err
+		}
+		if p.tok.isKeyword("INSERT") {
+			keyword = p.tok
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	} else if !keyword.isKeyword("INSERT") {
+		return nil, &syntaxError{line: keyword.line, column: keyword.column, msg: "expected DELETE or INSERT but found " + keyword.value}
+	}
+	if keyword.isKeyword("INSERT") {
+		if op.insert, err = p.quadBlock(true, false, false); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.isKeyword("USING") {
+		return nil, p.errorf("USING clauses are not supported")
+	}
+	if err := p.expectKeyword("WHERE"); err != nil {
+		return nil, err
+	}
+	if op.where, err = p.quadBlock(true, false, true); err != nil {
+		return nil, err
+	}
+	if with != nil {
+		for _, quads := range [][]*Quad{op.insert, op.delete, op.where} {
+			for _, quad := range quads {
+				if quad.Graph == nil {
+					quad.Graph = with
+				}
+			}
+		}
+	}
+	return op, nil
+}
+
+// quadBlock parses { triples GRAPH g { triples } ... }
+func (p *syntaxParser) quadBlock(variables bool, freshBlankNodes bool, pattern bool) ([]*Quad, error) {
+	p.variables = variables
+	p.freshBlankNodes = freshBlankNodes
+	p.blankNodesAsVariables = pattern
+	p.bnodes = make(map[string]Term)
+	defer func() {
+		p.variables, p.freshBlankNodes, p.blankNodesAsVariables = false, false, false
+	}()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var quads []*Quad
+	var graph Term
+	emit := func(s Term, pr Term, o Term) {
+		quads = append(quads, NewQuad(s, pr, o, graph))
+	}
+	for !p.isPunct("}") {
+		if p.tok.isKeyword("GRAPH") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind == tokVariable && variables {
+				graph = &variable{name: p.tok.value}
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			} else {
+				var err error
+				if graph, err = p.iriTerm(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectPunct("{"); err != nil {
+				return nil, err
+			}
+			if err := p.triplesBlock(emit); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("}"); err != nil {
+				return nil, err
+			}
+			graph = nil
+			if p.isPunct(".") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if p.tok.kind == tokKeyword && !p.tok.is(tokKeyword, "a") && p.tok.value != "true" && p.tok.value != "false" {
+			return nil, p.errorf("%s is not supported in this context", p.tok.value)
+		}
+		if err := p.triplesBlock(emit); err != nil {
+			return nil, err
+		}
+	}
+	return quads, p.advance()
+}
+
+// triplesBlock parses '.' separated triples up to a closing '}' or a GRAPH keyword
+func (p *syntaxParser) triplesBlock(emit func(s Term, pr Term, o Term)) error {
+	for !p.isPunct("}") {
+		if p.tok.kind == tokEOF {
+			return p.errorf("unexpected end of input, expected '}'")
+		}
+		if p.tok.isKeyword("GRAPH") {
+			return nil
+		}
+		if p.isPunct(".") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.triples(emit); err != nil {
+			return err
+		}
+		if !p.isPunct(".") && !p.isPunct("}") {
+			return p.errorf("expected '.' or '}' but found %s", p.tok)
+		}
+	}
+	return nil
+}
+
+func noBlankNodes(quads []*Quad) error {
+	for _, quad := range quads {
+		for _, t := range []Term{quad.Subject, quad.Object} {
+			if _, ok := t.(*BlankNode); ok {
+				return errors.New("blank nodes are not allowed in DELETE clauses")
+			}
+		}
+	}
+	return nil
+}
+
+func upperASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'a' && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,192 @@
+package rdf2go
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetUpdateInsertDeleteData(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	c, err := d.Update(`PREFIX ex: <http://example.org/>
+		INSERT DATA {
+			ex:alice ex:name "Alice"@en ; ex:age 28 .
+			GRAPH ex:g1 { ex:bob ex:knows ex:alice , _:b1 }
+		}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(c.Added))
+	assert.Equal(t, 4, d.Len())
+
+	g1 := NewResource("http://example.org/g1")
+	assert.NotNil(t, d.One(NewResource("http://example.org/alice"), NewResource("http://example.org/age"), NewLiteralWithDatatype("28", NewResource(xsdNamespace+"integer")), nil))
+	assert.Equal(t, 2, len(d.All(NewResource("http://example.org/bob"), nil, nil, g1)))
+
+	// inserting existing data has no effect
+	c, err = d.Update(`INSERT DATA { <http://example.org/alice> <http://example.org/name> "Alice"@en }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+
+	c, err = d.Update(`DELETE DATA { GRAPH <http://example.org/g1> { <http://example.org/bob> <http://example.org/knows> <http://example.org/alice> } }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(c.Removed))
+	assert.Equal(t, 3, d.Len())
+
+	_, err = d.Update(`DELETE DATA { _:x <http://example.org/p> <http://example.org/o> }`)
+	assert.Error(t, err)
+	_, err = d.Update(`INSERT DATA { ?s <http://example.org/p> <http://example.org/o> }`)
+	assert.Error(t, err)
+}
+
+func TestDatasetUpdateModify(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	_, err := d.Update(`PREFIX ex: <http://example.org/>
+		INSERT DATA {
+			ex:alice ex:name "Alice" . ex:bob ex:name "Bob" .
+			GRAPH ex:g1 { ex:alice ex:status "old" . ex:bob ex:status "old" }
+		}`)
+	assert.NoError(t, err)
+
+	c, err := d.Update(`PREFIX ex: <http://example.org/>
+		WITH ex:g1
+		DELETE { ?p ex:status "old" }
+		INSERT { ?p ex:status "new" ; ex:tag [ ex:label ?n ] }
+		WHERE { ?p ex:status "old" . GRAPH ?g { ?p ex:status ?s } }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(c.Removed))
+	// ?n is unbound, so the label triples are skipped
+	assert.Equal(t, 4, len(c.Added))
+
+	g1 := NewResource("http://example.org/g1")
+	assert.Equal(t, 2, len(d.All(nil, NewResource("http://example.org/status"), NewLiteral("new"), g1)))
+	tags := d.All(nil, NewResource("http://example.org/tag"), nil, g1)
+	assert.Equal(t, 2, len(tags))
+	assert.False(t, tags[0].Object.Equal(tags[1].Object))
+
+	c, err = d.Update(`DELETE WHERE { ?s <http://example.org/name> ?o }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(c.Removed))
+	assert.Equal(t, 4, d.Len())
+}
+
+func TestDatasetUpdateJoin(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	_, err := d.Update(`PREFIX ex: <http://example.org/>
+		INSERT DATA {
+			ex:alice ex:knows ex:bob . ex:bob ex:name "Bob" .
+			ex:carol ex:knows ex:dave .
+		}`)
+	assert.NoError(t, err)
+
+	c, err := d.Update(`PREFIX ex: <http://example.org/>
+		INSERT { ?a ex:knowsName ?n } WHERE { ?a ex:knows ?b . ?b ex:name ?n }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(c.Added))
+	assert.NotNil(t, d.One(NewResource("http://example.org/alice"), NewResource("http://example.org/knowsName"), NewLiteral("Bob"), nil))
+}
+
+func TestDatasetUpdateClearDrop(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	_, err := d.Update(`INSERT DATA {
+		<a> <b> <c> .
+		GRAPH <http://example.org/g1> { <a> <b> <c> }
+		GRAPH <http://example.org/g2> { <a> <b> <c> }
+	}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Len())
+
+	_, err = d.Update(`CLEAR GRAPH <http://example.org/g1>`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Len())
+
+	_, err = d.Update(`DROP SILENT DEFAULT`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, d.Len())
+
+	c, err := d.Update(`CREATE GRAPH <http://example.org/g3> ; DROP ALL`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(c.Removed))
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestDatasetUpdateGraphTransfers(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	g1 := NewResource("http://example.org/g1")
+	g2 := NewResource("http://example.org/g2")
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"), g1)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("d"), g2)
+
+	_, err := d.Update(`ADD <http://example.org/g1> TO DEFAULT ; COPY GRAPH <http://example.org/g1> TO <http://example.org/g2>`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Len())
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("c"), nil))
+	assert.Nil(t, d.One(nil, nil, NewLiteral("d"), g2))
+
+	c, err := d.Update(`MOVE DEFAULT TO <http://example.org/g3>`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(c.Added))
+	assert.Equal(t, 1, len(c.Removed))
+	assert.Len(t, d.All(nil, nil, nil, NewResource("http://example.org/g3")), 1)
+
+	_, err = d.Update(`MOVE <http://example.org/g1>`)
+	assert.Error(t, err)
+}
+
+func TestDatasetUpdateDryRun(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+
+	c, err := d.Update(`DELETE { ?s ?p ?o } INSERT { ?s ?p "d" } WHERE { ?s ?p ?o }`, true)
+	assert.NoError(t, err)
+	assert.True(t, c.DryRun)
+	assert.Equal(t, 1, len(c.Added))
+	assert.Equal(t, 1, len(c.Removed))
+	assert.NotNil(t, d.One(nil, nil, NewLiteral("c"), nil))
+	assert.Equal(t, 1, d.Len())
+}
+
+func TestDatasetUpdateNetChanges(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	c, err := d.Update(`INSERT DATA { <a> <b> <c> } ; DELETE DATA { <a> <b> <c> }`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestDatasetUpdateErrors(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+
+	_, err := d.Update(`INSERT DATA { <a> <b> <d> } ; DELETE DATA { <a> <b> }`)
+	assert.Error(t, err)
+	// operations are only applied once the whole request has been parsed
+	assert.Equal(t, 1, d.Len())
+
+	_, err = d.Update(`DELETE { ?s ?p ?o } WHERE { ?s ?p ?o FILTER(?o) }`)
+	assert.Error(t, err)
+
+	_, err = d.Update(`LOAD <http://example.org/data.ttl>`)
+	assert.Error(t, err)
+
+	_, err = d.Update(`INSERT DATA { ex:a <b> <c> }`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+}
+
+func TestDatasetParseSparqlUpdate(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral("c"))
+	// updates are only applied by Update, never by Parse
+	assert.Error(t, d.Parse(strings.NewReader(`DROP ALL`), "application/sparql-update"))
+	assert.Equal(t, 1, d.Len())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/sparql-update")
+		w.Write([]byte("DROP ALL"))
+	}))
+	defer server.Close()
+	assert.Error(t, d.LoadURI(server.URL))
+	assert.Equal(t, 1, d.Len())
+}
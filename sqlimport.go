@@ -0,0 +1,194 @@
+package rdf2go
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	xsdNamespace  = "http://www.w3.org/2001/XMLSchema#"
+	rdfNamespace  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rdfsNamespace = "http://www.w3.org/2000/01/rdf-schema#"
+	owlNamespace  = "http://www.w3.org/2002/07/owl#"
+)
+
+// RowMapping describes how the rows returned by a SQL query are turned into
+// triples. Templates are IRIs containing {column} placeholders, which are
+// replaced by the (path escaped) value of that column in the current row.
+type RowMapping struct {
+	// Query is the SQL query to execute and Args its arguments
+	Query string
+	Args  []interface{}
+
+	// Subject is the IRI template used for the subject of every row, e.g.
+	// "http://example.org/person/{id}"
+	Subject string
+
+	// Class is an optional class IRI added as rdf:type of every subject
+	Class string
+
+	// Columns maps column names to the predicate of the generated triples
+	Columns map[string]ColumnMapping
+
+	// Graph is the graph receiving the triples (nil for the default graph)
+	Graph Term
+}
+
+// ColumnMapping describes the triples generated for a single column
+type ColumnMapping struct {
+	// Predicate is the IRI of the predicate
+	Predicate string
+
+	// Datatype is the datatype IRI of the literal. When empty, it is inferred
+	// from the column value: integers, floats, booleans and times are typed
+	// using XML Schema datatypes, strings are plain literals.
+	Datatype string
+
+	// Language is the language tag of string literals
+	Language string
+
+	// Reference is an optional IRI template. When set, the column produces a
+	// resource instead of a literal, e.g. "http://example.org/dept/{dept_id}"
+	Reference string
+}
+
+// ImportSQL runs the query of the mapping against db and adds the triples
+// generated for each row to the dataset. NULL values do not produce triples.
+// It returns the number of quads added.
+func (d *Dataset) ImportSQL(ctx context.Context, db *sql.DB, m *RowMapping) (int, error) {
+	if len(m.Subject) == 0 {
+		return 0, errors.New("row mapping needs a subject template")
+	}
+	rows, err := db.QueryContext(ctx, m.Query, m.Args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	before := d.Len()
+	rdfType := NewResource(rdfNamespace + "type")
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return d.Len() - before, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, name := range columns {
+			row[name] = values[i]
+		}
+
+		subjectIRI, ok := expandRowTemplate(m.Subject, row)
+		if !ok {
+			continue
+		}
+		subject := NewResource(subjectIRI)
+		if len(m.Class) > 0 {
+			d.AddQuad(subject, rdfType, NewResource(m.Class), m.Graph)
+		}
+		for column, cm := range m.Columns {
+			value, found := row[column]
+			if !found {
+				return d.Len() - before, fmt.Errorf("column %s is not returned by the query", column)
+			}
+			if value == nil {
+				continue
+			}
+			var object Term
+			if len(cm.Reference) > 0 {
+				iri, ok := expandRowTemplate(cm.Reference, row)
+				if !ok {
+					continue
+				}
+				object = NewResource(iri)
+			} else {
+				object = sqlLiteral(value, cm)
+			}
+			d.AddQuad(subject, NewResource(cm.Predicate), object, m.Graph)
+		}
+	}
+	return d.Len() - before, rows.Err()
+}
+
+// expandRowTemplate replaces the {column} placeholders of an IRI template.
+// It returns false when a referenced column is NULL.
+func expandRowTemplate(template string, row map[string]interface{}) (string, bool) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), true
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			b.WriteString(template)
+			return b.String(), true
+		}
+		b.WriteString(template[:start])
+		value := row[template[start+1:start+end]]
+		if value == nil {
+			return "", false
+		}
+		b.WriteString(url.PathEscape(sqlString(value)))
+		template = template[start+end+1:]
+	}
+}
+
+// sqlString returns the lexical form of a column value
+func sqlString(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+// sqlLiteral converts a column value to a literal
+func sqlLiteral(value interface{}, cm ColumnMapping) Term {
+	lexical := sqlString(value)
+	datatype := cm.Datatype
+	if len(datatype) == 0 {
+		switch value.(type) {
+		case int64:
+			datatype = xsdNamespace + "integer"
+		case float64:
+			datatype = xsdNamespace + "double"
+		case bool:
+			datatype = xsdNamespace + "boolean"
+		case time.Time:
+			datatype = xsdNamespace + "dateTime"
+		}
+	}
+	if len(datatype) > 0 {
+		return NewLiteralWithDatatype(lexical, NewResource(datatype))
+	}
+	if len(cm.Language) > 0 {
+		return NewLiteralWithLanguage(lexical, cm.Language)
+	}
+	return NewLiteral(lexical)
+}
@@ -0,0 +1,128 @@
+package rdf2go
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSQLDriver serves canned result sets, keyed by query
+type fakeSQLDriver struct{}
+
+type fakeSQLResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var fakeSQLResults = map[string]fakeSQLResult{
+	"SELECT id, name, age, dept FROM people": {
+		columns: []string{"id", "name", "age", "dept"},
+		rows: [][]driver.Value{
+			{int64(1), "Alice", int64(28), int64(10)},
+			{int64(2), "Bob Smith", nil, nil},
+		},
+	},
+}
+
+type fakeSQLConn struct{}
+type fakeSQLStmt struct{ query string }
+type fakeSQLRows struct {
+	result fakeSQLResult
+	pos    int
+}
+
+func (fakeSQLDriver) Open(name string) (driver.Conn, error) { return fakeSQLConn{}, nil }
+
+func (fakeSQLConn) Prepare(query string) (driver.Stmt, error) { return &fakeSQLStmt{query}, nil }
+func (fakeSQLConn) Close() error                              { return nil }
+func (fakeSQLConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	result, ok := fakeSQLResults[s.query]
+	if !ok {
+		return nil, errors.New("unknown query")
+	}
+	return &fakeSQLRows{result: result}, nil
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.result.columns }
+func (r *fakeSQLRows) Close() error      { return nil }
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func init() {
+	sql.Register("rdf2go-fake", fakeSQLDriver{})
+}
+
+func TestDatasetImportSQL(t *testing.T) {
+	db, err := sql.Open("rdf2go-fake", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	graph := NewResource("http://example.org/graphs/hr")
+	d := NewDataset(testDatasetUri)
+	n, err := d.ImportSQL(context.Background(), db, &RowMapping{
+		Query:   "SELECT id, name, age, dept FROM people",
+		Subject: "http://example.org/person/{id}",
+		Class:   "http://xmlns.com/foaf/0.1/Person",
+		Columns: map[string]ColumnMapping{
+			"name": {Predicate: "http://xmlns.com/foaf/0.1/name", Language: "en"},
+			"age":  {Predicate: "http://xmlns.com/foaf/0.1/age"},
+			"dept": {Predicate: "http://example.org/dept", Reference: "http://example.org/dept/{dept}"},
+		},
+		Graph: graph,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	alice := NewResource("http://example.org/person/1")
+	assert.NotNil(t, d.One(alice, NewResource("http://xmlns.com/foaf/0.1/age"), NewLiteralWithDatatype("28", NewResource(xsdNamespace+"integer")), graph))
+	assert.NotNil(t, d.One(alice, NewResource("http://example.org/dept"), NewResource("http://example.org/dept/10"), graph))
+	assert.NotNil(t, d.One(NewResource("http://example.org/person/2"), NewResource("http://xmlns.com/foaf/0.1/name"), NewLiteralWithLanguage("Bob Smith", "en"), graph))
+	assert.Nil(t, d.One(NewResource("http://example.org/person/2"), NewResource("http://xmlns.com/foaf/0.1/age"), nil, graph))
+}
+
+func TestDatasetImportSQLErrors(t *testing.T) {
+	db, err := sql.Open("rdf2go-fake", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	d := NewDataset(testDatasetUri)
+	_, err = d.ImportSQL(context.Background(), db, &RowMapping{Query: "SELECT id, name, age, dept FROM people"})
+	assert.Error(t, err)
+
+	_, err = d.ImportSQL(context.Background(), db, &RowMapping{Query: "SELECT 1", Subject: "http://example.org/{id}"})
+	assert.Error(t, err)
+
+	_, err = d.ImportSQL(context.Background(), db, &RowMapping{
+		Query:   "SELECT id, name, age, dept FROM people",
+		Subject: "http://example.org/person/{id}",
+		Columns: map[string]ColumnMapping{"missing": {Predicate: "http://example.org/p"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestExpandRowTemplate(t *testing.T) {
+	iri, ok := expandRowTemplate("http://example.org/{a}/{b}", map[string]interface{}{"a": "x y", "b": int64(3)})
+	assert.True(t, ok)
+	assert.Equal(t, "http://example.org/x%20y/3", iri)
+
+	_, ok = expandRowTemplate("http://example.org/{a}", map[string]interface{}{"a": nil})
+	assert.False(t, ok)
+}
@@ -0,0 +1,260 @@
+package rdf2go
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// sqliteSchema creates the tables of a SQLiteStore: terms holds the
+// N-Triples representation of each term once, and quads the IDs of the terms
+// of each quad, the default graph having ID 0. The primary key of quads and
+// its indexes cover the SPO, POS and OSP orders, and the graphs.
+var sqliteSchema = []string{
+	"CREATE TABLE IF NOT EXISTS terms (id INTEGER PRIMARY KEY, value TEXT NOT NULL UNIQUE)",
+	"CREATE TABLE IF NOT EXISTS quads (s INTEGER NOT NULL, p INTEGER NOT NULL, o INTEGER NOT NULL, g INTEGER NOT NULL, PRIMARY KEY (s, p, o, g)) WITHOUT ROWID",
+	"CREATE INDEX IF NOT EXISTS quads_posg ON quads (p, o, s, g)",
+	"CREATE INDEX IF NOT EXISTS quads_ospg ON quads (o, s, p, g)",
+	"CREATE INDEX IF NOT EXISTS quads_gspo ON quads (g, s, p, o)",
+}
+
+// sqlBatchSize is the number of quads read by a query of Match, between
+// which fn may modify the store
+const sqlBatchSize = 1000
+
+// SQLiteStore is a Store persisting quads to a SQLite database, which makes
+// a single file persistent dataset without a server. Terms are stored once
+// in a dictionary table, and quads as the IDs of their terms, indexed in the
+// SPO, POS and OSP orders. The store works with any SQLite driver for
+// database/sql, such as the pure Go modernc.org/sqlite, the database being
+// opened by the caller.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore creates the tables of a SQLiteStore in a database, unless
+// they exist, and returns the store
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Add adds a quad, unless an equal quad is already present
+func (s *SQLiteStore) Add(q *Quad) (bool, error) {
+	n, err := s.AddAll([]*Quad{q})
+	return n > 0, err
+}
+
+// AddAll adds quads in a single transaction, skipping those already
+// present, and returns the number of quads added
+func (s *SQLiteStore) AddAll(quads []*Quad) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	added := 0
+	for _, q := range quads {
+		var ids [4]int64
+		for i, t := range []Term{q.Subject, q.Predicate, q.Object, q.Graph} {
+			if ids[i], err = sqlTermID(tx, t); err != nil {
+				return 0, err
+			}
+		}
+		res, err := tx.Exec("INSERT OR IGNORE INTO quads (s, p, o, g) VALUES (?, ?, ?, ?)", ids[0], ids[1], ids[2], ids[3])
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		added += int(n)
+	}
+	return added, tx.Commit()
+}
+
+// sqlTermID returns the ID of a term, adding it to the dictionary if needed
+func sqlTermID(tx *sql.Tx, t Term) (int64, error) {
+	id, found, err := sqlLookupID(tx, t)
+	if err != nil || found {
+		return id, err
+	}
+	value, err := encodeStoredTerm(t)
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec("INSERT INTO terms (value) VALUES (?)", value)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// sqlQueryer is implemented by sql.DB and sql.Tx
+type sqlQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// sqlLookupID returns the ID of a term, telling whether the dictionary holds
+// it
+func sqlLookupID(db sqlQueryer, t Term) (int64, bool, error) {
+	if t == nil || t == DefaultGraph {
+		return 0, true, nil
+	}
+	value, err := encodeStoredTerm(t)
+	if err != nil {
+		return 0, false, err
+	}
+	var id int64
+	err = db.QueryRow("SELECT id FROM terms WHERE value = ?", value).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return id, err == nil, err
+}
+
+// Remove removes the quad equal to q
+func (s *SQLiteStore) Remove(q *Quad) (bool, error) {
+	var ids [4]int64
+	for i, t := range []Term{q.Subject, q.Predicate, q.Object, q.Graph} {
+		id, found, err := sqlLookupID(s.db, t)
+		if err != nil || !found {
+			return false, err
+		}
+		ids[i] = id
+	}
+	res, err := s.db.Exec("DELETE FROM quads WHERE s = ? AND p = ? AND o = ? AND g = ?", ids[0], ids[1], ids[2], ids[3])
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Len returns the number of quads
+func (s *SQLiteStore) Len() (int, error) {
+	n := 0
+	err := s.db.QueryRow("SELECT COUNT(*) FROM quads").Scan(&n)
+	return n, err
+}
+
+// Iterate calls fn with every quad until it returns false
+func (s *SQLiteStore) Iterate(fn func(*Quad) bool) error {
+	return s.Match(nil, nil, nil, AnyGraph, fn)
+}
+
+// Match calls fn with the quads matching a pattern, see Store. The quads
+// are read in batches, between which fn is called and may modify the store.
+func (s *SQLiteStore) Match(subj Term, p Term, o Term, g Term, fn func(*Quad) bool) error {
+	var conditions []string
+	var args []any
+	for i, t := range []Term{subj, p, o, g} {
+		column := []string{"s", "p", "o", "g"}[i]
+		if t == UnionGraph {
+			conditions = append(conditions, "g <> 0")
+		}
+		if t == nil && i < 3 || t == AnyGraph || t == UnionGraph {
+			continue
+		}
+		id, found, err := sqlLookupID(s.db, t)
+		if err != nil || !found {
+			return err
+		}
+		conditions = append(conditions, column+" = ?")
+		args = append(args, id)
+	}
+
+	terms := make(map[int64]Term)
+	var last []any
+	for {
+		query := "SELECT s, p, o, g FROM quads"
+		where, params := conditions, args
+		if last != nil {
+			// the batch resumes after the last quad of the previous one
+			where = append(where[:len(where):len(where)], "(s, p, o, g) > (?, ?, ?, ?)")
+			params = append(params[:len(params):len(params)], last...)
+		}
+		if len(where) > 0 {
+			query += " WHERE " + strings.Join(where, " AND ")
+		}
+		query += " ORDER BY s, p, o, g LIMIT ?"
+		batch, err := s.batch(query, append(params, sqlBatchSize), terms)
+		if err != nil {
+			return err
+		}
+		for _, q := range batch {
+			if !fn(q.quad) {
+				return nil
+			}
+		}
+		if len(batch) < sqlBatchSize {
+			return nil
+		}
+		ids := batch[len(batch)-1].ids
+		last = []any{ids[0], ids[1], ids[2], ids[3]}
+		if len(terms) > sqlBatchSize*16 {
+			terms = make(map[int64]Term)
+		}
+	}
+}
+
+// sqlQuad is a quad read from a SQLiteStore with the IDs of its terms
+type sqlQuad struct {
+	quad *Quad
+	ids  [4]int64
+}
+
+// batch runs a query of Match, decoding the terms through a cache
+func (s *SQLiteStore) batch(query string, args []any, cache map[int64]Term) ([]sqlQuad, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var batch []sqlQuad
+	for rows.Next() {
+		var q sqlQuad
+		if err := rows.Scan(&q.ids[0], &q.ids[1], &q.ids[2], &q.ids[3]); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		batch = append(batch, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// the terms are read once the rows are closed, as SQLite databases are
+	// often limited to a single connection
+	for i := range batch {
+		var terms [4]Term
+		for j, id := range batch[i].ids {
+			if id == 0 {
+				continue
+			}
+			t, ok := cache[id]
+			if !ok {
+				var value string
+				if err := s.db.QueryRow("SELECT value FROM terms WHERE id = ?", id).Scan(&value); err != nil {
+					return nil, err
+				}
+				if t, err = decodeTerm(value); err != nil {
+					return nil, err
+				}
+				cache[id] = t
+			}
+			terms[j] = t
+		}
+		batch[i].quad = NewQuad(terms[0], terms[1], terms[2], terms[3])
+	}
+	return batch, nil
+}
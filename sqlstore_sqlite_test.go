@@ -0,0 +1,84 @@
+//go:build sqlite
+
+package rdf2go
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// Run with go test -tags sqlite to test SQLiteStore against SQLite
+
+func openSQLiteStore(t *testing.T, name string) *SQLiteStore {
+	db, err := sql.Open("sqlite", name)
+	assert.NoError(t, err)
+	// SQLite databases are often limited to a single connection
+	db.SetMaxOpenConns(1)
+	store, err := NewSQLiteStore(db)
+	assert.NoError(t, err)
+	return store
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store := openSQLiteStore(t, filepath.Join(t.TempDir(), "store.db"))
+	defer store.Close()
+	testStore(t, store)
+}
+
+func TestSQLiteStoreSchema(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "schema.db")
+	assert.NoError(t, openSQLiteStore(t, name).Close())
+	// the tables and indexes are only created once
+	store := openSQLiteStore(t, name)
+	defer store.Close()
+	var indexes int
+	assert.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name LIKE 'quads_%'").Scan(&indexes))
+	assert.Equal(t, 3, indexes)
+}
+
+func TestSQLiteStoreBatches(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "batches.db")
+	store := openSQLiteStore(t, name)
+	var quads []*Quad
+	for i := 0; i < 2500; i++ {
+		quads = append(quads, NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral(fmt.Sprint(i%1300)), nil))
+	}
+	n, err := store.AddAll(quads)
+	assert.NoError(t, err)
+	assert.Equal(t, 1300, n)
+	assert.NoError(t, store.Close())
+
+	store = openSQLiteStore(t, name)
+	defer store.Close()
+	d := NewStoreDataset("", store)
+	assert.Equal(t, 1300, d.Len())
+	// the quads are read in batches resuming after the last quad read
+	seen := make(map[string]bool)
+	for quad := range d.Match(NewResource("http://example.org/s"), nil, nil, nil) {
+		seen[quad.Object.RawValue()] = true
+	}
+	assert.Len(t, seen, 1300)
+	assert.NoError(t, d.Err())
+
+	// the store may be modified between batches
+	removed := 0
+	for quad := range d.Match(nil, nil, nil, AnyGraph) {
+		if removed < 1200 {
+			d.Remove(quad)
+			removed++
+		}
+	}
+	assert.NoError(t, d.Err())
+	assert.Equal(t, 100, d.Len())
+}
+
+func TestSQLiteStoreRealRollback(t *testing.T) {
+	store := openSQLiteStore(t, filepath.Join(t.TempDir(), "rollback.db"))
+	defer store.Close()
+	testSQLiteStoreRollback(t, store)
+}
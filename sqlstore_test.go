@@ -0,0 +1,248 @@
+package rdf2go
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSQLiteDriver emulates the statements of SQLiteStore on tables held in
+// memory, one database per name, for the error paths. The store is tested
+// against SQLite itself with the sqlite build tag. A transaction works on a copy of the
+// tables, which replaces them on commit and is discarded on rollback.
+type fakeSQLiteDriver struct{}
+
+type fakeSQLite struct {
+	sync.Mutex
+	tables *fakeSQLiteTables
+}
+
+type fakeSQLiteTables struct {
+	terms []string
+	quads map[[4]int64]bool
+}
+
+func (t *fakeSQLiteTables) clone() *fakeSQLiteTables {
+	return &fakeSQLiteTables{terms: slices.Clone(t.terms), quads: maps.Clone(t.quads)}
+}
+
+var fakeSQLiteDatabases sync.Map
+
+type fakeSQLiteConn struct {
+	db *fakeSQLite
+	tx *fakeSQLiteTables
+}
+type fakeSQLiteStmt struct {
+	conn  *fakeSQLiteConn
+	query string
+}
+type fakeSQLiteTx struct{ conn *fakeSQLiteConn }
+type fakeSQLiteResult struct{ id, rows int64 }
+
+func (fakeSQLiteDriver) Open(name string) (driver.Conn, error) {
+	db, _ := fakeSQLiteDatabases.LoadOrStore(name, &fakeSQLite{tables: &fakeSQLiteTables{quads: make(map[[4]int64]bool)}})
+	return &fakeSQLiteConn{db: db.(*fakeSQLite)}, nil
+}
+
+func (c *fakeSQLiteConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLiteStmt{c, query}, nil
+}
+func (c *fakeSQLiteConn) Close() error { return nil }
+func (c *fakeSQLiteConn) Begin() (driver.Tx, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+	c.tx = c.db.tables.clone()
+	return fakeSQLiteTx{c}, nil
+}
+
+// tables returns the tables seen by the connection, those of its transaction
+// if any, c.db being locked
+func (c *fakeSQLiteConn) tables() *fakeSQLiteTables {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.db.tables
+}
+
+func (tx fakeSQLiteTx) Commit() error {
+	tx.conn.db.Lock()
+	defer tx.conn.db.Unlock()
+	tx.conn.db.tables, tx.conn.tx = tx.conn.tx, nil
+	return nil
+}
+
+func (tx fakeSQLiteTx) Rollback() error {
+	tx.conn.db.Lock()
+	defer tx.conn.db.Unlock()
+	tx.conn.tx = nil
+	return nil
+}
+
+func (r fakeSQLiteResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r fakeSQLiteResult) RowsAffected() (int64, error) { return r.rows, nil }
+
+func (s *fakeSQLiteStmt) Close() error  { return nil }
+func (s *fakeSQLiteStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLiteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.db.Lock()
+	defer s.conn.db.Unlock()
+	db := s.conn.tables()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE "):
+		return fakeSQLiteResult{}, nil
+	case s.query == "INSERT INTO terms (value) VALUES (?)":
+		if slices.Contains(db.terms, args[0].(string)) {
+			return nil, fmt.Errorf("UNIQUE constraint failed: %v", args[0])
+		}
+		db.terms = append(db.terms, args[0].(string))
+		return fakeSQLiteResult{id: int64(len(db.terms))}, nil
+	case s.query == "INSERT OR IGNORE INTO quads (s, p, o, g) VALUES (?, ?, ?, ?)":
+		key := fakeSQLiteKey(args)
+		if db.quads[key] {
+			return fakeSQLiteResult{}, nil
+		}
+		db.quads[key] = true
+		return fakeSQLiteResult{rows: 1}, nil
+	case s.query == "DELETE FROM quads WHERE s = ? AND p = ? AND o = ? AND g = ?":
+		key := fakeSQLiteKey(args)
+		if !db.quads[key] {
+			return fakeSQLiteResult{}, nil
+		}
+		delete(db.quads, key)
+		return fakeSQLiteResult{rows: 1}, nil
+	}
+	return nil, fmt.Errorf("unexpected statement %q", s.query)
+}
+
+func (s *fakeSQLiteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.db.Lock()
+	defer s.conn.db.Unlock()
+	db := s.conn.tables()
+	switch {
+	case s.query == "SELECT id FROM terms WHERE value = ?":
+		result := fakeSQLResult{columns: []string{"id"}}
+		if i := slices.Index(db.terms, args[0].(string)); i >= 0 {
+			result.rows = [][]driver.Value{{int64(i + 1)}}
+		}
+		return &fakeSQLRows{result: result}, nil
+	case s.query == "SELECT value FROM terms WHERE id = ?":
+		return &fakeSQLRows{result: fakeSQLResult{
+			columns: []string{"value"},
+			rows:    [][]driver.Value{{db.terms[args[0].(int64)-1]}},
+		}}, nil
+	case s.query == "SELECT COUNT(*) FROM quads":
+		return &fakeSQLRows{result: fakeSQLResult{
+			columns: []string{"count"},
+			rows:    [][]driver.Value{{int64(len(db.quads))}},
+		}}, nil
+	case strings.HasPrefix(s.query, "SELECT s, p, o, g FROM quads"):
+		return s.match(db, args)
+	}
+	return nil, fmt.Errorf("unexpected query %q", s.query)
+}
+
+// match evaluates the conditions, order and limit of a query of Match
+func (s *fakeSQLiteStmt) match(db *fakeSQLiteTables, args []driver.Value) (driver.Rows, error) {
+	query := strings.TrimPrefix(s.query, "SELECT s, p, o, g FROM quads")
+	query, ok := strings.CutSuffix(query, " ORDER BY s, p, o, g LIMIT ?")
+	if !ok {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	var conditions []string
+	if where, ok := strings.CutPrefix(query, " WHERE "); ok {
+		conditions = strings.Split(where, " AND ")
+	}
+	var keys [][4]int64
+	for key := range db.quads {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [4]int64) int {
+		return slices.Compare(a[:], b[:])
+	})
+	result := fakeSQLResult{columns: []string{"s", "p", "o", "g"}}
+	for _, key := range keys {
+		arg := 0
+		matches := true
+		for _, condition := range conditions {
+			switch condition {
+			case "s = ?", "p = ?", "o = ?", "g = ?":
+				matches = matches && key[strings.Index("spog", condition[:1])] == args[arg].(int64)
+				arg++
+			case "g <> 0":
+				matches = matches && key[3] != 0
+			case "(s, p, o, g) > (?, ?, ?, ?)":
+				after := fakeSQLiteKey(args[arg:])
+				matches = matches && slices.Compare(key[:], after[:]) > 0
+				arg += 4
+			default:
+				return nil, fmt.Errorf("unexpected condition %q", condition)
+			}
+		}
+		if matches && int64(len(result.rows)) < args[len(args)-1].(int64) {
+			result.rows = append(result.rows, []driver.Value{key[0], key[1], key[2], key[3]})
+		}
+	}
+	return &fakeSQLRows{result: result}, nil
+}
+
+func fakeSQLiteKey(args []driver.Value) [4]int64 {
+	return [4]int64{args[0].(int64), args[1].(int64), args[2].(int64), args[3].(int64)}
+}
+
+func init() {
+	sql.Register("rdf2go-fake-sqlite", fakeSQLiteDriver{})
+}
+
+func openFakeSQLiteStore(t *testing.T, name string) *SQLiteStore {
+	db, err := sql.Open("rdf2go-fake-sqlite", name)
+	assert.NoError(t, err)
+	store, err := NewSQLiteStore(db)
+	assert.NoError(t, err)
+	return store
+}
+
+func TestSQLiteStoreRollback(t *testing.T) {
+	testSQLiteStoreRollback(t, openFakeSQLiteStore(t, filepath.Join(t.TempDir(), "rollback.db")))
+}
+
+// testSQLiteStoreRollback checks that a failed batch leaves a store unchanged
+func testSQLiteStoreRollback(t *testing.T, store *SQLiteStore) {
+	a := NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("a"), nil)
+	added, err := store.Add(a)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// the batch fails on its last quad, which cannot be stored, after its
+	// other quads and new terms were written
+	_, err = store.AddAll([]*Quad{
+		NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("b"), nil),
+		NewQuad(NewResource("http://example.org/t"), NewResource("http://example.org/q"), NewLiteral("c"), NewResource("http://example.org/g")),
+		NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), Var("x"), nil),
+	})
+	assert.Error(t, err)
+	n, err := store.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var quads []*Quad
+	assert.NoError(t, store.Iterate(func(q *Quad) bool {
+		quads = append(quads, q)
+		return true
+	}))
+	if assert.Len(t, quads, 1) {
+		assert.True(t, quads[0].Equal(a))
+	}
+	for _, term := range []Term{NewLiteral("b"), NewResource("http://example.org/t"), NewResource("http://example.org/g")} {
+		_, found, err := sqlLookupID(store.db, term)
+		assert.NoError(t, err)
+		assert.False(t, found, term.String())
+	}
+}
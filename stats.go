@@ -0,0 +1,146 @@
+package rdf2go
+
+// Stats profiles the content of a graph or dataset, for data profiling and
+// query planning. The maps are keyed by IRI, or by the N-Triples
+// representation of the terms that are not IRIs.
+type Stats struct {
+	// Statements counts the triples or quads
+	Statements int
+	// Subjects and Objects count the distinct subjects and objects
+	Subjects int
+	Objects  int
+	// Predicates maps predicates to the number of statements using them
+	Predicates map[string]int
+	// Classes maps the classes given by rdf:type statements to the number of
+	// their distinct instances
+	Classes map[string]int
+	// Graphs maps the graphs of a dataset to their number of quads, the
+	// default graph being "". It is nil for graphs.
+	Graphs map[string]int
+	// IRIs, BlankNodes, Literals and QuotedTriples count the distinct terms of
+	// each kind, in any position
+	IRIs          int
+	BlankNodes    int
+	Literals      int
+	QuotedTriples int
+	// OutDegree maps a number of statements to the number of subjects of
+	// that many statements, and InDegree maps a number of statements to the
+	// number of IRIs and blank nodes that many statements have as object
+	OutDegree map[int]int
+	InDegree  map[int]int
+}
+
+// statsKey returns the key of a term in the maps of Stats
+func statsKey(t Term) string {
+	if r, ok := t.(*Resource); ok {
+		return r.URI
+	}
+	return t.String()
+}
+
+// statsCollector gathers the Stats of statements
+type statsCollector struct {
+	stats     Stats
+	terms     map[string]bool
+	instances map[string]bool
+	objects   map[string]bool
+	out       map[string]int
+	in        map[string]int
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		stats: Stats{
+			Predicates: make(map[string]int),
+			Classes:    make(map[string]int),
+			OutDegree:  make(map[int]int),
+			InDegree:   make(map[int]int),
+		},
+		terms:     make(map[string]bool),
+		instances: make(map[string]bool),
+		objects:   make(map[string]bool),
+		out:       make(map[string]int),
+		in:        make(map[string]int),
+	}
+}
+
+// term counts a term by kind, once
+func (c *statsCollector) term(t Term) {
+	if t == nil {
+		return
+	}
+	key := t.String()
+	if c.terms[key] {
+		return
+	}
+	c.terms[key] = true
+	switch t.(type) {
+	case *Resource:
+		c.stats.IRIs++
+	case *BlankNode:
+		c.stats.BlankNodes++
+	case *Literal:
+		c.stats.Literals++
+	case *QuotedTriple:
+		c.stats.QuotedTriples++
+	}
+}
+
+// add counts a statement
+func (c *statsCollector) add(s Term, p Term, o Term, g Term) {
+	c.stats.Statements++
+	for _, t := range []Term{s, p, o, g} {
+		c.term(t)
+	}
+	c.stats.Predicates[statsKey(p)]++
+	c.out[s.String()]++
+	switch o.(type) {
+	case *Resource, *BlankNode:
+		c.in[o.String()]++
+	}
+	c.objects[o.String()] = true
+	if p.Equal(RDF.Get("type")) {
+		instance := s.String() + " " + o.String()
+		if !c.instances[instance] {
+			c.instances[instance] = true
+			c.stats.Classes[statsKey(o)]++
+		}
+	}
+}
+
+// done completes the stats
+func (c *statsCollector) done() Stats {
+	c.stats.Subjects = len(c.out)
+	c.stats.Objects = len(c.objects)
+	for _, n := range c.out {
+		c.stats.OutDegree[n]++
+	}
+	for _, n := range c.in {
+		c.stats.InDegree[n]++
+	}
+	return c.stats
+}
+
+// Stats returns statistics on the triples of the graph
+func (g *Graph) Stats() Stats {
+	c := newStatsCollector()
+	for t := range g.Triples() {
+		c.add(t.Subject, t.Predicate, t.Object, nil)
+	}
+	return c.done()
+}
+
+// Stats returns statistics on the quads of the dataset, in every graph
+func (d *Dataset) Stats() Stats {
+	c := newStatsCollector()
+	c.stats.Graphs = make(map[string]int)
+	for q := range d.Quads() {
+		c.add(q.Subject, q.Predicate, q.Object, q.Graph)
+		if q.Graph == nil {
+			c.stats.Graphs[""]++
+		} else {
+			c.stats.Graphs[statsKey(q.Graph)]++
+		}
+	}
+	return c.done()
+}
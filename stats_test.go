@@ -0,0 +1,49 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphStats(t *testing.T) {
+	g := NewGraph(testUri)
+	alice, bob := NewResource("http://example.org/alice"), NewResource("http://example.org/bob")
+	g.AddTriple(alice, RDF.Get("type"), FOAF.Get("Person"))
+	g.AddTriple(bob, RDF.Get("type"), FOAF.Get("Person"))
+	g.AddTriple(alice, FOAF.Get("knows"), bob)
+	g.AddTriple(alice, FOAF.Get("name"), NewLiteral("Alice"))
+	g.AddTriple(NewBlankNode("n"), FOAF.Get("knows"), bob)
+
+	stats := g.Stats()
+	assert.Equal(t, 5, stats.Statements)
+	assert.Equal(t, 3, stats.Subjects)
+	assert.Equal(t, 3, stats.Objects)
+	assert.Equal(t, map[string]int{RDF.Get("type").RawValue(): 2, FOAF.Get("knows").RawValue(): 2, FOAF.Get("name").RawValue(): 1}, stats.Predicates)
+	assert.Equal(t, map[string]int{FOAF.Get("Person").RawValue(): 2}, stats.Classes)
+	assert.Nil(t, stats.Graphs)
+	assert.Equal(t, 6, stats.IRIs)
+	assert.Equal(t, 1, stats.BlankNodes)
+	assert.Equal(t, 1, stats.Literals)
+	assert.Equal(t, map[int]int{3: 1, 1: 2}, stats.OutDegree)
+	assert.Equal(t, map[int]int{2: 2}, stats.InDegree)
+}
+
+func TestDatasetStats(t *testing.T) {
+	d := NewDataset(testUri)
+	a, p := NewResource("http://example.org/a"), NewResource("http://example.org/p")
+	g := NewResource("http://example.org/g")
+	d.AddQuad(a, p, NewLiteral("1"), nil)
+	d.AddQuad(a, p, NewLiteral("1"), g)
+	d.AddQuad(a, p, NewQuotedTriple(a, p, a), g)
+
+	stats := d.Stats()
+	assert.Equal(t, 3, stats.Statements)
+	assert.Equal(t, map[string]int{"": 1, g.RawValue(): 2}, stats.Graphs)
+	assert.Equal(t, map[string]int{p.RawValue(): 3}, stats.Predicates)
+	assert.Equal(t, 3, stats.IRIs)
+	assert.Equal(t, 1, stats.Literals)
+	assert.Equal(t, 1, stats.QuotedTriples)
+	assert.Equal(t, map[int]int{3: 1}, stats.OutDegree)
+	assert.Empty(t, stats.InDegree)
+}
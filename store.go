@@ -0,0 +1,302 @@
+package rdf2go
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+)
+
+// Store is a storage backend for the quads of a StoreDataset. Stores
+// compare quads by value, blank nodes by label, a nil graph term denoting
+// the default graph.
+type Store interface {
+	// Add adds a quad, telling whether it was missing from the store
+	Add(q *Quad) (bool, error)
+	// Remove removes the quad equal to q, telling whether it was present
+	Remove(q *Quad) (bool, error)
+	// Match calls fn with each quad matching a pattern of S, P, O objects
+	// within graph g until it returns false. A nil graph selects the
+	// default graph, and the DefaultGraph, UnionGraph and AnyGraph
+	// selectors are supported. fn may modify the store.
+	Match(s Term, p Term, o Term, g Term, fn func(*Quad) bool) error
+	// Iterate calls fn with every quad until it returns false
+	Iterate(fn func(*Quad) bool) error
+	// Len returns the number of quads
+	Len() (int, error)
+	// Close releases the resources of the store
+	Close() error
+}
+
+// batchAdder is implemented by stores adding several quads more efficiently
+// at once than one by one
+type batchAdder interface {
+	AddAll(quads []*Quad) (int, error)
+}
+
+// storeBatchSize is the number of quads parsed documents are added in
+var storeBatchSize = 1000
+
+// decodeTerm reads a term in its N-Triples representation
+func decodeTerm(s string) (Term, error) {
+	l := &lineLexer{s: s}
+	t, err := l.term()
+	if err != nil {
+		return nil, err
+	}
+	if l.skipSpace(); !l.done() {
+		return nil, fmt.Errorf("unexpected content after term: %q", s[l.pos:])
+	}
+	return t, nil
+}
+
+// encodeStoredTerm returns the N-Triples representation of a term held by a
+// store, failing for the terms that cannot be read back
+func encodeStoredTerm(t Term) (string, error) {
+	switch t.(type) {
+	case *Resource, *Literal, *BlankNode, *QuotedTriple:
+		return t.String(), nil
+	}
+	return "", fmt.Errorf("cannot store term %v of type %T", t, t)
+}
+
+// MemoryStore is a Store keeping quads in memory, in a Dataset. It is safe
+// for concurrent use.
+type MemoryStore struct {
+	dataset *Dataset
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{dataset: NewDatasetWithOptions("", WithThreadSafe(true))}
+}
+
+// Add adds a quad, unless an equal quad is already present
+func (m *MemoryStore) Add(q *Quad) (bool, error) {
+	if m.dataset.One(q.Subject, q.Predicate, q.Object, storedGraph(q.Graph)) != nil {
+		return false, nil
+	}
+	m.dataset.Add(q)
+	return true, nil
+}
+
+// Remove removes the quads equal to q
+func (m *MemoryStore) Remove(q *Quad) (bool, error) {
+	quads := m.dataset.All(q.Subject, q.Predicate, q.Object, storedGraph(q.Graph))
+	for _, quad := range quads {
+		m.dataset.Remove(quad)
+	}
+	return len(quads) > 0, nil
+}
+
+// Match calls fn with the quads matching a pattern, see Store
+func (m *MemoryStore) Match(s Term, p Term, o Term, g Term, fn func(*Quad) bool) error {
+	for _, quad := range m.dataset.All(s, p, o, storedGraph(g)) {
+		if !fn(quad) {
+			break
+		}
+	}
+	return nil
+}
+
+// Iterate calls fn with every quad until it returns false
+func (m *MemoryStore) Iterate(fn func(*Quad) bool) error {
+	return m.Match(nil, nil, nil, AnyGraph, fn)
+}
+
+// Len returns the number of quads
+func (m *MemoryStore) Len() (int, error) {
+	return m.dataset.Len(), nil
+}
+
+// Close does nothing
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// StoreDataset is a dataset whose quads are held by a Store, such as a
+// BoltStore on disk, so that datasets larger than memory can be used
+// through the Model interface. As most Model methods do not return errors,
+// the first error of the store met by Len, Quads, Match, Insert, Delete or
+// Apply is recorded, see Err.
+type StoreDataset struct {
+	store  Store
+	uri    string
+	config *Config
+
+	mu  sync.Mutex
+	err error
+}
+
+var _ Model = (*StoreDataset)(nil)
+
+// NewStoreDataset creates a dataset backed by a store, configured by the
+// package-level configuration and the given options
+func NewStoreDataset(uri string, store Store, opts ...Option) *StoreDataset {
+	return &StoreDataset{store: store, uri: uri, config: newConfig(opts...)}
+}
+
+// Store returns the store of the dataset
+func (d *StoreDataset) Store() Store {
+	return d.store
+}
+
+// Err returns the first error met by the store, if any
+func (d *StoreDataset) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// record records an error of the store
+func (d *StoreDataset) record(err error) {
+	if err == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// Close closes the store
+func (d *StoreDataset) Close() error {
+	return d.store.Close()
+}
+
+// URI returns the URI of the dataset
+func (d *StoreDataset) URI() string {
+	return d.uri
+}
+
+// Len returns the number of quads in the dataset
+func (d *StoreDataset) Len() int {
+	n, err := d.store.Len()
+	d.record(err)
+	return n
+}
+
+// Quads returns an iterator over the quads of the dataset
+func (d *StoreDataset) Quads() iter.Seq[*Quad] {
+	return func(yield func(*Quad) bool) {
+		d.record(d.store.Iterate(yield))
+	}
+}
+
+// Match returns an iterator over the quads matching a pattern of S, P, O
+// objects within graph g, nil being the default graph, or the union of the
+// named graphs when Config.UnionDefaultGraph is set, as with Dataset.All.
+func (d *StoreDataset) Match(s Term, p Term, o Term, g Term) iter.Seq[*Quad] {
+	if g == nil && d.config.UnionDefaultGraph {
+		g = UnionGraph
+	}
+	return func(yield func(*Quad) bool) {
+		d.record(d.store.Match(s, p, o, g, yield))
+	}
+}
+
+// One returns one quad matching a pattern, see Match
+func (d *StoreDataset) One(s Term, p Term, o Term, g Term) *Quad {
+	for quad := range d.Match(s, p, o, g) {
+		return quad
+	}
+	return nil
+}
+
+// All returns the quads matching a pattern, see Match
+func (d *StoreDataset) All(s Term, p Term, o Term, g Term) []*Quad {
+	var quads []*Quad
+	for quad := range d.Match(s, p, o, g) {
+		quads = append(quads, quad)
+	}
+	return quads
+}
+
+// Add adds a quad, unless it is already present
+func (d *StoreDataset) Add(q *Quad) error {
+	_, err := d.store.Add(q)
+	return err
+}
+
+// AddQuad adds a quad made of individual S, P, O, G objects
+func (d *StoreDataset) AddQuad(s Term, p Term, o Term, g Term) error {
+	return d.Add(NewQuad(s, p, o, g))
+}
+
+// Remove removes the quad equal to q
+func (d *StoreDataset) Remove(q *Quad) error {
+	_, err := d.store.Remove(q)
+	return err
+}
+
+// Insert adds a quad, unless an equal quad is already present
+func (d *StoreDataset) Insert(q *Quad) {
+	d.record(d.Add(q))
+}
+
+// Delete removes the quad equal to q
+func (d *StoreDataset) Delete(q *Quad) {
+	d.record(d.Remove(q))
+}
+
+// Apply applies a change set to the dataset, removing quads first
+func (d *StoreDataset) Apply(c *ChangeSet) {
+	for _, quad := range c.Removed {
+		d.Delete(quad)
+	}
+	for _, quad := range c.Added {
+		d.Insert(quad)
+	}
+}
+
+// Parse adds the quads read from reader in the given format, streaming
+// them into the store as ParseStream does
+func (d *StoreDataset) Parse(reader io.Reader, mime string) error {
+	var batch []*Quad
+	flush := func() error {
+		defer func() { batch = batch[:0] }()
+		if adder, ok := d.store.(batchAdder); ok {
+			_, err := adder.AddAll(batch)
+			return err
+		}
+		for _, quad := range batch {
+			if _, err := d.store.Add(quad); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err := ParseStream(reader, mime, func(q *Quad) error {
+		batch = append(batch, q)
+		if len(batch) < storeBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// Serialize writes the dataset to w in the given format. TriG, JSON-LD and
+// HTML are serialized from a Dataset holding a copy of the quads, while
+// N-Quads, the default, are written one quad at a time.
+func (d *StoreDataset) Serialize(w io.Writer, mime string) error {
+	switch mimeSerializer[mime] {
+	case "trig", "jsonld", "internal":
+		out := newDataset(d.uri, d.config, d.config.client())
+		out.graphs = make(map[string]*graphIndex)
+		for quad := range d.Quads() {
+			out.Add(quad)
+		}
+		if err := d.Err(); err != nil {
+			return err
+		}
+		return out.Serialize(w, mime)
+	}
+	_, err := streamStatements(w, d.Quads(), nil, func(q *Quad) string { return q.String() })
+	return errors.Join(err, d.Err())
+}
@@ -0,0 +1,167 @@
+package rdf2go
+
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testStore exercises a store holding no quads
+func testStore(t *testing.T, store Store) {
+	alice := NewResource("http://example.org/alice")
+	knows := NewResource("http://xmlns.com/foaf/0.1/knows")
+	name := NewResource("http://xmlns.com/foaf/0.1/name")
+	g := NewResource("http://example.org/g")
+
+	for _, q := range []*Quad{
+		NewQuad(alice, knows, NewResource("http://example.org/bob"), nil),
+		NewQuad(alice, name, NewLiteralWithLanguage("Alice", "en"), nil),
+		NewQuad(alice, name, NewLiteral("Alice"), g),
+		NewQuad(NewBlankNode("b1"), knows, alice, g),
+		NewQuad(NewQuotedTriple(alice, knows, NewBlankNode("b1")), name, NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer")), g),
+	} {
+		added, err := store.Add(q)
+		assert.NoError(t, err)
+		assert.True(t, added)
+	}
+	added, err := store.Add(NewQuad(alice, name, NewLiteral("Alice"), g))
+	assert.NoError(t, err)
+	assert.False(t, added)
+	n, err := store.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	count := func(s Term, p Term, o Term, graph Term) int {
+		n := 0
+		assert.NoError(t, store.Match(s, p, o, graph, func(*Quad) bool {
+			n++
+			return true
+		}))
+		return n
+	}
+	assert.Equal(t, 2, count(nil, nil, nil, nil))
+	assert.Equal(t, 2, count(nil, nil, nil, DefaultGraph))
+	assert.Equal(t, 3, count(nil, nil, nil, g))
+	assert.Equal(t, 3, count(nil, nil, nil, UnionGraph))
+	assert.Equal(t, 5, count(nil, nil, nil, AnyGraph))
+	assert.Equal(t, 2, count(alice, name, nil, AnyGraph))
+	assert.Equal(t, 1, count(nil, nil, alice, g))
+	assert.Equal(t, 0, count(nil, nil, alice, nil))
+	assert.Equal(t, 0, count(NewResource("http://example.org/nobody"), nil, nil, AnyGraph))
+	assert.Equal(t, 1, count(alice, name, NewLiteral("Alice"), g))
+
+	var found *Quad
+	assert.NoError(t, store.Match(NewBlankNode("b1"), nil, nil, g, func(q *Quad) bool {
+		found = q
+		return false
+	}))
+	assert.True(t, found.Object.Equal(alice))
+	assert.True(t, found.Graph.Equal(g))
+	assert.NoError(t, store.Match(nil, name, nil, g, func(q *Quad) bool {
+		if _, ok := q.Subject.(*QuotedTriple); ok {
+			found = q
+		}
+		return true
+	}))
+	assert.True(t, found.Subject.Equal(NewQuotedTriple(alice, knows, NewBlankNode("b1"))))
+	assert.True(t, found.Object.Equal(NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer"))))
+
+	// fn may modify the store
+	assert.NoError(t, store.Iterate(func(q *Quad) bool {
+		_, err := store.Remove(q)
+		assert.NoError(t, err)
+		return true
+	}))
+	n, err = store.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	removed, err := store.Remove(NewQuad(alice, knows, alice, nil))
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	assert.NoError(t, store.Close())
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "quads.db"))
+	assert.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestBoltStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quads.db")
+	store, err := OpenBoltStore(path)
+	assert.NoError(t, err)
+	var quads []*Quad
+	for i := 0; i < 2500; i++ {
+		quads = append(quads, NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral(strconv.Itoa(i%1300)), nil))
+	}
+	n, err := store.AddAll(quads)
+	assert.NoError(t, err)
+	assert.Equal(t, 1300, n)
+	assert.NoError(t, store.Close())
+
+	store, err = OpenBoltStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	l, err := store.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 1300, l)
+	seen := 0
+	assert.NoError(t, store.Match(NewResource("http://example.org/s"), nil, nil, nil, func(*Quad) bool {
+		seen++
+		return true
+	}))
+	assert.Equal(t, 1300, seen)
+	_, err = store.Add(NewQuad(NewResource("http://example.org/s"), NewResource("http://example.org/p"), NewLiteral("x"), AnyGraph))
+	assert.Error(t, err)
+}
+
+func TestStoreDataset(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "quads.db"))
+	assert.NoError(t, err)
+	d := NewStoreDataset("https://example.org/", store)
+	defer d.Close()
+
+	trig := `@prefix ex: <http://example.org/> .
+ex:a ex:p "x" .
+ex:g { ex:a ex:p "y" . ex:b ex:p ex:a . }`
+	assert.NoError(t, d.Parse(strings.NewReader(trig), "application/trig"))
+	assert.Equal(t, 3, d.Len())
+	assert.Len(t, d.All(nil, nil, nil, nil), 1)
+	assert.Len(t, d.All(nil, nil, nil, AnyGraph), 3)
+	assert.NotNil(t, d.One(NewResource("http://example.org/b"), nil, nil, NewResource("http://example.org/g")))
+
+	d.Insert(NewQuad(NewResource("http://example.org/c"), NewResource("http://example.org/p"), NewLiteral("z"), nil))
+	d.Delete(NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), nil))
+	assert.Equal(t, 3, d.Len())
+	assert.NoError(t, d.Err())
+
+	c, err := Diff(d, d)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+
+	var nq bytes.Buffer
+	assert.NoError(t, d.Serialize(&nq, "application/n-quads"))
+	assert.Equal(t, 3, strings.Count(nq.String(), "\n"))
+	var out bytes.Buffer
+	assert.NoError(t, d.Serialize(&out, "application/trig"))
+	parsed := NewDataset("")
+	assert.NoError(t, parsed.Parse(&out, "application/trig"))
+	assert.Equal(t, 3, parsed.Len())
+
+	union := NewStoreDataset("", NewMemoryStore(), WithUnionDefaultGraph(true))
+	union.Insert(NewQuad(NewResource("http://example.org/a"), NewResource("http://example.org/p"), NewLiteral("x"), NewResource("http://example.org/g")))
+	assert.Len(t, union.All(nil, nil, nil, nil), 1)
+	assert.Len(t, union.All(nil, nil, nil, DefaultGraph), 0)
+
+	d.Insert(NewQuad(NewResource("http://example.org/c"), NewResource("http://example.org/p"), NewLiteral("z"), UnionGraph))
+	assert.Error(t, d.Err())
+}
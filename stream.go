@@ -0,0 +1,145 @@
+package rdf2go
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+)
+
+// streamProgressInterval is the number of statements written between two
+// calls of the progress callback of Stream
+const streamProgressInterval = 10000
+
+// WriteTo writes the graph to w as N-Triples, one statement at a time
+// through a buffer, so that memory use does not grow with the graph. It
+// implements io.WriterTo.
+func (g *Graph) WriteTo(w io.Writer) (int64, error) {
+	return streamStatements(w, g.Triples(), nil, func(t *Triple) string { return t.String() })
+}
+
+// WriteTo writes the dataset to w as N-Quads, see Graph.WriteTo
+func (d *Dataset) WriteTo(w io.Writer) (int64, error) {
+	return streamStatements(w, d.Quads(), nil, func(q *Quad) string { return q.String() })
+}
+
+// Stream serializes the graph to w in the format of the given mime type,
+// calling progress, when not nil, with the number of statements written so
+// far every 10000 statements and once done. N-Triples and N-Quads are
+// written one statement at a time with bounded memory, unless comments are
+// enabled, which sorts them; other formats are serialized as with Serialize.
+// The output is compressed with the codec of the configuration, if any.
+func (g *Graph) Stream(w io.Writer, mime string, progress func(statements int)) error {
+	return compressTo(w, g.config.Codec, func(w io.Writer) error {
+		name := mimeSerializer[mime]
+		if (name == "ntriples" || name == "nquads") && !g.config.Serialize.Comments {
+			_, err := streamStatements(w, g.Triples(), progress, func(t *Triple) string { return t.String() })
+			return err
+		}
+		return streamSerialize(w, g.Len(), progress, func(w io.Writer) error { return g.Serialize(w, mime) })
+	})
+}
+
+// Stream serializes the dataset to w in the format of the given mime type,
+// see Graph.Stream
+func (d *Dataset) Stream(w io.Writer, mime string, progress func(statements int)) error {
+	return compressTo(w, d.config.Codec, func(w io.Writer) error {
+		name := mimeSerializer[mime]
+		if (name == "ntriples" || name == "nquads") && !d.config.Serialize.Comments {
+			_, err := streamStatements(w, d.Quads(), progress, func(q *Quad) string { return q.String() })
+			return err
+		}
+		return streamSerialize(w, d.Len(), progress, func(w io.Writer) error { return d.Serialize(w, mime) })
+	})
+}
+
+// ParseStream parses the statements read from reader in the format of the
+// given mime type, calling fn for each of them instead of adding them to a
+// graph or dataset, so that large documents can be filtered or transformed
+// on the fly. Parsing stops at the first error returned by fn, which is
+// returned. N-Quads and N-Triples are read one line at a time; Turtle and
+// TriG documents are read whole but their statements are not stored; other
+// formats are parsed into a Dataset first.
+func ParseStream(reader io.Reader, mime string, fn func(*Quad) error) error {
+	reader, err := decodeCharset(reader, mime)
+	if err != nil {
+		return err
+	}
+	mime = mediaType(mime)
+	switch name := mimeParser[mime]; name {
+	case "nquads", "ntriples":
+		return parseNQuads(reader, nil, nil, func(quad *Quad) error {
+			if name == "ntriples" && quad.Graph != nil {
+				return errors.New("N-Triples statements cannot have a graph label")
+			}
+			return fn(quad)
+		})
+	case "turtle", "trig":
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(reader); err != nil {
+			return err
+		}
+		var fnErr error
+		err := parseTrig(buf.String(), "", name == "turtle", func(s Term, p Term, o Term, g Term) {
+			if fnErr == nil {
+				fnErr = fn(NewQuad(s, p, o, g))
+			}
+		})
+		if fnErr != nil {
+			return fnErr
+		}
+		return err
+	}
+	d := NewDataset("")
+	if err := d.Parse(reader, mime); err != nil {
+		return err
+	}
+	for quad := range d.Quads() {
+		if err := fn(quad); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamStatements writes one line per statement through a buffer, and
+// returns the number of bytes written
+func streamStatements[T any](w io.Writer, statements iter.Seq[T], progress func(int), line func(T) string) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var written int64
+	n := 0
+	for s := range statements {
+		m, err := bw.WriteString(line(s) + "\n")
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+		n++
+		if progress != nil && n%streamProgressInterval == 0 {
+			progress(n)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return written, err
+	}
+	if progress != nil {
+		progress(n)
+	}
+	return written, nil
+}
+
+// streamSerialize serializes through a buffer, reporting progress once done
+func streamSerialize(w io.Writer, n int, progress func(int), serialize func(io.Writer) error) error {
+	bw := bufio.NewWriter(w)
+	if err := serialize(bw); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(n)
+	}
+	return nil
+}
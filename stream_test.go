@@ -0,0 +1,105 @@
+package rdf2go
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n--; w.n < 0 {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+func TestGraphStream(t *testing.T) {
+	g := NewGraph(testUri)
+	for i := 0; i < 25000; i++ {
+		g.AddTriple(NewResource("http://example.org/s"+strconv.Itoa(i)), NewResource("http://example.org/p"), NewLiteral("o"))
+	}
+
+	var calls []int
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Stream(buf, "application/n-triples", func(n int) { calls = append(calls, n) }))
+	assert.Equal(t, []int{10000, 20000, 25000}, calls)
+	assert.Equal(t, 25000, strings.Count(buf.String(), "\n"))
+
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(buf, "application/n-triples"))
+	assert.Equal(t, g.Len(), g2.Len())
+
+	calls = nil
+	buf.Reset()
+	assert.NoError(t, g.Stream(buf, "text/turtle", func(n int) { calls = append(calls, n) }))
+	assert.Equal(t, []int{25000}, calls)
+
+	assert.Error(t, g.Stream(&failingWriter{n: 1}, "application/n-triples", nil))
+}
+
+func TestDatasetWriteTo(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
+	d.AddTriple(NewResource("a"), NewResource("b"), NewLiteral("d"))
+
+	buf := new(bytes.Buffer)
+	n, err := d.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	lines := []string{"<a> <b> <c> <g> .", `<a> <b> "d" .`}
+	assert.ElementsMatch(t, lines, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+	assert.ElementsMatch(t, lines, strings.Split(strings.TrimSpace(d.String()), "\n"))
+
+	buf.Reset()
+	assert.NoError(t, d.Stream(buf, "application/n-quads", nil))
+	assert.ElementsMatch(t, lines, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestParseStream(t *testing.T) {
+	src := "<http://example.org/a> <http://example.org/p> \"1\" .\n" +
+		"<http://example.org/b> <http://example.org/q> \"2\" <http://example.org/g> .\n" +
+		"<http://example.org/c> <http://example.org/p> \"3\" .\n"
+	var subjects []string
+	err := ParseStream(strings.NewReader(src), "application/n-quads", func(q *Quad) error {
+		if q.Predicate.RawValue() == "http://example.org/p" {
+			subjects = append(subjects, q.Subject.RawValue())
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://example.org/a", "http://example.org/c"}, subjects)
+
+	stop := errors.New("stop")
+	n := 0
+	err = ParseStream(strings.NewReader(src), "application/n-quads", func(q *Quad) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+	assert.Error(t, ParseStream(strings.NewReader(src), "application/n-triples", func(q *Quad) error { return nil }))
+
+	var quads []*Quad
+	err = ParseStream(strings.NewReader("@prefix ex: <http://example.org/> .\nex:a ex:p ex:b .\nex:g { ex:c ex:p ex:d }"), "application/trig", func(q *Quad) error {
+		quads = append(quads, q)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, quads, 2)
+	assert.Nil(t, quads[0].Graph)
+	assert.True(t, quads[1].Graph.Equal(NewResource("http://example.org/g")))
+
+	n = 0
+	err = ParseStream(strings.NewReader(`{"@id": "http://example.org/a", "http://example.org/p": "x"}`), "application/ld+json", func(q *Quad) error {
+		n++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
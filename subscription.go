@@ -0,0 +1,158 @@
+package rdf2go
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ChangeKind tells whether a quad was added to or removed from a dataset
+type ChangeKind int
+
+const (
+	// QuadAdded is reported for quads added to a dataset
+	QuadAdded ChangeKind = iota
+	// QuadRemoved is reported for quads removed from a dataset
+	QuadRemoved
+)
+
+// String returns the name of the change kind
+func (k ChangeKind) String() string {
+	if k == QuadRemoved {
+		return "removed"
+	}
+	return "added"
+}
+
+// Subscription is a standing query registered on a dataset. Its callback is
+// invoked for every quad matching the pattern that is added to or removed
+// from the dataset after the subscription was created.
+type Subscription struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+	Graph     Term
+
+	dataset  *Dataset
+	callback func(ChangeKind, *Quad)
+	channel  *changeChannel
+	canceled atomic.Bool
+}
+
+// Change is a change of a dataset, delivered by the channel of a
+// subscription
+type Change struct {
+	Kind ChangeKind
+	Quad *Quad
+}
+
+// changeChannel delivers the changes of a subscription on a channel
+type changeChannel struct {
+	changes chan Change
+	done    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	closed  bool
+}
+
+// deliver sends a change, unless the subscription is canceled meanwhile
+func (c *changeChannel) deliver(kind ChangeKind, quad *Quad) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.changes <- Change{Kind: kind, Quad: quad}:
+	case <-c.done:
+	}
+}
+
+// close closes the channel once no change is being sent
+func (c *changeChannel) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.closed = true
+		close(c.changes)
+	})
+}
+
+// Subscribe registers a standing query for the pattern of S, P, O, G objects,
+// using the same matching rules as All. The callback runs synchronously,
+// right after the dataset has been modified.
+func (d *Dataset) Subscribe(s Term, p Term, o Term, g Term, callback func(ChangeKind, *Quad)) *Subscription {
+	sub := &Subscription{
+		Subject:   s,
+		Predicate: p,
+		Object:    o,
+		Graph:     g,
+		dataset:   d,
+		callback:  callback,
+	}
+	defer d.lock()()
+	d.subscriptions = append(d.subscriptions, sub)
+	return sub
+}
+
+// SubscribeChannel registers a standing query like Subscribe, delivering the
+// changes on a channel holding up to buffer of them. Once the buffer is
+// full, modifying the dataset blocks until the changes are received or the
+// subscription is canceled, which closes the channel. Cancel may be called
+// from any goroutine.
+func (d *Dataset) SubscribeChannel(s Term, p Term, o Term, g Term, buffer int) (*Subscription, <-chan Change) {
+	c := &changeChannel{
+		changes: make(chan Change, buffer),
+		done:    make(chan struct{}),
+	}
+	sub := d.Subscribe(s, p, o, g, c.deliver)
+	sub.channel = c
+	return sub, c.changes
+}
+
+// Cancel unregisters the subscription. It is safe to call from within the
+// subscription callback.
+func (sub *Subscription) Cancel() {
+	d := sub.dataset
+	if d == nil || sub.canceled.Swap(true) {
+		return
+	}
+	unlock := d.lock()
+	for i, other := range d.subscriptions {
+		if other == sub {
+			subs := make([]*Subscription, 0, len(d.subscriptions)-1)
+			subs = append(subs, d.subscriptions[:i]...)
+			d.subscriptions = append(subs, d.subscriptions[i+1:]...)
+			break
+		}
+	}
+	unlock()
+	if sub.channel != nil {
+		sub.channel.close()
+	}
+}
+
+// Matches returns whether a quad matches the subscription pattern
+func (sub *Subscription) Matches(quad *Quad) bool {
+	var policy *IRIPolicy
+	g := sub.Graph
+	if sub.dataset != nil {
+		policy = sub.dataset.iriPolicy
+		if g == nil && sub.dataset.config.UnionDefaultGraph {
+			g = UnionGraph
+		}
+	}
+	return quadMatches(policy, quad, sub.Subject, sub.Predicate, sub.Object, g)
+}
+
+// notify invokes the callbacks of all subscriptions matching the quad
+func (d *Dataset) notify(kind ChangeKind, quad *Quad) {
+	unlock := d.rlock()
+	subs := d.subscriptions
+	unlock()
+	for _, sub := range subs {
+		if !sub.canceled.Load() && sub.Matches(quad) {
+			sub.callback(kind, quad)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasetSubscribe(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	g1 := NewResource("http://example.org/graph1")
+	knows := NewResource("http://xmlns.com/foaf/0.1/knows")
+
+	var added, removed []*Quad
+	sub := d.Subscribe(nil, knows, nil, g1, func(kind ChangeKind, quad *Quad) {
+		if kind == QuadAdded {
+			added = append(added, quad)
+		} else {
+			removed = append(removed, quad)
+		}
+	})
+
+	quad := NewQuad(NewResource("alice"), knows, NewResource("bob"), g1)
+	d.Add(quad)
+	d.Add(quad)
+	d.AddQuad(NewResource("alice"), NewResource("name"), NewLiteral("Alice"), g1)
+	d.AddTriple(NewResource("alice"), knows, NewResource("carol"))
+	assert.Equal(t, 1, len(added))
+	assert.Equal(t, quad, added[0])
+
+	d.Remove(quad)
+	d.Remove(quad)
+	assert.Equal(t, 1, len(removed))
+
+	sub.Cancel()
+	d.AddQuad(NewResource("bob"), knows, NewResource("carol"), g1)
+	assert.Equal(t, 1, len(added))
+	sub.Cancel()
+}
+
+func TestDatasetSubscribeCancelInCallback(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	calls := 0
+	var sub *Subscription
+	sub = d.Subscribe(nil, nil, nil, nil, func(kind ChangeKind, quad *Quad) {
+		calls++
+		sub.Cancel()
+	})
+	other := 0
+	d.Subscribe(nil, nil, nil, nil, func(kind ChangeKind, quad *Quad) {
+		other++
+	})
+
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 2, other)
+	assert.Equal(t, "added", QuadAdded.String())
+	assert.Equal(t, "removed", QuadRemoved.String())
+}
+
+func TestDatasetSubscribeChannel(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true))
+	knows := NewResource("http://xmlns.com/foaf/0.1/knows")
+	sub, changes := d.SubscribeChannel(nil, knows, nil, nil, 2)
+
+	quad := NewQuad(NewResource("alice"), knows, NewResource("bob"), nil)
+	d.Add(quad)
+	d.AddTriple(NewResource("alice"), NewResource("name"), NewLiteral("Alice"))
+	d.Remove(quad)
+	assert.Equal(t, Change{Kind: QuadAdded, Quad: quad}, <-changes)
+	assert.Equal(t, Change{Kind: QuadRemoved, Quad: quad}, <-changes)
+
+	// a writer blocked on a full channel is released by Cancel
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 5; i++ {
+			d.AddTriple(NewResource("alice"), knows, NewLiteral(string(rune('a'+i))))
+		}
+		done <- true
+	}()
+	<-changes
+	sub.Cancel()
+	<-done
+	for range changes {
+	}
+	_, open := <-changes
+	assert.False(t, open)
+	sub.Cancel()
+}
+
+func TestDatasetSubscribeUnionDefaultGraph(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithUnionDefaultGraph(true))
+	calls := 0
+	d.Subscribe(nil, nil, nil, nil, func(ChangeKind, *Quad) {
+		calls++
+	})
+	stored := 0
+	d.Subscribe(nil, nil, nil, DefaultGraph, func(ChangeKind, *Quad) {
+		stored++
+	})
+	d.AddQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, stored)
+}
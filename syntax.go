@@ -0,0 +1,469 @@
+package rdf2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// variable is a query variable used in SPARQL patterns and templates
+type variable struct {
+	name string
+}
+
+// String returns the SPARQL representation of the variable
+func (v *variable) String() string {
+	return "?" + v.name
+}
+
+// RawValue returns the name of the variable
+func (v *variable) RawValue() string {
+	return v.name
+}
+
+// Equal returns whether this variable has the same name as another
+func (v *variable) Equal(other Term) bool {
+	if spec, ok := other.(*variable); ok {
+		return v.name == spec.name
+	}
+	return false
+}
+
+// syntaxParser parses the triples of the Turtle family of syntaxes. It is
+// shared by the TriG and SPARQL Update parsers, which handle the statements
+// surrounding the triples themselves.
+type syntaxParser struct {
+	lex      *lexer
+	tok      token
+	prefixes map[string]string
+	base     string
+
+	// bnodes maps blank node labels to the terms they denote
+	bnodes map[string]Term
+	// freshBlankNodes makes every label denote a new blank node, instead of
+	// keeping the label of the document
+	freshBlankNodes bool
+	// variables allows query variables in place of terms
+	variables bool
+	// blankNodesAsVariables turns blank nodes into variables, as in SPARQL
+	// graph patterns
+	blankNodesAsVariables bool
+	// malformed receives the errors of the malformed statements to skip,
+	// which make parsing fail when nil
+	malformed func(error) error
+	// lexFailed tells that the lexer failed to read the token after p.tok
+	lexFailed bool
+	// depth is the number of terms being parsed that nest others, which
+	// cannot exceed maxDepth unless it is zero
+	depth    int
+	maxDepth int
+}
+
+func newSyntaxParser(src string, base string) (*syntaxParser, error) {
+	p := &syntaxParser{
+		lex:      newLexer(src),
+		prefixes: make(map[string]string),
+		base:     base,
+		bnodes:   make(map[string]Term),
+	}
+	return p, p.advance()
+}
+
+// advance reads the next token
+func (p *syntaxParser) advance() error {
+	tok, err := p.lex.next()
+	p.lexFailed = err != nil
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// errorf returns an error located at the current token
+func (p *syntaxParser) errorf(format string, args ...interface{}) error {
+	return &syntaxError{line: p.tok.line, column: p.tok.column, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *syntaxParser) isPunct(value string) bool {
+	return p.tok.is(tokPunct, value)
+}
+
+// expectPunct consumes the given punctuation token
+func (p *syntaxParser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		return p.errorf("expected '%s' but found %s", value, p.tok)
+	}
+	return p.advance()
+}
+
+// expectKeyword consumes the given keyword, compared case insensitively
+func (p *syntaxParser) expectKeyword(value string) error {
+	if !p.tok.isKeyword(value) {
+		return p.errorf("expected %s but found %s", value, p.tok)
+	}
+	return p.advance()
+}
+
+// resolve resolves a possibly relative IRI against the base IRI
+func (p *syntaxParser) resolve(iri string) string {
+	return resolveIRI(p.base, iri)
+}
+
+// expandPName expands a prefixed name using the declared prefixes
+func (p *syntaxParser) expandPName(pname string) (string, error) {
+	i := strings.IndexByte(pname, ':')
+	ns, ok := p.prefixes[pname[:i]]
+	if !ok {
+		return "", p.errorf("undefined prefix %s:", pname[:i])
+	}
+	return ns + pname[i+1:], nil
+}
+
+// prefixDecl parses the rest of a prefix declaration, after the PREFIX keyword
+func (p *syntaxParser) prefixDecl() error {
+	if p.tok.kind != tokPName || !strings.HasSuffix(p.tok.value, ":") {
+		return p.errorf("expected a prefix name but found %s", p.tok)
+	}
+	prefix := strings.TrimSuffix(p.tok.value, ":")
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind != tokIRI {
+		return p.errorf("expected an IRI but found %s", p.tok)
+	}
+	p.prefixes[prefix] = p.resolve(p.tok.value)
+	return p.advance()
+}
+
+// baseDecl parses the rest of a base declaration, after the BASE keyword
+func (p *syntaxParser) baseDecl() error {
+	if p.tok.kind != tokIRI {
+		return p.errorf("expected an IRI but found %s", p.tok)
+	}
+	p.base = p.resolve(p.tok.value)
+	return p.advance()
+}
+
+// blankNode returns the term denoted by a blank node label
+func (p *syntaxParser) blankNode(label string) Term {
+	if p.blankNodesAsVariables {
+		return &variable{name: "_:" + label}
+	}
+	if node, ok := p.bnodes[label]; ok {
+		return node
+	}
+	var node Term
+	if p.freshBlankNodes {
+		node = NewAnonNode()
+	} else {
+		node = NewBlankNode(label)
+	}
+	p.bnodes[label] = node
+	return node
+}
+
+// anonNode returns a new blank node for [] and collections
+func (p *syntaxParser) anonNode() Term {
+	if p.blankNodesAsVariables {
+		return &variable{name: "_:" + NewAnonNode().RawValue()}
+	}
+	return NewAnonNode()
+}
+
+// iriTerm parses an IRI or prefixed name
+func (p *syntaxParser) iriTerm() (Term, error) {
+	var iri string
+	switch p.tok.kind {
+	case tokIRI:
+		iri = p.resolve(p.tok.value)
+	case tokPName:
+		var err error
+		if iri, err = p.expandPName(p.tok.value); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("expected an IRI but found %s", p.tok)
+	}
+	return NewResource(iri), p.advance()
+}
+
+// triples parses a single triples statement (without the final '.'), calling
+// emit for every triple it contains
+func (p *syntaxParser) triples(emit func(s Term, pr Term, o Term)) error {
+	if p.isPunct("[") {
+		subject, err := p.blankNodePropertyList(emit)
+		if err != nil {
+			return err
+		}
+		// a blank node property list may be a statement on its own
+		if p.isPunct(".") || p.isPunct("}") || p.tok.kind == tokEOF {
+			return nil
+		}
+		return p.predicateObjectList(subject, emit)
+	}
+	subject, err := p.subject(emit)
+	if err != nil {
+		return err
+	}
+	return p.predicateObjectList(subject, emit)
+}
+
+func (p *syntaxParser) subject(emit func(s Term, pr Term, o Term)) (Term, error) {
+	switch p.tok.kind {
+	case tokIRI, tokPName:
+		return p.iriTerm()
+	case tokBlankNode:
+		node := p.blankNode(p.tok.value)
+		return node, p.advance()
+	case tokVariable:
+		if !p.variables {
+			return nil, p.errorf("variables are not allowed here")
+		}
+		v := &variable{name: p.tok.value}
+		return v, p.advance()
+	case tokPunct:
+		if p.tok.value == "(" {
+			return p.collection(emit)
+		}
+		if p.tok.value == "[" {
+			return p.blankNodePropertyList(emit)
+		}
+		if p.tok.value == "<<" {
+			return p.quotedTriple()
+		}
+	}
+	return nil, p.errorf("unexpected %s as subject", p.tok)
+}
+
+// quotedTriple parses a quoted triple of Turtle-star, << s p o >>, whose
+// terms cannot be collections or blank node property lists
+func (p *syntaxParser) quotedTriple() (Term, error) {
+	if err := p.expectPunct("<<"); err != nil {
+		return nil, err
+	}
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+	defer p.unnest()
+	subject, err := p.quotedTerm(false)
+	if err != nil {
+		return nil, err
+	}
+	verb, err := p.verb()
+	if err != nil {
+		return nil, err
+	}
+	object, err := p.quotedTerm(true)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectPunct(">>"); err != nil {
+		return nil, err
+	}
+	return NewQuotedTriple(subject, verb, object), nil
+}
+
+// quotedTerm parses the subject, or the object, of a quoted triple
+func (p *syntaxParser) quotedTerm(object bool) (Term, error) {
+	switch p.tok.kind {
+	case tokIRI, tokPName:
+		return p.iriTerm()
+	case tokBlankNode:
+		node := p.blankNode(p.tok.value)
+		return node, p.advance()
+	case tokString, tokInteger, tokDecimal, tokDouble:
+		if object {
+			return p.literal()
+		}
+	case tokKeyword:
+		if object && (p.tok.value == "true" || p.tok.value == "false") {
+			return p.literal()
+		}
+	case tokPunct:
+		switch p.tok.value {
+		case "<<":
+			return p.quotedTriple()
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return p.anonNode(), p.expectPunct("]")
+		}
+	}
+	return nil, p.errorf("unexpected %s in quoted triple", p.tok)
+}
+
+func (p *syntaxParser) predicateObjectList(subject Term, emit func(s Term, pr Term, o Term)) error {
+	for {
+		verb, err := p.verb()
+		if err != nil {
+			return err
+		}
+		if err := p.objectList(subject, verb, emit); err != nil {
+			return err
+		}
+		if !p.isPunct(";") {
+			return nil
+		}
+		for p.isPunct(";") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		// a trailing ';' is allowed
+		if p.isPunct(".") || p.isPunct("]") || p.isPunct("}") || p.isPunct("|}") || p.tok.kind == tokEOF {
+			return nil
+		}
+	}
+}
+
+func (p *syntaxParser) verb() (Term, error) {
+	if p.tok.is(tokKeyword, "a") {
+		return NewResource(rdfNamespace + "type"), p.advance()
+	}
+	if p.tok.kind == tokVariable {
+		if !p.variables {
+			return nil, p.errorf("variables are not allowed here")
+		}
+		v := &variable{name: p.tok.value}
+		return v, p.advance()
+	}
+	if p.tok.kind != tokIRI && p.tok.kind != tokPName {
+		return nil, p.errorf("unexpected %s as predicate", p.tok)
+	}
+	return p.iriTerm()
+}
+
+func (p *syntaxParser) objectList(subject Term, verb Term, emit func(s Term, pr Term, o Term)) error {
+	for {
+		object, err := p.object(emit)
+		if err != nil {
+			return err
+		}
+		emit(subject, verb, object)
+		if p.isPunct("{|") {
+			if err := p.annotation(NewQuotedTriple(subject, verb, object), emit); err != nil {
+				return err
+			}
+		}
+		if !p.isPunct(",") {
+			return nil
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+}
+
+// annotation parses the annotation of an asserted triple, {| predicateObjectList |},
+// whose statements have the quoted triple as subject
+func (p *syntaxParser) annotation(triple Term, emit func(s Term, pr Term, o Term)) error {
+	if err := p.expectPunct("{|"); err != nil {
+		return err
+	}
+	if err := p.predicateObjectList(triple, emit); err != nil {
+		return err
+	}
+	return p.expectPunct("|}")
+}
+
+func (p *syntaxParser) object(emit func(s Term, pr Term, o Term)) (Term, error) {
+	switch p.tok.kind {
+	case tokString, tokInteger, tokDecimal, tokDouble:
+		return p.literal()
+	case tokKeyword:
+		if p.tok.value == "true" || p.tok.value == "false" {
+			return p.literal()
+		}
+	}
+	return p.subject(emit)
+}
+
+// literal parses a string, numeric or boolean literal
+func (p *syntaxParser) literal() (Term, error) {
+	tok := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case tokInteger:
+		return NewLiteralWithDatatype(tok.value, NewResource(xsdNamespace+"integer")), nil
+	case tokDecimal:
+		return NewLiteralWithDatatype(tok.value, NewResource(xsdNamespace+"decimal")), nil
+	case tokDouble:
+		return NewLiteralWithDatatype(tok.value, NewResource(xsdNamespace+"double")), nil
+	case tokKeyword:
+		return NewLiteralWithDatatype(tok.value, NewResource(xsdNamespace+"boolean")), nil
+	}
+	if p.tok.kind == tokLangTag {
+		lang := p.tok.value
+		return NewLiteralWithLanguage(tok.value, lang), p.advance()
+	}
+	if p.tok.kind == tokDatatype {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		datatype, err := p.iriTerm()
+		if err != nil {
+			return nil, err
+		}
+		return NewLiteralWithDatatype(tok.value, datatype), nil
+	}
+	return NewLiteral(tok.value), nil
+}
+
+// blankNodePropertyList parses [ predicateObjectList ]
+func (p *syntaxParser) blankNodePropertyList(emit func(s Term, pr Term, o Term)) (Term, error) {
+	if err := p.expectPunct("["); err != nil {
+		return nil, err
+	}
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+	defer p.unnest()
+	node := p.anonNode()
+	if p.isPunct("]") {
+		return node, p.advance()
+	}
+	if err := p.predicateObjectList(node, emit); err != nil {
+		return nil, err
+	}
+	return node, p.expectPunct("]")
+}
+
+// collection parses ( object* ) into an rdf:List
+func (p *syntaxParser) collection(emit func(s Term, pr Term, o Term)) (Term, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+	defer p.unnest()
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	var head, last Term
+	for !p.isPunct(")") {
+		if p.tok.kind == tokEOF {
+			return nil, p.errorf("unterminated collection")
+		}
+		object, err := p.object(emit)
+		if err != nil {
+			return nil, err
+		}
+		node := p.anonNode()
+		if head == nil {
+			head = node
+		} else {
+			emit(last, rest, node)
+		}
+		emit(node, first, object)
+		last = node
+	}
+	nilList := NewResource(rdfNamespace + "nil")
+	if head == nil {
+		return nilList, p.advance()
+	}
+	emit(last, rest, nilList)
+	return head, p.advance()
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"unicode/utf8"
 
 	rdf "github.com/deiu/gon3"
 	jsonld "github.com/linkeddata/gojsonld"
@@ -53,7 +54,7 @@ func NewResource(uri string) (term Term) {
 
 // String returns the NTriples representation of this resource.
 func (term Resource) String() (str string) {
-	return fmt.Sprintf("<%s>", term.URI)
+	return "<" + escapeIRI(term.URI) + ">"
 }
 
 // RawValue returns the string value of the a resource without brackets.
@@ -94,14 +95,7 @@ func NewLiteralWithDatatype(value string, datatype Term) (term Term) {
 
 // String returns the NTriples representation of this literal.
 func (term Literal) String() string {
-	str := term.Value
-	str = strings.Replace(str, "\\", "\\\\", -1)
-	str = strings.Replace(str, "\"", "\\\"", -1)
-	str = strings.Replace(str, "\n", "\\n", -1)
-	str = strings.Replace(str, "\r", "\\r", -1)
-	str = strings.Replace(str, "\t", "\\t", -1)
-
-	str = fmt.Sprintf("\"%s\"", str)
+	str := "\"" + escapeString(term.Value) + "\""
 
 	// if term.Language != "" {
 	str += atLang(term.Language)
@@ -125,7 +119,14 @@ func (term Literal) Equal(other Term) bool {
 	}
 
 	if term.Value != spec.Value {
-		return false
+		if term.Datatype == nil || spec.Datatype == nil || !term.Datatype.Equal(spec.Datatype) {
+			return false
+		}
+		cmp, ok := lookupComparator(term.Datatype)
+		if !ok || cmp.Equal == nil {
+			return false
+		}
+		return cmp.Equal(term.Value, spec.Value)
 	}
 
 	if term.Language != spec.Language {
@@ -176,6 +177,37 @@ func (term BlankNode) Equal(other Term) bool {
 	return false
 }
 
+// QuotedTriple is an RDF-star quoted triple, a triple used as the subject or
+// object of another statement without being asserted.
+type QuotedTriple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// NewQuotedTriple returns a new quoted triple with the given terms.
+func NewQuotedTriple(subject Term, predicate Term, object Term) (term Term) {
+	return Term(&QuotedTriple{Subject: subject, Predicate: predicate, Object: object})
+}
+
+// String returns the N-Triples-star representation of the quoted triple.
+func (term QuotedTriple) String() string {
+	return "<< " + term.RawValue() + " >>"
+}
+
+func (term QuotedTriple) RawValue() string {
+	return term.Subject.String() + " " + term.Predicate.String() + " " + term.Object.String()
+}
+
+// Equal returns whether this quoted triple has the same terms as another.
+func (term QuotedTriple) Equal(other Term) bool {
+	if spec, ok := other.(*QuotedTriple); ok {
+		return term.Subject.Equal(spec.Subject) && term.Predicate.Equal(spec.Predicate) && term.Object.Equal(spec.Object)
+	}
+
+	return false
+}
+
 func term2rdf(t Term) rdf.Term {
 	switch t := t.(type) {
 	case *BlankNode:
@@ -259,16 +291,96 @@ func term2jterm(term Term) jsonld.Term {
 func encodeTerm(iterm Term) string {
 	switch term := iterm.(type) {
 	case *Resource:
-		return fmt.Sprintf("<%s>", term.URI)
+		return term.String()
 	case *Literal:
 		return term.String()
 	case *BlankNode:
 		return term.String()
+	case *QuotedTriple:
+		return "<< " + encodeTerm(term.Subject) + " " + encodeTerm(term.Predicate) + " " + encodeTerm(term.Object) + " >>"
 	}
 
 	return ""
 }
 
+// escapeString escapes the value of a literal as N-Triples, N-Quads and
+// Turtle strings are in the canonical form of N-Triples: quotes, backslashes
+// and the control characters having an ECHAR escape sequence, such as \n,
+// are escaped with it, the other control characters as \uXXXX, and the
+// other characters, non-BMP ones included, are written as UTF-8, invalid
+// bytes becoming U+FFFD
+func escapeString(s string) string {
+	if !needsEscape(s, false) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	writeEscapedString(&b, s)
+	return b.String()
+}
+
+// writeEscapedString writes a string escaped as by escapeString
+func writeEscapedString(b *strings.Builder, s string) {
+	for _, r := range s {
+		switch r {
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			if r < 0x20 || r == 0x7F {
+				fmt.Fprintf(b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+}
+
+// escapeIRI escapes the characters that IRIREF does not allow in an IRI, as
+// \uXXXX, invalid bytes becoming U+FFFD
+func escapeIRI(iri string) string {
+	if !needsEscape(iri, true) {
+		return iri
+	}
+	var b strings.Builder
+	for _, r := range iri {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, `\u%04X`, r)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// needsEscape tells whether a string, an IRI if iri is set, holds characters
+// to escape or invalid UTF-8
+func needsEscape(s string, iri bool) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c < 0x20 || c == 0x7F || c == '"' || c == '\\':
+			return true
+		case c >= utf8.RuneSelf:
+			return !utf8.ValidString(s[i:])
+		case iri && strings.IndexByte(" <>{}|^`", c) >= 0:
+			return true
+		}
+	}
+	return false
+}
+
 func atLang(lang string) string {
 	if len(lang) > 0 {
 		if strings.HasPrefix(lang, "@") {
@@ -1,6 +1,7 @@
 package rdf2go
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -112,6 +113,52 @@ func TestEncodeTerm(t *testing.T) {
 	assert.Equal(t, "", encodeTerm(iterm))
 }
 
+// cases adapted from the W3C N-Triples test suite, such as
+// literal_all_controls and literal_with_UTF8_boundaries
+func TestEscapeString(t *testing.T) {
+	for value, expected := range map[string]string{
+		"plain": `"plain"`,
+		"x\x00\x01\x07\x08\t\n\x0b\x0c\r\x0e\x1f\x7f": `"x\u0000\u0001\u0007\b\t\n\u000B\f\r\u000E\u001F\u007F"`,
+		"'\"\\":                    `"'\"\\"`,
+		"\u0080\u07ff\u0800\uffff": "\"\u0080\u07ff\u0800\uffff\"",
+		"\U00010000\U0010FFFF":     "\"\U00010000\U0010FFFF\"",
+		"bad\xff":                  "\"bad\uFFFD\"",
+	} {
+		lit := NewLiteral(value)
+		assert.Equal(t, expected, lit.String())
+		assert.Equal(t, expected, encodeTerm(lit))
+	}
+	assert.Equal(t, `"a\nb"@en`, NewLiteralWithLanguage("a\nb", "en").String())
+}
+
+func TestEscapeIRI(t *testing.T) {
+	assert.Equal(t, "<http://example.org/a>", NewResource("http://example.org/a").String())
+	assert.Equal(t, "<http://example.org/\u00E9>", NewResource("http://example.org/\u00E9").String())
+	assert.Equal(t, `<http://example.org/a\u0020b\u003C\u003E\u0022\u007B\u007D\u007C\u005E\u0060\u005C\u000A>`, NewResource("http://example.org/a b<>\"{}|^`\\\n").String())
+}
+
+func TestEscapeRoundtrip(t *testing.T) {
+	value := "tab\tnewline\nquote\"backslash\\bell\x07\U0001F600"
+	iri := "http://example.org/a b"
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource(iri), NewResource("http://example.org/p"), NewLiteral(value))
+	for _, mime := range []string{"application/n-triples", "application/trig"} {
+		var b strings.Builder
+		assert.NoError(t, g.Serialize(&b, mime))
+		g2 := NewGraph(testUri)
+		assert.NoError(t, g2.Parse(strings.NewReader(b.String()), mime), mime)
+		assert.NotNil(t, g2.One(NewResource(iri), nil, NewLiteral(value)), mime)
+	}
+
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(NewResource(iri), NewResource("http://example.org/p"), NewLiteral(value), NewResource("http://example.org/g"))
+	var b strings.Builder
+	assert.NoError(t, d.Serialize(&b, "application/n-quads"))
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(b.String()), "application/n-quads"))
+	assert.NotNil(t, d2.One(NewResource(iri), nil, NewLiteral(value), NewResource("http://example.org/g")))
+}
+
 func TestSplitPrefix(t *testing.T) {
 	hashUri := testUri + "#me"
 	base, name := splitPrefix(hashUri)
@@ -0,0 +1,308 @@
+package rdf2go
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TermViewKind identifies the kind of term held by a TermView
+type TermViewKind int
+
+const (
+	// NoTermView marks an absent term, such as the graph of a statement of
+	// the default graph
+	NoTermView TermViewKind = iota
+	// IRIView is an IRI
+	IRIView
+	// BlankNodeView is a blank node, whose Value is the label
+	BlankNodeView
+	// LiteralView is a literal
+	LiteralView
+)
+
+// TermView is a term read in zero-copy mode by ScanNQuads. Its byte slices
+// point into the buffer of the scanner and are only valid until the next
+// statement is read; use Term to keep a copy.
+type TermView struct {
+	Kind TermViewKind
+	// Value is the IRI, the blank node label or the lexical form of the
+	// literal, with escape sequences resolved
+	Value []byte
+	// Language is the language tag of the literal, if any
+	Language []byte
+	// Datatype is the datatype IRI of the literal, if any
+	Datatype []byte
+}
+
+// Term returns a copy of the viewed term, or nil for an absent term
+func (v *TermView) Term() Term {
+	switch v.Kind {
+	case IRIView:
+		return NewResource(string(v.Value))
+	case BlankNodeView:
+		return NewBlankNode(string(v.Value))
+	case LiteralView:
+		if len(v.Language) > 0 {
+			return NewLiteralWithLanguage(string(v.Value), string(v.Language))
+		}
+		if len(v.Datatype) > 0 {
+			return NewLiteralWithDatatype(string(v.Value), NewResource(string(v.Datatype)))
+		}
+		return NewLiteral(string(v.Value))
+	}
+	return nil
+}
+
+// Equal returns whether the view denotes the given term, without allocating
+func (v *TermView) Equal(t Term) bool {
+	switch t := t.(type) {
+	case nil:
+		return v.Kind == NoTermView
+	case *Resource:
+		return v.Kind == IRIView && string(v.Value) == t.URI
+	case *BlankNode:
+		return v.Kind == BlankNodeView && string(v.Value) == t.ID
+	case *Literal:
+		if v.Kind != LiteralView || string(v.Value) != t.Value || string(v.Language) != t.Language {
+			return false
+		}
+		if t.Datatype == nil {
+			return len(v.Datatype) == 0
+		}
+		return string(v.Datatype) == t.Datatype.RawValue()
+	}
+	return false
+}
+
+// QuadView is a statement read in zero-copy mode by ScanNQuads
+type QuadView struct {
+	Subject   TermView
+	Predicate TermView
+	Object    TermView
+	Graph     TermView
+}
+
+// Quad returns a copy of the viewed statement
+func (q *QuadView) Quad() *Quad {
+	return NewQuad(q.Subject.Term(), q.Predicate.Term(), q.Object.Term(), q.Graph.Term())
+}
+
+// ScanNQuads reads N-Quads (or N-Triples) statements from reader and calls fn
+// for each of them, until fn returns an error. Statements are not copied:
+// the view passed to fn, and the slices it holds, are reused for the next
+// statement. This suits high-throughput filtering jobs, which only copy the
+// few statements they keep, with QuadView.Quad.
+func ScanNQuads(reader io.Reader, fn func(*QuadView) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNQuadsLine)
+	l := &viewLexer{}
+	view := &QuadView{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		l.reset(scanner.Bytes())
+		ok, err := l.statement(view)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(view); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// viewLexer reads the terms of a single statement as views into the line
+type viewLexer struct {
+	s   []byte
+	pos int
+	// scratch holds unescaped values; it is allocated with the capacity of
+	// the line, so that appending never moves previous values
+	scratch []byte
+}
+
+func (l *viewLexer) reset(line []byte) {
+	l.s, l.pos = line, 0
+	if cap(l.scratch) < len(line) {
+		l.scratch = make([]byte, 0, len(line))
+	}
+	l.scratch = l.scratch[:0]
+}
+
+func (l *viewLexer) done() bool {
+	return l.pos >= len(l.s)
+}
+
+func (l *viewLexer) skipSpace() {
+	for !l.done() && (l.s[l.pos] == ' ' || l.s[l.pos] == '\t' || l.s[l.pos] == '\r') {
+		l.pos++
+	}
+}
+
+// statement reads a statement into view. It returns false for empty lines
+// and comments.
+func (l *viewLexer) statement(view *QuadView) (bool, error) {
+	l.skipSpace()
+	if l.done() || l.s[l.pos] == '#' {
+		return false, nil
+	}
+	if err := l.term(&view.Subject); err != nil {
+		return false, err
+	}
+	if view.Subject.Kind == LiteralView {
+		return false, errors.New("a literal cannot be used as subject")
+	}
+	if err := l.term(&view.Predicate); err != nil {
+		return false, err
+	}
+	if view.Predicate.Kind != IRIView {
+		return false, errors.New("predicate must be an IRI")
+	}
+	if err := l.term(&view.Object); err != nil {
+		return false, err
+	}
+
+	view.Graph = TermView{}
+	l.skipSpace()
+	if !l.done() && l.s[l.pos] != '.' {
+		if err := l.term(&view.Graph); err != nil {
+			return false, err
+		}
+		if view.Graph.Kind == LiteralView {
+			return false, errors.New("a literal cannot be used as graph label")
+		}
+	}
+
+	l.skipSpace()
+	if l.done() || l.s[l.pos] != '.' {
+		return false, errors.New("expected '.' at the end of the statement")
+	}
+	l.pos++
+	l.skipSpace()
+	if !l.done() && l.s[l.pos] != '#' {
+		return false, fmt.Errorf("unexpected content after '.': %q", l.s[l.pos:])
+	}
+	return true, nil
+}
+
+func (l *viewLexer) term(v *TermView) error {
+	*v = TermView{}
+	l.skipSpace()
+	if l.done() {
+		return errors.New("unexpected end of statement")
+	}
+	var err error
+	switch l.s[l.pos] {
+	case '<':
+		v.Kind = IRIView
+		v.Value, err = l.iri()
+	case '_':
+		v.Kind = BlankNodeView
+		v.Value, err = l.blankNode()
+	case '"':
+		v.Kind = LiteralView
+		err = l.literal(v)
+	default:
+		err = fmt.Errorf("unexpected character %q", l.s[l.pos])
+	}
+	return err
+}
+
+// unescape returns raw, or a copy in scratch with escape sequences resolved
+func (l *viewLexer) unescape(raw []byte) ([]byte, error) {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return raw, nil
+	}
+	start := len(l.scratch)
+	var err error
+	if l.scratch, err = appendUnescaped(l.scratch, raw); err != nil {
+		return nil, err
+	}
+	return l.scratch[start:len(l.scratch):len(l.scratch)], nil
+}
+
+func (l *viewLexer) iri() ([]byte, error) {
+	start := l.pos + 1
+	end := bytes.IndexByte(l.s[start:], '>')
+	if end < 0 {
+		return nil, errors.New("unterminated IRI")
+	}
+	raw := l.s[start : start+end]
+	l.pos = start + end + 1
+	if bytes.ContainsAny(raw, " <\"{}|^`") {
+		return nil, fmt.Errorf("invalid character in IRI <%s>", raw)
+	}
+	return l.unescape(raw)
+}
+
+func (l *viewLexer) blankNode() ([]byte, error) {
+	if !bytes.HasPrefix(l.s[l.pos:], []byte("_:")) {
+		return nil, errors.New("invalid blank node")
+	}
+	start := l.pos + 2
+	end := start
+	for end < len(l.s) && !isTermDelimiter(l.s[end]) {
+		end++
+	}
+	// a label may contain dots, but never ends with one
+	for end > start && l.s[end-1] == '.' {
+		end--
+	}
+	if end == start {
+		return nil, errors.New("empty blank node label")
+	}
+	l.pos = end
+	return l.s[start:end], nil
+}
+
+func (l *viewLexer) literal(v *TermView) error {
+	start := l.pos + 1
+	end := start
+	for ; end < len(l.s); end++ {
+		c := l.s[end]
+		if c == '\\' {
+			end++
+			continue
+		}
+		if c == '"' {
+			break
+		}
+	}
+	if end >= len(l.s) {
+		return errors.New("unterminated literal")
+	}
+	var err error
+	if v.Value, err = l.unescape(l.s[start:end]); err != nil {
+		return err
+	}
+	l.pos = end + 1
+
+	if !l.done() && l.s[l.pos] == '@' {
+		langStart := l.pos + 1
+		langEnd := langStart
+		for langEnd < len(l.s) && (isAlphaNum(l.s[langEnd]) || l.s[langEnd] == '-') {
+			langEnd++
+		}
+		if langEnd == langStart {
+			return errors.New("empty language tag")
+		}
+		l.pos = langEnd
+		v.Language = l.s[langStart:langEnd]
+		return nil
+	}
+	if bytes.HasPrefix(l.s[l.pos:], []byte("^^")) {
+		l.pos += 2
+		if l.done() || l.s[l.pos] != '<' {
+			return errors.New("datatype must be an IRI")
+		}
+		v.Datatype, err = l.iri()
+		return err
+	}
+	return nil
+}
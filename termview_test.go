@@ -0,0 +1,75 @@
+package rdf2go
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanNQuads(t *testing.T) {
+	var quads []*Quad
+	var kinds []TermViewKind
+	err := ScanNQuads(strings.NewReader(simpleNQuads), func(v *QuadView) error {
+		quads = append(quads, v.Quad())
+		kinds = append(kinds, v.Graph.Kind)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(quads))
+	assert.Equal(t, []TermViewKind{NoTermView, IRIView, IRIView, NoTermView}, kinds)
+
+	// views and regular parsing agree
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(simpleNQuads), "application/n-quads"))
+	for _, q := range quads {
+		assert.NotNil(t, d.One(q.Subject, q.Predicate, q.Object, q.Graph), q.String())
+	}
+}
+
+func TestScanNQuadsFilter(t *testing.T) {
+	name := NewResource("http://xmlns.com/foaf/0.1/name")
+	bob := NewLiteralWithLanguage("Bob\t\"B\"é", "en")
+	var kept []*Quad
+	err := ScanNQuads(strings.NewReader(simpleNQuads), func(v *QuadView) error {
+		if v.Predicate.Equal(name) && v.Object.Equal(bob) {
+			kept = append(kept, v.Quad())
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(kept)) {
+		assert.Equal(t, "b1", kept[0].Subject.RawValue())
+	}
+}
+
+func TestScanNQuadsReusesViews(t *testing.T) {
+	var first *QuadView
+	ScanNQuads(strings.NewReader("<a> <b> \"x\" .\n<c> <d> \"y\" .\n"), func(v *QuadView) error {
+		if first == nil {
+			first = v
+		}
+		return nil
+	})
+	// the same view is passed for every statement
+	assert.Equal(t, "c", string(first.Subject.Value))
+}
+
+func TestScanNQuadsErrors(t *testing.T) {
+	err := ScanNQuads(strings.NewReader("<a> <b> <c> .\n<a> <b> .\n"), func(*QuadView) error { return nil })
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	stop := errors.New("stop")
+	err = ScanNQuads(strings.NewReader("<a> <b> <c> .\n"), func(*QuadView) error { return stop })
+	assert.Equal(t, stop, err)
+}
+
+func TestTermViewEqual(t *testing.T) {
+	v := TermView{Kind: LiteralView, Value: []byte("1"), Datatype: []byte(xsdNamespace + "integer")}
+	assert.True(t, v.Equal(NewLiteralWithDatatype("1", NewResource(xsdNamespace+"integer"))))
+	assert.False(t, v.Equal(NewLiteral("1")))
+	assert.False(t, v.Equal(NewResource("1")))
+	assert.True(t, (&TermView{}).Equal(nil))
+}
@@ -0,0 +1,167 @@
+package rdf2go
+
+import (
+	"io"
+)
+
+// TokenType identifies the kind of a Token
+type TokenType int
+
+const (
+	// EOFToken marks the end of the document
+	EOFToken TokenType = TokenType(tokEOF)
+	// IRIToken is an IRI reference, whose Value is the IRI without brackets
+	IRIToken TokenType = TokenType(tokIRI)
+	// PrefixedNameToken is a prefixed name, such as foaf:name
+	PrefixedNameToken TokenType = TokenType(tokPName)
+	// BlankNodeToken is a blank node, whose Value is the label without "_:"
+	BlankNodeToken TokenType = TokenType(tokBlankNode)
+	// VariableToken is a variable of a SPARQL pattern, whose Value is the
+	// name without "?" or "$"
+	VariableToken TokenType = TokenType(tokVariable)
+	// StringToken is the lexical form of a literal, whose Value is the string
+	// without quotes
+	StringToken TokenType = TokenType(tokString)
+	// LangTagToken is a language tag, whose Value is the tag without "@"
+	LangTagToken TokenType = TokenType(tokLangTag)
+	// DatatypeToken is the "^^" marker preceding the datatype of a literal
+	DatatypeToken TokenType = TokenType(tokDatatype)
+	// IntegerToken, DecimalToken and DoubleToken are numeric literals
+	IntegerToken TokenType = TokenType(tokInteger)
+	DecimalToken TokenType = TokenType(tokDecimal)
+	DoubleToken  TokenType = TokenType(tokDouble)
+	// KeywordToken is a bare word, such as a, true, PREFIX or GRAPH
+	KeywordToken TokenType = TokenType(tokKeyword)
+	// DirectiveToken is the @prefix or @base directive, without "@"
+	DirectiveToken TokenType = TokenType(tokDirective)
+	// PunctuationToken is a punctuation mark, such as ".", ";", "[" or "<<"
+	PunctuationToken TokenType = TokenType(tokPunct)
+	// CommentToken is a comment, whose Value is the text following "#". It
+	// is only returned when the Comments field of the Tokenizer is set.
+	CommentToken TokenType = TokenType(tokPunct) + 1
+)
+
+func (t TokenType) String() string {
+	if t == CommentToken {
+		return "Comment"
+	}
+	return tokenKind(t).String()
+}
+
+// Position locates a token in a document. Lines and columns start at 1 and
+// columns count characters, while offsets count bytes from the start of the
+// document.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+// Token is a lexical unit of an N-Triples, N-Quads, Turtle or TriG document
+type Token struct {
+	Type TokenType
+	// Value is the content of the token, with escape sequences resolved
+	Value string
+	// Raw is the text of the token in the document, delimiters included
+	Raw string
+	Pos Position
+}
+
+// Tokenizer splits N-Triples, N-Quads, Turtle and TriG documents into the
+// tokens read by the parsers of the package, for linters, syntax
+// highlighters and editors. The syntaxes share their lexical rules, so a
+// single tokenizer covers all of them. Tokens are read with Next, as with a
+// bufio.Scanner:
+//
+//	tz, err := NewTokenizer(reader)
+//	for tz.Next() {
+//		tok := tz.Token()
+//	}
+//	err = tz.Err()
+type Tokenizer struct {
+	// Comments makes Next return the comments of the document as
+	// CommentToken tokens instead of skipping them
+	Comments bool
+
+	lex *lexer
+	tok Token
+	err error
+	// failed is the position of the token that could not be read
+	failed Position
+}
+
+// NewTokenizer returns a Tokenizer reading the document of reader
+func NewTokenizer(reader io.Reader) (*Tokenizer, error) {
+	src, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokenizer{lex: newLexer(string(src))}, nil
+}
+
+// Next reads the next token, returning false at the end of the document or
+// on a lexical error, which is then returned by Err
+func (t *Tokenizer) Next() bool {
+	if t.err != nil {
+		return false
+	}
+	l := t.lex
+	if t.Comments {
+		for l.pos < len(l.src) && isSpace(l.src[l.pos]) {
+			l.advance(1)
+		}
+		if l.peekByte(0) == '#' {
+			start := Position{Line: l.line, Column: l.column, Offset: l.pos}
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.advance(1)
+			}
+			raw := l.src[start.Offset:l.pos]
+			t.tok = Token{Type: CommentToken, Value: raw[1:], Raw: raw, Pos: start}
+			return true
+		}
+	}
+	tok, err := l.next()
+	pos := Position{Line: tok.line, Column: tok.column, Offset: tok.offset}
+	if err != nil {
+		t.err = err
+		t.failed = pos
+		return false
+	}
+	t.tok = Token{Type: TokenType(tok.kind), Value: tok.value, Raw: l.src[tok.offset:l.pos], Pos: pos}
+	return tok.kind != tokEOF
+}
+
+// Token returns the token read by the last call to Next
+func (t *Tokenizer) Token() Token {
+	return t.tok
+}
+
+// Err returns the lexical error that stopped Next, if any
+func (t *Tokenizer) Err() error {
+	return t.err
+}
+
+// Resume clears the last lexical error and skips the first character of the
+// token that could not be read, so that tokenizing can go on past malformed
+// input, as editors of partial documents need
+func (t *Tokenizer) Resume() {
+	if t.err == nil {
+		return
+	}
+	t.err = nil
+	l := t.lex
+	l.pos, l.line, l.column = t.failed.Offset, t.failed.Line, t.failed.Column
+	l.advance(1)
+	for l.pos < len(l.src) && l.src[l.pos]&0xC0 == 0x80 {
+		l.pos++
+	}
+}
+
+// Position returns the position following the last token read
+func (t *Tokenizer) Position() Position {
+	return Position{Line: t.lex.line, Column: t.lex.column, Offset: t.lex.pos}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,63 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizer(t *testing.T) {
+	tz, err := NewTokenizer(strings.NewReader("@prefix ex: <http://example.org/> . # people\nex:a ex:name \"A\\u00e9\"@fr ."))
+	assert.NoError(t, err)
+	var types []TokenType
+	var toks []Token
+	for tz.Next() {
+		types = append(types, tz.Token().Type)
+		toks = append(toks, tz.Token())
+	}
+	assert.NoError(t, tz.Err())
+	assert.Equal(t, []TokenType{
+		DirectiveToken, PrefixedNameToken, IRIToken, PunctuationToken,
+		PrefixedNameToken, PrefixedNameToken, StringToken, LangTagToken, PunctuationToken,
+	}, types)
+	assert.Equal(t, "http://example.org/", toks[2].Value)
+	assert.Equal(t, "<http://example.org/>", toks[2].Raw)
+	assert.Equal(t, "Aé", toks[6].Value)
+	assert.Equal(t, `"A\u00e9"`, toks[6].Raw)
+	assert.Equal(t, Position{Line: 2, Column: 14, Offset: 58}, toks[6].Pos)
+	assert.Equal(t, "String", StringToken.String())
+}
+
+func TestTokenizerComments(t *testing.T) {
+	tz, err := NewTokenizer(strings.NewReader("# header\n<a> # trailing\n"))
+	assert.NoError(t, err)
+	tz.Comments = true
+	var toks []Token
+	for tz.Next() {
+		toks = append(toks, tz.Token())
+	}
+	if assert.Len(t, toks, 3) {
+		assert.Equal(t, Token{Type: CommentToken, Value: " header", Raw: "# header", Pos: Position{Line: 1, Column: 1}}, toks[0])
+		assert.Equal(t, IRIToken, toks[1].Type)
+		assert.Equal(t, CommentToken, toks[2].Type)
+		assert.Equal(t, "Comment", toks[2].Type.String())
+	}
+}
+
+func TestTokenizerResume(t *testing.T) {
+	tz, err := NewTokenizer(strings.NewReader("<a> ` <b> ."))
+	assert.NoError(t, err)
+	var values []string
+	for {
+		for tz.Next() {
+			values = append(values, tz.Token().Value)
+		}
+		if tz.Err() == nil {
+			break
+		}
+		assert.Contains(t, tz.Err().Error(), "line 1, column 5")
+		tz.Resume()
+	}
+	assert.Equal(t, []string{"a", "b", "."}, values)
+}
@@ -0,0 +1,119 @@
+package rdf2go
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrTransactionDone is returned when committing a transaction that was
+// already committed or rolled back
+var ErrTransactionDone = errors.New("transaction already committed or rolled back")
+
+// Transaction buffers the changes of a dataset until they are applied at
+// once by Commit, or discarded by Rollback. A transaction is not safe for
+// concurrent use, but the dataset may be used meanwhile.
+type Transaction struct {
+	dataset *Dataset
+	ops     []transactionOp
+	done    bool
+}
+
+// transactionOp is a buffered change
+type transactionOp struct {
+	kind ChangeKind
+	quad *Quad
+}
+
+// Begin starts a transaction on the dataset
+func (d *Dataset) Begin() *Transaction {
+	return &Transaction{dataset: d}
+}
+
+// Add buffers the addition of a quad
+func (tx *Transaction) Add(q *Quad) {
+	tx.ops = append(tx.ops, transactionOp{QuadAdded, q})
+}
+
+// AddQuad buffers the addition of a quad made of individual S, P, O, G
+// objects
+func (tx *Transaction) AddQuad(s Term, p Term, o Term, g Term) {
+	tx.Add(NewQuad(s, p, o, g))
+}
+
+// Remove buffers the removal of a quad
+func (tx *Transaction) Remove(q *Quad) {
+	tx.ops = append(tx.ops, transactionOp{QuadRemoved, q})
+}
+
+// Apply buffers a change set, removing quads first as Dataset.Apply does
+func (tx *Transaction) Apply(c *ChangeSet) {
+	for _, quad := range c.Removed {
+		tx.Remove(quad)
+	}
+	for _, quad := range c.Added {
+		tx.Add(quad)
+	}
+}
+
+// Parse buffers the addition of the quads of a document, parsed as
+// Dataset.Parse does. Nothing is buffered when the document is invalid.
+func (tx *Transaction) Parse(reader io.Reader, mime string) error {
+	staged := tx.dataset.empty()
+	if err := staged.Parse(reader, mime); err != nil {
+		return err
+	}
+	for _, quad := range staged.orderedQuads() {
+		tx.Add(quad)
+	}
+	return nil
+}
+
+// Len returns the number of buffered changes
+func (tx *Transaction) Len() int {
+	return len(tx.ops)
+}
+
+// Commit applies the buffered changes in order, holding the lock of a
+// thread-safe dataset so that readers see all of them or none, and returns
+// the changes actually made. Subscriptions and hooks are notified once all
+// the changes are applied.
+func (tx *Transaction) Commit() (*ChangeSet, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+	tx.done = true
+	d := tx.dataset
+	var applied []transactionOp
+	unlock := d.lock()
+	for _, op := range tx.ops {
+		changed := false
+		if op.kind == QuadAdded {
+			changed = d.addUnlocked(op.quad, "")
+		} else {
+			changed = d.removeUnlocked(op.quad)
+		}
+		if changed {
+			applied = append(applied, op)
+		}
+	}
+	unlock()
+
+	c := &ChangeSet{}
+	for _, op := range applied {
+		if op.kind == QuadAdded {
+			c.Added = append(c.Added, op.quad)
+		} else {
+			c.Removed = append(c.Removed, op.quad)
+		}
+		d.notify(op.kind, op.quad)
+	}
+	tx.ops = nil
+	return c, nil
+}
+
+// Rollback discards the buffered changes. It does nothing once the
+// transaction is committed, so that it can be deferred.
+func (tx *Transaction) Rollback() {
+	tx.done = true
+	tx.ops = nil
+}
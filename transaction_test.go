@@ -0,0 +1,57 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	existing := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil)
+	d.Add(existing)
+	notified := 0
+	d.Subscribe(nil, nil, nil, AnyGraph, func(ChangeKind, *Quad) {
+		assert.Equal(t, 2, d.Len())
+		notified++
+	})
+
+	tx := d.Begin()
+	tx.AddQuad(NewResource("a"), NewResource("b"), NewResource("d"), NewResource("g"))
+	tx.Add(existing)
+	tx.Remove(existing)
+	assert.NoError(t, tx.Parse(strings.NewReader(`<http://example.org/s> <http://example.org/p> "o" .`), "text/turtle"))
+	assert.Equal(t, 4, tx.Len())
+	assert.Equal(t, 1, d.Len())
+
+	c, err := tx.Commit()
+	assert.NoError(t, err)
+	assert.Len(t, c.Added, 2)
+	assert.Equal(t, []*Quad{existing}, c.Removed)
+	assert.Equal(t, 2, d.Len())
+	assert.Equal(t, 3, notified)
+
+	_, err = tx.Commit()
+	assert.ErrorIs(t, err, ErrTransactionDone)
+}
+
+func TestTransactionRollback(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true))
+	tx := d.Begin()
+	defer tx.Rollback()
+	tx.AddQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil)
+	assert.Error(t, tx.Parse(strings.NewReader("not turtle"), "text/turtle"))
+	assert.Equal(t, 1, tx.Len())
+	tx.Rollback()
+	assert.Equal(t, 0, tx.Len())
+	_, err := tx.Commit()
+	assert.ErrorIs(t, err, ErrTransactionDone)
+	assert.Equal(t, 0, d.Len())
+
+	tx = d.Begin()
+	tx.Apply(&ChangeSet{Added: []*Quad{NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), nil)}})
+	_, err = tx.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, d.Len())
+}
@@ -0,0 +1,249 @@
+package rdf2go
+
+// parseTrig parses a TriG document, calling emit for every statement with its
+// graph label, nil for the default graph. With turtle set, the document is
+// parsed as Turtle, where graph blocks are not allowed. Both syntaxes accept
+// the quoted triples and annotations of RDF-star.
+func parseTrig(src string, base string, turtle bool, emit func(s Term, p Term, o Term, g Term)) error {
+	_, err := parseTrigPrefixes(src, base, turtle, nil, 0, emit)
+	return err
+}
+
+// parseTrigPrefixes parses a TriG document like parseTrig, returning the
+// prefixes it declares. Unless malformed is nil, malformed statements are
+// passed to it and skipped, none of their triples being emitted. Unless
+// maxDepth is zero, it limits the nesting of terms.
+func parseTrigPrefixes(src string, base string, turtle bool, malformed func(error) error, maxDepth int, emit func(s Term, p Term, o Term, g Term)) (map[string]string, error) {
+	p, err := newSyntaxParser(src, base)
+	p.malformed = malformed
+	p.maxDepth = maxDepth
+	if err != nil {
+		if err := p.skipMalformed(err, false); err != nil {
+			return nil, err
+		}
+	}
+	for p.tok.kind != tokEOF {
+		if p.malformed == nil {
+			if err := p.trigStatement(turtle, emit); err != nil {
+				return p.prefixes, err
+			}
+			continue
+		}
+		// the triples of a statement are emitted once it is complete
+		var pending [][4]Term
+		err := p.trigStatement(turtle, func(s Term, pr Term, o Term, g Term) {
+			pending = append(pending, [4]Term{s, pr, o, g})
+		})
+		if err != nil {
+			if err := p.skipMalformed(err, false); err != nil {
+				return p.prefixes, err
+			}
+			continue
+		}
+		for _, t := range pending {
+			emit(t[0], t[1], t[2], t[3])
+		}
+	}
+	return p.prefixes, nil
+}
+
+// skipMalformed reports the error of a malformed statement to p.malformed,
+// and skips the rest of the statement: the tokens up to the next '.', or
+// the '}' closing the graph block when inBlock is set
+func (p *syntaxParser) skipMalformed(err error, inBlock bool) error {
+	if p.malformed == nil {
+		return err
+	}
+	if err := p.malformed(err); err != nil {
+		return err
+	}
+	if p.lexFailed {
+		p.skipToken()
+	}
+	depth := 0
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.isPunct("[") || p.isPunct("(") || p.isPunct("<<") || p.isPunct("{|"):
+			depth++
+		case p.isPunct("]") || p.isPunct(")") || p.isPunct(">>") || p.isPunct("|}"):
+			depth = max(depth-1, 0)
+		case depth == 0 && p.isPunct("."):
+			p.skipToken()
+			return nil
+		case depth == 0 && inBlock && p.isPunct("}"):
+			return nil
+		}
+		p.skipToken()
+	}
+	return nil
+}
+
+// skipToken reads the next token, skipping the characters that cannot be
+// read as one
+func (p *syntaxParser) skipToken() {
+	for p.advance() != nil {
+		p.lex.advance(1)
+	}
+}
+
+// trigStatement parses a directive, a graph block or a statement of the
+// default graph
+func (p *syntaxParser) trigStatement(turtle bool, emit func(s Term, p Term, o Term, g Term)) error {
+	inGraph := func(g Term) func(s Term, pr Term, o Term) {
+		return func(s Term, pr Term, o Term) {
+			emit(s, pr, o, g)
+		}
+	}
+
+	switch {
+	case p.tok.kind == tokDirective:
+		directive := p.tok.value
+		if err := p.advance(); err != nil {
+			return err
+		}
+		var err error
+		if directive == "prefix" {
+			err = p.prefixDecl()
+		} else {
+			err = p.baseDecl()
+		}
+		if err != nil {
+			return err
+		}
+		return p.expectPunct(".")
+	case p.tok.isKeyword("PREFIX"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		return p.prefixDecl()
+	case p.tok.isKeyword("BASE"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		return p.baseDecl()
+	case !turtle && p.tok.isKeyword("GRAPH"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		label, err := p.graphLabel()
+		if err != nil {
+			return err
+		}
+		return p.graphBlock(inGraph(label))
+	case !turtle && p.isPunct("{"):
+		return p.graphBlock(inGraph(nil))
+	}
+
+	// a statement of the default graph, or the label of a graph block
+	if p.isPunct("[") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.isPunct("]") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			label := p.anonNode()
+			if !turtle && p.isPunct("{") {
+				return p.graphBlock(inGraph(label))
+			}
+			if err := p.predicateObjectList(label, inGraph(nil)); err != nil {
+				return err
+			}
+			return p.expectPunct(".")
+		}
+		// the property list of a blank node subject
+		subject := p.anonNode()
+		if err := p.predicateObjectList(subject, inGraph(nil)); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+		if !p.isPunct(".") {
+			if err := p.predicateObjectList(subject, inGraph(nil)); err != nil {
+				return err
+			}
+		}
+		return p.expectPunct(".")
+	}
+	label := !turtle && (p.tok.kind == tokIRI || p.tok.kind == tokPName || p.tok.kind == tokBlankNode)
+	subject, err := p.subject(inGraph(nil))
+	if err != nil {
+		return err
+	}
+	if label && p.isPunct("{") {
+		return p.graphBlock(inGraph(subject))
+	}
+	if err := p.predicateObjectList(subject, inGraph(nil)); err != nil {
+		return err
+	}
+	return p.expectPunct(".")
+}
+
+// graphLabel parses the label of a graph block, an IRI or a blank node
+func (p *syntaxParser) graphLabel() (Term, error) {
+	switch {
+	case p.tok.kind == tokBlankNode:
+		node := p.blankNode(p.tok.value)
+		return node, p.advance()
+	case p.isPunct("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.anonNode(), p.expectPunct("]")
+	}
+	return p.iriTerm()
+}
+
+// graphBlock parses { triples . ... }, the final '.' being optional
+func (p *syntaxParser) graphBlock(emit func(s Term, pr Term, o Term)) error {
+	if err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for !p.isPunct("}") {
+		if p.malformed != nil {
+			if err := p.lenientTriples(emit); err != nil {
+				return err
+			}
+			if p.tok.kind == tokEOF {
+				return p.expectPunct("}")
+			}
+			continue
+		}
+		if err := p.triples(emit); err != nil {
+			return err
+		}
+		if !p.isPunct(".") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	return p.expectPunct("}")
+}
+
+// lenientTriples parses the triples of a graph block up to the '.' ending
+// them, emitting them once complete, or skips them when malformed
+func (p *syntaxParser) lenientTriples(emit func(s Term, pr Term, o Term)) error {
+	var pending [][3]Term
+	err := p.triples(func(s Term, pr Term, o Term) {
+		pending = append(pending, [3]Term{s, pr, o})
+	})
+	if err == nil && !p.isPunct(".") && !p.isPunct("}") {
+		err = p.errorf("expected '.' or '}' but found %s", p.tok)
+	}
+	if err != nil {
+		return p.skipMalformed(err, true)
+	}
+	for _, t := range pending {
+		emit(t[0], t[1], t[2])
+	}
+	if p.isPunct(".") {
+		if err := p.advance(); err != nil {
+			return p.skipMalformed(err, true)
+		}
+	}
+	return nil
+}
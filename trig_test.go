@@ -0,0 +1,115 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const trigStar = `@prefix ex: <http://example.org/> .
+PREFIX dc: <http://purl.org/dc/terms/>
+
+ex:alice ex:name "Alice" .
+
+GRAPH ex:g1 {
+  << ex:alice ex:knows ex:bob >> dc:source ex:survey ;
+    ex:certainty 0.9 .
+  ex:bob ex:age 42 {| dc:source << _:b ex:says "so" >> |} .
+}
+
+_:g2 {
+  ex:carol ex:says << ex:bob ex:knows << ex:alice ex:knows ex:carol >> >>
+}`
+
+func TestDatasetParseTrigStar(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(trigStar), "application/trig"))
+	assert.Equal(t, 6, d.Len())
+
+	ex := func(name string) Term { return NewResource("http://example.org/" + name) }
+	knows := NewQuotedTriple(ex("alice"), ex("knows"), ex("bob"))
+	g1 := ex("g1")
+	assert.Len(t, d.All(knows, nil, nil, g1), 2)
+	assert.Len(t, d.All(ex("bob"), ex("age"), nil, g1), 1)
+	annotation := d.One(NewQuotedTriple(ex("bob"), ex("age"), NewLiteralWithDatatype("42", NewResource(xsdNamespace+"integer"))), nil, nil, g1)
+	if assert.NotNil(t, annotation) {
+		quoted := annotation.Object.(*QuotedTriple)
+		assert.IsType(t, &BlankNode{}, quoted.Subject)
+	}
+
+	var nested *Quad
+	for quad := range d.Quads() {
+		if quad.Subject.Equal(ex("carol")) {
+			nested = quad
+		}
+	}
+	if assert.NotNil(t, nested) {
+		assert.IsType(t, &BlankNode{}, nested.Graph)
+		assert.Equal(t, "<< <http://example.org/bob> <http://example.org/knows> << <http://example.org/alice> <http://example.org/knows> <http://example.org/carol> >> >>", nested.Object.String())
+	}
+}
+
+func TestDatasetTrigStarRoundTrip(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	assert.NoError(t, d.Parse(strings.NewReader(trigStar), "application/trig"))
+
+	for _, mime := range []string{"application/trig", "application/n-quads"} {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, d.Serialize(buf, mime))
+		d2 := NewDataset(testDatasetUri)
+		assert.NoError(t, d2.Parse(strings.NewReader(buf.String()), mime), mime)
+		changes, err := Diff(d, d2)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, changes.Len(), buf.String())
+	}
+}
+
+func TestParseNQuadsStar(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	src := `<< <http://example.org/a> <http://example.org/b> "c"@en >> <http://example.org/p> << _:x <http://example.org/q> _:y >> <http://example.org/g> .`
+	assert.NoError(t, d.Parse(strings.NewReader(src), "application/n-quads"))
+	quad := d.One(nil, nil, nil, NewResource("http://example.org/g"))
+	if assert.NotNil(t, quad) {
+		assert.Equal(t, NewQuotedTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteralWithLanguage("c", "en")), quad.Subject)
+		assert.Equal(t, NewQuotedTriple(NewBlankNode("x"), NewResource("http://example.org/q"), NewBlankNode("y")), quad.Object)
+	}
+
+	for _, src := range []string{
+		`<< "a" <http://example.org/b> <http://example.org/c> >> <http://example.org/p> <http://example.org/o> .`,
+		`<< <http://example.org/a> _:b <http://example.org/c> >> <http://example.org/p> <http://example.org/o> .`,
+		`<< <http://example.org/a> <http://example.org/b> <http://example.org/c> <http://example.org/p> <http://example.org/o> .`,
+	} {
+		assert.Error(t, NewDataset(testDatasetUri).Parse(strings.NewReader(src), "application/n-quads"), src)
+	}
+}
+
+func TestParseTrigErrors(t *testing.T) {
+	for _, src := range []string{
+		`<http://example.org/g> { <http://example.org/a> <http://example.org/b> }`,
+		`{ <http://example.org/a> <http://example.org/b> <http://example.org/c> `,
+		`"g" { <http://example.org/a> <http://example.org/b> <http://example.org/c> }`,
+		`<< [ <http://example.org/b> <http://example.org/c> ] <http://example.org/b> <http://example.org/c> >> <http://example.org/p> <http://example.org/o> .`,
+		`<http://example.org/a> <http://example.org/b> <http://example.org/c> {| <http://example.org/p> <http://example.org/o> .`,
+	} {
+		assert.Error(t, NewDataset(testDatasetUri).Parse(strings.NewReader(src), "application/trig"), src)
+	}
+}
+
+func TestGraphTurtleStar(t *testing.T) {
+	g := NewGraph(testUri)
+	src := `@prefix ex: <http://example.org/> .
+ex:alice ex:knows ex:bob {| ex:since 2020 |} .`
+	assert.NoError(t, g.Parse(strings.NewReader(src), "text/turtle"))
+	assert.Equal(t, 2, g.Len())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Contains(t, buf.String(), "<< <http://example.org/alice> <http://example.org/knows> <http://example.org/bob> >>\n  <http://example.org/since> ")
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+	assert.Equal(t, 2, g2.Len())
+
+	assert.Error(t, g.Serialize(buf, "application/ld+json"))
+}
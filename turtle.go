@@ -0,0 +1,333 @@
+package rdf2go
+
+import (
+	"strings"
+)
+
+// SerializeOptions controls the layout of Turtle and TriG output, so that
+// generated files can follow formatting conventions and produce stable
+// diffs. Statements are always written in a deterministic order. Comments
+// also apply to N-Triples and N-Quads output.
+type SerializeOptions struct {
+	// LineWidth is the length after which the objects of a predicate are
+	// wrapped onto continuation lines, zero meaning no wrapping
+	LineWidth int
+	// Indent is the number of spaces per indentation level, two by default
+	Indent int
+	// ObjectPerLine writes every object of a predicate on its own line
+	ObjectPerLine bool
+	// Comments introduces the output with the IRI of the source document,
+	// and each graph and subject with a comment. N-Triples and N-Quads
+	// output is then sorted, to keep the statements of a graph, and of a
+	// subject, together.
+	Comments bool
+	// RelativeIRIs declares the base IRI of the configuration, or else the
+	// URI of the graph or dataset, with @base in Turtle and TriG output, and
+	// writes the IRIs that are not abbreviated by a prefix relative to it
+	RelativeIRIs bool
+}
+
+// WithSerializeOptions sets the layout of Turtle and TriG output
+func WithSerializeOptions(opts SerializeOptions) Option {
+	return func(c *Config) {
+		c.Serialize = opts
+	}
+}
+
+// serializeBase returns the base IRI of the Turtle or TriG output of the
+// document named source, "" unless IRIs are written relative to it
+func (c *Config) serializeBase(source string) string {
+	if base := c.base(source); c.Serialize.RelativeIRIs && isAbsoluteIRI(base) {
+		return base
+	}
+	return ""
+}
+
+func (o SerializeOptions) indent() string {
+	if o.Indent <= 0 {
+		return "  "
+	}
+	return strings.Repeat(" ", o.Indent)
+}
+
+// sourceComment returns the comment naming the source document of the
+// output, if it has an IRI
+func sourceComment(uri string) string {
+	if len(uri) == 0 {
+		return ""
+	}
+	return "# source: <" + uri + ">\n\n"
+}
+
+// graphComment returns the comment introducing the statements of a graph
+func graphComment(graph Term) string {
+	if graph == nil {
+		return "# graph: default\n"
+	}
+	return "# graph: " + encodeTerm(graph) + "\n"
+}
+
+// writeTurtleTriples lays out triples in Turtle syntax, one block per
+// subject, each line prefixed with margin and terms written with encode. The
+// final line has no newline.
+//
+// Blank nodes that are the object of a single statement are written in
+// place, as [ ... ] property lists or as ( ... ) when they are the head of a
+// well-formed rdf:List. Those listed in labeled, and those of quoted triples,
+// keep their label.
+func writeTurtleTriples(b *strings.Builder, triples []*Triple, margin string, opts SerializeOptions, encode func(Term) string, labeled map[string]bool) {
+	quads := make([]*Quad, len(triples))
+	for i, t := range triples {
+		quads[i] = NewTripleQuad(t)
+	}
+	// grouping relies on the order of sortQuads, which keeps the statements
+	// sharing a subject, and a predicate, together
+	sortQuads(quads)
+	w := newTurtleWriter(quads, encode, labeled)
+
+	indent := opts.indent()
+	first := true
+	for _, subject := range w.order {
+		if w.inline[subject] {
+			continue
+		}
+		if !first {
+			b.WriteByte('\n')
+		}
+		first = false
+		statements := w.subjects[subject]
+		name := encode(statements[0].Subject)
+		if opts.Comments {
+			b.WriteString(margin + "# subject: " + name + "\n")
+		}
+		b.WriteString(margin + name + "\n")
+		w.predicateObjects(statements, func(predicate string, objects []string, last bool) {
+			writeTurtleObjects(b, margin+indent, predicate, objects, opts)
+			if last {
+				b.WriteString(" .")
+			} else {
+				b.WriteString(" ;\n")
+			}
+		})
+	}
+}
+
+// turtleWriter holds the statements of a Turtle block, and the blank nodes
+// written in place of their label
+type turtleWriter struct {
+	encode func(Term) string
+	// subjects holds the sorted statements of each subject key, and order
+	// the subject keys in the order of the statements
+	subjects map[string][]*Quad
+	order    []string
+	// inline lists the blank nodes written in place, and lists those of them
+	// written as collections, with their elements
+	inline map[string]bool
+	lists  map[string][]Term
+}
+
+func newTurtleWriter(quads []*Quad, encode func(Term) string, labeled map[string]bool) *turtleWriter {
+	w := &turtleWriter{
+		encode:   encode,
+		subjects: make(map[string][]*Quad),
+		inline:   make(map[string]bool),
+		lists:    make(map[string][]Term),
+	}
+	refs := make(map[string]int)
+	referrers := make(map[string]*Quad)
+	pinned := make(map[string]bool)
+	for _, q := range quads {
+		k := termKey(q.Subject)
+		if _, ok := w.subjects[k]; !ok {
+			w.order = append(w.order, k)
+		}
+		w.subjects[k] = append(w.subjects[k], q)
+		if _, ok := q.Object.(*BlankNode); ok {
+			refs[termKey(q.Object)]++
+			referrers[termKey(q.Object)] = q
+		}
+		for _, t := range []Term{q.Subject, q.Object} {
+			if _, ok := t.(*QuotedTriple); ok {
+				for _, id := range blankNodeIDs(nil, t) {
+					pinned["_:"+id] = true
+				}
+			}
+		}
+	}
+	for k, n := range refs {
+		if n == 1 && !pinned[k] && !labeled[strings.TrimPrefix(k, "_:")] {
+			w.inline[k] = true
+		}
+	}
+
+	for k := range w.inline {
+		if elements, ok := w.list(k); ok {
+			w.lists[k] = elements
+		}
+	}
+	// the rest of a list is a list too, written as part of its head
+	rest := NewResource(rdfNamespace + "rest")
+	var tails []string
+	for k := range w.lists {
+		if q := referrers[k]; q.Predicate.Equal(rest) {
+			if _, ok := w.lists[termKey(q.Subject)]; ok {
+				tails = append(tails, k)
+			}
+		}
+	}
+	for _, k := range tails {
+		delete(w.lists, k)
+	}
+	w.unlink()
+	return w
+}
+
+// list returns the elements of the well-formed rdf:List starting at a blank
+// node, whose cells are only referenced by the list
+func (w *turtleWriter) list(head string) ([]Term, bool) {
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	nilList := NewResource(rdfNamespace + "nil")
+	var elements []Term
+	seen := make(map[string]bool)
+	for k := head; ; {
+		statements := w.subjects[k]
+		if seen[k] || len(statements) != 2 || !statements[0].Predicate.Equal(first) || !statements[1].Predicate.Equal(rest) {
+			return nil, false
+		}
+		seen[k] = true
+		elements = append(elements, statements[0].Object)
+		next := statements[1].Object
+		if next.Equal(nilList) {
+			return elements, true
+		}
+		if _, ok := next.(*BlankNode); !ok || !w.inline[termKey(next)] {
+			return nil, false
+		}
+		k = termKey(next)
+	}
+}
+
+// unlink gives back their label to the blank nodes which cannot be reached
+// from a block, as they only refer to each other
+func (w *turtleWriter) unlink() {
+	reached := make(map[string]bool)
+	var visit func(t Term)
+	visit = func(t Term) {
+		k := termKey(t)
+		if !w.inline[k] || reached[k] {
+			return
+		}
+		reached[k] = true
+		if elements, ok := w.lists[k]; ok {
+			for cell := t; !cell.Equal(NewResource(rdfNamespace + "nil")); cell = w.subjects[termKey(cell)][1].Object {
+				reached[termKey(cell)] = true
+			}
+			for _, e := range elements {
+				visit(e)
+			}
+			return
+		}
+		for _, q := range w.subjects[k] {
+			visit(q.Object)
+		}
+	}
+	roots := func(keys []string) {
+		for _, k := range keys {
+			for _, q := range w.subjects[k] {
+				visit(q.Object)
+			}
+		}
+	}
+	var blocks []string
+	for _, k := range w.order {
+		if !w.inline[k] {
+			blocks = append(blocks, k)
+		}
+	}
+	roots(blocks)
+	for {
+		var unreached []string
+		for _, k := range w.order {
+			if w.inline[k] && !reached[k] {
+				unreached = append(unreached, k)
+			}
+		}
+		if len(unreached) == 0 {
+			return
+		}
+		delete(w.inline, unreached[0])
+		delete(w.lists, unreached[0])
+		roots(unreached[:1])
+	}
+}
+
+// predicateObjects calls fn for every predicate of the statements of a
+// subject, with its objects written in Turtle syntax
+func (w *turtleWriter) predicateObjects(statements []*Quad, fn func(predicate string, objects []string, last bool)) {
+	for i := 0; i < len(statements); {
+		predicate := w.encode(statements[i].Predicate)
+		var objects []string
+		for ; i < len(statements) && w.encode(statements[i].Predicate) == predicate; i++ {
+			objects = append(objects, w.object(statements[i].Object))
+		}
+		fn(predicate, objects, i == len(statements))
+	}
+}
+
+// object writes an object in Turtle syntax, in place for inline blank nodes
+func (w *turtleWriter) object(t Term) string {
+	k := termKey(t)
+	if !w.inline[k] {
+		return w.encode(t)
+	}
+	if elements, ok := w.lists[k]; ok {
+		items := make([]string, len(elements))
+		for i, e := range elements {
+			items[i] = w.object(e)
+		}
+		return "( " + strings.Join(items, " ") + " )"
+	}
+	statements := w.subjects[k]
+	if len(statements) == 0 {
+		return "[]"
+	}
+	var b strings.Builder
+	b.WriteString("[ ")
+	w.predicateObjects(statements, func(predicate string, objects []string, last bool) {
+		b.WriteString(predicate + " " + strings.Join(objects, " , "))
+		if !last {
+			b.WriteString(" ; ")
+		}
+	})
+	b.WriteString(" ]")
+	return b.String()
+}
+
+// writeTurtleObjects writes a predicate and its object list, wrapping the
+// objects onto continuation lines indented by one more level
+func writeTurtleObjects(b *strings.Builder, margin string, predicate string, objects []string, opts SerializeOptions) {
+	continuation := margin + opts.indent()
+	line := margin + predicate
+	for i, o := range objects {
+		sep := " , "
+		if i == 0 {
+			sep = " "
+		}
+		// two more characters end the line with a comma, semicolon or dot
+		tooLong := opts.LineWidth > 0 && len(line)+len(sep)+len(o)+2 > opts.LineWidth
+		switch {
+		case i == 0 && tooLong && len(line) > len(margin):
+			b.WriteString(line + "\n")
+			line = continuation + o
+		case i == 0:
+			line += " " + o
+		case opts.ObjectPerLine || tooLong:
+			b.WriteString(line + " ,\n")
+			line = continuation + o
+		default:
+			line += " , " + o
+		}
+	}
+	b.WriteString(line)
+}
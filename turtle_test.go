@@ -0,0 +1,253 @@
+package rdf2go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLayoutGraph(opts ...Option) *Graph {
+	g := NewGraphWithOptions(testUri, opts...)
+	a := NewResource("http://example.org/a")
+	knows := NewResource("http://example.org/knows")
+	for _, name := range []string{"bob", "carol", "dave"} {
+		g.AddTriple(a, knows, NewResource("http://example.org/"+name))
+	}
+	g.AddTriple(a, NewResource("http://example.org/name"), NewLiteral("A"))
+	g.AddTriple(NewResource("http://example.org/b"), NewResource("http://example.org/name"), NewLiteral("B"))
+	return g
+}
+
+func TestSerializeTurtleLayout(t *testing.T) {
+	for _, test := range []struct {
+		opts     SerializeOptions
+		expected string
+	}{
+		{SerializeOptions{}, `<http://example.org/a>
+  <http://example.org/knows> <http://example.org/bob> , <http://example.org/carol> , <http://example.org/dave> ;
+  <http://example.org/name> "A" .
+<http://example.org/b>
+  <http://example.org/name> "B" .`},
+		{SerializeOptions{LineWidth: 80, Indent: 4}, `<http://example.org/a>
+    <http://example.org/knows> <http://example.org/bob> ,
+        <http://example.org/carol> , <http://example.org/dave> ;
+    <http://example.org/name> "A" .
+<http://example.org/b>
+    <http://example.org/name> "B" .`},
+		{SerializeOptions{ObjectPerLine: true}, `<http://example.org/a>
+  <http://example.org/knows> <http://example.org/bob> ,
+    <http://example.org/carol> ,
+    <http://example.org/dave> ;
+  <http://example.org/name> "A" .
+<http://example.org/b>
+  <http://example.org/name> "B" .`},
+		{SerializeOptions{LineWidth: 40}, `<http://example.org/a>
+  <http://example.org/knows>
+    <http://example.org/bob> ,
+    <http://example.org/carol> ,
+    <http://example.org/dave> ;
+  <http://example.org/name> "A" .
+<http://example.org/b>
+  <http://example.org/name> "B" .`},
+	} {
+		g := newLayoutGraph(WithSerializeOptions(test.opts))
+		buf := new(bytes.Buffer)
+		assert.NoError(t, g.Serialize(buf, "text/turtle"))
+		assert.Equal(t, test.expected, buf.String())
+
+		g2 := NewGraph(testUri)
+		assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+		assert.Equal(t, g.Len(), g2.Len())
+	}
+}
+
+func TestSerializeTrigLayout(t *testing.T) {
+	opts := SerializeOptions{ObjectPerLine: true, Indent: 4}
+	g := newLayoutGraph(WithSerializeOptions(opts))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "application/trig"))
+	assert.Contains(t, buf.String(), "{\n    <http://example.org/a>\n        <http://example.org/knows> <http://example.org/bob> ,\n            <http://example.org/carol> ,\n")
+
+	d := NewDatasetWithOptions(testDatasetUri, WithSerializeOptions(opts))
+	for triple := range g.Triples() {
+		d.AddQuad(triple.Subject, triple.Predicate, triple.Object, NewResource("http://example.org/g2"))
+		d.AddQuad(triple.Subject, triple.Predicate, triple.Object, NewResource("http://example.org/g1"))
+	}
+	d.AddTriple(NewResource("http://example.org/c"), NewResource("http://example.org/name"), NewLiteral("C"))
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "{\n    <http://example.org/c>\n        <http://example.org/name> \"C\" .\n}\n\n<http://example.org/g1> {\n"), out)
+	assert.Less(t, strings.Index(out, "<http://example.org/g1> {"), strings.Index(out, "<http://example.org/g2> {"))
+
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(out), "application/trig"))
+	assert.Equal(t, d.Len(), d2.Len())
+}
+
+func TestSerializeComments(t *testing.T) {
+	opts := WithSerializeOptions(SerializeOptions{Comments: true})
+	g := newLayoutGraph(opts)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.True(t, strings.HasPrefix(buf.String(), "# source: <"+testUri+">\n\n# subject: <http://example.org/a>\n<http://example.org/a>\n"), buf.String())
+	assert.Contains(t, buf.String(), " .\n# subject: <http://example.org/b>\n<http://example.org/b>\n")
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+	assert.Equal(t, g.Len(), g2.Len())
+
+	buf.Reset()
+	assert.NoError(t, g.Serialize(buf, "application/n-triples"))
+	assert.Equal(t, `# source: <https://example.org>
+
+# subject: <http://example.org/a>
+<http://example.org/a> <http://example.org/knows> <http://example.org/bob> .
+<http://example.org/a> <http://example.org/knows> <http://example.org/carol> .
+<http://example.org/a> <http://example.org/knows> <http://example.org/dave> .
+<http://example.org/a> <http://example.org/name> "A" .
+# subject: <http://example.org/b>
+<http://example.org/b> <http://example.org/name> "B" .
+`, buf.String())
+
+	d := NewDatasetWithOptions(testDatasetUri, opts)
+	d.AddQuad(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteral("A"), NewResource("http://example.org/g"))
+	d.AddTriple(NewResource("http://example.org/b"), NewResource("http://example.org/name"), NewLiteral("B"))
+	d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/name"), NewLiteral("A"))
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/n-quads"))
+	assert.Equal(t, `# source: <https://example.org/dataset>
+
+# graph: default
+# subject: <http://example.org/a>
+<http://example.org/a> <http://example.org/name> "A" .
+# subject: <http://example.org/b>
+<http://example.org/b> <http://example.org/name> "B" .
+
+# graph: <http://example.org/g>
+# subject: <http://example.org/a>
+<http://example.org/a> <http://example.org/name> "A" <http://example.org/g> .
+`, buf.String())
+	d2 := NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(buf.String()), "application/n-quads"))
+	assert.Equal(t, d.Len(), d2.Len())
+
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	assert.True(t, strings.HasPrefix(buf.String(), "# source: <"+testDatasetUri+">\n\n# graph: default\n{\n  # subject: <http://example.org/a>\n"), buf.String())
+	assert.Contains(t, buf.String(), "}\n\n# graph: <http://example.org/g>\n<http://example.org/g> {\n")
+	d2 = NewDataset(testDatasetUri)
+	assert.NoError(t, d2.Parse(strings.NewReader(buf.String()), "application/trig"))
+	assert.Equal(t, d.Len(), d2.Len())
+}
+
+func TestSerializeTurtleInline(t *testing.T) {
+	ex := func(name string) Term { return NewResource("http://example.org/" + name) }
+	first, rest, nilList := NewResource(rdfNamespace+"first"), NewResource(rdfNamespace+"rest"), NewResource(rdfNamespace+"nil")
+	g := NewGraph(testUri)
+	g.AddTriple(ex("a"), ex("address"), NewBlankNode("addr"))
+	g.AddTriple(NewBlankNode("addr"), ex("city"), NewLiteral("Paris"))
+	g.AddTriple(NewBlankNode("addr"), ex("zip"), NewLiteral("75001"))
+	g.AddTriple(ex("a"), ex("empty"), NewBlankNode("z"))
+	g.AddTriple(ex("a"), ex("tags"), NewBlankNode("l1"))
+	g.AddTriple(NewBlankNode("l1"), first, NewLiteral("x"))
+	g.AddTriple(NewBlankNode("l1"), rest, NewBlankNode("l2"))
+	g.AddTriple(NewBlankNode("l2"), first, NewBlankNode("e"))
+	g.AddTriple(NewBlankNode("l2"), rest, nilList)
+	g.AddTriple(NewBlankNode("e"), ex("n"), NewLiteral("1"))
+	g.AddTriple(NewBlankNode("s1"), ex("p"), NewBlankNode("s2"))
+	g.AddTriple(NewBlankNode("s2"), ex("p"), NewBlankNode("s1"))
+	g.AddTriple(ex("b"), ex("knows"), NewBlankNode("shared"))
+	g.AddTriple(ex("c"), ex("knows"), NewBlankNode("shared"))
+	g.AddTriple(NewBlankNode("shared"), ex("name"), NewLiteral("S"))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Equal(t, `<http://example.org/a>
+  <http://example.org/address> [ <http://example.org/city> "Paris" ; <http://example.org/zip> "75001" ] ;
+  <http://example.org/empty> [] ;
+  <http://example.org/tags> ( "x" [ <http://example.org/n> "1" ] ) .
+<http://example.org/b>
+  <http://example.org/knows> _:shared .
+<http://example.org/c>
+  <http://example.org/knows> _:shared .
+_:s1
+  <http://example.org/p> [ <http://example.org/p> _:s1 ] .
+_:shared
+  <http://example.org/name> "S" .`, buf.String())
+
+	g2 := NewGraph(testUri)
+	assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+	added, removed, err := DiffGraphs(g, g2)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+	// a blank node shared by two graphs of a dataset keeps its label
+	d := NewDataset(testDatasetUri)
+	d.AddQuad(ex("a"), ex("knows"), NewBlankNode("x"), ex("g1"))
+	d.AddQuad(NewBlankNode("x"), ex("name"), NewLiteral("X"), ex("g2"))
+	d.AddQuad(ex("a"), ex("likes"), NewBlankNode("y"), ex("g2"))
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	assert.Contains(t, buf.String(), "<http://example.org/knows> _:x .")
+	assert.Contains(t, buf.String(), "<http://example.org/likes> [] .")
+}
+
+func TestRelativeIRI(t *testing.T) {
+	base := "http://example.org/data/people.ttl"
+	for iri, expected := range map[string]string{
+		"http://example.org/data/people.ttl":       "",
+		"http://example.org/data/people.ttl#alice": "#alice",
+		"http://example.org/data/bob":              "bob",
+		"http://example.org/data/":                 "./",
+		"http://example.org/data/a:b":              "./a:b",
+		"http://example.org/data/sub/c?x=1":        "sub/c?x=1",
+		"http://example.org/other":                 "/other",
+		"https://example.org/data/bob":             "https://example.org/data/bob",
+		"http://example.com/data/bob":              "http://example.com/data/bob",
+		"urn:isbn:123":                             "urn:isbn:123",
+	} {
+		rel := relativeIRI(base, iri)
+		assert.Equal(t, expected, rel, iri)
+		assert.Equal(t, iri, resolveIRI(base, rel), iri)
+	}
+	assert.Equal(t, "http://example.org/a", relativeIRI("urn:x", "http://example.org/a"))
+	assert.Equal(t, "#a", relativeIRI("http://example.org/doc#frag", "http://example.org/doc#a"))
+}
+
+func TestSerializeRelativeIRIs(t *testing.T) {
+	opts := WithSerializeOptions(SerializeOptions{RelativeIRIs: true})
+	g := NewGraphWithOptions("http://example.org/data/people", opts)
+	assert.NoError(t, g.Bind("foaf", FOAF.IRI()))
+	g.AddTriple(NewResource("http://example.org/data/people#alice"), FOAF.Get("knows"), NewResource("http://example.org/data/bob"))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Serialize(buf, "text/turtle"))
+	assert.Equal(t, "@base <http://example.org/data/people> .\n@prefix foaf: <http://xmlns.com/foaf/0.1/> .\n\n<#alice>\n  foaf:knows <bob> .", buf.String())
+	g2 := NewGraph("http://example.org/elsewhere")
+	assert.NoError(t, g2.Parse(strings.NewReader(buf.String()), "text/turtle"))
+	assert.NotNil(t, g2.One(NewResource("http://example.org/data/people#alice"), FOAF.Get("knows"), NewResource("http://example.org/data/bob")))
+
+	g = NewGraphWithOptions("http://example.org/data/people", opts, WithBase("http://example.org/"))
+	g.AddTriple(NewResource("http://example.org/data/people#alice"), FOAF.Get("knows"), NewResource("http://example.org/data/bob"))
+	buf.Reset()
+	assert.NoError(t, g.Serialize(buf, "application/trig"))
+	assert.Contains(t, buf.String(), "@base <http://example.org/> .\n")
+	assert.Contains(t, buf.String(), "<data/people#alice>\n")
+
+	d := NewDatasetWithOptions("http://example.org/data/", opts)
+	d.AddQuad(NewResource("http://example.org/data/a"), FOAF.Get("knows"), NewResource("http://example.org/data/b"), NewResource("http://example.org/data/g"))
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/trig"))
+	assert.Contains(t, buf.String(), "@base <http://example.org/data/> .\n")
+	assert.Contains(t, buf.String(), "<g> {\n  <a>\n")
+	d2 := NewDatasetWithOptions("urn:x", WithBase("http://example.org/data/"))
+	assert.NoError(t, d2.Parse(strings.NewReader(strings.Replace(buf.String(), "@base <http://example.org/data/> .\n", "", 1)), "application/trig"))
+	assert.Equal(t, 1, d2.Len())
+	assert.NotNil(t, d2.One(NewResource("http://example.org/data/a"), nil, nil, NewResource("http://example.org/data/g")))
+
+	buf.Reset()
+	assert.NoError(t, d.Serialize(buf, "application/n-quads"))
+	assert.NotContains(t, buf.String(), "@base")
+}
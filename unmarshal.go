@@ -0,0 +1,322 @@
+package rdf2go
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Unmarshal populates a struct, through a pointer to it, from the description
+// of subject in g. It reads the fields tagged like those of Marshal: the
+// field tagged `rdf:"@id"` receives the subject, and the fields tagged with a
+// predicate receive the objects of the statements of the subject with that
+// predicate. Fields whose predicate has no object are left untouched.
+//
+// The objects are converted to the type of the field:
+//
+//   - strings receive the lexical forms of literals and the IRIs of
+//     resources. When a string field has several objects, the literal with
+//     the language of the lang option is preferred, then one whose language
+//     is a subtag of it, then one without a language.
+//   - booleans and numbers are parsed from the lexical forms of literals,
+//     time.Time values from xsd:dateTime and xsd:date literals, byte slices
+//     from xsd:base64Binary literals, and the values implementing
+//     encoding.TextUnmarshaler from any lexical form
+//   - Term and interface{} fields receive the terms themselves, and url.URL
+//     values the IRIs of resources
+//   - structs are populated from the description of the object. A node
+//     reached through several struct pointers is unmarshaled once, so cycles
+//     in the graph give cycles of pointers.
+//   - slices receive every object, or the elements of an rdf:List with the
+//     list option
+func Unmarshal(g *Graph, subject Term, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T, which is not a pointer to a struct", v)
+	}
+	u := &unmarshaler{
+		graph: g,
+		nodes: make(map[unmarshaledNode]reflect.Value),
+		busy:  make(map[unmarshaledNode]bool),
+	}
+	u.nodes[unmarshaledNode{subject.String(), rv.Type()}] = rv
+	return u.node(subject, rv.Elem())
+}
+
+// unmarshaledNode identifies the struct populated from a node
+type unmarshaledNode struct {
+	node string
+	typ  reflect.Type
+}
+
+type unmarshaler struct {
+	graph *Graph
+	// nodes holds the pointers to the structs populated from a node
+	nodes map[unmarshaledNode]reflect.Value
+	// busy holds the struct values being populated from a node
+	busy map[unmarshaledNode]bool
+}
+
+// node populates the fields of a struct from the description of a subject
+func (u *unmarshaler) node(subject Term, v reflect.Value) error {
+	fields, err := rdfFields(v.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		var objects []Term
+		if !f.id {
+			for _, triple := range u.graph.All(subject, f.predicate, nil) {
+				objects = append(objects, triple.Object)
+			}
+			if len(objects) == 0 {
+				continue
+			}
+		}
+		field := fieldByIndex(v, f.index)
+		if f.id {
+			err = unmarshalID(subject, field)
+		} else {
+			err = u.objects(objects, field, f)
+		}
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns a nested field of a struct, allocating the embedded
+// structs it is reached through
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// unmarshalID sets an @id field to the subject, leaving strings empty for
+// blank nodes
+func unmarshalID(subject Term, field reflect.Value) error {
+	if reflect.TypeOf(subject).AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(subject))
+		return nil
+	}
+	if _, blank := subject.(*BlankNode); blank {
+		return nil
+	}
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(subject.RawValue())
+	case field.Type() == urlType:
+		parsed, err := url.Parse(subject.RawValue())
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(*parsed))
+	case field.Kind() == reflect.Pointer && field.Type().Elem() == urlType:
+		parsed, err := url.Parse(subject.RawValue())
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+	default:
+		return fmt.Errorf("cannot use %s as @id", field.Type())
+	}
+	return nil
+}
+
+// objects sets a field to the objects of its predicate
+func (u *unmarshaler) objects(objects []Term, field reflect.Value, f *rdfField) error {
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() == reflect.Uint8 {
+		return u.assign(preferredObject(objects, f.language), field)
+	}
+	if f.list {
+		var err error
+		if objects, err = u.listElements(preferredObject(objects, "")); err != nil {
+			return err
+		}
+	} else {
+		slices.SortFunc(objects, func(a Term, b Term) int {
+			return strings.Compare(a.String(), b.String())
+		})
+	}
+	values := reflect.MakeSlice(field.Type(), len(objects), len(objects))
+	for i, o := range objects {
+		if err := u.assign(o, values.Index(i)); err != nil {
+			return err
+		}
+	}
+	field.Set(values)
+	return nil
+}
+
+// listElements returns the elements of an rdf:List
+func (u *unmarshaler) listElements(head Term) ([]Term, error) {
+	first := NewResource(rdfNamespace + "first")
+	rest := NewResource(rdfNamespace + "rest")
+	nilList := NewResource(rdfNamespace + "nil")
+	var elements []Term
+	seen := make(map[string]bool)
+	for !head.Equal(nilList) {
+		if seen[head.String()] {
+			return nil, fmt.Errorf("%s is a cyclic list", head)
+		}
+		seen[head.String()] = true
+		element := u.graph.One(head, first, nil)
+		next := u.graph.One(head, rest, nil)
+		if element == nil || next == nil {
+			return nil, fmt.Errorf("%s is not a list", head)
+		}
+		elements = append(elements, element.Object)
+		head = next.Object
+	}
+	return elements, nil
+}
+
+// preferredObject returns the object to set a single valued field to: the
+// literal with the preferred language, one with a subtag of it, one without
+// a language, or else the first object in N-Triples order
+func preferredObject(objects []Term, language string) Term {
+	rank := func(t Term) int {
+		lit, ok := t.(*Literal)
+		switch {
+		case !ok || len(lit.Language) == 0:
+			if len(language) > 0 {
+				return 2
+			}
+			return 0
+		case len(language) == 0:
+			return 1
+		case strings.EqualFold(lit.Language, language):
+			return 0
+		case len(lit.Language) > len(language) && strings.EqualFold(lit.Language[:len(language)+1], language+"-"):
+			return 1
+		}
+		return 3
+	}
+	best := objects[0]
+	for _, o := range objects[1:] {
+		if r, b := rank(o), rank(best); r < b || r == b && o.String() < best.String() {
+			best = o
+		}
+	}
+	return best
+}
+
+// assign sets a value to an object converted to the type of the value
+func (u *unmarshaler) assign(o Term, v reflect.Value) error {
+	t := v.Type()
+	if reflect.TypeOf(o).AssignableTo(t) {
+		v.Set(reflect.ValueOf(o))
+		return nil
+	}
+	lit, _ := o.(*Literal)
+	switch {
+	case t == timeType:
+		if lit != nil {
+			if parsed, ok := literalTime(lit); ok {
+				v.Set(reflect.ValueOf(parsed))
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot unmarshal %s into %s", o, t)
+	case t == urlType:
+		if _, ok := o.(*Resource); !ok {
+			return fmt.Errorf("cannot unmarshal %s into %s", o, t)
+		}
+		parsed, err := url.Parse(o.RawValue())
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*parsed))
+		return nil
+	case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct && t.Elem() != timeType && t.Elem() != urlType:
+		key := unmarshaledNode{o.String(), t}
+		if p, seen := u.nodes[key]; seen {
+			v.Set(p)
+			return nil
+		}
+		p := reflect.New(t.Elem())
+		u.nodes[key] = p
+		v.Set(p)
+		return u.node(o, p.Elem())
+	case t.Kind() == reflect.Pointer:
+		p := reflect.New(t.Elem())
+		if err := u.assign(o, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(o.RawValue()))
+	case t.Kind() == reflect.Struct:
+		key := unmarshaledNode{o.String(), t}
+		if u.busy[key] {
+			// a cycle through struct values, which cannot be represented
+			return nil
+		}
+		u.busy[key] = true
+		defer delete(u.busy, key)
+		return u.node(o, v)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		if lit == nil {
+			return fmt.Errorf("cannot unmarshal %s into %s", o, t)
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lit.Value))
+		if err != nil {
+			return err
+		}
+		v.SetBytes(data)
+		return nil
+	case t.Kind() == reflect.String:
+		v.SetString(o.RawValue())
+		return nil
+	}
+	if lit == nil {
+		return fmt.Errorf("cannot unmarshal %s into %s", o, t)
+	}
+	lexical := strings.TrimSpace(lit.Value)
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(lexical)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimPrefix(lexical, "+"), 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(strings.TrimPrefix(lexical, "+"), 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(lexical, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot unmarshal %s into %s", o, t)
+	}
+	return nil
+}
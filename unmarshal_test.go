@@ -0,0 +1,85 @@
+package rdf2go
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshal(t *testing.T) {
+	g := NewGraph(testUri)
+	assert.NoError(t, g.Parse(strings.NewReader(`
+@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+@prefix ex: <http://example.org/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+<https://alice.example/#me> a foaf:Person ;
+	foaf:name "Alicia"@es, "Alice"@en-GB, "Alice A." ;
+	foaf:age 42 ;
+	ex:height 1.5e0 ;
+	ex:active true ;
+	ex:birthday "1980-05-17T10:00:00Z"^^xsd:dateTime ;
+	foaf:homepage <https://alice.example/> ;
+	foaf:mbox <mailto:alice@example.org> ;
+	ex:status ex:Active ;
+	ex:tag "b", "a" ;
+	ex:scores (3 1 2) ;
+	ex:address [ ex:city "Oslo" ] ;
+	foaf:knows <https://bob.example/#me> .
+
+<https://bob.example/#me> foaf:name "Bob" ;
+	foaf:knows <https://alice.example/#me> .
+`), "text/turtle"))
+
+	me := NewResource("https://alice.example/#me")
+	var alice testPerson
+	assert.NoError(t, Unmarshal(g, me, &alice))
+	assert.Equal(t, "https://alice.example/#me", alice.ID)
+	assert.Equal(t, []string{testFOAF + "Person"}, alice.Types)
+	assert.Equal(t, "Alice", alice.Name)
+	assert.Equal(t, 42, alice.Age)
+	assert.Equal(t, 1.5, alice.Height)
+	assert.True(t, alice.Active)
+	assert.True(t, alice.Birthday.Equal(time.Date(1980, 5, 17, 10, 0, 0, 0, time.UTC)))
+	if assert.NotNil(t, alice.Homepage) {
+		assert.Equal(t, "https://alice.example/", alice.Homepage.String())
+	}
+	assert.Equal(t, "mailto:alice@example.org", alice.Mbox)
+	assert.Equal(t, NewResource("http://example.org/Active"), alice.Status)
+	assert.Equal(t, []string{"a", "b"}, alice.Tags)
+	assert.Equal(t, []int{3, 1, 2}, alice.Scores)
+	assert.Equal(t, "Oslo", alice.Address.City)
+
+	// nested resources are resolved, once per node
+	if assert.Len(t, alice.Knows, 1) {
+		bob := alice.Knows[0]
+		assert.Equal(t, "Bob", bob.Name)
+		if assert.Len(t, bob.Knows, 1) {
+			assert.Same(t, bob, bob.Knows[0].Knows[0])
+		}
+	}
+
+	// without the lang option, the literal without a language is preferred
+	var name struct {
+		Name string `rdf:"http://xmlns.com/foaf/0.1/name"`
+	}
+	assert.NoError(t, Unmarshal(g, me, &name))
+	assert.Equal(t, "Alice A.", name.Name)
+
+	// a round trip through Marshal
+	marshaled, err := Marshal(&alice)
+	assert.NoError(t, err)
+	var again testPerson
+	assert.NoError(t, Unmarshal(marshaled, me, &again))
+	assert.Equal(t, alice.Name, again.Name)
+	assert.Equal(t, alice.Scores, again.Scores)
+	assert.Equal(t, "Bob", again.Knows[0].Name)
+
+	assert.EqualError(t, Unmarshal(g, me, alice), "cannot unmarshal into rdf2go.testPerson, which is not a pointer to a struct")
+	var wrong struct {
+		Age bool `rdf:"http://xmlns.com/foaf/0.1/age"`
+	}
+	assert.Error(t, Unmarshal(g, me, &wrong))
+}
@@ -0,0 +1,148 @@
+package rdf2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IntegrityError is returned by Verify when internal invariants are broken.
+// It lists every problem that was found.
+type IntegrityError struct {
+	Problems []string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("integrity check failed with %d problem(s): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// verifyPermutation checks one permutation of an index, returning the number
+// of statements it holds. key returns the expected (a, b, c) keys of a statement.
+func verifyPermutation[T comparable](name string, m map[string]map[string]map[string]keySet[T], stored func(T) bool, key func(T) (string, string, string), problems *[]string) int {
+	count := 0
+	for a, l1 := range m {
+		if len(l1) == 0 {
+			*problems = append(*problems, fmt.Sprintf("%s index has a dangling entry for %s", name, a))
+		}
+		for b, l2 := range l1 {
+			if len(l2) == 0 {
+				*problems = append(*problems, fmt.Sprintf("%s index has a dangling entry for %s %s", name, a, b))
+			}
+			for c, set := range l2 {
+				if len(set) == 0 {
+					*problems = append(*problems, fmt.Sprintf("%s index has a dangling entry for %s %s %s", name, a, b, c))
+				}
+				for v := range set {
+					count++
+					if !stored(v) {
+						*problems = append(*problems, fmt.Sprintf("%s index references a statement that is not stored: %v", name, v))
+						continue
+					}
+					ka, kb, kc := key(v)
+					if ka != a || kb != b || kc != c {
+						*problems = append(*problems, fmt.Sprintf("%s index stores %v under the wrong key", name, v))
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
+// verify checks that the three permutations of the index agree with each
+// other and with the stored statements
+func (x *spoIndex[T]) verify(stored func(T) bool, key func(T) (string, string, string), problems *[]string) {
+	spo := verifyPermutation("SPO", x.spo, stored, key, problems)
+	pos := verifyPermutation("POS", x.pos, stored, func(v T) (string, string, string) {
+		s, p, o := key(v)
+		return p, o, s
+	}, problems)
+	osp := verifyPermutation("OSP", x.osp, stored, func(v T) (string, string, string) {
+		s, p, o := key(v)
+		return o, s, p
+	}, problems)
+	if spo != x.size || pos != x.size || osp != x.size {
+		*problems = append(*problems, fmt.Sprintf("index counts disagree: size %d, SPO %d, POS %d, OSP %d", x.size, spo, pos, osp))
+	}
+}
+
+func (p *IRIPolicy) tripleKeys(t *Triple) (string, string, string) {
+	return p.key(t.Subject), p.key(t.Predicate), p.key(t.Object)
+}
+
+func (p *IRIPolicy) quadKeys(q *Quad) (string, string, string) {
+	return p.key(q.Subject), p.key(q.Predicate), p.key(q.Object)
+}
+
+// Verify checks the internal invariants of the graph: every triple is well
+// formed and the lookup indexes agree with the stored triples. It returns an
+// *IntegrityError describing all problems found, or nil.
+func (g *Graph) Verify() error {
+	defer g.rlock()()
+	var problems []string
+	for triple := range g.triples {
+		if triple == nil || triple.Subject == nil || triple.Predicate == nil || triple.Object == nil {
+			problems = append(problems, fmt.Sprintf("incomplete triple %v", triple))
+		}
+	}
+	if g.index != nil {
+		g.index.verify(func(t *Triple) bool {
+			_, ok := g.triples[t]
+			return ok
+		}, g.iriPolicy.tripleKeys, &problems)
+		if g.index.size != len(g.triples) {
+			problems = append(problems, fmt.Sprintf("graph holds %d triples but the index holds %d", len(g.triples), g.index.size))
+		}
+	}
+	if len(problems) > 0 {
+		return &IntegrityError{Problems: problems}
+	}
+	return nil
+}
+
+// Verify checks the internal invariants of the dataset: every quad is well
+// formed with a unique sequence number, and the per-graph lookup indexes
+// agree with the stored quads. It returns an *IntegrityError describing all
+// problems found, or nil.
+func (d *Dataset) Verify() error {
+	defer d.rlock()()
+	var problems []string
+	seqs := make(map[uint64]bool, len(d.quads))
+	for quad, seq := range d.quads {
+		if quad == nil || quad.Subject == nil || quad.Predicate == nil || quad.Object == nil {
+			problems = append(problems, fmt.Sprintf("incomplete quad %v", quad))
+			continue
+		}
+		if seq == 0 || seq > d.seq {
+			problems = append(problems, fmt.Sprintf("quad %s has an invalid sequence number %d", quad, seq))
+		}
+		if seqs[seq] {
+			problems = append(problems, fmt.Sprintf("sequence number %d is used more than once", seq))
+		}
+		seqs[seq] = true
+	}
+
+	if d.graphs != nil {
+		indexed := 0
+		for gk, gi := range d.graphs {
+			if d.iriPolicy.key(gi.term) != gk {
+				problems = append(problems, fmt.Sprintf("graph index %s is stored under key %s", termKey(gi.term), gk))
+			}
+			if gi.idx.size == 0 {
+				problems = append(problems, fmt.Sprintf("graph index %s is empty", gk))
+			}
+			stored := func(q *Quad) bool {
+				_, ok := d.quads[q]
+				return ok && d.iriPolicy.key(q.Graph) == gk
+			}
+			gi.idx.verify(stored, d.iriPolicy.quadKeys, &problems)
+			indexed += gi.idx.size
+		}
+		if indexed != len(d.quads) {
+			problems = append(problems, fmt.Sprintf("dataset holds %d quads but the indexes hold %d", len(d.quads), indexed))
+		}
+	}
+	if len(problems) > 0 {
+		return &IntegrityError{Problems: problems}
+	}
+	return nil
+}
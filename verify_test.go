@@ -0,0 +1,42 @@
+package rdf2go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphVerify(t *testing.T) {
+	g := NewGraph(testUri)
+	g.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	triple := NewTriple(NewResource("a"), NewResource("b"), NewResource("d"))
+	g.Add(triple)
+	g.Remove(triple)
+	assert.NoError(t, g.Verify())
+
+	// corrupt the graph by bypassing the index
+	g.triples[NewTriple(NewResource("x"), NewResource("y"), NewResource("z"))] = 0
+	err := g.Verify()
+	assert.Error(t, err)
+	assert.IsType(t, &IntegrityError{}, err)
+}
+
+func TestDatasetVerify(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	quad := NewQuad(NewResource("a"), NewResource("b"), NewResource("c"), NewResource("g"))
+	d.Add(quad)
+	assert.NoError(t, d.Verify())
+
+	// leave a dangling index entry behind
+	delete(d.quads, quad)
+	err := d.Verify()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not stored")
+
+	d = NewUnindexedDataset(testDatasetUri)
+	d.AddTriple(NewResource("a"), NewResource("b"), NewResource("c"))
+	assert.NoError(t, d.Verify())
+	d.quads[NewQuad(NewResource("a"), NewResource("b"), nil, nil)] = 1
+	assert.Error(t, d.Verify())
+}
@@ -0,0 +1,183 @@
+package rdf2go
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Version is a committed state of a versioned dataset, recorded as the
+// changes made since the previous version
+type Version struct {
+	ID      int
+	Message string
+	Time    time.Time
+	Changes *ChangeSet
+}
+
+// VersionedDataset records the changes made to a dataset as a history of
+// versions, which can be tagged, compared and materialized. Version 0 holds
+// the content of the dataset when versioning started. Like subscriptions, a
+// versioned dataset must be canceled when it is no longer needed.
+type VersionedDataset struct {
+	dataset *Dataset
+	sub     *Subscription
+
+	// mu guards the versions, the tags and the pending changes, which the
+	// subscription records as the dataset changes
+	mu       sync.Mutex
+	versions []*Version
+	tags     map[string]int
+	pending  map[*Quad]ChangeKind
+}
+
+// NewVersionedDataset starts recording the changes made to a dataset
+func NewVersionedDataset(d *Dataset) *VersionedDataset {
+	v := &VersionedDataset{
+		dataset: d,
+		tags:    make(map[string]int),
+		pending: make(map[*Quad]ChangeKind),
+	}
+	v.versions = []*Version{{
+		Message: "initial version",
+		Time:    time.Now(),
+		Changes: &ChangeSet{Added: d.orderedQuads()},
+	}}
+	v.sub = d.Subscribe(nil, nil, nil, AnyGraph, v.changed)
+	return v
+}
+
+// Dataset returns the versioned dataset
+func (v *VersionedDataset) Dataset() *Dataset {
+	return v.dataset
+}
+
+// Cancel stops recording the changes of the dataset
+func (v *VersionedDataset) Cancel() {
+	v.sub.Cancel()
+}
+
+// changed records a change of the dataset. A quad added and then removed,
+// or the other way around, cancels out.
+func (v *VersionedDataset) changed(kind ChangeKind, q *Quad) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if previous, ok := v.pending[q]; ok && previous != kind {
+		delete(v.pending, q)
+		return
+	}
+	v.pending[q] = kind
+}
+
+// Pending returns the changes made since the last version
+func (v *VersionedDataset) Pending() *ChangeSet {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.pendingChanges()
+}
+
+// pendingChanges returns the changes made since the last version, v.mu
+// being held
+func (v *VersionedDataset) pendingChanges() *ChangeSet {
+	c := &ChangeSet{}
+	for q, kind := range v.pending {
+		if kind == QuadAdded {
+			c.Added = append(c.Added, q)
+		} else {
+			c.Removed = append(c.Removed, q)
+		}
+	}
+	sortQuads(c.Added)
+	sortQuads(c.Removed)
+	return c
+}
+
+// Commit records the changes made since the last version as a new version
+func (v *VersionedDataset) Commit(message string) *Version {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	version := &Version{
+		ID:      len(v.versions),
+		Message: message,
+		Time:    time.Now(),
+		Changes: v.pendingChanges(),
+	}
+	clear(v.pending)
+	v.versions = append(v.versions, version)
+	return version
+}
+
+// Versions returns the versions, the oldest first
+func (v *VersionedDataset) Versions() []*Version {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return slices.Clone(v.versions)
+}
+
+// Version returns a version from its ID or its tag
+func (v *VersionedDataset) Version(ref string) (*Version, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if id, ok := v.tags[ref]; ok {
+		return v.versions[id], nil
+	}
+	id, err := strconv.Atoi(ref)
+	if err != nil || id < 0 || id >= len(v.versions) {
+		return nil, fmt.Errorf("unknown version %q", ref)
+	}
+	return v.versions[id], nil
+}
+
+// Tag names a version, moving the tag when it already names another one
+func (v *VersionedDataset) Tag(name string, id int) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if id < 0 || id >= len(v.versions) {
+		return fmt.Errorf("unknown version %d", id)
+	}
+	v.tags[name] = id
+	return nil
+}
+
+// Tags returns the tags of the versions
+func (v *VersionedDataset) Tags() map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return maps.Clone(v.tags)
+}
+
+// At returns a new dataset holding the content of the dataset as of a
+// version, given by its ID or its tag
+func (v *VersionedDataset) At(ref string) (*Dataset, error) {
+	version, err := v.Version(ref)
+	if err != nil {
+		return nil, err
+	}
+	out := v.dataset.empty()
+	for _, past := range v.Versions()[:version.ID+1] {
+		for _, q := range past.Changes.Removed {
+			out.remove(q)
+		}
+		for _, q := range past.Changes.Added {
+			out.add(q, "")
+		}
+	}
+	return out, nil
+}
+
+// Diff returns the changes between two versions, given by their IDs or
+// tags, comparing blank nodes as Diff does
+func (v *VersionedDataset) Diff(from string, to string) (*ChangeSet, error) {
+	a, err := v.At(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := v.At(to)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(a, b)
+}
@@ -0,0 +1,97 @@
+package rdf2go
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedDataset(t *testing.T) {
+	d := NewDataset(testDatasetUri)
+	label := NewResource("http://www.w3.org/2000/01/rdf-schema#label")
+	cat := NewResource("http://example.org/cat")
+	d.AddTriple(cat, label, NewLiteral("Cat"))
+	v := NewVersionedDataset(d)
+	defer v.Cancel()
+
+	old := d.One(cat, label, nil, nil)
+	d.Remove(old)
+	d.AddTriple(cat, label, NewLiteralWithLanguage("Cat", "en"))
+	transient := NewQuad(cat, label, NewLiteral("Kitty"), nil)
+	d.Add(transient)
+	d.Remove(transient)
+	pending := v.Pending()
+	assert.Equal(t, 1, len(pending.Added))
+	assert.Equal(t, []*Quad{old}, pending.Removed)
+
+	first := v.Commit("tag the label")
+	assert.Equal(t, 1, first.ID)
+	assert.Equal(t, 0, v.Pending().Len())
+	assert.NoError(t, v.Tag("v1", first.ID))
+	assert.Error(t, v.Tag("v9", 9))
+
+	d.AddQuad(cat, label, NewLiteralWithLanguage("Chat", "fr"), NewResource("http://example.org/fr"))
+	v.Commit("add French")
+	assert.Len(t, v.Versions(), 3)
+
+	initial, err := v.At("0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, initial.Len())
+	assert.Equal(t, NewLiteral("Cat"), initial.One(cat, label, nil, nil).Object)
+
+	tagged, err := v.At("v1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tagged.Len())
+	assert.Equal(t, NewLiteralWithLanguage("Cat", "en"), tagged.One(cat, label, nil, nil).Object)
+
+	latest, err := v.At("2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest.Len())
+
+	c, err := v.Diff("0", "v1")
+	assert.NoError(t, err)
+	assert.Len(t, c.Added, 1)
+	assert.Len(t, c.Removed, 1)
+
+	_, err = v.At("3")
+	assert.Error(t, err)
+	_, err = v.Diff("nope", "1")
+	assert.Error(t, err)
+	version, err := v.Version("v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag the label", version.Message)
+	assert.Equal(t, map[string]int{"v1": 1}, v.Tags())
+}
+
+func TestVersionedDatasetConcurrentCommits(t *testing.T) {
+	d := NewDatasetWithOptions(testDatasetUri, WithThreadSafe(true))
+	v := NewVersionedDataset(d)
+	defer v.Cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			d.AddTriple(NewResource("http://example.org/a"), NewResource("http://example.org/b"), NewLiteral(strconv.Itoa(i)))
+			if i%10 == 0 {
+				v.Tag("latest", len(v.Versions())-1)
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		v.Commit("commit " + strconv.Itoa(i))
+	}
+	<-done
+	v.Commit("last")
+
+	// every change is recorded by exactly one version
+	added := 0
+	for _, version := range v.Versions()[1:] {
+		added += len(version.Changes.Added)
+	}
+	assert.Equal(t, 500, added)
+	latest, err := v.At("latest")
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, latest.Len(), 500)
+}